@@ -0,0 +1,7 @@
+-- Down migration for monitor ignore_maintenance flag
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN ignore_maintenance;
+
+COMMIT;
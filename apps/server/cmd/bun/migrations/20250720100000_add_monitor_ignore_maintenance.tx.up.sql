@@ -0,0 +1,2 @@
+-- Allow monitors to keep alerting during maintenance windows
+ALTER TABLE monitors ADD COLUMN ignore_maintenance BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,8 @@
+-- Down migration for monitor config version history
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_monitor_config_versions_monitor_created;
+DROP TABLE IF EXISTS monitor_config_versions;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Add config version history for monitors
+-- Stores a bounded number of config snapshots per monitor for rollback
+-- Wrapped in a transaction for atomicity
+
+CREATE TABLE IF NOT EXISTS monitor_config_versions (
+    id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    config TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- Versions are always listed per monitor, newest first
+CREATE INDEX IF NOT EXISTS idx_monitor_config_versions_monitor_created ON monitor_config_versions(monitor_id, created_at);
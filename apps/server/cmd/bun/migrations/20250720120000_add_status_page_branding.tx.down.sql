@@ -0,0 +1,9 @@
+-- Down migration for status page branding fields
+
+BEGIN;
+
+ALTER TABLE status_pages DROP COLUMN custom_css;
+ALTER TABLE status_pages DROP COLUMN primary_color;
+ALTER TABLE status_pages DROP COLUMN logo_url;
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- Add branding fields to status pages
+-- Logo, primary color and sanitized custom CSS for branded public pages
+
+ALTER TABLE status_pages ADD COLUMN logo_url VARCHAR(2048);
+ALTER TABLE status_pages ADD COLUMN primary_color VARCHAR(9);
+ALTER TABLE status_pages ADD COLUMN custom_css TEXT;
@@ -0,0 +1,8 @@
+-- Down migration for scheduled reports
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reports_active;
+DROP TABLE IF EXISTS reports;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Add scheduled reports emailed through an smtp notification channel
+-- Recipients and the monitor and status page selections are stored as JSON arrays
+-- Wrapped in a transaction for atomicity
+
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    cadence VARCHAR(10) NOT NULL,
+    notification_channel_id UUID NOT NULL,
+    recipients TEXT,
+    monitor_ids TEXT,
+    status_page_ids TEXT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (notification_channel_id) REFERENCES notification_channels(id) ON DELETE CASCADE
+);
+
+-- The scheduler only loads active reports
+CREATE INDEX IF NOT EXISTS idx_reports_active ON reports(active);
@@ -0,0 +1,7 @@
+-- Down migration for monitor retention_days
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN retention_days;
+
+COMMIT;
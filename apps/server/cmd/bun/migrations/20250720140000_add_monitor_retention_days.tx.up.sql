@@ -0,0 +1,2 @@
+-- Per-monitor heartbeat retention, 0 falls back to the global setting
+ALTER TABLE monitors ADD COLUMN retention_days INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Down migration for monitor alert acknowledgments
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_monitor_acks_monitor_expires;
+DROP TABLE IF EXISTS monitor_acks;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Alert acknowledgments suppressing re-notifications of a monitor
+-- until they expire or the monitor recovers
+
+CREATE TABLE IF NOT EXISTS monitor_acks (
+    id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    acknowledged_by VARCHAR(255) NOT NULL,
+    acknowledged_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    cleared_at TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- The listener looks up the open acknowledgment of a monitor on every notification
+CREATE INDEX IF NOT EXISTS idx_monitor_acks_monitor_expires ON monitor_acks(monitor_id, expires_at);
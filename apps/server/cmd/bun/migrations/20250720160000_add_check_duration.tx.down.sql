@@ -0,0 +1,8 @@
+-- Down migration for check duration stats and slow_check_threshold
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN slow_check_threshold;
+ALTER TABLE stats DROP COLUMN duration;
+
+COMMIT;
@@ -0,0 +1,5 @@
+-- Average check duration of the stat buckets
+ALTER TABLE stats ADD COLUMN duration DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Percent rise of the check duration that alerts, 0 disables the detection
+ALTER TABLE monitors ADD COLUMN slow_check_threshold INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Down migration for notification failures
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_notification_failures_failed;
+DROP INDEX IF EXISTS idx_notification_failures_channel_failed;
+DROP TABLE IF EXISTS notification_failures;
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Notifications that could not be delivered after every retry. The monitor
+-- is not a foreign key, coalesced notifications use group-* ids.
+
+CREATE TABLE IF NOT EXISTS notification_failures (
+    id UUID PRIMARY KEY,
+    notification_channel_id VARCHAR(255) NOT NULL,
+    channel_name VARCHAR(255) NOT NULL,
+    channel_type VARCHAR(255) NOT NULL,
+    monitor_id VARCHAR(255),
+    message TEXT,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    failed_at TIMESTAMP NOT NULL
+);
+
+-- Failures are listed newest first, optionally of one channel
+CREATE INDEX IF NOT EXISTS idx_notification_failures_channel_failed ON notification_failures(notification_channel_id, failed_at);
+CREATE INDEX IF NOT EXISTS idx_notification_failures_failed ON notification_failures(failed_at);
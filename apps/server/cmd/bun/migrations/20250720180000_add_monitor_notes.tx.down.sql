@@ -0,0 +1,8 @@
+-- Down migration for monitor notes and runbook link
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN runbook_url;
+ALTER TABLE monitors DROP COLUMN notes;
+
+COMMIT;
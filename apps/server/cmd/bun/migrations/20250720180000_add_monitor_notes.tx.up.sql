@@ -0,0 +1,4 @@
+-- Notes and runbook link of a monitor, included in notifications
+
+ALTER TABLE monitors ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN runbook_url VARCHAR(2048) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- Down migration for status page notifications
+
+BEGIN;
+
+ALTER TABLE status_pages DROP COLUMN notification_ids;
+ALTER TABLE status_pages DROP COLUMN notify_incidents;
+
+COMMIT;
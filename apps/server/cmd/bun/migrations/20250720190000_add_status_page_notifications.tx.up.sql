@@ -0,0 +1,4 @@
+-- Status pages can send the incidents of their monitors through notification
+-- channels, notification_ids is a JSON array of channel IDs
+ALTER TABLE status_pages ADD COLUMN notify_incidents BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE status_pages ADD COLUMN notification_ids TEXT NOT NULL DEFAULT '[]';
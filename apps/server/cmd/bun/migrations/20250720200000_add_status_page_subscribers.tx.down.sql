@@ -0,0 +1,8 @@
+-- Down migration for status page subscribers
+
+BEGIN;
+
+DROP TABLE IF EXISTS status_page_subscribers;
+ALTER TABLE status_pages DROP COLUMN subscriber_channel_id;
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Visitors subscribe to the incidents of a status page by email. The token of
+-- a subscriber confirms the subscription and later unsubscribes it.
+ALTER TABLE status_pages ADD COLUMN subscriber_channel_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS status_page_subscribers (
+    id UUID PRIMARY KEY,
+    status_page_id UUID NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    confirmation_sent_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages(id) ON DELETE CASCADE
+);
+
+-- An address subscribes to a page once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_status_page_subscribers_page_email ON status_page_subscribers(status_page_id, email);
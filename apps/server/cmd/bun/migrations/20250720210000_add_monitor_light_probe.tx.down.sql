@@ -0,0 +1,8 @@
+-- Down migration for monitor light probing
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN light_probe_interval;
+ALTER TABLE monitors DROP COLUMN light_probe_after;
+
+COMMIT;
@@ -0,0 +1,5 @@
+-- Monitors that time out repeatedly can fall back to a TCP connect check at a
+-- longer interval until the host is reachable again
+
+ALTER TABLE monitors ADD COLUMN light_probe_after INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN light_probe_interval INTEGER NOT NULL DEFAULT 0;
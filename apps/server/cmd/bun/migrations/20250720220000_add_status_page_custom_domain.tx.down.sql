@@ -0,0 +1,8 @@
+-- Down migration for status page custom domains
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_status_pages_custom_domain;
+ALTER TABLE status_pages DROP COLUMN custom_domain;
+
+COMMIT;
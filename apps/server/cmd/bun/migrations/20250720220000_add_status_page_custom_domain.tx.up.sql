@@ -0,0 +1,6 @@
+-- Status pages can be served on their own domain. Pages without one keep
+-- NULL so the unique index only applies to configured domains. SQLite cannot
+-- add a UNIQUE column, hence the separate index.
+
+ALTER TABLE status_pages ADD COLUMN custom_domain VARCHAR(253);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_status_pages_custom_domain ON status_pages(custom_domain);
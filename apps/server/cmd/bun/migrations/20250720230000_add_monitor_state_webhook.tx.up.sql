@@ -0,0 +1,3 @@
+-- Monitors can POST each status transition to a webhook of their own
+
+ALTER TABLE monitors ADD COLUMN state_webhook_url VARCHAR(2048) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Down migration for password protected status pages
+
+BEGIN;
+
+ALTER TABLE status_pages DROP COLUMN password_hash;
+
+COMMIT;
@@ -0,0 +1,4 @@
+-- Status pages can require a password, stored as a bcrypt hash like user
+-- passwords. An empty hash leaves the page public.
+
+ALTER TABLE status_pages ADD COLUMN password_hash VARCHAR(255) NOT NULL DEFAULT '';
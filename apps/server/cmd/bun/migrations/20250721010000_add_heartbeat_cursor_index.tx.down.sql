@@ -0,0 +1,7 @@
+-- Down migration for the heartbeat cursor index
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_heartbeats_monitor_time_id;
+
+COMMIT;
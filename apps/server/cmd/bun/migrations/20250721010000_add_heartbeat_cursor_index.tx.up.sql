@@ -0,0 +1,3 @@
+-- Cursor pagination of heartbeats orders by time and then id within a monitor
+
+CREATE INDEX IF NOT EXISTS idx_heartbeats_monitor_time_id ON heartbeats(monitor_id, time, id);
@@ -0,0 +1,8 @@
+-- Down migration for status page incidents
+
+BEGIN;
+
+DROP TABLE IF EXISTS status_page_incident_updates;
+DROP TABLE IF EXISTS status_page_incidents;
+
+COMMIT;
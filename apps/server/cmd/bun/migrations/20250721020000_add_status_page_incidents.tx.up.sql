@@ -0,0 +1,26 @@
+-- Operators post incidents on a status page independent of the monitor
+-- states. An incident carries a timeline of updates, the status of the
+-- incident is the one of its latest update.
+CREATE TABLE IF NOT EXISTS status_page_incidents (
+    id UUID PRIMARY KEY,
+    status_page_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    monitor_ids TEXT NOT NULL DEFAULT '[]',
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS status_page_incident_updates (
+    id UUID PRIMARY KEY,
+    incident_id UUID NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (incident_id) REFERENCES status_page_incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_incidents_page_created ON status_page_incidents(status_page_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_status_page_incident_updates_incident ON status_page_incident_updates(incident_id, created_at);
@@ -0,0 +1,7 @@
+-- Down migration for monitor state-change webhook templates
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN state_webhook_template;
+
+COMMIT;
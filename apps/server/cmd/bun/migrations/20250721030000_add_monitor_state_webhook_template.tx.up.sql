@@ -0,0 +1,4 @@
+-- The state-change webhook of a monitor can render its body from a liquid
+-- template, empty sends the default JSON payload
+
+ALTER TABLE monitors ADD COLUMN state_webhook_template TEXT NOT NULL DEFAULT '';
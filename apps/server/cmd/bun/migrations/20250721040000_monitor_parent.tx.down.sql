@@ -0,0 +1,7 @@
+-- Down migration for monitor parents
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN parent_id;
+
+COMMIT;
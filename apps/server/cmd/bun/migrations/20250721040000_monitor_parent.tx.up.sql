@@ -0,0 +1,4 @@
+-- A monitor can depend on a parent monitor, its down alerts are held back
+-- while the parent is down. Empty means no parent.
+
+ALTER TABLE monitors ADD COLUMN parent_id VARCHAR(255) NOT NULL DEFAULT '';
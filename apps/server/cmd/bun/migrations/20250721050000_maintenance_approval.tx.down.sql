@@ -0,0 +1,9 @@
+-- Down migration for maintenance approvals
+
+BEGIN;
+
+ALTER TABLE maintenances DROP COLUMN reviewed_at;
+ALTER TABLE maintenances DROP COLUMN reviewed_by;
+ALTER TABLE maintenances DROP COLUMN approval_status;
+
+COMMIT;
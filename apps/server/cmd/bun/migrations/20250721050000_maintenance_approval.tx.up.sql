@@ -0,0 +1,6 @@
+-- Maintenance windows can require an approval before they suppress checks.
+-- Existing windows stay approved.
+
+ALTER TABLE maintenances ADD COLUMN approval_status VARCHAR(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE maintenances ADD COLUMN reviewed_by VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE maintenances ADD COLUMN reviewed_at TIMESTAMP;
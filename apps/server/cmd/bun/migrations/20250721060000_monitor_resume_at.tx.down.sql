@@ -0,0 +1,7 @@
+-- Down migration for scheduled monitor resume
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN resume_at;
+
+COMMIT;
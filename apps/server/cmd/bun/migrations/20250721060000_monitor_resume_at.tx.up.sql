@@ -0,0 +1,4 @@
+-- A paused monitor with resume_at is resumed automatically once it has
+-- passed. NULL keeps the monitor paused until it is resumed by hand.
+
+ALTER TABLE monitors ADD COLUMN resume_at TIMESTAMP NULL;
@@ -0,0 +1,7 @@
+-- Down migration for stored certificates
+
+BEGIN;
+
+DROP TABLE IF EXISTS certificates;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Stored mTLS client certificates, HTTP monitors reference them by ID
+-- instead of embedding the PEM material in their config.
+
+CREATE TABLE IF NOT EXISTS certificates (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    certificate TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    ca_certificate TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
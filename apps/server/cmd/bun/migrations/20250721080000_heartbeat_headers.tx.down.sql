@@ -0,0 +1,7 @@
+-- Down migration for heartbeat headers
+
+BEGIN;
+
+ALTER TABLE heartbeats DROP COLUMN headers;
+
+COMMIT;
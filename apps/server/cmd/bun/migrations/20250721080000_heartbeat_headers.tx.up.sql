@@ -0,0 +1,4 @@
+-- Response headers captured by the check, stored as JSON. Only HTTP
+-- monitors with capture_headers fill it.
+
+ALTER TABLE heartbeats ADD COLUMN headers TEXT;
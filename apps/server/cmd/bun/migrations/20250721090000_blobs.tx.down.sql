@@ -0,0 +1,7 @@
+-- Down migration for stored blobs
+
+BEGIN;
+
+DROP TABLE IF EXISTS blobs;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Stored blobs, HTTP monitors reference them with body_ref to send request
+-- bodies too large to keep in their config.
+
+CREATE TABLE IF NOT EXISTS blobs (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Down migration for monitor failover proxies
+
+BEGIN;
+
+ALTER TABLE monitors DROP COLUMN failover_proxy_ids;
+
+COMMIT;
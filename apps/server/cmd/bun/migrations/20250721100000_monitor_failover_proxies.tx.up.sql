@@ -0,0 +1,4 @@
+-- Proxies a monitor falls back to, in order, when its proxy stops accepting
+-- connections. Stored as a JSON array of proxy ids.
+
+ALTER TABLE monitors ADD COLUMN failover_proxy_ids TEXT;
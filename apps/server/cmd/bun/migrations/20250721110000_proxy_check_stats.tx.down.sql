@@ -0,0 +1,10 @@
+-- Down migration for proxy check stats
+
+BEGIN;
+
+ALTER TABLE proxies DROP COLUMN last_error_at;
+ALTER TABLE proxies DROP COLUMN last_error;
+ALTER TABLE proxies DROP COLUMN failure_count;
+ALTER TABLE proxies DROP COLUMN success_count;
+
+COMMIT;
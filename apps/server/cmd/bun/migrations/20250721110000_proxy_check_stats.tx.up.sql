@@ -0,0 +1,7 @@
+-- Outcomes of the monitor checks routed through each proxy, counted by the
+-- proxy event listener.
+
+ALTER TABLE proxies ADD COLUMN success_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE proxies ADD COLUMN failure_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE proxies ADD COLUMN last_error TEXT;
+ALTER TABLE proxies ADD COLUMN last_error_at TIMESTAMP NULL;
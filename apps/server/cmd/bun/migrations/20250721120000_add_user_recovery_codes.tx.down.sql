@@ -0,0 +1,7 @@
+-- Down migration for user recovery codes
+
+BEGIN;
+
+ALTER TABLE users DROP COLUMN twofa_recovery_codes;
+
+COMMIT;
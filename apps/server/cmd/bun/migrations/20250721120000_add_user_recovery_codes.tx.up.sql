@@ -0,0 +1,4 @@
+-- Hashed one-time recovery codes logging in without the authenticator app,
+-- comma separated
+
+ALTER TABLE users ADD COLUMN twofa_recovery_codes TEXT;
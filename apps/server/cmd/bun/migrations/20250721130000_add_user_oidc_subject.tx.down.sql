@@ -0,0 +1,8 @@
+-- Down migration for user oidc subject
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_users_oidc_subject;
+ALTER TABLE users DROP COLUMN oidc_subject;
+
+COMMIT;
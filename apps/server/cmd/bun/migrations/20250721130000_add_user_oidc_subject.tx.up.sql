@@ -0,0 +1,6 @@
+-- Subject of the identity provider account linked to the user, set on the
+-- first OpenID Connect login
+
+ALTER TABLE users ADD COLUMN oidc_subject VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users(oidc_subject);
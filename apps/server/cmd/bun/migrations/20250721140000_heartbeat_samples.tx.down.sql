@@ -0,0 +1,7 @@
+-- Down migration for heartbeat samples
+
+BEGIN;
+
+ALTER TABLE heartbeats DROP COLUMN samples;
+
+COMMIT;
@@ -0,0 +1,4 @@
+-- Latency distribution of the probes of a check, stored as JSON. Only
+-- monitors taking several samples per check fill it.
+
+ALTER TABLE heartbeats ADD COLUMN samples TEXT;
@@ -43,6 +43,87 @@ type Config struct {
 	LokiURL    string            `env:"LOKI_URL" validate:"omitempty,url"`
 	LokiLabels map[string]string // Set programmatically or extend env parsing for map
 	Timezone   string            `env:"TZ" validate:"required" default:"UTC"`
+
+	// Outbound probe throttling, 0 disables the limit
+	ProbeMaxRPSPerHost int `env:"PROBE_MAX_RPS_PER_HOST" validate:"min=0"`
+	ProbeMaxRPSGlobal  int `env:"PROBE_MAX_RPS_GLOBAL" validate:"min=0"`
+
+	// Notifications for monitors changing state within the window are grouped
+	// per channel once at least the threshold of monitors is affected, 0 disables
+	NotificationCoalesceWindow    time.Duration `env:"NOTIFICATION_COALESCE_WINDOW"`
+	NotificationCoalesceThreshold int           `env:"NOTIFICATION_COALESCE_THRESHOLD" validate:"min=2" default:"5"`
+
+	// Failed notification sends are retried with exponential backoff and jitter,
+	// 1 attempt disables retries. Sends failing every attempt are stored when
+	// persisting is enabled.
+	NotificationSendAttempts    int           `env:"NOTIFICATION_SEND_ATTEMPTS" validate:"min=0,max=10" default:"3"`
+	NotificationRetryBaseDelay  time.Duration `env:"NOTIFICATION_RETRY_BASE_DELAY" validate:"duration_min=10ms" default:"2s"`
+	NotificationPersistFailures bool          `env:"NOTIFICATION_PERSIST_FAILURES" default:"false"`
+
+	// Heartbeats are written in batches once the buffer is full or the flush
+	// interval has passed, a buffer size below 2 writes every heartbeat directly
+	HeartbeatBufferSize    int           `env:"HEARTBEAT_BUFFER_SIZE" validate:"min=0" default:"100"`
+	HeartbeatFlushInterval time.Duration `env:"HEARTBEAT_FLUSH_INTERVAL" validate:"duration_min=10ms" default:"1s"`
+
+	// Bearer token required to scrape /metrics, empty leaves the endpoint open
+	MetricsToken string `env:"METRICS_TOKEN"`
+
+	// Declarative YAML or JSON monitors file applied at startup. Authoritative
+	// also deletes the monitors the file does not declare.
+	MonitorsFile              string `env:"MONITORS_FILE"`
+	MonitorsFileAuthoritative bool   `env:"MONITORS_FILE_AUTHORITATIVE" default:"false"`
+
+	// How long the public monitors of a status page are served from memory, a
+	// negative duration disables the cache
+	StatusPageCacheTTL time.Duration `env:"STATUS_PAGE_CACHE_TTL" default:"10s"`
+
+	// How long a new monitor may fail without a single successful check before
+	// a one-time notification is sent, a negative duration disables it
+	NeverSucceededAlertAfter time.Duration `env:"NEVER_SUCCEEDED_ALERT_AFTER" default:"1h"`
+
+	// Comma separated emails of the users allowed to approve maintenance
+	// windows. When set, windows only suppress checks once approved.
+	MaintenanceApprovers string `env:"MAINTENANCE_APPROVERS"`
+
+	// Bearer token Alertmanager sends to the webhook driving alertmanager
+	// monitors, empty disables the webhook
+	AlertmanagerWebhookToken string `env:"ALERTMANAGER_WEBHOOK_TOKEN"`
+
+	// Offset the check loop of every monitor by a random fraction of its
+	// interval, so monitors started together do not check on the same tick
+	CheckJitter bool `env:"CHECK_JITTER" default:"false"`
+
+	// Checks executing at once across all monitors, further checks queue
+	// for a free slot, 0 disables the limit
+	MaxConcurrentChecks int `env:"MAX_CONCURRENT_CHECKS" validate:"min=0"`
+
+	// Seconds between the connection checks of every proxy, monitors fail
+	// over to their next healthy proxy. 0 only checks a proxy after a failed
+	// check that used it.
+	ProxyHealthCheckInterval int `env:"PROXY_HEALTH_CHECK_INTERVAL" validate:"min=0"`
+
+	// Key encrypting the stored TOTP secrets, 2FA cannot be set up without
+	// it. It is separate from the token secret keys so those can be rotated.
+	// Changing it invalidates the enrolled authenticators, the recovery codes
+	// still log in.
+	TwoFASecretKey string `env:"TWOFA_SECRET_KEY" validate:"omitempty,min=16"`
+
+	// OpenID Connect login through an identity provider. The redirect URL is
+	// the /api/v1/auth/oidc/callback endpoint as the browser reaches it.
+	OIDCEnabled      bool   `env:"OIDC_ENABLED" default:"false"`
+	OIDCIssuer       string `env:"OIDC_ISSUER" validate:"required_if=OIDCEnabled true,omitempty,url"`
+	OIDCClientID     string `env:"OIDC_CLIENT_ID" validate:"required_if=OIDCEnabled true"`
+	OIDCClientSecret string `env:"OIDC_CLIENT_SECRET" validate:"required_if=OIDCEnabled true"`
+	OIDCRedirectURL  string `env:"OIDC_REDIRECT_URL" validate:"required_if=OIDCEnabled true,omitempty,url"`
+	// Comma separated scopes requested besides openid
+	OIDCScopes string `env:"OIDC_SCOPES" default:"email,profile"`
+	// Create a local user for a verified email without one. Otherwise only
+	// the existing users log in through the provider.
+	OIDCAutoProvision bool `env:"OIDC_AUTO_PROVISION" default:"false"`
+
+	// Comma separated emails and email domains allowed to be provisioned,
+	// required with OIDCAutoProvision since every user is an admin
+	OIDCAllowedDomains string `env:"OIDC_ALLOWED_DOMAINS" validate:"required_if=OIDCAutoProvision true"`
 }
 
 var validate = validator.New()
@@ -124,6 +205,8 @@ func formatValidationError(err validator.FieldError) string {
 	switch tag {
 	case "required":
 		return fmt.Sprintf("%s is required", field)
+	case "required_if":
+		return fmt.Sprintf("%s is required when %s", field, strings.Replace(err.Param(), " ", " is ", 1))
 	case "url":
 		return fmt.Sprintf("%s must be a valid URL", field)
 	case "numeric":
@@ -5,28 +5,42 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"peekaping/docs"
 	"peekaping/src/config"
 	"peekaping/src/modules/auth"
+	"peekaping/src/modules/blob"
+	"peekaping/src/modules/certificate"
 	"peekaping/src/modules/cleanup"
+	"peekaping/src/modules/client_cert"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/metrics"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_ack"
+	"peekaping/src/modules/monitor_config_version"
 	"peekaping/src/modules/monitor_maintenance"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/monitor_status_page"
 	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/monitor_webhook"
 	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/notification_failure"
+	"peekaping/src/modules/provisioning"
 	"peekaping/src/modules/proxy"
+	"peekaping/src/modules/report"
 	"peekaping/src/modules/setting"
 	"peekaping/src/modules/stats"
 	"peekaping/src/modules/status_page"
+	"peekaping/src/modules/status_page_incident"
+	"peekaping/src/modules/status_page_subscriber"
 	"peekaping/src/modules/tag"
 	"peekaping/src/modules/websocket"
 	"peekaping/src/utils"
 	"peekaping/src/version"
+	"syscall"
 
 	"go.uber.org/dig"
 	"go.uber.org/zap"
@@ -80,16 +94,28 @@ func main() {
 	healthcheck.RegisterDependencies(container)
 	auth.RegisterDependencies(container, &cfg)
 	notification_channel.RegisterDependencies(container, &cfg)
+	notification_failure.RegisterDependencies(container, &cfg)
 	monitor_notification.RegisterDependencies(container, &cfg)
 	proxy.RegisterDependencies(container, &cfg)
+	certificate.RegisterDependencies(container, &cfg)
+	blob.RegisterDependencies(container, &cfg)
 	setting.RegisterDependencies(container, &cfg)
+	client_cert.RegisterDependencies(container, &cfg)
 	stats.RegisterDependencies(container, &cfg)
 	monitor_maintenance.RegisterDependencies(container, &cfg)
 	maintenance.RegisterDependencies(container, &cfg)
 	status_page.RegisterDependencies(container, &cfg)
 	monitor_status_page.RegisterDependencies(container, &cfg)
+	status_page_subscriber.RegisterDependencies(container, &cfg)
+	status_page_incident.RegisterDependencies(container, &cfg)
 	tag.RegisterDependencies(container, &cfg)
 	monitor_tag.RegisterDependencies(container, &cfg)
+	monitor_config_version.RegisterDependencies(container, &cfg)
+	monitor_ack.RegisterDependencies(container, &cfg)
+	monitor_webhook.RegisterDependencies(container)
+	metrics.RegisterDependencies(container)
+	report.RegisterDependencies(container, &cfg)
+	provisioning.RegisterDependencies(container)
 
 	// Start the event healthcheck listener
 	err = container.Invoke(func(listener *healthcheck.EventListener, eventBus *events.EventBus) {
@@ -101,8 +127,58 @@ func main() {
 	}
 
 	// Start cleanup cron job(s)
-	err = container.Invoke(func(heartbeatService heartbeat.Service, settingService setting.Service, logger *zap.SugaredLogger) {
-		cleanup.StartCleanupCron(heartbeatService, settingService, logger)
+	err = container.Invoke(func(heartbeatService heartbeat.Service, monitorService monitor.Service, settingService setting.Service, failureService notification_failure.Service, logger *zap.SugaredLogger) {
+		cleanup.StartCleanupCron(heartbeatService, monitorService, settingService, failureService, logger)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start scheduled report cron job
+	err = container.Invoke(func(reportService report.Service) {
+		report.StartReportCron(reportService)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Resume paused monitors whose resume_at has passed
+	err = container.Invoke(func(monitorService monitor.Service, logger *zap.SugaredLogger) {
+		monitor.StartAutoResume(monitorService, logger)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Check the proxies monitors fail over between
+	err = container.Invoke(func(proxyService proxy.Service, logger *zap.SugaredLogger) {
+		proxy.StartHealthChecks(proxyService, cfg.ProxyHealthCheckInterval, logger)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the buffered heartbeat writer, flushing what is left on shutdown
+	err = container.Invoke(func(writer *heartbeat.Writer, logger *zap.SugaredLogger) {
+		writer.Start()
+
+		go func() {
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+			<-signals
+
+			logger.Info("Shutting down, flushing buffered heartbeats")
+			writer.Stop()
+			os.Exit(0)
+		}()
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Apply the monitors file, the healthcheck listener picks up created monitors
+	err = container.Invoke(func(service provisioning.Service, logger *zap.SugaredLogger) {
+		provisioning.ReconcileFromConfig(context.Background(), &cfg, service, logger)
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -125,6 +201,27 @@ func main() {
 		log.Fatal(err)
 	}
 
+	err = container.Invoke(func(listener *status_page_subscriber.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = container.Invoke(func(listener *monitor_webhook.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = container.Invoke(func(listener *proxy.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Start the monitor event listener
 	err = container.Invoke(func(listener *monitor.MonitorEventListener, eventBus *events.EventBus) {
 		listener.Subscribe(eventBus)
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"peekaping/src/config"
 	"peekaping/src/utils"
 	"strings"
 
@@ -14,15 +16,21 @@ import (
 
 type Controller struct {
 	service Service
+	oidc    *OIDCClient
+	cfg     *config.Config
 	logger  *zap.SugaredLogger
 }
 
 func NewController(
 	service Service,
+	oidc *OIDCClient,
+	cfg *config.Config,
 	logger *zap.SugaredLogger,
 ) *Controller {
 	return &Controller{
 		service: service,
+		oidc:    oidc,
+		cfg:     cfg,
 		logger:  logger,
 	}
 }
@@ -254,13 +262,13 @@ func (c *Controller) VerifyTwoFA(ctx *gin.Context) {
 		return
 	}
 
-	success, err := c.service.VerifyTwoFA(ctx, userId.(string), dto.Code)
+	recoveryCodes, err := c.service.VerifyTwoFA(ctx, userId.(string), dto.Code)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, TwoFAVerifyResponseDto{Success: false, Message: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, TwoFAVerifyResponseDto{Success: success, Message: "2FA verification successful"})
+	ctx.JSON(http.StatusOK, TwoFAVerifyResponseDto{Success: true, Message: "2FA verification successful", RecoveryCodes: recoveryCodes})
 }
 
 // @Router	/auth/2fa/disable [post]
@@ -293,3 +301,186 @@ func (c *Controller) DisableTwoFA(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("2FA disabled successfully", nil))
 }
+
+// @Router	/auth/2fa/recovery-codes [post]
+// @Summary	Regenerate 2FA recovery codes
+// @Tags		Auth
+// @Produce	json
+// @Accept	json
+// @Param	body body     TwoFARecoveryCodesRequestDto  true  "Recovery codes request"
+// @Success	200 {object} utils.ApiResponse[TwoFARecoveryCodesResponseDto]
+// @Failure	400 {object} utils.APIError[any]
+// @Failure	500 {object} utils.APIError[any]
+func (c *Controller) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userId, _ := ctx.Get("userId")
+
+	var dto TwoFARecoveryCodesRequestDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := c.validateWithDetails(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	codes, err := c.service.RegenerateRecoveryCodes(ctx, userId.(string), dto.Password)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Recovery codes regenerated successfully", TwoFARecoveryCodesResponseDto{RecoveryCodes: codes}))
+}
+
+// oidcStateCookie carries the sealed login from the browser that started it
+const oidcStateCookie = "peekaping_oidc_state"
+
+// @Router		/auth/oidc/login [get]
+// @Summary		Start OIDC login
+// @Description	Redirects to the identity provider
+// @Tags			Auth
+// @Success		302
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) OIDCLogin(ctx *gin.Context) {
+	if !c.oidc.Enabled() {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("OIDC login is disabled"))
+		return
+	}
+
+	authURL, sealed, err := c.oidc.AuthCodeURL(ctx)
+	if err != nil {
+		c.logger.Errorw("Failed to start OIDC login", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	c.setOIDCStateCookie(ctx, sealed, int(oidcStateTTL.Seconds()))
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// @Router		/auth/oidc/callback [get]
+// @Summary		Complete OIDC login
+// @Description	Redirects to the client with the tokens in the URL fragment, or an error code: provider_error, login_failed, email_not_verified, account_conflict, email_not_allowed or user_not_found. A user with 2FA enabled gets 2fa_required and a challenge to send to /auth/oidc/2fa with the token.
+// @Tags			Auth
+// @Param       code   query    string  false  "Authorization code"
+// @Param       state  query    string  false  "Login state"
+// @Success		302
+// @Failure		404	{object}	utils.APIError[any]
+func (c *Controller) OIDCCallback(ctx *gin.Context) {
+	if !c.oidc.Enabled() {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("OIDC login is disabled"))
+		return
+	}
+
+	sealed, _ := ctx.Cookie(oidcStateCookie)
+	c.setOIDCStateCookie(ctx, "", -1)
+
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		c.logger.Warnw("OIDC provider returned an error", "error", providerErr, "description", ctx.Query("error_description"))
+		c.redirectOIDCResult(ctx, url.Values{"error": {"provider_error"}})
+		return
+	}
+
+	identity, err := c.oidc.Exchange(ctx, ctx.Query("code"), ctx.Query("state"), sealed)
+	if err != nil {
+		c.logger.Errorw("Failed to complete OIDC login", "error", err)
+		c.redirectOIDCResult(ctx, url.Values{"error": {"login_failed"}})
+		return
+	}
+
+	response, err := c.service.LoginOIDC(ctx, identity)
+	if errors.Is(err, ErrOIDC2FARequired) {
+		challenge, err := c.oidc.SealTwoFAChallenge(response.User.ID)
+		if err != nil {
+			c.logger.Errorw("Failed to seal OIDC 2FA challenge", "error", err)
+			c.redirectOIDCResult(ctx, url.Values{"error": {"login_failed"}})
+			return
+		}
+		c.redirectOIDCResult(ctx, url.Values{"error": {"2fa_required"}, "challenge": {challenge}})
+		return
+	}
+	if err != nil {
+		c.logger.Errorw("Failed to login OIDC user", "subject", identity.Subject, "error", err)
+		c.redirectOIDCResult(ctx, url.Values{"error": {oidcErrorCode(err)}})
+		return
+	}
+
+	c.redirectOIDCResult(ctx, url.Values{
+		"accessToken":  {response.AccessToken},
+		"refreshToken": {response.RefreshToken},
+	})
+}
+
+// @Router		/auth/oidc/2fa [post]
+// @Summary		Complete OIDC login with 2FA
+// @Tags			Auth
+// @Produce		json
+// @Accept		json
+// @Param       body body     OIDCTwoFADto  true  "Challenge and 2FA token"
+// @Success		200	{object}	utils.ApiResponse[LoginResponse]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+func (c *Controller) OIDCTwoFA(ctx *gin.Context) {
+	if !c.oidc.Enabled() {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("OIDC login is disabled"))
+		return
+	}
+
+	var dto OIDCTwoFADto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := c.validateWithDetails(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	userID, err := c.oidc.OpenTwoFAChallenge(dto.Challenge)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	response, err := c.service.LoginOIDCTwoFA(ctx, userID, dto.Token, dto.RecoveryCode)
+	if err != nil {
+		c.logger.Errorw("Failed to complete OIDC 2FA login", "userId", userID, "error", err)
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Login successful", response))
+}
+
+// oidcErrorCode is the fixed code handed to the client for a refused login,
+// the other errors are only logged
+func oidcErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrOIDCEmailNotVerified):
+		return "email_not_verified"
+	case errors.Is(err, ErrOIDCAccountConflict):
+		return "account_conflict"
+	case errors.Is(err, ErrOIDCEmailNotAllowed):
+		return "email_not_allowed"
+	case errors.Is(err, ErrOIDCUserNotFound):
+		return "user_not_found"
+	default:
+		return "login_failed"
+	}
+}
+
+func (c *Controller) setOIDCStateCookie(ctx *gin.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(c.cfg.OIDCRedirectURL, "https://")
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, value, maxAge, "/api/v1/auth/oidc", "", secure, true)
+}
+
+// redirectOIDCResult hands the result to the client in the URL fragment, which
+// is not sent to servers or kept in their logs
+func (c *Controller) redirectOIDCResult(ctx *gin.Context, values url.Values) {
+	target := strings.TrimRight(c.cfg.ClientURL, "/") + "/login/oidc#" + values.Encode()
+	ctx.Redirect(http.StatusFound, target)
+}
@@ -12,6 +12,8 @@ func RegisterDependencies(container *dig.Container, cfg *config.Config) {
 
 	container.Provide(NewRoute)
 	container.Provide(NewTokenMaker)
+	container.Provide(NewTOTPCipher)
+	container.Provide(NewOIDCClient)
 	container.Provide(NewService)
 	container.Provide(NewController)
 	container.Provide(NewMiddlewareProvider)
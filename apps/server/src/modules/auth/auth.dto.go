@@ -9,6 +9,17 @@ type LoginDto struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 	Token    string `json:"token"`
+	// One-time recovery code used in place of the token
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// OIDCTwoFADto completes an OIDC login of a user with 2FA enabled
+type OIDCTwoFADto struct {
+	// Challenge handed to the client by the OIDC callback
+	Challenge string `json:"challenge" validate:"required"`
+	Token     string `json:"token"`
+	// One-time recovery code used in place of the token
+	RecoveryCode string `json:"recoveryCode"`
 }
 
 type RefreshTokenDto struct {
@@ -70,6 +81,8 @@ type TwoFAVerifyRequestDto struct {
 type TwoFAVerifyResponseDto struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	// Recovery codes issued when the verification enabled 2FA, shown once
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
 }
 
 // DTO for 2FA disable request
@@ -82,3 +95,18 @@ type TwoFADisableRequestDto struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// DTO for regenerating the 2FA recovery codes
+// swagger:model
+// @Description TwoFARecoveryCodesRequestDto is used to request new recovery codes
+// @Param password body string true "User password"
+type TwoFARecoveryCodesRequestDto struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// DTO for the 2FA recovery codes response
+// Contains the new recovery codes, replacing the previous ones
+// swagger:model
+type TwoFARecoveryCodesResponseDto struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
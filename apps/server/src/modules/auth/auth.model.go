@@ -14,6 +14,12 @@ type Model struct {
 	TwoFALastToken string    `json:"-"`
 	CreatedAt      time.Time `json:"createdAt"`
 	UpdatedAt      time.Time `json:"updatedAt"`
+
+	// Hashes of the unused recovery codes
+	TwoFARecoveryCodes []string `json:"-"`
+
+	// Issuer and subject of the linked identity provider account, as issuer|subject
+	OIDCSubject string `json:"-"`
 }
 
 type UpdateModel struct {
@@ -23,4 +29,8 @@ type UpdateModel struct {
 	TwoFASecret    *string `json:"twofa_secret"`
 	TwoFAStatus    *bool   `json:"twofa_status"`
 	TwoFALastToken *string `json:"twofa_last_token"`
+
+	TwoFARecoveryCodes *[]string `json:"twofa_recovery_codes"`
+
+	OIDCSubject *string `json:"oidc_subject"`
 }
@@ -21,6 +21,10 @@ type mongoModel struct {
 	TwoFALastToken string             `bson:"twofa_last_token"`
 	CreatedAt      time.Time          `bson:"createdAt"`
 	UpdatedAt      time.Time          `bson:"updatedAt"`
+
+	TwoFARecoveryCodes []string `bson:"twofa_recovery_codes,omitempty"`
+
+	OIDCSubject string `bson:"oidc_subject,omitempty"`
 }
 
 type mongoUpdateModel struct {
@@ -32,6 +36,10 @@ type mongoUpdateModel struct {
 	TwoFALastToken *string    `bson:"twofa_last_token,omitempty"`
 	CreatedAt      *time.Time `bson:"createdAt,omitempty"`
 	UpdatedAt      *time.Time `bson:"updatedAt,omitempty"`
+
+	TwoFARecoveryCodes *[]string `bson:"twofa_recovery_codes,omitempty"`
+
+	OIDCSubject *string `bson:"oidc_subject,omitempty"`
 }
 
 func toDomainModel(mm *mongoModel) *Model {
@@ -45,6 +53,10 @@ func toDomainModel(mm *mongoModel) *Model {
 		TwoFALastToken: mm.TwoFALastToken,
 		CreatedAt:      mm.CreatedAt,
 		UpdatedAt:      mm.UpdatedAt,
+
+		TwoFARecoveryCodes: mm.TwoFARecoveryCodes,
+
+		OIDCSubject: mm.OIDCSubject,
 	}
 }
 
@@ -71,6 +83,10 @@ func (r *RepositoryImpl) Create(ctx context.Context, user *Model) (*Model, error
 		TwoFALastToken: user.TwoFALastToken,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+
+		TwoFARecoveryCodes: user.TwoFARecoveryCodes,
+
+		OIDCSubject: user.OIDCSubject,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mm)
@@ -93,6 +109,18 @@ func (r *RepositoryImpl) FindByEmail(ctx context.Context, email string) (*Model,
 	return toDomainModel(&admin), nil
 }
 
+func (r *RepositoryImpl) FindByOIDCSubject(ctx context.Context, subject string) (*Model, error) {
+	var entity mongoModel
+	err := r.collection.FindOne(ctx, bson.M{"oidc_subject": subject}).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModel(&entity), nil
+}
+
 func (r *RepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
 	var entity mongoModel
 
@@ -125,6 +153,10 @@ func (r *RepositoryImpl) Update(ctx context.Context, id string, entity *UpdateMo
 		TwoFASecret:    entity.TwoFASecret,
 		TwoFAStatus:    entity.TwoFAStatus,
 		TwoFALastToken: entity.TwoFALastToken,
+
+		TwoFARecoveryCodes: entity.TwoFARecoveryCodes,
+
+		OIDCSubject: entity.OIDCSubject,
 	}
 
 	set := buildSetMapFromUpdateModel(mu)
@@ -169,6 +201,12 @@ func buildSetMapFromUpdateModel(mu *mongoUpdateModel) bson.M {
 	if mu.TwoFALastToken != nil {
 		set["twofa_last_token"] = *mu.TwoFALastToken
 	}
+	if mu.TwoFARecoveryCodes != nil {
+		set["twofa_recovery_codes"] = *mu.TwoFARecoveryCodes
+	}
+	if mu.OIDCSubject != nil {
+		set["oidc_subject"] = *mu.OIDCSubject
+	}
 	if mu.CreatedAt != nil {
 		set["createdAt"] = *mu.CreatedAt
 	}
@@ -24,10 +24,14 @@ func (r *Route) ConnectRoute(router *gin.RouterGroup, controller *Controller) {
 	auth.POST("/register", controller.Register)
 	auth.POST("/login", controller.Login)
 	auth.POST("/refresh", controller.RefreshToken)
+	auth.GET("/oidc/login", controller.OIDCLogin)
+	auth.GET("/oidc/callback", controller.OIDCCallback)
+	auth.POST("/oidc/2fa", controller.OIDCTwoFA)
 
 	auth.Use(r.middleware.Auth())
 	auth.POST("/2fa/setup", controller.SetupTwoFA)
 	auth.POST("/2fa/verify", controller.VerifyTwoFA)
 	auth.POST("/2fa/disable", controller.DisableTwoFA)
+	auth.POST("/2fa/recovery-codes", controller.RegenerateRecoveryCodes)
 	auth.PUT("/password", controller.UpdatePassword)
 }
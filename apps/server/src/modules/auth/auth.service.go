@@ -3,11 +3,21 @@ package auth
 import (
 	"context"
 	"errors"
+	"peekaping/src/config"
 	"time"
 
 	"github.com/pquerna/otp/totp"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
+)
+
+// Refusals of an OIDC login, the client is told which one happened
+var (
+	ErrOIDCEmailNotVerified = errors.New("identity provider did not return a verified email")
+	ErrOIDCAccountConflict  = errors.New("user is linked to another identity provider account")
+	ErrOIDCEmailNotAllowed  = errors.New("email is not allowed to be provisioned")
+	ErrOIDCUserNotFound     = errors.New("no user is linked to this identity provider account")
+	// ErrOIDC2FARequired asks the user for the second factor, like a password login
+	ErrOIDC2FARequired = errors.New("2FA token required")
 )
 
 type Service interface {
@@ -18,24 +28,40 @@ type Service interface {
 
 	// 2FA methods
 	SetupTwoFA(ctx context.Context, userId, password string) (secret string, provisioningURI string, err error)
-	VerifyTwoFA(ctx context.Context, userId, code string) (bool, error)
+	// VerifyTwoFA checks a code of the authenticator, the first valid code
+	// enables 2FA and returns the recovery codes
+	VerifyTwoFA(ctx context.Context, userId, code string) (recoveryCodes []string, err error)
 	DisableTwoFA(ctx context.Context, userId, password string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId, password string) ([]string, error)
+
+	// LoginOIDC logs in the user linked to the provider account, linking a
+	// user by verified email on the first login. A user with 2FA enabled gets
+	// ErrOIDC2FARequired along with a response carrying only the user.
+	LoginOIDC(ctx context.Context, identity *OIDCIdentity) (*LoginResponse, error)
+	// LoginOIDCTwoFA completes an OIDC login of a user with 2FA enabled
+	LoginOIDCTwoFA(ctx context.Context, userId, token, recoveryCode string) (*LoginResponse, error)
 }
 
 type ServiceImpl struct {
 	repo       Repository
 	tokenMaker *TokenMaker
+	totpCipher *TOTPCipher
+	cfg        *config.Config
 	logger     *zap.SugaredLogger
 }
 
 func NewService(
 	repo Repository,
 	tokenMaker *TokenMaker,
+	totpCipher *TOTPCipher,
+	cfg *config.Config,
 	logger *zap.SugaredLogger,
 ) Service {
 	return &ServiceImpl{
 		repo:       repo,
 		tokenMaker: tokenMaker,
+		totpCipher: totpCipher,
+		cfg:        cfg,
 		logger:     logger.Named("[auth-service]"),
 	}
 }
@@ -56,7 +82,7 @@ func (s *ServiceImpl) Register(ctx context.Context, dto RegisterDto) (*LoginResp
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+	hashedPassword, err := HashPassword(dto.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +90,7 @@ func (s *ServiceImpl) Register(ctx context.Context, dto RegisterDto) (*LoginResp
 	// Create new admin
 	user := &Model{
 		Email:     dto.Email,
-		Password:  string(hashedPassword),
+		Password:  hashedPassword,
 		Active:    true,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
@@ -106,18 +132,15 @@ func (s *ServiceImpl) Login(ctx context.Context, dto LoginDto) (*LoginResponse,
 	}
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(dto.Password))
+	err = ComparePassword(user.Password, dto.Password)
 	if err != nil {
 		return nil, errors.New("invalid credentials")
 	}
 
 	// Enforce 2FA if enabled
-	if user.TwoFASecret != "" && user.TwoFAStatus {
-		if dto.Token == "" {
-			return nil, errors.New("2FA token required")
-		}
-		if !totp.Validate(dto.Token, user.TwoFASecret) {
-			return nil, errors.New("invalid 2FA token")
+	if twoFAEnabled(user) {
+		if err := s.checkSecondFactor(ctx, user, dto.Token, dto.RecoveryCode); err != nil {
+			return nil, err
 		}
 	}
 
@@ -185,18 +208,18 @@ func (s *ServiceImpl) UpdatePassword(ctx context.Context, userId string, dto Upd
 	}
 
 	// Verify current password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(dto.CurrentPassword))
+	err = ComparePassword(user.Password, dto.CurrentPassword)
 	if err != nil {
 		return errors.New("current password is incorrect")
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.NewPassword), bcrypt.DefaultCost)
+	hashedPassword, err := HashPassword(dto.NewPassword)
 	if err != nil {
 		return errors.New("failed to hash new password")
 	}
 
-	password := string(hashedPassword)
+	password := hashedPassword
 	updateModel := &UpdateModel{
 		Password: &password,
 	}
@@ -217,63 +240,67 @@ func (s *ServiceImpl) SetupTwoFA(ctx context.Context, userId, password string) (
 	}
 
 	// Require password verification
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = ComparePassword(user.Password, password)
 	if err != nil {
 		return "", "", errors.New("invalid password")
 	}
-	var secretStr, provisioningURI string
-	if user.TwoFASecret == "" {
-		// Generate new secret and provisioning URI
-		key, err := totp.Generate(totp.GenerateOpts{
-			Issuer:      "peekaping",
-			AccountName: user.Email,
-		})
-		if err != nil {
-			return "", "", err
-		}
-		secretStr = key.Secret()
-		provisioningURI = key.URL()
-		updateModel := &UpdateModel{
-			TwoFASecret: &secretStr,
-		}
-		err = s.repo.Update(ctx, userId, updateModel)
-		if err != nil {
-			return "", "", err
-		}
-	} else {
-		// If already set, just return existing
-		secretStr = user.TwoFASecret
-		// Recreate the provisioning URI
-		key, err := totp.Generate(totp.GenerateOpts{
-			Issuer:      "peekaping",
-			AccountName: user.Email,
-			Secret:      []byte(user.TwoFASecret),
-		})
-		if err != nil {
-			return "", "", err
-		}
-		provisioningURI = key.URL()
+	if user.TwoFAStatus {
+		return "", "", errors.New("2FA is already enabled")
+	}
+	if !s.totpCipher.Enabled() {
+		return "", "", ErrTwoFAKeyMissing
+	}
+
+	// Every setup starts over with a new secret until a code verifies it
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      "peekaping",
+		AccountName: user.Email,
+		SecretSize:  20,
+	})
+	if err != nil {
+		return "", "", err
 	}
-	return secretStr, provisioningURI, nil
+	encrypted, err := s.totpCipher.Encrypt(user.ID, key.Secret())
+	if err != nil {
+		return "", "", err
+	}
+	updateModel := &UpdateModel{
+		TwoFASecret: &encrypted,
+	}
+	if err := s.repo.Update(ctx, userId, updateModel); err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
 }
 
-func (s *ServiceImpl) VerifyTwoFA(ctx context.Context, userId, code string) (bool, error) {
+func (s *ServiceImpl) VerifyTwoFA(ctx context.Context, userId, code string) ([]string, error) {
 	user, err := s.repo.FindByID(ctx, userId)
 	if err != nil || user == nil {
-		return false, errors.New("user not found")
+		return nil, errors.New("user not found")
 	}
 	if user.TwoFASecret == "" {
-		return false, errors.New("2FA not setup")
+		return nil, errors.New("2FA not setup")
 	}
-	valid := totp.Validate(code, user.TwoFASecret)
-	if valid {
-		status := true
-		updateModel := &UpdateModel{
-			TwoFAStatus: &status,
-		}
-		s.repo.Update(ctx, userId, updateModel)
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+	if user.TwoFAStatus {
+		return nil, nil
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	status := true
+	updateModel := &UpdateModel{
+		TwoFAStatus:        &status,
+		TwoFARecoveryCodes: &hashes,
+	}
+	if err := s.repo.Update(ctx, userId, updateModel); err != nil {
+		return nil, errors.New("failed to enable 2FA")
 	}
-	return valid, nil
+	return codes, nil
 }
 
 func (s *ServiceImpl) DisableTwoFA(ctx context.Context, userId, password string) error {
@@ -282,7 +309,7 @@ func (s *ServiceImpl) DisableTwoFA(ctx context.Context, userId, password string)
 		return errors.New("user not found")
 	}
 	// Require password verification
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = ComparePassword(user.Password, password)
 	if err != nil {
 		return errors.New("invalid password")
 	}
@@ -290,10 +317,14 @@ func (s *ServiceImpl) DisableTwoFA(ctx context.Context, userId, password string)
 	// Prepare values for pointers
 	secret := ""
 	status := false
+	lastToken := ""
+	recoveryCodes := []string{}
 
 	updateModel := &UpdateModel{
-		TwoFASecret: &secret,
-		TwoFAStatus: &status,
+		TwoFASecret:        &secret,
+		TwoFAStatus:        &status,
+		TwoFALastToken:     &lastToken,
+		TwoFARecoveryCodes: &recoveryCodes,
 	}
 
 	err = s.repo.Update(ctx, userId, updateModel)
@@ -303,3 +334,182 @@ func (s *ServiceImpl) DisableTwoFA(ctx context.Context, userId, password string)
 
 	return nil
 }
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, the unused
+// ones stop working
+func (s *ServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userId, password string) ([]string, error) {
+	user, err := s.repo.FindByID(ctx, userId)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := ComparePassword(user.Password, password); err != nil {
+		return nil, errors.New("invalid password")
+	}
+	if !user.TwoFAStatus {
+		return nil, errors.New("2FA is not enabled")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, userId, &UpdateModel{TwoFARecoveryCodes: &hashes}); err != nil {
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+	return codes, nil
+}
+
+// checkTOTP validates the code against the secret of the user. A code is
+// accepted once, and a secret stored before the encryption is encrypted on
+// its first use.
+func (s *ServiceImpl) checkTOTP(ctx context.Context, user *Model, code string) error {
+	secret, legacy, err := s.totpCipher.Decrypt(user.ID, user.TwoFASecret)
+	if err != nil {
+		s.logger.Errorw("Failed to read 2FA secret", "userId", user.ID, "error", err)
+		return errors.New("invalid 2FA token")
+	}
+	if user.TwoFALastToken != "" && code == user.TwoFALastToken {
+		return errors.New("2FA token already used")
+	}
+	if !totp.Validate(code, secret) {
+		return errors.New("invalid 2FA token")
+	}
+
+	updateModel := &UpdateModel{
+		TwoFALastToken: &code,
+	}
+	if legacy {
+		encrypted, err := s.totpCipher.Encrypt(user.ID, secret)
+		if err == nil {
+			updateModel.TwoFASecret = &encrypted
+		}
+	}
+	if err := s.repo.Update(ctx, user.ID, updateModel); err != nil {
+		s.logger.Errorw("Failed to record used 2FA token", "userId", user.ID, "error", err)
+	}
+	return nil
+}
+
+// useRecoveryCode consumes one of the recovery codes of the user
+func (s *ServiceImpl) useRecoveryCode(ctx context.Context, user *Model, code string) error {
+	left, ok := consumeRecoveryCode(user.TwoFARecoveryCodes, code)
+	if !ok {
+		return errors.New("invalid recovery code")
+	}
+	if err := s.repo.Update(ctx, user.ID, &UpdateModel{TwoFARecoveryCodes: &left}); err != nil {
+		return errors.New("failed to use recovery code")
+	}
+	s.logger.Infow("Recovery code used to log in", "userId", user.ID, "left", len(left))
+	return nil
+}
+
+func twoFAEnabled(user *Model) bool {
+	return user.TwoFASecret != "" && user.TwoFAStatus
+}
+
+// checkSecondFactor checks the token of the authenticator, a recovery code
+// stands in for the token
+func (s *ServiceImpl) checkSecondFactor(ctx context.Context, user *Model, token, recoveryCode string) error {
+	if token == "" && recoveryCode == "" {
+		return ErrOIDC2FARequired
+	}
+	if token != "" {
+		return s.checkTOTP(ctx, user, token)
+	}
+	return s.useRecoveryCode(ctx, user, recoveryCode)
+}
+
+func (s *ServiceImpl) LoginOIDC(ctx context.Context, identity *OIDCIdentity) (*LoginResponse, error) {
+	user, err := s.repo.FindByOIDCSubject(ctx, identity.accountKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		if identity.Email == "" || !identity.EmailVerified {
+			return nil, ErrOIDCEmailNotVerified
+		}
+		user, err = s.repo.FindByEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case user != nil && user.OIDCSubject != "":
+			return nil, ErrOIDCAccountConflict
+		case user != nil:
+			subject := identity.accountKey()
+			if err := s.repo.Update(ctx, user.ID, &UpdateModel{OIDCSubject: &subject}); err != nil {
+				return nil, err
+			}
+			s.logger.Infow("Linked user to OIDC account", "userId", user.ID)
+		case s.cfg.OIDCAutoProvision && !oidcEmailAllowed(s.cfg.OIDCAllowedDomains, identity.Email):
+			return nil, ErrOIDCEmailNotAllowed
+		case s.cfg.OIDCAutoProvision:
+			user, err = s.provisionOIDCUser(ctx, identity)
+			if err != nil {
+				return nil, err
+			}
+			s.logger.Infow("Provisioned user from OIDC account", "userId", user.ID)
+		default:
+			return nil, ErrOIDCUserNotFound
+		}
+	}
+
+	if twoFAEnabled(user) {
+		return &LoginResponse{User: user}, ErrOIDC2FARequired
+	}
+	return s.issueTokens(user)
+}
+
+func (s *ServiceImpl) LoginOIDCTwoFA(ctx context.Context, userId, token, recoveryCode string) (*LoginResponse, error) {
+	user, err := s.repo.FindByID(ctx, userId)
+	if err != nil || user == nil {
+		return nil, errors.New("invalid login")
+	}
+	if twoFAEnabled(user) {
+		if err := s.checkSecondFactor(ctx, user, token, recoveryCode); err != nil {
+			return nil, err
+		}
+	}
+	return s.issueTokens(user)
+}
+
+func (s *ServiceImpl) issueTokens(user *Model) (*LoginResponse, error) {
+	accessToken, err := s.tokenMaker.CreateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.tokenMaker.CreateRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         user,
+	}, nil
+}
+
+// provisionOIDCUser creates the user of a provider account. Its random
+// password is never shown, the user logs in through the provider.
+func (s *ServiceImpl) provisionOIDCUser(ctx context.Context, identity *OIDCIdentity) (*Model, error) {
+	password, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, &Model{
+		Email:       identity.Email,
+		Password:    hashedPassword,
+		Active:      true,
+		OIDCSubject: identity.accountKey(),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	})
+}
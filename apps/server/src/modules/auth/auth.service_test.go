@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		AccessTokenSecretKey:  "access-secret-access-secret",
+		RefreshTokenSecretKey: "refresh-secret-refresh-secret",
+		TwoFASecretKey:        "twofa-secret-twofa-secret",
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenExpiresIn: time.Hour,
+	}
+}
+
+func newTestService(t *testing.T) (*ServiceImpl, Repository) {
+	t.Helper()
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(context.Background())
+	require.NoError(t, err)
+
+	cipher, err := NewTOTPCipher(testConfig())
+	require.NoError(t, err)
+	repo := NewSQLRepository(db)
+	return NewService(repo, NewTokenMaker(testConfig()), cipher, testConfig(), zap.NewNop().Sugar()).(*ServiceImpl), repo
+}
+
+func TestTOTPCipher(t *testing.T) {
+	cipher, err := NewTOTPCipher(testConfig())
+	require.NoError(t, err)
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "peekaping", AccountName: "admin@example.com", SecretSize: 20})
+	require.NoError(t, err)
+
+	encrypted, err := cipher.Encrypt("user-1", key.Secret())
+	require.NoError(t, err)
+	assert.Len(t, encrypted, encryptedSecretLen, "expected the secret to fit the twofa_secret column")
+	assert.NotContains(t, encrypted, key.Secret())
+
+	secret, legacy, err := cipher.Decrypt("user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, key.Secret(), secret)
+	assert.False(t, legacy)
+
+	_, _, err = cipher.Decrypt("user-2", encrypted)
+	assert.Error(t, err, "expected the secret to be bound to its user")
+
+	cfg := testConfig()
+	cfg.TwoFASecretKey = "another-key-another-key"
+	other, err := NewTOTPCipher(cfg)
+	require.NoError(t, err)
+	_, _, err = other.Decrypt("user-1", encrypted)
+	assert.Error(t, err)
+
+	secret, legacy, err = cipher.Decrypt("user-1", key.Secret())
+	require.NoError(t, err)
+	assert.Equal(t, key.Secret(), secret, "expected a plaintext secret to be read as is")
+	assert.True(t, legacy)
+}
+
+func TestTOTPCipher_RequiresKey(t *testing.T) {
+	cfg := testConfig()
+	cfg.TwoFASecretKey = ""
+	cipher, err := NewTOTPCipher(cfg)
+	require.NoError(t, err)
+	assert.False(t, cipher.Enabled())
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "peekaping", AccountName: "admin@example.com", SecretSize: 20})
+	require.NoError(t, err)
+	_, err = cipher.Encrypt("user-1", key.Secret())
+	assert.ErrorIs(t, err, ErrTwoFAKeyMissing)
+
+	secret, legacy, err := cipher.Decrypt("user-1", key.Secret())
+	require.NoError(t, err)
+	assert.Equal(t, key.Secret(), secret)
+	assert.True(t, legacy)
+
+	// the token secrets do not take part in the key
+	rotated := testConfig()
+	rotated.AccessTokenSecretKey = "rotated-secret-rotated-secret"
+	before, err := NewTOTPCipher(testConfig())
+	require.NoError(t, err)
+	after, err := NewTOTPCipher(rotated)
+	require.NoError(t, err)
+	encrypted, err := before.Encrypt("user-1", key.Secret())
+	require.NoError(t, err)
+	secret, _, err = after.Decrypt("user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, key.Secret(), secret)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+	assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, codes[0])
+	assert.NotContains(t, hashes, codes[0])
+
+	left, ok := consumeRecoveryCode(hashes, strings.ToUpper(strings.ReplaceAll(codes[3], "-", "")))
+	assert.True(t, ok, "expected case and dashes to be ignored")
+	assert.Len(t, left, recoveryCodeCount-1)
+
+	_, ok = consumeRecoveryCode(left, codes[3])
+	assert.False(t, ok, "expected a code to work once")
+}
+
+func TestService_TwoFA(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestService(t)
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+	userID := registered.User.ID
+	login := LoginDto{Email: "admin@example.com", Password: "Secret123!"}
+
+	_, _, err = s.SetupTwoFA(ctx, userID, "wrong")
+	assert.EqualError(t, err, "invalid password")
+
+	secret, uri, err := s.SetupTwoFA(ctx, userID, "Secret123!")
+	require.NoError(t, err)
+	assert.Contains(t, uri, "otpauth://totp/peekaping:admin@example.com")
+	assert.Contains(t, uri, "secret="+secret)
+
+	stored, err := repo.FindByID(ctx, userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, stored.TwoFASecret, "expected the secret to be stored encrypted")
+
+	// not enforced before a code is verified
+	_, err = s.Login(ctx, login)
+	require.NoError(t, err)
+
+	_, err = s.VerifyTwoFA(ctx, userID, "000000")
+	assert.Error(t, err)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err := s.VerifyTwoFA(ctx, userID, code)
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+
+	_, err = s.Login(ctx, login)
+	assert.EqualError(t, err, "2FA token required")
+
+	withToken := login
+	withToken.Token = code
+	_, err = s.Login(ctx, withToken)
+	assert.EqualError(t, err, "2FA token already used")
+
+	withToken.Token, err = totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+	_, err = s.Login(ctx, withToken)
+	require.NoError(t, err)
+
+	withRecovery := login
+	withRecovery.RecoveryCode = recoveryCodes[0]
+	_, err = s.Login(ctx, withRecovery)
+	require.NoError(t, err)
+	_, err = s.Login(ctx, withRecovery)
+	assert.EqualError(t, err, "invalid recovery code")
+
+	_, _, err = s.SetupTwoFA(ctx, userID, "Secret123!")
+	assert.EqualError(t, err, "2FA is already enabled")
+
+	regenerated, err := s.RegenerateRecoveryCodes(ctx, userID, "Secret123!")
+	require.NoError(t, err)
+	withRecovery.RecoveryCode = recoveryCodes[1]
+	_, err = s.Login(ctx, withRecovery)
+	assert.EqualError(t, err, "invalid recovery code", "expected the previous codes to stop working")
+	withRecovery.RecoveryCode = regenerated[0]
+	_, err = s.Login(ctx, withRecovery)
+	require.NoError(t, err)
+
+	require.NoError(t, s.DisableTwoFA(ctx, userID, "Secret123!"))
+	_, err = s.Login(ctx, login)
+	require.NoError(t, err)
+	_, err = s.RegenerateRecoveryCodes(ctx, userID, "Secret123!")
+	assert.EqualError(t, err, "2FA is not enabled")
+}
+
+func TestService_SetupTwoFA_RequiresKey(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t)
+	s.totpCipher = &TOTPCipher{}
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+
+	_, _, err = s.SetupTwoFA(ctx, registered.User.ID, "Secret123!")
+	assert.ErrorIs(t, err, ErrTwoFAKeyMissing)
+}
+
+func TestService_TwoFA_LegacySecret(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestService(t)
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+	userID := registered.User.ID
+
+	// a secret enabled before the secrets were encrypted
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "peekaping", AccountName: "admin@example.com"})
+	require.NoError(t, err)
+	secret, status := key.Secret(), true
+	require.NoError(t, repo.Update(ctx, userID, &UpdateModel{TwoFASecret: &secret, TwoFAStatus: &status}))
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	_, err = s.Login(ctx, LoginDto{Email: "admin@example.com", Password: "Secret123!", Token: code})
+	require.NoError(t, err)
+
+	stored, err := repo.FindByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, stored.TwoFASecret, encryptedSecretLen, "expected the secret to be encrypted on first use")
+	assert.Equal(t, code, stored.TwoFALastToken)
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,10 @@ type sqlModel struct {
 	TwoFALastToken string    `bun:"twofa_last_token"`
 	CreatedAt      time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt      time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	TwoFARecoveryCodes string `bun:"twofa_recovery_codes"`
+
+	OIDCSubject *string `bun:"oidc_subject"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -33,6 +38,10 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		TwoFALastToken: sm.TwoFALastToken,
 		CreatedAt:      sm.CreatedAt,
 		UpdatedAt:      sm.UpdatedAt,
+
+		TwoFARecoveryCodes: splitRecoveryCodes(sm.TwoFARecoveryCodes),
+
+		OIDCSubject: stringValue(sm.OIDCSubject),
 	}
 }
 
@@ -47,7 +56,35 @@ func toSQLModel(m *Model) *sqlModel {
 		TwoFALastToken: m.TwoFALastToken,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
+
+		TwoFARecoveryCodes: strings.Join(m.TwoFARecoveryCodes, ","),
+
+		OIDCSubject: nullableString(m.OIDCSubject),
+	}
+}
+
+// nullableString stores an empty subject as NULL, the unique index allows
+// several NULLs only
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// splitRecoveryCodes reads the comma separated recovery code hashes
+func splitRecoveryCodes(joined string) []string {
+	if joined == "" {
+		return nil
 	}
+	return strings.Split(joined, ",")
 }
 
 type SQLRepositoryImpl struct {
@@ -69,6 +106,10 @@ func (r *SQLRepositoryImpl) Create(ctx context.Context, user *Model) (*Model, er
 		TwoFALastToken: user.TwoFALastToken,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+
+		TwoFARecoveryCodes: strings.Join(user.TwoFARecoveryCodes, ","),
+
+		OIDCSubject: nullableString(user.OIDCSubject),
 	}
 
 	// Let Bun handle ID generation based on the database type
@@ -104,6 +145,18 @@ func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, er
 	return toDomainModelFromSQL(sm), nil
 }
 
+func (r *SQLRepositoryImpl) FindByOIDCSubject(ctx context.Context, subject string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("oidc_subject = ?", subject).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
 func (r *SQLRepositoryImpl) FindAllCount(ctx context.Context) (int64, error) {
 	count, err := r.db.NewSelect().Model((*sqlModel)(nil)).Count(ctx)
 	return int64(count), err
@@ -138,6 +191,14 @@ func (r *SQLRepositoryImpl) Update(ctx context.Context, id string, entity *Updat
 		query = query.Set("twofa_last_token = ?", *entity.TwoFALastToken)
 		hasUpdates = true
 	}
+	if entity.TwoFARecoveryCodes != nil {
+		query = query.Set("twofa_recovery_codes = ?", strings.Join(*entity.TwoFARecoveryCodes, ","))
+		hasUpdates = true
+	}
+	if entity.OIDCSubject != nil {
+		query = query.Set("oidc_subject = ?", nullableString(*entity.OIDCSubject))
+		hasUpdates = true
+	}
 
 	if !hasUpdates {
 		return nil
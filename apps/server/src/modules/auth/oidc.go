@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"peekaping/src/config"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// oidcStateTTL bounds the time between the redirect to the provider and the
+// callback
+const oidcStateTTL = 10 * time.Minute
+
+// oidcChallengeTTL bounds the time a user has to enter the 2FA token after
+// the provider authenticated them
+const oidcChallengeTTL = 5 * time.Minute
+
+// OIDCIdentity is the verified account of the identity provider
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// accountKey identifies the account across providers, a subject is only
+// unique within its issuer
+func (i *OIDCIdentity) accountKey() string {
+	return i.Issuer + "|" + i.Subject
+}
+
+// oidcPendingLogin is kept from the redirect to the provider until the
+// callback, sealed in the state cookie of the browser
+type oidcPendingLogin struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	Expires  int64  `json:"e"`
+}
+
+// oidcPendingTwoFA is a login the provider completed for a user with 2FA
+// enabled, sealed and handed to the client until the token is entered
+type oidcPendingTwoFA struct {
+	UserID  string `json:"u"`
+	Expires int64  `json:"e"`
+}
+
+// Purposes of the sealed values, a value sealed for one purpose does not
+// open for another
+var (
+	oidcLoginPurpose = []byte("login")
+	oidcTwoFAPurpose = []byte("2fa")
+)
+
+// OIDCClient runs the authorization code flow against the configured
+// provider. The provider is discovered on the first login so an unreachable
+// provider does not prevent the server from starting.
+type OIDCClient struct {
+	cfg    *config.Config
+	aead   cipher.AEAD
+	logger *zap.SugaredLogger
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCClient(cfg *config.Config, logger *zap.SugaredLogger) (*OIDCClient, error) {
+	sum := sha256.Sum256([]byte("peekaping-oidc:" + cfg.AccessTokenSecretKey))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCClient{
+		cfg:    cfg,
+		aead:   aead,
+		logger: logger.Named("[oidc]"),
+	}, nil
+}
+
+// Enabled tells whether OIDC login is configured
+func (c *OIDCClient) Enabled() bool {
+	return c.cfg.OIDCEnabled
+}
+
+func (c *OIDCClient) discover(ctx context.Context) (*oidc.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.provider != nil {
+		return c.provider, nil
+	}
+	provider, err := oidc.NewProvider(ctx, c.cfg.OIDCIssuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+	c.provider = provider
+	return provider, nil
+}
+
+func (c *OIDCClient) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range strings.Split(c.cfg.OIDCScopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" && scope != oidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:     c.cfg.OIDCClientID,
+		ClientSecret: c.cfg.OIDCClientSecret,
+		RedirectURL:  c.cfg.OIDCRedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+}
+
+// AuthCodeURL starts a login, it returns the provider URL to redirect to and
+// the sealed login to keep in the state cookie until the callback
+func (c *OIDCClient) AuthCodeURL(ctx context.Context) (string, string, error) {
+	provider, err := c.discover(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	login := oidcPendingLogin{
+		Verifier: oauth2.GenerateVerifier(),
+		Expires:  time.Now().Add(oidcStateTTL).Unix(),
+	}
+	if login.State, err = randomToken(); err != nil {
+		return "", "", err
+	}
+	if login.Nonce, err = randomToken(); err != nil {
+		return "", "", err
+	}
+	sealed, err := c.sealLogin(login)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL := c.oauth2Config(provider).AuthCodeURL(login.State, oidc.Nonce(login.Nonce), oauth2.S256ChallengeOption(login.Verifier))
+	return authURL, sealed, nil
+}
+
+// Exchange completes the login sealed in the state cookie, it redeems the code
+// and verifies the ID token the provider returns
+func (c *OIDCClient) Exchange(ctx context.Context, code, state, sealed string) (*OIDCIdentity, error) {
+	login, err := c.openLogin(sealed)
+	if err != nil || time.Now().Unix() > login.Expires ||
+		subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		return nil, errors.New("invalid or expired login state")
+	}
+	if code == "" {
+		return nil, errors.New("authorization code is missing")
+	}
+
+	provider, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("provider returned no ID token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: c.cfg.OIDCClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	if idToken.Nonce != login.Nonce {
+		return nil, errors.New("invalid ID token: nonce does not match")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid ID token claims: %w", err)
+	}
+
+	return &OIDCIdentity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+// SealTwoFAChallenge seals the pending login of a user with 2FA enabled
+func (c *OIDCClient) SealTwoFAChallenge(userID string) (string, error) {
+	return c.seal(oidcTwoFAPurpose, oidcPendingTwoFA{
+		UserID:  userID,
+		Expires: time.Now().Add(oidcChallengeTTL).Unix(),
+	})
+}
+
+// OpenTwoFAChallenge returns the user of a sealed pending login
+func (c *OIDCClient) OpenTwoFAChallenge(sealed string) (string, error) {
+	var pending oidcPendingTwoFA
+	if err := c.open(oidcTwoFAPurpose, sealed, &pending); err != nil ||
+		pending.UserID == "" || time.Now().Unix() > pending.Expires {
+		return "", errors.New("invalid or expired 2FA challenge")
+	}
+	return pending.UserID, nil
+}
+
+func (c *OIDCClient) sealLogin(login oidcPendingLogin) (string, error) {
+	return c.seal(oidcLoginPurpose, login)
+}
+
+func (c *OIDCClient) openLogin(sealed string) (*oidcPendingLogin, error) {
+	var login oidcPendingLogin
+	if err := c.open(oidcLoginPurpose, sealed, &login); err != nil {
+		return nil, errors.New("invalid login state")
+	}
+	return &login, nil
+}
+
+func (c *OIDCClient) seal(purpose []byte, value any) (string, error) {
+	plain, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plain, purpose)), nil
+}
+
+func (c *OIDCClient) open(purpose []byte, sealed string, value any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < c.aead.NonceSize() {
+		return errors.New("invalid sealed value")
+	}
+	plain, err := c.aead.Open(nil, raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():], purpose)
+	if err != nil {
+		return errors.New("invalid sealed value")
+	}
+	return json.Unmarshal(plain, value)
+}
+
+// oidcEmailAllowed tells whether the email is in the comma separated list of
+// emails and domains, ignoring case
+func oidcEmailAllowed(allowed, email string) bool {
+	email = strings.ToLower(email)
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return false
+	}
+	for _, entry := range strings.Split(allowed, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "@") && !strings.HasPrefix(entry, "@") {
+			if entry == email {
+				return true
+			}
+		} else if strings.TrimPrefix(entry, "@") == email[at+1:] {
+			return true
+		}
+	}
+	return false
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
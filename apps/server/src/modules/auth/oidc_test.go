@@ -0,0 +1,431 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeIdP is an identity provider issuing an ID token for whatever account is
+// set on it
+type fakeIdP struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	subject       string
+	email         string
+	emailVerified bool
+
+	nonce string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &fakeIdP{key: key, subject: "idp-user-1", email: "admin@example.com", emailVerified: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &idp.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code_challenge_method") != "S256" {
+			http.Error(w, "PKCE required", http.StatusBadRequest)
+			return
+		}
+		idp.nonce = r.URL.Query().Get("nonce")
+		redirect := r.URL.Query().Get("redirect_uri") + "?code=good-code&state=" + url.QueryEscape(r.URL.Query().Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") == "" {
+			http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "idp-access-token",
+			"token_type":   "Bearer",
+			"id_token":     idp.idToken(t),
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *fakeIdP) idToken(t *testing.T) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: idp.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"),
+	)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(map[string]any{
+		"iss":            idp.server.URL,
+		"sub":            idp.subject,
+		"aud":            "peekaping",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          idp.nonce,
+		"email":          idp.email,
+		"email_verified": idp.emailVerified,
+	}).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+func newOIDCTestRouter(t *testing.T, idp *fakeIdP, autoProvision bool) (*gin.Engine, *ServiceImpl, Repository) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig()
+	cfg.ClientURL = "http://client.example.com"
+	cfg.OIDCEnabled = true
+	cfg.OIDCIssuer = idp.server.URL
+	cfg.OIDCClientID = "peekaping"
+	cfg.OIDCClientSecret = "client-secret"
+	cfg.OIDCRedirectURL = "http://peekaping.example.com/api/v1/auth/oidc/callback"
+	cfg.OIDCScopes = "email,profile"
+	cfg.OIDCAutoProvision = autoProvision
+	cfg.OIDCAllowedDomains = "example.com"
+
+	s, repo := newTestService(t)
+	s.cfg = cfg
+	oidcClient, err := NewOIDCClient(cfg, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	controller := NewController(s, oidcClient, cfg, zap.NewNop().Sugar())
+
+	router := gin.New()
+	route := NewRoute(controller, NewMiddlewareProvider(NewTokenMaker(cfg)))
+	route.ConnectRoute(router.Group("/api/v1"), controller)
+	return router, s, repo
+}
+
+// runOIDCLogin follows the redirects of a login from the browser and returns
+// the values handed to the client
+func runOIDCLogin(t *testing.T, router *gin.Engine, tamper func(callback *url.URL)) url.Values {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, rec.Code, rec.Body.String())
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.Get(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	if tamper != nil {
+		tamper(callback)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code, rec.Body.String())
+
+	location := rec.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "http://client.example.com/login/oidc#"), location)
+	values, err := url.ParseQuery(strings.SplitN(location, "#", 2)[1])
+	require.NoError(t, err)
+	return values
+}
+
+func TestOIDCLogin_LinksUserByEmail(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	router, s, repo := newOIDCTestRouter(t, idp, false)
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+
+	values := runOIDCLogin(t, router, nil)
+	require.Empty(t, values.Get("error"))
+	claims, err := s.tokenMaker.VerifyToken(values.Get("accessToken"), "access")
+	require.NoError(t, err)
+	assert.Equal(t, registered.User.ID, claims.UserID)
+	assert.NotEmpty(t, values.Get("refreshToken"))
+
+	stored, err := repo.FindByID(ctx, registered.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"|idp-user-1", stored.OIDCSubject)
+
+	// linked by subject from now on, whatever the email
+	idp.email = "renamed@example.com"
+	idp.emailVerified = false
+	values = runOIDCLogin(t, router, nil)
+	require.Empty(t, values.Get("error"))
+	claims, err = s.tokenMaker.VerifyToken(values.Get("accessToken"), "access")
+	require.NoError(t, err)
+	assert.Equal(t, registered.User.ID, claims.UserID)
+}
+
+func TestOIDCLogin_SubjectScopedToIssuer(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	router, s, repo := newOIDCTestRouter(t, idp, false)
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+	linked := "https://other-idp.example.com|idp-user-1"
+	require.NoError(t, repo.Update(ctx, registered.User.ID, &UpdateModel{OIDCSubject: &linked}))
+
+	// the same subject issued by another provider is another account
+	values := runOIDCLogin(t, router, nil)
+	assert.Equal(t, "account_conflict", values.Get("error"))
+	assert.Empty(t, values.Get("accessToken"))
+}
+
+func TestOIDCLogin_TwoFA(t *testing.T) {
+	ctx := context.Background()
+	router, s, _ := newOIDCTestRouter(t, newFakeIdP(t), false)
+
+	registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+	secret, _, err := s.SetupTwoFA(ctx, registered.User.ID, "Secret123!")
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	_, err = s.VerifyTwoFA(ctx, registered.User.ID, code)
+	require.NoError(t, err)
+
+	values := runOIDCLogin(t, router, nil)
+	assert.Equal(t, "2fa_required", values.Get("error"))
+	assert.Empty(t, values.Get("accessToken"))
+	require.NotEmpty(t, values.Get("challenge"))
+
+	completeTwoFA := func(dto OIDCTwoFADto) *httptest.ResponseRecorder {
+		body, err := json.Marshal(dto)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/2fa", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := completeTwoFA(OIDCTwoFADto{Challenge: values.Get("challenge")})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "2FA token required")
+
+	rec = completeTwoFA(OIDCTwoFADto{Challenge: values.Get("challenge") + "x", Token: "123456"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid or expired 2FA challenge")
+
+	next, err := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+	rec = completeTwoFA(OIDCTwoFADto{Challenge: values.Get("challenge"), Token: next})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response struct {
+		Data LoginResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	claims, err := s.tokenMaker.VerifyToken(response.Data.AccessToken, "access")
+	require.NoError(t, err)
+	assert.Equal(t, registered.User.ID, claims.UserID)
+}
+
+func TestOIDCClient_TwoFAChallenge(t *testing.T) {
+	client, err := NewOIDCClient(testConfig(), zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	sealed, err := client.SealTwoFAChallenge("user-1")
+	require.NoError(t, err)
+	userID, err := client.OpenTwoFAChallenge(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	// a login state is not a challenge
+	login, err := client.sealLogin(oidcPendingLogin{State: "state", Expires: time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	_, err = client.OpenTwoFAChallenge(login)
+	assert.Error(t, err)
+
+	expired, err := client.seal(oidcTwoFAPurpose, oidcPendingTwoFA{UserID: "user-1", Expires: time.Now().Add(-time.Second).Unix()})
+	require.NoError(t, err)
+	_, err = client.OpenTwoFAChallenge(expired)
+	assert.Error(t, err)
+}
+
+func TestOIDCLogin_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown user", func(t *testing.T) {
+		router, _, _ := newOIDCTestRouter(t, newFakeIdP(t), false)
+		values := runOIDCLogin(t, router, nil)
+		assert.Equal(t, "user_not_found", values.Get("error"))
+		assert.Empty(t, values.Get("accessToken"))
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		idp.emailVerified = false
+		router, s, _ := newOIDCTestRouter(t, idp, true)
+		_, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+
+		values := runOIDCLogin(t, router, nil)
+		assert.Equal(t, "email_not_verified", values.Get("error"))
+	})
+
+	t.Run("user linked to another account", func(t *testing.T) {
+		router, s, repo := newOIDCTestRouter(t, newFakeIdP(t), false)
+		registered, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+		other := "idp-user-2"
+		require.NoError(t, repo.Update(ctx, registered.User.ID, &UpdateModel{OIDCSubject: &other}))
+
+		values := runOIDCLogin(t, router, nil)
+		assert.Equal(t, "account_conflict", values.Get("error"))
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		router, s, _ := newOIDCTestRouter(t, newFakeIdP(t), false)
+		_, err := s.Register(ctx, RegisterDto{Email: "admin@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+
+		values := runOIDCLogin(t, router, func(callback *url.URL) {
+			query := callback.Query()
+			query.Set("state", "forged")
+			callback.RawQuery = query.Encode()
+		})
+		assert.Equal(t, "login_failed", values.Get("error"))
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		router, _, _ := newOIDCTestRouter(t, newFakeIdP(t), false)
+		values := runOIDCLogin(t, router, func(callback *url.URL) {
+			callback.RawQuery = url.Values{"error": {"access_denied"}}.Encode()
+		})
+		assert.Equal(t, "provider_error", values.Get("error"))
+	})
+}
+
+func TestOIDCLogin_AutoProvision(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	router, s, repo := newOIDCTestRouter(t, idp, true)
+
+	values := runOIDCLogin(t, router, nil)
+	require.Empty(t, values.Get("error"))
+	claims, err := s.tokenMaker.VerifyToken(values.Get("accessToken"), "access")
+	require.NoError(t, err)
+
+	user, err := repo.FindByOIDCSubject(ctx, idp.server.URL+"|idp-user-1")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, claims.UserID, user.ID)
+	assert.Equal(t, "admin@example.com", user.Email)
+	assert.True(t, user.Active)
+}
+
+func TestOIDCLogin_AutoProvision_NotAllowed(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	idp.email = "someone@other.example.org"
+	router, _, repo := newOIDCTestRouter(t, idp, true)
+
+	values := runOIDCLogin(t, router, nil)
+	assert.Equal(t, "email_not_allowed", values.Get("error"))
+	user, err := repo.FindByOIDCSubject(ctx, idp.server.URL+"|idp-user-1")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+func TestOIDCEmailAllowed(t *testing.T) {
+	allowed := "example.com, @corp.example.org,contractor@partner.com"
+	assert.True(t, oidcEmailAllowed(allowed, "admin@example.com"))
+	assert.True(t, oidcEmailAllowed(allowed, "Admin@EXAMPLE.com"))
+	assert.True(t, oidcEmailAllowed(allowed, "ops@corp.example.org"))
+	assert.True(t, oidcEmailAllowed(allowed, "contractor@partner.com"))
+	assert.False(t, oidcEmailAllowed(allowed, "other@partner.com"))
+	assert.False(t, oidcEmailAllowed(allowed, "admin@sub.example.com"))
+	assert.False(t, oidcEmailAllowed(allowed, "admin@example.com.evil.io"))
+	assert.False(t, oidcEmailAllowed(allowed, "example.com"))
+	assert.False(t, oidcEmailAllowed("", "admin@example.com"))
+}
+
+func TestOIDCLogin_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := testConfig()
+	s, _ := newTestService(t)
+	oidcClient, err := NewOIDCClient(cfg, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	controller := NewController(s, oidcClient, cfg, zap.NewNop().Sugar())
+	router := gin.New()
+	NewRoute(controller, NewMiddlewareProvider(NewTokenMaker(cfg))).ConnectRoute(router.Group("/api/v1"), controller)
+
+	for _, path := range []string{"/api/v1/auth/oidc/login", "/api/v1/auth/oidc/callback?code=x&state=y"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestOIDCClient_LoginState(t *testing.T) {
+	cfg := testConfig()
+	client, err := NewOIDCClient(cfg, zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	login := oidcPendingLogin{State: "state", Nonce: "nonce", Verifier: "verifier", Expires: time.Now().Add(time.Minute).Unix()}
+	sealed, err := client.sealLogin(login)
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, "verifier")
+
+	opened, err := client.openLogin(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, login, *opened)
+
+	tampered := []byte(sealed)
+	tampered[len(tampered)/2] ^= 1
+	_, err = client.openLogin(string(tampered))
+	assert.Error(t, err)
+
+	cfg.AccessTokenSecretKey = "another-secret-another-secret"
+	other, err := NewOIDCClient(cfg, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	_, err = other.openLogin(sealed)
+	assert.Error(t, err, "expected the state to be bound to the server key")
+
+	_, err = client.Exchange(context.Background(), "code", "another-state", sealed)
+	assert.EqualError(t, err, "invalid or expired login state")
+
+	login.Expires = time.Now().Add(-time.Second).Unix()
+	expired, err := client.sealLogin(login)
+	require.NoError(t, err)
+	_, err = client.Exchange(context.Background(), "code", "state", expired)
+	assert.EqualError(t, err, "invalid or expired login state")
+}
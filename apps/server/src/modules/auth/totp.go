@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"peekaping/src/config"
+	"strings"
+)
+
+// recoveryCodeCount is the number of recovery codes issued at once
+const recoveryCodeCount = 10
+
+// recoveryCodeAlphabet leaves out the characters easily mistaken for others
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// totpSecretEncoding is the unpadded base32 of the generated TOTP secrets
+var totpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ErrTwoFAKeyMissing is returned by the 2FA operations when no TWOFA_SECRET_KEY is set
+var ErrTwoFAKeyMissing = errors.New("2FA is not configured, TWOFA_SECRET_KEY is not set")
+
+// TOTPCipher encrypts the TOTP secrets stored on the users with AES-GCM,
+// bound to the user they belong to
+type TOTPCipher struct {
+	aead cipher.AEAD
+}
+
+// NewTOTPCipher uses the dedicated TWOFA_SECRET_KEY so rotating the token
+// secrets keeps the stored TOTP secrets readable. Without the key 2FA cannot
+// be set up and only secrets stored before the encryption are read.
+func NewTOTPCipher(cfg *config.Config) (*TOTPCipher, error) {
+	if cfg.TwoFASecretKey == "" {
+		return &TOTPCipher{}, nil
+	}
+	sum := sha256.Sum256([]byte(cfg.TwoFASecretKey))
+
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &TOTPCipher{aead: aead}, nil
+}
+
+// encryptedSecretLen is the length of an encrypted 20 byte secret, nonce and
+// tag included. The stored value fits the twofa_secret column.
+const encryptedSecretLen = 64
+
+// Enabled reports whether a key is configured
+func (c *TOTPCipher) Enabled() bool {
+	return c.aead != nil
+}
+
+// Encrypt seals the base32 secret of the user
+func (c *TOTPCipher) Encrypt(userID, secret string) (string, error) {
+	if !c.Enabled() {
+		return "", ErrTwoFAKeyMissing
+	}
+	raw, err := totpSecretEncoding.DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, raw, []byte(userID))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the base32 secret of the user. A secret stored before the
+// secrets were encrypted is returned as is, legacy reports it.
+func (c *TOTPCipher) Decrypt(userID, stored string) (secret string, legacy bool, err error) {
+	if len(stored) != encryptedSecretLen {
+		if _, err := totpSecretEncoding.DecodeString(stored); err != nil {
+			return "", false, errors.New("invalid stored 2FA secret")
+		}
+		return stored, true, nil
+	}
+	if !c.Enabled() {
+		return "", false, ErrTwoFAKeyMissing
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(stored)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", false, errors.New("invalid stored 2FA secret")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	raw, err := c.aead.Open(nil, nonce, ciphertext, []byte(userID))
+	if err != nil {
+		return "", false, errors.New("failed to decrypt 2FA secret, was TWOFA_SECRET_KEY changed?")
+	}
+	return totpSecretEncoding.EncodeToString(raw), false, nil
+}
+
+// generateRecoveryCodes returns new codes formatted as xxxxx-xxxxx and the
+// hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		var code strings.Builder
+		for j := 0; j < 10; j++ {
+			if j == 5 {
+				code.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			code.WriteByte(recoveryCodeAlphabet[n.Int64()])
+		}
+		codes = append(codes, code.String())
+		hashes = append(hashes, hashRecoveryCode(code.String()))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes the code ignoring case, spaces and dashes. The codes
+// are random enough for a plain hash.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// consumeRecoveryCode returns the hashes left once the code is used, ok is
+// false when the code matches none of them
+func consumeRecoveryCode(hashes []string, code string) (left []string, ok bool) {
+	hash := hashRecoveryCode(code)
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			left = append(append(left, hashes[:i]...), hashes[i+1:]...)
+			return left, true
+		}
+	}
+	return hashes, false
+}
@@ -0,0 +1,183 @@
+package blob
+
+import (
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router		/blobs [get]
+// @Summary		Get stored blobs
+// @Tags			Blobs
+// @Produce		json
+// @Security  BearerAuth
+// @Param     q    query     string  false  "Search query"
+// @Param     page query     int     false  "Page number" default(1)
+// @Param     limit query    int     false  "Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindAll(ctx *gin.Context) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return
+	}
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return
+	}
+
+	q := ctx.Query("q")
+
+	entities, err := ic.service.FindAll(ctx, page, limit, q)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch blobs", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entities))
+}
+
+// @Router		/blobs [post]
+// @Summary		Store a blob
+// @Description	Stores a payload that HTTP monitors send as request body with body_ref.
+// @Tags			Blobs
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param     body body   CreateUpdateDto  true  "Blob object"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Create(ctx *gin.Context) {
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	created, err := ic.service.Create(ctx, &entity)
+	if err != nil {
+		ic.logger.Errorw("Failed to create blob", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Blob created successfully", created))
+}
+
+// @Router		/blobs/{id} [get]
+// @Summary		Get blob by ID
+// @Tags			Blobs
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Blob ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	entity, err := ic.service.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch blob", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if entity == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Blob not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entity))
+}
+
+// @Router		/blobs/{id} [put]
+// @Summary		Update blob
+// @Description	Replaces the blob, monitors referencing it send the new content from their next check.
+// @Tags			Blobs
+// @Produce		json
+// @Accept		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Blob ID"
+// @Param       body body     CreateUpdateDto  true  "Blob object"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) UpdateFull(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updated, err := ic.service.UpdateFull(ctx, id, &entity)
+	if err != nil {
+		ic.logger.Errorw("Failed to update blob", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if updated == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Blob not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Blob updated successfully", updated))
+}
+
+// @Router		/blobs/{id} [delete]
+// @Summary		Delete blob
+// @Description	Monitors still referencing the blob go down until they are pointed at another one.
+// @Tags			Blobs
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Blob ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := ic.service.Delete(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to delete blob", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Blob deleted successfully", nil))
+}
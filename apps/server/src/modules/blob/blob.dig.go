@@ -0,0 +1,22 @@
+package blob
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/utils"
+
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container, cfg *config.Config) {
+	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
+	container.Provide(NewService)
+	container.Provide(NewBlobStore)
+	container.Provide(NewController)
+	container.Provide(NewRoute)
+}
+
+// NewBlobStore lets the executors resolve the bodies monitors reference
+func NewBlobStore(service Service) executor.BlobStore {
+	return service
+}
@@ -0,0 +1,9 @@
+package blob
+
+// CreateUpdateDto is used for both create and full update operations.
+type CreateUpdateDto struct {
+	Name string `json:"name" validate:"required,max=255" example:"bulk order payload"`
+	// Content is sent as is on every check, up to 1 MiB. The content type
+	// comes from the monitor encoding.
+	Content string `json:"content" validate:"required,max=1048576"`
+}
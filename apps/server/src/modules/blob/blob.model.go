@@ -0,0 +1,5 @@
+package blob
+
+import "peekaping/src/modules/shared"
+
+type Model = shared.Blob
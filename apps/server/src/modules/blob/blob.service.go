@@ -0,0 +1,64 @@
+package blob
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error)
+	// UpdateFull replaces the blob, it returns nil when the blob does not exist
+	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[blob-service]"),
+	}
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+	model := &Model{
+		Name:    entity.Name,
+		Content: entity.Content,
+	}
+	return s.repository.Create(ctx, model)
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	return s.repository.FindAll(ctx, page, limit, q)
+}
+
+func (s *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error) {
+	existing, err := s.repository.FindByID(ctx, id)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	model := &Model{
+		Name:    entity.Name,
+		Content: entity.Content,
+	}
+	return s.repository.UpdateFull(ctx, id, model)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
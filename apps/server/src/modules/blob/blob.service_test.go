@@ -0,0 +1,54 @@
+package blob
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T) Service {
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(context.Background())
+	require.NoError(t, err)
+
+	return NewService(NewSQLRepository(db), zap.NewNop().Sugar())
+}
+
+func TestService_CreateAndUpdateFull(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &CreateUpdateDto{Name: "orders", Content: `{"orders":[]}`})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+
+	updated, err := service.UpdateFull(ctx, created.ID, &CreateUpdateDto{Name: "orders", Content: `{"orders":[1]}`})
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, `{"orders":[1]}`, updated.Content)
+
+	missing, err := service.UpdateFull(ctx, "missing", &CreateUpdateDto{Name: "orders", Content: "x"})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	found, err := service.FindAll(ctx, 0, 10, "ORD")
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	require.NoError(t, service.Delete(ctx, created.ID))
+	deleted, err := service.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
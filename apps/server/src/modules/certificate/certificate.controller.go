@@ -0,0 +1,192 @@
+package certificate
+
+import (
+	"errors"
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router		/certificates [get]
+// @Summary		Get stored certificates
+// @Tags			Certificates
+// @Produce		json
+// @Security  BearerAuth
+// @Param     q    query     string  false  "Search query"
+// @Param     page query     int     false  "Page number" default(1)
+// @Param     limit query    int     false  "Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindAll(ctx *gin.Context) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return
+	}
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return
+	}
+
+	q := ctx.Query("q")
+
+	entities, err := ic.service.FindAll(ctx, page, limit, q)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch certificates", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entities))
+}
+
+// @Router		/certificates [post]
+// @Summary		Store a certificate
+// @Description	Stores an mTLS client certificate that HTTP monitors reference with tls_certificate_id. The key is never returned.
+// @Tags			Certificates
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param     body body   CreateUpdateDto  true  "Certificate object"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Create(ctx *gin.Context) {
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	created, err := ic.service.Create(ctx, &entity)
+	if errors.Is(err, ErrInvalidCertificate) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to create certificate", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Certificate created successfully", created))
+}
+
+// @Router		/certificates/{id} [get]
+// @Summary		Get certificate by ID
+// @Tags			Certificates
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Certificate ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	entity, err := ic.service.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch certificate", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if entity == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Certificate not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entity))
+}
+
+// @Router		/certificates/{id} [put]
+// @Summary		Update certificate
+// @Description	Replaces the certificate, monitors referencing it use the new one from their next check. An empty key keeps the stored one.
+// @Tags			Certificates
+// @Produce		json
+// @Accept		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Certificate ID"
+// @Param       body body     CreateUpdateDto  true  "Certificate object"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) UpdateFull(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updated, err := ic.service.UpdateFull(ctx, id, &entity)
+	if errors.Is(err, ErrInvalidCertificate) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to update certificate", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if updated == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Certificate not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Certificate updated successfully", updated))
+}
+
+// @Router		/certificates/{id} [delete]
+// @Summary		Delete certificate
+// @Description	Monitors still referencing the certificate go down until they are pointed at another one.
+// @Tags			Certificates
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Certificate ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := ic.service.Delete(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to delete certificate", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Certificate deleted successfully", nil))
+}
@@ -0,0 +1,22 @@
+package certificate
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/utils"
+
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container, cfg *config.Config) {
+	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
+	container.Provide(NewService)
+	container.Provide(NewCertificateStore)
+	container.Provide(NewController)
+	container.Provide(NewRoute)
+}
+
+// NewCertificateStore lets the executors resolve the certificates monitors reference
+func NewCertificateStore(service Service) executor.CertificateStore {
+	return service
+}
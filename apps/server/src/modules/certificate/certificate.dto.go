@@ -0,0 +1,14 @@
+package certificate
+
+// CreateUpdateDto is used for both create and full update operations.
+type CreateUpdateDto struct {
+	Name string `json:"name" validate:"required,max=255" example:"payments-api client"`
+	// PEM encoded client certificate
+	Cert string `json:"cert" validate:"required"`
+	// PEM encoded private key, required on create. An update without a key
+	// keeps the stored one.
+	Key string `json:"key"`
+	// PEM encoded CA the server certificate is verified against, the system
+	// roots when empty
+	CA string `json:"ca"`
+}
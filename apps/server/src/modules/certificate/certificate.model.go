@@ -0,0 +1,5 @@
+package certificate
+
+import "peekaping/src/modules/shared"
+
+type Model = shared.Certificate
@@ -0,0 +1,173 @@
+package certificate
+
+import (
+	"context"
+	"peekaping/src/config"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Name          string             `bson:"name"`
+	Certificate   string             `bson:"certificate"`
+	PrivateKey    string             `bson:"private_key"`
+	CACertificate string             `bson:"ca_certificate"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at"`
+}
+
+func toDomainModel(mm *mongoModel) *Model {
+	return &Model{
+		ID:        mm.ID.Hex(),
+		Name:      mm.Name,
+		Cert:      mm.Certificate,
+		Key:       mm.PrivateKey,
+		CA:        mm.CACertificate,
+		CreatedAt: mm.CreatedAt,
+		UpdatedAt: mm.UpdatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("certificates")
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, error) {
+	mm := &mongoModel{
+		ID:            primitive.NewObjectID(),
+		Name:          entity.Name,
+		Certificate:   entity.Cert,
+		PrivateKey:    entity.Key,
+		CACertificate: entity.CA,
+		CreatedAt:     time.Now().UTC(),
+		UpdatedAt:     time.Now().UTC(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, mm)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModel(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+	}
+	var mm mongoModel
+	err = r.collection.FindOne(ctx, filter).Decode(&mm)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModel(&mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	var entities []*Model
+
+	// Calculate the number of documents to skip
+	skip := int64(page * limit)
+	limit64 := int64(limit)
+
+	// Define options for pagination
+	options := &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit64,
+		Sort:  bson.D{{Key: "created_at", Value: -1}},
+	}
+
+	filter := bson.M{}
+	if q != "" {
+		filter["name"] = bson.M{"$regex": regexp.QuoteMeta(q), "$options": "i"}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		entities = append(entities, toDomainModel(&mm))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+func (r *MongoRepositoryImpl) UpdateFull(ctx context.Context, id string, entity *Model) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":           entity.Name,
+		"certificate":    entity.Cert,
+		"private_key":    entity.Key,
+		"ca_certificate": entity.CA,
+		"updated_at":     time.Now().UTC(),
+	}}
+
+	result := r.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+
+	var updatedMM mongoModel
+	if err := result.Decode(&updatedMM); err != nil {
+		return nil, err
+	}
+
+	return toDomainModel(&updatedMM), nil
+}
+
+func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+	}
+	_, err = r.collection.DeleteOne(ctx, filter)
+	return err
+}
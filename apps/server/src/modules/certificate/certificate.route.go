@@ -0,0 +1,36 @@
+package certificate
+
+import (
+	"peekaping/src/modules/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+) *Route {
+	return &Route{
+		controller,
+		middleware,
+	}
+}
+
+func (uc *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	router := rg.Group("certificates")
+
+	router.Use(uc.middleware.Auth())
+	router.GET("", uc.controller.FindAll)
+	router.POST("", uc.controller.Create)
+	router.GET(":id", uc.controller.FindByID)
+	router.PUT(":id", uc.controller.UpdateFull)
+	router.DELETE(":id", uc.controller.Delete)
+}
@@ -0,0 +1,102 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidCertificate is returned for PEM material that cannot be used for mTLS
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+type Service interface {
+	Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error)
+	// UpdateFull replaces the certificate, the stored key is kept when the
+	// dto has none. It returns nil when the certificate does not exist.
+	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[certificate-service]"),
+	}
+}
+
+// validateMaterial checks the key belongs to the certificate and the CA, when
+// given, holds at least one certificate
+func validateMaterial(cert, key, ca string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key is required", ErrInvalidCertificate)
+	}
+	if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	if ca != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(ca)) {
+		return fmt.Errorf("%w: ca holds no PEM encoded certificate", ErrInvalidCertificate)
+	}
+	return nil
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+	if err := validateMaterial(entity.Cert, entity.Key, entity.CA); err != nil {
+		return nil, err
+	}
+	model := &Model{
+		Name: entity.Name,
+		Cert: entity.Cert,
+		Key:  entity.Key,
+		CA:   entity.CA,
+	}
+	return s.repository.Create(ctx, model)
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	return s.repository.FindAll(ctx, page, limit, q)
+}
+
+func (s *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error) {
+	existing, err := s.repository.FindByID(ctx, id)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	key := entity.Key
+	if key == "" {
+		key = existing.Key
+	}
+	if err := validateMaterial(entity.Cert, key, entity.CA); err != nil {
+		return nil, err
+	}
+
+	model := &Model{
+		Name: entity.Name,
+		Cert: entity.Cert,
+		Key:  key,
+		CA:   entity.CA,
+	}
+	return s.repository.UpdateFull(ctx, id, model)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
@@ -0,0 +1,116 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T) Service {
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(context.Background())
+	require.NoError(t, err)
+
+	return NewService(NewSQLRepository(db), zap.NewNop().Sugar())
+}
+
+// selfSignedPEM returns a certificate and its key
+func selfSignedPEM(t *testing.T, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: commonName}}, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestService_CreateValidatesMaterial(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+	cert, key := selfSignedPEM(t, "client")
+	_, otherKey := selfSignedPEM(t, "other")
+
+	_, err := service.Create(ctx, &CreateUpdateDto{Name: "client", Cert: cert})
+	assert.ErrorIs(t, err, ErrInvalidCertificate, "expected a certificate without key to be rejected")
+
+	_, err = service.Create(ctx, &CreateUpdateDto{Name: "client", Cert: cert, Key: otherKey})
+	assert.ErrorIs(t, err, ErrInvalidCertificate, "expected the key of another certificate to be rejected")
+
+	_, err = service.Create(ctx, &CreateUpdateDto{Name: "client", Cert: cert, Key: key, CA: "not a certificate"})
+	assert.ErrorIs(t, err, ErrInvalidCertificate)
+
+	created, err := service.Create(ctx, &CreateUpdateDto{Name: "client", Cert: cert, Key: key, CA: cert})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+
+	stored, err := service.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, key, stored.Key)
+	assert.Equal(t, cert, stored.CA)
+
+	body, err := json.Marshal(stored)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "PRIVATE KEY", "the key is never returned")
+}
+
+func TestService_UpdateFullKeepsKey(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+	cert, key := selfSignedPEM(t, "client")
+
+	created, err := service.Create(ctx, &CreateUpdateDto{Name: "client", Cert: cert, Key: key, CA: cert})
+	require.NoError(t, err)
+
+	updated, err := service.UpdateFull(ctx, created.ID, &CreateUpdateDto{Name: "renamed", Cert: cert})
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, "renamed", updated.Name)
+	assert.Equal(t, key, updated.Key, "an update without key keeps the stored one")
+	assert.Empty(t, updated.CA, "the CA can be cleared")
+
+	renewed, renewedKey := selfSignedPEM(t, "client")
+	_, err = service.UpdateFull(ctx, created.ID, &CreateUpdateDto{Name: "renamed", Cert: renewed})
+	assert.ErrorIs(t, err, ErrInvalidCertificate, "expected the stored key to be checked against a new certificate")
+
+	updated, err = service.UpdateFull(ctx, created.ID, &CreateUpdateDto{Name: "renamed", Cert: renewed, Key: renewedKey})
+	require.NoError(t, err)
+	assert.Equal(t, renewedKey, updated.Key)
+
+	missing, err := service.UpdateFull(ctx, "missing", &CreateUpdateDto{Name: "renamed", Cert: cert, Key: key})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	found, err := service.FindAll(ctx, 0, 10, "REN")
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+}
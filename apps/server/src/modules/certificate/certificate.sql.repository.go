@@ -0,0 +1,126 @@
+package certificate
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:certificates,alias:c"`
+
+	ID            string    `bun:"id,pk"`
+	Name          string    `bun:"name,notnull"`
+	Certificate   string    `bun:"certificate,notnull"`
+	PrivateKey    string    `bun:"private_key,notnull"`
+	CACertificate string    `bun:"ca_certificate"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:        sm.ID,
+		Name:      sm.Name,
+		Cert:      sm.Certificate,
+		Key:       sm.PrivateKey,
+		CA:        sm.CACertificate,
+		CreatedAt: sm.CreatedAt,
+		UpdatedAt: sm.UpdatedAt,
+	}
+}
+
+func toSQLModel(m *Model) *sqlModel {
+	return &sqlModel{
+		ID:            m.ID,
+		Name:          m.Name,
+		Certificate:   m.Cert,
+		PrivateKey:    m.Key,
+		CACertificate: m.CA,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, error) {
+	sm := toSQLModel(entity)
+	sm.ID = uuid.New().String()
+	sm.CreatedAt = time.Now()
+	sm.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	query := r.db.NewSelect().Model((*sqlModel)(nil))
+
+	if q != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+
+	query = query.Order("created_at DESC").
+		Limit(limit).
+		Offset(page * limit)
+
+	var sms []*sqlModel
+	err := query.Scan(ctx, &sms)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) UpdateFull(ctx context.Context, id string, entity *Model) (*Model, error) {
+	sm := toSQLModel(entity)
+	sm.UpdatedAt = time.Now()
+
+	// the CA may be cleared, so the columns are listed rather than omitting zero values
+	_, err := r.db.NewUpdate().
+		Model(sm).
+		Column("name", "certificate", "private_key", "ca_certificate", "updated_at").
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
@@ -6,13 +6,18 @@ import (
 	"time"
 
 	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/notification_failure"
 	"peekaping/src/modules/setting"
 
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
-func cleanupHeartbeats(heartbeatService heartbeat.Service, settingService setting.Service, logger *zap.SugaredLogger) {
+// monitorsPageSize is how many monitors are loaded at once while applying retention
+const monitorsPageSize = 100
+
+func globalKeepDays(settingService setting.Service, logger *zap.SugaredLogger) int {
 	keepDays := 365 // default fallback
 	settingModel, err := settingService.GetByKey(context.Background(), "KEEP_DATA_PERIOD_DAYS")
 	if err != nil {
@@ -24,22 +29,69 @@ func cleanupHeartbeats(heartbeatService heartbeat.Service, settingService settin
 			logger.Errorw("Invalid KEEP_DATA_PERIOD_DAYS value", "value", settingModel.Value, "error", err)
 		}
 	}
+	return keepDays
+}
+
+// cleanupHeartbeats deletes the heartbeats of every monitor older than its own
+// retention, monitors without retention_days keep the global setting
+func cleanupHeartbeats(heartbeatService heartbeat.Service, monitorService monitor.Service, settingService setting.Service, logger *zap.SugaredLogger) {
+	ctx := context.Background()
+	keepDays := globalKeepDays(settingService, logger)
+	now := time.Now().UTC()
+
+	var total int64
+	for page := 0; ; page++ {
+		monitors, err := monitorService.FindAll(ctx, page, monitorsPageSize, "", nil, nil, nil)
+		if err != nil {
+			logger.Errorw("Failed to fetch monitors for heartbeat cleanup", "error", err)
+			return
+		}
+
+		for _, m := range monitors {
+			days := keepDays
+			if m.RetentionDays > 0 {
+				days = m.RetentionDays
+			}
+			cutoff := now.AddDate(0, 0, -days)
+			deleted, err := heartbeatService.DeleteOlderThanForMonitor(ctx, m.ID, cutoff)
+			if err != nil {
+				logger.Errorw("Failed to delete old heartbeats", "monitorID", m.ID, "error", err)
+				continue
+			}
+			total += deleted
+		}
+
+		if len(monitors) < monitorsPageSize {
+			break
+		}
+	}
+	logger.Infow("Deleted old heartbeats", "count", total, "keepDays", keepDays)
+}
+
+// cleanupNotificationFailures deletes failed notifications older than the global retention
+func cleanupNotificationFailures(failureService notification_failure.Service, settingService setting.Service, logger *zap.SugaredLogger) {
+	keepDays := globalKeepDays(settingService, logger)
 	cutoff := time.Now().UTC().AddDate(0, 0, -keepDays)
-	deleted, err := heartbeatService.DeleteOlderThan(context.Background(), cutoff)
+
+	deleted, err := failureService.DeleteOlderThan(context.Background(), cutoff)
 	if err != nil {
-		logger.Errorw("Failed to delete old heartbeats", "error", err)
+		logger.Errorw("Failed to delete old notification failures", "error", err)
 		return
 	}
-	logger.Infow("Deleted old heartbeats", "count", deleted, "cutoff", cutoff)
+	logger.Infow("Deleted old notification failures", "count", deleted, "keepDays", keepDays)
 }
 
 // StartCleanupCron starts the general cleanup cron job(s).
-func StartCleanupCron(heartbeatService heartbeat.Service, settingService setting.Service, logger *zap.SugaredLogger) {
+func StartCleanupCron(heartbeatService heartbeat.Service, monitorService monitor.Service, settingService setting.Service, failureService notification_failure.Service, logger *zap.SugaredLogger) {
 	c := cron.New()
 
 	// Heartbeat cleanup task
 	c.AddFunc("0 * * * *", func() {
-		cleanupHeartbeats(heartbeatService, settingService, logger)
+		cleanupHeartbeats(heartbeatService, monitorService, settingService, logger)
+	})
+
+	c.AddFunc("30 * * * *", func() {
+		cleanupNotificationFailures(failureService, settingService, logger)
 	})
 
 	c.Start()
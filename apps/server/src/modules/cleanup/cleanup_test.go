@@ -0,0 +1,93 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/setting"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fakeHeartbeatService struct {
+	heartbeat.Service
+	cutoffs map[string]time.Time
+}
+
+func (s *fakeHeartbeatService) DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error) {
+	s.cutoffs[monitorID] = cutoff
+	return 1, nil
+}
+
+type fakeMonitorService struct {
+	monitor.Service
+	monitors []*monitor.Model
+}
+
+func (s *fakeMonitorService) FindAll(ctx context.Context, page int, limit int, q string, active *bool, status *int, tagIds []string) ([]*monitor.Model, error) {
+	start := page * limit
+	if start >= len(s.monitors) {
+		return nil, nil
+	}
+	end := start + limit
+	if end > len(s.monitors) {
+		end = len(s.monitors)
+	}
+	return s.monitors[start:end], nil
+}
+
+type fakeSettingService struct {
+	setting.Service
+	value string
+}
+
+func (s *fakeSettingService) GetByKey(ctx context.Context, key string) (*setting.Model, error) {
+	if s.value == "" {
+		return nil, nil
+	}
+	return &setting.Model{Key: key, Value: s.value}, nil
+}
+
+func assertKeptDays(t *testing.T, cutoff time.Time, days int) {
+	t.Helper()
+	expected := time.Now().UTC().AddDate(0, 0, -days)
+	if diff := expected.Sub(cutoff); diff < 0 || diff > time.Minute {
+		t.Errorf("expected a cutoff %d days ago, got %v", days, cutoff)
+	}
+}
+
+func TestCleanupHeartbeats_PerMonitorRetention(t *testing.T) {
+	heartbeats := &fakeHeartbeatService{cutoffs: make(map[string]time.Time)}
+	monitors := &fakeMonitorService{monitors: []*monitor.Model{
+		{ID: "noisy", RetentionDays: 3},
+		{ID: "default"},
+		{ID: "critical", RetentionDays: 730},
+	}}
+
+	cleanupHeartbeats(heartbeats, monitors, &fakeSettingService{value: "90"}, zap.NewNop().Sugar())
+
+	if len(heartbeats.cutoffs) != 3 {
+		t.Fatalf("expected a cleanup per monitor, got %v", heartbeats.cutoffs)
+	}
+	assertKeptDays(t, heartbeats.cutoffs["noisy"], 3)
+	assertKeptDays(t, heartbeats.cutoffs["default"], 90)
+	assertKeptDays(t, heartbeats.cutoffs["critical"], 730)
+}
+
+func TestCleanupHeartbeats_DefaultRetentionAcrossPages(t *testing.T) {
+	heartbeats := &fakeHeartbeatService{cutoffs: make(map[string]time.Time)}
+	monitors := &fakeMonitorService{}
+	for i := 0; i < monitorsPageSize+5; i++ {
+		monitors.monitors = append(monitors.monitors, &monitor.Model{ID: fmt.Sprintf("m%d", i)})
+	}
+
+	cleanupHeartbeats(heartbeats, monitors, &fakeSettingService{}, zap.NewNop().Sugar())
+
+	if len(heartbeats.cutoffs) != monitorsPageSize+5 {
+		t.Fatalf("expected every monitor to be cleaned up, got %d", len(heartbeats.cutoffs))
+	}
+	assertKeptDays(t, heartbeats.cutoffs["m0"], 365)
+}
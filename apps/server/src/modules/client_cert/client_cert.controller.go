@@ -0,0 +1,118 @@
+package client_cert
+
+import (
+	"errors"
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router	/client-certificates [post]
+// @Summary	Issue a client certificate for mTLS monitors
+// @Description	Signs a client certificate with the internal CA. Without a CSR a new key is generated and returned.
+// @Tags		Client Certificates
+// @Produce	json
+// @Accept	json
+// @Security	BearerAuth
+// @Param	body	body	IssueDto	true	"Certificate request"
+// @Success	201	{object}	utils.ApiResponse[IssuedCertificateDto]
+// @Failure	400	{object}	utils.APIError[any]
+// @Failure	500	{object}	utils.APIError[any]
+func (ic *Controller) Issue(ctx *gin.Context) {
+	var dto IssueDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	issued, err := ic.service.Issue(ctx, &dto)
+	if errors.Is(err, ErrCANotConfigured) || errors.Is(err, ErrInvalidCSR) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to issue client certificate", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Client certificate issued successfully", issued))
+}
+
+// @Router	/client-certificates/ca [get]
+// @Summary	Get the internal CA certificate
+// @Tags		Client Certificates
+// @Produce	json
+// @Security	BearerAuth
+// @Success	200	{object}	utils.ApiResponse[CADto]
+// @Failure	404	{object}	utils.APIError[any]
+// @Failure	500	{object}	utils.APIError[any]
+func (ic *Controller) GetCA(ctx *gin.Context) {
+	ca, err := ic.service.GetCA(ctx)
+	if err != nil {
+		ic.logger.Errorw("Failed to load CA", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if ca == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse(ErrCANotConfigured.Error()))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", ca))
+}
+
+// @Router	/client-certificates/ca [post]
+// @Summary	Generate the internal CA
+// @Description	Creates a CA for client certificates and stores it in the settings. Fails when one is configured.
+// @Tags		Client Certificates
+// @Produce	json
+// @Accept	json
+// @Security	BearerAuth
+// @Param	body	body	CreateCADto	true	"CA"
+// @Success	201	{object}	utils.ApiResponse[CADto]
+// @Failure	400	{object}	utils.APIError[any]
+// @Failure	409	{object}	utils.APIError[any]
+// @Failure	500	{object}	utils.APIError[any]
+func (ic *Controller) CreateCA(ctx *gin.Context) {
+	var dto CreateCADto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	ca, err := ic.service.CreateCA(ctx, &dto)
+	if errors.Is(err, ErrCAExists) {
+		ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to create CA", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("CA created successfully", ca))
+}
@@ -0,0 +1,13 @@
+package client_cert
+
+import (
+	"peekaping/src/config"
+
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container, cfg *config.Config) {
+	container.Provide(NewService)
+	container.Provide(NewController)
+	container.Provide(NewRoute)
+}
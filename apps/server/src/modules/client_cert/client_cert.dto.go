@@ -0,0 +1,37 @@
+package client_cert
+
+import "time"
+
+type IssueDto struct {
+	// Common name of the client, ignored when a CSR is given
+	CommonName   string `json:"common_name" validate:"required_without=CSR,omitempty,max=64" example:"peekaping-monitor"`
+	Organization string `json:"organization" validate:"omitempty,max=64" example:"Peekaping"`
+	// Validity in days, 365 when not set
+	ValidityDays int `json:"validity_days" validate:"omitempty,min=1,max=825" example:"365"`
+	// PEM encoded certificate signing request. When set its key and subject are
+	// used and no private key is returned.
+	CSR string `json:"csr" validate:"omitempty" example:"-----BEGIN CERTIFICATE REQUEST-----..."`
+}
+
+type IssuedCertificateDto struct {
+	// Client certificate for the tlsCert field of an mTLS HTTP monitor
+	Certificate string `json:"certificate"`
+	// Private key for the tlsKey field, empty when a CSR was signed
+	PrivateKey string `json:"private_key,omitempty"`
+	// CA certificate the server has to trust to accept the client certificate
+	CACertificate string    `json:"ca_certificate"`
+	SerialNumber  string    `json:"serial_number" example:"5f1e3c9a0b7d4e2f8a6c1d3b9e7f0a2c"`
+	Fingerprint   string    `json:"fingerprint" example:"SHA256:3f8a..."`
+	NotAfter      time.Time `json:"not_after"`
+}
+
+type CreateCADto struct {
+	CommonName string `json:"common_name" validate:"required,max=64" example:"Peekaping Internal CA"`
+	// Validity in days, 10 years when not set
+	ValidityDays int `json:"validity_days" validate:"omitempty,min=1,max=7300" example:"3650"`
+}
+
+type CADto struct {
+	CACertificate string    `json:"ca_certificate"`
+	NotAfter      time.Time `json:"not_after"`
+}
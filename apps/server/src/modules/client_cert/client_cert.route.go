@@ -0,0 +1,34 @@
+package client_cert
+
+import (
+	"peekaping/src/modules/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+) *Route {
+	return &Route{
+		controller, middleware,
+	}
+}
+
+func (uc *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	router := rg.Group("/client-certificates")
+
+	router.Use(uc.middleware.Auth())
+
+	router.POST("", uc.controller.Issue)
+	router.GET("ca", uc.controller.GetCA)
+	router.POST("ca", uc.controller.CreateCA)
+}
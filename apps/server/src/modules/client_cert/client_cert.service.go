@@ -0,0 +1,313 @@
+package client_cert
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"peekaping/src/modules/setting"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SettingCACert holds the PEM encoded certificate of the internal CA
+	SettingCACert = "MTLS_CA_CERT"
+	// SettingCAKey holds the PEM encoded private key of the internal CA
+	SettingCAKey = "MTLS_CA_KEY"
+
+	defaultValidityDays   = 365
+	defaultCAValidityDays = 3650
+
+	// clockSkew backdates certificates so servers with a slow clock accept them
+	clockSkew = 5 * time.Minute
+)
+
+// ErrCANotConfigured is returned when the CA settings are missing
+var ErrCANotConfigured = fmt.Errorf("mTLS CA is not configured, set the %s and %s settings", SettingCACert, SettingCAKey)
+
+// ErrCAExists is returned when creating a CA while one is configured
+var ErrCAExists = errors.New("mTLS CA is already configured")
+
+// ErrInvalidCSR is returned for a CSR that cannot be parsed or is not signed by its key
+var ErrInvalidCSR = errors.New("invalid certificate signing request")
+
+type Service interface {
+	// Issue signs a client certificate with the internal CA
+	Issue(ctx context.Context, dto *IssueDto) (*IssuedCertificateDto, error)
+	// GetCA returns the certificate of the internal CA, nil when none is configured
+	GetCA(ctx context.Context) (*CADto, error)
+	// CreateCA generates an internal CA and stores it in the settings
+	CreateCA(ctx context.Context, dto *CreateCADto) (*CADto, error)
+}
+
+type ServiceImpl struct {
+	settingService setting.Service
+	logger         *zap.SugaredLogger
+	now            func() time.Time
+}
+
+func NewService(
+	settingService setting.Service,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		settingService,
+		logger.Named("[client-cert-service]"),
+		time.Now,
+	}
+}
+
+// ca is the loaded internal CA
+type ca struct {
+	cert *x509.Certificate
+	pem  string
+	key  crypto.Signer
+}
+
+func (s *ServiceImpl) loadCA(ctx context.Context) (*ca, error) {
+	certSetting, err := s.settingService.GetByKey(ctx, SettingCACert)
+	if err != nil {
+		return nil, err
+	}
+	keySetting, err := s.settingService.GetByKey(ctx, SettingCAKey)
+	if err != nil {
+		return nil, err
+	}
+	if certSetting == nil || keySetting == nil || certSetting.Value == "" || keySetting.Value == "" {
+		return nil, ErrCANotConfigured
+	}
+
+	block, _ := pem.Decode([]byte(certSetting.Value))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a PEM encoded certificate", SettingCACert)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SettingCACert, err)
+	}
+	if !cert.IsCA || cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("%s is not a CA certificate allowed to sign certificates", SettingCACert)
+	}
+
+	key, err := parsePrivateKey(keySetting.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SettingCAKey, err)
+	}
+	if !publicKeysEqual(cert.PublicKey, key.Public()) {
+		return nil, fmt.Errorf("%s does not belong to %s", SettingCAKey, SettingCACert)
+	}
+
+	return &ca{cert: cert, pem: certSetting.Value, key: key}, nil
+}
+
+func (s *ServiceImpl) Issue(ctx context.Context, dto *IssueDto) (*IssuedCertificateDto, error) {
+	authority, err := s.loadCA(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := pkix.Name{CommonName: dto.CommonName}
+	if dto.Organization != "" {
+		subject.Organization = []string{dto.Organization}
+	}
+
+	var publicKey crypto.PublicKey
+	var keyPEM string
+	if dto.CSR != "" {
+		csr, err := parseCSR(dto.CSR)
+		if err != nil {
+			return nil, err
+		}
+		subject = csr.Subject
+		publicKey = csr.PublicKey
+	} else {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode key: %w", err)
+		}
+		publicKey = key.Public()
+		keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	}
+
+	validityDays := dto.ValidityDays
+	if validityDays == 0 {
+		validityDays = defaultValidityDays
+	}
+	// certificates store whole seconds, truncating keeps not_after exact
+	now := s.now().UTC().Truncate(time.Second)
+	notAfter := now.AddDate(0, 0, validityDays)
+	// a certificate outliving its CA would fail verification anyway
+	if notAfter.After(authority.cert.NotAfter) {
+		notAfter = authority.cert.NotAfter
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, authority.cert, publicKey, authority.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+
+	fingerprint := sha256.Sum256(der)
+	s.logger.Infof("Issued client certificate %s for %q", hex.EncodeToString(serial.Bytes()), subject.CommonName)
+
+	return &IssuedCertificateDto{
+		Certificate:   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKey:    keyPEM,
+		CACertificate: authority.pem,
+		SerialNumber:  hex.EncodeToString(serial.Bytes()),
+		Fingerprint:   "SHA256:" + base64.RawStdEncoding.EncodeToString(fingerprint[:]),
+		NotAfter:      notAfter,
+	}, nil
+}
+
+func (s *ServiceImpl) GetCA(ctx context.Context) (*CADto, error) {
+	authority, err := s.loadCA(ctx)
+	if errors.Is(err, ErrCANotConfigured) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CADto{CACertificate: authority.pem, NotAfter: authority.cert.NotAfter}, nil
+}
+
+func (s *ServiceImpl) CreateCA(ctx context.Context, dto *CreateCADto) (*CADto, error) {
+	existing, err := s.settingService.GetByKey(ctx, SettingCACert)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Value != "" {
+		return nil, ErrCAExists
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	validityDays := dto.ValidityDays
+	if validityDays == 0 {
+		validityDays = defaultCAValidityDays
+	}
+	now := s.now().UTC().Truncate(time.Second)
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: dto.CommonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.AddDate(0, 0, validityDays),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	// the key goes first so a failure never leaves a certificate without its key
+	if _, err := s.settingService.SetByKey(ctx, SettingCAKey, &setting.CreateUpdateDto{Value: keyPEM, Type: "string"}); err != nil {
+		return nil, err
+	}
+	if _, err := s.settingService.SetByKey(ctx, SettingCACert, &setting.CreateUpdateDto{Value: certPEM, Type: "string"}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Created mTLS CA %q", dto.CommonName)
+	return &CADto{CACertificate: certPEM, NotAfter: template.NotAfter}, nil
+}
+
+func parseCSR(csrPEM string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: not a PEM encoded certificate request", ErrInvalidCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	if csr.Subject.CommonName == "" {
+		return nil, fmt.Errorf("%w: the subject has no common name", ErrInvalidCSR)
+	}
+	return csr, nil
+}
+
+// parsePrivateKey accepts PKCS#8, PKCS#1 and SEC 1 encoded keys, the formats
+// openssl writes
+func parsePrivateKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("not a PEM encoded private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// randomSerial returns a random positive 128 bit serial number
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial.Add(serial, big.NewInt(1)), nil
+}
@@ -0,0 +1,215 @@
+package client_cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/setting"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fakeSettingService struct {
+	setting.Service
+	values map[string]string
+}
+
+func (s *fakeSettingService) GetByKey(ctx context.Context, key string) (*setting.Model, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, nil
+	}
+	return &setting.Model{Key: key, Value: value, Type: "string"}, nil
+}
+
+func (s *fakeSettingService) SetByKey(ctx context.Context, key string, dto *setting.CreateUpdateDto) (*setting.Model, error) {
+	s.values[key] = dto.Value
+	return &setting.Model{Key: key, Value: dto.Value, Type: dto.Type}, nil
+}
+
+func newTestService(t *testing.T) (*ServiceImpl, *fakeSettingService) {
+	t.Helper()
+	settings := &fakeSettingService{values: make(map[string]string)}
+	service := NewService(settings, zap.NewNop().Sugar()).(*ServiceImpl)
+	if _, err := service.CreateCA(context.Background(), &CreateCADto{CommonName: "Test CA"}); err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	return service, settings
+}
+
+func parseCertificate(t *testing.T, certPEM string) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		t.Fatal("expected a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func verifyClientCert(t *testing.T, issued *IssuedCertificateDto) *x509.Certificate {
+	t.Helper()
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(issued.CACertificate)) {
+		t.Fatal("expected the CA certificate to be PEM encoded")
+	}
+	cert := parseCertificate(t, issued.Certificate)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Fatalf("expected the certificate to verify as a client certificate: %v", err)
+	}
+	return cert
+}
+
+func TestIssue_GeneratedKeyPairLoads(t *testing.T) {
+	service, _ := newTestService(t)
+
+	issued, err := service.Issue(context.Background(), &IssueDto{CommonName: "api-monitor", Organization: "Peekaping", ValidityDays: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tls.X509KeyPair([]byte(issued.Certificate), []byte(issued.PrivateKey)); err != nil {
+		t.Fatalf("expected the certificate and key to load as a key pair: %v", err)
+	}
+
+	cert := verifyClientCert(t, issued)
+	if cert.Subject.CommonName != "api-monitor" || len(cert.Subject.Organization) != 1 || cert.Subject.Organization[0] != "Peekaping" {
+		t.Errorf("unexpected subject %v", cert.Subject)
+	}
+	if days := time.Until(cert.NotAfter).Hours() / 24; days < 29 || days > 30 {
+		t.Errorf("expected a validity of 30 days, got %.1f", days)
+	}
+	if !cert.NotAfter.Equal(issued.NotAfter) {
+		t.Errorf("expected not_after %v, got %v", cert.NotAfter, issued.NotAfter)
+	}
+}
+
+func TestIssue_WorksForMutualTLS(t *testing.T) {
+	service, _ := newTestService(t)
+	issued, err := service.Issue(context.Background(), &IssueDto{CommonName: "api-monitor"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(issued.CACertificate))
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	keyPair, err := tls.X509KeyPair([]byte(issued.Certificate), []byte(issued.PrivateKey))
+	if err != nil {
+		t.Fatalf("failed to load key pair: %v", err)
+	}
+	client := server.Client()
+	client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{keyPair}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the server to accept the client certificate: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestIssue_SignsCSR(t *testing.T) {
+	service, _ := newTestService(t)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "from-csr"}}, key)
+	if err != nil {
+		t.Fatalf("failed to create CSR: %v", err)
+	}
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+
+	issued, err := service.Issue(context.Background(), &IssueDto{CommonName: "ignored", CSR: csrPEM})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issued.PrivateKey != "" {
+		t.Error("expected no private key for a CSR")
+	}
+	cert := verifyClientCert(t, issued)
+	if cert.Subject.CommonName != "from-csr" {
+		t.Errorf("expected the CSR subject, got %q", cert.Subject.CommonName)
+	}
+
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	if _, err := tls.X509KeyPair([]byte(issued.Certificate), keyPEM); err != nil {
+		t.Errorf("expected the certificate to match the CSR key: %v", err)
+	}
+
+	if _, err := service.Issue(context.Background(), &IssueDto{CSR: "not a csr"}); !errors.Is(err, ErrInvalidCSR) {
+		t.Errorf("expected ErrInvalidCSR, got %v", err)
+	}
+}
+
+func TestIssue_CappedAtCAExpiry(t *testing.T) {
+	settings := &fakeSettingService{values: make(map[string]string)}
+	service := NewService(settings, zap.NewNop().Sugar()).(*ServiceImpl)
+	ca, err := service.CreateCA(context.Background(), &CreateCADto{CommonName: "Short CA", ValidityDays: 10})
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+
+	issued, err := service.Issue(context.Background(), &IssueDto{CommonName: "api-monitor", ValidityDays: 365})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !issued.NotAfter.Equal(ca.NotAfter) {
+		t.Errorf("expected the certificate to expire with the CA at %v, got %v", ca.NotAfter, issued.NotAfter)
+	}
+}
+
+func TestIssue_CANotConfigured(t *testing.T) {
+	service := NewService(&fakeSettingService{values: make(map[string]string)}, zap.NewNop().Sugar())
+
+	if _, err := service.Issue(context.Background(), &IssueDto{CommonName: "api-monitor"}); !errors.Is(err, ErrCANotConfigured) {
+		t.Errorf("expected ErrCANotConfigured, got %v", err)
+	}
+	if ca, err := service.GetCA(context.Background()); err != nil || ca != nil {
+		t.Errorf("expected no CA, got %v, %v", ca, err)
+	}
+}
+
+func TestCreateCA_RefusesToReplace(t *testing.T) {
+	service, settings := newTestService(t)
+	caCert := settings.values[SettingCACert]
+
+	if _, err := service.CreateCA(context.Background(), &CreateCADto{CommonName: "Other CA"}); !errors.Is(err, ErrCAExists) {
+		t.Errorf("expected ErrCAExists, got %v", err)
+	}
+	if settings.values[SettingCACert] != caCert {
+		t.Error("expected the CA to be kept")
+	}
+}
+
+func TestIssue_RejectsMismatchedCAKey(t *testing.T) {
+	service, settings := newTestService(t)
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalECPrivateKey(other)
+	settings.values[SettingCAKey] = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	if _, err := service.Issue(context.Background(), &IssueDto{CommonName: "api-monitor"}); err == nil {
+		t.Error("expected an error for a key that does not belong to the CA")
+	}
+}
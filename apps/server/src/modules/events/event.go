@@ -20,10 +20,21 @@ const (
 	HeartbeatEvent EventType = "heartbeat"
 	// NotifyEvent is emitted when a monitor status changes (up <-> down)
 	MonitorStatusChanged EventType = "monitor.status.changed"
+	// MonitorStateTransition is emitted when the status of a monitor differs
+	// from its previous check
+	MonitorStateTransition EventType = "monitor.state_transition"
+	// MonitorSlowCheck is emitted when the check duration of a monitor trends upward
+	MonitorSlowCheck EventType = "monitor.slow_check"
+	// MonitorNeverSucceeded is emitted once when a monitor has been failing since
+	// it was created for longer than the configured duration
+	MonitorNeverSucceeded EventType = "monitor.never_succeeded"
 	// ProxyUpdated is emitted when a proxy is updated
 	ProxyUpdated EventType = "proxy.updated"
 	// ProxyDeleted is emitted when a proxy is deleted
 	ProxyDeleted EventType = "proxy.deleted"
+	// ProxyCheckCompleted is emitted when a monitor check routed through a
+	// proxy completes
+	ProxyCheckCompleted EventType = "proxy.check_completed"
 )
 
 // Event represents a generic event with a type and payload
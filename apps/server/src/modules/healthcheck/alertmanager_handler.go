@@ -0,0 +1,233 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"peekaping/src/config"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"peekaping/src/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AlertmanagerPayload is the body of an Alertmanager webhook notification
+type AlertmanagerPayload struct {
+	Version  string              `json:"version"`
+	GroupKey string              `json:"groupKey"`
+	Status   string              `json:"status"`
+	Receiver string              `json:"receiver"`
+	Alerts   []AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+// key identifies the alert across notifications, older Alertmanager
+// versions send no fingerprint
+func (a *AlertmanagerAlert) key() string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+	names := make([]string, 0, len(a.Labels))
+	for name := range a.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%q,", name, a.Labels[name])
+	}
+	return b.String()
+}
+
+func (a *AlertmanagerAlert) message() string {
+	msg := a.Labels["alertname"] + " firing"
+	if summary := a.Annotations["summary"]; summary != "" {
+		return msg + ": " + summary
+	}
+	if description := a.Annotations["description"]; description != "" {
+		return msg + ": " + description
+	}
+	return msg
+}
+
+// alertmanagerChange is the state an Alertmanager notification puts a monitor in
+type alertmanagerChange struct {
+	Monitor *Monitor
+	Result  *executor.Result
+}
+
+// alertmanagerTracker remembers the firing alerts of every monitor, a
+// monitor only comes back up once all of its alerts resolved
+type alertmanagerTracker struct {
+	mu      sync.Mutex
+	firing  map[string]map[string]string
+	configs map[string]*alertmanagerMonitorConfig
+}
+
+// alertmanagerMonitorConfig is the parsed config of a monitor, kept until
+// the monitor config changes so the label regexes compile only once
+type alertmanagerMonitorConfig struct {
+	raw string
+	cfg *executor.AlertmanagerConfig
+}
+
+func newAlertmanagerTracker() *alertmanagerTracker {
+	return &alertmanagerTracker{
+		firing:  make(map[string]map[string]string),
+		configs: make(map[string]*alertmanagerMonitorConfig),
+	}
+}
+
+func (t *alertmanagerTracker) config(m *Monitor) (*executor.AlertmanagerConfig, error) {
+	if cached, ok := t.configs[m.ID]; ok && cached.raw == m.Config {
+		return cached.cfg, nil
+	}
+	cfg, err := executor.GenericUnmarshal[executor.AlertmanagerConfig](m.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Compile(); err != nil {
+		return nil, err
+	}
+	t.configs[m.ID] = &alertmanagerMonitorConfig{raw: m.Config, cfg: cfg}
+	return cfg, nil
+}
+
+// apply records the alerts of the payload and returns the state of every
+// alertmanager monitor matched by at least one of them
+func (t *alertmanagerTracker) apply(payload *AlertmanagerPayload, monitors []*Monitor, now time.Time) []*alertmanagerChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changes []*alertmanagerChange
+	for _, m := range monitors {
+		if m.Type != "alertmanager" {
+			continue
+		}
+		cfg, err := t.config(m)
+		if err != nil {
+			continue
+		}
+
+		matched := false
+		var resolved []string
+		for i := range payload.Alerts {
+			alert := &payload.Alerts[i]
+			if !cfg.Matches(alert.Labels) {
+				continue
+			}
+			matched = true
+			if t.firing[m.ID] == nil {
+				t.firing[m.ID] = make(map[string]string)
+			}
+			if alert.Status == "firing" {
+				t.firing[m.ID][alert.key()] = alert.message()
+			} else {
+				delete(t.firing[m.ID], alert.key())
+				resolved = append(resolved, alert.Labels["alertname"])
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		result := &executor.Result{StartTime: now, EndTime: now}
+		if firing := t.firing[m.ID]; len(firing) > 0 {
+			messages := make([]string, 0, len(firing))
+			for _, msg := range firing {
+				messages = append(messages, msg)
+			}
+			sort.Strings(messages)
+			result.Status = shared.MonitorStatusDown
+			result.Message = strings.Join(messages, "; ")
+		} else {
+			delete(t.firing, m.ID)
+			result.Status = shared.MonitorStatusUp
+			result.Message = "Resolved: " + strings.Join(resolved, ", ")
+		}
+		changes = append(changes, &alertmanagerChange{Monitor: m, Result: result})
+	}
+	return changes
+}
+
+// RegisterAlertmanagerEndpoint accepts Alertmanager webhook notifications
+// and drives the alertmanager monitors matching their alerts. The endpoint
+// is disabled unless ALERTMANAGER_WEBHOOK_TOKEN is set.
+func RegisterAlertmanagerEndpoint(
+	router *gin.RouterGroup,
+	monitorService monitor.Service,
+	healthcheckSupervisor *HealthCheckSupervisor,
+	cfg *config.Config,
+	logger *zap.SugaredLogger,
+) {
+	tracker := newAlertmanagerTracker()
+
+	router.POST("/alertmanager/webhook", func(ctx *gin.Context) {
+		if cfg.AlertmanagerWebhookToken == "" {
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Alertmanager ingestion is not enabled"))
+			return
+		}
+		expected := "Bearer " + cfg.AlertmanagerWebhookToken
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte(expected)) != 1 {
+			ctx.JSON(http.StatusUnauthorized, utils.NewFailResponse("Invalid or missing token"))
+			return
+		}
+
+		var payload AlertmanagerPayload
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+
+		monitors, err := monitorService.FindActive(ctx)
+		if err != nil {
+			logger.Errorw("Failed to find active monitors", "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+
+		changes := tracker.apply(&payload, monitors, time.Now().UTC())
+		for _, change := range changes {
+			healthcheckSupervisor.postProcessAlertmanagerChange(ctx, change)
+		}
+
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", gin.H{"monitors": len(changes)}))
+	})
+}
+
+// postProcessAlertmanagerChange records the state of the monitor, a monitor
+// under maintenance reports maintenance like on a regular tick
+func (s *HealthCheckSupervisor) postProcessAlertmanagerChange(ctx context.Context, change *alertmanagerChange) {
+	m := change.Monitor
+	isUnderMaintenance, err := s.isUnderMaintenance(ctx, m.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to check maintenance status for monitor %s: %v", m.ID, err)
+	}
+
+	result := change.Result
+	if isUnderMaintenance && !m.IgnoreMaintenance {
+		result = &executor.Result{
+			Status:    shared.MonitorStatusMaintenance,
+			Message:   "Monitor under maintenance",
+			StartTime: result.StartTime,
+			EndTime:   result.EndTime,
+		}
+	}
+	s.postProcessHeartbeat(result, m, nil)
+}
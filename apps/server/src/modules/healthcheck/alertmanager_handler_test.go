@@ -0,0 +1,140 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// sampleAlertmanagerPayload is a webhook notification as sent by Alertmanager 0.27
+const sampleAlertmanagerPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighErrorRate\"}",
+  "truncatedAlerts": 0,
+  "status": "firing",
+  "receiver": "peekaping",
+  "groupLabels": {"alertname": "HighErrorRate"},
+  "commonLabels": {"alertname": "HighErrorRate", "severity": "critical"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighErrorRate", "service": "api", "instance": "api-1", "severity": "critical"},
+      "annotations": {"summary": "5xx rate above 5% on api-1"},
+      "startsAt": "2025-07-21T10:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "a1b2c3"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighErrorRate", "service": "api", "instance": "api-2", "severity": "critical"},
+      "annotations": {"summary": "5xx rate above 5% on api-2"},
+      "startsAt": "2025-07-21T10:01:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "d4e5f6"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighErrorRate", "service": "billing", "instance": "billing-1", "severity": "critical"},
+      "annotations": {"description": "errors on billing"},
+      "startsAt": "2025-07-21T10:02:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "0a0b0c"
+    }
+  ]
+}`
+
+func parseAlertmanagerPayload(t *testing.T, body string) *AlertmanagerPayload {
+	var payload AlertmanagerPayload
+	require.NoError(t, json.Unmarshal([]byte(body), &payload))
+	return &payload
+}
+
+func resolve(payload *AlertmanagerPayload, fingerprints ...string) *AlertmanagerPayload {
+	resolved := &AlertmanagerPayload{Status: "resolved"}
+	for _, alert := range payload.Alerts {
+		for _, fingerprint := range fingerprints {
+			if alert.Fingerprint == fingerprint {
+				alert.Status = "resolved"
+				resolved.Alerts = append(resolved.Alerts, alert)
+			}
+		}
+	}
+	return resolved
+}
+
+func TestAlertmanagerTracker_Apply(t *testing.T) {
+	monitors := []*Monitor{
+		{ID: "api", Type: "alertmanager", Config: `{"labels": {"alertname": "HighErrorRate", "service": "api"}}`},
+		{ID: "billing", Type: "alertmanager", Config: `{"labels_regex": {"service": "bill.*"}}`},
+		{ID: "checkout", Type: "alertmanager", Config: `{"labels": {"service": "checkout"}}`},
+		{ID: "push", Type: "push", Config: `{"pushToken": "token"}`},
+	}
+	payload := parseAlertmanagerPayload(t, sampleAlertmanagerPayload)
+	tracker := newAlertmanagerTracker()
+	now := time.Date(2025, 7, 21, 10, 5, 0, 0, time.UTC)
+
+	changes := tracker.apply(payload, monitors, now)
+	require.Len(t, changes, 2, "only matched alertmanager monitors change")
+
+	assert.Equal(t, "api", changes[0].Monitor.ID)
+	assert.Equal(t, shared.MonitorStatusDown, changes[0].Result.Status)
+	assert.Equal(t, "HighErrorRate firing: 5xx rate above 5% on api-1; HighErrorRate firing: 5xx rate above 5% on api-2", changes[0].Result.Message)
+	assert.Equal(t, now, changes[0].Result.StartTime)
+
+	assert.Equal(t, "billing", changes[1].Monitor.ID)
+	assert.Equal(t, shared.MonitorStatusDown, changes[1].Result.Status)
+	assert.Equal(t, "HighErrorRate firing: errors on billing", changes[1].Result.Message)
+
+	// one of two api alerts resolved, the monitor stays down
+	changes = tracker.apply(resolve(payload, "a1b2c3"), monitors, now)
+	require.Len(t, changes, 1)
+	assert.Equal(t, shared.MonitorStatusDown, changes[0].Result.Status)
+	assert.Equal(t, "HighErrorRate firing: 5xx rate above 5% on api-2", changes[0].Result.Message)
+
+	changes = tracker.apply(resolve(payload, "d4e5f6", "0a0b0c"), monitors, now)
+	require.Len(t, changes, 2)
+	for _, change := range changes {
+		assert.Equal(t, shared.MonitorStatusUp, change.Result.Status, change.Monitor.ID)
+		assert.Equal(t, "Resolved: HighErrorRate", change.Result.Message)
+	}
+}
+
+func TestAlertmanagerAlert_KeyWithoutFingerprint(t *testing.T) {
+	a := AlertmanagerAlert{Labels: map[string]string{"b": "2", "a": "1"}}
+	b := AlertmanagerAlert{Labels: map[string]string{"a": "1", "b": "2"}}
+	assert.Equal(t, a.key(), b.key())
+	assert.NotEqual(t, a.key(), (&AlertmanagerAlert{Labels: map[string]string{"a": "1"}}).key())
+}
+
+func TestPostProcessAlertmanagerChange_Maintenance(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	ms := &fakeMaintenanceService{monitorIDs: map[string]bool{"flagged": true, "regular": true}}
+	s := newTestSupervisor(hb, ms, events.NewEventBus(zap.NewNop().Sugar()))
+
+	now := time.Now().UTC()
+	for _, m := range []*Monitor{
+		{ID: "flagged", Name: "flagged", Type: "alertmanager", IgnoreMaintenance: true},
+		{ID: "regular", Name: "regular", Type: "alertmanager"},
+	} {
+		s.postProcessAlertmanagerChange(context.Background(), &alertmanagerChange{
+			Monitor: m,
+			Result:  &executor.Result{Status: shared.MonitorStatusDown, Message: "HighErrorRate firing", StartTime: now, EndTime: now},
+		})
+	}
+
+	assert.Equal(t, shared.MonitorStatusDown, hb.latest("flagged").Status)
+	assert.Equal(t, shared.MonitorStatusMaintenance, hb.latest("regular").Status)
+}
@@ -0,0 +1,34 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/config"
+)
+
+// checkSlots caps the checks executing at once, checks beyond the limit
+// queue for a free slot. A monitor never runs two checks at once, so one
+// slow monitor holds at most one slot.
+type checkSlots struct {
+	slots chan struct{}
+}
+
+func newCheckSlots(cfg *config.Config) *checkSlots {
+	if cfg == nil || cfg.MaxConcurrentChecks <= 0 {
+		return &checkSlots{}
+	}
+	return &checkSlots{slots: make(chan struct{}, cfg.MaxConcurrentChecks)}
+}
+
+// acquire blocks until a slot is free, the returned release frees it again.
+// Without a limit it returns right away.
+func (c *checkSlots) acquire(ctx context.Context) (release func(), err error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return func() { <-c.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
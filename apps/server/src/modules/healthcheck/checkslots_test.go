@@ -0,0 +1,72 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/config"
+	"peekaping/src/modules/events"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckSlots_Acquire(t *testing.T) {
+	unlimited := newCheckSlots(&config.Config{})
+	for i := 0; i < 10; i++ {
+		_, err := unlimited.acquire(context.Background())
+		require.NoError(t, err)
+	}
+
+	slots := newCheckSlots(&config.Config{MaxConcurrentChecks: 1})
+	release, err := slots.acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = slots.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "a full pool queues the check")
+
+	release()
+	release, err = slots.acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestHandleMonitorTick_CapsConcurrentChecks(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := NewHealthCheck(nil, &fakeMaintenanceService{}, hb, nil, events.NewEventBus(zap.NewNop().Sugar()), nil,
+		zap.NewNop().Sugar(), nil, &config.Config{MaxConcurrentChecks: 2})
+	exec := &slowExecutor{started: make(chan struct{}, 10), release: make(chan struct{})}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		m := &Monitor{ID: fmt.Sprintf("m%d", i), Name: fmt.Sprintf("m%d", i), Interval: 60, Timeout: 5}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+		}()
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-exec.started:
+		case <-time.After(time.Second):
+			t.Fatal("checks did not start")
+		}
+	}
+	select {
+	case <-exec.started:
+		t.Fatal("a third check ran while both slots were taken")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the queued checks run once slots are released
+	close(exec.release)
+	wg.Wait()
+	assert.Equal(t, int32(5), exec.calls.Load())
+}
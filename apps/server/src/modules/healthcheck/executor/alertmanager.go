@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+)
+
+// AlertmanagerConfig selects the Alertmanager alerts driving the monitor, an
+// alert matches when it carries all labels with the given values
+type AlertmanagerConfig struct {
+	Labels map[string]string `json:"labels"`
+	// Label values matched by anchored regular expressions, like match_re of Alertmanager routes
+	LabelsRegex map[string]string `json:"labels_regex"`
+
+	compiled map[string]*regexp.Regexp
+}
+
+// Compile compiles the label regexes, Matches compiles them on first use
+// otherwise
+func (c *AlertmanagerConfig) Compile() error {
+	compiled := make(map[string]*regexp.Regexp, len(c.LabelsRegex))
+	for name, pattern := range c.LabelsRegex {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid regular expression for label %s: %w", name, err)
+		}
+		compiled[name] = re
+	}
+	c.compiled = compiled
+	return nil
+}
+
+// Matches tells whether an alert with the labels drives the monitor
+func (c *AlertmanagerConfig) Matches(labels map[string]string) bool {
+	for name, value := range c.Labels {
+		if labels[name] != value {
+			return false
+		}
+	}
+	if c.compiled == nil {
+		if err := c.Compile(); err != nil {
+			return false
+		}
+	}
+	for name, re := range c.compiled {
+		if !re.MatchString(labels[name]) {
+			return false
+		}
+	}
+	return len(c.Labels)+len(c.LabelsRegex) > 0
+}
+
+// AlertmanagerExecutor never checks anything itself, the monitor only
+// changes state when alerts arrive on the Alertmanager webhook
+type AlertmanagerExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewAlertmanagerExecutor(logger *zap.SugaredLogger) *AlertmanagerExecutor {
+	return &AlertmanagerExecutor{
+		logger: logger,
+	}
+}
+
+func (s *AlertmanagerExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[AlertmanagerConfig](configJSON)
+}
+
+func (s *AlertmanagerExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	c := cfg.(*AlertmanagerConfig)
+	if len(c.Labels)+len(c.LabelsRegex) == 0 {
+		return fmt.Errorf("at least one label matcher is required")
+	}
+	return c.Compile()
+}
+
+func (s *AlertmanagerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	return nil
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAlertmanagerExecutor_Validate(t *testing.T) {
+	executor := NewAlertmanagerExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "labels", config: `{"labels": {"alertname": "HighErrorRate"}}`},
+		{name: "regex", config: `{"labels_regex": {"instance": "api-[0-9]+"}}`},
+		{name: "no matchers", config: `{}`, wantError: true},
+		{name: "empty matchers", config: `{"labels": {}}`, wantError: true},
+		{name: "invalid regex", config: `{"labels_regex": {"instance": "api-("}}`, wantError: true},
+		{name: "unknown field", config: `{"labels": {"a": "b"}, "match": {}}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerConfig_Matches(t *testing.T) {
+	labels := map[string]string{"alertname": "HighErrorRate", "instance": "api-12", "severity": "critical"}
+
+	assert.True(t, (&AlertmanagerConfig{Labels: map[string]string{"alertname": "HighErrorRate"}}).Matches(labels))
+	assert.True(t, (&AlertmanagerConfig{
+		Labels:      map[string]string{"severity": "critical"},
+		LabelsRegex: map[string]string{"instance": "api-[0-9]+"},
+	}).Matches(labels))
+	assert.False(t, (&AlertmanagerConfig{Labels: map[string]string{"alertname": "Other"}}).Matches(labels))
+	assert.False(t, (&AlertmanagerConfig{LabelsRegex: map[string]string{"instance": "api"}}).Matches(labels), "regexes are anchored")
+	assert.False(t, (&AlertmanagerConfig{}).Matches(labels), "no matchers match nothing")
+}
+
+func TestAlertmanagerExecutor_ExecuteLeavesStateAlone(t *testing.T) {
+	executor := NewAlertmanagerExecutor(zap.NewNop().Sugar())
+	assert.Nil(t, executor.Execute(context.Background(), &Monitor{Type: "alertmanager"}, nil))
+}
@@ -0,0 +1,212 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"go.uber.org/zap"
+)
+
+type CassandraConfig struct {
+	Hosts    []string `json:"hosts" validate:"required,min=1,dive,required" example:"cassandra-1:9042"`
+	Keyspace string   `json:"keyspace,omitempty" example:"app"`
+	Username string   `json:"username,omitempty" example:"cassandra"`
+	Password string   `json:"password,omitempty" example:"password"`
+	Query    string   `json:"query,omitempty" example:"SELECT now() FROM system.local"`
+}
+
+// cassandraDefaultPort is the CQL native protocol port used for hosts without one
+const cassandraDefaultPort = "9042"
+
+// cassandraKeyspaceRegex matches the unquoted keyspace names Cassandra accepts
+var cassandraKeyspaceRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_]{0,47}$`)
+
+type CassandraExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewCassandraExecutor(logger *zap.SugaredLogger) *CassandraExecutor {
+	return &CassandraExecutor{
+		logger: logger,
+	}
+}
+
+func (c *CassandraExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[CassandraConfig](configJSON)
+}
+
+func (c *CassandraExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	hosts := cfg.(*CassandraConfig).Hosts
+	if len(hosts) == 0 {
+		return "", false
+	}
+	return cassandraHostAddress(hosts[0]), true
+}
+
+// cassandraHostAddress returns host:port of a configured host, adding the
+// default port when it has none
+func cassandraHostAddress(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), cassandraDefaultPort)
+}
+
+func (c *CassandraExecutor) Validate(configJSON string) error {
+	cfg, err := c.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	cassandraCfg := cfg.(*CassandraConfig)
+
+	if err := GenericValidator(cassandraCfg); err != nil {
+		return err
+	}
+
+	for _, host := range cassandraCfg.Hosts {
+		if err := validateCassandraHost(host); err != nil {
+			return fmt.Errorf("invalid host %q: %w", host, err)
+		}
+	}
+
+	if cassandraCfg.Keyspace != "" && !cassandraKeyspaceRegex.MatchString(cassandraCfg.Keyspace) {
+		return fmt.Errorf("invalid keyspace %q", cassandraCfg.Keyspace)
+	}
+
+	if (cassandraCfg.Username == "") != (cassandraCfg.Password == "") {
+		return fmt.Errorf("username and password must be provided together")
+	}
+
+	if strings.TrimSpace(cassandraCfg.Query) != "" {
+		if err := c.validateQuery(cassandraCfg.Query); err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateCassandraHost accepts a hostname or IP address with an optional port
+func validateCassandraHost(host string) error {
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+
+	if port != "" {
+		portNum, err := strconv.Atoi(port)
+		if err != nil || portNum < 1 || portNum > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535")
+		}
+	}
+
+	if !isValidHostname(strings.Trim(hostname, "[]")) {
+		return fmt.Errorf("hostname contains invalid characters")
+	}
+	return nil
+}
+
+func (c *CassandraExecutor) validateQuery(query string) error {
+	trimmedQuery := strings.TrimSpace(query)
+	if trimmedQuery == "" {
+		return fmt.Errorf("query cannot be empty or whitespace only")
+	}
+
+	// Allow only SELECT statements for safety, CQL has no other read statements
+	if !strings.HasPrefix(strings.ToLower(trimmedQuery), "select") {
+		return fmt.Errorf("only SELECT statements are allowed for monitoring queries")
+	}
+
+	if strings.Contains(strings.TrimSuffix(trimmedQuery, ";"), ";") {
+		return fmt.Errorf("only a single statement is allowed")
+	}
+
+	return nil
+}
+
+func (c *CassandraExecutor) Execute(ctx context.Context, monitor *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := c.Unmarshal(monitor.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*CassandraConfig)
+
+	c.logger.Debugf("execute cassandra hosts: %v, keyspace: %s", cfg.Hosts, cfg.Keyspace)
+
+	startTime := time.Now().UTC()
+
+	if len(cfg.Hosts) == 0 {
+		return DownResult(fmt.Errorf("at least one host is required"), startTime, time.Now().UTC())
+	}
+
+	query := strings.TrimSpace(cfg.Query)
+	if query == "" {
+		query = "SELECT now() FROM system.local"
+	} else {
+		// Validate query before execution
+		if err := c.validateQuery(query); err != nil {
+			return DownResult(fmt.Errorf("query validation failed: %w", err), startTime, time.Now().UTC())
+		}
+		query = strings.TrimSuffix(query, ";")
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	// Both the session and the query share the monitor's configured timeout
+	cluster := gocql.NewCluster(cfg.Hosts...)
+	cluster.Keyspace = cfg.Keyspace
+	cluster.Consistency = gocql.One
+	cluster.ConnectTimeout = timeout
+	cluster.Timeout = timeout
+	cluster.NumConns = 1
+	if cfg.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		c.logger.Infof("Cassandra connection failed: %s, %s", monitor.Name, err.Error())
+		return DownResult(fmt.Errorf("Cassandra connection failed: %w", err), startTime, time.Now().UTC())
+	}
+	defer session.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	scanner := session.Query(query).WithContext(queryCtx).Iter().Scanner()
+	rowCount := 0
+	for scanner.Next() {
+		rowCount++
+	}
+	endTime := time.Now().UTC()
+
+	if err := scanner.Err(); err != nil {
+		c.logger.Infof("Cassandra query failed: %s, %s", monitor.Name, err.Error())
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Cassandra query failed: %v", err),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	ping := endTime.Sub(startTime).Milliseconds()
+	c.logger.Infof("Cassandra query successful: %s, ping: %dms", monitor.Name, ping)
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("Query successful, ping: %dms, Rows: %d", ping, rowCount),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCassandraExecutor_Validate(t *testing.T) {
+	executor := NewCassandraExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		configJSON  string
+		expectError bool
+	}{
+		{"valid config", `{"hosts": ["cassandra-1:9042", "cassandra-2"], "keyspace": "app", "username": "cassandra", "password": "secret", "query": "SELECT release_version FROM system.local"}`, false},
+		{"default query", `{"hosts": ["10.0.0.1"]}`, false},
+		{"ipv6 host", `{"hosts": ["[::1]:9042"]}`, false},
+		{"missing hosts", `{"keyspace": "app"}`, true},
+		{"empty host", `{"hosts": [""]}`, true},
+		{"invalid host", `{"hosts": ["cassandra_1:9042"]}`, true},
+		{"invalid port", `{"hosts": ["cassandra-1:99999"]}`, true},
+		{"invalid keyspace", `{"hosts": ["cassandra-1"], "keyspace": "app-prod"}`, true},
+		{"username without password", `{"hosts": ["cassandra-1"], "username": "cassandra"}`, true},
+		{"insert statement", `{"hosts": ["cassandra-1"], "query": "INSERT INTO app.health (id) VALUES (1)"}`, true},
+		{"truncate statement", `{"hosts": ["cassandra-1"], "query": "TRUNCATE app.health"}`, true},
+		{"multiple statements", `{"hosts": ["cassandra-1"], "query": "SELECT now() FROM system.local; DROP KEYSPACE app"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.configJSON)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCassandraExecutor_ReachabilityAddress(t *testing.T) {
+	executor := NewCassandraExecutor(zap.NewNop().Sugar())
+
+	address, ok := executor.ReachabilityAddress(&CassandraConfig{Hosts: []string{"cassandra-1", "cassandra-2"}})
+	assert.True(t, ok)
+	assert.Equal(t, "cassandra-1:9042", address)
+
+	address, ok = executor.ReachabilityAddress(&CassandraConfig{Hosts: []string{"cassandra-1:19042"}})
+	assert.True(t, ok)
+	assert.Equal(t, "cassandra-1:19042", address)
+
+	_, ok = executor.ReachabilityAddress(&CassandraConfig{})
+	assert.False(t, ok)
+}
+
+func TestCassandraExecutor_Execute_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m := &Monitor{
+		ID:      "cassandra",
+		Name:    "cassandra",
+		Type:    "cassandra",
+		Timeout: 2,
+		Config:  `{"hosts": ["127.0.0.1:` + strconv.Itoa(port) + `"]}`,
+	}
+	result := NewCassandraExecutor(zap.NewNop().Sugar()).Execute(context.Background(), m, nil)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "Cassandra connection failed")
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CertChainReport describes whether the certificates presented by a server
+// build a chain to a trusted root on their own
+type CertChainReport struct {
+	Length   int
+	Complete bool
+	Gaps     []string
+	Err      error
+}
+
+// Summary returns a short human readable description of the report
+func (r *CertChainReport) Summary() string {
+	if r.Err != nil {
+		return fmt.Sprintf("certificate chain warning: %s", r.Err.Error())
+	}
+	return fmt.Sprintf("certificate chain complete (length %d)", r.Length)
+}
+
+// certChainRoots returns the system roots extended with an optional PEM encoded CA bundle
+func certChainRoots(caPEM string) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if caPEM != "" && !roots.AppendCertsFromPEM([]byte(caPEM)) {
+		return nil, fmt.Errorf("invalid CA certificate for chain validation")
+	}
+	return roots, nil
+}
+
+// verifyCertChain verifies the presented chain using only the intermediates the
+// server sent, so a chain that only validates through locally cached or fetched
+// intermediates is reported as incomplete
+func verifyCertChain(certs []*x509.Certificate, roots *x509.CertPool, serverName string) *CertChainReport {
+	report := &CertChainReport{Length: len(certs)}
+	if len(certs) == 0 {
+		report.Err = errors.New("server presented no certificates")
+		return report
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err == nil {
+		report.Complete = true
+		return report
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		report.Gaps = findCertChainGaps(certs, roots)
+		if len(report.Gaps) > 0 {
+			report.Err = fmt.Errorf("incomplete certificate chain (length %d): %s", report.Length, strings.Join(report.Gaps, "; "))
+			return report
+		}
+	}
+
+	report.Err = fmt.Errorf("certificate chain verification failed (length %d): %w", report.Length, err)
+	return report
+}
+
+// findCertChainGaps walks the presented chain from the leaf and reports the first
+// issuer that is neither presented by the server nor a trusted root
+func findCertChainGaps(certs []*x509.Certificate, roots *x509.CertPool) []string {
+	current := certs[0]
+	visited := map[*x509.Certificate]bool{current: true}
+	for {
+		if bytes.Equal(current.RawIssuer, current.RawSubject) {
+			// self-signed certificate that is not trusted
+			return []string{fmt.Sprintf("untrusted self-signed certificate %q", current.Subject.CommonName)}
+		}
+
+		// stop once the issuer is a trusted root
+		if _, err := current.Verify(x509.VerifyOptions{Roots: roots}); err == nil {
+			return nil
+		}
+
+		var next *x509.Certificate
+		for _, candidate := range certs {
+			if !visited[candidate] && bytes.Equal(candidate.RawSubject, current.RawIssuer) {
+				next = candidate
+				break
+			}
+		}
+		if next == nil {
+			return []string{fmt.Sprintf("missing issuer %q for %q", current.Issuer.CommonName, current.Subject.CommonName)}
+		}
+
+		visited[next] = true
+		current = next
+	}
+}
+
+// setupCertChainCheck replaces the default certificate verification of tlsConfig
+// with verifyCertChain, storing the outcome in report. Verification errors fail
+// the handshake unless ignoreErrors is set, in which case they are only reported.
+func setupCertChainCheck(tlsConfig *tls.Config, roots *x509.CertPool, hostname string, ignoreErrors bool, report **CertChainReport) {
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		serverName := cs.ServerName
+		if serverName == "" {
+			serverName = hostname
+		}
+
+		r := verifyCertChain(cs.PeerCertificates, roots, serverName)
+		*report = r
+		if r.Err != nil && !ignoreErrors {
+			return r.Err
+		}
+		return nil
+	}
+}
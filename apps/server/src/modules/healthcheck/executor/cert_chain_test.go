@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testCertChain struct {
+	root, intermediate, leaf *x509.Certificate
+	leafKey                  *ecdsa.PrivateKey
+	rootPEM                  string
+}
+
+func issueTestCert(t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func newTestCertChain(t *testing.T) *testCertChain {
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(24 * time.Hour)
+
+	root, rootKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	intermediate, intermediateKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, root, rootKey)
+
+	leaf, leafKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}, intermediate, intermediateKey)
+
+	return &testCertChain{
+		root:         root,
+		intermediate: intermediate,
+		leaf:         leaf,
+		leafKey:      leafKey,
+		rootPEM:      string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})),
+	}
+}
+
+func TestVerifyCertChain(t *testing.T) {
+	chain := newTestCertChain(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(chain.root)
+
+	t.Run("complete chain", func(t *testing.T) {
+		report := verifyCertChain([]*x509.Certificate{chain.leaf, chain.intermediate}, roots, "127.0.0.1")
+		assert.True(t, report.Complete)
+		assert.NoError(t, report.Err)
+		assert.Equal(t, 2, report.Length)
+		assert.Contains(t, report.Summary(), "complete (length 2)")
+	})
+
+	t.Run("missing intermediate", func(t *testing.T) {
+		report := verifyCertChain([]*x509.Certificate{chain.leaf}, roots, "127.0.0.1")
+		assert.False(t, report.Complete)
+		assert.Equal(t, 1, report.Length)
+		assert.Equal(t, []string{`missing issuer "Test Intermediate CA" for "127.0.0.1"`}, report.Gaps)
+		assert.ErrorContains(t, report.Err, "incomplete certificate chain (length 1)")
+	})
+
+	t.Run("hostname mismatch is not a gap", func(t *testing.T) {
+		report := verifyCertChain([]*x509.Certificate{chain.leaf, chain.intermediate}, roots, "example.com")
+		assert.False(t, report.Complete)
+		assert.Empty(t, report.Gaps)
+		assert.ErrorContains(t, report.Err, "verification failed")
+	})
+
+	t.Run("no certificates", func(t *testing.T) {
+		report := verifyCertChain(nil, roots, "127.0.0.1")
+		assert.False(t, report.Complete)
+		assert.Error(t, report.Err)
+	})
+}
+
+func TestHTTPExecutor_Execute_CheckCertChain(t *testing.T) {
+	chain := newTestCertChain(t)
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	newServer := func(certs ...*x509.Certificate) *httptest.Server {
+		tlsCert := tls.Certificate{PrivateKey: chain.leafKey, Leaf: chain.leaf}
+		for _, c := range certs {
+			tlsCert.Certificate = append(tlsCert.Certificate, c.Raw)
+		}
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{tlsCert}}
+		server.StartTLS()
+		return server
+	}
+
+	tests := []struct {
+		name           string
+		certs          []*x509.Certificate
+		ignoreTls      bool
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{
+			name:           "complete chain",
+			certs:          []*x509.Certificate{chain.leaf, chain.intermediate},
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "certificate chain complete (length 2)",
+		},
+		{
+			name:           "incomplete chain",
+			certs:          []*x509.Certificate{chain.leaf},
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: `incomplete certificate chain (length 1): missing issuer "Test Intermediate CA"`,
+		},
+		{
+			name:           "incomplete chain with ignored TLS errors is a warning",
+			certs:          []*x509.Certificate{chain.leaf},
+			ignoreTls:      true,
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "certificate chain warning: incomplete certificate chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(tt.certs...)
+			defer server.Close()
+
+			config := map[string]any{
+				"url":                  server.URL,
+				"method":               "GET",
+				"encoding":             "json",
+				"accepted_statuscodes": []string{"2XX"},
+				"authMethod":           "none",
+				"check_cert_chain":     true,
+				"ignore_tls_errors":    tt.ignoreTls,
+				"tlsCa":                chain.rootPEM,
+			}
+			configJSON, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Test Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   string(configJSON),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package executor
+
+import "strconv"
+
+// compareValues compares a checked value with the expected one for the eq,
+// ne, lt, gt, le and ge operators. The ordering operators compare numerically
+// and only hold when both values are numbers.
+func compareValues(actual, operator, expected string) bool {
+	switch operator {
+	case "eq":
+		return actual == expected
+	case "ne":
+		return actual != expected
+	}
+
+	actualNum, err1 := strconv.ParseFloat(actual, 64)
+	expectedNum, err2 := strconv.ParseFloat(expected, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	switch operator {
+	case "lt":
+		return actualNum < expectedNum
+	case "gt":
+		return actualNum > expectedNum
+	case "le":
+		return actualNum <= expectedNum
+	case "ge":
+		return actualNum >= expectedNum
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		actual, operator, expected string
+		want                       bool
+	}{
+		{"ok", "eq", "ok", true},
+		{"ok", "eq", "nope", false},
+		{"ok", "ne", "nope", true},
+		{"12", "gt", "0", true},
+		{"0", "gt", "0", false},
+		{"3", "le", "3", true},
+		{"2.5", "lt", "3", true},
+		{"3", "ge", "3.0", true},
+		{"abc", "gt", "0", false},
+		{"1", "contains", "1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareValues(tt.actual, tt.operator, tt.expected), "%s %s %s", tt.actual, tt.operator, tt.expected)
+	}
+}
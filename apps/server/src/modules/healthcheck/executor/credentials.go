@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"time"
+)
+
+// ClientCertificate is a PEM encoded client certificate from a monitor config,
+// Field names the config field it is stored in
+type ClientCertificate struct {
+	Field string
+	PEM   string
+}
+
+// ClientCertificateSource is implemented by executors that authenticate with a
+// stored client certificate. It returns the certificates the parsed config
+// will present, none when they are not used.
+type ClientCertificateSource interface {
+	ClientCertificates(cfg any) []ClientCertificate
+}
+
+// CheckClientCredentials checks the validity window of the client certificates
+// a monitor uses before it is executed. An expired certificate otherwise
+// surfaces as an opaque handshake failure. It returns nil when every
+// certificate is valid, or the executor does not use any.
+func CheckClientCredentials(exec Executor, configJSON string, now time.Time) *Result {
+	source, ok := exec.(ClientCertificateSource)
+	if !ok {
+		return nil
+	}
+	cfg, err := exec.Unmarshal(configJSON)
+	if err != nil {
+		// the executor reports the broken config itself
+		return nil
+	}
+
+	for _, clientCert := range source.ClientCertificates(cfg) {
+		if err := checkCertificateValidity(clientCert, now); err != nil {
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   err.Error(),
+				StartTime: now,
+				EndTime:   now,
+			}
+		}
+	}
+	return nil
+}
+
+// checkCertificateValidity checks the leaf certificate of a PEM bundle, a
+// certificate that cannot be parsed is left to the executor to report
+func checkCertificateValidity(clientCert ClientCertificate, now time.Time) error {
+	block, _ := pem.Decode([]byte(clientCert.PEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	subject := cert.Subject.CommonName
+	if subject == "" {
+		subject = cert.SerialNumber.String()
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("credential expired: client certificate %q in %s expired on %s",
+			subject, clientCert.Field, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("credential not yet valid: client certificate %q in %s is valid from %s",
+			subject, clientCert.Field, cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CertificateStore looks up the stored certificates monitors reference by ID
+type CertificateStore interface {
+	FindByID(ctx context.Context, id string) (*shared.Certificate, error)
+}
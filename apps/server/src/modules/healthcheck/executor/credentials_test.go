@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func clientCertPEM(t *testing.T, notBefore, notAfter time.Time) string {
+	cert, _ := issueTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "peekaping-client"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil, nil)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func mtlsHTTPConfig(t *testing.T, authMethod, certPEM string) string {
+	configJSON, err := json.Marshal(map[string]any{
+		"url":                  "https://example.com",
+		"method":               "GET",
+		"encoding":             "json",
+		"accepted_statuscodes": []string{"2XX"},
+		"authMethod":           authMethod,
+		"tlsCert":              certPEM,
+		"tlsKey":               "key",
+		"tlsCa":                "ca",
+	})
+	require.NoError(t, err)
+	return string(configJSON)
+}
+
+func TestCheckClientCredentials_HTTP(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+
+	expired := clientCertPEM(t, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1))
+	result := CheckClientCredentials(executor, mtlsHTTPConfig(t, "mtls", expired), now)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, `credential expired: client certificate "peekaping-client" in tlsCert expired on 2025-07-19T12:00:00Z`, result.Message)
+
+	notYetValid := clientCertPEM(t, now.AddDate(0, 0, 1), now.AddDate(1, 0, 0))
+	result = CheckClientCredentials(executor, mtlsHTTPConfig(t, "mtls", notYetValid), now)
+	require.NotNil(t, result)
+	assert.Contains(t, result.Message, "credential not yet valid")
+
+	valid := clientCertPEM(t, now.AddDate(0, 0, -1), now.AddDate(1, 0, 0))
+	assert.Nil(t, CheckClientCredentials(executor, mtlsHTTPConfig(t, "mtls", valid), now))
+
+	// the certificate is not presented without mTLS
+	assert.Nil(t, CheckClientCredentials(executor, mtlsHTTPConfig(t, "none", expired), now))
+	// a certificate that does not parse is reported by the executor
+	assert.Nil(t, CheckClientCredentials(executor, mtlsHTTPConfig(t, "mtls", "not a certificate"), now))
+}
+
+func TestCheckClientCredentials_OtherExecutors(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	now := time.Now().UTC()
+	expired := clientCertPEM(t, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1))
+	expiredJSON, _ := json.Marshal(expired)
+
+	tests := []struct {
+		name     string
+		executor Executor
+		config   string
+		expired  bool
+	}{
+		{name: "docker with TLS", executor: NewDockerExecutor(logger), config: `{"tls_enabled": true, "tls_cert": ` + string(expiredJSON) + `}`, expired: true},
+		{name: "docker without TLS", executor: NewDockerExecutor(logger), config: `{"tls_cert": ` + string(expiredJSON) + `}`},
+		{name: "kafka with SSL", executor: NewKafkaExecutor(logger), config: `{"ssl": true, "clientCert": ` + string(expiredJSON) + `}`, expired: true},
+		{name: "redis over TLS", executor: NewRedisExecutor(logger), config: `{"databaseConnectionString": "rediss://localhost:6380", "clientCert": ` + string(expiredJSON) + `}`, expired: true},
+		{name: "redis without TLS", executor: NewRedisExecutor(logger), config: `{"databaseConnectionString": "redis://localhost:6379", "clientCert": ` + string(expiredJSON) + `}`},
+		{name: "grpc with mTLS", executor: NewGRPCExecutor(logger), config: `{"grpcTlsCert": ` + string(expiredJSON) + `}`, expired: true},
+		{name: "executor without credentials", executor: NewTCPExecutor(logger), config: `{"host": "localhost", "port": 80}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckClientCredentials(tt.executor, tt.config, now)
+			if !tt.expired {
+				assert.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, shared.MonitorStatusDown, result.Status)
+			assert.Contains(t, result.Message, "credential expired")
+		})
+	}
+}
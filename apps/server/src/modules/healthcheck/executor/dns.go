@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"peekaping/src/modules/shared"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,9 @@ type DNSConfig struct {
 	ResolverServer string `json:"resolver_server" validate:"required,ip" example:"1.1.1.1"`
 	Port           int    `json:"port" validate:"required,min=1,max=65535" example:"53"`
 	ResolveType    string `json:"resolve_type" validate:"required,oneof=A AAAA CAA CNAME MX NS PTR SOA SRV TXT" example:"A"`
+	// RequireDNSSEC marks the monitor down unless the records are DNSSEC signed
+	// and the signatures validate
+	RequireDNSSEC bool `json:"require_dnssec" example:"false"`
 }
 
 type DNSExecutor struct {
@@ -216,6 +220,33 @@ func (d *DNSExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy
 		}
 	}
 
+	if cfg.RequireDNSSEC {
+		qtype := dns.StringToType[strings.ToUpper(cfg.ResolveType)]
+		address := net.JoinHostPort(cfg.ResolverServer, strconv.Itoa(cfg.Port))
+		client := newDNSSECClient(time.Duration(m.Timeout) * time.Second)
+
+		validation, err := validateDNSSEC(ctx, client, address, dnssecQueryName(cfg.Host, qtype), qtype, time.Now())
+		endTime = time.Now().UTC()
+		if err != nil {
+			d.logger.Infof("DNSSEC validation failed: %s, %s", m.Name, err.Error())
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   fmt.Sprintf("DNSSEC validation failed: %v", err),
+				StartTime: startTime,
+				EndTime:   endTime,
+			}
+		}
+		if !validation.Validated {
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   fmt.Sprintf("%s | %s", validation.Message, message),
+				StartTime: startTime,
+				EndTime:   endTime,
+			}
+		}
+		message = fmt.Sprintf("%s | %s", message, validation.Message)
+	}
+
 	d.logger.Infof("DNS lookup successful: %s, %s", m.Name, message)
 
 	return &Result{
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// dnssecResult is the outcome of validating the DNSSEC chain of a record
+type dnssecResult struct {
+	// Validated is false when the signatures are absent or do not verify
+	Validated bool
+	Message   string
+}
+
+// dnssecExchanger sends a single DNS query, dnssecClient in production
+type dnssecExchanger interface {
+	ExchangeContext(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error)
+}
+
+// dnssecClient queries over UDP and retries over TCP when the answer did not
+// fit, which is common for DNSKEY sets
+type dnssecClient struct {
+	udp *dns.Client
+	tcp *dns.Client
+}
+
+func newDNSSECClient(timeout time.Duration) *dnssecClient {
+	return &dnssecClient{
+		udp: &dns.Client{Net: "udp", Timeout: timeout},
+		tcp: &dns.Client{Net: "tcp", Timeout: timeout},
+	}
+}
+
+func (c *dnssecClient) ExchangeContext(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+	resp, rtt, err := c.udp.ExchangeContext(ctx, m, address)
+	if err == nil && resp.Truncated {
+		return c.tcp.ExchangeContext(ctx, m, address)
+	}
+	return resp, rtt, err
+}
+
+// validateDNSSEC queries the record with the DO bit set. A resolver that
+// validated the answer sets the AD bit, which is trusted. Otherwise the RRSIG
+// of the answer is verified against the DNSKEY of the signing zone, the DNSKEY
+// set against its key signing key and that key against the DS of the parent.
+func validateDNSSEC(ctx context.Context, client dnssecExchanger, address, name string, qtype uint16, now time.Time) (*dnssecResult, error) {
+	name = dns.Fqdn(name)
+
+	answer, err := dnssecQuery(ctx, client, address, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+	if answer.AuthenticatedData {
+		return &dnssecResult{Validated: true, Message: "DNSSEC validated by resolver (AD)"}, nil
+	}
+
+	rrset, sigs := splitSigned(answer.Answer, qtype)
+	if len(rrset) == 0 {
+		return &dnssecResult{Message: fmt.Sprintf("DNSSEC validation failed: no %s records in signed answer", dns.TypeToString[qtype])}, nil
+	}
+	if len(sigs) == 0 {
+		return &dnssecResult{Message: "DNSSEC signatures absent"}, nil
+	}
+
+	zone := sigs[0].SignerName
+	keysAnswer, err := dnssecQuery(ctx, client, address, zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	keyRRs, keySigs := splitSigned(keysAnswer.Answer, dns.TypeDNSKEY)
+	keys := make([]*dns.DNSKEY, 0, len(keyRRs))
+	for _, rr := range keyRRs {
+		keys = append(keys, rr.(*dns.DNSKEY))
+	}
+	if len(keys) == 0 {
+		return &dnssecResult{Message: fmt.Sprintf("DNSSEC validation failed: no DNSKEY for %s", zone)}, nil
+	}
+
+	zsk, err := verifyRRSet(rrset, sigs, keys, now)
+	if err != nil {
+		return &dnssecResult{Message: fmt.Sprintf("DNSSEC validation failed: %s %v", dns.TypeToString[qtype], err)}, nil
+	}
+	ksk, err := verifyRRSet(keyRRs, keySigs, keys, now)
+	if err != nil {
+		return &dnssecResult{Message: fmt.Sprintf("DNSSEC validation failed: DNSKEY %v", err)}, nil
+	}
+
+	// the root is the trust anchor and has no parent to delegate from
+	if zone != "." {
+		dsAnswer, err := dnssecQuery(ctx, client, address, zone, dns.TypeDS)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesDS(ksk, dsAnswer.Answer) {
+			return &dnssecResult{Message: fmt.Sprintf("DNSSEC validation failed: no DS of %s matches key tag %d", zone, ksk.KeyTag())}, nil
+		}
+	}
+
+	return &dnssecResult{
+		Validated: true,
+		Message:   fmt.Sprintf("DNSSEC validated (%s signed with key tag %d)", strings.TrimSuffix(zone, "."), zsk.KeyTag()),
+	}, nil
+}
+
+func dnssecQuery(ctx context.Context, client dnssecExchanger, address, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.AuthenticatedData = true
+	msg.SetEdns0(4096, true)
+
+	resp, _, err := client.ExchangeContext(ctx, msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("DNSSEC query for %s %s failed: %w", name, dns.TypeToString[qtype], err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("DNSSEC query for %s %s failed: %s", name, dns.TypeToString[qtype], dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// splitSigned returns the records of the type and the signatures covering them
+func splitSigned(records []dns.RR, qtype uint16) ([]dns.RR, []*dns.RRSIG) {
+	var rrset []dns.RR
+	var sigs []*dns.RRSIG
+	for _, rr := range records {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			if sig.TypeCovered == qtype {
+				sigs = append(sigs, sig)
+			}
+			continue
+		}
+		if rr.Header().Rrtype == qtype {
+			rrset = append(rrset, rr)
+		}
+	}
+	return rrset, sigs
+}
+
+// verifyRRSet returns the key of the first signature that verifies the set
+// and is valid at now
+func verifyRRSet(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY, now time.Time) (*dns.DNSKEY, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("signature absent")
+	}
+
+	err := errors.New("no DNSKEY matches the signature")
+	for _, sig := range sigs {
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if verifyErr := sig.Verify(key, rrset); verifyErr != nil {
+				err = fmt.Errorf("signature with key tag %d: %w", sig.KeyTag, verifyErr)
+				continue
+			}
+			if !sig.ValidityPeriod(now) {
+				err = fmt.Errorf("signature with key tag %d is outside its validity period", sig.KeyTag)
+				continue
+			}
+			return key, nil
+		}
+	}
+	return nil, err
+}
+
+func matchesDS(key *dns.DNSKEY, records []dns.RR) bool {
+	for _, rr := range records {
+		ds, ok := rr.(*dns.DS)
+		if !ok || ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+			continue
+		}
+		if expected := key.ToDS(ds.DigestType); expected != nil && strings.EqualFold(expected.Digest, ds.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// dnssecQueryName returns the name the record type is looked up under, PTR
+// monitors are configured with the address
+func dnssecQueryName(host string, qtype uint16) string {
+	if qtype == dns.TypePTR && net.ParseIP(host) != nil {
+		if reverse, err := dns.ReverseAddr(host); err == nil {
+			return reverse
+		}
+	}
+	return host
+}
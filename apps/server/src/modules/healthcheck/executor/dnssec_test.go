@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubResolver answers every query from a fixed set of records per type
+type stubResolver struct {
+	answers map[uint16][]dns.RR
+	ad      bool
+}
+
+func (s *stubResolver) ExchangeContext(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+	resp := new(dns.Msg)
+	resp.SetReply(m)
+	resp.AuthenticatedData = s.ad
+	resp.Answer = s.answers[m.Question[0].Qtype]
+	return resp, time.Millisecond, nil
+}
+
+// signedZone is example.com with a single combined signing key
+type signedZone struct {
+	key     *dns.DNSKEY
+	signer  crypto.Signer
+	records []dns.RR
+}
+
+func newSignedZone(t *testing.T) *signedZone {
+	t.Helper()
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	private, err := key.Generate(256)
+	require.NoError(t, err)
+
+	a := &dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300}, A: net.ParseIP("192.0.2.1")}
+	return &signedZone{key: key, signer: private.(crypto.Signer), records: []dns.RR{a}}
+}
+
+func (z *signedZone) sign(t *testing.T, rrset []dns.RR, inception, expiration time.Time) *dns.RRSIG {
+	t.Helper()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: rrset[0].Header().Ttl},
+		KeyTag:     z.key.KeyTag(),
+		SignerName: "example.com.",
+		Algorithm:  z.key.Algorithm,
+		Inception:  uint32(inception.Unix()),
+		Expiration: uint32(expiration.Unix()),
+	}
+	require.NoError(t, sig.Sign(z.signer, rrset))
+	return sig
+}
+
+func (z *signedZone) resolver(t *testing.T, now time.Time) *stubResolver {
+	aSig := z.sign(t, z.records, now.Add(-time.Hour), now.Add(time.Hour))
+	keySig := z.sign(t, []dns.RR{z.key}, now.Add(-time.Hour), now.Add(time.Hour))
+	return &stubResolver{answers: map[uint16][]dns.RR{
+		dns.TypeA:      append(append([]dns.RR{}, z.records...), aSig),
+		dns.TypeDNSKEY: {z.key, keySig},
+		dns.TypeDS:     {z.key.ToDS(dns.SHA256)},
+	}}
+}
+
+func TestValidateDNSSEC(t *testing.T) {
+	now := time.Now()
+	zone := newSignedZone(t)
+
+	t.Run("valid chain", func(t *testing.T) {
+		result, err := validateDNSSEC(context.Background(), zone.resolver(t, now), "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.True(t, result.Validated, result.Message)
+		assert.Contains(t, result.Message, "DNSSEC validated (example.com signed with key tag")
+	})
+
+	t.Run("validated by resolver", func(t *testing.T) {
+		resolver := &stubResolver{ad: true, answers: map[uint16][]dns.RR{dns.TypeA: zone.records}}
+		result, err := validateDNSSEC(context.Background(), resolver, "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.True(t, result.Validated)
+		assert.Equal(t, "DNSSEC validated by resolver (AD)", result.Message)
+	})
+
+	t.Run("tampered record", func(t *testing.T) {
+		resolver := zone.resolver(t, now)
+		forged := dns.Copy(resolver.answers[dns.TypeA][0]).(*dns.A)
+		forged.A = net.ParseIP("203.0.113.66")
+		resolver.answers[dns.TypeA][0] = forged
+
+		result, err := validateDNSSEC(context.Background(), resolver, "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.False(t, result.Validated)
+		assert.True(t, strings.HasPrefix(result.Message, "DNSSEC validation failed: A signature with key tag"), result.Message)
+	})
+
+	t.Run("expired signature", func(t *testing.T) {
+		resolver := zone.resolver(t, now)
+		resolver.answers[dns.TypeA][1] = zone.sign(t, zone.records, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+
+		result, err := validateDNSSEC(context.Background(), resolver, "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.False(t, result.Validated)
+		assert.Contains(t, result.Message, "outside its validity period")
+	})
+
+	t.Run("unsigned zone", func(t *testing.T) {
+		resolver := &stubResolver{answers: map[uint16][]dns.RR{dns.TypeA: zone.records}}
+		result, err := validateDNSSEC(context.Background(), resolver, "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.False(t, result.Validated)
+		assert.Equal(t, "DNSSEC signatures absent", result.Message)
+	})
+
+	t.Run("broken delegation", func(t *testing.T) {
+		resolver := zone.resolver(t, now)
+		ds := resolver.answers[dns.TypeDS][0].(*dns.DS)
+		ds.Digest = strings.Repeat("0", len(ds.Digest))
+
+		result, err := validateDNSSEC(context.Background(), resolver, "stub", "example.com", dns.TypeA, now)
+		require.NoError(t, err)
+		assert.False(t, result.Validated)
+		assert.Contains(t, result.Message, "no DS of example.com. matches key tag")
+	})
+}
+
+func TestDNSSECQueryName(t *testing.T) {
+	assert.Equal(t, "1.2.0.192.in-addr.arpa.", dnssecQueryName("192.0.2.1", dns.TypePTR))
+	assert.Equal(t, "example.com", dnssecQueryName("example.com", dns.TypeA))
+}
+
+func TestDNSExecutor_Execute_RequireDNSSEC(t *testing.T) {
+	now := time.Now()
+	zone := newSignedZone(t)
+	resolver := zone.resolver(t, now)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		resp, _, _ := resolver.ExchangeContext(context.Background(), r, "")
+		w.WriteMsg(resp)
+	})}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	monitor := func(requireDNSSEC bool) *Monitor {
+		return &Monitor{
+			Name:    "dnssec",
+			Timeout: 2,
+			Config:  fmt.Sprintf(`{"host": "example.com", "resolver_server": "127.0.0.1", "port": %d, "resolve_type": "A", "require_dnssec": %t}`, port, requireDNSSEC),
+		}
+	}
+	executor := NewDNSExecutor(zap.NewNop().Sugar())
+
+	result := executor.Execute(context.Background(), monitor(true), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Contains(t, result.Message, "A records: 192.0.2.1 | DNSSEC validated")
+
+	resolver.answers[dns.TypeA] = zone.records
+	result = executor.Execute(context.Background(), monitor(true), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, "DNSSEC signatures absent | A records: 192.0.2.1", result.Message)
+
+	result = executor.Execute(context.Background(), monitor(false), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	assert.Equal(t, "A records: 192.0.2.1", result.Message)
+}
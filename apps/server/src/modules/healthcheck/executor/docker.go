@@ -5,17 +5,19 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
-	"net/http"
 	"peekaping/src/modules/shared"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"go.uber.org/zap"
 )
 
 type DockerConfig struct {
-	ContainerID    string `json:"container_id" validate:"required"`
+	ContainerID    string `json:"container_id" validate:"required_without=LabelSelector,excluded_with=LabelSelector"`
 	ConnectionType string `json:"connection_type" validate:"required,oneof=socket tcp"`
 	DockerDaemon   string `json:"docker_daemon" validate:"required"`
 	// TLS fields
@@ -24,26 +26,116 @@ type DockerConfig struct {
 	TLSKey     string `json:"tls_key,omitempty"`
 	TLSCA      string `json:"tls_ca,omitempty"`
 	TLSVerify  bool   `json:"tls_verify,omitempty"`
+
+	// Restarts the container may have between two checks, a running
+	// container restarted more often since the previous check is down. Unset
+	// leaves the restart count unchecked.
+	MaxRestarts *int `json:"max_restarts,omitempty" validate:"omitempty,min=0"`
+
+	// Labels selecting the container instead of container_id, e.g.
+	// com.docker.compose.service=api. Comma separated labels must all match,
+	// the first running match is checked.
+	LabelSelector string `json:"label_selector,omitempty" validate:"required_without=ContainerID,excluded_with=ContainerID" example:"com.docker.compose.service=api"`
 }
 
 type DockerExecutor struct {
 	logger *zap.SugaredLogger
+
+	// clients are kept across checks so the monitors of one daemon reuse its
+	// connections, keyed by daemon and TLS settings. monitorClients holds the
+	// key each monitor last used.
+	clientsMu      sync.Mutex
+	clients        map[string]*client.Client
+	monitorClients map[string]string
+
+	// restarts holds the restart count each monitor saw on its previous check
+	restartsMu sync.Mutex
+	restarts   map[string]dockerRestartCount
+}
+
+// dockerRestartCount is the restart count of the container checked by a monitor
+type dockerRestartCount struct {
+	containerID string
+	count       int
 }
 
 func NewDockerExecutor(logger *zap.SugaredLogger) *DockerExecutor {
-	return &DockerExecutor{logger: logger}
+	return &DockerExecutor{
+		logger:         logger,
+		clients:        make(map[string]*client.Client),
+		monitorClients: make(map[string]string),
+		restarts:       make(map[string]dockerRestartCount),
+	}
 }
 
 func (e *DockerExecutor) Unmarshal(configJSON string) (any, error) {
 	return GenericUnmarshal[DockerConfig](configJSON)
 }
 
+// ClientCertificates returns the certificate used to authenticate to the daemon
+func (e *DockerExecutor) ClientCertificates(cfg any) []ClientCertificate {
+	dockerCfg := cfg.(*DockerConfig)
+	if !dockerCfg.TLSEnabled || dockerCfg.TLSCert == "" {
+		return nil
+	}
+	return []ClientCertificate{{Field: "tls_cert", PEM: dockerCfg.TLSCert}}
+}
+
 func (e *DockerExecutor) Validate(configJSON string) error {
 	cfg, err := e.Unmarshal(configJSON)
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*DockerConfig))
+	dockerCfg := cfg.(*DockerConfig)
+	if err := GenericValidator(dockerCfg); err != nil {
+		return err
+	}
+	if dockerCfg.LabelSelector != "" {
+		if _, err := parseLabelSelector(dockerCfg.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label_selector: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseLabelSelector splits the comma separated key=value or key labels
+func parseLabelSelector(selector string) ([]string, error) {
+	var labels []string
+	for _, label := range strings.Split(selector, ",") {
+		label = strings.TrimSpace(label)
+		key, _, _ := strings.Cut(label, "=")
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("label %q has no key", label)
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// resolveContainer returns the container to inspect, the first running
+// container matching the label selector when one is set
+func (e *DockerExecutor) resolveContainer(ctx context.Context, cli *client.Client, cfg *DockerConfig) (string, error) {
+	if cfg.LabelSelector == "" {
+		return cfg.ContainerID, nil
+	}
+
+	labels, err := parseLabelSelector(cfg.LabelSelector)
+	if err != nil {
+		return "", err
+	}
+	args := filters.NewArgs(filters.Arg("status", "running"))
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{Filters: args})
+	if err != nil {
+		return "", fmt.Errorf("container list error: %w", err)
+	}
+	if len(containers) == 0 {
+		return "", fmt.Errorf("no running container matches label selector %s", cfg.LabelSelector)
+	}
+	return containers[0].ID, nil
 }
 
 func (e *DockerExecutor) createTLSConfig(cfg *DockerConfig) (*tls.Config, error) {
@@ -91,46 +183,17 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 
 	e.logger.Debugf("execute docker cfg: %+v", cfg)
 
-	var cli *client.Client
-	var cliErr error
-
-	if cfg.ConnectionType == "socket" {
-		cli, cliErr = client.NewClientWithOpts(
-			client.WithHost("unix://"+cfg.DockerDaemon),
-			client.WithAPIVersionNegotiation(),
-		)
-	} else if cfg.ConnectionType == "tcp" {
-		clientOpts := []client.Opt{
-			client.WithHost(cfg.DockerDaemon),
-			client.WithAPIVersionNegotiation(),
-		}
-
-		// Add TLS support for TCP connections
-		if cfg.TLSEnabled {
-			tlsConfig, err := e.createTLSConfig(cfg)
-			if err != nil {
-				return DownResult(fmt.Errorf("TLS configuration error: %w", err), start, time.Now().UTC())
-			}
-
-			httpClient := &http.Client{
-				Transport: &http.Transport{
-					TLSClientConfig: tlsConfig,
-				},
-			}
-			clientOpts = append(clientOpts, client.WithHTTPClient(httpClient))
-		}
-
-		cli, cliErr = client.NewClientWithOpts(clientOpts...)
-	} else {
-		return DownResult(fmt.Errorf("unknown docker connection type: %s", cfg.ConnectionType), start, time.Now().UTC())
+	cli, err := e.clientFor(m.ID, cfg)
+	if err != nil {
+		return DownResult(err, start, time.Now().UTC())
 	}
 
-	if cliErr != nil {
-		return DownResult(fmt.Errorf("docker client error: %w", cliErr), start, time.Now().UTC())
+	containerID, err := e.resolveContainer(ctx, cli, cfg)
+	if err != nil {
+		return DownResult(err, start, time.Now().UTC())
 	}
-	defer cli.Close()
 
-	container, err := cli.ContainerInspect(ctx, cfg.ContainerID)
+	inspect, err := cli.ContainerInspect(ctx, containerID)
 	if err != nil {
 		// Provide better error messages for common TLS issues
 		errorMsg := err.Error()
@@ -146,31 +209,64 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 		return DownResult(fmt.Errorf("container inspect error: %w", err), start, time.Now().UTC())
 	}
 
-	endTime := time.Now().UTC()
+	var restarts int
+	if inspect.ContainerJSONBase != nil {
+		restarts = e.restartsSinceLastCheck(m.ID, inspect.ID, inspect.RestartCount)
+	}
+	return containerResult(inspect, cfg, restarts, start, time.Now().UTC())
+}
+
+// restartsSinceLastCheck records the restart count of the container and
+// returns how many restarts happened since the previous check of the
+// monitor. The first check of a container only records its count.
+func (e *DockerExecutor) restartsSinceLastCheck(monitorID, containerID string, count int) int {
+	e.restartsMu.Lock()
+	defer e.restartsMu.Unlock()
+
+	previous, ok := e.restarts[monitorID]
+	e.restarts[monitorID] = dockerRestartCount{containerID: containerID, count: count}
+	if !ok || previous.containerID != containerID || count < previous.count {
+		return 0
+	}
+	return count - previous.count
+}
+
+// containerResult maps the inspected state of the container to the monitor
+// status, restarts are the restarts since the previous check
+func containerResult(inspect container.InspectResponse, cfg *DockerConfig, restarts int, start, endTime time.Time) *Result {
+	if inspect.ContainerJSONBase == nil {
+		return DownResult(fmt.Errorf("container state is nil"), start, endTime)
+	}
 
-	if container.State != nil && container.State.Running {
-		if container.State.Health != nil && container.State.Health.Status != "healthy" {
+	if inspect.State != nil && inspect.State.Running {
+		if cfg.MaxRestarts != nil && restarts > *cfg.MaxRestarts {
+			return DownResult(fmt.Errorf("container restarted %d times since the last check, more than the allowed %d", restarts, *cfg.MaxRestarts), start, endTime)
+		}
+		if inspect.State.Health != nil && inspect.State.Health.Status != "healthy" {
 			// Handle different health statuses appropriately
-			switch container.State.Health.Status {
+			switch inspect.State.Health.Status {
 			case "starting":
 				return &Result{
 					Status:    shared.MonitorStatusPending,
-					Message:   container.State.Health.Status,
+					Message:   inspect.State.Health.Status,
 					StartTime: start,
 					EndTime:   endTime,
 				}
 			case "unhealthy":
-				return DownResult(fmt.Errorf("container is unhealthy: %s", container.State.Health.Status), start, endTime)
+				return DownResult(fmt.Errorf("container is unhealthy: %s", inspect.State.Health.Status), start, endTime)
 			default:
 				// For any other non-healthy status, consider it down
-				return DownResult(fmt.Errorf("container health status: %s", container.State.Health.Status), start, endTime)
+				return DownResult(fmt.Errorf("container health status: %s", inspect.State.Health.Status), start, endTime)
 			}
 		}
 		var message string
-		if container.State.Health != nil {
-			message = container.State.Health.Status
+		if inspect.State.Health != nil {
+			message = inspect.State.Health.Status
 		} else {
-			message = container.State.Status
+			message = inspect.State.Status
+		}
+		if cfg.MaxRestarts != nil {
+			message = fmt.Sprintf("%s (restarts: %d)", message, inspect.RestartCount)
 		}
 		return &Result{
 			Status:    shared.MonitorStatusUp,
@@ -180,9 +276,9 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 		}
 	}
 
-	if container.State == nil {
+	if inspect.State == nil {
 		return DownResult(fmt.Errorf("container state is nil"), start, endTime)
 	}
 
-	return DownResult(fmt.Errorf("container state is %s", container.State.Status), start, endTime)
+	return DownResult(fmt.Errorf("container state is %s", inspect.State.Status), start, endTime)
 }
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/docker/client"
+)
+
+// dockerClientKey identifies the checks that can share a Docker client, the
+// TLS material is hashed so it does not sit in memory in another copy
+func dockerClientKey(cfg *DockerConfig) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|tls=%t", cfg.ConnectionType, cfg.DockerDaemon, cfg.TLSEnabled)
+	if cfg.TLSEnabled {
+		fmt.Fprintf(hash, "|verify=%t|%q,%q,%q", cfg.TLSVerify, cfg.TLSCert, cfg.TLSKey, cfg.TLSCA)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (e *DockerExecutor) newClient(cfg *DockerConfig) (*client.Client, error) {
+	var cli *client.Client
+	var cliErr error
+
+	if cfg.ConnectionType == "socket" {
+		cli, cliErr = client.NewClientWithOpts(
+			client.WithHost("unix://"+cfg.DockerDaemon),
+			client.WithAPIVersionNegotiation(),
+		)
+	} else if cfg.ConnectionType == "tcp" {
+		clientOpts := []client.Opt{
+			client.WithHost(cfg.DockerDaemon),
+			client.WithAPIVersionNegotiation(),
+		}
+
+		// Add TLS support for TCP connections
+		if cfg.TLSEnabled {
+			tlsConfig, err := e.createTLSConfig(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("TLS configuration error: %w", err)
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: tlsConfig,
+				},
+			}
+			clientOpts = append(clientOpts, client.WithHTTPClient(httpClient))
+		}
+
+		cli, cliErr = client.NewClientWithOpts(clientOpts...)
+	} else {
+		return nil, fmt.Errorf("unknown docker connection type: %s", cfg.ConnectionType)
+	}
+
+	if cliErr != nil {
+		return nil, fmt.Errorf("docker client error: %w", cliErr)
+	}
+	return cli, nil
+}
+
+// clientFor returns the client of the monitor, shared with every monitor of
+// the same daemon and TLS settings. A monitor moved to other settings releases
+// its previous client, which is closed once no monitor uses it.
+func (e *DockerExecutor) clientFor(monitorID string, cfg *DockerConfig) (*client.Client, error) {
+	key := dockerClientKey(cfg)
+
+	e.clientsMu.Lock()
+	defer e.clientsMu.Unlock()
+
+	if previous, ok := e.monitorClients[monitorID]; ok && previous != key {
+		delete(e.monitorClients, monitorID)
+		e.releaseClient(previous)
+	}
+
+	cli, ok := e.clients[key]
+	if !ok {
+		var err error
+		cli, err = e.newClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		e.clients[key] = cli
+	}
+	e.monitorClients[monitorID] = key
+	return cli, nil
+}
+
+// releaseClient closes the client of the key when no monitor uses it anymore,
+// the caller holds clientsMu
+func (e *DockerExecutor) releaseClient(key string) {
+	for _, used := range e.monitorClients {
+		if used == key {
+			return
+		}
+	}
+	if cli, ok := e.clients[key]; ok {
+		if err := cli.Close(); err != nil {
+			e.logger.Warnf("Failed to close docker client: %v", err)
+		}
+		delete(e.clients, key)
+	}
+}
+
+// Forget releases the client of the monitor and drops its restart count, the
+// next check of a changed monitor connects and counts again
+func (e *DockerExecutor) Forget(monitorID string) {
+	e.clientsMu.Lock()
+	if key, ok := e.monitorClients[monitorID]; ok {
+		delete(e.monitorClients, monitorID)
+		e.releaseClient(key)
+	}
+	e.clientsMu.Unlock()
+
+	e.restartsMu.Lock()
+	delete(e.restarts, monitorID)
+	e.restartsMu.Unlock()
+}
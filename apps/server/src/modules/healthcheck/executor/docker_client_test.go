@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDockerExecutor_ClientFor_ReusesClient(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	cfg := &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2375"}
+
+	first, err := executor.clientFor("monitor-1", cfg)
+	require.NoError(t, err)
+	again, err := executor.clientFor("monitor-1", cfg)
+	require.NoError(t, err)
+	other, err := executor.clientFor("monitor-2", &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2375"})
+	require.NoError(t, err)
+
+	assert.Same(t, first, again)
+	assert.Same(t, first, other, "expected monitors of one daemon to share the client")
+	assert.Len(t, executor.clients, 1)
+}
+
+func TestDockerExecutor_ClientFor_ConfigChange(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	shared := &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2375"}
+
+	_, err := executor.clientFor("monitor-1", shared)
+	require.NoError(t, err)
+	_, err = executor.clientFor("monitor-2", shared)
+	require.NoError(t, err)
+
+	// monitor-2 still uses the first client, it is kept
+	_, err = executor.clientFor("monitor-1", &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2376"})
+	require.NoError(t, err)
+	assert.Len(t, executor.clients, 2)
+	assert.Contains(t, executor.clients, dockerClientKey(shared))
+
+	// no monitor uses the first client anymore, it is closed
+	_, err = executor.clientFor("monitor-2", &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2376"})
+	require.NoError(t, err)
+	assert.Len(t, executor.clients, 1)
+	assert.NotContains(t, executor.clients, dockerClientKey(shared))
+}
+
+func TestDockerClientKey(t *testing.T) {
+	base := DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://docker:2376", TLSEnabled: true, TLSVerify: true, TLSCA: "-----BEGIN CERTIFICATE-----"}
+
+	verifyOff := base
+	verifyOff.TLSVerify = false
+	otherCA := base
+	otherCA.TLSCA = "other-ca"
+	plain := base
+	plain.TLSEnabled = false
+
+	assert.NotEqual(t, dockerClientKey(&base), dockerClientKey(&verifyOff))
+	assert.NotEqual(t, dockerClientKey(&base), dockerClientKey(&otherCA))
+	assert.NotEqual(t, dockerClientKey(&base), dockerClientKey(&plain))
+	assert.NotContains(t, dockerClientKey(&base), "CERTIFICATE", "expected the TLS material to be hashed")
+}
+
+func TestDockerExecutor_ClientFor_InvalidTLS(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	cfg := &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://docker:2376", TLSEnabled: true, TLSCA: "not a certificate"}
+
+	_, err := executor.clientFor("monitor-1", cfg)
+	assert.ErrorContains(t, err, "TLS configuration error")
+	assert.Empty(t, executor.clients)
+}
+
+func TestDockerExecutor_Forget(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	cfg := &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2375"}
+
+	_, err := executor.clientFor("monitor-1", cfg)
+	require.NoError(t, err)
+	_, err = executor.clientFor("monitor-2", cfg)
+	require.NoError(t, err)
+	executor.restartsSinceLastCheck("monitor-1", "container", 3)
+
+	// monitor-2 still uses the client, it is kept
+	executor.Forget("monitor-1")
+	assert.Len(t, executor.clients, 1)
+	assert.NotContains(t, executor.monitorClients, "monitor-1")
+	assert.NotContains(t, executor.restarts, "monitor-1")
+
+	executor.Forget("monitor-2")
+	assert.Empty(t, executor.clients)
+	assert.Empty(t, executor.monitorClients)
+}
+
+func TestExecutorRegistry_Forget(t *testing.T) {
+	registry := NewExecutorRegistry(zap.NewNop().Sugar(), nil)
+	docker := registry.registry["docker"].(*DockerExecutor)
+
+	_, err := docker.clientFor("monitor-1", &DockerConfig{ConnectionType: "tcp", DockerDaemon: "tcp://127.0.0.1:2375"})
+	require.NoError(t, err)
+
+	registry.Forget("monitor-1")
+	assert.Empty(t, docker.clients)
+
+	var none *ExecutorRegistry
+	none.Forget("monitor-1")
+}
@@ -2,11 +2,18 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"peekaping/src/modules/shared"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -1163,3 +1170,146 @@ func TestDockerExecutor_TLS_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerResult_MaxRestarts(t *testing.T) {
+	maxRestarts := 3
+	running := func(restarts int, health *container.Health) container.InspectResponse {
+		return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{
+			RestartCount: restarts,
+			State:        &container.State{Status: "running", Running: true, Health: health},
+		}}
+	}
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name        string
+		inspect     container.InspectResponse
+		restarts    int
+		maxRestarts *int
+		status      shared.MonitorStatus
+		message     string
+	}{
+		{"unchecked restarts", running(10, nil), 10, nil, shared.MonitorStatusUp, "running"},
+		{"within the limit", running(3, nil), 3, &maxRestarts, shared.MonitorStatusUp, "running (restarts: 3)"},
+		{"healthy within the limit", running(1, &container.Health{Status: "healthy"}), 1, &maxRestarts, shared.MonitorStatusUp, "healthy (restarts: 1)"},
+		{"steady count above the limit", running(12, nil), 0, &maxRestarts, shared.MonitorStatusUp, "running (restarts: 12)"},
+		{"crash looping", running(4, nil), 4, &maxRestarts, shared.MonitorStatusDown, "container restarted 4 times since the last check, more than the allowed 3"},
+		{"crash looping while healthy", running(14, &container.Health{Status: "healthy"}), 4, &maxRestarts, shared.MonitorStatusDown, "container restarted 4 times"},
+		{"stopped", container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{Status: "exited"}}}, 0, &maxRestarts, shared.MonitorStatusDown, "container state is exited"},
+		{"no inspect data", container.InspectResponse{}, 0, &maxRestarts, shared.MonitorStatusDown, "container state is nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := containerResult(tt.inspect, &DockerConfig{MaxRestarts: tt.maxRestarts}, tt.restarts, now, now)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestDockerExecutor_RestartsSinceLastCheck(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+
+	// the first check only records the count, however high it is
+	assert.Equal(t, 0, executor.restartsSinceLastCheck("m1", "c1", 12))
+	// a steady count is no restart
+	assert.Equal(t, 0, executor.restartsSinceLastCheck("m1", "c1", 12))
+	assert.Equal(t, 4, executor.restartsSinceLastCheck("m1", "c1", 16))
+	// a recreated container starts over
+	assert.Equal(t, 0, executor.restartsSinceLastCheck("m1", "c2", 1))
+	assert.Equal(t, 2, executor.restartsSinceLastCheck("m1", "c2", 3))
+	// monitors are counted apart
+	assert.Equal(t, 0, executor.restartsSinceLastCheck("m2", "c2", 3))
+}
+
+func TestDockerExecutor_Validate_MaxRestarts(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	base := `"container_id": "web", "connection_type": "socket", "docker_daemon": "/var/run/docker.sock"`
+
+	assert.NoError(t, executor.Validate(`{`+base+`, "max_restarts": 0}`))
+	assert.NoError(t, executor.Validate(`{`+base+`, "max_restarts": 5}`))
+	assert.Error(t, executor.Validate(`{`+base+`, "max_restarts": -1}`))
+}
+
+// newFakeDockerDaemon serves the container list and inspect endpoints for
+// the running containers, keyed by ID with their labels
+func newFakeDockerDaemon(t *testing.T, running map[string]map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		path := strings.TrimPrefix(r.URL.Path, "/v1.41")
+		switch {
+		case path == "/_ping":
+			_, _ = w.Write([]byte("OK"))
+		case path == "/containers/json":
+			args, err := filters.FromJSON(r.URL.Query().Get("filters"))
+			require.NoError(t, err)
+			var list []container.Summary
+			for id, labels := range running {
+				match := true
+				for _, label := range args.Get("label") {
+					key, value, _ := strings.Cut(label, "=")
+					if got, ok := labels[key]; !ok || (value != "" && got != value) {
+						match = false
+					}
+				}
+				if match {
+					list = append(list, container.Summary{ID: id, Labels: labels, State: "running"})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		case strings.HasPrefix(path, "/containers/") && strings.HasSuffix(path, "/json"):
+			id := strings.TrimSuffix(strings.TrimPrefix(path, "/containers/"), "/json")
+			if _, ok := running[id]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"No such container: ` + id + `"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{
+				ID:    id,
+				State: &container.State{Status: "running", Running: true},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDockerExecutor_Execute_LabelSelector(t *testing.T) {
+	daemon := newFakeDockerDaemon(t, map[string]map[string]string{
+		"api-1": {"com.docker.compose.service": "api", "tier": "backend"},
+		"web-1": {"com.docker.compose.service": "web"},
+	})
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+
+	monitor := func(selector string) *Monitor {
+		return &Monitor{
+			ID:      "docker-labels",
+			Name:    "docker-labels",
+			Type:    "docker",
+			Timeout: 5,
+			Config:  `{"label_selector": "` + selector + `", "connection_type": "tcp", "docker_daemon": "tcp://` + strings.TrimPrefix(daemon.URL, "http://") + `"}`,
+		}
+	}
+
+	result := executor.Execute(context.Background(), monitor("com.docker.compose.service=api, tier"), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+
+	result = executor.Execute(context.Background(), monitor("com.docker.compose.service=worker"), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "no running container matches label selector com.docker.compose.service=worker")
+}
+
+func TestDockerExecutor_Validate_LabelSelector(t *testing.T) {
+	executor := NewDockerExecutor(zap.NewNop().Sugar())
+	daemon := `"connection_type": "socket", "docker_daemon": "/var/run/docker.sock"`
+
+	assert.NoError(t, executor.Validate(`{"label_selector": "com.docker.compose.service=api", `+daemon+`}`))
+	assert.NoError(t, executor.Validate(`{"label_selector": "com.docker.compose.service=api,tier", `+daemon+`}`))
+	assert.Error(t, executor.Validate(`{"container_id": "web", "label_selector": "tier=web", `+daemon+`}`), "expected container_id and label_selector to exclude each other")
+	assert.Error(t, executor.Validate(`{`+daemon+`}`), "expected one of container_id and label_selector")
+	assert.Error(t, executor.Validate(`{"label_selector": "=api", `+daemon+`}`))
+	assert.Error(t, executor.Validate(`{"label_selector": "tier,,", `+daemon+`}`))
+}
@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"peekaping/src/modules/shared"
+	"peekaping/src/version"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ElasticConfig struct {
+	Url             string `json:"url" validate:"required,url" example:"https://localhost:9200"`
+	Username        string `json:"username" example:"elastic"`
+	Password        string `json:"password" example:"changeme"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+}
+
+// elasticClusterHealth is the subset of the /_cluster/health response used by the executor
+type elasticClusterHealth struct {
+	ClusterName        string `json:"cluster_name"`
+	Status             string `json:"status"`
+	NumberOfNodes      int    `json:"number_of_nodes"`
+	ActiveShards       int    `json:"active_shards"`
+	RelocatingShards   int    `json:"relocating_shards"`
+	InitializingShards int    `json:"initializing_shards"`
+	UnassignedShards   int    `json:"unassigned_shards"`
+}
+
+type ElasticsearchExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewElasticsearchExecutor(logger *zap.SugaredLogger) *ElasticsearchExecutor {
+	return &ElasticsearchExecutor{
+		logger: logger,
+	}
+}
+
+func (e *ElasticsearchExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[ElasticConfig](configJSON)
+}
+
+func (e *ElasticsearchExecutor) Validate(configJSON string) error {
+	cfg, err := e.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	elasticCfg := cfg.(*ElasticConfig)
+
+	if err := GenericValidator(elasticCfg); err != nil {
+		return err
+	}
+
+	parsedURL, err := url.Parse(elasticCfg.Url)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("url scheme must be http or https, got: %s", parsedURL.Scheme)
+	}
+
+	if elasticCfg.Username == "" && elasticCfg.Password != "" {
+		return fmt.Errorf("username is required when password is set")
+	}
+
+	return nil
+}
+
+func (e *ElasticsearchExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := e.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*ElasticConfig)
+
+	e.logger.Debugf("execute elasticsearch cfg: %+v", cfg)
+
+	healthURL, err := url.JoinPath(strings.TrimSuffix(cfg.Url, "/"), "_cluster/health")
+	if err != nil {
+		return DownResult(fmt.Errorf("invalid url: %w", err), time.Now().UTC(), time.Now().UTC())
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: cfg.IgnoreTlsErrors,
+		},
+	}
+	client := &http.Client{
+		Timeout:   time.Duration(m.Timeout) * time.Second,
+		Transport: buildProxyTransport(transport, proxyModel),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to create request: %w", err), time.Now().UTC(), time.Now().UTC())
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "peekaping/"+version.Version)
+	if cfg.Username != "" {
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	startTime := time.Now().UTC()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		e.logger.Infof("Elasticsearch request failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("cluster health request failed: %w", err), startTime, time.Now().UTC())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	endTime := time.Now().UTC()
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to read response: %w", err), startTime, endTime)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return DownResult(fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode, truncateMessage(string(body), 200)), startTime, endTime)
+	}
+
+	var health elasticClusterHealth
+	if err := json.Unmarshal(body, &health); err != nil {
+		return DownResult(fmt.Errorf("failed to parse cluster health: %w", err), startTime, endTime)
+	}
+
+	message := fmt.Sprintf(
+		"Cluster %s is %s (nodes: %d, active shards: %d, relocating: %d, initializing: %d, unassigned: %d)",
+		health.ClusterName,
+		health.Status,
+		health.NumberOfNodes,
+		health.ActiveShards,
+		health.RelocatingShards,
+		health.InitializingShards,
+		health.UnassignedShards,
+	)
+
+	var status shared.MonitorStatus
+	switch health.Status {
+	case "green":
+		status = shared.MonitorStatusUp
+	case "yellow":
+		status = shared.MonitorStatusPending
+	case "red":
+		status = shared.MonitorStatusDown
+	default:
+		return DownResult(fmt.Errorf("unknown cluster health status: %q", health.Status), startTime, endTime)
+	}
+
+	e.logger.Infof("Elasticsearch cluster health for %s: %s", m.Name, health.Status)
+
+	return &Result{
+		Status:    status,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
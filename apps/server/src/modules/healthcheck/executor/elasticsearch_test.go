@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestElasticsearchExecutor_Validate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewElasticsearchExecutor(logger)
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid url", config: `{"url": "http://localhost:9200"}`, wantError: false},
+		{name: "valid url with basic auth", config: `{"url": "https://localhost:9200", "username": "elastic", "password": "changeme", "ignore_tls_errors": true}`, wantError: false},
+		{name: "missing url", config: `{}`, wantError: true},
+		{name: "unsupported scheme", config: `{"url": "ftp://localhost:9200"}`, wantError: true},
+		{name: "password without username", config: `{"url": "http://localhost:9200", "password": "changeme"}`, wantError: true},
+		{name: "unknown field", config: `{"url": "http://localhost:9200", "index": "logs"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestElasticsearchExecutor_Execute(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewElasticsearchExecutor(logger)
+
+	healthStatus := "green"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_cluster/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); ok && (user != "elastic" || pass != "changeme") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"cluster_name":        "test-cluster",
+			"status":              healthStatus,
+			"number_of_nodes":     3,
+			"active_shards":       10,
+			"relocating_shards":   2,
+			"initializing_shards": 0,
+			"unassigned_shards":   1,
+		})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		healthStatus   string
+		config         string
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{
+			name:           "green cluster is up",
+			healthStatus:   "green",
+			config:         `{"url": "` + server.URL + `"}`,
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "Cluster test-cluster is green (nodes: 3, active shards: 10, relocating: 2",
+		},
+		{
+			name:           "yellow cluster is pending",
+			healthStatus:   "yellow",
+			config:         `{"url": "` + server.URL + `/"}`,
+			expectedStatus: shared.MonitorStatusPending,
+			messageContain: "is yellow",
+		},
+		{
+			name:           "red cluster is down",
+			healthStatus:   "red",
+			config:         `{"url": "` + server.URL + `", "username": "elastic", "password": "changeme"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "is red",
+		},
+		{
+			name:           "wrong credentials",
+			healthStatus:   "green",
+			config:         `{"url": "` + server.URL + `", "username": "elastic", "password": "wrong"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "unexpected status code: 401",
+		},
+		{
+			name:           "unknown status",
+			healthStatus:   "purple",
+			config:         `{"url": "` + server.URL + `"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "unknown cluster health status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthStatus = tt.healthStatus
+
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "elasticsearch",
+				Name:     "Test Elasticsearch",
+				Interval: 30,
+				Timeout:  5,
+				Config:   tt.config,
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"peekaping/src/config"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/shared"
 	"time"
@@ -15,6 +16,13 @@ type Result struct {
 	Message   string
 	StartTime time.Time
 	EndTime   time.Time
+	// Duration is the wall-clock time of the whole check, set by the
+	// supervisor. Unlike StartTime and EndTime it covers every sample.
+	Duration time.Duration
+	// Samples is set when the check was made of several probes
+	Samples *SampleStats
+	// Headers holds the response headers the monitor captures
+	Headers map[string]string
 }
 
 type Monitor = shared.Monitor
@@ -44,9 +52,16 @@ type Executor interface {
 	Unmarshal(configJSON string) (any, error)
 }
 
+// MonitorForgetter is implemented by executors that keep state per monitor,
+// Forget drops the state of a deleted or changed monitor
+type MonitorForgetter interface {
+	Forget(monitorID string)
+}
+
 type ExecutorRegistry struct {
 	logger   *zap.SugaredLogger
 	registry map[string]Executor
+	limiter  *ProbeRateLimiter
 }
 
 func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.Service) *ExecutorRegistry {
@@ -64,10 +79,20 @@ func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.S
 	registry["mysql"] = NewMySQLExecutor(logger)
 	registry["postgres"] = NewPostgresExecutor(logger)
 	registry["sqlserver"] = NewSQLServerExecutor(logger)
+	registry["oracle"] = NewOracleExecutor(logger)
+	registry["cassandra"] = NewCassandraExecutor(logger)
+	registry["kubernetes"] = NewK8sExecutor(logger)
 	registry["redis"] = NewRedisExecutor(logger)
 	registry["mqtt"] = NewMQTTExecutor(logger)
 	registry["rabbitmq"] = NewRabbitMQExecutor(logger)
 	registry["kafka-producer"] = NewKafkaProducerExecutor(logger)
+	registry["kafka"] = NewKafkaExecutor(logger)
+	registry["websocket"] = NewWebSocketExecutor(logger)
+	registry["elasticsearch"] = NewElasticsearchExecutor(logger)
+	registry["steam"] = NewGameServerExecutor(logger)
+	registry["ssh"] = NewSSHExecutor(logger)
+	registry["smtp"] = NewSMTPExecutor(logger)
+	registry["alertmanager"] = NewAlertmanagerExecutor(logger)
 
 	return &ExecutorRegistry{
 		registry: registry,
@@ -75,6 +100,24 @@ func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.S
 	}
 }
 
+// NewThrottledExecutorRegistry creates the registry with the probe rate limits
+// from the config, HTTP monitors resolve their stored certificates and bodies
+// from certificates and blobs
+func NewThrottledExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.Service, cfg *config.Config, certificates CertificateStore, blobs BlobStore) *ExecutorRegistry {
+	registry := NewExecutorRegistry(logger, heartbeatService)
+	httpExecutor := registry.registry["http"].(*HTTPExecutor)
+	httpExecutor.certificates = certificates
+	httpExecutor.blobs = blobs
+
+	limiter := NewProbeRateLimiter(float64(cfg.ProbeMaxRPSPerHost), float64(cfg.ProbeMaxRPSGlobal))
+	if limiter.Enabled() {
+		logger.Infof("Probe rate limit enabled: %d rps per host, %d rps global", cfg.ProbeMaxRPSPerHost, cfg.ProbeMaxRPSGlobal)
+		registry.limiter = limiter
+	}
+
+	return registry
+}
+
 // func (f *ExecutorRegistry) RegisterExecutor(name string, executor Executor) {
 // 	f.registry[name] = executor
 // }
@@ -84,6 +127,33 @@ func (f *ExecutorRegistry) GetExecutor(name string) (Executor, bool) {
 	return e, ok
 }
 
+// Forget drops the state the executors keep for the monitor
+func (er *ExecutorRegistry) Forget(monitorID string) {
+	if er == nil {
+		return
+	}
+	for _, executor := range er.registry {
+		if forgetter, ok := executor.(MonitorForgetter); ok {
+			forgetter.Forget(monitorID)
+		}
+	}
+}
+
+// WaitForProbeSlot blocks until the probe rate limits allow a check of the monitor's
+// target host and returns the delay. It returns immediately when no limit is configured.
+func (er *ExecutorRegistry) WaitForProbeSlot(ctx context.Context, m *Monitor) (time.Duration, error) {
+	if er == nil || er.limiter == nil {
+		return 0, nil
+	}
+
+	host := probeTargetHost(m.Config)
+	if host == "" && er.limiter.global == nil {
+		return 0, nil
+	}
+
+	return er.limiter.Wait(ctx, host)
+}
+
 func (er *ExecutorRegistry) ValidateConfig(monitorType string, configJSON string) error {
 	executor, ok := er.GetExecutor(monitorType)
 	if !ok {
@@ -97,5 +167,15 @@ func (er *ExecutorRegistry) ValidateConfig(monitorType string, configJSON string
 		return err
 	}
 
+	if validator, ok := executor.(ReferenceValidator); ok {
+		cfg, err := executor.Unmarshal(configJSON)
+		if err != nil {
+			return err
+		}
+		if err := validator.ValidateReferences(context.Background(), cfg); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -41,16 +41,31 @@ func (m *ExecutorMockHeartbeatService) FindUptimeStatsByMonitorID(ctx context.Co
 	return args.Get(0).(map[string]float64), args.Error(1)
 }
 
+func (m *ExecutorMockHeartbeatService) FindLatencyPercentilesByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]*heartbeat.LatencyPercentiles, error) {
+	args := m.Called(ctx, monitorID, periods, now)
+	return args.Get(0).(map[string]*heartbeat.LatencyPercentiles), args.Error(1)
+}
+
 func (m *ExecutorMockHeartbeatService) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
 	args := m.Called(ctx, cutoff)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *ExecutorMockHeartbeatService) DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, monitorID, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *ExecutorMockHeartbeatService) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
 	args := m.Called(ctx, monitorID, limit, page, important, reverse)
 	return args.Get(0).([]*heartbeat.Model), args.Error(1)
 }
 
+func (m *ExecutorMockHeartbeatService) FindByMonitorIDAfterCursor(ctx context.Context, monitorID string, limit int, cursor string, important *bool) (*heartbeat.CursorPage, error) {
+	args := m.Called(ctx, monitorID, limit, cursor, important)
+	return args.Get(0).(*heartbeat.CursorPage), args.Error(1)
+}
+
 func (m *ExecutorMockHeartbeatService) DeleteByMonitorID(ctx context.Context, monitorID string) error {
 	args := m.Called(ctx, monitorID)
 	return args.Error(0)
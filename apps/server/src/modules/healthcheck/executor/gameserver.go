@@ -0,0 +1,231 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GameServerConfig describes a game server answering the Source engine query protocol (A2S)
+type GameServerConfig struct {
+	Host     string `json:"host" validate:"required" example:"play.example.com"`
+	Port     int    `json:"port" validate:"required,min=1,max=65535" example:"27015"`
+	GameType string `json:"game_type" validate:"required,oneof=source csgo cs2 tf2 css dods gmod l4d l4d2 rust ark insurgency squad unturned valheim 7dtd arma3 dayz" example:"cs2"`
+}
+
+// gameServerInfo holds the A2S_INFO fields reported in the check result
+type gameServerInfo struct {
+	Name       string
+	Map        string
+	Game       string
+	Players    int
+	MaxPlayers int
+	Bots       int
+}
+
+const (
+	a2sHeaderSingle   = 0xFFFFFFFF
+	a2sHeaderSplit    = 0xFFFFFFFE
+	a2sInfoResponse   = 0x49
+	a2sChallenge      = 0x41
+	a2sMaxPacketBytes = 1400
+)
+
+var a2sInfoRequest = append([]byte{0xFF, 0xFF, 0xFF, 0xFF, 'T'}, append([]byte("Source Engine Query"), 0x00)...)
+
+type GameServerExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewGameServerExecutor(logger *zap.SugaredLogger) *GameServerExecutor {
+	return &GameServerExecutor{
+		logger: logger,
+	}
+}
+
+func (g *GameServerExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[GameServerConfig](configJSON)
+}
+
+func (g *GameServerExecutor) Validate(configJSON string) error {
+	cfg, err := g.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	gameCfg := cfg.(*GameServerConfig)
+	if err := GenericValidator(gameCfg); err != nil {
+		return err
+	}
+
+	if _, _, err := net.SplitHostPort(gameCfg.Host); err == nil {
+		return fmt.Errorf("host must not include a port, use the port field instead")
+	}
+
+	return nil
+}
+
+func (g *GameServerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := g.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*GameServerConfig)
+
+	g.logger.Debugf("execute game server cfg: %+v", cfg)
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	timeout := time.Duration(m.Timeout) * time.Second
+
+	startTime := time.Now().UTC()
+
+	info, err := g.queryInfo(ctx, address, timeout)
+	endTime := time.Now().UTC()
+	if err != nil {
+		g.logger.Infof("Game server query failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("game server query failed: %w", err), startTime, endTime)
+	}
+
+	g.logger.Infof("Game server query successful: %s", m.Name)
+
+	message := fmt.Sprintf("%d/%d players on %s", info.Players, info.MaxPlayers, info.Map)
+	if info.Bots > 0 {
+		message = fmt.Sprintf("%s (%d bots)", message, info.Bots)
+	}
+	if info.Name != "" {
+		message = fmt.Sprintf("%s - %s", message, info.Name)
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// queryInfo sends an A2S_INFO request, answering a challenge if the server asks for one
+func (g *GameServerExecutor) queryInfo(ctx context.Context, address string, timeout time.Duration) (*gameServerInfo, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	request := a2sInfoRequest
+	buf := make([]byte, a2sMaxPacketBytes)
+
+	// Servers may answer with a single challenge before sending the info response
+	for attempt := 0; attempt < 2; attempt++ {
+		if _, err := conn.Write(request); err != nil {
+			return nil, fmt.Errorf("failed to send query: %w", err)
+		}
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("no response: %w", err)
+		}
+
+		payload, err := a2sPayload(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+
+		switch payload[0] {
+		case a2sInfoResponse:
+			return parseA2SInfo(payload[1:])
+		case a2sChallenge:
+			if len(payload) < 5 {
+				return nil, errors.New("malformed challenge response")
+			}
+			request = append(append([]byte{}, a2sInfoRequest...), payload[1:5]...)
+		default:
+			return nil, fmt.Errorf("unexpected response type 0x%02x", payload[0])
+		}
+	}
+
+	return nil, errors.New("server kept answering with a challenge")
+}
+
+// a2sPayload strips the packet header, split responses are not used for A2S_INFO
+func a2sPayload(packet []byte) ([]byte, error) {
+	if len(packet) < 5 {
+		return nil, errors.New("response too short")
+	}
+	switch binary.LittleEndian.Uint32(packet[:4]) {
+	case a2sHeaderSingle:
+		return packet[4:], nil
+	case a2sHeaderSplit:
+		return nil, errors.New("split responses are not supported")
+	default:
+		return nil, errors.New("invalid response header")
+	}
+}
+
+// parseA2SInfo decodes the body of an A2S_INFO response following the type byte
+func parseA2SInfo(body []byte) (*gameServerInfo, error) {
+	r := bytes.NewReader(body)
+
+	if _, err := r.ReadByte(); err != nil { // protocol version
+		return nil, errors.New("truncated info response")
+	}
+
+	readString := func() (string, error) {
+		var b bytes.Buffer
+		for {
+			c, err := r.ReadByte()
+			if err != nil {
+				return "", errors.New("truncated info response")
+			}
+			if c == 0 {
+				return b.String(), nil
+			}
+			b.WriteByte(c)
+		}
+	}
+
+	info := &gameServerInfo{}
+	var err error
+	if info.Name, err = readString(); err != nil {
+		return nil, err
+	}
+	if info.Map, err = readString(); err != nil {
+		return nil, err
+	}
+	if _, err = readString(); err != nil { // folder
+		return nil, err
+	}
+	if info.Game, err = readString(); err != nil {
+		return nil, err
+	}
+
+	var fixed struct {
+		AppID      uint16
+		Players    uint8
+		MaxPlayers uint8
+		Bots       uint8
+	}
+	if err := binary.Read(r, binary.LittleEndian, &fixed); err != nil {
+		return nil, errors.New("truncated info response")
+	}
+
+	info.Players = int(fixed.Players)
+	info.MaxPlayers = int(fixed.MaxPlayers)
+	info.Bots = int(fixed.Bots)
+	return info, nil
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func a2sInfoPacket(name, mapName string, players, maxPlayers, bots byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, a2sInfoResponse, 17})
+	for _, s := range []string{name, mapName, "csgo", "Counter-Strike"} {
+		b.WriteString(s)
+		b.WriteByte(0)
+	}
+	b.Write([]byte{0xDA, 0x02}) // app id 730
+	b.Write([]byte{players, maxPlayers, bots})
+	b.Write([]byte{'d', 'l', 0, 1}) // server type, environment, visibility, vac
+	return b.Bytes()
+}
+
+// startA2SServer answers A2S_INFO queries, optionally requiring a challenge first
+func startA2SServer(t *testing.T, withChallenge bool, response []byte) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+
+	challenge := []byte{0x11, 0x22, 0x33, 0x44}
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			request := buf[:n]
+			if !bytes.HasPrefix(request, a2sInfoRequest) {
+				continue
+			}
+			if withChallenge && !bytes.Equal(request[len(a2sInfoRequest):], challenge) {
+				conn.WriteToUDP(append([]byte{0xFF, 0xFF, 0xFF, 0xFF, a2sChallenge}, challenge...), addr)
+				continue
+			}
+			conn.WriteToUDP(response, addr)
+		}
+	}()
+
+	return conn
+}
+
+func TestGameServerExecutor_Validate(t *testing.T) {
+	executor := NewGameServerExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid config", config: `{"host": "play.example.com", "port": 27015, "game_type": "cs2"}`},
+		{name: "unsupported game type", config: `{"host": "play.example.com", "port": 27015, "game_type": "quake3"}`, wantError: true},
+		{name: "missing game type", config: `{"host": "play.example.com", "port": 27015}`, wantError: true},
+		{name: "invalid port", config: `{"host": "play.example.com", "port": 70000, "game_type": "tf2"}`, wantError: true},
+		{name: "port in host", config: `{"host": "play.example.com:27015", "port": 27015, "game_type": "tf2"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGameServerExecutor_Execute(t *testing.T) {
+	executor := NewGameServerExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name           string
+		withChallenge  bool
+		response       []byte
+		expectedStatus shared.MonitorStatus
+		expectedMsg    string
+	}{
+		{
+			name:           "info response",
+			response:       a2sInfoPacket("Test Server", "de_dust2", 14, 32, 0),
+			expectedStatus: shared.MonitorStatusUp,
+			expectedMsg:    "14/32 players on de_dust2 - Test Server",
+		},
+		{
+			name:           "info response after challenge",
+			withChallenge:  true,
+			response:       a2sInfoPacket("", "cp_badlands", 3, 24, 2),
+			expectedStatus: shared.MonitorStatusUp,
+			expectedMsg:    "3/24 players on cp_badlands (2 bots)",
+		},
+		{
+			name:           "truncated response",
+			response:       []byte{0xFF, 0xFF, 0xFF, 0xFF, a2sInfoResponse, 17, 'x'},
+			expectedStatus: shared.MonitorStatusDown,
+			expectedMsg:    "truncated info response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startA2SServer(t, tt.withChallenge, tt.response)
+			defer server.Close()
+
+			monitor := &Monitor{
+				ID:      "monitor1",
+				Type:    "steam",
+				Name:    "Test Game Server",
+				Timeout: 2,
+				Config:  fmt.Sprintf(`{"host": "127.0.0.1", "port": %d, "game_type": "csgo"}`, server.LocalAddr().(*net.UDPAddr).Port),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
@@ -2,12 +2,28 @@ package executor
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
 	"peekaping/src/modules/shared"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestGRPCExecutor_Unmarshal(t *testing.T) {
@@ -174,6 +190,30 @@ func TestGRPCExecutor_Validate(t *testing.T) {
 			expectedError: true,
 			description:   "grpcServiceName is required",
 		},
+		{
+			name: "valid grpcMetadata",
+			config: `{
+				"grpcUrl": "localhost:50051",
+				"grpcProtobuf": "syntax = \"proto3\";",
+				"grpcServiceName": "Health",
+				"grpcMethod": "check",
+				"grpcMetadata": "{\"authorization\":\"Bearer token\"}"
+			}`,
+			expectedError: false,
+			description:   "grpcMetadata is a JSON map",
+		},
+		{
+			name: "invalid grpcMetadata",
+			config: `{
+				"grpcUrl": "localhost:50051",
+				"grpcProtobuf": "syntax = \"proto3\";",
+				"grpcServiceName": "Health",
+				"grpcMethod": "check",
+				"grpcMetadata": "{authorization: token"
+			}`,
+			expectedError: true,
+			description:   "grpcMetadata must be valid JSON",
+		},
 		{
 			name: "missing grpcMethod",
 			config: `{
@@ -199,9 +239,10 @@ func TestGRPCExecutor_Validate(t *testing.T) {
 }
 
 func TestGRPCExecutor_Execute(t *testing.T) {
-	// Setup
+	// Setup, no server is running so the canned responses exercise the keyword logic
 	logger := zap.NewNop().Sugar()
 	executor := NewGRPCExecutor(logger)
+	executor.mockResponses = true
 
 	tests := []struct {
 		name           string
@@ -320,6 +361,266 @@ func TestGRPCExecutor_Execute(t *testing.T) {
 	}
 }
 
+func TestGRPCExecutor_Execute_UnreachableServer(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewGRPCExecutor(logger)
+
+	// Reserve a port and close it so nothing is listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	monitor := &Monitor{
+		ID:      "monitor1",
+		Type:    "grpc-keyword",
+		Name:    "Test gRPC Monitor",
+		Timeout: 1,
+		Config: `{
+			"grpcUrl": "` + addr + `",
+			"grpcProtobuf": "syntax = \"proto3\";",
+			"grpcServiceName": "Health",
+			"grpcMethod": "check",
+			"keyword": "OK"
+		}`,
+	}
+
+	result := executor.Execute(context.Background(), monitor, nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "Error in send gRPC")
+	assert.NotContains(t, result.Message, "SERVING")
+}
+
+func TestGRPCExecutor_Execute_HealthServer(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewGRPCExecutor(logger)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	newMonitor := func(keyword string) *Monitor {
+		config, err := json.Marshal(map[string]any{
+			"grpcUrl":         listener.Addr().String(),
+			"grpcProtobuf":    "package grpc.health.v1; service Health { rpc Check(HealthCheckRequest) returns (HealthCheckResponse); }",
+			"grpcServiceName": "Health",
+			"grpcMethod":      "Check",
+			"keyword":         keyword,
+		})
+		require.NoError(t, err)
+		return &Monitor{
+			ID:      "monitor1",
+			Type:    "grpc-keyword",
+			Name:    "Test gRPC Monitor",
+			Timeout: 2,
+			Config:  string(config),
+		}
+	}
+
+	result := executor.Execute(context.Background(), newMonitor("\"SERVING\""), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	result = executor.Execute(context.Background(), newMonitor("\"SERVING\""), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status, result.Message)
+	assert.Contains(t, result.Message, "NOT_SERVING")
+}
+
+func TestGRPCExecutor_Execute_Metadata(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewGRPCExecutor(logger)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// Reject calls without the expected token, like an auth interceptor would
+	authInterceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("authorization"); len(values) == 0 || values[0] != "Bearer secret" {
+			return nil, status.Error(codes.Unauthenticated, "missing or invalid token")
+		}
+		return handler(ctx, req)
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(authInterceptor))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	newMonitor := func(grpcMetadata string) *Monitor {
+		config, err := json.Marshal(map[string]any{
+			"grpcUrl":         listener.Addr().String(),
+			"grpcProtobuf":    "package grpc.health.v1; service Health { rpc Check(HealthCheckRequest) returns (HealthCheckResponse); }",
+			"grpcServiceName": "Health",
+			"grpcMethod":      "Check",
+			"grpcMetadata":    grpcMetadata,
+		})
+		require.NoError(t, err)
+		return &Monitor{
+			ID:      "monitor1",
+			Type:    "grpc-keyword",
+			Name:    "Test gRPC Monitor",
+			Timeout: 2,
+			Config:  string(config),
+		}
+	}
+
+	result := executor.Execute(context.Background(), newMonitor(""), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status, result.Message)
+	assert.Contains(t, result.Message, "Unauthenticated")
+
+	result = executor.Execute(context.Background(), newMonitor(`{"Authorization":"Bearer secret"}`), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Contains(t, result.Message, "SERVING")
+}
+
+func TestGRPCExecutor_Validate_MTLS(t *testing.T) {
+	executor := NewGRPCExecutor(zap.NewNop().Sugar())
+	certs := newGRPCTestMTLSCerts(t)
+
+	newConfig := func(cert, key, ca string) string {
+		config, err := json.Marshal(map[string]any{
+			"grpcUrl":         "localhost:50051",
+			"grpcProtobuf":    "syntax = \"proto3\";",
+			"grpcServiceName": "Health",
+			"grpcMethod":      "check",
+			"grpcTlsCert":     cert,
+			"grpcTlsKey":      key,
+			"grpcTlsCa":       ca,
+		})
+		require.NoError(t, err)
+		return string(config)
+	}
+
+	assert.NoError(t, executor.Validate(newConfig(certs.clientCert, certs.clientKey, certs.ca)))
+	assert.Error(t, executor.Validate(newConfig(certs.clientCert, certs.clientKey, "")), "CA is required with the client cert")
+	assert.Error(t, executor.Validate(newConfig(certs.clientCert, "", certs.ca)), "key is required with the client cert")
+	assert.Error(t, executor.Validate(newConfig("", "", certs.ca)), "cert and key are required with the CA")
+	assert.ErrorContains(t, executor.Validate(newConfig(certs.clientCert, certs.serverKey, certs.ca)), "invalid mTLS cert/key")
+	assert.ErrorContains(t, executor.Validate(newConfig(certs.clientCert, certs.clientKey, "not a cert")), "invalid mTLS CA cert")
+}
+
+func TestGRPCExecutor_Execute_MTLS(t *testing.T) {
+	executor := NewGRPCExecutor(zap.NewNop().Sugar())
+	certs := newGRPCTestMTLSCerts(t)
+
+	serverCert, err := tls.X509KeyPair([]byte(certs.serverCert), []byte(certs.serverKey))
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(certs.ca)))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	newMonitor := func(extra map[string]any) *Monitor {
+		config := map[string]any{
+			"grpcUrl":         listener.Addr().String(),
+			"grpcProtobuf":    "package grpc.health.v1; service Health { rpc Check(HealthCheckRequest) returns (HealthCheckResponse); }",
+			"grpcServiceName": "Health",
+			"grpcMethod":      "Check",
+		}
+		for k, v := range extra {
+			config[k] = v
+		}
+		configJSON, err := json.Marshal(config)
+		require.NoError(t, err)
+		return &Monitor{
+			ID:      "monitor1",
+			Type:    "grpc-keyword",
+			Name:    "Test gRPC Monitor",
+			Timeout: 2,
+			Config:  string(configJSON),
+		}
+	}
+
+	result := executor.Execute(context.Background(), newMonitor(map[string]any{
+		"grpcTlsCert": certs.clientCert,
+		"grpcTlsKey":  certs.clientKey,
+		"grpcTlsCa":   certs.ca,
+	}), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Contains(t, result.Message, "SERVING")
+
+	// The server rejects a certificate that is not valid for client authentication
+	result = executor.Execute(context.Background(), newMonitor(map[string]any{
+		"grpcTlsCert": certs.serverCert,
+		"grpcTlsKey":  certs.serverKey,
+		"grpcTlsCa":   certs.ca,
+	}), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status, result.Message)
+}
+
+type grpcTestMTLSCerts struct {
+	ca                    string
+	serverCert, serverKey string
+	clientCert, clientKey string
+}
+
+func newGRPCTestMTLSCerts(t *testing.T) *grpcTestMTLSCerts {
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(24 * time.Hour)
+
+	ca, caKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test gRPC CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	server, serverKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}, ca, caKey)
+
+	client, clientKey := issueTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "peekaping"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	encodeKey := func(key *ecdsa.PrivateKey) string {
+		der, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	}
+	encodeCert := func(cert *x509.Certificate) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+
+	return &grpcTestMTLSCerts{
+		ca:         encodeCert(ca),
+		serverCert: encodeCert(server),
+		serverKey:  encodeKey(serverKey),
+		clientCert: encodeCert(client),
+		clientKey:  encodeKey(clientKey),
+	}
+}
+
 func TestNewGRPCExecutor(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
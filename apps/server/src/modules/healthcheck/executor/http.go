@@ -3,9 +3,12 @@ package executor
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -15,10 +18,10 @@ import (
 	"peekaping/src/utils"
 	"peekaping/src/version"
 	"strings"
+	"sync"
 	"time"
 
 	"crypto/tls"
-	"crypto/x509"
 
 	"github.com/Azure/go-ntlmssp"
 	"github.com/go-playground/validator/v10"
@@ -54,6 +57,39 @@ func HTTPConfigStructLevelValidation(sl validator.StructLevel) {
 		// No validation needed
 	}
 
+	if cfg.ExpectedRedirectTo != "" && cfg.MaxRedirects != 0 {
+		sl.ReportError(cfg.MaxRedirects, "MaxRedirects", "max_redirects", "eq0_with_expected_redirect_to", "")
+	}
+	if cfg.ExpectedRedirectCount != nil {
+		if cfg.ExpectedRedirectTo != "" {
+			sl.ReportError(cfg.ExpectedRedirectCount, "ExpectedRedirectCount", "expected_redirect_count", "excluded_with_expected_redirect_to", "")
+		} else if *cfg.ExpectedRedirectCount > cfg.MaxRedirects {
+			sl.ReportError(cfg.ExpectedRedirectCount, "ExpectedRedirectCount", "expected_redirect_count", "ltefield_max_redirects", "")
+		}
+	}
+
+	if cfg.ExpectedALPN != "" || cfg.ExpectedTLSVersion != "" {
+		if u, err := url.Parse(cfg.Url); err == nil && u.Scheme != "https" {
+			sl.ReportError(cfg.Url, "Url", "url", "https_with_expected_tls", "")
+		}
+		if cfg.ExpectedALPN != "" && cfg.AuthMethod == "ntlm" {
+			sl.ReportError(cfg.ExpectedALPN, "ExpectedALPN", "expected_alpn", "excluded_with_auth_ntlm", "")
+		}
+	}
+
+	if cfg.HTTPVersion == "http2" {
+		if u, err := url.Parse(cfg.Url); err == nil && u.Scheme != "https" {
+			sl.ReportError(cfg.Url, "Url", "url", "https_with_http2", "")
+		}
+		if cfg.AuthMethod == "ntlm" {
+			sl.ReportError(cfg.HTTPVersion, "HTTPVersion", "http_version", "excluded_with_auth_ntlm", "")
+		}
+	}
+
+	if cfg.TlsCertificateID != "" && cfg.AuthMethod != "mtls" {
+		sl.ReportError(cfg.TlsCertificateID, "TlsCertificateID", "tls_certificate_id", "excluded_unless_auth_mtls", "")
+	}
+
 	// Authentication validation
 	switch cfg.AuthMethod {
 	case "none":
@@ -98,6 +134,13 @@ func HTTPConfigStructLevelValidation(sl validator.StructLevel) {
 		}
 		// OauthScopes is optional
 	case "mtls":
+		if cfg.TlsCertificateID != "" {
+			// the stored certificate replaces the inline PEM fields
+			if cfg.TlsCert != "" || cfg.TlsKey != "" || cfg.TlsCa != "" {
+				sl.ReportError(cfg.TlsCertificateID, "TlsCertificateID", "tls_certificate_id", "excluded_with_inline_tls", "")
+			}
+			break
+		}
 		if cfg.TlsCert == "" {
 			sl.ReportError(cfg.TlsCert, "TlsCert", "tlsCert", "required_with_auth_mtls", "")
 		}
@@ -120,6 +163,52 @@ type HTTPConfig struct {
 	AcceptedStatusCodes []string `json:"accepted_statuscodes" validate:"required,dive,oneof=2XX 3XX 4XX 5XX"`
 	MaxRedirects        int      `json:"max_redirects" validate:"omitempty,min=0"`
 	IgnoreTlsErrors     bool     `json:"ignore_tls_errors"`
+	// Verify the served certificate chain is complete without relying on
+	// locally available intermediates
+	CheckCertChain bool `json:"check_cert_chain,omitempty"`
+
+	// Protocol assertions against the completed handshake, e.g. h2 and 1.3
+	ExpectedALPN       string `json:"expected_alpn,omitempty" validate:"omitempty"`
+	ExpectedTLSVersion string `json:"expected_tls_version,omitempty" validate:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+
+	// HTTP protocol of the request, auto negotiates like any client while
+	// http1 and http2 pin it. A pinned http2 fails when the server falls back.
+	HTTPVersion string `json:"http_version,omitempty" validate:"omitempty,oneof=auto http1 http2"`
+
+	// Latency sampling, the median of the probes decides the check status
+	SamplesPerCheck    int `json:"samples_per_check,omitempty" validate:"omitempty,min=1,max=20"`
+	MaxMedianLatencyMs int `json:"max_median_latency_ms,omitempty" validate:"omitempty,min=1"`
+
+	// Redirect assertion, requires max_redirects to be 0
+	ExpectedRedirectTo    string `json:"expected_redirect_to,omitempty" validate:"omitempty"`
+	ExpectedRedirectMatch string `json:"expected_redirect_match,omitempty" validate:"omitempty,oneof=exact prefix"`
+
+	// Exact number of redirects the request must follow, within max_redirects
+	ExpectedRedirectCount *int `json:"expected_redirect_count,omitempty" validate:"omitempty,min=0"`
+
+	// JSON Schema the response body must conform to
+	ExpectedJsonSchema string `json:"expected_json_schema,omitempty" validate:"omitempty,json"`
+
+	// Keyword the response body must contain, or must not contain when inverted
+	Keyword       string `json:"keyword,omitempty" validate:"omitempty"`
+	InvertKeyword bool   `json:"invert_keyword,omitempty"`
+
+	// Assertions on the status, headers and JSON body, evaluated in order
+	// once the status code is accepted. The first failing one marks the
+	// check down.
+	Assertions []HTTPAssertion `json:"assertions,omitempty" validate:"omitempty,max=50,dive"`
+
+	// Response headers recorded on the heartbeat for debugging, e.g. Server
+	// or X-Cache. Long values are truncated.
+	CaptureHeaders []string `json:"capture_headers,omitempty" validate:"omitempty,max=20,dive,required,max=128"`
+
+	// Deadline for reading the response body once the headers are received,
+	// separate from the monitor timeout. Status-only checks never read the body.
+	ReadDeadlineMs int `json:"read_deadline_ms,omitempty" validate:"omitempty,min=1"`
+
+	// Stored blob sent as request body in place of body, for payloads too
+	// large to keep in the monitor config
+	BodyRef string `json:"body_ref,omitempty" validate:"omitempty,excluded_with=Body"`
 
 	// Authentication fields
 	AuthMethod        string `json:"authMethod" validate:"required,oneof=none basic oauth2-cc ntlm mtls"`
@@ -135,19 +224,40 @@ type HTTPConfig struct {
 	TlsCert           string `json:"tlsCert,omitempty"`
 	TlsKey            string `json:"tlsKey,omitempty"`
 	TlsCa             string `json:"tlsCa,omitempty"`
+	// Stored certificate presented for mtls in place of tlsCert, tlsKey and tlsCa
+	TlsCertificateID string `json:"tls_certificate_id,omitempty"`
 }
 
 type HTTPExecutor struct {
-	client *http.Client
 	logger *zap.SugaredLogger
+
+	// NTLM authenticates the underlying connection rather than the request,
+	// so its transports are kept across executions to preserve keep-alive
+	// connections between the negotiate, challenge and authenticate steps.
+	ntlmMu         sync.Mutex
+	ntlmTransports map[string]*http.Transport
+
+	// transport is shared by the checks without proxy or custom TLS settings
+	// so their keep-alive connections are reused, the others share a
+	// transport per proxy and TLS settings
+	transport    *http.Transport
+	transportsMu sync.Mutex
+	transports   map[string]http.RoundTripper
+
+	// certificates resolves tls_certificate_id, nil when no store is wired
+	certificates CertificateStore
+	// blobs resolves body_ref, nil when no store is wired
+	blobs BlobStore
 }
 
 func NewHTTPExecutor(logger *zap.SugaredLogger) *HTTPExecutor {
 	utils.Validate.RegisterStructValidation(HTTPConfigStructLevelValidation, HTTPConfig{})
 
 	return &HTTPExecutor{
-		client: &http.Client{},
-		logger: logger,
+		logger:         logger,
+		ntlmTransports: make(map[string]*http.Transport),
+		transport:      newPooledTransport(),
+		transports:     make(map[string]http.RoundTripper),
 	}
 }
 
@@ -155,12 +265,41 @@ func (s *HTTPExecutor) Unmarshal(configJSON string) (any, error) {
 	return GenericUnmarshal[HTTPConfig](configJSON)
 }
 
+// ReachabilityAddress returns the host and port of the URL
+func (s *HTTPExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	return urlAddress(cfg.(*HTTPConfig).Url, map[string]string{"http": "80", "https": "443"})
+}
+
+// ClientCertificates returns the mTLS client certificate
+func (s *HTTPExecutor) ClientCertificates(cfg any) []ClientCertificate {
+	httpCfg := cfg.(*HTTPConfig)
+	if httpCfg.AuthMethod != "mtls" {
+		return nil
+	}
+	return []ClientCertificate{{Field: "tlsCert", PEM: httpCfg.TlsCert}}
+}
+
 func (s *HTTPExecutor) Validate(configJSON string) error {
 	cfg, err := s.Unmarshal(configJSON)
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*HTTPConfig))
+	httpCfg := cfg.(*HTTPConfig)
+	if err := GenericValidator(httpCfg); err != nil {
+		return err
+	}
+
+	if httpCfg.ExpectedJsonSchema != "" {
+		if _, err := compileJSONSchema(httpCfg.ExpectedJsonSchema); err != nil {
+			return err
+		}
+	}
+	return validateHTTPAssertions(httpCfg.Assertions)
+}
+
+// readsBody tells whether a check of the config reads the response body
+func readsBody(cfg *HTTPConfig) bool {
+	return cfg.Keyword != "" || cfg.ExpectedJsonSchema != "" || assertionsNeedBody(cfg.Assertions)
 }
 
 // Helper to check if status code matches accepted patterns
@@ -234,6 +373,39 @@ func buildProxyTransport(base *http.Transport, proxyModel *Proxy) http.RoundTrip
 	}
 }
 
+// ntlmTransport returns a persistent keep-alive transport for the given
+// TLS and proxy settings, creating it on first use.
+func (h *HTTPExecutor) ntlmTransport(ignoreTlsErrors bool, proxyModel *Proxy) *http.Transport {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "insecure=%t", ignoreTlsErrors)
+	if proxyModel != nil {
+		fmt.Fprintf(hash, "|proxy=%s,%s,%d,%t,%q,%q", proxyModel.Protocol, proxyModel.Host, proxyModel.Port,
+			proxyModel.Auth, proxyModel.Username, proxyModel.Password)
+	}
+	key := hex.EncodeToString(hash.Sum(nil))
+
+	h.ntlmMu.Lock()
+	defer h.ntlmMu.Unlock()
+
+	if transport, ok := h.ntlmTransports[key]; ok {
+		return transport
+	}
+
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		// NTLM is not supported over HTTP/2, keep the handshake on HTTP/1.1
+		TLSNextProto: make(map[string]func(string, *tls.Conn) http.RoundTripper),
+	}
+	if ignoreTlsErrors {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	buildProxyTransport(transport, proxyModel)
+
+	h.ntlmTransports[key] = transport
+	return transport
+}
+
 func setDefaultHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", "peekaping/"+version.Version)
 	req.Header.Set("Accept", "*/*")
@@ -246,6 +418,32 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 	}
 	cfg := cfgAny.(*HTTPConfig)
 
+	if cfg.TlsCertificateID != "" && cfg.AuthMethod == "mtls" {
+		cfg, err = h.withStoredCertificate(ctx, cfg, time.Now().UTC())
+		if err != nil {
+			return DownResult(err, time.Now().UTC(), time.Now().UTC())
+		}
+	}
+
+	if cfg.BodyRef != "" {
+		cfg, err = h.withStoredBody(ctx, cfg)
+		if err != nil {
+			return DownResult(err, time.Now().UTC(), time.Now().UTC())
+		}
+	}
+
+	if cfg.SamplesPerCheck <= 1 {
+		return h.execute(ctx, m, proxyModel, cfg)
+	}
+
+	samples := collectSamples(ctx, cfg.SamplesPerCheck, func() *Result {
+		return h.execute(ctx, m, proxyModel, cfg)
+	})
+	return summarizeSamples(samples, time.Duration(cfg.MaxMedianLatencyMs)*time.Millisecond)
+}
+
+// execute performs a single HTTP probe
+func (h *HTTPExecutor) execute(ctx context.Context, m *Monitor, proxyModel *Proxy, cfg *HTTPConfig) (result *Result) {
 	h.logger.Debugf("execute http cfg: %+v", cfg)
 
 	var bodyReader io.Reader
@@ -272,18 +470,31 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 
 	// Determine effective max redirects value
 	effectiveMaxRedirects := cfg.MaxRedirects
+	// redirects the client followed to reach the final response
+	redirectsFollowed := 0
 
 	checkRedirect := func(req *http.Request, via []*http.Request) error {
 		h.logger.Debugf("checkRedirect: %d redirects followed, max allowed: %d", len(via), effectiveMaxRedirects)
 		if effectiveMaxRedirects == 0 {
+			if cfg.ExpectedRedirectTo != "" {
+				// Stop at the redirect response so its Location can be asserted
+				return http.ErrUseLastResponse
+			}
 			return fmt.Errorf("redirects disabled: max_redirects set to 0")
 		}
 		if len(via) > effectiveMaxRedirects {
 			return fmt.Errorf("too many redirects: followed %d redirects, maximum allowed is %d", len(via), effectiveMaxRedirects)
 		}
+		redirectsFollowed = len(via)
 		return nil
 	}
 
+	// body checks decode the response themselves, the transport only
+	// handles gzip and only when it asked for it
+	if readsBody(cfg) && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptedBodyEncodings)
+	}
+
 	switch cfg.Encoding {
 	case "json":
 		req.Header.Set("Content-Type", "application/json")
@@ -295,39 +506,34 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 		req.Header.Set("Content-Type", "text/plain")
 	}
 
-	// --- PROXY LOGIC ---
+	// --- TRANSPORT ---
 
-	// Default transport with proxy if needed
-	baseTransport := &http.Transport{}
-
-	// Configure TLS settings if needed
-	if cfg.IgnoreTlsErrors {
-		if baseTransport.TLSClientConfig == nil {
-			baseTransport.TLSClientConfig = &tls.Config{}
+	var chainReport *CertChainReport
+	var transport http.RoundTripper
+	if cfg.AuthMethod == "ntlm" {
+		// NTLM authentication using github.com/Azure/go-ntlmssp
+		transport = &ntlmssp.Negotiator{
+			RoundTripper: h.ntlmTransport(cfg.IgnoreTlsErrors, proxyModel),
+		}
+	} else {
+		transport, err = h.transportFor(cfg, proxyModel, req.URL.Hostname(), &chainReport)
+		if err != nil {
+			return DownResult(err, time.Now().UTC(), time.Now().UTC())
 		}
-		baseTransport.TLSClientConfig.InsecureSkipVerify = true
 	}
 
-	transport := buildProxyTransport(baseTransport, proxyModel)
-
 	// Set timeout from monitor configuration
-	timeout := time.Duration(m.Timeout) * time.Second
+	client := &http.Client{
+		Transport:     transport,
+		Timeout:       time.Duration(m.Timeout) * time.Second,
+		CheckRedirect: checkRedirect,
+	}
 
 	// --- AUTHENTICATION LOGIC ---
 	switch cfg.AuthMethod {
 	case "basic":
 		req.SetBasicAuth(cfg.BasicAuthUser, cfg.BasicAuthPass)
 	case "ntlm":
-		// NTLM authentication using github.com/Azure/go-ntlmssp
-		ntlmTransport := ntlmssp.Negotiator{
-			RoundTripper: transport,
-		}
-		h.client = &http.Client{
-			Transport:     &ntlmTransport,
-			Timeout:       time.Duration(m.Timeout) * time.Second,
-			CheckRedirect: checkRedirect,
-		}
-
 		if cfg.AuthDomain != "" {
 			req.SetBasicAuth(cfg.AuthDomain+"\\"+cfg.BasicAuthUser, cfg.BasicAuthPass)
 		} else {
@@ -370,52 +576,63 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			return DownResult(fmt.Errorf("failed to parse oauth2 token response: %w", err), time.Now().UTC(), time.Now().UTC())
 		}
 		req.Header.Set("Authorization", "Bearer "+tokenData.AccessToken)
-	case "mtls":
-		cert, err := tls.X509KeyPair([]byte(cfg.TlsCert), []byte(cfg.TlsKey))
-		if err != nil {
-			return DownResult(fmt.Errorf("invalid mTLS cert/key: %w", err), time.Now().UTC(), time.Now().UTC())
-		}
-		caCertPool := x509.NewCertPool()
-		if ok := caCertPool.AppendCertsFromPEM([]byte(cfg.TlsCa)); !ok {
-			return DownResult(fmt.Errorf("invalid mTLS CA cert"), time.Now().UTC(), time.Now().UTC())
-		}
-		mtlsTransport := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				Certificates:       []tls.Certificate{cert},
-				RootCAs:            caCertPool,
-				InsecureSkipVerify: cfg.IgnoreTlsErrors,
-			},
-		}
-		mtlsTransportWithProxy := buildProxyTransport(mtlsTransport, proxyModel)
-		h.client = &http.Client{
-			Transport:     mtlsTransportWithProxy,
-			Timeout:       time.Duration(m.Timeout) * time.Second,
-			CheckRedirect: checkRedirect,
-		}
-	}
-
-	if cfg.AuthMethod != "mtls" && cfg.AuthMethod != "ntlm" {
-		h.client = &http.Client{
-			Timeout:       timeout,
-			CheckRedirect: checkRedirect,
-			Transport:     transport,
-		}
 	}
 
 	// Set user agent and accept headers
 
 	startTime := time.Now().UTC()
-	resp, err := h.client.Do(req)
+	resp, err := client.Do(req)
 	endTime := time.Now().UTC()
 
 	if err != nil {
 		h.logger.Infof("HTTP request failed: %s, %s", m.Name, err.Error())
 		return DownResult(err, startTime, endTime)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	h.logger.Infof("HTTP response status: %s, %d", m.Name, resp.StatusCode)
 
+	// every result from here on has a response, failed ones included
+	if headers := captureHeaders(resp.Header, cfg.CaptureHeaders); headers != nil {
+		defer func() {
+			if result != nil {
+				result.Headers = headers
+			}
+		}()
+	}
+
+	if err := checkHTTPVersion(resp, cfg.HTTPVersion); err != nil {
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("%d - %s", resp.StatusCode, err.Error()),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	negotiated, err := checkNegotiatedTLS(resp.TLS, cfg)
+	if err != nil {
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("%d - %s", resp.StatusCode, err.Error()),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	if cfg.ExpectedRedirectTo != "" {
+		return checkExpectedRedirect(resp, cfg, startTime, endTime)
+	}
+
+	if cfg.ExpectedRedirectCount != nil && redirectsFollowed != *cfg.ExpectedRedirectCount {
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("%d - expected %d redirects, followed %d", resp.StatusCode, *cfg.ExpectedRedirectCount, redirectsFollowed),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
 	if !isStatusAccepted(resp.StatusCode, cfg.AcceptedStatusCodes) {
 		return &Result{
 			Status:    shared.MonitorStatusDown,
@@ -425,9 +642,151 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 		}
 	}
 
+	message := fmt.Sprintf("%d - %s", resp.StatusCode, resp.Status)
+
+	// response body read for the body checks, nil when none needs it
+	var bodyData []byte
+
+	if readsBody(cfg) {
+		// a body that is never complete can still contain the keyword, but the
+		// schema and the json assertions need the whole document
+		wholeBody := cfg.ExpectedJsonSchema != "" || assertionsNeedBody(cfg.Assertions)
+		var done func([]byte) bool
+		if !wholeBody && !cfg.InvertKeyword {
+			keyword := []byte(cfg.Keyword)
+			done = func(data []byte) bool { return bytes.Contains(data, keyword) }
+		}
+
+		body := resp.Body
+		if !resp.Uncompressed {
+			body, err = decodeBody(resp.Body, resp.Header.Get("Content-Encoding"))
+			if err != nil {
+				return &Result{
+					Status:    shared.MonitorStatusDown,
+					Message:   fmt.Sprintf("%d - failed to read response body: %s", resp.StatusCode, err.Error()),
+					StartTime: startTime,
+					EndTime:   time.Now().UTC(),
+				}
+			}
+		}
+
+		readDeadline := time.Duration(cfg.ReadDeadlineMs) * time.Millisecond
+		data, err := readBody(body, maxHTTPBodySize, readDeadline, done)
+		if err != nil && (wholeBody || !errors.Is(err, errReadDeadline)) {
+			h.logger.Infof("HTTP response body read failed: %s, %s", m.Name, err.Error())
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   fmt.Sprintf("%d - failed to read response body: %s", resp.StatusCode, err.Error()),
+				StartTime: startTime,
+				EndTime:   time.Now().UTC(),
+			}
+		}
+
+		if cfg.Keyword != "" {
+			found := bytes.Contains(data, []byte(cfg.Keyword))
+			if found == cfg.InvertKeyword {
+				return &Result{
+					Status:    shared.MonitorStatusDown,
+					Message:   fmt.Sprintf("%d - keyword [%s] is %s in [%s]", resp.StatusCode, cfg.Keyword, map[bool]string{true: "present", false: "not"}[found], truncateBody(data)),
+					StartTime: startTime,
+					EndTime:   time.Now().UTC(),
+				}
+			}
+			message = fmt.Sprintf("%s | keyword [%s] %s found", message, cfg.Keyword, map[bool]string{true: "is", false: "not"}[found])
+		}
+
+		if cfg.ExpectedJsonSchema != "" {
+			schema, err := compileJSONSchema(cfg.ExpectedJsonSchema)
+			if err != nil {
+				return DownResult(err, startTime, endTime)
+			}
+			if err := validateJSONSchemaBody(schema, bytes.NewReader(data)); err != nil {
+				h.logger.Infof("HTTP response failed schema validation: %s, %s", m.Name, err.Error())
+				return &Result{
+					Status:    shared.MonitorStatusDown,
+					Message:   fmt.Sprintf("%d - %s", resp.StatusCode, err.Error()),
+					StartTime: startTime,
+					EndTime:   endTime,
+				}
+			}
+			message = fmt.Sprintf("%s | response matches json schema", message)
+		}
+		bodyData = data
+	}
+
+	if len(cfg.Assertions) > 0 {
+		if err := checkHTTPAssertions(resp, bodyData, cfg.Assertions); err != nil {
+			h.logger.Infof("HTTP response failed assertion: %s, %s", m.Name, err.Error())
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   fmt.Sprintf("%d - %s", resp.StatusCode, err.Error()),
+				StartTime: startTime,
+				EndTime:   endTime,
+			}
+		}
+		message = fmt.Sprintf("%s | %d assertions passed", message, len(cfg.Assertions))
+	}
+
+	if cfg.ExpectedRedirectCount != nil {
+		message = fmt.Sprintf("%s | followed %d redirects", message, redirectsFollowed)
+	}
+	if chainReport != nil {
+		message = fmt.Sprintf("%s | %s", message, chainReport.Summary())
+	}
+	if negotiated != "" {
+		message = fmt.Sprintf("%s | %s", message, negotiated)
+	}
+	if cfg.HTTPVersion == "http1" || cfg.HTTPVersion == "http2" {
+		message = fmt.Sprintf("%s | %s", message, resp.Proto)
+	}
+
 	return &Result{
 		Status:    shared.MonitorStatusUp,
-		Message:   fmt.Sprintf("%d - %s", resp.StatusCode, resp.Status),
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// checkExpectedRedirect asserts that the response is a redirect whose Location
+// matches the configured target, either exactly or by prefix
+func checkExpectedRedirect(resp *http.Response, cfg *HTTPConfig, startTime, endTime time.Time) *Result {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Expected redirect to %s, but got status: %d", cfg.ExpectedRedirectTo, resp.StatusCode),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	location := resp.Header.Get("Location")
+
+	// Accept both the raw header value and its resolved absolute form so
+	// relative Location headers can be matched against absolute targets
+	candidates := []string{location}
+	if resolved, err := resp.Location(); err == nil {
+		candidates = append(candidates, resolved.String())
+	}
+
+	for _, candidate := range candidates {
+		matched := candidate == cfg.ExpectedRedirectTo
+		if cfg.ExpectedRedirectMatch == "prefix" {
+			matched = strings.HasPrefix(candidate, cfg.ExpectedRedirectTo)
+		}
+		if matched {
+			return &Result{
+				Status:    shared.MonitorStatusUp,
+				Message:   fmt.Sprintf("%d - redirected to %s", resp.StatusCode, location),
+				StartTime: startTime,
+				EndTime:   endTime,
+			}
+		}
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusDown,
+		Message:   fmt.Sprintf("Redirect location mismatch: expected %s, got %s", cfg.ExpectedRedirectTo, location),
 		StartTime: startTime,
 		EndTime:   endTime,
 	}
@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/blues/jsonata-go"
+)
+
+// HTTPAssertion is a check of the response, evaluated in order after the
+// accepted status codes. Target is the header name or the JSONata expression
+// of a json_path assertion, unused for status.
+type HTTPAssertion struct {
+	Type     string `json:"type" validate:"required,oneof=status header json_path" example:"json_path"`
+	Target   string `json:"target,omitempty" example:"$.status"`
+	Operator string `json:"operator" validate:"required,oneof=eq ne contains gt lt ge le" example:"eq"`
+	Value    string `json:"value" example:"ok"`
+}
+
+// httpAssertionOperators lists the operators each assertion type accepts
+var httpAssertionOperators = map[string][]string{
+	"status":    {"eq", "ne", "gt", "lt", "ge", "le"},
+	"header":    {"eq", "ne", "contains"},
+	"json_path": {"eq", "ne", "contains", "gt", "lt", "ge", "le"},
+}
+
+func (a HTTPAssertion) String() string {
+	if a.Type == "status" {
+		return fmt.Sprintf("status %s %s", a.Operator, a.Value)
+	}
+	return fmt.Sprintf("%s %s %s %q", a.Type, a.Target, a.Operator, a.Value)
+}
+
+// validateHTTPAssertions checks the operator, target and value of every
+// assertion, the errors name the assertion by its position
+func validateHTTPAssertions(assertions []HTTPAssertion) error {
+	for i, assertion := range assertions {
+		allowed := httpAssertionOperators[assertion.Type]
+		valid := false
+		for _, operator := range allowed {
+			valid = valid || operator == assertion.Operator
+		}
+		if !valid {
+			return fmt.Errorf("assertion %d: operator %s is not supported for %s, use one of %s", i+1, assertion.Operator, assertion.Type, strings.Join(allowed, ", "))
+		}
+
+		switch assertion.Type {
+		case "status":
+			if _, err := strconv.Atoi(assertion.Value); err != nil {
+				return fmt.Errorf("assertion %d: status value must be a number", i+1)
+			}
+		case "header":
+			if strings.TrimSpace(assertion.Target) == "" {
+				return fmt.Errorf("assertion %d: header name is required", i+1)
+			}
+		case "json_path":
+			if strings.TrimSpace(assertion.Target) == "" {
+				return fmt.Errorf("assertion %d: json path is required", i+1)
+			}
+			if _, err := jsonata.Compile(assertion.Target); err != nil {
+				return fmt.Errorf("assertion %d: invalid JSONata expression: %w", i+1, err)
+			}
+		}
+
+		if isNumericOperator(assertion.Operator) {
+			if _, err := strconv.ParseFloat(assertion.Value, 64); err != nil {
+				return fmt.Errorf("assertion %d: %s needs a numeric value", i+1, assertion.Operator)
+			}
+		}
+	}
+	return nil
+}
+
+func isNumericOperator(operator string) bool {
+	return operator == "gt" || operator == "lt" || operator == "ge" || operator == "le"
+}
+
+// assertionsNeedBody tells whether an assertion reads the response body
+func assertionsNeedBody(assertions []HTTPAssertion) bool {
+	for _, assertion := range assertions {
+		if assertion.Type == "json_path" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHTTPAssertions evaluates the assertions in order and reports the first
+// failing one
+func checkHTTPAssertions(resp *http.Response, body []byte, assertions []HTTPAssertion) error {
+	var document any
+	parsed := false
+
+	for i, assertion := range assertions {
+		var actual string
+		switch assertion.Type {
+		case "status":
+			actual = strconv.Itoa(resp.StatusCode)
+		case "header":
+			actual = strings.Join(resp.Header.Values(assertion.Target), ", ")
+		case "json_path":
+			if !parsed {
+				if err := json.Unmarshal(body, &document); err != nil {
+					return fmt.Errorf("assertion %d failed: %s: response is not valid JSON: %v", i+1, assertion, err)
+				}
+				parsed = true
+			}
+			value, err := evaluateJSONPath(assertion.Target, document)
+			if err != nil {
+				return fmt.Errorf("assertion %d failed: %s: %v", i+1, assertion, err)
+			}
+			if list, ok := value.([]any); ok && assertion.Operator == "contains" {
+				if !listContains(list, assertion.Value) {
+					return fmt.Errorf("assertion %d failed: %s, got %s", i+1, assertion, truncateMessage(formatJSONValue(value), 200))
+				}
+				continue
+			}
+			actual = formatJSONValue(value)
+		}
+
+		var passed bool
+		if assertion.Operator == "contains" {
+			passed = strings.Contains(actual, assertion.Value)
+		} else {
+			passed = compareValues(actual, assertion.Operator, assertion.Value)
+		}
+		if !passed {
+			return fmt.Errorf("assertion %d failed: %s, got %q", i+1, assertion, truncateMessage(actual, 200))
+		}
+	}
+	return nil
+}
+
+func evaluateJSONPath(expression string, document any) (any, error) {
+	expr, err := jsonata.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONata expression: %w", err)
+	}
+	value, err := expr.Eval(document)
+	if errors.Is(err, jsonata.ErrUndefined) {
+		return nil, fmt.Errorf("no value at %s", expression)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JSONata evaluation failed: %w", err)
+	}
+	return value, nil
+}
+
+// formatJSONValue renders strings and numbers as they read, objects and
+// arrays as JSON
+func formatJSONValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+func listContains(list []any, expected string) bool {
+	for _, item := range list {
+		if formatJSONValue(item) == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"peekaping/src/modules/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateHTTPAssertions(t *testing.T) {
+	tests := []struct {
+		name        string
+		assertion   HTTPAssertion
+		expectError bool
+	}{
+		{"status eq", HTTPAssertion{Type: "status", Operator: "eq", Value: "200"}, false},
+		{"status lt", HTTPAssertion{Type: "status", Operator: "lt", Value: "300"}, false},
+		{"status contains", HTTPAssertion{Type: "status", Operator: "contains", Value: "20"}, true},
+		{"status not a number", HTTPAssertion{Type: "status", Operator: "eq", Value: "ok"}, true},
+		{"header eq", HTTPAssertion{Type: "header", Target: "Content-Type", Operator: "contains", Value: "json"}, false},
+		{"header gt", HTTPAssertion{Type: "header", Target: "Age", Operator: "gt", Value: "10"}, true},
+		{"header without name", HTTPAssertion{Type: "header", Operator: "eq", Value: "json"}, true},
+		{"json path gt", HTTPAssertion{Type: "json_path", Target: "$.uptime", Operator: "gt", Value: "60"}, false},
+		{"json path gt not a number", HTTPAssertion{Type: "json_path", Target: "$.uptime", Operator: "gt", Value: "long"}, true},
+		{"json path without expression", HTTPAssertion{Type: "json_path", Operator: "eq", Value: "ok"}, true},
+		{"json path invalid expression", HTTPAssertion{Type: "json_path", Target: "$.status[", Operator: "eq", Value: "ok"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHTTPAssertions([]HTTPAssertion{tt.assertion})
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPExecutor_Validate_Assertions(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	config := func(assertions string) string {
+		return fmt.Sprintf(`{"url": "https://example.com", "method": "GET", "encoding": "json", "accepted_statuscodes": ["2XX"], "authMethod": "none", "assertions": %s}`, assertions)
+	}
+
+	assert.NoError(t, executor.Validate(config(`[{"type": "status", "operator": "eq", "value": "200"}, {"type": "json_path", "target": "$.status", "operator": "eq", "value": "ok"}]`)))
+	assert.Error(t, executor.Validate(config(`[{"type": "cookie", "operator": "eq", "value": "1"}]`)))
+	assert.Error(t, executor.Validate(config(`[{"type": "status", "operator": "matches", "value": "200"}]`)))
+	assert.ErrorContains(t, executor.Validate(config(`[{"type": "status", "operator": "eq", "value": "200"}, {"type": "header", "target": "X-Cache", "operator": "le", "value": "1"}]`)), "assertion 2")
+}
+
+func TestHTTPExecutor_Execute_Assertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Region", "eu-west-1")
+		if r.URL.Path == "/text" {
+			_, _ = w.Write([]byte("OK"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "ok", "uptime": 3600, "checks": ["db", "cache"], "version": {"major": 2}}`))
+	}))
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	monitor := func(path, assertions string) *Monitor {
+		return &Monitor{
+			ID:      "monitor1",
+			Type:    "http",
+			Name:    "Assertions",
+			Timeout: 5,
+			Config: fmt.Sprintf(`{
+				"url": "%s%s",
+				"method": "GET",
+				"encoding": "json",
+				"accepted_statuscodes": ["2XX"],
+				"authMethod": "none",
+				"assertions": %s
+			}`, server.URL, path, assertions),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		assertions     string
+		expectedStatus shared.MonitorStatus
+		expectedMsg    string
+	}{
+		{"all pass", "/", `[
+			{"type": "status", "operator": "eq", "value": "200"},
+			{"type": "header", "target": "content-type", "operator": "contains", "value": "application/json"},
+			{"type": "json_path", "target": "$.status", "operator": "eq", "value": "ok"},
+			{"type": "json_path", "target": "$.uptime", "operator": "ge", "value": "3600"},
+			{"type": "json_path", "target": "$.checks", "operator": "contains", "value": "cache"},
+			{"type": "json_path", "target": "$.version.major", "operator": "lt", "value": "3"}
+		]`, shared.MonitorStatusUp, "6 assertions passed"},
+		{"status fails", "/", `[{"type": "status", "operator": "ne", "value": "200"}]`, shared.MonitorStatusDown, "assertion 1 failed: status ne 200"},
+		{"first failing assertion reported", "/", `[
+			{"type": "header", "target": "X-Region", "operator": "eq", "value": "eu-west-1"},
+			{"type": "json_path", "target": "$.uptime", "operator": "gt", "value": "7200"},
+			{"type": "json_path", "target": "$.status", "operator": "eq", "value": "degraded"}
+		]`, shared.MonitorStatusDown, `assertion 2 failed: json_path $.uptime gt "7200", got "3600"`},
+		{"missing json value", "/", `[{"type": "json_path", "target": "$.missing", "operator": "eq", "value": "1"}]`, shared.MonitorStatusDown, "no value at $.missing"},
+		{"list without item", "/", `[{"type": "json_path", "target": "$.checks", "operator": "contains", "value": "queue"}]`, shared.MonitorStatusDown, `got ["db","cache"]`},
+		{"body not json", "/text", `[{"type": "json_path", "target": "$.status", "operator": "eq", "value": "ok"}]`, shared.MonitorStatusDown, "response is not valid JSON"},
+		{"status and headers without json", "/text", `[{"type": "header", "target": "X-Missing", "operator": "ne", "value": "1"}]`, shared.MonitorStatusUp, "1 assertions passed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := monitor(tt.path, tt.assertions)
+			require.NoError(t, executor.Validate(m.Config))
+			result := executor.Execute(context.Background(), m, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// maxHTTPBodySize limits how much of a response body is read for body checks
+const maxHTTPBodySize = maxJSONSchemaBodySize
+
+var errReadDeadline = errors.New("response body read deadline exceeded")
+
+const (
+	// maxDrainBytes bounds the unread body discarded to reuse the connection,
+	// the connection of a larger body is closed instead
+	maxDrainBytes = 256 * 1024
+	// drainTimeout bounds how long a slow body is drained
+	drainTimeout = 500 * time.Millisecond
+)
+
+// drainAndClose discards what is left of the body before closing it. Only a
+// body read to the end hands its keep-alive connection back to the pool.
+func drainAndClose(body io.ReadCloser) {
+	timer := time.AfterFunc(drainTimeout, func() { body.Close() })
+	defer timer.Stop()
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	body.Close()
+}
+
+// readBody reads the response body until it ends, more than limit bytes were
+// read, done reports the data read so far is enough or the deadline passes.
+// A zero deadline leaves the read bounded by the request timeout only. When
+// the deadline cuts the read short the data read so far is returned together
+// with errReadDeadline.
+func readBody(body io.ReadCloser, limit int, deadline time.Duration, done func([]byte) bool) ([]byte, error) {
+	var expired atomic.Bool
+	if deadline > 0 {
+		// closing the body unblocks a pending read on a connection that keeps streaming
+		timer := time.AfterFunc(deadline, func() {
+			expired.Store(true)
+			body.Close()
+		})
+		defer timer.Stop()
+	}
+
+	var buf bytes.Buffer
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(chunk)
+		if n > 0 {
+			buf.Write(chunk[:n])
+			if buf.Len() > limit || (done != nil && done(buf.Bytes())) {
+				return buf.Bytes(), nil
+			}
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			if expired.Load() {
+				return buf.Bytes(), errReadDeadline
+			}
+			return buf.Bytes(), err
+		}
+	}
+}
+
+// acceptedBodyEncodings are the content encodings decodeBody understands
+const acceptedBodyEncodings = "gzip, deflate, br"
+
+// decodedBody reads the decoded response body, closing it also closes the
+// response body so a pending read on the connection is unblocked. The
+// decoders read the first bytes of the body as they are created, so they are
+// created on the first read, within the read deadline of readBody.
+type decodedBody struct {
+	body      io.ReadCloser
+	encodings []string
+	reader    io.Reader
+	err       error
+}
+
+func (d *decodedBody) Read(p []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.reader, d.err = newBodyDecoder(d.body, d.encodings)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.reader.Read(p)
+}
+
+func (d *decodedBody) Close() error {
+	return d.body.Close()
+}
+
+// decodeBody undoes the Content-Encoding of a response body. Encodings are
+// listed in the order they were applied and are undone in reverse. The
+// decoded size is bounded by readBody, compressed bodies cannot inflate past
+// its limit. An unsupported encoding fails at once, a malformed body on the
+// first read.
+func decodeBody(body io.ReadCloser, contentEncoding string) (io.ReadCloser, error) {
+	var encodings []string
+	for _, encoding := range strings.Split(contentEncoding, ",") {
+		switch encoding = strings.ToLower(strings.TrimSpace(encoding)); encoding {
+		case "", "identity":
+		case "gzip", "x-gzip", "deflate", "br":
+			encodings = append(encodings, encoding)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+		}
+	}
+	if len(encodings) == 0 {
+		return body, nil
+	}
+	return &decodedBody{body: body, encodings: encodings}, nil
+}
+
+// newBodyDecoder stacks the decoders of the encodings, undoing the last
+// applied first
+func newBodyDecoder(body io.Reader, encodings []string) (io.Reader, error) {
+	reader := body
+	for i := len(encodings) - 1; i >= 0; i-- {
+		switch encodings[i] {
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gzip body: %w", err)
+			}
+			reader = gz
+		case "deflate":
+			deflated, err := newDeflateReader(reader)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deflate body: %w", err)
+			}
+			reader = deflated
+		case "br":
+			reader = brotli.NewReader(reader)
+		}
+	}
+	return reader, nil
+}
+
+// newDeflateReader reads a deflate body. The encoding is zlib wrapped
+// deflate, but some servers send raw deflate data.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+// truncateBody shortens a response body for use in a check message
+func truncateBody(data []byte) string {
+	const maxLen = 50
+	if len(data) > maxLen {
+		return string(data[:maxLen-3]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/shared"
+)
+
+// BlobStore looks up the stored blobs monitors send as request body
+type BlobStore interface {
+	FindByID(ctx context.Context, id string) (*shared.Blob, error)
+}
+
+// ReferenceValidator is implemented by executors whose config references
+// stored entities. ValidateReferences reports the ones that do not exist.
+type ReferenceValidator interface {
+	ValidateReferences(ctx context.Context, cfg any) error
+}
+
+func (h *HTTPExecutor) loadBlob(ctx context.Context, id string) (*shared.Blob, error) {
+	if h.blobs == nil {
+		return nil, fmt.Errorf("stored blobs are not available")
+	}
+	stored, err := h.blobs.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blob %s: %w", id, err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("blob %s not found", id)
+	}
+	return stored, nil
+}
+
+// withStoredBody returns a copy of the config carrying the content of its
+// stored body, loaded once for every sample of the check
+func (h *HTTPExecutor) withStoredBody(ctx context.Context, cfg *HTTPConfig) (*HTTPConfig, error) {
+	stored, err := h.loadBlob(ctx, cfg.BodyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *cfg
+	resolved.Body = stored.Content
+	return &resolved, nil
+}
+
+func (h *HTTPExecutor) ValidateReferences(ctx context.Context, cfg any) error {
+	httpCfg := cfg.(*HTTPConfig)
+	if httpCfg.BodyRef == "" {
+		return nil
+	}
+	_, err := h.loadBlob(ctx, httpCfg.BodyRef)
+	return err
+}
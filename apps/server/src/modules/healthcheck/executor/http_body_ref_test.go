@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeBlobStore map[string]*shared.Blob
+
+func (s fakeBlobStore) FindByID(ctx context.Context, id string) (*shared.Blob, error) {
+	return s[id], nil
+}
+
+func TestHTTPExecutor_Execute_BodyRef(t *testing.T) {
+	payload := `{"orders":[` + strings.Repeat(`{"id":1},`, 10000) + `{"id":2}]}`
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	executor.blobs = fakeBlobStore{"orders": {ID: "orders", Name: "orders", Content: payload}}
+
+	newMonitor := func(bodyRef string) *Monitor {
+		return &Monitor{ID: "monitor1", Type: "http", Name: "Body ref", Timeout: 5, Config: httpStreamingConfig(t, server.URL, map[string]any{
+			"method":            "POST",
+			"body_ref":          bodyRef,
+			"samples_per_check": 2,
+		})}
+	}
+
+	result := executor.Execute(context.Background(), newMonitor("orders"), nil)
+	require.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, []string{payload, payload}, received, "expected every sample to send the stored body")
+
+	result = executor.Execute(context.Background(), newMonitor("missing"), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "blob missing not found")
+}
+
+func TestExecutorRegistry_ValidateConfig_BodyRef(t *testing.T) {
+	registry := NewExecutorRegistry(zap.NewNop().Sugar(), nil)
+	registry.registry["http"].(*HTTPExecutor).blobs = fakeBlobStore{"orders": {ID: "orders", Name: "orders", Content: "{}"}}
+
+	config := func(extra map[string]any) string {
+		return httpStreamingConfig(t, "https://example.com", extra)
+	}
+
+	assert.NoError(t, registry.ValidateConfig("http", config(map[string]any{"body_ref": "orders"})))
+	assert.ErrorContains(t, registry.ValidateConfig("http", config(map[string]any{"body_ref": "missing"})), "blob missing not found")
+	assert.Error(t, registry.ValidateConfig("http", config(map[string]any{"body_ref": "orders", "body": "{}"})), "expected body and body_ref to exclude each other")
+	assert.NoError(t, registry.ValidateConfig("http", config(nil)))
+}
@@ -0,0 +1,366 @@
+package executor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newStreamingServer returns a server that sends the headers and the prefix,
+// then keeps streaming chunks until the client goes away
+func newStreamingServer(prefix string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, prefix)
+		flusher.Flush()
+
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, "data: tick\n")
+				flusher.Flush()
+			}
+		}
+	}))
+}
+
+func httpStreamingConfig(t *testing.T, url string, extra map[string]any) string {
+	config := map[string]any{
+		"url":                  url,
+		"method":               "GET",
+		"encoding":             "json",
+		"accepted_statuscodes": []string{"2XX"},
+		"authMethod":           "none",
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	configJSON, err := json.Marshal(config)
+	require.NoError(t, err)
+	return string(configJSON)
+}
+
+func TestHTTPExecutor_Execute_StreamingResponse(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	server := newStreamingServer("event: ready\n")
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		extra          map[string]any
+		expectedStatus shared.MonitorStatus
+		msgContains    string
+	}{
+		{
+			name:           "status only check does not wait for the body",
+			extra:          map[string]any{},
+			expectedStatus: shared.MonitorStatusUp,
+			msgContains:    "200",
+		},
+		{
+			name:           "keyword found in the streamed body",
+			extra:          map[string]any{"keyword": "ready", "read_deadline_ms": 2000},
+			expectedStatus: shared.MonitorStatusUp,
+			msgContains:    "keyword [ready] is found",
+		},
+		{
+			name:           "keyword found without read deadline",
+			extra:          map[string]any{"keyword": "tick"},
+			expectedStatus: shared.MonitorStatusUp,
+			msgContains:    "keyword [tick] is found",
+		},
+		{
+			name:           "keyword missing when the read deadline passes",
+			extra:          map[string]any{"keyword": "healthy", "read_deadline_ms": 200},
+			expectedStatus: shared.MonitorStatusDown,
+			msgContains:    "keyword [healthy] is not in",
+		},
+		{
+			name:           "inverted keyword absent until the read deadline",
+			extra:          map[string]any{"keyword": "error", "invert_keyword": true, "read_deadline_ms": 200},
+			expectedStatus: shared.MonitorStatusUp,
+			msgContains:    "keyword [error] not found",
+		},
+		{
+			name:           "json schema body never completes",
+			extra:          map[string]any{"expected_json_schema": `{"type": "object"}`, "read_deadline_ms": 200},
+			expectedStatus: shared.MonitorStatusDown,
+			msgContains:    "read deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Streaming Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   httpStreamingConfig(t, server.URL, tt.extra),
+			}
+
+			start := time.Now()
+			result := executor.Execute(context.Background(), monitor, nil)
+			elapsed := time.Since(start)
+
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.msgContains)
+			assert.Less(t, elapsed, 2*time.Second, "the check must not wait for the monitor timeout")
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_KeywordCompleteBody(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		extra          map[string]any
+		expectedStatus shared.MonitorStatus
+	}{
+		{name: "keyword present", extra: map[string]any{"keyword": `"ok"`}, expectedStatus: shared.MonitorStatusUp},
+		{name: "keyword absent", extra: map[string]any{"keyword": "degraded"}, expectedStatus: shared.MonitorStatusDown},
+		{name: "inverted keyword present", extra: map[string]any{"keyword": "ok", "invert_keyword": true}, expectedStatus: shared.MonitorStatusDown},
+		{name: "inverted keyword absent", extra: map[string]any{"keyword": "degraded", "invert_keyword": true}, expectedStatus: shared.MonitorStatusUp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Keyword Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   httpStreamingConfig(t, server.URL, tt.extra),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+		})
+	}
+}
+
+func TestHTTPExecutor_Validate_ReadDeadline(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	assert.NoError(t, executor.Validate(httpStreamingConfig(t, "https://example.com/events", map[string]any{"read_deadline_ms": 500})))
+	assert.Error(t, executor.Validate(httpStreamingConfig(t, "https://example.com/events", map[string]any{"read_deadline_ms": -1})))
+}
+
+func compressBody(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+		w = fw
+	case "br":
+		w = brotli.NewWriter(&buf)
+	}
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeBody(t *testing.T) {
+	plain := []byte(`{"status":"healthy","checks":["db","cache"]}`)
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			header := strings.TrimPrefix(encoding, "raw-")
+			body, err := decodeBody(io.NopCloser(bytes.NewReader(compressBody(t, encoding, plain))), header)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, plain, decoded)
+		})
+	}
+
+	// encodings are undone in reverse order of the header
+	twice := compressBody(t, "br", compressBody(t, "gzip", plain))
+	body, err := decodeBody(io.NopCloser(bytes.NewReader(twice)), "gzip, br")
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, plain, decoded)
+
+	body, err = decodeBody(io.NopCloser(bytes.NewReader(plain)), "")
+	require.NoError(t, err)
+	decoded, _ = io.ReadAll(body)
+	assert.Equal(t, plain, decoded)
+
+	_, err = decodeBody(io.NopCloser(bytes.NewReader(plain)), "compress")
+	assert.ErrorContains(t, err, "unsupported content encoding")
+
+	body, err = decodeBody(io.NopCloser(bytes.NewReader(plain)), "gzip")
+	require.NoError(t, err)
+	_, err = io.ReadAll(body)
+	assert.ErrorContains(t, err, "invalid gzip body")
+}
+
+func TestHTTPExecutor_Execute_CompressedBody(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	plain := []byte(`{"status":"healthy"}`)
+
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Contains(t, r.Header.Get("Accept-Encoding"), encoding)
+				w.Header().Set("Content-Encoding", encoding)
+				w.Write(compressBody(t, encoding, plain))
+			}))
+			defer server.Close()
+
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "http",
+				Timeout: 5,
+				Config: httpStreamingConfig(t, server.URL, map[string]any{
+					"keyword":              "healthy",
+					"expected_json_schema": `{"type":"object","required":["status"]}`,
+				}),
+			}, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+			assert.Contains(t, result.Message, "keyword [healthy] is found")
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_CompressedBodyStalls(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// headers sent, the compressed stream never starts
+				w.Header().Set("Content-Encoding", encoding)
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer server.Close()
+			defer close(release)
+
+			start := time.Now()
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "http",
+				Timeout: 5,
+				Config:  httpStreamingConfig(t, server.URL, map[string]any{"keyword": "healthy", "read_deadline_ms": 200}),
+			}, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, shared.MonitorStatusDown, result.Status, result.Message)
+			assert.Less(t, time.Since(start), 2*time.Second, "the read deadline must bound the decoder")
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_CompressedBodyIsLimited(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	// a small gzip body inflating to far more than the read limit
+	bomb := compressBody(t, "gzip", bytes.Repeat([]byte{'a'}, 64*maxHTTPBodySize))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb)
+	}))
+	defer server.Close()
+
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "http",
+		Timeout: 5,
+		Config:  httpStreamingConfig(t, server.URL, map[string]any{"keyword": "healthy"}),
+	}, nil)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "keyword [healthy] is not in [aaaa")
+}
+
+func TestHTTPExecutor_Execute_ReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	// the end of the body arrives late, after the check has its status
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 16*1024))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("end"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	m := &Monitor{Type: "http", Name: "Reuse", Timeout: 5, Config: httpStreamingConfig(t, server.URL, nil)}
+	for i := 0; i < 3; i++ {
+		result := executor.Execute(context.Background(), m, nil)
+		require.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	}
+	assert.Equal(t, int32(1), connections.Load(), "expected the unread bodies to be drained so the connection is reused")
+}
+
+func TestDrainAndClose_LargeBody(t *testing.T) {
+	body := &countingBody{Reader: bytes.NewReader(make([]byte, 4*maxDrainBytes))}
+	drainAndClose(body)
+	assert.Equal(t, int64(maxDrainBytes), body.read, "expected the drain to stop at the bound")
+	assert.True(t, body.closed)
+}
+
+type countingBody struct {
+	io.Reader
+	read   int64
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return nil
+}
@@ -0,0 +1,30 @@
+package executor
+
+import (
+	"net/http"
+	"strings"
+)
+
+// maxCapturedHeaderValue bounds the size of every captured header value
+const maxCapturedHeaderValue = 256
+
+// captureHeaders picks the named headers from the response, repeated headers
+// are joined like a single header line. It returns nil when none is present.
+func captureHeaders(header http.Header, names []string) map[string]string {
+	var captured map[string]string
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if len(value) > maxCapturedHeaderValue {
+			value = strings.ToValidUTF8(value[:maxCapturedHeaderValue], "") + "..."
+		}
+		if captured == nil {
+			captured = make(map[string]string)
+		}
+		captured[http.CanonicalHeaderKey(name)] = value
+	}
+	return captured
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"peekaping/src/modules/shared"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCaptureHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Server", "nginx")
+	header.Add("X-Cache", "MISS")
+	header.Add("X-Cache", "HIT")
+	header.Set("X-Long", strings.Repeat("a", maxCapturedHeaderValue+10))
+
+	captured := captureHeaders(header, []string{"server", "X-Cache", "X-Long", "X-Missing"})
+	assert.Equal(t, "nginx", captured["Server"], "names are canonicalized")
+	assert.Equal(t, "MISS, HIT", captured["X-Cache"])
+	assert.Equal(t, strings.Repeat("a", maxCapturedHeaderValue)+"...", captured["X-Long"])
+	assert.NotContains(t, captured, "X-Missing")
+
+	assert.Nil(t, captureHeaders(header, nil))
+	assert.Nil(t, captureHeaders(header, []string{"X-Missing"}))
+}
+
+func TestHTTPExecutor_Execute_CaptureHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test-server")
+		w.Header().Set("X-Cache", "HIT")
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	monitor := func(path, extra string) *Monitor {
+		return &Monitor{
+			ID:      "monitor1",
+			Type:    "http",
+			Name:    "Headers",
+			Timeout: 5,
+			Config: fmt.Sprintf(`{
+				"url": "%s%s",
+				"method": "GET",
+				"encoding": "json",
+				"accepted_statuscodes": ["2XX"],
+				"authMethod": "none"
+				%s
+			}`, server.URL, path, extra),
+		}
+	}
+
+	result := executor.Execute(context.Background(), monitor("/", `, "capture_headers": ["Server", "X-Cache"]`), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, map[string]string{"Server": "test-server", "X-Cache": "HIT"}, result.Headers)
+
+	result = executor.Execute(context.Background(), monitor("/down", `, "capture_headers": ["X-Cache"]`), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, map[string]string{"X-Cache": "HIT"}, result.Headers, "failed checks keep the headers for debugging")
+
+	result = executor.Execute(context.Background(), monitor("/", `, "capture_headers": ["X-Cache"], "samples_per_check": 3`), nil)
+	assert.Equal(t, map[string]string{"X-Cache": "HIT"}, result.Headers, "sampled checks report the headers of the median sample")
+
+	result = executor.Execute(context.Background(), monitor("/", ``), nil)
+	assert.Nil(t, result.Headers, "nothing is captured unless configured")
+
+	assert.Error(t, executor.Validate(monitor("/", `, "capture_headers": [""]`).Config))
+	tooMany := `"H0"` + strings.Repeat(`, "H"`, 20)
+	assert.Error(t, executor.Validate(monitor("/", `, "capture_headers": [`+tooMany+`]`).Config))
+}
@@ -6,6 +6,8 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -14,6 +16,7 @@ import (
 	"peekaping/src/modules/shared"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -967,6 +970,125 @@ func TestHTTPExecutor_Execute_Proxy(t *testing.T) {
 	assert.Contains(t, result.Message, "connection refused")
 }
 
+// ntlmChallengeMessage builds a minimal NTLMv2 challenge (type 2) message
+func ntlmChallengeMessage() []byte {
+	msg := make([]byte, 48)
+	copy(msg[0:8], "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:12], 2)
+	// NEGOTIATE_UNICODE | NEGOTIATE_NTLM
+	binary.LittleEndian.PutUint32(msg[20:24], 0x00000201)
+	copy(msg[24:32], "12345678")
+	return msg
+}
+
+func TestHTTPExecutor_Execute_NTLM_ThroughProxy(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	var mu sync.Mutex
+	negotiatedOn := map[string]bool{}
+	proxied := 0
+
+	// The test server plays both the HTTP proxy and the NTLM protected origin.
+	// It only accepts the authenticate message on the connection that sent the
+	// negotiate message, like a real NTLM endpoint.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.IsAbs() {
+			proxied++
+		}
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "NTLM ") {
+			w.Header().Set("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		msg, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "NTLM "))
+		if err != nil || len(msg) < 12 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch binary.LittleEndian.Uint32(msg[8:12]) {
+		case 1:
+			negotiatedOn[r.RemoteAddr] = true
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(ntlmChallengeMessage()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			if !negotiatedOn[r.RemoteAddr] {
+				w.Header().Set("WWW-Authenticate", "NTLM")
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			delete(negotiatedOn, r.RemoteAddr)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	host, portStr, _ := strings.Cut(strings.TrimPrefix(server.URL, "http://"), ":")
+	port, _ := strconv.Atoi(portStr)
+
+	proxy := &Proxy{
+		ID:       "proxy1",
+		Host:     host,
+		Port:     port,
+		Protocol: "http",
+	}
+
+	monitor := &Monitor{
+		ID:       "monitor1",
+		Type:     "http",
+		Name:     "Test Monitor",
+		Interval: 30,
+		Timeout:  5,
+		Config: `{
+			"url": "http://ntlm.example.test/secure",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "ntlm",
+			"basic_auth_user": "user",
+			"basic_auth_pass": "pass",
+			"authDomain": "DOMAIN",
+			"authWorkstation": "WORKSTATION"
+		}`,
+	}
+
+	for i := 0; i < 2; i++ {
+		result := executor.Execute(context.Background(), monitor, proxy)
+		assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 6, proxied, "every handshake step should go through the proxy")
+	assert.Len(t, executor.ntlmTransports, 1, "transport should be reused across executions")
+}
+
+func TestHTTPExecutor_NTLMTransport_ProxyCredentials(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	proxy := &Proxy{Protocol: "http", Host: "proxy.example.test", Port: 3128, Auth: true, Username: "user", Password: "old"}
+
+	first := executor.ntlmTransport(false, proxy)
+	assert.Same(t, first, executor.ntlmTransport(false, proxy))
+
+	changed := *proxy
+	changed.Password = "new"
+	assert.NotSame(t, first, executor.ntlmTransport(false, &changed), "expected a new transport for the new password")
+
+	noAuth := *proxy
+	noAuth.Auth = false
+	assert.NotSame(t, first, executor.ntlmTransport(false, &noAuth))
+	assert.NotSame(t, first, executor.ntlmTransport(true, proxy))
+}
+
 func TestHTTPExecutor_Execute_MaxRedirects(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
@@ -1099,6 +1221,223 @@ func TestHTTPExecutor_Execute_DisabledRedirects(t *testing.T) {
 	assert.Contains(t, result.Message, "redirects disabled")
 }
 
+func TestHTTPExecutor_Execute_ExpectedRedirectTo(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "https://new.example.com/landing?utm=1", http.StatusMovedPermanently)
+		case "/relative":
+			http.Redirect(w, r, "/landing", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		path           string
+		expected       string
+		match          string
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{
+			name:           "exact match",
+			path:           "/old",
+			expected:       "https://new.example.com/landing?utm=1",
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "redirected to https://new.example.com/landing?utm=1",
+		},
+		{
+			name:           "prefix match",
+			path:           "/old",
+			expected:       "https://new.example.com/landing",
+			match:          "prefix",
+			expectedStatus: shared.MonitorStatusUp,
+		},
+		{
+			name:           "exact mismatch",
+			path:           "/old",
+			expected:       "https://new.example.com/landing",
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "got https://new.example.com/landing?utm=1",
+		},
+		{
+			name:           "prefix mismatch",
+			path:           "/old",
+			expected:       "https://other.example.com",
+			match:          "prefix",
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "got https://new.example.com/landing?utm=1",
+		},
+		{
+			name:           "relative location resolved against request",
+			path:           "/relative",
+			expected:       server.URL + "/landing",
+			expectedStatus: shared.MonitorStatusUp,
+		},
+		{
+			name:           "no redirect",
+			path:           "/",
+			expected:       "https://new.example.com/landing",
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "got status: 200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{
+				"url":                  server.URL + tt.path,
+				"method":               "GET",
+				"encoding":             "json",
+				"accepted_statuscodes": []string{"2XX"},
+				"authMethod":           "none",
+				"max_redirects":        0,
+				"expected_redirect_to": tt.expected,
+			}
+			if tt.match != "" {
+				config["expected_redirect_match"] = tt.match
+			}
+			configJSON, _ := json.Marshal(config)
+
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Test Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   string(configJSON),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			if tt.messageContain != "" {
+				assert.Contains(t, result.Message, tt.messageContain)
+			}
+		})
+	}
+}
+
+func TestHTTPExecutor_Validate_ExpectedRedirectTo(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	valid := `{
+		"url": "http://example.com",
+		"method": "GET",
+		"encoding": "json",
+		"accepted_statuscodes": ["3XX"],
+		"authMethod": "none",
+		"max_redirects": 0,
+		"expected_redirect_to": "https://example.org",
+		"expected_redirect_match": "prefix"
+	}`
+	assert.NoError(t, executor.Validate(valid))
+
+	withRedirects := `{
+		"url": "http://example.com",
+		"method": "GET",
+		"encoding": "json",
+		"accepted_statuscodes": ["3XX"],
+		"authMethod": "none",
+		"max_redirects": 5,
+		"expected_redirect_to": "https://example.org"
+	}`
+	assert.Error(t, executor.Validate(withRedirects))
+
+	badMatch := `{
+		"url": "http://example.com",
+		"method": "GET",
+		"encoding": "json",
+		"accepted_statuscodes": ["3XX"],
+		"authMethod": "none",
+		"expected_redirect_to": "https://example.org",
+		"expected_redirect_match": "regex"
+	}`
+	assert.Error(t, executor.Validate(badMatch))
+}
+
+func TestHTTPExecutor_Execute_ExpectedRedirectCount(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	// /hops/N redirects N times before answering
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hops/"))
+		if hops > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hops/%d", hops-1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		hops           int
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{name: "too few redirects", hops: 0, expectedStatus: shared.MonitorStatusDown, messageContain: "expected 1 redirects, followed 0"},
+		{name: "exact redirects", hops: 1, expectedStatus: shared.MonitorStatusUp, messageContain: "followed 1 redirects"},
+		{name: "too many redirects", hops: 3, expectedStatus: shared.MonitorStatusDown, messageContain: "expected 1 redirects, followed 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configJSON, _ := json.Marshal(map[string]any{
+				"url":                     fmt.Sprintf("%s/hops/%d", server.URL, tt.hops),
+				"method":                  "GET",
+				"encoding":                "json",
+				"accepted_statuscodes":    []string{"2XX"},
+				"authMethod":              "none",
+				"max_redirects":           5,
+				"expected_redirect_count": 1,
+			})
+
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Test Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   string(configJSON),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
+
+func TestHTTPExecutor_Validate_ExpectedRedirectCount(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	config := func(extra string) string {
+		return `{
+			"url": "http://example.com",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none",
+			` + extra + `
+		}`
+	}
+
+	assert.NoError(t, executor.Validate(config(`"max_redirects": 5, "expected_redirect_count": 1`)))
+	assert.NoError(t, executor.Validate(config(`"max_redirects": 0, "expected_redirect_count": 0`)))
+	assert.Error(t, executor.Validate(config(`"max_redirects": 1, "expected_redirect_count": 2`)))
+	assert.Error(t, executor.Validate(config(`"max_redirects": 5, "expected_redirect_count": -1`)))
+	assert.Error(t, executor.Validate(config(`"max_redirects": 0, "expected_redirect_count": 0, "expected_redirect_to": "https://example.org"`)))
+}
+
 func TestIsStatusAccepted(t *testing.T) {
 	tests := []struct {
 		name           string
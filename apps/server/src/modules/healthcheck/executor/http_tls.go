@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// tlsVersions maps the expected_tls_version values to protocol versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func tlsVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// negotiatedTLS describes the protocol of a completed handshake
+func negotiatedTLS(state *tls.ConnectionState) string {
+	alpn := state.NegotiatedProtocol
+	if alpn == "" {
+		alpn = "none"
+	}
+	return fmt.Sprintf("TLS %s, ALPN %s", tlsVersionName(state.Version), alpn)
+}
+
+// checkNegotiatedTLS asserts the TLS version and ALPN protocol negotiated for
+// the response, returning the negotiated values for the check message
+func checkNegotiatedTLS(state *tls.ConnectionState, cfg *HTTPConfig) (string, error) {
+	if cfg.ExpectedALPN == "" && cfg.ExpectedTLSVersion == "" {
+		return "", nil
+	}
+	if state == nil {
+		return "", fmt.Errorf("expected a TLS connection, but the response was not served over TLS")
+	}
+
+	negotiated := negotiatedTLS(state)
+	var mismatches []string
+	if cfg.ExpectedTLSVersion != "" && state.Version != tlsVersions[cfg.ExpectedTLSVersion] {
+		mismatches = append(mismatches, fmt.Sprintf("TLS %s", cfg.ExpectedTLSVersion))
+	}
+	if cfg.ExpectedALPN != "" && state.NegotiatedProtocol != cfg.ExpectedALPN {
+		mismatches = append(mismatches, fmt.Sprintf("ALPN %s", cfg.ExpectedALPN))
+	}
+	if len(mismatches) > 0 {
+		return negotiated, fmt.Errorf("expected %s, negotiated %s", strings.Join(mismatches, " and "), negotiated)
+	}
+	return negotiated, nil
+}
+
+// configureHTTPVersion pins the protocol of the transport, auto leaves the
+// transport negotiating as it is
+func configureHTTPVersion(transport *http.Transport, version string) {
+	switch version {
+	case "http1":
+		transport.ForceAttemptHTTP2 = false
+		// a non-nil empty map keeps the transport from upgrading to HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	case "http2":
+		transport.ForceAttemptHTTP2 = true
+	}
+}
+
+// checkHTTPVersion fails a response to a pinned HTTP/2 request that the
+// server answered over HTTP/1.1
+func checkHTTPVersion(resp *http.Response, version string) error {
+	if version == "http2" && resp.ProtoMajor != 2 {
+		return fmt.Errorf("expected HTTP/2, server answered %s", resp.Proto)
+	}
+	return nil
+}
+
+// withStoredCertificate returns a copy of the config carrying the PEM material
+// of its stored certificate. The certificate is checked here as the inline one
+// is before execution, so an expired entry names the stored certificate.
+func (h *HTTPExecutor) withStoredCertificate(ctx context.Context, cfg *HTTPConfig, now time.Time) (*HTTPConfig, error) {
+	if h.certificates == nil {
+		return nil, fmt.Errorf("stored certificates are not available")
+	}
+	stored, err := h.certificates.FindByID(ctx, cfg.TlsCertificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate %s: %w", cfg.TlsCertificateID, err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("certificate %s not found", cfg.TlsCertificateID)
+	}
+	if err := checkCertificateValidity(ClientCertificate{Field: fmt.Sprintf("certificate %q", stored.Name), PEM: stored.Cert}, now); err != nil {
+		return nil, err
+	}
+
+	resolved := *cfg
+	resolved.TlsCert = stored.Cert
+	resolved.TlsKey = stored.Key
+	resolved.TlsCa = stored.CA
+	return &resolved, nil
+}
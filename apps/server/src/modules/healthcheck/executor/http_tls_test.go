@@ -0,0 +1,277 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newNegotiationServer starts a TLS server capped at maxVersion, offering h2
+// only when http2 is set
+func newNegotiationServer(t *testing.T, maxVersion uint16, http2 bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = http2
+	server.TLS = &tls.Config{MaxVersion: maxVersion}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func negotiationMonitor(url, assertions string) *Monitor {
+	return &Monitor{
+		ID:      "monitor1",
+		Type:    "http",
+		Name:    "Negotiation",
+		Timeout: 5,
+		Config: fmt.Sprintf(`{
+			"url": "%s",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none",
+			"ignore_tls_errors": true
+			%s
+		}`, url, assertions),
+	}
+}
+
+func TestHTTPExecutor_Execute_ExpectedNegotiation(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	h2TLS13 := newNegotiationServer(t, tls.VersionTLS13, true)
+	h1TLS12 := newNegotiationServer(t, tls.VersionTLS12, false)
+
+	tests := []struct {
+		name       string
+		server     *httptest.Server
+		assertions string
+		status     shared.MonitorStatus
+		message    string
+	}{
+		{
+			name:       "h2 and TLS 1.3 negotiated",
+			server:     h2TLS13,
+			assertions: `, "expected_alpn": "h2", "expected_tls_version": "1.3"`,
+			status:     shared.MonitorStatusUp,
+			message:    "TLS 1.3, ALPN h2",
+		},
+		{
+			name:       "http/1.1 negotiated instead of h2",
+			server:     h1TLS12,
+			assertions: `, "expected_alpn": "h2"`,
+			status:     shared.MonitorStatusDown,
+			message:    "expected ALPN h2, negotiated TLS 1.2, ALPN http/1.1",
+		},
+		{
+			name:       "older TLS version negotiated",
+			server:     h1TLS12,
+			assertions: `, "expected_tls_version": "1.3"`,
+			status:     shared.MonitorStatusDown,
+			message:    "expected TLS 1.3, negotiated TLS 1.2",
+		},
+		{
+			name:       "both mismatch",
+			server:     h1TLS12,
+			assertions: `, "expected_alpn": "h2", "expected_tls_version": "1.3"`,
+			status:     shared.MonitorStatusDown,
+			message:    "expected TLS 1.3 and ALPN h2",
+		},
+		{
+			name:       "TLS 1.2 asserted",
+			server:     h1TLS12,
+			assertions: `, "expected_tls_version": "1.2", "expected_alpn": "http/1.1"`,
+			status:     shared.MonitorStatusUp,
+			message:    "TLS 1.2, ALPN http/1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), negotiationMonitor(tt.server.URL, tt.assertions), nil)
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_NoNegotiationReportedWithoutAssertion(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	server := newNegotiationServer(t, tls.VersionTLS13, true)
+
+	result := executor.Execute(context.Background(), negotiationMonitor(server.URL, ""), nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	assert.NotContains(t, result.Message, "ALPN")
+}
+
+func TestHTTPConfig_Validate_ExpectedNegotiation(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	config := func(url, assertions string) string {
+		return fmt.Sprintf(`{
+			"url": "%s",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none"
+			%s
+		}`, url, assertions)
+	}
+
+	assert.NoError(t, executor.Validate(config("https://example.com", `, "expected_alpn": "h2", "expected_tls_version": "1.3"`)))
+	assert.Error(t, executor.Validate(config("http://example.com", `, "expected_alpn": "h2"`)), "expected plain http to be rejected")
+	assert.Error(t, executor.Validate(config("https://example.com", `, "expected_tls_version": "1.4"`)), "expected an unknown version to be rejected")
+}
+
+func TestHTTPExecutor_Execute_HTTPVersion(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	h2 := newNegotiationServer(t, tls.VersionTLS13, true)
+	h1 := newNegotiationServer(t, tls.VersionTLS13, false)
+
+	tests := []struct {
+		name    string
+		server  *httptest.Server
+		version string
+		status  shared.MonitorStatus
+		message string
+	}{
+		{name: "http1 pinned on an h2 server", server: h2, version: "http1", status: shared.MonitorStatusUp, message: "HTTP/1.1"},
+		{name: "http2 pinned", server: h2, version: "http2", status: shared.MonitorStatusUp, message: "HTTP/2.0"},
+		{name: "http2 pinned on an http1 server", server: h1, version: "http2", status: shared.MonitorStatusDown, message: "expected HTTP/2, server answered HTTP/1.1"},
+		{name: "auto", server: h1, version: "auto", status: shared.MonitorStatusUp, message: "200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), negotiationMonitor(tt.server.URL, `, "http_version": "`+tt.version+`"`), nil)
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestHTTPConfig_Validate_HTTPVersion(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	config := func(url, extra string) string {
+		return fmt.Sprintf(`{
+			"url": "%s",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none"
+			%s
+		}`, url, extra)
+	}
+
+	assert.NoError(t, executor.Validate(config("http://example.com", `, "http_version": "http1"`)))
+	assert.NoError(t, executor.Validate(config("https://example.com", `, "http_version": "http2"`)))
+	assert.NoError(t, executor.Validate(config("http://example.com", `, "http_version": "auto"`)))
+	assert.Error(t, executor.Validate(config("http://example.com", `, "http_version": "http2"`)), "HTTP/2 needs TLS")
+	assert.Error(t, executor.Validate(config("https://example.com", `, "http_version": "http3"`)))
+}
+
+type fakeCertificateStore map[string]*shared.Certificate
+
+func (s fakeCertificateStore) FindByID(ctx context.Context, id string) (*shared.Certificate, error) {
+	return s[id], nil
+}
+
+func storedCertificateMonitor(url, certificateID string) *Monitor {
+	return &Monitor{
+		ID:      "monitor1",
+		Type:    "http",
+		Name:    "Stored certificate",
+		Timeout: 5,
+		Config: fmt.Sprintf(`{
+			"url": "%s",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "mtls",
+			"tls_certificate_id": "%s"
+		}`, url, certificateID),
+	}
+}
+
+func TestHTTPExecutor_Execute_StoredCertificate(t *testing.T) {
+	certs := newGRPCTestMTLSCerts(t)
+	serverCert, err := tls.X509KeyPair([]byte(certs.serverCert), []byte(certs.serverKey))
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(certs.ca)))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	executor.certificates = fakeCertificateStore{
+		"client":  {ID: "client", Name: "payments client", Cert: certs.clientCert, Key: certs.clientKey, CA: certs.ca},
+		"expired": {ID: "expired", Name: "old client", Cert: clientCertPEM(t, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1)), Key: certs.clientKey},
+		"foreign": {ID: "foreign", Name: "wrong pair", Cert: certs.serverCert, Key: certs.clientKey, CA: certs.ca},
+	}
+
+	tests := []struct {
+		name          string
+		certificateID string
+		status        shared.MonitorStatus
+		message       string
+	}{
+		{name: "stored certificate", certificateID: "client", status: shared.MonitorStatusUp, message: "200"},
+		{name: "unknown certificate", certificateID: "missing", status: shared.MonitorStatusDown, message: "certificate missing not found"},
+		{name: "expired certificate", certificateID: "expired", status: shared.MonitorStatusDown, message: `client certificate "peekaping-client" in certificate "old client" expired`},
+		{name: "key of another certificate", certificateID: "foreign", status: shared.MonitorStatusDown, message: "invalid mTLS cert/key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), storedCertificateMonitor(server.URL, tt.certificateID), nil)
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+
+	t.Run("no store", func(t *testing.T) {
+		result := NewHTTPExecutor(zap.NewNop().Sugar()).Execute(context.Background(), storedCertificateMonitor(server.URL, "client"), nil)
+		assert.Equal(t, shared.MonitorStatusDown, result.Status)
+		assert.Contains(t, result.Message, "stored certificates are not available")
+	})
+}
+
+func TestHTTPConfig_Validate_StoredCertificate(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	config := func(authMethod, extra string) string {
+		return fmt.Sprintf(`{
+			"url": "https://example.com",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "%s"
+			%s
+		}`, authMethod, extra)
+	}
+
+	assert.NoError(t, executor.Validate(config("mtls", `, "tls_certificate_id": "client"`)), "the reference replaces the inline fields")
+	assert.Error(t, executor.Validate(config("mtls", `, "tls_certificate_id": "client", "tlsCert": "cert"`)), "expected inline material next to a reference to be rejected")
+	assert.Error(t, executor.Validate(config("none", `, "tls_certificate_id": "client"`)), "the certificate is only presented with mtls")
+	assert.Error(t, executor.Validate(config("mtls", ``)), "inline fields are still required without a reference")
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// maxCachedTransports bounds the transports kept for distinct proxy and TLS
+// settings, the idle connections of an evicted transport are closed
+const maxCachedTransports = 256
+
+func newPooledTransport() *http.Transport {
+	return &http.Transport{
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// clientTLSConfig returns the TLS settings of the check, nil when the defaults apply
+func clientTLSConfig(cfg *HTTPConfig) (*tls.Config, error) {
+	if cfg.AuthMethod != "mtls" {
+		if cfg.IgnoreTlsErrors {
+			return &tls.Config{InsecureSkipVerify: true}, nil
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(cfg.TlsCert), []byte(cfg.TlsKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid mTLS cert/key: %w", err)
+	}
+	// a stored certificate without CA verifies against the system roots
+	var caCertPool *x509.CertPool
+	if cfg.TlsCa != "" {
+		caCertPool = x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(cfg.TlsCa)); !ok {
+			return nil, fmt.Errorf("invalid mTLS CA cert")
+		}
+	}
+	return &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		RootCAs:            caCertPool,
+		InsecureSkipVerify: cfg.IgnoreTlsErrors,
+	}, nil
+}
+
+// newCheckTransport builds a pooled transport with the TLS settings. A custom
+// TLS config turns HTTP/2 off unless forced, so h2 is offered whenever ALPN
+// is asserted.
+func newCheckTransport(cfg *HTTPConfig, tlsConfig *tls.Config) *http.Transport {
+	transport := newPooledTransport()
+	transport.ForceAttemptHTTP2 = cfg.ExpectedALPN != ""
+	transport.TLSClientConfig = tlsConfig
+	configureHTTPVersion(transport, cfg.HTTPVersion)
+	return transport
+}
+
+// transportKey identifies the checks that can share a transport, it is empty
+// for the checks using the default transport. Credentials are hashed so they
+// do not sit in memory in another copy.
+func transportKey(cfg *HTTPConfig, proxyModel *Proxy) string {
+	pinnedVersion := cfg.HTTPVersion != "" && cfg.HTTPVersion != "auto"
+	if proxyModel == nil && !cfg.IgnoreTlsErrors && cfg.AuthMethod != "mtls" && cfg.ExpectedALPN == "" && !pinnedVersion {
+		return ""
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "insecure=%t|alpn=%t|version=%s", cfg.IgnoreTlsErrors, cfg.ExpectedALPN != "", cfg.HTTPVersion)
+	if cfg.AuthMethod == "mtls" {
+		fmt.Fprintf(hash, "|mtls=%q,%q,%q", cfg.TlsCert, cfg.TlsKey, cfg.TlsCa)
+	}
+	if proxyModel != nil {
+		fmt.Fprintf(hash, "|proxy=%s,%s,%d,%t,%q,%q", proxyModel.Protocol, proxyModel.Host, proxyModel.Port,
+			proxyModel.Auth, proxyModel.Username, proxyModel.Password)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// transportFor returns the transport of a check, shared with every check of
+// the same proxy and TLS settings. A certificate chain check gets a transport
+// of its own, the chain is only reported from a handshake of that check.
+func (h *HTTPExecutor) transportFor(cfg *HTTPConfig, proxyModel *Proxy, hostname string, chainReport **CertChainReport) (http.RoundTripper, error) {
+	tlsConfig, err := clientTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.CheckCertChain {
+		roots, err := certChainRoots(cfg.TlsCa)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		setupCertChainCheck(tlsConfig, roots, hostname, cfg.IgnoreTlsErrors, chainReport)
+		transport := newCheckTransport(cfg, tlsConfig)
+		transport.DisableKeepAlives = true
+		return buildProxyTransport(transport, proxyModel), nil
+	}
+
+	key := transportKey(cfg, proxyModel)
+	if key == "" {
+		return h.transport, nil
+	}
+
+	h.transportsMu.Lock()
+	defer h.transportsMu.Unlock()
+
+	if transport, ok := h.transports[key]; ok {
+		return transport, nil
+	}
+	if len(h.transports) >= maxCachedTransports {
+		for evictedKey, evicted := range h.transports {
+			if idle, ok := evicted.(interface{ CloseIdleConnections() }); ok {
+				idle.CloseIdleConnections()
+			}
+			delete(h.transports, evictedKey)
+			break
+		}
+	}
+
+	transport := buildProxyTransport(newCheckTransport(cfg, tlsConfig), proxyModel)
+	h.transports[key] = transport
+	return transport, nil
+}
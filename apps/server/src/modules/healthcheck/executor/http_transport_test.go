@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTransportKey(t *testing.T) {
+	proxyModel := &Proxy{Protocol: "http", Host: "proxy.local", Port: 3128}
+
+	assert.Empty(t, transportKey(&HTTPConfig{}, nil), "expected the default transport for plain checks")
+	assert.Empty(t, transportKey(&HTTPConfig{HTTPVersion: "auto"}, nil))
+
+	insecure := transportKey(&HTTPConfig{IgnoreTlsErrors: true}, nil)
+	assert.NotEmpty(t, insecure)
+	assert.Equal(t, insecure, transportKey(&HTTPConfig{IgnoreTlsErrors: true}, nil), "expected equal settings to share a key")
+
+	proxied := transportKey(&HTTPConfig{}, proxyModel)
+	assert.NotEmpty(t, proxied)
+	assert.NotEqual(t, proxied, transportKey(&HTTPConfig{}, &Proxy{Protocol: "http", Host: "proxy.local", Port: 8080}))
+	assert.NotEqual(t, proxied, transportKey(&HTTPConfig{}, &Proxy{Protocol: "http", Host: "proxy.local", Port: 3128, Auth: true, Username: "user", Password: "pass"}))
+
+	mtls := transportKey(&HTTPConfig{AuthMethod: "mtls", TlsCert: "cert-a", TlsKey: "key"}, nil)
+	assert.NotEqual(t, mtls, transportKey(&HTTPConfig{AuthMethod: "mtls", TlsCert: "cert-b", TlsKey: "key"}, nil), "expected another client certificate to get another transport")
+	assert.NotContains(t, mtls, "key")
+
+	assert.NotEqual(t, transportKey(&HTTPConfig{HTTPVersion: "1.1"}, nil), transportKey(&HTTPConfig{HTTPVersion: "2"}, nil))
+}
+
+func TestHTTPExecutor_Execute_ReusesTLSConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	newMonitor := func(id string) *Monitor {
+		return &Monitor{ID: id, Type: "http", Name: "TLS", Timeout: 5, Config: httpStreamingConfig(t, server.URL, map[string]any{"ignore_tls_errors": true})}
+	}
+	for _, id := range []string{"monitor1", "monitor2", "monitor1"} {
+		result := executor.Execute(context.Background(), newMonitor(id), nil)
+		require.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	}
+	assert.Equal(t, int32(1), connections.Load(), "expected checks with the same TLS settings to share a connection")
+	assert.Len(t, executor.transports, 1)
+}
+
+func TestHTTPExecutor_Execute_CheckCertChainReportsEveryCheck(t *testing.T) {
+	chain := newTestCertChain(t)
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{chain.leaf.Raw, chain.intermediate.Raw},
+		PrivateKey:  chain.leafKey,
+		Leaf:        chain.leaf,
+	}}}
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+	m := &Monitor{ID: "monitor1", Type: "http", Name: "Chain", Timeout: 5, Config: httpStreamingConfig(t, server.URL, map[string]any{
+		"check_cert_chain": true,
+		"tlsCa":            chain.rootPEM,
+	})}
+	for i := 0; i < 2; i++ {
+		result := executor.Execute(context.Background(), m, nil)
+		require.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+		assert.Contains(t, result.Message, "certificate chain complete (length 2)")
+	}
+	assert.Equal(t, int32(2), connections.Load(), "expected a handshake per chain check")
+	assert.Empty(t, executor.transports, "expected chain checks to keep out of the transport cache")
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// maxJSONSchemaBodySize limits how much of a response body is read for schema validation
+const maxJSONSchemaBodySize = 1 << 20
+
+const jsonSchemaResourceURL = "peekaping://expected_json_schema.json"
+
+// compileJSONSchema compiles an inline JSON Schema. Remote references are not
+// followed so a schema can't make the server fetch arbitrary URLs or files.
+func compileJSONSchema(schema string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("external schema references are not supported: %s", s)
+	}
+
+	if err := compiler.AddResource(jsonSchemaResourceURL, strings.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	compiled, err := compiler.Compile(jsonSchemaResourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	return compiled, nil
+}
+
+// validateJSONSchemaBody validates a response body against schema and returns
+// the first violation found
+func validateJSONSchemaBody(schema *jsonschema.Schema, body io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(body, maxJSONSchemaBodySize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) > maxJSONSchemaBodySize {
+		return fmt.Errorf("response body exceeds %d bytes", maxJSONSchemaBodySize)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("response body is not valid json: %w", err)
+	}
+
+	err = schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	first := firstSchemaViolation(validationErr)
+	location := first.InstanceLocation
+	if location == "" {
+		location = "/"
+	}
+	return fmt.Errorf("schema violation at %s: %s", location, first.Message)
+}
+
+// firstSchemaViolation follows the first cause down to the most specific error
+func firstSchemaViolation(err *jsonschema.ValidationError) *jsonschema.ValidationError {
+	for len(err.Causes) > 0 {
+		err = err.Causes[0]
+	}
+	return err
+}
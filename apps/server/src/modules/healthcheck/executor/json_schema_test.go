@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testHealthSchema = `{
+	"type": "object",
+	"required": ["status", "checks"],
+	"properties": {
+		"status": {"enum": ["ok", "degraded"]},
+		"checks": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["name", "latency_ms"],
+				"properties": {
+					"name": {"type": "string"},
+					"latency_ms": {"type": "integer", "minimum": 0}
+				}
+			}
+		}
+	}
+}`
+
+func httpSchemaConfig(t *testing.T, url, schema string) string {
+	config := map[string]any{
+		"url":                  url,
+		"method":               "GET",
+		"encoding":             "json",
+		"accepted_statuscodes": []string{"2XX"},
+		"authMethod":           "none",
+		"expected_json_schema": schema,
+	}
+	configJSON, err := json.Marshal(config)
+	require.NoError(t, err)
+	return string(configJSON)
+}
+
+func TestHTTPExecutor_Validate_ExpectedJsonSchema(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		schema      string
+		errContains string
+	}{
+		{name: "valid schema", schema: testHealthSchema},
+		{name: "malformed json", schema: `{"type": "object"`, errContains: "ExpectedJsonSchema"},
+		{name: "schema does not compile", schema: `{"type": "objekt"}`, errContains: "invalid json schema"},
+		{name: "invalid pattern", schema: `{"type": "string", "pattern": "("}`, errContains: "invalid json schema"},
+		{name: "remote reference", schema: `{"$ref": "https://example.com/schema.json"}`, errContains: "invalid json schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(httpSchemaConfig(t, "https://example.com/health", tt.schema))
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errContains)
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_ExpectedJsonSchema(t *testing.T) {
+	executor := NewHTTPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{
+			name:           "conforming response",
+			body:           `{"status": "ok", "checks": [{"name": "db", "latency_ms": 3}]}`,
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "response matches json schema",
+		},
+		{
+			name:           "missing required property",
+			body:           `{"status": "ok"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "schema violation at /: missing properties: 'checks'",
+		},
+		{
+			name:           "nested violation",
+			body:           `{"status": "ok", "checks": [{"name": "db", "latency_ms": 3}, {"name": "cache", "latency_ms": -1}]}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "schema violation at /checks/1/latency_ms",
+		},
+		{
+			name:           "non json response",
+			body:           `OK`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "response body is not valid json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "http",
+				Name:     "Test Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   httpSchemaConfig(t, server.URL, testHealthSchema),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
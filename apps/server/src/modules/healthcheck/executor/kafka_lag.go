@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+// kafkaTopicPattern matches the topic names Kafka accepts
+var kafkaTopicPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// kafkaOffsetReader is the part of the cluster needed to compute consumer group lag
+type kafkaOffsetReader interface {
+	Partitions(topic string) ([]int32, error)
+	// GetOffset returns the oldest or newest offset of a partition
+	GetOffset(topic string, partition int32, offset int64) (int64, error)
+	// CommittedOffsets returns the committed offset of the group per partition,
+	// -1 for partitions without a commit
+	CommittedOffsets(group, topic string, partitions []int32) (map[int32]int64, error)
+}
+
+type saramaOffsetReader struct {
+	sarama.Client
+	admin sarama.ClusterAdmin
+}
+
+func newSaramaOffsetReader(client sarama.Client) (*saramaOffsetReader, error) {
+	// the admin shares the client, closing the client is enough
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka admin client: %w", err)
+	}
+	return &saramaOffsetReader{Client: client, admin: admin}, nil
+}
+
+func (r *saramaOffsetReader) CommittedOffsets(group, topic string, partitions []int32) (map[int32]int64, error) {
+	response, err := r.admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return nil, err
+	}
+	if response.Err != sarama.ErrNoError {
+		return nil, response.Err
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		block := response.GetBlock(topic, partition)
+		if block == nil {
+			offsets[partition] = -1
+			continue
+		}
+		if block.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("partition %d: %w", partition, block.Err)
+		}
+		offsets[partition] = block.Offset
+	}
+	return offsets, nil
+}
+
+// consumerLag is the lag of a consumer group on a topic
+type consumerLag struct {
+	Total      int64
+	Partitions int
+	// partition with the highest lag and its lag
+	MaxPartition    int32
+	MaxPartitionLag int64
+	// partitions the group has committed an offset for
+	Committed int
+}
+
+// consumerGroupLag sums the lag of group over every partition of topic. A
+// partition without a committed offset lags by everything still retained.
+func consumerGroupLag(reader kafkaOffsetReader, group, topic string) (*consumerLag, error) {
+	partitions, err := reader.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("topic '%s' not available: %w", topic, err)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+	committed, err := reader.CommittedOffsets(group, topic, partitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offsets of consumer group '%s': %w", group, err)
+	}
+
+	lag := &consumerLag{Partitions: len(partitions)}
+	for _, partition := range partitions {
+		newest, err := reader.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch newest offset of partition %d: %w", partition, err)
+		}
+
+		from, ok := committed[partition]
+		if ok && from >= 0 {
+			lag.Committed++
+		} else {
+			from, err = reader.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch oldest offset of partition %d: %w", partition, err)
+			}
+		}
+
+		partitionLag := newest - from
+		if partitionLag < 0 {
+			partitionLag = 0
+		}
+		lag.Total += partitionLag
+		if partitionLag > lag.MaxPartitionLag {
+			lag.MaxPartition = partition
+			lag.MaxPartitionLag = partitionLag
+		}
+	}
+	return lag, nil
+}
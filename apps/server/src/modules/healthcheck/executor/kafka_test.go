@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestKafkaExecutor_Validate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewKafkaExecutor(logger)
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid single broker",
+			config:    `{"brokers": "localhost:9092", "sasl_options": {"mechanism": "None"}}`,
+			wantError: false,
+		},
+		{
+			name:      "valid broker list with topic",
+			config:    `{"brokers": "kafka-1:9092, kafka-2:9092", "topic": "events", "sasl_options": {"mechanism": "None"}}`,
+			wantError: false,
+		},
+		{
+			name: "valid SASL config",
+			config: `{
+				"brokers": "localhost:9092",
+				"ssl": true,
+				"sasl_options": {"mechanism": "SCRAM-SHA-512", "username": "user", "password": "pass"}
+			}`,
+			wantError: false,
+		},
+		{
+			name:      "empty broker list",
+			config:    `{"brokers": " , ", "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "missing port",
+			config:    `{"brokers": "localhost", "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid port",
+			config:    `{"brokers": "localhost:99999", "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "missing host",
+			config:    `{"brokers": ":9092", "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "SASL without credentials",
+			config:    `{"brokers": "localhost:9092", "sasl_options": {"mechanism": "PLAIN"}}`,
+			wantError: true,
+		},
+		{
+			name:      "valid consumer lag config",
+			config:    `{"brokers": "localhost:9092", "topic": "orders", "consumer_group": "billing", "max_lag": 500, "sasl_options": {"mechanism": "None"}}`,
+			wantError: false,
+		},
+		{
+			name:      "consumer lag without topic",
+			config:    `{"brokers": "localhost:9092", "consumer_group": "billing", "max_lag": 500, "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "consumer lag with invalid topic",
+			config:    `{"brokers": "localhost:9092", "topic": "orders/eu", "consumer_group": "billing", "max_lag": 500, "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "consumer lag without threshold",
+			config:    `{"brokers": "localhost:9092", "topic": "orders", "consumer_group": "billing", "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "consumer lag with negative threshold",
+			config:    `{"brokers": "localhost:9092", "topic": "orders", "consumer_group": "billing", "max_lag": -1, "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "consumer group with surrounding whitespace",
+			config:    `{"brokers": "localhost:9092", "topic": "orders", "consumer_group": " billing", "max_lag": 500, "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "threshold without consumer group",
+			config:    `{"brokers": "localhost:9092", "topic": "orders", "max_lag": 500, "sasl_options": {"mechanism": "None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown field",
+			config:    `{"brokers": "localhost:9092", "unknown": true}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseKafkaBrokers(t *testing.T) {
+	brokers, err := parseKafkaBrokers("kafka-1:9092, kafka-2:9093,,")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9093"}, brokers)
+}
+
+func TestKafkaExecutor_Execute_Unreachable(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewKafkaExecutor(logger)
+
+	monitor := &Monitor{
+		ID:      "monitor1",
+		Type:    "kafka",
+		Name:    "Test Monitor",
+		Timeout: 2,
+		Config:  `{"brokers": "127.0.0.1:1", "sasl_options": {"mechanism": "None"}}`,
+	}
+
+	result := executor.Execute(context.Background(), monitor, nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "failed to connect to Kafka")
+}
+
+// stubOffsetReader serves fixed offsets per partition
+type stubOffsetReader struct {
+	oldest    map[int32]int64
+	newest    map[int32]int64
+	committed map[int32]int64
+	err       error
+}
+
+func (r *stubOffsetReader) Partitions(topic string) ([]int32, error) {
+	var partitions []int32
+	for p := range r.newest {
+		partitions = append(partitions, p)
+	}
+	return partitions, nil
+}
+
+func (r *stubOffsetReader) GetOffset(topic string, partition int32, offset int64) (int64, error) {
+	if offset == sarama.OffsetOldest {
+		return r.oldest[partition], nil
+	}
+	return r.newest[partition], nil
+}
+
+func (r *stubOffsetReader) CommittedOffsets(group, topic string, partitions []int32) (map[int32]int64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	offsets := make(map[int32]int64)
+	for _, p := range partitions {
+		if offset, ok := r.committed[p]; ok {
+			offsets[p] = offset
+		} else {
+			offsets[p] = -1
+		}
+	}
+	return offsets, nil
+}
+
+func TestConsumerGroupLag(t *testing.T) {
+	reader := &stubOffsetReader{
+		oldest:    map[int32]int64{0: 0, 1: 100, 2: 50},
+		newest:    map[int32]int64{0: 1000, 1: 400, 2: 80},
+		committed: map[int32]int64{0: 990, 1: 150},
+	}
+
+	lag, err := consumerGroupLag(reader, "billing", "orders")
+	assert.NoError(t, err)
+	// 10 + 250 + 30 for the partition without a commit
+	assert.Equal(t, int64(290), lag.Total)
+	assert.Equal(t, 3, lag.Partitions)
+	assert.Equal(t, 2, lag.Committed)
+	assert.Equal(t, int32(1), lag.MaxPartition)
+	assert.Equal(t, int64(250), lag.MaxPartitionLag)
+
+	// committed offsets ahead of the newest offset never count as negative lag
+	reader.committed[2] = 90
+	lag, err = consumerGroupLag(reader, "billing", "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(260), lag.Total)
+
+	reader.err = errors.New("group coordinator not available")
+	_, err = consumerGroupLag(reader, "billing", "orders")
+	assert.ErrorContains(t, err, "failed to fetch offsets of consumer group 'billing'")
+}
+
+func TestKafkaLagResult(t *testing.T) {
+	cfg := &KafkaConfig{Topic: "orders", ConsumerGroup: "billing", MaxLag: 100}
+	now := time.Now().UTC()
+
+	result := kafkaLagResult(cfg, &consumerLag{Total: 42, Partitions: 3, Committed: 3}, now, now)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	assert.Equal(t, "Consumer group 'billing' lag on 'orders': 42 (max 100, partitions: 3)", result.Message)
+
+	result = kafkaLagResult(cfg, &consumerLag{Total: 101, Partitions: 3, Committed: 3, MaxPartition: 2, MaxPartitionLag: 90}, now, now)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "lag on 'orders': 101 (max 100")
+	assert.Contains(t, result.Message, "highest on partition 2: 90")
+
+	result = kafkaLagResult(cfg, &consumerLag{Total: 0, Partitions: 3}, now, now)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "has no committed offsets")
+}
@@ -0,0 +1,350 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"peekaping/src/modules/shared"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type K8sConfig struct {
+	// Connection, exactly one of in_cluster, kubeconfig and api_server
+	InCluster  bool   `json:"in_cluster,omitempty"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Kubeconfig context, the current context when empty
+	Context         string `json:"context,omitempty" example:"production"`
+	APIServer       string `json:"api_server,omitempty" validate:"omitempty,url" example:"https://k8s.example.com:6443"`
+	Token           string `json:"token,omitempty"`
+	CACert          string `json:"ca_cert,omitempty"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors,omitempty"`
+
+	Namespace string `json:"namespace" validate:"required" example:"default"`
+	Resource  string `json:"resource" validate:"required,oneof=deployment pod" example:"deployment"`
+	// Name of the resource, or a label selector matching several of them
+	Name     string `json:"name,omitempty" validate:"required_without=Selector,excluded_with=Selector" example:"api"`
+	Selector string `json:"selector,omitempty" validate:"required_without=Name,excluded_with=Name" example:"app=api"`
+}
+
+// k8sMaxResponseSize bounds the API responses read, a pod list carries the
+// full pod specs
+const k8sMaxResponseSize = 16 << 20
+
+// k8sNamespaceRegex and k8sNameRegex match the DNS label and subdomain names
+// Kubernetes accepts
+var (
+	k8sNamespaceRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	k8sNameRegex      = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+)
+
+type K8sExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewK8sExecutor(logger *zap.SugaredLogger) *K8sExecutor {
+	return &K8sExecutor{
+		logger: logger,
+	}
+}
+
+func (k *K8sExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[K8sConfig](configJSON)
+}
+
+// ClientCertificates returns the client certificate of the kubeconfig context
+func (k *K8sExecutor) ClientCertificates(cfg any) []ClientCertificate {
+	k8sCfg := cfg.(*K8sConfig)
+	if k8sCfg.Kubeconfig == "" {
+		return nil
+	}
+	cert := kubeconfigClientCertificate(k8sCfg.Kubeconfig, k8sCfg.Context)
+	if cert == "" {
+		return nil
+	}
+	return []ClientCertificate{{Field: "kubeconfig", PEM: cert}}
+}
+
+func (k *K8sExecutor) Validate(configJSON string) error {
+	cfg, err := k.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	k8sCfg := cfg.(*K8sConfig)
+
+	if err := GenericValidator(k8sCfg); err != nil {
+		return err
+	}
+
+	connections := 0
+	for _, set := range []bool{k8sCfg.InCluster, k8sCfg.Kubeconfig != "", k8sCfg.APIServer != ""} {
+		if set {
+			connections++
+		}
+	}
+	if connections != 1 {
+		return fmt.Errorf("exactly one of in_cluster, kubeconfig and api_server must be set")
+	}
+
+	if k8sCfg.APIServer != "" {
+		if !strings.HasPrefix(k8sCfg.APIServer, "https://") && !strings.HasPrefix(k8sCfg.APIServer, "http://") {
+			return fmt.Errorf("api_server must be an http or https URL")
+		}
+		if k8sCfg.Token == "" {
+			return fmt.Errorf("token is required with api_server")
+		}
+		if k8sCfg.CACert != "" {
+			if _, err := k8sCertPool([]byte(k8sCfg.CACert)); err != nil {
+				return err
+			}
+		}
+	}
+	if k8sCfg.Kubeconfig != "" {
+		if _, err := kubeconfigConnection(k8sCfg.Kubeconfig, k8sCfg.Context); err != nil {
+			return err
+		}
+	}
+
+	if !k8sNamespaceRegex.MatchString(k8sCfg.Namespace) {
+		return fmt.Errorf("invalid namespace %q", k8sCfg.Namespace)
+	}
+	if k8sCfg.Name != "" && !k8sNameRegex.MatchString(k8sCfg.Name) {
+		return fmt.Errorf("invalid name %q", k8sCfg.Name)
+	}
+
+	return nil
+}
+
+// connection resolves the API server and credentials of the config
+func (k *K8sExecutor) connection(cfg *K8sConfig) (*k8sConnection, error) {
+	switch {
+	case cfg.InCluster:
+		return inClusterConnection()
+	case cfg.Kubeconfig != "":
+		return kubeconfigConnection(cfg.Kubeconfig, cfg.Context)
+	case cfg.APIServer != "":
+		conn := &k8sConnection{
+			server:    strings.TrimSuffix(cfg.APIServer, "/"),
+			token:     cfg.Token,
+			tlsConfig: &tls.Config{InsecureSkipVerify: cfg.IgnoreTlsErrors},
+		}
+		if cfg.CACert != "" {
+			roots, err := k8sCertPool([]byte(cfg.CACert))
+			if err != nil {
+				return nil, err
+			}
+			conn.tlsConfig.RootCAs = roots
+		}
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("no Kubernetes connection configured")
+	}
+}
+
+func (k *K8sExecutor) Execute(ctx context.Context, monitor *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := k.Unmarshal(monitor.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*K8sConfig)
+
+	k.logger.Debugf("execute kubernetes %s namespace: %s, name: %s, selector: %s", cfg.Resource, cfg.Namespace, cfg.Name, cfg.Selector)
+
+	startTime := time.Now().UTC()
+
+	conn, err := k.connection(cfg)
+	if err != nil {
+		return DownResult(fmt.Errorf("Kubernetes connection error: %w", err), startTime, time.Now().UTC())
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(monitor.Timeout)*time.Second)
+	defer cancel()
+
+	transport := &http.Transport{TLSClientConfig: conn.tlsConfig}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	var ready, desired int
+	switch cfg.Resource {
+	case "deployment":
+		ready, desired, err = k.deploymentReplicas(timeoutCtx, client, conn, cfg)
+	case "pod":
+		ready, desired, err = k.podReadiness(timeoutCtx, client, conn, cfg)
+	default:
+		err = fmt.Errorf("unknown Kubernetes resource: %s", cfg.Resource)
+	}
+	endTime := time.Now().UTC()
+
+	if err != nil {
+		k.logger.Infof("Kubernetes check failed: %s, %s", monitor.Name, err.Error())
+		return DownResult(err, startTime, endTime)
+	}
+
+	message := fmt.Sprintf("%s: %d/%d ready", k8sTarget(cfg), ready, desired)
+	if ready < desired {
+		k.logger.Infof("Kubernetes %s not ready: %s, %d/%d", cfg.Resource, monitor.Name, ready, desired)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// k8sTarget names the checked resources in the result message
+func k8sTarget(cfg *K8sConfig) string {
+	if cfg.Name != "" {
+		return fmt.Sprintf("%s %s/%s", cfg.Resource, cfg.Namespace, cfg.Name)
+	}
+	return fmt.Sprintf("%ss %s in %s", cfg.Resource, cfg.Selector, cfg.Namespace)
+}
+
+type k8sDeployment struct {
+	Spec struct {
+		// Replicas defaults to 1 when unset
+		Replicas *int `json:"replicas"`
+	} `json:"spec"`
+	Status struct {
+		ReadyReplicas int `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+type k8sPod struct {
+	Status struct {
+		Phase      string `json:"phase"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// deploymentReplicas returns the ready and desired replicas of the deployment,
+// summed over the deployments matching the selector
+func (k *K8sExecutor) deploymentReplicas(ctx context.Context, client *http.Client, conn *k8sConnection, cfg *K8sConfig) (int, int, error) {
+	var deployments []k8sDeployment
+	path := fmt.Sprintf("/apis/apps/v1/namespaces/%s/deployments", url.PathEscape(cfg.Namespace))
+	if cfg.Name != "" {
+		var deployment k8sDeployment
+		if err := k.get(ctx, client, conn, path+"/"+url.PathEscape(cfg.Name), nil, &deployment); err != nil {
+			return 0, 0, err
+		}
+		deployments = append(deployments, deployment)
+	} else {
+		var list struct {
+			Items []k8sDeployment `json:"items"`
+		}
+		if err := k.get(ctx, client, conn, path, url.Values{"labelSelector": {cfg.Selector}}, &list); err != nil {
+			return 0, 0, err
+		}
+		if len(list.Items) == 0 {
+			return 0, 0, fmt.Errorf("no deployments match selector %s in %s", cfg.Selector, cfg.Namespace)
+		}
+		deployments = list.Items
+	}
+
+	ready, desired := 0, 0
+	for _, deployment := range deployments {
+		replicas := 1
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		desired += replicas
+		ready += deployment.Status.ReadyReplicas
+	}
+	return ready, desired, nil
+}
+
+// podReadiness returns the ready pods and the pods expected to be ready, the
+// pods that ran to completion are left out
+func (k *K8sExecutor) podReadiness(ctx context.Context, client *http.Client, conn *k8sConnection, cfg *K8sConfig) (int, int, error) {
+	var pods []k8sPod
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(cfg.Namespace))
+	if cfg.Name != "" {
+		var pod k8sPod
+		if err := k.get(ctx, client, conn, path+"/"+url.PathEscape(cfg.Name), nil, &pod); err != nil {
+			return 0, 0, err
+		}
+		pods = append(pods, pod)
+	} else {
+		var list struct {
+			Items []k8sPod `json:"items"`
+		}
+		if err := k.get(ctx, client, conn, path, url.Values{"labelSelector": {cfg.Selector}}, &list); err != nil {
+			return 0, 0, err
+		}
+		pods = list.Items
+	}
+
+	ready, desired := 0, 0
+	for _, pod := range pods {
+		if pod.Status.Phase == "Succeeded" {
+			continue
+		}
+		desired++
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == "Ready" && condition.Status == "True" {
+				ready++
+				break
+			}
+		}
+	}
+	if desired == 0 {
+		return 0, 0, fmt.Errorf("no running pods match %s", k8sTarget(cfg))
+	}
+	return ready, desired, nil
+}
+
+// get decodes the API object at the path, an error status carries the
+// message of the returned Status object
+func (k *K8sExecutor) get(ctx context.Context, client *http.Client, conn *k8sConnection, path string, query url.Values, out any) error {
+	target := conn.server + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	conn.authorize(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Kubernetes API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body := io.LimitReader(resp.Body, k8sMaxResponseSize)
+	if resp.StatusCode != http.StatusOK {
+		var status struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(body).Decode(&status); err == nil && status.Message != "" {
+			return fmt.Errorf("Kubernetes API returned %d: %s", resp.StatusCode, status.Message)
+		}
+		return fmt.Errorf("Kubernetes API returned %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Kubernetes API response: %w", err)
+	}
+	return nil
+}
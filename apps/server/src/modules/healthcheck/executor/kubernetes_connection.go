@@ -0,0 +1,237 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// k8sServiceAccountDir holds the token and CA mounted into pods, used for the
+// in-cluster connection
+var k8sServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// k8sConnection is the resolved API server, credentials and TLS settings
+type k8sConnection struct {
+	server    string
+	token     string
+	username  string
+	password  string
+	tlsConfig *tls.Config
+}
+
+// authorize sets the credentials of the connection on the request
+func (c *k8sConnection) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// kubeconfig is the subset of the kubeconfig file the executor understands
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string            `yaml:"name"`
+		Cluster kubeconfigCluster `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string         `yaml:"name"`
+		User kubeconfigUser `yaml:"user"`
+	} `yaml:"users"`
+}
+
+type kubeconfigCluster struct {
+	Server                   string `yaml:"server"`
+	CertificateAuthority     string `yaml:"certificate-authority"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+	TLSServerName            string `yaml:"tls-server-name"`
+}
+
+type kubeconfigUser struct {
+	Token                 string         `yaml:"token"`
+	TokenFile             string         `yaml:"tokenFile"`
+	Username              string         `yaml:"username"`
+	Password              string         `yaml:"password"`
+	ClientCertificate     string         `yaml:"client-certificate"`
+	ClientCertificateData string         `yaml:"client-certificate-data"`
+	ClientKey             string         `yaml:"client-key"`
+	ClientKeyData         string         `yaml:"client-key-data"`
+	Exec                  map[string]any `yaml:"exec"`
+	AuthProvider          map[string]any `yaml:"auth-provider"`
+}
+
+// kubeconfigClientCertificate returns the PEM client certificate of the
+// context, empty when it authenticates otherwise
+func kubeconfigClientCertificate(content, contextName string) string {
+	_, user, err := kubeconfigEntries(content, contextName)
+	if err != nil || user == nil {
+		return ""
+	}
+	cert, err := base64.StdEncoding.DecodeString(user.ClientCertificateData)
+	if err != nil {
+		return ""
+	}
+	return string(cert)
+}
+
+// kubeconfigEntries returns the cluster and user of the context, the current
+// context when none is given. The user is nil for a context without one.
+func kubeconfigEntries(content, contextName string) (*kubeconfigCluster, *kubeconfigUser, error) {
+	var config kubeconfig
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil, nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	if contextName == "" {
+		return nil, nil, fmt.Errorf("kubeconfig has no current-context, set context")
+	}
+
+	var clusterName, userName string
+	found := false
+	for _, ctx := range config.Contexts {
+		if ctx.Name == contextName {
+			clusterName, userName, found = ctx.Context.Cluster, ctx.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	var cluster *kubeconfigCluster
+	for i := range config.Clusters {
+		if config.Clusters[i].Name == clusterName {
+			cluster = &config.Clusters[i].Cluster
+			break
+		}
+	}
+	if cluster == nil {
+		return nil, nil, fmt.Errorf("cluster %q not found in kubeconfig", clusterName)
+	}
+
+	var user *kubeconfigUser
+	for i := range config.Users {
+		if config.Users[i].Name == userName {
+			user = &config.Users[i].User
+			break
+		}
+	}
+	if user == nil && userName != "" {
+		return nil, nil, fmt.Errorf("user %q not found in kubeconfig", userName)
+	}
+	return cluster, user, nil
+}
+
+// kubeconfigConnection resolves the connection of a kubeconfig context. Only
+// embedded credentials are supported, paths on the server and credential
+// plugins are rejected.
+func kubeconfigConnection(content, contextName string) (*k8sConnection, error) {
+	cluster, user, err := kubeconfigEntries(content, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Server == "" {
+		return nil, fmt.Errorf("kubeconfig cluster has no server")
+	}
+	if cluster.CertificateAuthority != "" {
+		return nil, fmt.Errorf("kubeconfig certificate-authority paths are not supported, use certificate-authority-data")
+	}
+
+	conn := &k8sConnection{
+		server:    strings.TrimSuffix(cluster.Server, "/"),
+		tlsConfig: &tls.Config{InsecureSkipVerify: cluster.InsecureSkipTLSVerify, ServerName: cluster.TLSServerName},
+	}
+	if cluster.CertificateAuthorityData != "" {
+		ca, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig certificate-authority-data: %w", err)
+		}
+		if conn.tlsConfig.RootCAs, err = k8sCertPool(ca); err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		return conn, nil
+	}
+	switch {
+	case user.Exec != nil || user.AuthProvider != nil:
+		return nil, fmt.Errorf("kubeconfig credential plugins are not supported, use a token or client certificate")
+	case user.TokenFile != "" || user.ClientCertificate != "" || user.ClientKey != "":
+		return nil, fmt.Errorf("kubeconfig credential paths are not supported, use token or client-certificate-data and client-key-data")
+	}
+
+	conn.token, conn.username, conn.password = user.Token, user.Username, user.Password
+	if user.ClientCertificateData != "" || user.ClientKeyData != "" {
+		cert, err := base64.StdEncoding.DecodeString(user.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig client-certificate-data: %w", err)
+		}
+		key, err := base64.StdEncoding.DecodeString(user.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig client-key-data: %w", err)
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig client certificate: %w", err)
+		}
+		conn.tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	return conn, nil
+}
+
+// inClusterConnection uses the service account of the pod peekaping runs in
+func inClusterConnection() (*k8sConnection, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running inside a Kubernetes cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+
+	token, err := os.ReadFile(filepath.Join(k8sServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(k8sServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	roots, err := k8sCertPool(ca)
+	if err != nil {
+		return nil, err
+	}
+
+	return &k8sConnection{
+		server:    "https://" + net.JoinHostPort(host, port),
+		token:     strings.TrimSpace(string(token)),
+		tlsConfig: &tls.Config{RootCAs: roots},
+	}, nil
+}
+
+func k8sCertPool(ca []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to parse Kubernetes CA certificate")
+	}
+	return pool, nil
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestK8sExecutor_Validate(t *testing.T) {
+	executor := NewK8sExecutor(zap.NewNop().Sugar())
+	kubeconfig := testKubeconfig("https://k8s.example.com:6443", "", "secret")
+
+	tests := []struct {
+		name        string
+		configJSON  string
+		expectError bool
+	}{
+		{"api server deployment", `{"api_server": "https://k8s.example.com:6443", "token": "secret", "namespace": "default", "resource": "deployment", "name": "api"}`, false},
+		{"in cluster pods", `{"in_cluster": true, "namespace": "payments", "resource": "pod", "selector": "app=api,tier=backend"}`, false},
+		{"kubeconfig", fmt.Sprintf(`{"kubeconfig": %q, "namespace": "default", "resource": "deployment", "selector": "app=api"}`, kubeconfig), false},
+		{"kubeconfig unknown context", fmt.Sprintf(`{"kubeconfig": %q, "context": "staging", "namespace": "default", "resource": "deployment", "name": "api"}`, kubeconfig), true},
+		{"kubeconfig exec plugin", fmt.Sprintf(`{"kubeconfig": %q, "namespace": "default", "resource": "deployment", "name": "api"}`, strings.Replace(kubeconfig, "token: secret", "exec: {command: aws}", 1)), true},
+		{"no connection", `{"namespace": "default", "resource": "deployment", "name": "api"}`, true},
+		{"two connections", `{"in_cluster": true, "api_server": "https://k8s.example.com", "token": "secret", "namespace": "default", "resource": "deployment", "name": "api"}`, true},
+		{"api server without token", `{"api_server": "https://k8s.example.com", "namespace": "default", "resource": "deployment", "name": "api"}`, true},
+		{"invalid ca cert", `{"api_server": "https://k8s.example.com", "token": "secret", "ca_cert": "not a certificate", "namespace": "default", "resource": "deployment", "name": "api"}`, true},
+		{"missing namespace", `{"in_cluster": true, "resource": "deployment", "name": "api"}`, true},
+		{"invalid namespace", `{"in_cluster": true, "namespace": "Default", "resource": "deployment", "name": "api"}`, true},
+		{"unknown resource", `{"in_cluster": true, "namespace": "default", "resource": "statefulset", "name": "api"}`, true},
+		{"name and selector", `{"in_cluster": true, "namespace": "default", "resource": "pod", "name": "api-0", "selector": "app=api"}`, true},
+		{"neither name nor selector", `{"in_cluster": true, "namespace": "default", "resource": "pod"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.configJSON)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newFakeK8sAPI serves the objects by path to requests with the bearer token
+func newFakeK8sAPI(t *testing.T, objects map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"kind":"Status","message":"Unauthorized"}`))
+			return
+		}
+		key := r.URL.Path
+		if selector := r.URL.Query().Get("labelSelector"); selector != "" {
+			key += "?" + selector
+		}
+		object, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(object)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testKubeconfig(server, caPEM, token string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: production
+clusters:
+- name: production
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: monitor
+users:
+- name: monitor
+  user:
+    token: %s
+`, server, base64.StdEncoding.EncodeToString([]byte(caPEM)), token)
+}
+
+func readyPod(ready bool, phase string) map[string]any {
+	status := "False"
+	if ready {
+		status = "True"
+	}
+	return map[string]any{"status": map[string]any{
+		"phase":      phase,
+		"conditions": []map[string]string{{"type": "Ready", "status": status}},
+	}}
+}
+
+func TestK8sExecutor_Execute(t *testing.T) {
+	server := newFakeK8sAPI(t, map[string]any{
+		"/apis/apps/v1/namespaces/default/deployments/api": map[string]any{
+			"spec":   map[string]any{"replicas": 3},
+			"status": map[string]any{"readyReplicas": 3},
+		},
+		"/apis/apps/v1/namespaces/default/deployments/worker": map[string]any{
+			"spec":   map[string]any{"replicas": 3},
+			"status": map[string]any{"readyReplicas": 1},
+		},
+		"/apis/apps/v1/namespaces/default/deployments?tier=backend": map[string]any{"items": []map[string]any{
+			{"spec": map[string]any{"replicas": 2}, "status": map[string]any{"readyReplicas": 2}},
+			{"spec": map[string]any{}, "status": map[string]any{"readyReplicas": 1}},
+		}},
+		"/api/v1/namespaces/default/pods?app=api": map[string]any{"items": []map[string]any{
+			readyPod(true, "Running"),
+			readyPod(false, "Running"),
+			readyPod(false, "Succeeded"),
+		}},
+		"/api/v1/namespaces/default/pods?app=none": map[string]any{"items": []map[string]any{}},
+	})
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	executor := NewK8sExecutor(zap.NewNop().Sugar())
+
+	apiServer := func(target string) string {
+		return fmt.Sprintf(`{"api_server": %q, "token": "secret", "ca_cert": %q, "namespace": "default", %s}`, server.URL, caPEM, target)
+	}
+	monitor := func(config string) *Monitor {
+		return &Monitor{ID: "k8s", Name: "k8s", Type: "kubernetes", Timeout: 5, Config: config}
+	}
+
+	tests := []struct {
+		name           string
+		config         string
+		expectedStatus shared.MonitorStatus
+		expectedMsg    string
+	}{
+		{"deployment ready", apiServer(`"resource": "deployment", "name": "api"`), shared.MonitorStatusUp, "deployment default/api: 3/3 ready"},
+		{"deployment not ready", apiServer(`"resource": "deployment", "name": "worker"`), shared.MonitorStatusDown, "deployment default/worker: 1/3 ready"},
+		{"deployments by selector", apiServer(`"resource": "deployment", "selector": "tier=backend"`), shared.MonitorStatusUp, "deployments tier=backend in default: 3/3 ready"},
+		{"missing deployment", apiServer(`"resource": "deployment", "name": "missing"`), shared.MonitorStatusDown, "Kubernetes API returned 404: not found"},
+		{"pods by selector", apiServer(`"resource": "pod", "selector": "app=api"`), shared.MonitorStatusDown, "pods app=api in default: 1/2 ready"},
+		{"no matching pods", apiServer(`"resource": "pod", "selector": "app=none"`), shared.MonitorStatusDown, "no running pods match"},
+		{"kubeconfig", fmt.Sprintf(`{"kubeconfig": %q, "namespace": "default", "resource": "deployment", "name": "api"}`, testKubeconfig(server.URL, caPEM, "secret")), shared.MonitorStatusUp, "3/3 ready"},
+		{"kubeconfig wrong token", fmt.Sprintf(`{"kubeconfig": %q, "namespace": "default", "resource": "deployment", "name": "api"}`, testKubeconfig(server.URL, caPEM, "other")), shared.MonitorStatusDown, "Kubernetes API returned 401: Unauthorized"},
+		{"untrusted server", fmt.Sprintf(`{"api_server": %q, "token": "secret", "namespace": "default", "resource": "deployment", "name": "api"}`, server.URL), shared.MonitorStatusDown, "Kubernetes API request failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), monitor(tt.config), nil)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
+
+func TestK8sExecutor_Execute_InClusterOutsideCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	executor := NewK8sExecutor(zap.NewNop().Sugar())
+
+	result := executor.Execute(context.Background(), &Monitor{
+		ID:      "k8s",
+		Timeout: 5,
+		Config:  `{"in_cluster": true, "namespace": "default", "resource": "deployment", "name": "api"}`,
+	}, nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "not running inside a Kubernetes cluster")
+}
@@ -37,6 +37,12 @@ func (m *MongoDBExecutor) Unmarshal(configJSON string) (any, error) {
 	return GenericUnmarshal[MongoDBConfig](configJSON)
 }
 
+// ReachabilityAddress returns the host of a single-host connection string,
+// mongodb+srv hosts are resolved by the driver and have no fixed port
+func (m *MongoDBExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	return urlAddress(cfg.(*MongoDBConfig).ConnectionString, map[string]string{"mongodb": "27017"})
+}
+
 func (m *MongoDBExecutor) Validate(configJSON string) error {
 	cfg, err := m.Unmarshal(configJSON)
 	if err != nil {
@@ -178,6 +184,7 @@ func (m *MongoDBExecutor) runMongoDBCommand(ctx context.Context, connectionStrin
 	clientOptions := options.Client().ApplyURI(enhancedConnectionString)
 	clientOptions.SetConnectTimeout(timeout)
 	clientOptions.SetSocketTimeout(timeout)
+	clientOptions.SetServerSelectionTimeout(timeout)
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
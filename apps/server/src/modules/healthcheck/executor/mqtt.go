@@ -20,7 +20,7 @@ type MQTTConfig struct {
 	Topic          string `json:"topic" validate:"required" example:"test/topic"`
 	Username       string `json:"username" example:"user"`
 	Password       string `json:"password" example:"password"`
-	CheckType      string `json:"check_type" validate:"oneof=keyword json-query none" example:"keyword"`
+	CheckType      string `json:"check_type" validate:"oneof=keyword json-query none connect" example:"keyword"`
 	SuccessKeyword string `json:"success_keyword" example:"success"`
 	JsonPath       string `json:"json_path" example:"$.status"`
 	ExpectedValue  string `json:"expected_value" example:"ok"`
@@ -93,6 +93,8 @@ func (m *MQTTExecutor) Execute(ctx context.Context, monitor *Monitor, proxyModel
 		"username": cfg.Username,
 		"password": cfg.Password,
 		"timeout":  time.Duration(monitor.Timeout) * time.Second,
+		// "connect" only needs the broker to acknowledge connect and subscribe
+		"wait_for_message": cfg.CheckType != "connect",
 	})
 
 	endTime := time.Now().UTC()
@@ -108,7 +110,14 @@ func (m *MQTTExecutor) Execute(ctx context.Context, monitor *Monitor, proxyModel
 	}
 
 	// Perform check based on check type
-	if cfg.CheckType == "none" {
+	if cfg.CheckType == "connect" {
+		return &Result{
+			Status:    shared.MonitorStatusUp,
+			Message:   fmt.Sprintf("Topic: %s; Connected and subscribed", cfg.Topic),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	} else if cfg.CheckType == "none" {
 		// For "none" check type, any received message is considered success
 		if receivedMessage != "" {
 			return &Result{
@@ -236,6 +245,10 @@ func (m *MQTTExecutor) mqttAsync(ctx context.Context, hostname, topic string, op
 	username, _ := options["username"].(string)
 	password, _ := options["password"].(string)
 	timeout, _ := options["timeout"].(time.Duration)
+	waitForMessage, ok := options["wait_for_message"].(bool)
+	if !ok {
+		waitForMessage = true
+	}
 
 	if timeout == 0 {
 		timeout = 20 * time.Second
@@ -305,6 +318,11 @@ func (m *MQTTExecutor) mqttAsync(ctx context.Context, hostname, topic string, op
 
 	m.logger.Debugf("MQTT subscribed to topic %s", topic)
 
+	if !waitForMessage {
+		client.Disconnect(100)
+		return "", nil
+	}
+
 	// Wait for message or timeout
 	timeoutTimer := time.NewTimer(time.Duration(float64(timeout) * 0.8))
 	defer timeoutTimer.Stop()
@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// hasMySQLCertificates tells whether the config carries certificates that
+// need a custom TLS config
+func hasMySQLCertificates(cfg *MySQLConfig) bool {
+	return cfg.CaCert != "" || cfg.ClientCert != "" || cfg.ClientKey != ""
+}
+
+// validateMySQLTLS checks the TLS settings without registering them
+func validateMySQLTLS(cfg *MySQLConfig) error {
+	if cfg.TLS != "" || hasMySQLCertificates(cfg) {
+		if u, err := url.Parse(cfg.ConnectionString); err == nil && u.Query().Has("tls") {
+			return fmt.Errorf("set tls either in the connection string or in the tls field")
+		}
+	}
+	if !hasMySQLCertificates(cfg) {
+		return nil
+	}
+	if cfg.TLS == "preferred" {
+		return fmt.Errorf("tls preferred cannot be combined with certificates")
+	}
+	_, err := mysqlTLSConfig(cfg)
+	return err
+}
+
+// mysqlTLSConfig builds the TLS config of the connection from the certificates
+func mysqlTLSConfig(cfg *MySQLConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.TLS == "skip-verify"}
+
+	caCertPool, err := loadCACertPool(cfg.CaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA certificate: %w", err)
+	}
+	tlsConfig.RootCAs = caCertPool
+
+	clientCert, err := loadClientCertificate(cfg.ClientCert, cfg.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	return tlsConfig, nil
+}
+
+// mysqlTLSParam returns the tls parameter of the DSN. Certificates are
+// registered with the driver under a name derived from them, so monitors
+// sharing certificates share the registration.
+func mysqlTLSParam(cfg *MySQLConfig) (string, error) {
+	if !hasMySQLCertificates(cfg) {
+		return cfg.TLS, nil
+	}
+
+	tlsConfig, err := mysqlTLSConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{cfg.TLS, cfg.CaCert, cfg.ClientCert, cfg.ClientKey}, "\x00")))
+	name := "peekaping-" + hex.EncodeToString(sum[:8])
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", fmt.Errorf("failed to register TLS config: %w", err)
+	}
+	return name, nil
+}
+
+// withMySQLTLS appends the tls parameter to the DSN when TLS is configured
+func withMySQLTLS(dsn string, cfg *MySQLConfig) (string, error) {
+	param, err := mysqlTLSParam(cfg)
+	if err != nil || param == "" {
+		return dsn, err
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + "tls=" + url.QueryEscape(param), nil
+}
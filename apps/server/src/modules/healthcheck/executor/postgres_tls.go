@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// postgresSSLModes are the sslmode values of libpq the executor supports
+var postgresSSLModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// validatePostgresTLS checks the sslmode of the connection string against the
+// certificates of the config
+func validatePostgresTLS(sslMode string, cfg *PostgresConfig) error {
+	if !postgresSSLModes[sslMode] {
+		return fmt.Errorf("unsupported sslmode %q", sslMode)
+	}
+	if (sslMode == "verify-ca" || sslMode == "verify-full") && cfg.CaCert == "" {
+		return fmt.Errorf("sslmode %s requires a CA certificate", sslMode)
+	}
+	if sslMode == "disable" && (cfg.CaCert != "" || cfg.ClientCert != "" || cfg.ClientKey != "") {
+		return fmt.Errorf("certificates require an sslmode other than disable")
+	}
+	_, err := postgresTLSConfig(sslMode, "localhost", cfg)
+	return err
+}
+
+// postgresTLSConfig builds the TLS config of the connection the way libpq
+// applies sslmode, nil for disable. As in libpq, require with a CA
+// certificate verifies the chain like verify-ca.
+func postgresTLSConfig(sslMode, host string, cfg *PostgresConfig) (*tls.Config, error) {
+	if sslMode == "disable" {
+		return nil, nil
+	}
+
+	rootCAs, err := loadCACertPool(cfg.CaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{ServerName: host, RootCAs: rootCAs}
+
+	clientCert, err := loadClientCertificate(cfg.ClientCert, cfg.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	switch sslMode {
+	case "allow", "prefer":
+		tlsConfig.InsecureSkipVerify = true
+	case "require":
+		tlsConfig.InsecureSkipVerify = true
+		if rootCAs != nil {
+			tlsConfig.VerifyPeerCertificate = verifyPostgresChain(rootCAs)
+		}
+	case "verify-ca":
+		// the chain is verified but not the host name, tls.Config has no
+		// setting for it
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyPostgresChain(rootCAs)
+	case "verify-full":
+	default:
+		return nil, fmt.Errorf("unsupported sslmode %q", sslMode)
+	}
+	return tlsConfig, nil
+}
+
+// verifyPostgresChain verifies the server certificate chain against the roots
+// without checking the host name
+func verifyPostgresChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("server sent no certificate")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return fmt.Errorf("failed to parse server certificate: %w", err)
+			}
+			certs = append(certs, cert)
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err
+	}
+}
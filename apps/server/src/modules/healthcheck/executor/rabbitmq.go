@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"peekaping/src/modules/shared"
@@ -20,6 +21,10 @@ type RabbitMQConfig struct {
 	Password string   `json:"password" validate:"required" example:"password"`
 }
 
+// rabbitMQDefaultManagementPort is used when an http node URL does not
+// specify a port, an https URL keeps the default port of https
+const rabbitMQDefaultManagementPort = "15672"
+
 type RabbitMQExecutor struct {
 	logger *zap.SugaredLogger
 	client *http.Client
@@ -46,9 +51,13 @@ func (r *RabbitMQExecutor) Validate(configJSON string) error {
 
 	// Validate each node URL
 	for _, nodeURL := range rabbitCfg.Nodes {
-		if _, err := url.Parse(nodeURL); err != nil {
+		parsedURL, err := url.Parse(nodeURL)
+		if err != nil {
 			return fmt.Errorf("invalid node URL %s: %w", nodeURL, err)
 		}
+		if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+			return fmt.Errorf("invalid node URL %s: scheme must be http or https", nodeURL)
+		}
 	}
 
 	return GenericValidator(rabbitCfg)
@@ -87,7 +96,7 @@ func (r *RabbitMQExecutor) Execute(ctx context.Context, monitor *Monitor, proxyM
 		}
 
 		// Ensure trailing slash for proper URL joining
-		baseURL := nodeURL
+		baseURL := withDefaultManagementPort(nodeURL)
 		if !strings.HasSuffix(baseURL, "/") {
 			baseURL += "/"
 		}
@@ -168,6 +177,16 @@ func (r *RabbitMQExecutor) checkNode(ctx context.Context, healthURL, username, p
 	// Handle response based on status code
 	switch resp.StatusCode {
 	case 200:
+		// The alarms check answers {"status":"ok"} when the node is healthy
+		var okResp struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(body, &okResp); err != nil || okResp.Status == "" {
+			return false, "Unexpected response", fmt.Errorf("RabbitMQ health check failed: response carries no status")
+		}
+		if okResp.Status != "ok" {
+			return false, okResp.Status, fmt.Errorf("RabbitMQ health check failed: node reported status %s", okResp.Status)
+		}
 		return true, "OK", nil
 	case 503:
 		// Parse error message from response
@@ -182,3 +201,13 @@ func (r *RabbitMQExecutor) checkNode(ctx context.Context, healthURL, username, p
 		return false, fmt.Sprintf("%d - %s", resp.StatusCode, resp.Status), fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 }
+
+// withDefaultManagementPort adds the RabbitMQ management port to http node URLs without an explicit port
+func withDefaultManagementPort(nodeURL string) string {
+	parsedURL, err := url.Parse(nodeURL)
+	if err != nil || parsedURL.Host == "" || parsedURL.Port() != "" || parsedURL.Scheme == "https" {
+		return nodeURL
+	}
+	parsedURL.Host = net.JoinHostPort(parsedURL.Hostname(), rabbitMQDefaultManagementPort)
+	return parsedURL.String()
+}
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRabbitMQExecutor_Validate(t *testing.T) {
+	executor := NewRabbitMQExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid config", config: `{"nodes": ["https://rabbit.example.com:15672"], "username": "guest", "password": "guest"}`},
+		{name: "missing credentials", config: `{"nodes": ["https://rabbit.example.com:15672"]}`, wantError: true},
+		{name: "no nodes", config: `{"nodes": [], "username": "guest", "password": "guest"}`, wantError: true},
+		{name: "unsupported scheme", config: `{"nodes": ["amqp://rabbit.example.com:5672"], "username": "guest", "password": "guest"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithDefaultManagementPort(t *testing.T) {
+	assert.Equal(t, "http://rabbit.example.com:15672", withDefaultManagementPort("http://rabbit.example.com"))
+	assert.Equal(t, "https://rabbit.example.com", withDefaultManagementPort("https://rabbit.example.com"))
+	assert.Equal(t, "http://rabbit.example.com:15672/prefix/", withDefaultManagementPort("http://rabbit.example.com/prefix/"))
+	assert.Equal(t, "https://rabbit.example.com:443", withDefaultManagementPort("https://rabbit.example.com:443"))
+}
+
+func TestRabbitMQExecutor_Execute(t *testing.T) {
+	executor := NewRabbitMQExecutor(zap.NewNop().Sugar())
+
+	body := `{"status":"ok"}`
+	code := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "guest" || pass != "guest" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/health/checks/alarms/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		code           int
+		body           string
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{name: "healthy node", code: http.StatusOK, body: `{"status":"ok"}`, expectedStatus: shared.MonitorStatusUp, messageContain: "OK"},
+		{name: "alarm in effect", code: http.StatusServiceUnavailable, body: `{"status":"failed","reason":"resource alarm(s) in effect"}`, expectedStatus: shared.MonitorStatusDown, messageContain: "resource alarm(s) in effect"},
+		{name: "unexpected status field", code: http.StatusOK, body: `{"status":"failed"}`, expectedStatus: shared.MonitorStatusDown, messageContain: "node reported status failed"},
+		{name: "empty status", code: http.StatusOK, body: `{}`, expectedStatus: shared.MonitorStatusDown, messageContain: "response carries no status"},
+		{name: "unparseable body", code: http.StatusOK, body: `<html>login</html>`, expectedStatus: shared.MonitorStatusDown, messageContain: "response carries no status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body = tt.code, tt.body
+			monitor := &Monitor{
+				ID:      "monitor1",
+				Type:    "rabbitmq",
+				Name:    "Test RabbitMQ",
+				Timeout: 5,
+				Config:  `{"nodes": ["` + server.URL + `"], "username": "guest", "password": "guest"}`,
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
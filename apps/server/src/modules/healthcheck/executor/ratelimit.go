@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// probeBucketIdleTTL is how long an unused per-host bucket is kept before it is pruned
+const probeBucketIdleTTL = 5 * time.Minute
+
+// tokenBucket is a reservation based token bucket. Reservations may drive the
+// balance negative, in which case the caller waits until it is paid back, so
+// concurrent callers are spaced out instead of racing for the next token.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: 1, tokens: 1, last: now}
+}
+
+// reserve takes one token and returns how long the caller has to wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// idle reports whether the bucket is full and has not been used for ttl
+func (b *tokenBucket) idle(now time.Time, ttl time.Duration) bool {
+	return b.tokens >= 0 && now.Sub(b.last) > ttl
+}
+
+// ProbeRateLimiter caps the outbound check rate per target host and across all monitors
+type ProbeRateLimiter struct {
+	mu         sync.Mutex
+	perHostRPS float64
+	global     *tokenBucket
+	hosts      map[string]*tokenBucket
+	lastPrune  time.Time
+	now        func() time.Time
+}
+
+// NewProbeRateLimiter creates a limiter, a zero rate disables the respective limit
+func NewProbeRateLimiter(perHostRPS, globalRPS float64) *ProbeRateLimiter {
+	l := &ProbeRateLimiter{
+		perHostRPS: perHostRPS,
+		hosts:      make(map[string]*tokenBucket),
+		now:        time.Now,
+	}
+	l.lastPrune = l.now()
+	if globalRPS > 0 {
+		l.global = newTokenBucket(globalRPS, l.now())
+	}
+	return l
+}
+
+// Enabled reports whether any limit is configured
+func (l *ProbeRateLimiter) Enabled() bool {
+	return l != nil && (l.perHostRPS > 0 || l.global != nil)
+}
+
+// reserve books a slot for host and returns the delay until the slot starts
+func (l *ProbeRateLimiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var delay time.Duration
+
+	if l.global != nil {
+		delay = l.global.reserve(now)
+	}
+
+	if l.perHostRPS > 0 && host != "" {
+		bucket, ok := l.hosts[host]
+		if !ok {
+			bucket = newTokenBucket(l.perHostRPS, now)
+			l.hosts[host] = bucket
+		}
+		// The host slot starts no earlier than the global one, booking it at
+		// now would let a backed up global bucket hide the host spacing
+		delay += bucket.reserve(now.Add(delay))
+	}
+
+	if now.Sub(l.lastPrune) > probeBucketIdleTTL {
+		for h, bucket := range l.hosts {
+			if bucket.idle(now, probeBucketIdleTTL) {
+				delete(l.hosts, h)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	return delay
+}
+
+// Wait blocks until a check against host is allowed and returns how long it was delayed
+func (l *ProbeRateLimiter) Wait(ctx context.Context, host string) (time.Duration, error) {
+	delay := l.reserve(host)
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return delay, fmt.Errorf("check throttled by probe rate limit: %w", ctx.Err())
+	case <-timer.C:
+		return delay, nil
+	}
+}
+
+// probeTargetHost extracts the target host from common monitor config fields
+func probeTargetHost(configJSON string) string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(configJSON), &fields); err != nil {
+		return ""
+	}
+
+	if raw, ok := fields["url"].(string); ok && raw != "" {
+		if parsed, err := url.Parse(raw); err == nil && parsed.Hostname() != "" {
+			return strings.ToLower(parsed.Hostname())
+		}
+	}
+
+	for _, key := range []string{"host", "hostname"} {
+		raw, ok := fields[key].(string)
+		if !ok || raw == "" {
+			continue
+		}
+		if host, _, err := net.SplitHostPort(raw); err == nil {
+			return strings.ToLower(host)
+		}
+		return strings.ToLower(raw)
+	}
+
+	return ""
+}
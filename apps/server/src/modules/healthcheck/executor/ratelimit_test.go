@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	start := time.Now()
+	bucket := newTokenBucket(2, start)
+
+	// the first token is available immediately, the next ones are spaced by 1/rate
+	assert.Equal(t, time.Duration(0), bucket.reserve(start))
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(start))
+	assert.Equal(t, time.Second, bucket.reserve(start))
+
+	// after the backlog is paid back tokens refill up to the burst only
+	later := start.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), bucket.reserve(later))
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(later))
+}
+
+func TestProbeRateLimiter_PerHostRateNeverExceeded(t *testing.T) {
+	const rps = 20
+	limiter := NewProbeRateLimiter(rps, 0)
+
+	var mu sync.Mutex
+	var hits []time.Time
+	var wg sync.WaitGroup
+
+	// 60 monitors hitting the same host at once, plus unrelated traffic on another host
+	for i := 0; i < 60; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := limiter.Wait(context.Background(), "shared.example.com")
+			require.NoError(t, err)
+			mu.Lock()
+			hits = append(hits, time.Now())
+			mu.Unlock()
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delay, err := limiter.Wait(context.Background(), "other.example.com")
+			require.NoError(t, err)
+			assert.Less(t, delay, time.Second, "other hosts must not be throttled by the shared host")
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, hits, 60)
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Before(hits[j]) })
+
+	// no one second window may contain more than rps checks, allowing one for the burst token
+	for i := range hits {
+		count := 0
+		for j := i; j < len(hits) && hits[j].Sub(hits[i]) < time.Second; j++ {
+			count++
+		}
+		assert.LessOrEqual(t, count, rps+1, "window starting at hit %d", i)
+	}
+
+	// 60 checks at 20 rps take about 3 seconds
+	assert.GreaterOrEqual(t, hits[len(hits)-1].Sub(hits[0]), 2900*time.Millisecond)
+}
+
+func TestProbeRateLimiter_GlobalLimit(t *testing.T) {
+	limiter := NewProbeRateLimiter(0, 10)
+
+	_, err := limiter.Wait(context.Background(), "a.example.com")
+	require.NoError(t, err)
+	delay, err := limiter.Wait(context.Background(), "b.example.com")
+	require.NoError(t, err)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(delay), float64(10*time.Millisecond))
+}
+
+func TestProbeRateLimiter_PerHostSpacedBehindGlobalBacklog(t *testing.T) {
+	start := time.Now()
+	limiter := NewProbeRateLimiter(1, 10)
+	limiter.now = func() time.Time { return start }
+
+	// saturate the global bucket with other hosts, the next global slot is a second out
+	for i := 0; i < 10; i++ {
+		limiter.reserve("other.example.com")
+	}
+
+	// checks of one host issued together keep the host spacing after the global backlog
+	first := limiter.reserve("a.example.com")
+	second := limiter.reserve("a.example.com")
+	third := limiter.reserve("a.example.com")
+	assert.Equal(t, time.Second, first)
+	assert.GreaterOrEqual(t, second-first, time.Second)
+	assert.GreaterOrEqual(t, third-second, time.Second)
+}
+
+func TestProbeRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := NewProbeRateLimiter(1, 0)
+	_, err := limiter.Wait(context.Background(), "example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Wait(ctx, "example.com")
+	assert.ErrorContains(t, err, "throttled by probe rate limit")
+}
+
+func TestProbeTargetHost(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{`{"url": "https://API.example.com:8443/health"}`, "api.example.com"},
+		{`{"url": "wss://ws.example.com/socket"}`, "ws.example.com"},
+		{`{"host": "db.example.com", "port": 5432}`, "db.example.com"},
+		{`{"host": "10.0.0.1:161"}`, "10.0.0.1"},
+		{`{"hostname": "mail.example.com"}`, "mail.example.com"},
+		{`{"pushToken": "abc"}`, ""},
+		{`not json`, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, probeTargetHost(tt.config), tt.config)
+	}
+}
+
+func TestExecutorRegistry_WaitForProbeSlot(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	// without limits the wait is a no-op, also on a nil registry
+	var nilRegistry *ExecutorRegistry
+	delay, err := nilRegistry.WaitForProbeSlot(context.Background(), &Monitor{})
+	assert.NoError(t, err)
+	assert.Zero(t, delay)
+
+	registry := NewExecutorRegistry(logger, nil)
+	registry.limiter = NewProbeRateLimiter(5, 0)
+
+	m := &Monitor{Config: `{"url": "https://example.com"}`}
+	delay, err = registry.WaitForProbeSlot(context.Background(), m)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	delay, err = registry.WaitForProbeSlot(context.Background(), m)
+	require.NoError(t, err)
+	assert.Greater(t, delay, 150*time.Millisecond)
+
+	// monitors without a target host are not limited per host
+	delay, err = registry.WaitForProbeSlot(context.Background(), &Monitor{Config: `{}`})
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReachabilitySource is implemented by executors whose target is a single
+// host that a TCP connect can tell reachable or not. It returns the host:port
+// of the parsed config, false when there is no such address.
+type ReachabilitySource interface {
+	ReachabilityAddress(cfg any) (string, bool)
+}
+
+// ReachabilityAddress returns the address the light probe of a monitor
+// connects to, false when the executor does not support light probing
+func ReachabilityAddress(exec Executor, configJSON string) (string, bool) {
+	source, ok := exec.(ReachabilitySource)
+	if !ok {
+		return "", false
+	}
+	cfg, err := exec.Unmarshal(configJSON)
+	if err != nil {
+		return "", false
+	}
+	return source.ReachabilityAddress(cfg)
+}
+
+// ProbeReachability only opens and closes a TCP connection to the address, a
+// cheap check of whether a host that keeps timing out is reachable again
+func ProbeReachability(ctx context.Context, address string) *Result {
+	startTime := time.Now().UTC()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	endTime := time.Now().UTC()
+	if err != nil {
+		return DownResult(fmt.Errorf("%s unreachable: %w", address, err), startTime, endTime)
+	}
+	conn.Close()
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("%s reachable", address),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// urlAddress returns the host:port of a URL, the port defaults by scheme.
+// Connection strings listing several hosts have no single address.
+func urlAddress(raw string, defaultPorts map[string]string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || strings.Contains(u.Host, ",") {
+		return "", false
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[u.Scheme]
+	}
+	if port == "" {
+		return "", false
+	}
+	return net.JoinHostPort(u.Hostname(), port), true
+}
+
+func hostPortAddress(host string, port int) (string, bool) {
+	if host == "" || port <= 0 {
+		return "", false
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), true
+}
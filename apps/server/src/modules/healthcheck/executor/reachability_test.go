@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestReachabilityAddress(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name     string
+		exec     Executor
+		config   string
+		expected string
+		ok       bool
+	}{
+		{"https default port", NewHTTPExecutor(logger), `{"url":"https://example.com/health"}`, "example.com:443", true},
+		{"http explicit port", NewHTTPExecutor(logger), `{"url":"http://10.0.0.5:8080"}`, "10.0.0.5:8080", true},
+		{"tcp", NewTCPExecutor(logger), `{"host":"db.internal","port":5432}`, "db.internal:5432", true},
+		{"postgres default port", NewPostgresExecutor(logger), `{"database_connection_string":"postgres://u:p@db.internal/app"}`, "db.internal:5432", true},
+		{"mysql", NewMySQLExecutor(logger), `{"connection_string":"mysql://u:p@[::1]:3307/app"}`, "[::1]:3307", true},
+		{"mongodb srv", NewMongoDBExecutor(logger), `{"connectionString":"mongodb+srv://u:p@cluster.example.com/app"}`, "", false},
+		{"mongodb replica set", NewMongoDBExecutor(logger), `{"connectionString":"mongodb://a:27017,b:27017/app"}`, "", false},
+		{"broken config", NewTCPExecutor(logger), `{"host":`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, ok := ReachabilityAddress(tt.exec, tt.config)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, address)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// redisReadOnlyCommands are the commands a monitor may run, none of them
+// changes data on the server
+var redisReadOnlyCommands = map[string]bool{
+	"PING":      true,
+	"ECHO":      true,
+	"GET":       true,
+	"MGET":      true,
+	"STRLEN":    true,
+	"EXISTS":    true,
+	"TYPE":      true,
+	"TTL":       true,
+	"PTTL":      true,
+	"DBSIZE":    true,
+	"INFO":      true,
+	"TIME":      true,
+	"LASTSAVE":  true,
+	"ROLE":      true,
+	"HGET":      true,
+	"HLEN":      true,
+	"HEXISTS":   true,
+	"LLEN":      true,
+	"LINDEX":    true,
+	"SCARD":     true,
+	"SISMEMBER": true,
+	"ZCARD":     true,
+	"ZSCORE":    true,
+	"XLEN":      true,
+}
+
+// redisMessageLimit bounds the command reply quoted in result messages
+const redisMessageLimit = 200
+
+// parseRedisCommand splits the command into its arguments and checks it
+// against the read-only allowlist. Arguments are separated by whitespace,
+// quoting is not supported.
+func parseRedisCommand(command string) ([]any, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("command cannot be empty")
+	}
+
+	name := strings.ToUpper(fields[0])
+	if !redisReadOnlyCommands[name] {
+		return nil, fmt.Errorf("command %s is not allowed, only read-only commands can be used for monitoring", name)
+	}
+
+	args := make([]any, len(fields))
+	args[0] = name
+	for i, field := range fields[1:] {
+		args[i+1] = field
+	}
+	return args, nil
+}
+
+// formatRedisReply renders a command reply for comparison with the expected value
+func formatRedisReply(reply any) string {
+	switch v := reply.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatRedisReply(item)
+		}
+		return strings.Join(parts, " ")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
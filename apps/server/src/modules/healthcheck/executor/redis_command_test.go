@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startFakeRedis serves the RESP protocol, answering the commands in replies
+// and rejecting every other one
+func startFakeRedis(t *testing.T, replies map[string]string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, replies)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFakeRedis(conn net.Conn, replies map[string]string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(reader)
+		if err != nil {
+			return
+		}
+		reply, ok := replies[strings.ToUpper(strings.Join(args, " "))]
+		if !ok {
+			reply = "-ERR unknown command\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPArray(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func TestParseRedisCommand(t *testing.T) {
+	args, err := parseRedisCommand("  get   healthkey ")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"GET", "healthkey"}, args)
+
+	for _, command := range []string{"", "SET healthkey ok", "FLUSHALL", "CONFIG GET *", "EVAL return 1 0"} {
+		_, err := parseRedisCommand(command)
+		assert.Error(t, err, "expected %q to be rejected", command)
+	}
+}
+
+func TestRedisExecutor_Validate_Command(t *testing.T) {
+	executor := NewRedisExecutor(zap.NewNop().Sugar())
+
+	assert.NoError(t, executor.Validate(`{"databaseConnectionString":"redis://localhost:6379","command":"GET healthkey","expected_value":"ok"}`))
+	assert.NoError(t, executor.Validate(`{"databaseConnectionString":"redis://localhost:6379","command":"DBSIZE","expected_value":"0","operator":"gt"}`))
+	assert.Error(t, executor.Validate(`{"databaseConnectionString":"redis://localhost:6379","command":"DEL healthkey"}`))
+	assert.Error(t, executor.Validate(`{"databaseConnectionString":"redis://localhost:6379","expected_value":"ok"}`))
+	assert.Error(t, executor.Validate(`{"databaseConnectionString":"redis://localhost:6379","command":"DBSIZE","operator":"between"}`))
+}
+
+func TestRedisExecutor_Execute_Command(t *testing.T) {
+	addr := startFakeRedis(t, map[string]string{
+		"GET HEALTHKEY": "$2\r\nok\r\n",
+		"GET MISSING":   "$-1\r\n",
+		"DBSIZE":        ":42\r\n",
+	})
+	executor := NewRedisExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		status  shared.MonitorStatus
+		message string
+	}{
+		{"matching value", `"command":"GET healthkey","expected_value":"ok"`, shared.MonitorStatusUp, "Redis command GET successful: ok"},
+		{"mismatching value", `"command":"GET healthkey","expected_value":"ready"`, shared.MonitorStatusDown, "returned ok, expected eq ready"},
+		{"missing key", `"command":"GET missing"`, shared.MonitorStatusDown, "returned no value"},
+		{"numeric comparison", `"command":"DBSIZE","expected_value":"0","operator":"gt"`, shared.MonitorStatusUp, "Redis command DBSIZE successful: 42"},
+		{"failing numeric comparison", `"command":"DBSIZE","expected_value":"100","operator":"ge"`, shared.MonitorStatusDown, "returned 42, expected ge 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{
+				ID:      "redis-command",
+				Name:    "redis-command",
+				Timeout: 5,
+				Config:  fmt.Sprintf(`{"databaseConnectionString":"redis://%s",%s}`, addr, tt.config),
+			}
+			result := executor.Execute(context.Background(), m, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
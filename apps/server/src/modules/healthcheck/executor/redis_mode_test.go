@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedisExecutor_Validate_Mode(t *testing.T) {
+	executor := NewRedisExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"standalone", `{"databaseConnectionString":"redis://localhost:6379","mode":"standalone"}`, false},
+		{"sentinel", `{"databaseConnectionString":"redis://:secret@localhost/1","mode":"sentinel","master_name":"mymaster","sentinel_addresses":["sentinel-1:26379","sentinel-2:26379"]}`, false},
+		{"sentinel without master name", `{"databaseConnectionString":"redis://localhost","mode":"sentinel","sentinel_addresses":["sentinel-1:26379"]}`, true},
+		{"sentinel without addresses", `{"databaseConnectionString":"redis://localhost","mode":"sentinel","master_name":"mymaster"}`, true},
+		{"sentinel with invalid address", `{"databaseConnectionString":"redis://localhost","mode":"sentinel","master_name":"mymaster","sentinel_addresses":["sentinel-1"]}`, true},
+		{"cluster", `{"databaseConnectionString":"rediss://localhost","mode":"cluster","cluster_nodes":["node-1:6379","node-2:6379"]}`, false},
+		{"cluster without nodes", `{"databaseConnectionString":"redis://localhost","mode":"cluster"}`, true},
+		{"cluster with database", `{"databaseConnectionString":"redis://localhost/2","mode":"cluster","cluster_nodes":["node-1:6379"]}`, true},
+		{"unknown mode", `{"databaseConnectionString":"redis://localhost","mode":"replica"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewRedisClient(t *testing.T) {
+	opts := &redis.Options{Addr: "localhost:6379"}
+
+	client := newRedisClient(&RedisConfig{}, opts)
+	defer client.Close()
+	assert.IsType(t, &redis.Client{}, client)
+
+	cluster := newRedisClient(&RedisConfig{Mode: redisModeCluster, ClusterNodes: []string{"node-1:6379"}}, opts)
+	defer cluster.Close()
+	assert.IsType(t, &redis.ClusterClient{}, cluster)
+}
+
+func TestRedisExecutor_ReachabilityAddress_Mode(t *testing.T) {
+	executor := NewRedisExecutor(zap.NewNop().Sugar())
+
+	address, ok := executor.ReachabilityAddress(&RedisConfig{DatabaseConnectionString: "redis://localhost", Mode: redisModeSentinel, SentinelAddresses: []string{"sentinel-1:26379"}})
+	assert.True(t, ok)
+	assert.Equal(t, "sentinel-1:26379", address)
+
+	address, ok = executor.ReachabilityAddress(&RedisConfig{DatabaseConnectionString: "redis://localhost", Mode: redisModeCluster, ClusterNodes: []string{"node-1:6379"}})
+	assert.True(t, ok)
+	assert.Equal(t, "node-1:6379", address)
+}
+
+func TestRedisExecutor_Execute_Sentinel(t *testing.T) {
+	master := startFakeRedis(t, map[string]string{"PING": "+PONG\r\n"})
+	host, port, err := net.SplitHostPort(master)
+	require.NoError(t, err)
+
+	// the sentinel points the client at the fake master
+	sentinel := startFakeRedis(t, map[string]string{
+		"SENTINEL GET-MASTER-ADDR-BY-NAME MYMASTER": fmt.Sprintf("*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(host), host, len(port), port),
+	})
+
+	m := &Monitor{
+		ID:      "redis-sentinel",
+		Name:    "redis-sentinel",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"databaseConnectionString":"redis://localhost","mode":"sentinel","master_name":"mymaster","sentinel_addresses":[%q]}`, sentinel),
+	}
+	result := NewRedisExecutor(zap.NewNop().Sugar()).Execute(context.Background(), m, nil)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Contains(t, result.Message, "PONG")
+}
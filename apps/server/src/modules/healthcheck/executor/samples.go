@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"peekaping/src/modules/shared"
+	"sort"
+	"time"
+)
+
+// SampleStats holds the latency distribution of the probes taken in a single check
+type SampleStats struct {
+	Count  int
+	Up     int
+	Min    time.Duration
+	Avg    time.Duration
+	Max    time.Duration
+	Median time.Duration
+	P95    time.Duration
+}
+
+// HeartBeatSamples is the distribution as stored on the heartbeat, nil when
+// the check took a single probe
+func (s *SampleStats) HeartBeatSamples() *shared.HeartBeatSamples {
+	if s == nil {
+		return nil
+	}
+	return &shared.HeartBeatSamples{
+		Count:    s.Count,
+		Up:       s.Up,
+		MinMs:    int(s.Min.Milliseconds()),
+		AvgMs:    int(s.Avg.Milliseconds()),
+		MaxMs:    int(s.Max.Milliseconds()),
+		MedianMs: int(s.Median.Milliseconds()),
+		P95Ms:    int(s.P95.Milliseconds()),
+	}
+}
+
+// collectSamples runs probe up to n times, stopping early once ctx is done.
+// At least one probe is always performed.
+func collectSamples(ctx context.Context, n int, probe func() *Result) []*Result {
+	if n < 1 {
+		n = 1
+	}
+	samples := make([]*Result, 0, n)
+	for i := 0; i < n; i++ {
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+		samples = append(samples, probe())
+	}
+	return samples
+}
+
+// computeSampleStats calculates min/avg/max/median/p95 over the sample latencies
+func computeSampleStats(samples []*Result) *SampleStats {
+	stats := &SampleStats{Count: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	var total time.Duration
+	for _, s := range samples {
+		if s.Status == shared.MonitorStatusUp {
+			stats.Up++
+		}
+		latency := s.EndTime.Sub(s.StartTime)
+		latencies = append(latencies, latency)
+		total += latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	stats.Min = latencies[0]
+	stats.Max = latencies[len(latencies)-1]
+	stats.Avg = total / time.Duration(len(latencies))
+	stats.Median = latencies[(len(latencies)-1)/2]
+	// nearest-rank percentile
+	stats.P95 = latencies[int(math.Ceil(0.95*float64(len(latencies))))-1]
+
+	return stats
+}
+
+// summarizeSamples folds the samples of a check into a single result.
+// The check is UP when the majority of samples succeeded and, if maxMedianLatency
+// is set, the median latency does not exceed it. The timing of the returned
+// result is taken from the median sample so the recorded ping is the median.
+func summarizeSamples(samples []*Result, maxMedianLatency time.Duration) *Result {
+	if len(samples) == 1 {
+		return samples[0]
+	}
+
+	stats := computeSampleStats(samples)
+
+	sorted := make([]*Result, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EndTime.Sub(sorted[i].StartTime) < sorted[j].EndTime.Sub(sorted[j].StartTime)
+	})
+	median := sorted[(len(sorted)-1)/2]
+
+	summary := fmt.Sprintf(
+		"samples: %d (%d up), min %s, avg %s, max %s, p95 %s, median %s",
+		stats.Count, stats.Up, stats.Min, stats.Avg, stats.Max, stats.P95, stats.Median,
+	)
+
+	result := &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("%s | %s", median.Message, summary),
+		StartTime: median.StartTime,
+		EndTime:   median.EndTime,
+		Samples:   stats,
+		Headers:   median.Headers,
+	}
+
+	if stats.Up*2 <= stats.Count {
+		result.Status = shared.MonitorStatusDown
+		// report a failing sample's message rather than a successful one
+		for _, s := range sorted {
+			if s.Status != shared.MonitorStatusUp {
+				result.Message = fmt.Sprintf("%s | %s", s.Message, summary)
+				result.Headers = s.Headers
+				break
+			}
+		}
+		return result
+	}
+
+	if maxMedianLatency > 0 && stats.Median > maxMedianLatency {
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("Median latency %s exceeds threshold %s | %s", stats.Median, maxMedianLatency, summary)
+	}
+
+	return result
+}
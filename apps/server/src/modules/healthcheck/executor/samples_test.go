@@ -0,0 +1,230 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func sampleWithLatency(status shared.MonitorStatus, latency time.Duration) *Result {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Result{
+		Status:    status,
+		Message:   "sample " + latency.String(),
+		StartTime: start,
+		EndTime:   start.Add(latency),
+	}
+}
+
+func TestComputeSampleStats(t *testing.T) {
+	up := shared.MonitorStatusUp
+	samples := []*Result{
+		sampleWithLatency(up, 40*time.Millisecond),
+		sampleWithLatency(up, 10*time.Millisecond),
+		sampleWithLatency(up, 30*time.Millisecond),
+		sampleWithLatency(shared.MonitorStatusDown, 100*time.Millisecond),
+		sampleWithLatency(up, 20*time.Millisecond),
+	}
+
+	stats := computeSampleStats(samples)
+
+	assert.Equal(t, 5, stats.Count)
+	assert.Equal(t, 4, stats.Up)
+	assert.Equal(t, 10*time.Millisecond, stats.Min)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 40*time.Millisecond, stats.Avg)
+	assert.Equal(t, 30*time.Millisecond, stats.Median)
+	assert.Equal(t, 100*time.Millisecond, stats.P95)
+}
+
+func TestComputeSampleStats_P95NearestRank(t *testing.T) {
+	samples := make([]*Result, 0, 20)
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, sampleWithLatency(shared.MonitorStatusUp, time.Duration(i)*time.Millisecond))
+	}
+
+	stats := computeSampleStats(samples)
+
+	assert.Equal(t, 19*time.Millisecond, stats.P95)
+	assert.Equal(t, 10*time.Millisecond, stats.Median)
+	assert.Equal(t, 10500*time.Microsecond, stats.Avg)
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	up := shared.MonitorStatusUp
+	down := shared.MonitorStatusDown
+
+	tests := []struct {
+		name             string
+		samples          []*Result
+		maxMedianLatency time.Duration
+		expectedStatus   shared.MonitorStatus
+		expectedPing     time.Duration
+		messageContain   string
+	}{
+		{
+			name: "all up uses median sample timing",
+			samples: []*Result{
+				sampleWithLatency(up, 50*time.Millisecond),
+				sampleWithLatency(up, 10*time.Millisecond),
+				sampleWithLatency(up, 20*time.Millisecond),
+			},
+			expectedStatus: up,
+			expectedPing:   20 * time.Millisecond,
+			messageContain: "samples: 3 (3 up)",
+		},
+		{
+			name: "minority down stays up",
+			samples: []*Result{
+				sampleWithLatency(up, 10*time.Millisecond),
+				sampleWithLatency(down, 5*time.Millisecond),
+				sampleWithLatency(up, 20*time.Millisecond),
+			},
+			expectedStatus: up,
+			expectedPing:   10 * time.Millisecond,
+		},
+		{
+			name: "majority down is down",
+			samples: []*Result{
+				sampleWithLatency(down, 10*time.Millisecond),
+				sampleWithLatency(down, 15*time.Millisecond),
+				sampleWithLatency(up, 20*time.Millisecond),
+			},
+			expectedStatus: down,
+			messageContain: "samples: 3 (1 up)",
+		},
+		{
+			name: "median over threshold is down",
+			samples: []*Result{
+				sampleWithLatency(up, 150*time.Millisecond),
+				sampleWithLatency(up, 10*time.Millisecond),
+				sampleWithLatency(up, 200*time.Millisecond),
+			},
+			maxMedianLatency: 100 * time.Millisecond,
+			expectedStatus:   down,
+			messageContain:   "Median latency 150ms exceeds threshold 100ms",
+		},
+		{
+			name: "single slow outlier does not trip threshold",
+			samples: []*Result{
+				sampleWithLatency(up, 20*time.Millisecond),
+				sampleWithLatency(up, 10*time.Millisecond),
+				sampleWithLatency(up, 900*time.Millisecond),
+			},
+			maxMedianLatency: 100 * time.Millisecond,
+			expectedStatus:   up,
+			expectedPing:     20 * time.Millisecond,
+			messageContain:   "max 900ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := summarizeSamples(tt.samples, tt.maxMedianLatency)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.NotNil(t, result.Samples)
+			if tt.expectedPing > 0 {
+				assert.Equal(t, tt.expectedPing, result.EndTime.Sub(result.StartTime))
+			}
+			if tt.messageContain != "" {
+				assert.Contains(t, result.Message, tt.messageContain)
+			}
+		})
+	}
+}
+
+func TestHTTPExecutor_Execute_SamplesPerCheck(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	monitor := &Monitor{
+		ID:       "monitor1",
+		Type:     "http",
+		Name:     "Test Monitor",
+		Interval: 30,
+		Timeout:  5,
+		Config: `{
+			"url": "` + server.URL + `",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none",
+			"samples_per_check": 5
+		}`,
+	}
+
+	result := executor.Execute(context.Background(), monitor, nil)
+
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&hits))
+	if assert.NotNil(t, result.Samples) {
+		assert.Equal(t, 5, result.Samples.Count)
+		assert.Equal(t, 5, result.Samples.Up)
+		assert.LessOrEqual(t, result.Samples.Min, result.Samples.Median)
+		assert.LessOrEqual(t, result.Samples.Median, result.Samples.P95)
+		assert.LessOrEqual(t, result.Samples.P95, result.Samples.Max)
+	}
+	assert.Contains(t, result.Message, "samples: 5 (5 up)")
+}
+
+func TestTCPExecutor_Execute_SamplesPerCheck(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewTCPExecutor(logger)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	_, portStr, _ := strings.Cut(listener.Addr().String(), ":")
+
+	monitor := &Monitor{
+		ID:       "monitor1",
+		Type:     "tcp",
+		Name:     "Test Monitor",
+		Interval: 30,
+		Timeout:  5,
+		Config:   `{"host": "127.0.0.1", "port": ` + portStr + `, "samples_per_check": 3}`,
+	}
+
+	result := executor.Execute(context.Background(), monitor, nil)
+
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	if assert.NotNil(t, result.Samples) {
+		assert.Equal(t, 3, result.Samples.Count)
+		assert.Equal(t, 3, result.Samples.Up)
+	}
+}
+
+func TestSampleStats_HeartBeatSamples(t *testing.T) {
+	var none *SampleStats
+	assert.Nil(t, none.HeartBeatSamples())
+
+	stats := &SampleStats{Count: 3, Up: 2, Min: 10 * time.Millisecond, Avg: 20 * time.Millisecond, Max: 30 * time.Millisecond, Median: 20 * time.Millisecond, P95: 30 * time.Millisecond}
+	assert.Equal(t, &shared.HeartBeatSamples{Count: 3, Up: 2, MinMs: 10, AvgMs: 20, MaxMs: 30, MedianMs: 20, P95Ms: 30}, stats.HeartBeatSamples())
+}
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"peekaping/src/modules/shared"
+	"peekaping/src/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SMTPConfig describes a mail server whose greeting, TLS and EHLO response
+// are checked without sending mail
+type SMTPConfig struct {
+	Host string `json:"host" validate:"required" example:"mail.example.com"`
+	Port int    `json:"port" validate:"required,min=1,max=65535" example:"587"`
+
+	// Encryption is "tls" for implicit TLS (usually 465), "starttls" to
+	// upgrade a plain session (587 or 25) or "none" for plain SMTP. Empty
+	// picks by port: tls on 465, starttls on 587 and none otherwise.
+	Encryption      string `json:"encryption,omitempty" validate:"omitempty,oneof=none starttls tls" example:"starttls"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+	// Capability that must be advertised in the EHLO response, e.g. AUTH
+	ExpectedCapability string `json:"expected_capability,omitempty" validate:"omitempty,max=64" example:"AUTH"`
+}
+
+// smtpLocalName is the name we introduce ourselves with in EHLO
+const smtpLocalName = "localhost"
+
+// encryption returns the security mode for the session
+func (c *SMTPConfig) encryption() string {
+	if c.Encryption != "" {
+		return c.Encryption
+	}
+	switch c.Port {
+	case 465:
+		return utils.SMTPSecurityTLS
+	case 587:
+		return utils.SMTPSecurityStartTLS
+	default:
+		return utils.SMTPSecurityNone
+	}
+}
+
+type SMTPExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewSMTPExecutor(logger *zap.SugaredLogger) *SMTPExecutor {
+	return &SMTPExecutor{
+		logger: logger,
+	}
+}
+
+func (s *SMTPExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[SMTPConfig](configJSON)
+}
+
+func (s *SMTPExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	smtpCfg := cfg.(*SMTPConfig)
+	return hostPortAddress(smtpCfg.Host, smtpCfg.Port)
+}
+
+func (s *SMTPExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	smtpCfg := cfg.(*SMTPConfig)
+	if err := GenericValidator(smtpCfg); err != nil {
+		return err
+	}
+
+	if _, _, err := net.SplitHostPort(smtpCfg.Host); err == nil {
+		return fmt.Errorf("host must not include a port, use the port field instead")
+	}
+	if strings.ContainsAny(smtpCfg.ExpectedCapability, " \t\r\n") {
+		return fmt.Errorf("expected_capability must be a single EHLO keyword")
+	}
+
+	return nil
+}
+
+func (s *SMTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := s.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*SMTPConfig)
+
+	s.logger.Debugf("execute smtp cfg: %+v", cfg)
+
+	encryption := cfg.encryption()
+	timeout := time.Duration(m.Timeout) * time.Second
+
+	startTime := time.Now().UTC()
+	client, err := utils.DialSMTP(ctx, cfg.Host, cfg.Port, encryption, &tls.Config{InsecureSkipVerify: cfg.IgnoreTlsErrors}, timeout)
+	if err != nil {
+		s.logger.Infof("SMTP check failed: %s, %s", m.Name, err.Error())
+		return DownResult(err, startTime, time.Now().UTC())
+	}
+	defer client.Close()
+
+	capabilities, err := smtpCapabilities(client)
+	if err == nil {
+		client.Quit()
+	}
+	endTime := time.Now().UTC()
+	if err != nil {
+		s.logger.Infof("SMTP EHLO failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("EHLO failed: %w", err), startTime, endTime)
+	}
+
+	advertised := strings.Join(capabilities, ", ")
+	if advertised == "" {
+		advertised = "none"
+	}
+
+	if expected := cfg.ExpectedCapability; expected != "" && !smtpHasCapability(capabilities, expected) &&
+		// the upgrade already proved STARTTLS, servers stop advertising it after
+		!(encryption == utils.SMTPSecurityStartTLS && strings.EqualFold(expected, "STARTTLS")) {
+		s.logger.Infof("SMTP capability missing: %s, %s", m.Name, expected)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("SMTP server does not advertise %s, capabilities: %s", strings.ToUpper(expected), advertised),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	s.logger.Infof("SMTP check successful: %s", m.Name)
+
+	session := "plain"
+	if state, ok := client.TLSConnectionState(); ok {
+		session = tls.VersionName(state.Version)
+	}
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("SMTP %s (%s), capabilities: %s", encryption, session, advertised),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// smtpCapabilities sends EHLO and returns the advertised extensions with
+// their parameters, e.g. "AUTH PLAIN LOGIN". net/smtp keeps the parsed list
+// to itself, so the command is sent on the text connection directly.
+func smtpCapabilities(client *smtp.Client) ([]string, error) {
+	id, err := client.Text.Cmd("EHLO %s", smtpLocalName)
+	if err != nil {
+		return nil, err
+	}
+	client.Text.StartResponse(id)
+	defer client.Text.EndResponse(id)
+	_, msg, err := client.Text.ReadResponse(250)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first line greets us with the server name
+	lines := strings.Split(msg, "\n")
+	capabilities := make([]string, 0, len(lines))
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			capabilities = append(capabilities, line)
+		}
+	}
+	return capabilities, nil
+}
+
+func smtpHasCapability(capabilities []string, keyword string) bool {
+	for _, capability := range capabilities {
+		name, _, _ := strings.Cut(capability, " ")
+		if strings.EqualFold(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"peekaping/src/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubSMTPServer answers EHLO with a fixed list of extensions, which may
+// differ before and after TLS like on real submission servers
+type stubSMTPServer struct {
+	tls         *tls.Config
+	implicitTLS bool
+	offerTLS    bool
+	plainExts   []string
+	securedExts []string
+}
+
+func (s *stubSMTPServer) start(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if s.implicitTLS {
+		listener = tls.NewListener(listener, s.tls)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *stubSMTPServer) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	secured := s.implicitTLS
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 stub ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"):
+			exts := s.plainExts
+			if secured {
+				exts = s.securedExts
+			}
+			if s.offerTLS && !secured {
+				exts = append([]string{"STARTTLS"}, exts...)
+			}
+			lines := append([]string{"stub.example.com"}, exts...)
+			for i, ext := range lines {
+				separator := "-"
+				if i == len(lines)-1 {
+					separator = " "
+				}
+				reply("250" + separator + ext)
+			}
+		case command == "STARTTLS" && s.offerTLS && !secured:
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			secured = true
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func newStubSMTPTLS(t *testing.T) *tls.Config {
+	chain := newTestCertChain(t)
+	return &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{chain.leaf.Raw, chain.intermediate.Raw},
+		PrivateKey:  chain.leafKey,
+	}}}
+}
+
+func smtpMonitor(port int, extra string) *Monitor {
+	return &Monitor{
+		ID:      "smtp",
+		Name:    "mail",
+		Type:    "smtp",
+		Timeout: 2,
+		Config:  fmt.Sprintf(`{"host": "127.0.0.1", "port": %d, "ignore_tls_errors": true%s}`, port, extra),
+	}
+}
+
+func TestSMTPExecutor_Validate(t *testing.T) {
+	executor := NewSMTPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid config", config: `{"host": "mail.example.com", "port": 25}`},
+		{name: "starttls with capability", config: `{"host": "mail.example.com", "port": 587, "encryption": "starttls", "expected_capability": "AUTH"}`},
+		{name: "implicit tls", config: `{"host": "mail.example.com", "port": 465, "encryption": "tls"}`},
+		{name: "unknown encryption", config: `{"host": "mail.example.com", "port": 465, "encryption": "ssl"}`, wantError: true},
+		{name: "capability with parameters", config: `{"host": "mail.example.com", "port": 25, "expected_capability": "AUTH PLAIN"}`, wantError: true},
+		{name: "invalid port", config: `{"host": "mail.example.com", "port": 0}`, wantError: true},
+		{name: "port in host", config: `{"host": "mail.example.com:25", "port": 25}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSMTPConfig_EncryptionByPort(t *testing.T) {
+	tests := []struct {
+		port       int
+		encryption string
+		want       string
+	}{
+		{port: 465, want: utils.SMTPSecurityTLS},
+		{port: 587, want: utils.SMTPSecurityStartTLS},
+		{port: 25, want: utils.SMTPSecurityNone},
+		{port: 2525, want: utils.SMTPSecurityNone},
+		{port: 25, encryption: utils.SMTPSecurityStartTLS, want: utils.SMTPSecurityStartTLS},
+		{port: 587, encryption: utils.SMTPSecurityNone, want: utils.SMTPSecurityNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", tt.port, tt.encryption), func(t *testing.T) {
+			cfg := &SMTPConfig{Port: tt.port, Encryption: tt.encryption}
+			assert.Equal(t, tt.want, cfg.encryption())
+		})
+	}
+}
+
+func TestSMTPExecutor_Execute(t *testing.T) {
+	serverTLS := newStubSMTPTLS(t)
+	// submission on 465
+	implicit := (&stubSMTPServer{tls: serverTLS, implicitTLS: true, securedExts: []string{"AUTH PLAIN LOGIN", "8BITMIME"}}).start(t)
+	// submission on 587, AUTH only after the upgrade
+	submission := (&stubSMTPServer{tls: serverTLS, offerTLS: true, plainExts: []string{"8BITMIME"}, securedExts: []string{"AUTH PLAIN", "8BITMIME"}}).start(t)
+	// relay on 25 without TLS
+	relay := (&stubSMTPServer{plainExts: []string{"PIPELINING", "SIZE 10240000"}}).start(t)
+
+	tests := []struct {
+		name        string
+		port        int
+		extra       string
+		wantStatus  shared.MonitorStatus
+		wantMessage []string
+	}{
+		{
+			name:        "implicit tls",
+			port:        implicit,
+			extra:       `, "encryption": "tls", "expected_capability": "auth"`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: []string{"SMTP tls (TLS 1.3)", "capabilities: AUTH PLAIN LOGIN, 8BITMIME"},
+		},
+		{
+			name:        "starttls",
+			port:        submission,
+			extra:       `, "encryption": "starttls", "expected_capability": "AUTH"`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: []string{"SMTP starttls (TLS 1.3)", "capabilities: AUTH PLAIN, 8BITMIME"},
+		},
+		{
+			name:        "starttls expected after upgrade",
+			port:        submission,
+			extra:       `, "encryption": "starttls", "expected_capability": "STARTTLS"`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: []string{"SMTP starttls"},
+		},
+		{
+			name:        "plain on a starttls server",
+			port:        submission,
+			extra:       `, "encryption": "none", "expected_capability": "STARTTLS"`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: []string{"SMTP none (plain)", "capabilities: STARTTLS, 8BITMIME"},
+		},
+		{
+			name:        "auth not offered before tls",
+			port:        submission,
+			extra:       `, "encryption": "none", "expected_capability": "AUTH"`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: []string{"does not advertise AUTH", "capabilities: STARTTLS, 8BITMIME"},
+		},
+		{
+			name:        "plain relay",
+			port:        relay,
+			extra:       `, "encryption": "none", "expected_capability": "SIZE"`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: []string{"SMTP none (plain)", "capabilities: PIPELINING, SIZE 10240000"},
+		},
+		{
+			name:        "starttls not offered",
+			port:        relay,
+			extra:       `, "encryption": "starttls"`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: []string{"does not support STARTTLS"},
+		},
+		{
+			name:       "implicit tls against plain server",
+			port:       relay,
+			extra:      `, "encryption": "tls"`,
+			wantStatus: shared.MonitorStatusDown,
+		},
+	}
+
+	executor := NewSMTPExecutor(zap.NewNop().Sugar())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), smtpMonitor(tt.port, tt.extra), nil)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			for _, want := range tt.wantMessage {
+				assert.Contains(t, result.Message, want)
+			}
+		})
+	}
+}
+
+func TestSMTPExecutor_Execute_VerifiesCertificate(t *testing.T) {
+	port := (&stubSMTPServer{tls: newStubSMTPTLS(t), implicitTLS: true}).start(t)
+
+	m := smtpMonitor(port, `, "encryption": "tls"`)
+	m.Config = strings.Replace(m.Config, `"ignore_tls_errors": true`, `"ignore_tls_errors": false`, 1)
+
+	result := NewSMTPExecutor(zap.NewNop().Sugar()).Execute(context.Background(), m, nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "certificate")
+}
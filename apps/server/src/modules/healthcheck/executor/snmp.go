@@ -20,6 +20,14 @@ type SnmpConfig struct {
 	JsonPath         string `json:"json_path" example:"$"`
 	JsonPathOperator string `json:"json_path_operator" validate:"omitempty,oneof=eq ne lt gt le ge" example:"eq"`
 	ExpectedValue    string `json:"expected_value" example:""`
+
+	// SNMPv3 user-based security model settings
+	V3Username      string `json:"v3_username" example:"monitor"`
+	V3SecurityLevel string `json:"v3_security_level" validate:"omitempty,oneof=noAuthNoPriv authNoPriv authPriv" example:"authPriv"`
+	V3AuthProtocol  string `json:"v3_auth_protocol" validate:"omitempty,oneof=MD5 SHA SHA224 SHA256 SHA384 SHA512" example:"SHA256"`
+	V3AuthPassword  string `json:"v3_auth_password" example:"authpassword"`
+	V3PrivProtocol  string `json:"v3_priv_protocol" validate:"omitempty,oneof=DES AES AES192 AES256" example:"AES"`
+	V3PrivPassword  string `json:"v3_priv_password" example:"privpassword"`
 }
 
 type SnmpExecutor struct {
@@ -41,7 +49,88 @@ func (s *SnmpExecutor) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*SnmpConfig))
+
+	snmpCfg := cfg.(*SnmpConfig)
+	if err := GenericValidator(snmpCfg); err != nil {
+		return err
+	}
+
+	if snmpCfg.SnmpVersion == "v3" {
+		return s.validateV3(snmpCfg)
+	}
+	return nil
+}
+
+// validateV3 checks that the credentials required by the v3 security level are set
+func (s *SnmpExecutor) validateV3(cfg *SnmpConfig) error {
+	if cfg.V3Username == "" {
+		return fmt.Errorf("v3_username is required for SNMP v3")
+	}
+
+	switch cfg.V3SecurityLevel {
+	case "authPriv":
+		if cfg.V3PrivProtocol == "" || cfg.V3PrivPassword == "" {
+			return fmt.Errorf("v3_priv_protocol and v3_priv_password are required for authPriv")
+		}
+		fallthrough
+	case "authNoPriv":
+		if cfg.V3AuthProtocol == "" || cfg.V3AuthPassword == "" {
+			return fmt.Errorf("v3_auth_protocol and v3_auth_password are required for %s", cfg.V3SecurityLevel)
+		}
+	}
+
+	return nil
+}
+
+// configureV3 applies the user-based security model settings to the client
+func (s *SnmpExecutor) configureV3(client *gosnmp.GoSNMP, cfg *SnmpConfig) {
+	params := &gosnmp.UsmSecurityParameters{
+		UserName: cfg.V3Username,
+	}
+
+	switch cfg.V3SecurityLevel {
+	case "authPriv":
+		client.MsgFlags = gosnmp.AuthPriv
+	case "authNoPriv":
+		client.MsgFlags = gosnmp.AuthNoPriv
+	default:
+		client.MsgFlags = gosnmp.NoAuthNoPriv
+	}
+
+	if client.MsgFlags != gosnmp.NoAuthNoPriv {
+		params.AuthenticationPassphrase = cfg.V3AuthPassword
+		switch cfg.V3AuthProtocol {
+		case "MD5":
+			params.AuthenticationProtocol = gosnmp.MD5
+		case "SHA224":
+			params.AuthenticationProtocol = gosnmp.SHA224
+		case "SHA256":
+			params.AuthenticationProtocol = gosnmp.SHA256
+		case "SHA384":
+			params.AuthenticationProtocol = gosnmp.SHA384
+		case "SHA512":
+			params.AuthenticationProtocol = gosnmp.SHA512
+		default:
+			params.AuthenticationProtocol = gosnmp.SHA
+		}
+	}
+
+	if client.MsgFlags == gosnmp.AuthPriv {
+		params.PrivacyPassphrase = cfg.V3PrivPassword
+		switch cfg.V3PrivProtocol {
+		case "DES":
+			params.PrivacyProtocol = gosnmp.DES
+		case "AES192":
+			params.PrivacyProtocol = gosnmp.AES192
+		case "AES256":
+			params.PrivacyProtocol = gosnmp.AES256
+		default:
+			params.PrivacyProtocol = gosnmp.AES
+		}
+	}
+
+	client.SecurityModel = gosnmp.UserSecurityModel
+	client.SecurityParameters = params
 }
 
 func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
@@ -70,6 +159,10 @@ func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 		Retries:   m.MaxRetries,
 	}
 
+	if cfg.SnmpVersion == "v3" {
+		s.configureV3(snmpClient, cfg)
+	}
+
 	err = snmpClient.Connect()
 	if err != nil {
 		endTime := time.Now().UTC()
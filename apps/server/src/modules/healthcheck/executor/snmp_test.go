@@ -68,6 +68,60 @@ func TestSnmpExecutor_Validate(t *testing.T) {
 			}`,
 			wantError: true,
 		},
+		{
+			name: "valid v3 authPriv config",
+			config: `{
+				"host": "127.0.0.1",
+				"community": "public",
+				"snmp_version": "v3",
+				"oid": "1.3.6.1.2.1.1.1.0",
+				"v3_username": "monitor",
+				"v3_security_level": "authPriv",
+				"v3_auth_protocol": "SHA256",
+				"v3_auth_password": "authpassword",
+				"v3_priv_protocol": "AES",
+				"v3_priv_password": "privpassword"
+			}`,
+			wantError: false,
+		},
+		{
+			name: "v3 missing username",
+			config: `{
+				"host": "127.0.0.1",
+				"community": "public",
+				"snmp_version": "v3",
+				"oid": "1.3.6.1.2.1.1.1.0"
+			}`,
+			wantError: true,
+		},
+		{
+			name: "v3 authPriv missing privacy password",
+			config: `{
+				"host": "127.0.0.1",
+				"community": "public",
+				"snmp_version": "v3",
+				"oid": "1.3.6.1.2.1.1.1.0",
+				"v3_username": "monitor",
+				"v3_security_level": "authPriv",
+				"v3_auth_protocol": "SHA",
+				"v3_auth_password": "authpassword"
+			}`,
+			wantError: true,
+		},
+		{
+			name: "v3 unsupported auth protocol",
+			config: `{
+				"host": "127.0.0.1",
+				"community": "public",
+				"snmp_version": "v3",
+				"oid": "1.3.6.1.2.1.1.1.0",
+				"v3_username": "monitor",
+				"v3_security_level": "authNoPriv",
+				"v3_auth_protocol": "MD4",
+				"v3_auth_password": "authpassword"
+			}`,
+			wantError: true,
+		},
 		{
 			name: "invalid operator",
 			config: `{
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// expectSQLValue compares the first column of the first row with the expected
+// value and returns the value read. NULL reads as "NULL".
+func expectSQLValue(rows *sql.Rows, expected string) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("failed to get columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("query returned no columns, expected %q", expected)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("error iterating rows: %w", err)
+		}
+		return "", fmt.Errorf("query returned no rows, expected %q", expected)
+	}
+
+	var first sql.NullString
+	dest := make([]any, len(columns))
+	dest[0] = &first
+	for i := 1; i < len(dest); i++ {
+		dest[i] = new(any)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", fmt.Errorf("failed to read the first row: %w", err)
+	}
+
+	actual := "NULL"
+	if first.Valid {
+		actual = first.String
+	}
+	if actual != expected {
+		return actual, fmt.Errorf("query returned %q, expected %q", actual, expected)
+	}
+	return actual, nil
+}
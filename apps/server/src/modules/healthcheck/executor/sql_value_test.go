@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+func TestExpectSQLValue(t *testing.T) {
+	db, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "CREATE TABLE health (status TEXT, checks INTEGER)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO health (status, checks) VALUES ('ok', 3), ('degraded', 1)")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		value    string
+		wantErr  string
+	}{
+		{"matching first row", "SELECT status, checks FROM health ORDER BY checks DESC", "ok", "ok", ""},
+		{"numeric column", "SELECT checks FROM health ORDER BY checks DESC", "3", "3", ""},
+		{"mismatch", "SELECT status FROM health ORDER BY checks ASC", "ok", "degraded", `query returned "degraded", expected "ok"`},
+		{"null", "SELECT NULL", "ok", "NULL", `query returned "NULL", expected "ok"`},
+		{"no rows", "SELECT status FROM health WHERE checks > 10", "ok", "", `query returned no rows, expected "ok"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.QueryContext(ctx, tt.query)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			value, err := expectSQLValue(rows, tt.expected)
+			assert.Equal(t, tt.value, value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			}
+		})
+	}
+}
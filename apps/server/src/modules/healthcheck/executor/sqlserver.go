@@ -18,6 +18,13 @@ import (
 type SQLServerConfig struct {
 	DatabaseConnectionString string `json:"database_connection_string" validate:"required" example:"Server=localhost,1433;Database=master;User Id=sa;Password=password;Encrypt=false;TrustServerCertificate=true;Connection Timeout=30"`
 	DatabaseQuery            string `json:"database_query" validate:"omitempty" example:"SELECT 1"`
+
+	// Value the first column of the first row must hold
+	ExpectedValue string `json:"expected_value,omitempty" example:"ok"`
+
+	// Seconds the query may run once connected, 0 shares the monitor timeout
+	// with the connection
+	QueryTimeout int `json:"query_timeout,omitempty" validate:"min=0" example:"30"`
 }
 
 type SQLServerExecutor struct {
@@ -34,6 +41,14 @@ func (s *SQLServerExecutor) Unmarshal(configJSON string) (any, error) {
 	return GenericUnmarshal[SQLServerConfig](configJSON)
 }
 
+// CheckTimeout adds the query timeout to the connection budget of the monitor
+func (s *SQLServerExecutor) CheckTimeout(cfg any, timeout time.Duration) time.Duration {
+	if queryTimeout := cfg.(*SQLServerConfig).QueryTimeout; queryTimeout > 0 {
+		return timeout + time.Duration(queryTimeout)*time.Second
+	}
+	return timeout
+}
+
 // Regex to validate SQL Server connection string format
 var sqlServerConnectionStringRegex = regexp.MustCompile(`(?i)^Server=([^;,]+)(,\d+)?;Database=[^;]+;User Id=[^;]+;Password=[^;]*;?.*$`)
 
@@ -288,14 +303,22 @@ func (s *SQLServerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel
 	defer db.Close()
 
 	// Set connection timeout using the monitor's configured timeout
-	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
+	connectCtx, cancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
 	defer cancel()
 
 	// Test connection
-	if err := db.PingContext(ctx); err != nil {
+	if err := db.PingContext(connectCtx); err != nil {
 		return DownResult(fmt.Errorf("connection failed: %w", err), startTime, time.Now().UTC())
 	}
 
+	// The query gets a budget of its own when one is configured
+	queryCtx := connectCtx
+	if cfg.QueryTimeout > 0 {
+		var queryCancel context.CancelFunc
+		queryCtx, queryCancel = context.WithTimeout(ctx, time.Duration(cfg.QueryTimeout)*time.Second)
+		defer queryCancel()
+	}
+
 	query := cfg.DatabaseQuery
 	if query == "" || strings.TrimSpace(query) == "" {
 		query = "SELECT 1"
@@ -306,12 +329,26 @@ func (s *SQLServerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel
 		}
 	}
 
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := db.QueryContext(queryCtx, query)
 	if err != nil {
 		return DownResult(fmt.Errorf("query execution failed: %w", err), startTime, time.Now().UTC())
 	}
 	defer rows.Close()
 
+	if cfg.ExpectedValue != "" {
+		value, err := expectSQLValue(rows, cfg.ExpectedValue)
+		if err != nil {
+			return DownResult(err, startTime, time.Now().UTC())
+		}
+		endTime := time.Now().UTC()
+		return &Result{
+			Status:    shared.MonitorStatusUp,
+			Message:   fmt.Sprintf("Query successful, ping: %dms, value: %s", endTime.Sub(startTime).Milliseconds(), value),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
 	// Get column names
 	columns, err := rows.Columns()
 	if err != nil {
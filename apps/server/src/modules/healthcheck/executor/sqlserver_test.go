@@ -5,6 +5,7 @@ import (
 	"peekaping/src/modules/shared"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -561,3 +562,20 @@ func TestSQLServerExecutor_Execute(t *testing.T) {
 	assert.Equal(t, shared.MonitorStatusDown, result.Status)
 	assert.Contains(t, result.Message, "connection string validation failed")
 }
+
+func TestSQLServerExecutor_QueryTimeout(t *testing.T) {
+	executor := NewSQLServerExecutor(zap.NewNop().Sugar())
+	connectionString := "Server=localhost,1433;Database=master;User Id=sa;Password=password;Encrypt=false"
+
+	assert.NoError(t, executor.Validate(`{"database_connection_string":"`+connectionString+`","query_timeout":30}`))
+	err := executor.Validate(`{"database_connection_string":"` + connectionString + `","query_timeout":-1}`)
+	assert.Error(t, err, "expected a negative query timeout to be rejected")
+
+	m := &Monitor{Timeout: 5, Config: `{"database_connection_string":"` + connectionString + `","query_timeout":30}`}
+	assert.Equal(t, 35*time.Second, CheckTimeout(executor, m), "expected the query timeout on top of the connect timeout")
+
+	m.Config = `{"database_connection_string":"` + connectionString + `"}`
+	assert.Equal(t, 5*time.Second, CheckTimeout(executor, m))
+
+	assert.Equal(t, 5*time.Second, CheckTimeout(NewMySQLExecutor(zap.NewNop().Sugar()), m), "expected other executors to keep the monitor timeout")
+}
@@ -0,0 +1,215 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHConfig describes an SSH server whose transport handshake is checked
+// without logging in
+type SSHConfig struct {
+	Host string `json:"host" validate:"required" example:"jump.example.com"`
+	Port int    `json:"port" validate:"required,min=1,max=65535" example:"22"`
+
+	// Pinned host key in the OpenSSH SHA256 fingerprint format, a different key
+	// marks the monitor down
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty" validate:"omitempty,startswith=SHA256:" example:"SHA256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU"`
+	// Host key algorithm to negotiate, so a server with several keys always
+	// presents the pinned one
+	HostKeyAlgorithm string `json:"host_key_algorithm,omitempty" validate:"omitempty,oneof=ssh-ed25519 ecdsa-sha2-nistp256 ecdsa-sha2-nistp384 ecdsa-sha2-nistp521 rsa-sha2-256 rsa-sha2-512 ssh-rsa" example:"ssh-ed25519"`
+}
+
+// sshCheckUser is the user name sent with the "none" authentication request
+// that ends the handshake
+const sshCheckUser = "peekaping"
+
+// errSSHHostKeyMismatch aborts the handshake when the host key is not the pinned one
+var errSSHHostKeyMismatch = errors.New("host key mismatch")
+
+// sshHandshake is what the server revealed before authentication
+type sshHandshake struct {
+	ServerVersion string
+	KeyType       string
+	Fingerprint   string
+}
+
+type SSHExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewSSHExecutor(logger *zap.SugaredLogger) *SSHExecutor {
+	return &SSHExecutor{
+		logger: logger,
+	}
+}
+
+func (s *SSHExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[SSHConfig](configJSON)
+}
+
+func (s *SSHExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	sshCfg := cfg.(*SSHConfig)
+	return hostPortAddress(sshCfg.Host, sshCfg.Port)
+}
+
+func (s *SSHExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	sshCfg := cfg.(*SSHConfig)
+	if err := GenericValidator(sshCfg); err != nil {
+		return err
+	}
+
+	if _, _, err := net.SplitHostPort(sshCfg.Host); err == nil {
+		return fmt.Errorf("host must not include a port, use the port field instead")
+	}
+
+	return nil
+}
+
+func (s *SSHExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := s.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*SSHConfig)
+
+	s.logger.Debugf("execute ssh cfg: %+v", cfg)
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	timeout := time.Duration(m.Timeout) * time.Second
+
+	startTime := time.Now().UTC()
+	handshake, err := sshCheckHandshake(ctx, address, cfg, timeout)
+	endTime := time.Now().UTC()
+
+	if errors.Is(err, errSSHHostKeyMismatch) {
+		s.logger.Warnf("SSH host key mismatch: %s, got %s", m.Name, handshake.Fingerprint)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("SSH host key changed: expected %s, got %s (%s)", cfg.HostKeyFingerprint, handshake.Fingerprint, handshake.KeyType),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+	if err != nil {
+		s.logger.Infof("SSH handshake failed: %s, %s", m.Name, err.Error())
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("SSH handshake failed: %v", err),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	s.logger.Infof("SSH handshake successful: %s", m.Name)
+
+	message := fmt.Sprintf("SSH handshake succeeded, host key %s (%s)", handshake.Fingerprint, handshake.KeyType)
+	if handshake.ServerVersion != "" {
+		message = fmt.Sprintf("%s: %s", handshake.ServerVersion, message)
+	}
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// sshCheckHandshake runs the key exchange with the server and verifies its
+// host key, then stops at authentication. The server turning down the login
+// means the transport works. On a mismatch the presented key is returned with
+// errSSHHostKeyMismatch.
+func sshCheckHandshake(ctx context.Context, address string, cfg *SSHConfig, timeout time.Duration) (*sshHandshake, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	recorder := &sshVersionConn{Conn: conn}
+	handshake := &sshHandshake{}
+	keyVerified := false
+
+	clientCfg := &ssh.ClientConfig{
+		User:    sshCheckUser,
+		Timeout: timeout,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			handshake.KeyType = key.Type()
+			handshake.Fingerprint = ssh.FingerprintSHA256(key)
+			if cfg.HostKeyFingerprint != "" && handshake.Fingerprint != cfg.HostKeyFingerprint {
+				return errSSHHostKeyMismatch
+			}
+			keyVerified = true
+			return nil
+		},
+	}
+	if cfg.HostKeyAlgorithm != "" {
+		clientCfg.HostKeyAlgorithms = []string{cfg.HostKeyAlgorithm}
+	}
+
+	client, chans, reqs, err := ssh.NewClientConn(recorder, address, clientCfg)
+	handshake.ServerVersion = recorder.version()
+	if err == nil {
+		// the server let us in without credentials, the transport works all the same
+		ssh.NewClient(client, chans, reqs).Close()
+		return handshake, nil
+	}
+	if errors.Is(err, errSSHHostKeyMismatch) {
+		return handshake, errSSHHostKeyMismatch
+	}
+	if keyVerified {
+		return handshake, nil
+	}
+	return nil, err
+}
+
+// sshVersionConn records the identification line the server sends first
+type sshVersionConn struct {
+	net.Conn
+	head []byte
+}
+
+// sshMaxHeadBytes bounds how much of the stream is kept to find the
+// identification line
+const sshMaxHeadBytes = 4096
+
+func (c *sshVersionConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if len(c.head) < sshMaxHeadBytes && c.version() == "" {
+		c.head = append(c.head, p[:min(n, sshMaxHeadBytes-len(c.head))]...)
+	}
+	return n, err
+}
+
+// version returns the identification line once it was received completely
+func (c *sshVersionConn) version() string {
+	lines := strings.Split(string(c.head), "\n")
+	// servers may send other lines before the one starting with "SSH-", the
+	// last element is not terminated yet
+	for _, line := range lines[:len(lines)-1] {
+		if line = strings.TrimRight(line, "\r"); strings.HasPrefix(line, "SSH-") {
+			return line
+		}
+	}
+	return ""
+}
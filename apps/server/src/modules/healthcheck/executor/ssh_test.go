@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// startSSHServer accepts connections, runs the handshake with the host key and
+// turns every login down
+func startSSHServer(t *testing.T) (int, ssh.Signer) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	serverCfg := &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-StubSSH_1.0",
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			return nil, errors.New("denied")
+		},
+	}
+	serverCfg.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				ssh.NewServerConn(conn, serverCfg)
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, signer
+}
+
+func sshMonitor(config string) *Monitor {
+	return &Monitor{ID: "ssh", Name: "jump host", Type: "ssh", Timeout: 2, Config: config}
+}
+
+func TestSSHExecutor_Validate(t *testing.T) {
+	executor := NewSSHExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid config", config: `{"host": "jump.example.com", "port": 22}`},
+		{name: "pinned key", config: `{"host": "jump.example.com", "port": 22, "host_key_fingerprint": "SHA256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU", "host_key_algorithm": "ssh-ed25519"}`},
+		{name: "md5 fingerprint", config: `{"host": "jump.example.com", "port": 22, "host_key_fingerprint": "16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48"}`, wantError: true},
+		{name: "unknown algorithm", config: `{"host": "jump.example.com", "port": 22, "host_key_algorithm": "ssh-dss"}`, wantError: true},
+		{name: "invalid port", config: `{"host": "jump.example.com", "port": 70000}`, wantError: true},
+		{name: "port in host", config: `{"host": "jump.example.com:22", "port": 22}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSSHExecutor_Execute(t *testing.T) {
+	port, signer := startSSHServer(t)
+	fingerprint := ssh.FingerprintSHA256(signer.PublicKey())
+	executor := NewSSHExecutor(zap.NewNop().Sugar())
+
+	t.Run("handshake without pin", func(t *testing.T) {
+		result := executor.Execute(context.Background(), sshMonitor(fmt.Sprintf(`{"host": "127.0.0.1", "port": %d}`, port)), nil)
+		assert.Equal(t, shared.MonitorStatusUp, result.Status)
+		assert.Contains(t, result.Message, "SSH-2.0-StubSSH_1.0")
+		assert.Contains(t, result.Message, fingerprint)
+	})
+
+	t.Run("matching pin", func(t *testing.T) {
+		config := fmt.Sprintf(`{"host": "127.0.0.1", "port": %d, "host_key_fingerprint": %q}`, port, fingerprint)
+		result := executor.Execute(context.Background(), sshMonitor(config), nil)
+		assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	})
+
+	t.Run("changed host key", func(t *testing.T) {
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		otherSigner, err := ssh.NewSignerFromKey(otherKey)
+		require.NoError(t, err)
+		pinned := ssh.FingerprintSHA256(otherSigner.PublicKey())
+
+		config := fmt.Sprintf(`{"host": "127.0.0.1", "port": %d, "host_key_fingerprint": %q}`, port, pinned)
+		result := executor.Execute(context.Background(), sshMonitor(config), nil)
+		assert.Equal(t, shared.MonitorStatusDown, result.Status)
+		assert.Contains(t, result.Message, "SSH host key changed")
+		assert.Contains(t, result.Message, pinned)
+		assert.Contains(t, result.Message, fingerprint)
+	})
+
+	t.Run("unsupported host key algorithm", func(t *testing.T) {
+		config := fmt.Sprintf(`{"host": "127.0.0.1", "port": %d, "host_key_algorithm": "rsa-sha2-256"}`, port)
+		result := executor.Execute(context.Background(), sshMonitor(config), nil)
+		assert.Equal(t, shared.MonitorStatusDown, result.Status)
+		assert.Contains(t, result.Message, "SSH handshake failed")
+	})
+}
+
+func TestSSHExecutor_Execute_NotSSH(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+		conn.Close()
+	}()
+
+	config := fmt.Sprintf(`{"host": "127.0.0.1", "port": %d}`, listener.Addr().(*net.TCPAddr).Port)
+	result := NewSSHExecutor(zap.NewNop().Sugar()).Execute(context.Background(), sshMonitor(config), nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "SSH handshake failed")
+}
@@ -13,6 +13,10 @@ import (
 type TCPConfig struct {
 	Host string `json:"host" validate:"required" example:"example.com"`
 	Port int    `json:"port" validate:"required,min=1,max=65535" example:"80"`
+
+	// Latency sampling, the median of the probes decides the check status
+	SamplesPerCheck    int `json:"samples_per_check,omitempty" validate:"omitempty,min=1,max=20" example:"5"`
+	MaxMedianLatencyMs int `json:"max_median_latency_ms,omitempty" validate:"omitempty,min=1" example:"200"`
 }
 
 type TCPExecutor struct {
@@ -29,6 +33,11 @@ func (s *TCPExecutor) Unmarshal(configJSON string) (any, error) {
 	return GenericUnmarshal[TCPConfig](configJSON)
 }
 
+func (s *TCPExecutor) ReachabilityAddress(cfg any) (string, bool) {
+	tcpCfg := cfg.(*TCPConfig)
+	return hostPortAddress(tcpCfg.Host, tcpCfg.Port)
+}
+
 func (s *TCPExecutor) Validate(configJSON string) error {
 	cfg, err := s.Unmarshal(configJSON)
 	if err != nil {
@@ -46,6 +55,18 @@ func (t *TCPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy
 
 	t.logger.Debugf("execute tcp cfg: %+v", cfg)
 
+	if cfg.SamplesPerCheck <= 1 {
+		return t.execute(ctx, m, cfg)
+	}
+
+	samples := collectSamples(ctx, cfg.SamplesPerCheck, func() *Result {
+		return t.execute(ctx, m, cfg)
+	})
+	return summarizeSamples(samples, time.Duration(cfg.MaxMedianLatencyMs)*time.Millisecond)
+}
+
+// execute performs a single TCP connect probe
+func (t *TCPExecutor) execute(ctx context.Context, m *Monitor, cfg *TCPConfig) *Result {
 	address := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
 	startTime := time.Now().UTC()
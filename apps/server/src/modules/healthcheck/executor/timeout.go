@@ -0,0 +1,25 @@
+package executor
+
+import "time"
+
+// TimeoutSource is implemented by executors whose check can outlast the
+// timeout of the monitor, e.g. by a query budget of its own. It returns the
+// time the whole check may take.
+type TimeoutSource interface {
+	CheckTimeout(cfg any, timeout time.Duration) time.Duration
+}
+
+// CheckTimeout returns the time a check of the monitor may take, the timeout
+// of the monitor unless the executor extends it
+func CheckTimeout(exec Executor, m *Monitor) time.Duration {
+	timeout := time.Duration(m.Timeout) * time.Second
+	source, ok := exec.(TimeoutSource)
+	if !ok {
+		return timeout
+	}
+	cfg, err := exec.Unmarshal(m.Config)
+	if err != nil {
+		return timeout
+	}
+	return source.CheckTimeout(cfg, timeout)
+}
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"peekaping/src/modules/shared"
+	"peekaping/src/version"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+type WebSocketConfig struct {
+	Url             string `json:"url" validate:"required,url" example:"wss://example.com/socket"`
+	Send            string `json:"send" example:"{\"type\":\"ping\"}"`
+	Expect          string `json:"expect" example:"pong"`
+	Headers         string `json:"headers" validate:"omitempty,json" example:"{\"Authorization\":\"Bearer token\"}"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+}
+
+type WebSocketExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewWebSocketExecutor(logger *zap.SugaredLogger) *WebSocketExecutor {
+	return &WebSocketExecutor{
+		logger: logger,
+	}
+}
+
+func (w *WebSocketExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[WebSocketConfig](configJSON)
+}
+
+func (w *WebSocketExecutor) Validate(configJSON string) error {
+	cfg, err := w.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	wsCfg := cfg.(*WebSocketConfig)
+
+	if err := GenericValidator(wsCfg); err != nil {
+		return err
+	}
+
+	parsedURL, err := url.Parse(wsCfg.Url)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
+		return fmt.Errorf("url scheme must be ws or wss, got: %s", parsedURL.Scheme)
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("url must contain a host")
+	}
+
+	return nil
+}
+
+// webSocketDialer connects through the proxy of the monitor like the HTTP
+// executor, without one it connects directly
+func webSocketDialer(cfg *WebSocketConfig, timeout time.Duration, proxyModel *Proxy) *websocket.Dialer {
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: timeout,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: cfg.IgnoreTlsErrors,
+		},
+	}
+
+	// Reuse the HTTP proxy handling so http and socks proxies behave the same
+	proxyTransport := &http.Transport{}
+	buildProxyTransport(proxyTransport, proxyModel)
+	dialer.Proxy = proxyTransport.Proxy
+	dialer.NetDialContext = proxyTransport.DialContext
+	return dialer
+}
+
+func (w *WebSocketExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := w.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*WebSocketConfig)
+
+	w.logger.Debugf("execute websocket cfg: %+v", cfg)
+
+	timeout := time.Duration(m.Timeout) * time.Second
+
+	header := http.Header{}
+	header.Set("User-Agent", "peekaping/"+version.Version)
+	if cfg.Headers != "" {
+		headersMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(cfg.Headers), &headersMap); err != nil {
+			return DownResult(fmt.Errorf("invalid headers json: %w", err), time.Now().UTC(), time.Now().UTC())
+		}
+		for k, v := range headersMap {
+			header.Set(k, v)
+		}
+	}
+
+	dialer := webSocketDialer(cfg, timeout, proxyModel)
+
+	startTime := time.Now().UTC()
+
+	conn, resp, err := dialer.DialContext(ctx, cfg.Url, header)
+	if err != nil {
+		endTime := time.Now().UTC()
+		w.logger.Infof("WebSocket handshake failed: %s, %s", m.Name, err.Error())
+		if resp != nil {
+			return DownResult(fmt.Errorf("websocket handshake failed with status %d: %w", resp.StatusCode, err), startTime, endTime)
+		}
+		return DownResult(fmt.Errorf("websocket handshake failed: %w", err), startTime, endTime)
+	}
+	defer conn.Close()
+
+	deadline := startTime.Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	if cfg.Send != "" {
+		conn.SetWriteDeadline(deadline)
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(cfg.Send)); err != nil {
+			return DownResult(fmt.Errorf("failed to send websocket message: %w", err), startTime, time.Now().UTC())
+		}
+	}
+
+	var received string
+	if cfg.Send != "" || cfg.Expect != "" {
+		conn.SetReadDeadline(deadline)
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return DownResult(fmt.Errorf("failed to read websocket message: %w", err), startTime, time.Now().UTC())
+		}
+		received = string(data)
+	}
+
+	endTime := time.Now().UTC()
+
+	// Close the connection gracefully, the result does not depend on it
+	conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second),
+	)
+
+	if cfg.Expect != "" && !strings.Contains(received, cfg.Expect) {
+		w.logger.Infof("WebSocket expectation mismatch: %s", m.Name)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Message mismatch, expected '%s' in: %s", cfg.Expect, truncateMessage(received, 200)),
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	w.logger.Infof("WebSocket check successful: %s", m.Name)
+
+	message := fmt.Sprintf("%d - WebSocket connection established", resp.StatusCode)
+	if cfg.Expect != "" {
+		message = fmt.Sprintf("%d - WebSocket message matched", resp.StatusCode)
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// truncateMessage shortens s to at most max bytes for use in result messages
+func truncateMessage(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWebSocketExecutor_Validate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewWebSocketExecutor(logger)
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "valid ws url", config: `{"url": "ws://localhost:8080/socket"}`, wantError: false},
+		{name: "valid wss url with send and expect", config: `{"url": "wss://example.com/socket", "send": "ping", "expect": "pong"}`, wantError: false},
+		{name: "http scheme", config: `{"url": "http://example.com/socket"}`, wantError: true},
+		{name: "missing url", config: `{}`, wantError: true},
+		{name: "invalid headers", config: `{"url": "ws://localhost:8080", "headers": "not json"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebSocketExecutor_Execute(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewWebSocketExecutor(logger)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, append([]byte("echo: "), data...)); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		name           string
+		config         string
+		expectedStatus shared.MonitorStatus
+		messageContain string
+	}{
+		{
+			name:           "handshake only",
+			config:         `{"url": "` + wsURL + `/socket"}`,
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "101 - WebSocket connection established",
+		},
+		{
+			name:           "send and expect match",
+			config:         `{"url": "` + wsURL + `/socket", "send": "ping", "expect": "echo: ping"}`,
+			expectedStatus: shared.MonitorStatusUp,
+			messageContain: "message matched",
+		},
+		{
+			name:           "send and expect mismatch",
+			config:         `{"url": "` + wsURL + `/socket", "send": "ping", "expect": "pong"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "Message mismatch",
+		},
+		{
+			name:           "handshake rejected",
+			config:         `{"url": "` + wsURL + `/forbidden"}`,
+			expectedStatus: shared.MonitorStatusDown,
+			messageContain: "status 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &Monitor{
+				ID:       "monitor1",
+				Type:     "websocket",
+				Name:     "Test Monitor",
+				Interval: 30,
+				Timeout:  5,
+				Config:   tt.config,
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			assert.Equal(t, tt.expectedStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.messageContain)
+		})
+	}
+}
+
+func TestWebSocketDialer_Proxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://env-proxy:3128")
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	cfg := &WebSocketConfig{Url: "wss://example.com/socket"}
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/socket", nil)
+	require.NoError(t, err)
+
+	direct := webSocketDialer(cfg, time.Second, nil)
+	assert.Nil(t, direct.Proxy, "expected no environment proxy without a monitor proxy")
+	assert.Nil(t, direct.NetDialContext)
+
+	viaHTTP := webSocketDialer(cfg, time.Second, &Proxy{Protocol: "http", Host: "proxy.local", Port: 8080})
+	require.NotNil(t, viaHTTP.Proxy)
+	proxyURL, err := viaHTTP.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.local:8080", proxyURL.String())
+
+	viaSocks := webSocketDialer(cfg, time.Second, &Proxy{Protocol: "socks5", Host: "proxy.local", Port: 1080})
+	assert.Nil(t, viaSocks.Proxy)
+	assert.NotNil(t, viaSocks.NetDialContext)
+}
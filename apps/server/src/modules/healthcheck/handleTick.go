@@ -2,6 +2,7 @@ package healthcheck
 
 import (
 	"context"
+	"fmt"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
@@ -16,6 +17,7 @@ func (s *HealthCheckSupervisor) isImportantForNotification(prevBeatStatus, currB
 	down := shared.MonitorStatusDown
 	pending := shared.MonitorStatusPending
 	maintenance := shared.MonitorStatusMaintenance
+	dependency := shared.MonitorStatusDependencyDown
 
 	// * ? -> ANY STATUS = important [isFirstBeat]
 	// UP -> PENDING = not important
@@ -32,8 +34,12 @@ func (s *HealthCheckSupervisor) isImportantForNotification(prevBeatStatus, currB
 	// * MAINTENANCE -> DOWN = important
 	// DOWN -> MAINTENANCE = not important
 	// UP -> MAINTENANCE = not important
+	// ANY -> DEPENDENCY DOWN = not important, the parent alerts instead
+	// DEPENDENCY DOWN -> UP = not important
+	// * DEPENDENCY DOWN -> DOWN = important, the parent is back but this monitor is not
 
-	return (prevBeatStatus == maintenance && currBeatStatus == down) ||
+	return (prevBeatStatus == dependency && currBeatStatus == down) ||
+		(prevBeatStatus == maintenance && currBeatStatus == down) ||
 		(prevBeatStatus == up && currBeatStatus == down) ||
 		(prevBeatStatus == down && currBeatStatus == up) ||
 		(prevBeatStatus == pending && currBeatStatus == down)
@@ -45,6 +51,7 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 	down := shared.MonitorStatusDown
 	pending := shared.MonitorStatusPending
 	maintenance := shared.MonitorStatusMaintenance
+	dependency := shared.MonitorStatusDependencyDown
 
 	// UP -> PENDING = not important
 	// * UP -> DOWN = important
@@ -60,6 +67,12 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 	// * MAINTENANCE -> DOWN = important
 	// * DOWN -> MAINTENANCE = important
 	// * UP -> MAINTENANCE = important
+	// * ANY -> DEPENDENCY DOWN = important
+	// * DEPENDENCY DOWN -> ANY = important
+
+	if (prevBeatStatus == dependency) != (currBeatStatus == dependency) {
+		return true
+	}
 
 	return (prevBeatStatus == down && currBeatStatus == maintenance) ||
 		(prevBeatStatus == up && currBeatStatus == maintenance) ||
@@ -70,20 +83,65 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 		(prevBeatStatus == pending && currBeatStatus == down)
 }
 
+// latestHeartbeat returns the newest heartbeat of the monitor, buffered or stored
+func (s *HealthCheckSupervisor) latestHeartbeat(ctx context.Context, monitorID string) *heartbeat.Model {
+	if hb := s.bufferedHeartbeat(monitorID); hb != nil {
+		return hb
+	}
+	beats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, monitorID, 1, 0, nil, false)
+	if err != nil {
+		s.logger.Errorf("Failed to get previous heartbeat for monitor %s: %v", monitorID, err)
+	}
+	if len(beats) > 0 {
+		return beats[0]
+	}
+	return nil
+}
+
+// parentIsDown tells whether the latest check of the parent monitor failed,
+// either on its own or because of a parent further up
+func (s *HealthCheckSupervisor) parentIsDown(ctx context.Context, parentID string) bool {
+	latest := s.latestHeartbeat(ctx, parentID)
+	return latest != nil &&
+		(latest.Status == shared.MonitorStatusDown || latest.Status == shared.MonitorStatusDependencyDown)
+}
+
+// bufferedHeartbeat returns the newest heartbeat of the monitor not stored yet
+func (s *HealthCheckSupervisor) bufferedHeartbeat(monitorID string) *heartbeat.Model {
+	if s.heartbeatWriter == nil {
+		return nil
+	}
+	return s.heartbeatWriter.Latest(monitorID)
+}
+
+// bufferHeartbeat stores the heartbeat with the next batch
+func (s *HealthCheckSupervisor) bufferHeartbeat(ctx context.Context, hb *heartbeat.CreateUpdateDto) error {
+	if s.heartbeatWriter == nil {
+		_, err := s.heartbeatService.Create(ctx, hb)
+		return err
+	}
+	return s.heartbeatWriter.Write(ctx, hb)
+}
+
+// writeHeartbeat stores the heartbeat, and everything buffered before it, immediately
+func (s *HealthCheckSupervisor) writeHeartbeat(ctx context.Context, hb *heartbeat.CreateUpdateDto) (*heartbeat.Model, error) {
+	if s.heartbeatWriter == nil {
+		return s.heartbeatService.Create(ctx, hb)
+	}
+	return s.heartbeatWriter.WriteNow(ctx, hb)
+}
+
 func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m *Monitor, intervalUpdateCb func(newInterval time.Duration)) {
 	ping := int(result.EndTime.Sub(result.StartTime).Milliseconds())
+	duration := result.Duration
+	if duration == 0 {
+		duration = result.EndTime.Sub(result.StartTime)
+	}
 
 	ctx := context.Background()
 
-	// get the previous heartbeat
-	previousBeats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, m.ID, 1, 0, nil, false)
-	var previousBeat *heartbeat.Model = nil
-	if err != nil {
-		s.logger.Errorf("Failed to get previous heartbeat for monitor %s: %v", m.ID, err)
-	}
-	if len(previousBeats) > 0 {
-		previousBeat = previousBeats[0]
-	}
+	// get the previous heartbeat, it may still be waiting in the write buffer
+	previousBeat := s.latestHeartbeat(ctx, m.ID)
 
 	s.logger.Debugf("previousBeat %t", previousBeat != nil)
 
@@ -94,13 +152,15 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		Status:    result.Status,
 		Msg:       result.Message,
 		Ping:      ping,
-		Duration:  0,
+		Duration:  int(duration.Milliseconds()),
 		DownCount: 0,
 		Retries:   0,
 		Important: false,
 		Time:      result.StartTime,
 		EndTime:   result.EndTime,
 		Notified:  false,
+		Headers:   result.Headers,
+		Samples:   result.Samples.HeartBeatSamples(),
 	}
 
 	if !isFirstBeat {
@@ -124,6 +184,13 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		hb.Retries = 0
 	}
 
+	// a monitor failing together with the monitor it depends on is affected
+	// by the outage of the parent, only the parent alerts
+	if hb.Status == shared.MonitorStatusDown && m.ParentID != "" && s.parentIsDown(ctx, m.ParentID) {
+		hb.Status = shared.MonitorStatusDependencyDown
+		hb.Msg = fmt.Sprintf("Affected by dependency, parent monitor %s is down: %s", m.ParentID, hb.Msg)
+	}
+
 	s.logger.Debugf("isFirstBeat for: %s %t", m.Name, isFirstBeat)
 	s.logger.Debugf("checking if important for: %s", m.Name)
 	isImportant := isFirstBeat || s.isImportantBeat(previousBeat.Status, hb.Status)
@@ -140,7 +207,8 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		// 	Status: &hb.Status,
 		// })
 
-		if isFirstBeat || s.isImportantForNotification(previousBeat.Status, hb.Status) {
+		if (isFirstBeat && hb.Status != shared.MonitorStatusDependencyDown) ||
+			(!isFirstBeat && s.isImportantForNotification(previousBeat.Status, hb.Status)) {
 			s.logger.Debugf("sending notification %s", m.Name)
 			shouldNotify = true
 			hb.Notified = true
@@ -152,7 +220,7 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 	} else {
 		hb.Important = false
 
-		if result.Status == shared.MonitorStatusDown && m.ResendInterval > 0 {
+		if result.Status == shared.MonitorStatusDown && hb.Status != shared.MonitorStatusDependencyDown && m.ResendInterval > 0 {
 			hb.DownCount += 1
 
 			if hb.DownCount >= m.ResendInterval {
@@ -175,7 +243,32 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 
 	// TODO: calculate uptime
 
-	dbHb, err := s.heartbeatService.Create(ctx, hb)
+	if !isFirstBeat && previousBeat.Status != hb.Status {
+		defer s.publishStateTransition(previousBeat.Status, hb)
+	}
+
+	// only successful checks say something about the target getting slower
+	if result.Status == shared.MonitorStatusUp {
+		if slow := s.slowChecks.observe(m.ID, duration, m.SlowCheckThreshold); slow != nil {
+			s.logger.Warnf("%s: %s", m.Name, slow)
+			defer s.publishSlowCheck(hb, slow)
+		}
+	}
+
+	if s.neverSucceeded.observe(m, hb.Status, time.Now().UTC(), func() bool { return s.hasSucceeded(ctx, m) }) {
+		s.logger.Warnf("%s has not succeeded once since it was created", m.Name)
+		defer s.publishNeverSucceeded(hb, m)
+	}
+
+	// status changes are stored right away so notifications and readers see them
+	if !hb.Important && !shouldNotify {
+		if err := s.bufferHeartbeat(ctx, hb); err != nil {
+			s.logger.Errorf("Failed to create heartbeat", err.Error())
+		}
+		return
+	}
+
+	dbHb, err := s.writeHeartbeat(ctx, hb)
 	if err != nil {
 		s.logger.Errorf("Failed to create heartbeat", err.Error())
 		return
@@ -189,14 +282,85 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 	}
 }
 
+// publishStateTransition announces that the status of a monitor changed
+// since its previous check
+func (s *HealthCheckSupervisor) publishStateTransition(previous heartbeat.MonitorStatus, hb *heartbeat.CreateUpdateDto) {
+	s.eventBus.Publish(events.Event{
+		Type: events.MonitorStateTransition,
+		Payload: &shared.MonitorStateTransition{
+			MonitorID:      hb.MonitorID,
+			PreviousStatus: previous,
+			Status:         hb.Status,
+			Msg:            hb.Msg,
+			Time:           hb.Time,
+		},
+	})
+}
+
+// publishSlowCheck announces a slowdown of the checks of a monitor
+func (s *HealthCheckSupervisor) publishSlowCheck(hb *heartbeat.CreateUpdateDto, slow *slowCheck) {
+	s.eventBus.Publish(events.Event{
+		Type: events.MonitorSlowCheck,
+		Payload: &heartbeat.Model{
+			MonitorID: hb.MonitorID,
+			Status:    hb.Status,
+			Msg:       slow.String(),
+			Ping:      hb.Ping,
+			Duration:  hb.Duration,
+			Time:      hb.Time,
+			EndTime:   hb.EndTime,
+		},
+	})
+}
+
+// hasSucceeded tells whether the stored history of a monitor holds a
+// successful check. Lookup errors count as success so a failing database
+// never triggers the alert.
+func (s *HealthCheckSupervisor) hasSucceeded(ctx context.Context, m *Monitor) bool {
+	now := time.Now().UTC()
+	uptime, err := s.heartbeatService.FindUptimeStatsByMonitorID(ctx, m.ID, map[string]time.Duration{"all": now.Sub(m.CreatedAt)}, now)
+	if err != nil {
+		s.logger.Errorf("Failed to get uptime of monitor %s: %v", m.ID, err)
+		return true
+	}
+	return uptime["all"] > 0
+}
+
+// publishNeverSucceeded announces that a monitor has been failing since it
+// was created
+func (s *HealthCheckSupervisor) publishNeverSucceeded(hb *heartbeat.CreateUpdateDto, m *Monitor) {
+	age := hb.Time.Sub(m.CreatedAt).Round(time.Minute)
+	s.eventBus.Publish(events.Event{
+		Type: events.MonitorNeverSucceeded,
+		Payload: &heartbeat.Model{
+			MonitorID: hb.MonitorID,
+			Status:    hb.Status,
+			Msg:       fmt.Sprintf("Monitor has not succeeded once since it was created %s ago, latest check: %s", age, hb.Msg),
+			Ping:      hb.Ping,
+			Duration:  hb.Duration,
+			Time:      hb.Time,
+			EndTime:   hb.EndTime,
+		},
+	})
+}
+
 // handleMonitorTick processes a single monitor tick in its own goroutine.
 func (s *HealthCheckSupervisor) handleMonitorTick(
 	ctx context.Context,
 	m *Monitor,
 	exec executor.Executor,
-	proxyModel *proxy.Model,
+	proxies []*proxy.Model,
 	intervalUpdateCb func(newInterval time.Duration),
 ) {
+	// Never run two checks of the same monitor at once, a tick that fires while
+	// the previous check is still running is skipped
+	run, skipped := s.runLocks.tryLock(m.ID)
+	if run == nil {
+		s.logger.Warnf("skipping check of %s, previous run still in progress (%d overlapping runs skipped)", m.Name, skipped)
+		return
+	}
+	defer run.unlock()
+
 	// Check if monitor is under maintenance
 	isUnderMaintenance, err := s.isUnderMaintenance(ctx, m.ID)
 	s.logger.Debugf("isUnderMaintenance for %s: %t", m.Name, isUnderMaintenance)
@@ -204,7 +368,11 @@ func (s *HealthCheckSupervisor) handleMonitorTick(
 		s.logger.Errorf("Failed to check maintenance status for monitor %s: %v", m.ID, err)
 	}
 
-	if isUnderMaintenance {
+	if isUnderMaintenance && m.IgnoreMaintenance {
+		s.logger.Debugf("monitor %s ignores maintenance, running check", m.Name)
+	}
+
+	if isUnderMaintenance && !m.IgnoreMaintenance {
 		// If under maintenance, create a maintenance status heartbeat
 		result := &executor.Result{
 			Status:    shared.MonitorStatusMaintenance,
@@ -216,17 +384,75 @@ func (s *HealthCheckSupervisor) handleMonitorTick(
 		return
 	}
 
-	callCtx, cCancel := context.WithTimeout(
-		ctx,
-		time.Duration(m.Timeout)*time.Second,
-	)
+	// An expired client certificate fails the handshake with an opaque
+	// error, report it as such without probing
+	if result := executor.CheckClientCredentials(exec, m.Config, time.Now().UTC()); result != nil {
+		s.postProcessHeartbeat(result, m, intervalUpdateCb)
+		return
+	}
+
+	// A host that kept timing out only gets a TCP connect until it is
+	// reachable again, then the full check runs right away
+	if address, ok := s.lightProbes.probing(m.ID); ok {
+		if result := s.runLightProbe(ctx, m, address); result != nil {
+			s.postProcessHeartbeat(result, m, withInterval(intervalUpdateCb, lightProbeInterval(m)))
+			return
+		}
+	}
+
+	// Respect the probe rate limits before the check timeout starts
+	throttled, err := s.execRegistry.WaitForProbeSlot(ctx, m)
+	if err != nil {
+		s.logger.Debugf("probe slot wait for %s aborted: %v", m.Name, err)
+		return
+	}
+
+	// Queue for a check slot, the wait does not count against the timeout
+	release, err := s.checkSlots.acquire(ctx)
+	if err != nil {
+		s.logger.Debugf("check slot wait for %s aborted: %v", m.Name, err)
+		return
+	}
+
+	timeout := executor.CheckTimeout(exec, m)
+	callCtx, cCancel := context.WithTimeout(ctx, timeout)
 	defer cCancel()
 
 	// Execute the health check
+	checkStart := time.Now()
+	proxyModel := s.selectProxy(proxies)
 	result := exec.Execute(callCtx, m, proxyModel)
+	s.publishProxyOutcome(m, proxyModel, result)
+
+	// A check failing through a proxy that stopped accepting connections is
+	// retried once through the next healthy proxy, with a timeout of its own
+	if failover := s.failoverProxy(ctx, result, proxies, proxyModel); failover != nil {
+		s.logger.Warnf("%s failed through unhealthy proxy %s, failing over to %s", m.Name, proxyAddress(proxyModel), proxyAddress(failover))
+		retryCtx, retryCancel := context.WithTimeout(ctx, timeout)
+		result = exec.Execute(retryCtx, m, failover)
+		retryCancel()
+		s.publishProxyOutcome(m, failover, result)
+		if result != nil {
+			result.Message = fmt.Sprintf("%s (through failover proxy %s)", result.Message, proxyAddress(failover))
+		}
+	}
+	release()
 	if result == nil {
 		return
 	}
+	result.Duration = time.Since(checkStart)
+
+	if throttled > 0 {
+		result.Message = fmt.Sprintf("%s (delayed %s by probe rate limit)", result.Message, throttled.Round(time.Millisecond))
+	}
+
+	address, lightProbing := lightProbeAddress(exec, m)
+	if s.lightProbes.observe(m.ID, lightProbing && isTimeout(result, callCtx, timeout), m.LightProbeAfter, address) {
+		interval := lightProbeInterval(m)
+		s.logger.Warnf("%s timed out %d times in a row, light probing %s every %s", m.Name, m.LightProbeAfter, address, interval)
+		result.Message = fmt.Sprintf("%s (timed out %d times in a row, light probing every %s)", result.Message, m.LightProbeAfter, interval)
+		intervalUpdateCb = withInterval(intervalUpdateCb, interval)
+	}
 
 	s.postProcessHeartbeat(result, m, intervalUpdateCb)
 }
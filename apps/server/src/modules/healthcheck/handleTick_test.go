@@ -0,0 +1,328 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/config"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeHeartbeatService keeps created heartbeats in memory, newest first per monitor
+type fakeHeartbeatService struct {
+	heartbeat.Service
+	mu    sync.Mutex
+	beats map[string][]*heartbeat.Model
+}
+
+func newFakeHeartbeatService() *fakeHeartbeatService {
+	return &fakeHeartbeatService{beats: make(map[string][]*heartbeat.Model)}
+}
+
+func (f *fakeHeartbeatService) Create(ctx context.Context, dto *heartbeat.CreateUpdateDto) (*heartbeat.Model, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hb := &heartbeat.Model{
+		ID:        time.Now().String(),
+		MonitorID: dto.MonitorID,
+		Status:    dto.Status,
+		Msg:       dto.Msg,
+		Ping:      dto.Ping,
+		Duration:  dto.Duration,
+		DownCount: dto.DownCount,
+		Retries:   dto.Retries,
+		Important: dto.Important,
+		Time:      dto.Time,
+		EndTime:   dto.EndTime,
+		Notified:  dto.Notified,
+	}
+	f.beats[dto.MonitorID] = append([]*heartbeat.Model{hb}, f.beats[dto.MonitorID]...)
+	return hb, nil
+}
+
+func (f *fakeHeartbeatService) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	beats := f.beats[monitorID]
+	if len(beats) > limit {
+		beats = beats[:limit]
+	}
+	return beats, nil
+}
+
+func (f *fakeHeartbeatService) latest(monitorID string) *heartbeat.Model {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.beats[monitorID]) == 0 {
+		return nil
+	}
+	return f.beats[monitorID][0]
+}
+
+// fakeMaintenanceService reports every monitor in monitorIDs as under maintenance
+type fakeMaintenanceService struct {
+	maintenance.Service
+	monitorIDs map[string]bool
+}
+
+func (f *fakeMaintenanceService) GetMaintenancesByMonitorID(ctx context.Context, monitorID string) ([]*maintenance.Model, error) {
+	if f.monitorIDs[monitorID] {
+		return []*maintenance.Model{{ID: "maintenance1", Active: true}}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeMaintenanceService) IsUnderMaintenance(ctx context.Context, m *maintenance.Model) (bool, error) {
+	return m.Active, nil
+}
+
+// stubExecutor returns a fixed status for every check
+type stubExecutor struct {
+	status shared.MonitorStatus
+	calls  int
+	mu     sync.Mutex
+}
+
+func (e *stubExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	e.mu.Lock()
+	e.calls++
+	e.mu.Unlock()
+	now := time.Now().UTC()
+	return &executor.Result{Status: e.status, Message: "stub", StartTime: now, EndTime: now}
+}
+
+func (e *stubExecutor) Validate(configJSON string) error { return nil }
+
+func (e *stubExecutor) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func newTestSupervisor(hb *fakeHeartbeatService, ms maintenance.Service, bus *events.EventBus) *HealthCheckSupervisor {
+	return NewHealthCheck(nil, ms, hb, nil, bus, nil, zap.NewNop().Sugar(), nil, nil)
+}
+
+func TestHandleMonitorTick_IgnoreMaintenance(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	ms := &fakeMaintenanceService{monitorIDs: map[string]bool{"flagged": true, "regular": true}}
+	s := newTestSupervisor(hb, ms, bus)
+
+	notified := make(chan string, 10)
+	bus.Subscribe(events.MonitorStatusChanged, func(event events.Event) {
+		notified <- event.Payload.(*heartbeat.Model).MonitorID
+	})
+
+	flagged := &Monitor{ID: "flagged", Name: "flagged", Interval: 60, Timeout: 5, IgnoreMaintenance: true}
+	regular := &Monitor{ID: "regular", Name: "regular", Interval: 60, Timeout: 5}
+
+	// Both monitors start out UP before the outage
+	for _, m := range []*Monitor{flagged, regular} {
+		hb.Create(context.Background(), &heartbeat.CreateUpdateDto{MonitorID: m.ID, Status: shared.MonitorStatusUp})
+	}
+
+	flaggedExec := &stubExecutor{status: shared.MonitorStatusDown}
+	regularExec := &stubExecutor{status: shared.MonitorStatusDown}
+
+	s.handleMonitorTick(context.Background(), flagged, flaggedExec, nil, nil)
+	s.handleMonitorTick(context.Background(), regular, regularExec, nil, nil)
+
+	// The flagged monitor is checked and alerts despite the active window
+	assert.Equal(t, 1, flaggedExec.calls)
+	assert.Equal(t, shared.MonitorStatusDown, hb.latest("flagged").Status)
+
+	// The regular monitor is suppressed
+	assert.Equal(t, 0, regularExec.calls)
+	assert.Equal(t, shared.MonitorStatusMaintenance, hb.latest("regular").Status)
+
+	select {
+	case id := <-notified:
+		assert.Equal(t, "flagged", id)
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification for the flagged monitor")
+	}
+
+	select {
+	case id := <-notified:
+		t.Fatalf("unexpected notification for monitor %s", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// fakeHeartbeatRepository stores batches in the fake heartbeat service
+type fakeHeartbeatRepository struct {
+	heartbeat.Repository
+	service *fakeHeartbeatService
+	batches int
+}
+
+func (r *fakeHeartbeatRepository) CreateBatch(ctx context.Context, heartbeats []*heartbeat.Model) ([]*heartbeat.Model, error) {
+	r.batches++
+	created := make([]*heartbeat.Model, 0, len(heartbeats))
+	for _, hb := range heartbeats {
+		stored, _ := r.service.Create(ctx, &heartbeat.CreateUpdateDto{
+			MonitorID: hb.MonitorID,
+			Status:    hb.Status,
+			Msg:       hb.Msg,
+			Retries:   hb.Retries,
+			DownCount: hb.DownCount,
+			Important: hb.Important,
+			Notified:  hb.Notified,
+			Time:      hb.Time,
+		})
+		created = append(created, stored)
+	}
+	return created, nil
+}
+
+func TestHandleMonitorTick_BufferedHeartbeats(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	repo := &fakeHeartbeatRepository{service: hb}
+	writer := heartbeat.NewWriter(repo, bus, &config.Config{HeartbeatBufferSize: 100, HeartbeatFlushInterval: time.Hour}, zap.NewNop().Sugar())
+	s := NewHealthCheck(nil, &fakeMaintenanceService{}, hb, writer, bus, nil, zap.NewNop().Sugar(), nil, nil)
+
+	notified := make(chan *heartbeat.Model, 10)
+	bus.Subscribe(events.MonitorStatusChanged, func(event events.Event) {
+		notified <- event.Payload.(*heartbeat.Model)
+	})
+
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5, MaxRetries: 1}
+	exec := &stubExecutor{status: shared.MonitorStatusUp}
+
+	// the first beat is a status change and stored immediately
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	assert.Equal(t, 1, repo.batches)
+	<-notified
+
+	// regular beats wait in the buffer
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	exec.status = shared.MonitorStatusDown
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	assert.Equal(t, 1, repo.batches)
+
+	// the pending beat in the buffer counts as the previous beat, so its retry is seen
+	pending := writer.Latest("api")
+	if assert.NotNil(t, pending) {
+		assert.Equal(t, shared.MonitorStatusPending, pending.Status)
+		assert.Equal(t, 1, pending.Retries)
+	}
+
+	// the retry goes down and is flushed together with the buffered beats
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	assert.Equal(t, 2, repo.batches)
+	assert.Nil(t, writer.Latest("api"))
+	assert.Len(t, hb.beats["api"], 4)
+
+	select {
+	case down := <-notified:
+		assert.Equal(t, shared.MonitorStatusDown, down.Status)
+		assert.NotEmpty(t, down.ID)
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification for the outage")
+	}
+}
+
+func TestHandleMonitorTick_StateTransitions(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, bus)
+
+	transitions := make(chan *shared.MonitorStateTransition, 10)
+	bus.Subscribe(events.MonitorStateTransition, func(event events.Event) {
+		transitions <- event.Payload.(*shared.MonitorStateTransition)
+	})
+
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5}
+	exec := &stubExecutor{status: shared.MonitorStatusUp}
+
+	// the first beat has nothing to transition from, repeated statuses neither
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+
+	exec.status = shared.MonitorStatusDown
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+
+	exec.status = shared.MonitorStatusUp
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+
+	var got []*shared.MonitorStateTransition
+	for len(got) < 2 {
+		select {
+		case transition := <-transitions:
+			got = append(got, transition)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 transitions, got %d", len(got))
+		}
+	}
+	select {
+	case transition := <-transitions:
+		t.Fatalf("unexpected transition %+v", transition)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// handlers run concurrently, order the transitions by time
+	if got[0].Time.After(got[1].Time) {
+		got[0], got[1] = got[1], got[0]
+	}
+	assert.Equal(t, shared.MonitorStatusUp, got[0].PreviousStatus)
+	assert.Equal(t, shared.MonitorStatusDown, got[0].Status)
+	assert.Equal(t, "stub", got[0].Msg)
+	assert.Equal(t, shared.MonitorStatusDown, got[1].PreviousStatus)
+	assert.Equal(t, shared.MonitorStatusUp, got[1].Status)
+}
+
+func TestHandleMonitorTick_DependencyDown(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	router := &Monitor{ID: "router", Name: "router", Interval: 60, Timeout: 5}
+	api := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5, ResendInterval: 1, ParentID: "router"}
+	routerExec := &stubExecutor{status: shared.MonitorStatusUp}
+	apiExec := &stubExecutor{status: shared.MonitorStatusUp}
+
+	s.handleMonitorTick(context.Background(), router, routerExec, nil, nil)
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+
+	// the parent goes down and alerts, the child failing with it does not
+	routerExec.status = shared.MonitorStatusDown
+	apiExec.status = shared.MonitorStatusDown
+	s.handleMonitorTick(context.Background(), router, routerExec, nil, nil)
+	assert.True(t, hb.latest("router").Notified)
+
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+	affected := hb.latest("api")
+	assert.Equal(t, shared.MonitorStatusDependencyDown, affected.Status)
+	assert.True(t, affected.Important)
+	assert.False(t, affected.Notified)
+	assert.Contains(t, affected.Msg, "parent monitor router is down")
+
+	// no resends while the parent is down
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+	assert.Equal(t, shared.MonitorStatusDependencyDown, hb.latest("api").Status)
+	assert.False(t, hb.latest("api").Notified)
+
+	// the parent recovers but the child is still down, now the child alerts
+	routerExec.status = shared.MonitorStatusUp
+	s.handleMonitorTick(context.Background(), router, routerExec, nil, nil)
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+	assert.Equal(t, shared.MonitorStatusDown, hb.latest("api").Status)
+	assert.True(t, hb.latest("api").Notified)
+
+	// a child recovering from an outage of its parent stays quiet
+	routerExec.status = shared.MonitorStatusDown
+	s.handleMonitorTick(context.Background(), router, routerExec, nil, nil)
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+	assert.Equal(t, shared.MonitorStatusDependencyDown, hb.latest("api").Status)
+
+	apiExec.status = shared.MonitorStatusUp
+	s.handleMonitorTick(context.Background(), api, apiExec, nil, nil)
+	assert.Equal(t, shared.MonitorStatusUp, hb.latest("api").Status)
+	assert.False(t, hb.latest("api").Notified)
+}
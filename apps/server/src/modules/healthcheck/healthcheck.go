@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"peekaping/src/config"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
@@ -24,10 +25,17 @@ type HealthCheckSupervisor struct {
 	maintenanceSvc   maintenance.Service
 	execRegistry     *executor.ExecutorRegistry
 	heartbeatService heartbeat.Service
+	heartbeatWriter  *heartbeat.Writer
 	eventBus         *events.EventBus
 	logger           *zap.SugaredLogger
 	proxyService     proxy.Service
 	maxJitterSeconds int64 // configurable jitter for testing
+	checkJitter      bool  // offset check loops by a fraction of their interval
+	runLocks         *monitorRunLocks
+	slowChecks       *slowCheckDetector
+	neverSucceeded   *neverSucceededDetector
+	lightProbes      *lightProbeTracker
+	checkSlots       *checkSlots
 }
 
 type task struct {
@@ -40,10 +48,12 @@ func NewHealthCheck(
 	monitorService monitor.Service,
 	maintenanceService maintenance.Service,
 	heartbeatService heartbeat.Service,
+	heartbeatWriter *heartbeat.Writer,
 	eventBus *events.EventBus,
 	execRegistry *executor.ExecutorRegistry,
 	logger *zap.SugaredLogger,
 	proxyService proxy.Service,
+	cfg *config.Config,
 ) *HealthCheckSupervisor {
 	return &HealthCheckSupervisor{
 		active:           make(map[string]*task),
@@ -51,10 +61,17 @@ func NewHealthCheck(
 		maintenanceSvc:   maintenanceService,
 		execRegistry:     execRegistry,
 		heartbeatService: heartbeatService,
+		heartbeatWriter:  heartbeatWriter,
 		eventBus:         eventBus,
 		logger:           logger.With("service", "[healthcheck]"),
 		proxyService:     proxyService,
+		runLocks:         newMonitorRunLocks(),
+		slowChecks:       newSlowCheckDetector(),
+		neverSucceeded:   newNeverSucceededDetector(cfg),
+		lightProbes:      newLightProbeTracker(),
+		checkSlots:       newCheckSlots(cfg),
 		maxJitterSeconds: 20, // default production jitter
+		checkJitter:      cfg != nil && cfg.CheckJitter,
 	}
 }
 
@@ -63,10 +80,12 @@ func NewHealthCheckWithJitter(
 	monitorService monitor.Service,
 	maintenanceService maintenance.Service,
 	heartbeatService heartbeat.Service,
+	heartbeatWriter *heartbeat.Writer,
 	eventBus *events.EventBus,
 	execRegistry *executor.ExecutorRegistry,
 	logger *zap.SugaredLogger,
 	proxyService proxy.Service,
+	cfg *config.Config,
 	maxJitterSeconds int64,
 ) *HealthCheckSupervisor {
 	return &HealthCheckSupervisor{
@@ -75,10 +94,17 @@ func NewHealthCheckWithJitter(
 		maintenanceSvc:   maintenanceService,
 		execRegistry:     execRegistry,
 		heartbeatService: heartbeatService,
+		heartbeatWriter:  heartbeatWriter,
 		eventBus:         eventBus,
 		logger:           logger.With("service", "[healthcheck]"),
 		proxyService:     proxyService,
+		runLocks:         newMonitorRunLocks(),
+		slowChecks:       newSlowCheckDetector(),
+		neverSucceeded:   newNeverSucceededDetector(cfg),
+		lightProbes:      newLightProbeTracker(),
+		checkSlots:       newCheckSlots(cfg),
 		maxJitterSeconds: maxJitterSeconds,
+		checkJitter:      cfg != nil && cfg.CheckJitter,
 	}
 }
 
@@ -115,30 +141,40 @@ func (s *HealthCheckSupervisor) StartMonitor(
 		t.cancel()
 		<-t.done
 	}
+	// a changed monitor starts with full checks again
+	s.lightProbes.forget(m.ID)
+	s.execRegistry.Forget(m.ID)
 
 	ctx, cancel := context.WithCancel(ctx)
 	done := make(chan struct{})
 	intervalUpdate := make(chan time.Duration, 1)
 
-	// Fetch proxy once here
-	var proxyModel *proxy.Model = nil
-	if m.ProxyId != "" {
-		p, err := s.proxyService.FindByID(ctx, m.ProxyId)
-		if err != nil {
-			s.logger.Errorf("Failed to fetch proxy for monitor %s: %v", m.ID, err)
-		} else if p != nil {
-			proxyModel = p
-		}
-	}
+	// Fetch the proxies once here, each tick picks a healthy one
+	proxies := s.loadProxies(ctx, m)
 
 	go func() {
 		defer close(done)
 		interval := time.Duration(m.Interval) * time.Second
+		wait := interval
 
 		// Add random jitter before starting the loop
-		if withJitter && s.maxJitterSeconds > 0 {
-			jitter := time.Duration(rand.Int63n(int64(s.maxJitterSeconds))) * time.Second
-			time.Sleep(jitter)
+		var jitter time.Duration
+		if s.checkJitter {
+			jitter = jitterOffset(interval)
+			if !withJitter {
+				// a new or changed monitor is checked right away, the
+				// offset moves the checks after the first one
+				wait, jitter = jitter, 0
+			}
+		} else if withJitter && s.maxJitterSeconds > 0 {
+			jitter = time.Duration(rand.Int63n(int64(s.maxJitterSeconds))) * time.Second
+		}
+		if jitter > 0 {
+			select {
+			case <-time.After(jitter):
+			case <-ctx.Done():
+				return
+			}
 		}
 
 		// Get the appropriate executor for this monitor type
@@ -149,27 +185,56 @@ func (s *HealthCheckSupervisor) StartMonitor(
 			return
 		}
 
-		// Run once immediately
-		go s.handleMonitorTick(ctx, m, executor, proxyModel, func(newInterval time.Duration) {
-			intervalUpdate <- newInterval
+		scheduleChecks(ctx, interval, wait, intervalUpdate, func(intervalUpdateCb func(time.Duration)) {
+			go s.handleMonitorTick(ctx, m, executor, proxies, intervalUpdateCb)
 		})
+	}()
 
-		for {
-			select {
-			case <-time.After(interval):
-				go s.handleMonitorTick(ctx, m, executor, proxyModel, func(newInterval time.Duration) {
-					intervalUpdate <- newInterval
-				})
-			case newInterval := <-intervalUpdate:
+	s.active[m.ID] = &task{cancel: cancel, done: done, intervalUpdate: intervalUpdate}
+	return nil
+}
+
+// scheduleChecks runs a check right away, the next one after wait and the
+// following ones every interval until ctx is done. A check reports the
+// interval to continue with, only a changed interval restarts the wait so
+// the first wait is kept.
+func scheduleChecks(
+	ctx context.Context,
+	interval, wait time.Duration,
+	intervalUpdate chan time.Duration,
+	check func(intervalUpdateCb func(newInterval time.Duration)),
+) {
+	intervalUpdateCb := func(newInterval time.Duration) {
+		intervalUpdate <- newInterval
+	}
+
+	// Run once immediately
+	check(intervalUpdateCb)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			timer.Reset(interval)
+			check(intervalUpdateCb)
+		case newInterval := <-intervalUpdate:
+			if newInterval != interval {
 				interval = newInterval
-			case <-ctx.Done():
-				return
+				timer.Reset(interval)
 			}
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
+}
 
-	s.active[m.ID] = &task{cancel: cancel, done: done, intervalUpdate: intervalUpdate}
-	return nil
+// jitterOffset is a random offset within the interval
+func jitterOffset(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(interval)))
 }
 
 func (s *HealthCheckSupervisor) DeleteMonitor(monitorId string) {
@@ -180,6 +245,11 @@ func (s *HealthCheckSupervisor) DeleteMonitor(monitorId string) {
 		<-t.done
 		delete(s.active, monitorId)
 	}
+	s.runLocks.forget(monitorId)
+	s.slowChecks.forget(monitorId)
+	s.neverSucceeded.forget(monitorId)
+	s.lightProbes.forget(monitorId)
+	s.execRegistry.Forget(monitorId)
 }
 
 func (s *HealthCheckSupervisor) Shutdown() {
@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestJitterOffset(t *testing.T) {
+	interval := time.Minute
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		offset := jitterOffset(interval)
+		assert.GreaterOrEqual(t, offset, time.Duration(0))
+		assert.Less(t, offset, interval)
+		seen[offset] = true
+	}
+	assert.Greater(t, len(seen), 1, "offsets are random")
+	assert.Zero(t, jitterOffset(0))
+}
+
+func TestStartMonitor_JitterWaitIsCancelled(t *testing.T) {
+	s := NewHealthCheck(nil, nil, nil, nil, nil, nil, zap.NewNop().Sugar(), nil, &config.Config{CheckJitter: true})
+	require.True(t, s.checkJitter)
+
+	// a day long interval keeps the loop waiting out its offset
+	require.NoError(t, s.StartMonitor(context.Background(), &Monitor{ID: "m1", Type: "http", Interval: 86400}, true))
+
+	stopped := make(chan struct{})
+	go func() {
+		s.DeleteMonitor("m1")
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("deleting the monitor waited for the jitter offset")
+	}
+}
+
+func TestScheduleChecks_KeepsFirstWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 300 * time.Millisecond
+	offset := 50 * time.Millisecond
+	ticks := make(chan time.Time, 10)
+	start := time.Now()
+	go scheduleChecks(ctx, interval, offset, make(chan time.Duration, 1), func(intervalUpdateCb func(time.Duration)) {
+		ticks <- time.Now()
+		// every check reports the interval to continue with, like postProcessHeartbeat
+		intervalUpdateCb(interval)
+	})
+
+	next := func() time.Duration {
+		select {
+		case tick := <-ticks:
+			return tick.Sub(start)
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected another check")
+			return 0
+		}
+	}
+
+	assert.Less(t, next(), offset, "the first check runs right away")
+	second := next()
+	assert.GreaterOrEqual(t, second, offset)
+	assert.Less(t, second, interval, "the offset is kept when the first check reports its interval")
+	third := next()
+	assert.InDelta(t, float64(offset+interval), float64(third), float64(100*time.Millisecond))
+}
+
+func TestScheduleChecks_ChangedIntervalRestartsWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan time.Time, 10)
+	start := time.Now()
+	retryInterval := 100 * time.Millisecond
+	go scheduleChecks(ctx, time.Hour, time.Hour, make(chan time.Duration, 1), func(intervalUpdateCb func(time.Duration)) {
+		ticks <- time.Now()
+		// a failing check continues with the retry interval
+		intervalUpdateCb(retryInterval)
+	})
+
+	<-ticks
+	select {
+	case tick := <-ticks:
+		assert.InDelta(t, float64(retryInterval), float64(tick.Sub(start)), float64(80*time.Millisecond))
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the retry interval to apply")
+	}
+}
@@ -0,0 +1,146 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+)
+
+// lightProbeTimeout caps the TCP connect of a light probe, a reachable host
+// accepts the connection well within it
+const lightProbeTimeout = 5 * time.Second
+
+// lightProbeState is what the tracker knows about the timeouts of a monitor
+type lightProbeState struct {
+	timeouts int
+	// address is only set while the monitor is light probed
+	address string
+}
+
+// lightProbeTracker counts consecutive timeouts of full checks. After enough
+// of them a monitor only gets a TCP connect to its host at a longer interval,
+// so a host that is down for long does not cost a full timeout every check.
+type lightProbeTracker struct {
+	mu     sync.Mutex
+	states map[string]*lightProbeState
+}
+
+func newLightProbeTracker() *lightProbeTracker {
+	return &lightProbeTracker{states: make(map[string]*lightProbeState)}
+}
+
+// probing returns the address of a monitor that is light probed
+func (t *lightProbeTracker) probing(monitorID string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.states[monitorID]
+	if !ok || state.address == "" {
+		return "", false
+	}
+	return state.address, true
+}
+
+// observe records whether a full check timed out. It returns true when the
+// timeouts reach after and switches the monitor to light probing address. An
+// empty address or an after of 0 never switches.
+func (t *lightProbeTracker) observe(monitorID string, timedOut bool, after int, address string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !timedOut || after <= 0 || address == "" {
+		delete(t.states, monitorID)
+		return false
+	}
+
+	state, ok := t.states[monitorID]
+	if !ok {
+		state = &lightProbeState{}
+		t.states[monitorID] = state
+	}
+	state.timeouts++
+	if state.timeouts < after {
+		return false
+	}
+	state.address = address
+	return true
+}
+
+// timeouts returns the consecutive timeouts counted for a monitor
+func (t *lightProbeTracker) timeouts(monitorID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state, ok := t.states[monitorID]; ok {
+		return state.timeouts
+	}
+	return 0
+}
+
+// forget returns the monitor to full checks
+func (t *lightProbeTracker) forget(monitorID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, monitorID)
+}
+
+// lightProbeInterval is the interval of the light probes, five check
+// intervals unless configured
+func lightProbeInterval(m *Monitor) time.Duration {
+	if m.LightProbeInterval > 0 {
+		return time.Duration(m.LightProbeInterval) * time.Second
+	}
+	return 5 * time.Duration(m.Interval) * time.Second
+}
+
+// lightProbeAddress returns the address a monitor would be light probed at.
+// Proxied monitors are not, the host may only be reachable through the proxy.
+func lightProbeAddress(exec executor.Executor, m *Monitor) (string, bool) {
+	if m.LightProbeAfter <= 0 || m.ProxyId != "" {
+		return "", false
+	}
+	return executor.ReachabilityAddress(exec, m.Config)
+}
+
+// isTimeout reports whether a full check failed by running into the timeout
+// of the monitor rather than with an answer from the host
+func isTimeout(result *executor.Result, callCtx context.Context, timeout time.Duration) bool {
+	if result.Status != shared.MonitorStatusDown {
+		return false
+	}
+	return errors.Is(callCtx.Err(), context.DeadlineExceeded) || (timeout > 0 && result.Duration >= timeout)
+}
+
+// runLightProbe connects to the host of a light probed monitor. It returns the
+// down result while the host is unreachable, nil once it is reachable again
+// and the monitor is back on full checks.
+func (s *HealthCheckSupervisor) runLightProbe(ctx context.Context, m *Monitor, address string) *executor.Result {
+	timeout := time.Duration(m.Timeout) * time.Second
+	if timeout <= 0 || timeout > lightProbeTimeout {
+		timeout = lightProbeTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := executor.ProbeReachability(probeCtx, address)
+	if result.Status == shared.MonitorStatusUp {
+		s.logger.Infof("%s is reachable again at %s, resuming full checks", m.Name, address)
+		s.lightProbes.forget(m.ID)
+		return nil
+	}
+
+	result.Message = fmt.Sprintf("%s (light probing every %s)", result.Message, lightProbeInterval(m))
+	return result
+}
+
+// withInterval replaces the interval a heartbeat would schedule
+func withInterval(intervalUpdateCb func(newInterval time.Duration), interval time.Duration) func(newInterval time.Duration) {
+	if intervalUpdateCb == nil {
+		return nil
+	}
+	return func(time.Duration) {
+		intervalUpdateCb(interval)
+	}
+}
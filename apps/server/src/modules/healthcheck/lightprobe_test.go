@@ -0,0 +1,170 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// hangingExecutor fails like an unreachable host when the check runs into its
+// timeout and succeeds otherwise
+type hangingExecutor struct {
+	address string
+	mu      sync.Mutex
+	calls   int
+}
+
+func (e *hangingExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	e.mu.Lock()
+	e.calls++
+	e.mu.Unlock()
+	now := time.Now().UTC()
+	if err := ctx.Err(); err != nil {
+		return executor.DownResult(err, now, now)
+	}
+	return &executor.Result{Status: shared.MonitorStatusUp, Message: "ok", StartTime: now, EndTime: now}
+}
+
+func (e *hangingExecutor) Validate(configJSON string) error { return nil }
+
+func (e *hangingExecutor) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func (e *hangingExecutor) ReachabilityAddress(cfg any) (string, bool) { return e.address, true }
+
+// expiredContext is a check context whose timeout has already passed
+func expiredContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestLightProbeTracker_ConsecutiveTimeouts(t *testing.T) {
+	tracker := newLightProbeTracker()
+
+	assert.False(t, tracker.observe("api", true, 3, "db:5432"))
+	assert.False(t, tracker.observe("api", true, 3, "db:5432"))
+	// an answer from the host starts the count over
+	assert.False(t, tracker.observe("api", false, 3, "db:5432"))
+	assert.Equal(t, 0, tracker.timeouts("api"))
+
+	assert.False(t, tracker.observe("api", true, 3, "db:5432"))
+	assert.False(t, tracker.observe("api", true, 3, "db:5432"))
+	_, probing := tracker.probing("api")
+	assert.False(t, probing)
+
+	assert.True(t, tracker.observe("api", true, 3, "db:5432"))
+	address, probing := tracker.probing("api")
+	assert.True(t, probing)
+	assert.Equal(t, "db:5432", address)
+
+	tracker.forget("api")
+	_, probing = tracker.probing("api")
+	assert.False(t, probing)
+}
+
+func TestLightProbeTracker_Disabled(t *testing.T) {
+	tracker := newLightProbeTracker()
+
+	for i := 0; i < 5; i++ {
+		assert.False(t, tracker.observe("off", true, 0, "db:5432"))
+		assert.False(t, tracker.observe("no-address", true, 1, ""))
+	}
+	assert.Equal(t, 0, tracker.timeouts("off"))
+	assert.Equal(t, 0, tracker.timeouts("no-address"))
+}
+
+func TestLightProbeAddress(t *testing.T) {
+	exec := &hangingExecutor{address: "db:5432"}
+
+	address, ok := lightProbeAddress(exec, &Monitor{LightProbeAfter: 3})
+	assert.True(t, ok)
+	assert.Equal(t, "db:5432", address)
+
+	_, ok = lightProbeAddress(exec, &Monitor{})
+	assert.False(t, ok, "light probing is off by default")
+
+	_, ok = lightProbeAddress(exec, &Monitor{LightProbeAfter: 3, ProxyId: "proxy"})
+	assert.False(t, ok, "a proxied host may not be reachable directly")
+
+	_, ok = lightProbeAddress(&stubExecutor{}, &Monitor{LightProbeAfter: 3})
+	assert.False(t, ok, "executors without a single host cannot be light probed")
+}
+
+func TestLightProbeInterval(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, lightProbeInterval(&Monitor{Interval: 60}))
+	assert.Equal(t, 10*time.Minute, lightProbeInterval(&Monitor{Interval: 60, LightProbeInterval: 600}))
+}
+
+func TestHandleMonitorTick_LightProbing(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	// a port nobody listens on yet, connecting to it is refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	exec := &hangingExecutor{address: address}
+	m := &Monitor{ID: "db", Name: "db", Interval: 60, RetryInterval: 30, Timeout: 5, LightProbeAfter: 2, LightProbeInterval: 300}
+
+	var intervals []time.Duration
+	intervalUpdate := func(newInterval time.Duration) { intervals = append(intervals, newInterval) }
+
+	// the first timeout keeps full checks at the retry interval
+	s.handleMonitorTick(expiredContext(t), m, exec, nil, intervalUpdate)
+	assert.Equal(t, 30*time.Second, intervals[len(intervals)-1])
+	_, probing := s.lightProbes.probing("db")
+	assert.False(t, probing)
+
+	// the second one switches to light probing at its interval
+	s.handleMonitorTick(expiredContext(t), m, exec, nil, intervalUpdate)
+	_, probing = s.lightProbes.probing("db")
+	assert.True(t, probing)
+	assert.Equal(t, 5*time.Minute, intervals[len(intervals)-1])
+	assert.Contains(t, hb.latest("db").Msg, "light probing every 5m0s")
+
+	// while the host is unreachable only the TCP connect runs
+	s.handleMonitorTick(context.Background(), m, exec, nil, intervalUpdate)
+	assert.Equal(t, 2, exec.calls)
+	assert.Equal(t, shared.MonitorStatusDown, hb.latest("db").Status)
+	assert.Contains(t, hb.latest("db").Msg, "unreachable")
+	assert.Equal(t, 5*time.Minute, intervals[len(intervals)-1])
+
+	// once the host accepts connections the full check runs again right away
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	s.handleMonitorTick(context.Background(), m, exec, nil, intervalUpdate)
+	assert.Equal(t, 3, exec.calls)
+	assert.Equal(t, shared.MonitorStatusUp, hb.latest("db").Status)
+	assert.Equal(t, 60*time.Second, intervals[len(intervals)-1])
+	_, probing = s.lightProbes.probing("db")
+	assert.False(t, probing)
+}
+
+func TestHandleMonitorTick_FailuresWithAnswerKeepFullChecks(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	exec := &stubExecutor{status: shared.MonitorStatusDown}
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5, LightProbeAfter: 1}
+
+	// the stub has no host to probe, and it fails fast rather than timing out
+	for i := 0; i < 3; i++ {
+		s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	}
+	assert.Equal(t, 3, exec.calls)
+	_, probing := s.lightProbes.probing("api")
+	assert.False(t, probing)
+}
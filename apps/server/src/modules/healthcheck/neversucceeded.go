@@ -0,0 +1,101 @@
+package healthcheck
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+)
+
+// neverSucceededMaxAge bounds how old a monitor may be and still be reported.
+// Older monitors may have lost their successful checks to the heartbeat
+// retention, so their history no longer tells whether they ever worked.
+const neverSucceededMaxAge = 30 * 24 * time.Hour
+
+// launchState is what the detector knows about a monitor since its creation
+type launchState struct {
+	succeeded bool
+	notified  bool
+}
+
+// neverSucceededDetector notices monitors that keep failing from the moment
+// they were created, which usually points at a misconfiguration rather than
+// an outage of the target
+type neverSucceededDetector struct {
+	mu     sync.Mutex
+	after  time.Duration
+	states map[string]*launchState
+}
+
+func newNeverSucceededDetector(cfg *config.Config) *neverSucceededDetector {
+	d := &neverSucceededDetector{states: make(map[string]*launchState)}
+	if cfg != nil {
+		d.after = cfg.NeverSucceededAlertAfter
+	}
+	return d
+}
+
+// observe records the status of a check. It returns true once when the
+// monitor is failing, was created longer ago than the configured duration and
+// has not had a single successful check since. hasSucceeded is consulted
+// before reporting so successful checks stored before a restart still count.
+func (d *neverSucceededDetector) observe(m *Monitor, status shared.MonitorStatus, now time.Time, hasSucceeded func() bool) bool {
+	if d.after <= 0 || m.CreatedAt.IsZero() {
+		return false
+	}
+
+	d.mu.Lock()
+	state, ok := d.states[m.ID]
+	if !ok {
+		state = &launchState{}
+		d.states[m.ID] = state
+	}
+	if status == shared.MonitorStatusUp {
+		state.succeeded = true
+	}
+	failing := status == shared.MonitorStatusDown || status == shared.MonitorStatusPending
+	if !failing || state.succeeded || state.notified {
+		d.mu.Unlock()
+		return false
+	}
+
+	age := now.Sub(m.CreatedAt)
+	if age > launchHorizon(m) {
+		state.succeeded = true
+	}
+	if age < d.after || state.succeeded {
+		d.mu.Unlock()
+		return false
+	}
+	d.mu.Unlock()
+
+	// checks of one monitor never overlap, so the state is not changed
+	// by another check while the history is looked up
+	succeeded := hasSucceeded()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if succeeded {
+		state.succeeded = true
+		return false
+	}
+	state.notified = true
+	return true
+}
+
+// launchHorizon is the age up to which the history of a monitor still covers
+// its whole life
+func launchHorizon(m *Monitor) time.Duration {
+	if m.RetentionDays > 0 {
+		if retention := time.Duration(m.RetentionDays) * 24 * time.Hour; retention < neverSucceededMaxAge {
+			return retention
+		}
+	}
+	return neverSucceededMaxAge
+}
+
+func (d *neverSucceededDetector) forget(monitorID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.states, monitorID)
+}
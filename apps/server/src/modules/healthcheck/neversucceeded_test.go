@@ -0,0 +1,121 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/config"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func noHistory() bool { return false }
+
+func TestNeverSucceededDetector_NotifiesOnceAfterThreshold(t *testing.T) {
+	d := newNeverSucceededDetector(&config.Config{NeverSucceededAlertAfter: time.Hour})
+	created := time.Now().UTC()
+	m := &Monitor{ID: "api", CreatedAt: created}
+
+	assert.False(t, d.observe(m, shared.MonitorStatusDown, created.Add(30*time.Minute), noHistory))
+	assert.False(t, d.observe(m, shared.MonitorStatusPending, created.Add(59*time.Minute), noHistory))
+	assert.True(t, d.observe(m, shared.MonitorStatusDown, created.Add(61*time.Minute), noHistory))
+	assert.False(t, d.observe(m, shared.MonitorStatusDown, created.Add(2*time.Hour), noHistory))
+}
+
+func TestNeverSucceededDetector_SuccessfulCheckPreventsAlert(t *testing.T) {
+	d := newNeverSucceededDetector(&config.Config{NeverSucceededAlertAfter: time.Hour})
+	created := time.Now().UTC()
+	m := &Monitor{ID: "api", CreatedAt: created}
+
+	assert.False(t, d.observe(m, shared.MonitorStatusUp, created.Add(time.Minute), noHistory))
+	assert.False(t, d.observe(m, shared.MonitorStatusDown, created.Add(2*time.Hour), noHistory))
+}
+
+func TestNeverSucceededDetector_StoredSuccessPreventsAlert(t *testing.T) {
+	d := newNeverSucceededDetector(&config.Config{NeverSucceededAlertAfter: time.Hour})
+	created := time.Now().UTC()
+	m := &Monitor{ID: "api", CreatedAt: created}
+
+	lookups := 0
+	succeeded := func() bool { lookups++; return true }
+	assert.False(t, d.observe(m, shared.MonitorStatusDown, created.Add(2*time.Hour), succeeded))
+	assert.False(t, d.observe(m, shared.MonitorStatusDown, created.Add(3*time.Hour), succeeded))
+	assert.Equal(t, 1, lookups)
+}
+
+func TestNeverSucceededDetector_IgnoresMaintenanceAndOldMonitors(t *testing.T) {
+	d := newNeverSucceededDetector(&config.Config{NeverSucceededAlertAfter: time.Hour})
+	now := time.Now().UTC()
+
+	m := &Monitor{ID: "api", CreatedAt: now.Add(-2 * time.Hour)}
+	assert.False(t, d.observe(m, shared.MonitorStatusMaintenance, now, noHistory))
+
+	old := &Monitor{ID: "old", CreatedAt: now.Add(-neverSucceededMaxAge - time.Hour)}
+	assert.False(t, d.observe(old, shared.MonitorStatusDown, now, noHistory))
+
+	// the history of a short retention no longer covers the first checks
+	short := &Monitor{ID: "short", CreatedAt: now.Add(-50 * time.Hour), RetentionDays: 2}
+	assert.False(t, d.observe(short, shared.MonitorStatusDown, now, noHistory))
+}
+
+func TestNeverSucceededDetector_Disabled(t *testing.T) {
+	now := time.Now().UTC()
+	m := &Monitor{ID: "api", CreatedAt: now.Add(-2 * time.Hour)}
+
+	assert.False(t, newNeverSucceededDetector(nil).observe(m, shared.MonitorStatusDown, now, noHistory))
+	disabled := newNeverSucceededDetector(&config.Config{NeverSucceededAlertAfter: -1})
+	assert.False(t, disabled.observe(m, shared.MonitorStatusDown, now, noHistory))
+}
+
+// uptimeHeartbeatService reports no successful check in the stored history
+type uptimeHeartbeatService struct {
+	*fakeHeartbeatService
+}
+
+func (f *uptimeHeartbeatService) FindUptimeStatsByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]float64, error) {
+	return map[string]float64{"all": 0}, nil
+}
+
+func TestHandleMonitorTick_NeverSucceededAlert(t *testing.T) {
+	hb := &uptimeHeartbeatService{newFakeHeartbeatService()}
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	s := NewHealthCheck(nil, &fakeMaintenanceService{}, hb, nil, bus, nil, zap.NewNop().Sugar(), nil,
+		&config.Config{NeverSucceededAlertAfter: time.Hour})
+
+	alerts := make(chan *heartbeat.Model, 10)
+	bus.Subscribe(events.MonitorNeverSucceeded, func(event events.Event) {
+		alerts <- event.Payload.(*heartbeat.Model)
+	})
+
+	exec := &stubExecutor{status: shared.MonitorStatusDown}
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5, CreatedAt: time.Now().UTC().Add(-30 * time.Minute)}
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	select {
+	case <-alerts:
+		t.Fatal("unexpected alert before the threshold")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the monitor crosses the threshold without ever succeeding
+	m.CreatedAt = time.Now().UTC().Add(-2 * time.Hour)
+	for i := 0; i < 3; i++ {
+		s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	}
+	select {
+	case event := <-alerts:
+		assert.Equal(t, "api", event.MonitorID)
+		assert.Equal(t, shared.MonitorStatusDown, event.Status)
+		assert.Contains(t, event.Msg, "has not succeeded once since it was created 2h0m0s ago")
+	case <-time.After(time.Second):
+		t.Fatal("expected an alert for the monitor that never succeeded")
+	}
+	select {
+	case <-alerts:
+		t.Fatal("expected the alert to be sent once")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,90 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/proxy"
+	"peekaping/src/modules/shared"
+	"time"
+)
+
+// loadProxies returns the proxy of the monitor followed by its failover
+// proxies, the failovers only apply to a monitor with a proxy
+func (s *HealthCheckSupervisor) loadProxies(ctx context.Context, m *Monitor) []*proxy.Model {
+	if m.ProxyId == "" || s.proxyService == nil {
+		return nil
+	}
+
+	var proxies []*proxy.Model
+	for _, id := range append([]string{m.ProxyId}, m.FailoverProxyIds...) {
+		p, err := s.proxyService.FindByID(ctx, id)
+		if err != nil {
+			s.logger.Errorf("Failed to fetch proxy %s for monitor %s: %v", id, m.ID, err)
+			continue
+		}
+		if p != nil {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// selectProxy returns the first healthy proxy of the monitor, the first one
+// when none is healthy
+func (s *HealthCheckSupervisor) selectProxy(proxies []*proxy.Model) *proxy.Model {
+	if len(proxies) == 0 {
+		return nil
+	}
+	for _, p := range proxies {
+		if s.proxyService.IsHealthy(p.ID) {
+			return p
+		}
+	}
+	return proxies[0]
+}
+
+// failoverProxy returns the proxy to retry a failed check through. The proxy
+// the check used is checked again, and only when it does not accept
+// connections the next healthy proxy is returned.
+func (s *HealthCheckSupervisor) failoverProxy(ctx context.Context, result *executor.Result, proxies []*proxy.Model, used *proxy.Model) *proxy.Model {
+	if used == nil || len(proxies) < 2 || result == nil || result.Status != shared.MonitorStatusDown {
+		return nil
+	}
+	if status := s.proxyService.CheckProxyHealth(ctx, used); status.Healthy {
+		return nil
+	}
+
+	for _, p := range proxies {
+		if p.ID != used.ID && s.proxyService.IsHealthy(p.ID) {
+			return p
+		}
+	}
+	return nil
+}
+
+// publishProxyOutcome announces the result of a check routed through a proxy
+// so the proxy statistics are kept in one place
+func (s *HealthCheckSupervisor) publishProxyOutcome(m *Monitor, p *proxy.Model, result *executor.Result) {
+	if p == nil || result == nil {
+		return
+	}
+	outcome := &shared.ProxyCheckOutcome{
+		ProxyID:   p.ID,
+		MonitorID: m.ID,
+		Success:   result.Status == shared.MonitorStatusUp,
+		Time:      time.Now().UTC(),
+	}
+	if !outcome.Success {
+		outcome.Error = result.Message
+	}
+	s.eventBus.Publish(events.Event{
+		Type:    events.ProxyCheckCompleted,
+		Payload: outcome,
+	})
+}
+
+func proxyAddress(p *proxy.Model) string {
+	return fmt.Sprintf("%s:%d", p.Host, p.Port)
+}
@@ -0,0 +1,122 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/proxy"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeProxyService reports the proxies in unhealthy as not accepting connections
+type fakeProxyService struct {
+	proxy.Service
+	mu        sync.Mutex
+	unhealthy map[string]bool
+	checked   map[string]bool
+	rechecks  []string
+}
+
+func (f *fakeProxyService) IsHealthy(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.checked[id] || !f.unhealthy[id]
+}
+
+func (f *fakeProxyService) CheckProxyHealth(ctx context.Context, p *proxy.Model) proxy.HealthStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checked[p.ID] = true
+	f.rechecks = append(f.rechecks, p.ID)
+	return proxy.HealthStatus{ProxyID: p.ID, Healthy: !f.unhealthy[p.ID], CheckedAt: time.Now().UTC()}
+}
+
+// proxyExecutor is down through the proxies in down and records the proxy of every check
+type proxyExecutor struct {
+	down map[string]bool
+	used []string
+}
+
+func (e *proxyExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	e.used = append(e.used, proxyModel.ID)
+	now := time.Now().UTC()
+	if e.down[proxyModel.ID] {
+		return &executor.Result{Status: shared.MonitorStatusDown, Message: "proxyconnect tcp: connection refused", StartTime: now, EndTime: now}
+	}
+	return &executor.Result{Status: shared.MonitorStatusUp, Message: "200 - OK", StartTime: now, EndTime: now}
+}
+
+func (e *proxyExecutor) Validate(configJSON string) error { return nil }
+
+func (e *proxyExecutor) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func TestHandleMonitorTick_ProxyFailover(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	proxies := &fakeProxyService{unhealthy: map[string]bool{"primary": true}, checked: map[string]bool{}}
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	outcomes := make(chan *shared.ProxyCheckOutcome, 8)
+	bus.Subscribe(events.ProxyCheckCompleted, func(event events.Event) {
+		outcomes <- event.Payload.(*shared.ProxyCheckOutcome)
+	})
+	s := NewHealthCheck(nil, &fakeMaintenanceService{}, hb, nil, bus, nil, zap.NewNop().Sugar(), proxies, nil)
+
+	m := &Monitor{ID: "proxied", Name: "proxied", Interval: 60, Timeout: 5}
+	candidates := []*proxy.Model{
+		{ID: "primary", Host: "primary.local", Port: 3128},
+		{ID: "secondary", Host: "secondary.local", Port: 3128},
+	}
+	exec := &proxyExecutor{down: map[string]bool{"primary": true}}
+
+	s.handleMonitorTick(context.Background(), m, exec, candidates, nil)
+	beat := hb.latest("proxied")
+	require.NotNil(t, beat)
+	assert.Equal(t, shared.MonitorStatusUp, beat.Status)
+	assert.Contains(t, beat.Msg, "through failover proxy secondary.local:3128")
+	assert.Equal(t, []string{"primary", "secondary"}, exec.used)
+
+	// both checks are reported against the proxy they went through
+	recorded := map[string]*shared.ProxyCheckOutcome{}
+	for i := 0; i < 2; i++ {
+		select {
+		case outcome := <-outcomes:
+			recorded[outcome.ProxyID] = outcome
+		case <-time.After(time.Second):
+			t.Fatal("expected a proxy check outcome")
+		}
+	}
+	require.Contains(t, recorded, "primary")
+	assert.False(t, recorded["primary"].Success)
+	assert.Equal(t, "proxyconnect tcp: connection refused", recorded["primary"].Error)
+	require.Contains(t, recorded, "secondary")
+	assert.True(t, recorded["secondary"].Success)
+	assert.Equal(t, "proxied", recorded["secondary"].MonitorID)
+
+	// the unhealthy primary is skipped until it accepts connections again
+	s.handleMonitorTick(context.Background(), m, exec, candidates, nil)
+	assert.Equal(t, []string{"primary", "secondary", "secondary"}, exec.used)
+	assert.Equal(t, []string{"primary"}, proxies.rechecks, "expected only failed checks to recheck their proxy")
+}
+
+func TestHandleMonitorTick_ProxyFailoverKeepsHealthyPrimary(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	proxies := &fakeProxyService{unhealthy: map[string]bool{}, checked: map[string]bool{}}
+	s := NewHealthCheck(nil, &fakeMaintenanceService{}, hb, nil, events.NewEventBus(zap.NewNop().Sugar()), nil, zap.NewNop().Sugar(), proxies, nil)
+
+	m := &Monitor{ID: "proxied", Name: "proxied", Interval: 60, Timeout: 5}
+	candidates := []*proxy.Model{{ID: "primary", Host: "primary.local", Port: 3128}, {ID: "secondary", Host: "secondary.local", Port: 3128}}
+	exec := &proxyExecutor{down: map[string]bool{"primary": true}}
+
+	// the target is down, not the proxy
+	s.handleMonitorTick(context.Background(), m, exec, candidates, nil)
+	beat := hb.latest("proxied")
+	require.NotNil(t, beat)
+	assert.Equal(t, shared.MonitorStatusDown, beat.Status)
+	assert.Equal(t, []string{"primary"}, exec.used)
+}
@@ -0,0 +1,77 @@
+package healthcheck
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// monitorRunState tracks whether a check of a monitor is in flight and how
+// many ticks were skipped because the previous check was still running
+type monitorRunState struct {
+	running atomic.Bool
+	skipped atomic.Uint64
+}
+
+// monitorRunLocks guarantees a monitor never has two concurrent executions
+type monitorRunLocks struct {
+	mu     sync.Mutex
+	states map[string]*monitorRunState
+}
+
+func newMonitorRunLocks() *monitorRunLocks {
+	return &monitorRunLocks{states: make(map[string]*monitorRunState)}
+}
+
+func (l *monitorRunLocks) state(monitorID string) *monitorRunState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st, ok := l.states[monitorID]
+	if !ok {
+		st = &monitorRunState{}
+		l.states[monitorID] = st
+	}
+	return st
+}
+
+// tryLock marks the monitor as running. When a previous run is still in flight
+// the skip is counted and nil is returned, otherwise the caller must release
+// the returned state once the run is finished.
+func (l *monitorRunLocks) tryLock(monitorID string) (*monitorRunState, uint64) {
+	st := l.state(monitorID)
+	if !st.running.CompareAndSwap(false, true) {
+		return nil, st.skipped.Add(1)
+	}
+	return st, 0
+}
+
+func (st *monitorRunState) unlock() {
+	st.running.Store(false)
+}
+
+// skippedRuns returns the number of skipped overlapping runs per monitor
+func (l *monitorRunLocks) skippedRuns() map[string]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make(map[string]uint64, len(l.states))
+	for id, st := range l.states {
+		if skipped := st.skipped.Load(); skipped > 0 {
+			result[id] = skipped
+		}
+	}
+	return result
+}
+
+// forget drops the state of a deleted monitor. A run still in flight keeps
+// its own reference and releases it normally.
+func (l *monitorRunLocks) forget(monitorID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.states, monitorID)
+}
+
+// SkippedOverlappingRuns returns, per monitor, how many ticks were skipped
+// because the previous check had not finished yet. Monitors that never
+// overlapped are omitted.
+func (s *HealthCheckSupervisor) SkippedOverlappingRuns() map[string]uint64 {
+	return s.runLocks.skippedRuns()
+}
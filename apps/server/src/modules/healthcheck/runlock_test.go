@@ -0,0 +1,80 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// slowExecutor blocks every check until release is closed
+type slowExecutor struct {
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (e *slowExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	e.calls.Add(1)
+	e.started <- struct{}{}
+	<-e.release
+	now := time.Now().UTC()
+	return &executor.Result{Status: shared.MonitorStatusUp, Message: "slow", StartTime: now, EndTime: now}
+}
+
+func (e *slowExecutor) Validate(configJSON string) error { return nil }
+
+func (e *slowExecutor) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func TestHandleMonitorTick_SkipsOverlappingRuns(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	m := &Monitor{ID: "slow", Name: "slow", Interval: 1, Timeout: 5}
+	other := &Monitor{ID: "other", Name: "other", Interval: 1, Timeout: 5}
+	exec := &slowExecutor{started: make(chan struct{}, 10), release: make(chan struct{})}
+
+	finished := make(chan struct{})
+	go func() {
+		s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+		close(finished)
+	}()
+
+	select {
+	case <-exec.started:
+	case <-time.After(time.Second):
+		t.Fatal("first check did not start")
+	}
+
+	// The next ticks fire while the first check is still running
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	assert.Equal(t, int32(1), exec.calls.Load())
+	assert.Equal(t, map[string]uint64{"slow": 2}, s.SkippedOverlappingRuns())
+
+	// Other monitors are not blocked by the slow one
+	fast := &stubExecutor{status: shared.MonitorStatusUp}
+	s.handleMonitorTick(context.Background(), other, fast, nil, nil)
+	assert.Equal(t, 1, fast.calls)
+
+	close(exec.release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("first check did not finish")
+	}
+	require.NotNil(t, hb.latest("slow"))
+
+	// Once the run finished, the next tick executes again
+	s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	assert.Equal(t, int32(2), exec.calls.Load())
+	assert.Equal(t, map[string]uint64{"slow": 2}, s.SkippedOverlappingRuns())
+	assert.Len(t, hb.beats["slow"], 2)
+}
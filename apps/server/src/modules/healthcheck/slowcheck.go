@@ -0,0 +1,100 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// slowCheckWindow is how many checks are averaged on each side of the
+// baseline/recent comparison
+const slowCheckWindow = 5
+
+// durationTrend holds the latest check durations of a monitor, oldest first
+type durationTrend struct {
+	durations []time.Duration
+	slow      bool
+}
+
+// slowCheckDetector notices monitors whose checks keep getting slower by
+// comparing the average duration of the latest checks to the checks before
+type slowCheckDetector struct {
+	mu     sync.Mutex
+	trends map[string]*durationTrend
+}
+
+func newSlowCheckDetector() *slowCheckDetector {
+	return &slowCheckDetector{trends: make(map[string]*durationTrend)}
+}
+
+// slowCheck describes a rise of the check duration over its baseline
+type slowCheck struct {
+	Recent   time.Duration
+	Baseline time.Duration
+}
+
+func (c *slowCheck) String() string {
+	rise := (c.Recent - c.Baseline) * 100 / c.Baseline
+	return fmt.Sprintf(
+		"Check duration rising: average %s over the last %d checks, up from %s (+%d%%)",
+		c.Recent.Round(time.Millisecond), slowCheckWindow, c.Baseline.Round(time.Millisecond), rise,
+	)
+}
+
+// observe records the duration of a check. It returns the rise when the recent
+// average exceeds the baseline by more than thresholdPct percent and the
+// monitor was not slow already, so a slowdown is reported once. A threshold
+// of 0 disables the detection.
+func (d *slowCheckDetector) observe(monitorID string, duration time.Duration, thresholdPct int) *slowCheck {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if thresholdPct <= 0 {
+		delete(d.trends, monitorID)
+		return nil
+	}
+
+	trend, ok := d.trends[monitorID]
+	if !ok {
+		trend = &durationTrend{}
+		d.trends[monitorID] = trend
+	}
+
+	trend.durations = append(trend.durations, duration)
+	if len(trend.durations) > 2*slowCheckWindow {
+		trend.durations = trend.durations[len(trend.durations)-2*slowCheckWindow:]
+	}
+	if len(trend.durations) < 2*slowCheckWindow {
+		return nil
+	}
+
+	check := &slowCheck{
+		Baseline: averageDuration(trend.durations[:slowCheckWindow]),
+		Recent:   averageDuration(trend.durations[slowCheckWindow:]),
+	}
+	rising := check.Baseline > 0 && check.Recent*100 > check.Baseline*time.Duration(100+thresholdPct)
+	if !rising {
+		trend.slow = false
+		return nil
+	}
+	if trend.slow {
+		return nil
+	}
+	trend.slow = true
+	return check
+}
+
+// forget drops the durations of a deleted monitor
+func (d *slowCheckDetector) forget(monitorID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.trends, monitorID)
+}
+
+func averageDuration(durations []time.Duration) time.Duration {
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
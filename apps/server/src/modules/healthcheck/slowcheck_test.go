@@ -0,0 +1,168 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func observeAll(d *slowCheckDetector, monitorID string, threshold int, durations ...time.Duration) []*slowCheck {
+	var fired []*slowCheck
+	for _, duration := range durations {
+		if slow := d.observe(monitorID, duration, threshold); slow != nil {
+			fired = append(fired, slow)
+		}
+	}
+	return fired
+}
+
+func repeatDuration(d time.Duration, n int) []time.Duration {
+	durations := make([]time.Duration, n)
+	for i := range durations {
+		durations[i] = d
+	}
+	return durations
+}
+
+func TestSlowCheckDetector_RisingTrend(t *testing.T) {
+	d := newSlowCheckDetector()
+
+	durations := append(repeatDuration(100*time.Millisecond, slowCheckWindow), repeatDuration(250*time.Millisecond, slowCheckWindow)...)
+	fired := observeAll(d, "api", 50, durations...)
+
+	require.Len(t, fired, 1)
+	assert.Equal(t, 100*time.Millisecond, fired[0].Baseline)
+	assert.Equal(t, 250*time.Millisecond, fired[0].Recent)
+	assert.Contains(t, fired[0].String(), "+150%")
+}
+
+func TestSlowCheckDetector_StableDurations(t *testing.T) {
+	d := newSlowCheckDetector()
+
+	fired := observeAll(d, "api", 50, repeatDuration(100*time.Millisecond, 4*slowCheckWindow)...)
+	assert.Empty(t, fired)
+
+	// a rise within the threshold is not a slowdown
+	fired = observeAll(d, "api", 50, repeatDuration(140*time.Millisecond, slowCheckWindow)...)
+	assert.Empty(t, fired)
+}
+
+func TestSlowCheckDetector_ReportsOncePerSlowdown(t *testing.T) {
+	d := newSlowCheckDetector()
+
+	observeAll(d, "api", 50, repeatDuration(100*time.Millisecond, slowCheckWindow)...)
+	fired := observeAll(d, "api", 50, 300*time.Millisecond, 300*time.Millisecond, 300*time.Millisecond, 300*time.Millisecond, 300*time.Millisecond, 300*time.Millisecond)
+	assert.Len(t, fired, 1)
+
+	// once the baseline caught up the next rise is reported again
+	observeAll(d, "api", 50, repeatDuration(300*time.Millisecond, 2*slowCheckWindow)...)
+	fired = observeAll(d, "api", 50, repeatDuration(time.Second, slowCheckWindow)...)
+	assert.Len(t, fired, 1)
+}
+
+func TestSlowCheckDetector_Disabled(t *testing.T) {
+	d := newSlowCheckDetector()
+
+	durations := append(repeatDuration(10*time.Millisecond, slowCheckWindow), repeatDuration(time.Second, slowCheckWindow)...)
+	assert.Empty(t, observeAll(d, "api", 0, durations...))
+	assert.Empty(t, d.trends)
+}
+
+// delayExecutor takes the next of its delays for every check
+type delayExecutor struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (e *delayExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	e.mu.Lock()
+	delay := e.delays[0]
+	e.delays = e.delays[1:]
+	e.mu.Unlock()
+
+	start := time.Now().UTC()
+	time.Sleep(delay)
+	return &executor.Result{Status: shared.MonitorStatusUp, Message: "ok", StartTime: start, EndTime: time.Now().UTC()}
+}
+
+func (e *delayExecutor) Validate(configJSON string) error { return nil }
+
+func (e *delayExecutor) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func TestHandleMonitorTick_StoresCheckDuration(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5}
+	s.handleMonitorTick(context.Background(), m, &delayExecutor{delays: []time.Duration{30 * time.Millisecond}}, nil, nil)
+
+	latest := hb.latest("api")
+	require.NotNil(t, latest)
+	assert.GreaterOrEqual(t, latest.Duration, 30)
+}
+
+func TestHandleMonitorTick_SampledCheckDuration(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, events.NewEventBus(zap.NewNop().Sugar()))
+
+	// the result timing is a single sample while the check took longer
+	now := time.Now().UTC()
+	result := &executor.Result{
+		Status:    shared.MonitorStatusUp,
+		StartTime: now,
+		EndTime:   now.Add(20 * time.Millisecond),
+		Duration:  90 * time.Millisecond,
+	}
+	s.postProcessHeartbeat(result, &Monitor{ID: "api", Name: "api", Interval: 60}, nil)
+
+	latest := hb.latest("api")
+	require.NotNil(t, latest)
+	assert.Equal(t, 20, latest.Ping)
+	assert.Equal(t, 90, latest.Duration)
+}
+
+func TestHandleMonitorTick_SlowCheckAlert(t *testing.T) {
+	hb := newFakeHeartbeatService()
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	s := newTestSupervisor(hb, &fakeMaintenanceService{}, bus)
+
+	slowEvents := make(chan *heartbeat.Model, 10)
+	bus.Subscribe(events.MonitorSlowCheck, func(event events.Event) {
+		slowEvents <- event.Payload.(*heartbeat.Model)
+	})
+
+	m := &Monitor{ID: "api", Name: "api", Interval: 60, Timeout: 5, SlowCheckThreshold: 100}
+	exec := &delayExecutor{delays: append(
+		repeatDuration(5*time.Millisecond, slowCheckWindow),
+		repeatDuration(40*time.Millisecond, slowCheckWindow)...,
+	)}
+
+	for i := 0; i < slowCheckWindow; i++ {
+		s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	}
+	select {
+	case <-slowEvents:
+		t.Fatal("unexpected slow check alert while the durations are stable")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for i := 0; i < slowCheckWindow; i++ {
+		s.handleMonitorTick(context.Background(), m, exec, nil, nil)
+	}
+	select {
+	case event := <-slowEvents:
+		assert.Equal(t, "api", event.MonitorID)
+		assert.Contains(t, event.Msg, "Check duration rising")
+	case <-time.After(time.Second):
+		t.Fatal("expected a slow check alert on the rising durations")
+	}
+}
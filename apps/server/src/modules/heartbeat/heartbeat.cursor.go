@@ -0,0 +1,54 @@
+package heartbeat
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of the last heartbeat of a page. Heartbeats are
+// ordered newest first by time and then ID, so a page continues with the
+// heartbeats strictly before the cursor in that order.
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// CursorPage is a page of heartbeats, NextCursor is empty on the last page
+type CursorPage struct {
+	Items      []*Model `json:"items"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+func cursorOf(hb *Model) *Cursor {
+	return &Cursor{Time: hb.Time, ID: hb.ID}
+}
+
+// Encode returns the opaque token handed to clients
+func (c *Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a token returned by Encode, nil for an empty token
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Time: t, ID: id}, nil
+}
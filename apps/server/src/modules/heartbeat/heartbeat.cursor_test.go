@@ -0,0 +1,179 @@
+package heartbeat
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+// newSQLiteRepository returns a repository on a private in-memory database
+func newSQLiteRepository(tb testing.TB) (Repository, *bun.DB) {
+	tb.Helper()
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", tb.Name()))
+	require.NoError(tb, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	tb.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(ctx)
+	require.NoError(tb, err)
+	_, err = db.NewCreateIndex().Model((*sqlModel)(nil)).Index("idx_heartbeats_monitor_time_id").Column("monitor_id", "time", "id").Exec(ctx)
+	require.NoError(tb, err)
+	return NewSQLRepository(db), db
+}
+
+// seedHeartbeats stores count heartbeats one second apart, pairs of them
+// sharing a timestamp so the ID has to break the tie. Every third is important.
+func seedHeartbeats(tb testing.TB, repo Repository, monitorID string, count int) {
+	tb.Helper()
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	const batchSize = 500
+	for offset := 0; offset < count; offset += batchSize {
+		batch := make([]*Model, 0, batchSize)
+		for i := offset; i < count && i < offset+batchSize; i++ {
+			batch = append(batch, &Model{
+				MonitorID: monitorID,
+				Status:    shared.MonitorStatusUp,
+				Important: i%3 == 0,
+				Time:      start.Add(time.Duration(i/2) * time.Second),
+			})
+		}
+		_, err := repo.CreateBatch(context.Background(), batch)
+		require.NoError(tb, err)
+	}
+}
+
+func newCursorTestService(repo Repository) Service {
+	return NewService(repo, nil, nil, zap.NewNop().Sugar())
+}
+
+// collectPages follows next cursors until the last page
+func collectPages(t *testing.T, service Service, monitorID string, limit int, important *bool) ([]*Model, int) {
+	t.Helper()
+	var all []*Model
+	pages := 0
+	cursor := ""
+	for {
+		page, err := service.FindByMonitorIDAfterCursor(context.Background(), monitorID, limit, cursor, important)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Items), limit)
+		all = append(all, page.Items...)
+		pages++
+		if page.NextCursor == "" {
+			return all, pages
+		}
+		cursor = page.NextCursor
+		require.Less(t, pages, 1000, "cursor pagination does not terminate")
+	}
+}
+
+func TestFindByMonitorIDAfterCursor_CompleteNonOverlappingPages(t *testing.T) {
+	repo, _ := newSQLiteRepository(t)
+	seedHeartbeats(t, repo, "api", 95)
+	seedHeartbeats(t, repo, "other", 10)
+	service := newCursorTestService(repo)
+
+	all, pages := collectPages(t, service, "api", 10, nil)
+	assert.Equal(t, 10, pages)
+	require.Len(t, all, 95)
+
+	seen := make(map[string]bool, len(all))
+	for i, hb := range all {
+		assert.Equal(t, "api", hb.MonitorID)
+		assert.False(t, seen[hb.ID], "heartbeat %s returned twice", hb.ID)
+		seen[hb.ID] = true
+		if i > 0 {
+			prev := all[i-1]
+			ordered := prev.Time.After(hb.Time) || (prev.Time.Equal(hb.Time) && prev.ID > hb.ID)
+			assert.True(t, ordered, "heartbeat %d is not older than the one before it", i)
+		}
+	}
+}
+
+func TestFindByMonitorIDAfterCursor_Important(t *testing.T) {
+	repo, _ := newSQLiteRepository(t)
+	seedHeartbeats(t, repo, "api", 60)
+	service := newCursorTestService(repo)
+
+	important := true
+	all, _ := collectPages(t, service, "api", 7, &important)
+	require.Len(t, all, 20)
+	for _, hb := range all {
+		assert.True(t, hb.Important)
+	}
+}
+
+func TestFindByMonitorIDAfterCursor_LastPage(t *testing.T) {
+	repo, _ := newSQLiteRepository(t)
+	seedHeartbeats(t, repo, "api", 20)
+	service := newCursorTestService(repo)
+
+	// a full last page has no next cursor
+	page, err := service.FindByMonitorIDAfterCursor(context.Background(), "api", 20, "", nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 20)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = service.FindByMonitorIDAfterCursor(context.Background(), "missing", 20, "", nil)
+	require.NoError(t, err)
+	assert.NotNil(t, page.Items)
+	assert.Empty(t, page.Items)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	cursor := &Cursor{Time: time.Date(2025, 7, 1, 12, 30, 0, 123456789, time.UTC), ID: "0197c1f2-5d7a-7b3e-a4e2-1f0c2d3e4f50"}
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.Time.Equal(decoded.Time))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	decoded, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	for _, token := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "eWVzdGVyZGF5fGlk"} {
+		_, err := DecodeCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+// BenchmarkDeepPage compares reading a page deep into a large table by
+// offset and by cursor
+func BenchmarkDeepPage(b *testing.B) {
+	const rows, limit = 100000, 50
+	repo, _ := newSQLiteRepository(b)
+	seedHeartbeats(b, repo, "api", rows)
+	ctx := context.Background()
+
+	// the 1800th page of 50
+	deepPage := rows/limit - 200
+	before, err := repo.FindByMonitorIDPaginated(ctx, "api", 1, deepPage*limit-1, nil, false)
+	require.NoError(b, err)
+	require.Len(b, before, 1)
+	cursor := cursorOf(before[0])
+
+	b.Run("offset", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.FindByMonitorIDPaginated(ctx, "api", limit, deepPage, nil, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cursor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.FindByMonitorIDAfterCursor(ctx, "api", limit, cursor, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
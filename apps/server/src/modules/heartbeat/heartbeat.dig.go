@@ -10,4 +10,5 @@ import (
 func RegisterDependencies(container *dig.Container, cfg *config.Config) {
 	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
 	container.Provide(NewService)
+	container.Provide(NewWriter)
 }
@@ -16,4 +16,8 @@ type CreateUpdateDto struct {
 	Time      time.Time     `json:"time"`
 	EndTime   time.Time     `json:"end_time"`
 	Notified  bool          `json:"notified"`
+	// Response headers captured by the check
+	Headers map[string]string `json:"headers,omitempty"`
+	// Latency distribution of the probes of a sampled check
+	Samples *Samples `json:"samples,omitempty"`
 }
@@ -0,0 +1,30 @@
+package heartbeat
+
+import "sort"
+
+// latencyPercentiles computes the nearest-rank p50, p95 and p99 of the pings
+func latencyPercentiles(pings []int) *LatencyPercentiles {
+	result := &LatencyPercentiles{Count: len(pings)}
+	if len(pings) == 0 {
+		return result
+	}
+
+	sorted := make([]int, len(pings))
+	copy(sorted, pings)
+	sort.Ints(sorted)
+
+	result.P50 = nearestRank(sorted, 50)
+	result.P95 = nearestRank(sorted, 95)
+	result.P99 = nearestRank(sorted, 99)
+	return result
+}
+
+// nearestRank returns the smallest value of sorted with at least p percent of
+// the values less than or equal to it
+func nearestRank(sorted []int, p int) int {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,51 @@
+package heartbeat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		pings    []int
+		expected LatencyPercentiles
+	}{
+		{
+			name:     "no heartbeats",
+			pings:    nil,
+			expected: LatencyPercentiles{},
+		},
+		{
+			name:     "single heartbeat",
+			pings:    []int{42},
+			expected: LatencyPercentiles{P50: 42, P95: 42, P99: 42, Count: 1},
+		},
+		{
+			name:     "unsorted pings",
+			pings:    []int{300, 100, 200, 400},
+			expected: LatencyPercentiles{P50: 200, P95: 400, P99: 400, Count: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, *latencyPercentiles(tt.pings))
+		})
+	}
+}
+
+func TestLatencyPercentiles_Distribution(t *testing.T) {
+	pings := make([]int, 0, 1000)
+	for i := 1000; i >= 1; i-- {
+		pings = append(pings, i)
+	}
+
+	result := latencyPercentiles(pings)
+	assert.Equal(t, 500, result.P50)
+	assert.Equal(t, 950, result.P95)
+	assert.Equal(t, 990, result.P99)
+	assert.Equal(t, 1000, result.Count)
+	assert.Equal(t, 1000, pings[0], "the pings must not be reordered")
+}
@@ -4,4 +4,6 @@ import "peekaping/src/modules/shared"
 
 type Model = shared.HeartBeatModel
 type ChartPoint = shared.HeartBeatChartPoint
+type LatencyPercentiles = shared.HeartBeatLatencyPercentiles
+type Samples = shared.HeartBeatSamples
 type MonitorStatus = shared.MonitorStatus
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"peekaping/src/config"
+	"peekaping/src/modules/shared"
 
 	"time"
 
@@ -26,6 +27,8 @@ type mongoModel struct {
 	Time      time.Time          `bson:"time"`
 	EndTime   time.Time          `bson:"end_time"`
 	Notified  bool               `bson:"notified"`
+	Headers   map[string]string  `bson:"headers,omitempty"`
+	Samples   *Samples           `bson:"samples,omitempty"`
 }
 
 type RepositoryImpl struct {
@@ -48,6 +51,8 @@ func toDomainModel(mm *mongoModel) *Model {
 		Time:      mm.Time,
 		EndTime:   mm.EndTime,
 		Notified:  mm.Notified,
+		Headers:   mm.Headers,
+		Samples:   mm.Samples,
 	}
 }
 
@@ -86,6 +91,8 @@ func (r *RepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, err
 		Time:      entity.Time,
 		EndTime:   entity.EndTime,
 		Notified:  entity.Notified,
+		Headers:   entity.Headers,
+		Samples:   entity.Samples,
 	}
 
 	_, err = r.collection.InsertOne(ctx, mm)
@@ -96,6 +103,51 @@ func (r *RepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, err
 	return toDomainModel(mm), nil
 }
 
+func (r *RepositoryImpl) CreateBatch(ctx context.Context, heartbeats []*Model) ([]*Model, error) {
+	if len(heartbeats) == 0 {
+		return nil, nil
+	}
+
+	docs := make([]any, 0, len(heartbeats))
+	mms := make([]*mongoModel, 0, len(heartbeats))
+	for _, entity := range heartbeats {
+		monitorID, err := primitive.ObjectIDFromHex(entity.MonitorID)
+		if err != nil {
+			return nil, err
+		}
+
+		mm := &mongoModel{
+			ID:        primitive.NewObjectID(),
+			MonitorID: monitorID,
+			Status:    entity.Status,
+			Msg:       entity.Msg,
+			Ping:      entity.Ping,
+			Duration:  entity.Duration,
+			DownCount: entity.DownCount,
+			Retries:   entity.Retries,
+			Important: entity.Important,
+			Time:      entity.Time,
+			EndTime:   entity.EndTime,
+			Notified:  entity.Notified,
+			Headers:   entity.Headers,
+			Samples:   entity.Samples,
+		}
+		docs = append(docs, mm)
+		mms = append(mms, mm)
+	}
+
+	// ordered so a batch lands in the order it was buffered
+	if _, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(true)); err != nil {
+		return nil, err
+	}
+
+	created := make([]*Model, 0, len(mms))
+	for _, mm := range mms {
+		created = append(created, toDomainModel(mm))
+	}
+	return created, nil
+}
+
 func (r *RepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
 	var mm mongoModel
 
@@ -245,6 +297,40 @@ func (r *RepositoryImpl) FindUptimeStatsByMonitorID(ctx context.Context, monitor
 	return result, nil
 }
 
+func (r *RepositoryImpl) FindPingsByMonitorID(ctx context.Context, monitorID string, since, until time.Time) ([]int, error) {
+	objectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"monitor_id": objectID,
+		"status":     shared.MonitorStatusUp,
+		"time":       bson.M{"$gte": since, "$lte": until},
+	}
+	opts := options.Find().SetProjection(bson.M{"ping": 1, "_id": 0})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var pings []int
+	for cursor.Next(ctx) {
+		var entity struct {
+			Ping int `bson:"ping"`
+		}
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		pings = append(pings, entity.Ping)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return pings, nil
+}
+
 func (r *RepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
 	filter := bson.M{"time": bson.M{"$lt": cutoff}}
 	result, err := r.collection.DeleteMany(ctx, filter)
@@ -254,6 +340,20 @@ func (r *RepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time)
 	return result.DeletedCount, nil
 }
 
+func (r *RepositoryImpl) DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"monitor_id": objectID, "time": bson.M{"$lt": cutoff}}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
 func (r *RepositoryImpl) FindByMonitorIDPaginated(
 	ctx context.Context,
 	monitorID string,
@@ -311,6 +411,56 @@ func (r *RepositoryImpl) FindByMonitorIDPaginated(
 	return models, nil
 }
 
+func (r *RepositoryImpl) FindByMonitorIDAfterCursor(
+	ctx context.Context,
+	monitorID string,
+	limit int,
+	cursor *Cursor,
+	important *bool,
+) ([]*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"monitor_id": objectID}
+	if important != nil {
+		filter["important"] = *important
+	}
+	if cursor != nil {
+		cursorID, err := primitive.ObjectIDFromHex(cursor.ID)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter["$or"] = bson.A{
+			bson.M{"time": bson.M{"$lt": cursor.Time}},
+			bson.M{"time": cursor.Time, "_id": bson.M{"$lt": cursorID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "time", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursorRows, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursorRows.Close(ctx)
+
+	models := make([]*Model, 0, limit)
+	for cursorRows.Next(ctx) {
+		var mm mongoModel
+		if err := cursorRows.Decode(&mm); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModel(&mm))
+	}
+	if err := cursorRows.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
 func (r *RepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
 	objectID, err := primitive.ObjectIDFromHex(monitorID)
 	if err != nil {
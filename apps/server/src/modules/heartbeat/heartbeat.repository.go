@@ -7,6 +7,8 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, heartbeat *Model) (*Model, error)
+	// CreateBatch inserts all heartbeats in a single statement and returns them as stored
+	CreateBatch(ctx context.Context, heartbeats []*Model) ([]*Model, error)
 	FindByID(ctx context.Context, id string) (*Model, error)
 	FindAll(ctx context.Context, page int, limit int) ([]*Model, error)
 	FindActive(ctx context.Context) ([]*Model, error)
@@ -20,12 +22,24 @@ type Repository interface {
 		important *bool,
 		reverse bool,
 	) ([]*Model, error)
+	// FindByMonitorIDAfterCursor returns up to limit heartbeats, newest first,
+	// that come after the cursor. A nil cursor starts at the newest heartbeat.
+	FindByMonitorIDAfterCursor(
+		ctx context.Context,
+		monitorID string,
+		limit int,
+		cursor *Cursor,
+		important *bool,
+	) ([]*Model, error)
 	FindUptimeStatsByMonitorID(
 		ctx context.Context,
 		monitorID string,
 		periods map[string]time.Duration,
 		now time.Time,
 	) (map[string]float64, error)
+	// FindPingsByMonitorID returns the ping of every up heartbeat of the monitor between since and until
+	FindPingsByMonitorID(ctx context.Context, monitorID string, since, until time.Time) ([]int, error)
 	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error)
 	DeleteByMonitorID(ctx context.Context, monitorID string) error
 }
@@ -16,8 +16,13 @@ type Service interface {
 	Delete(ctx context.Context, id string) error
 
 	FindUptimeStatsByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]float64, error)
+	FindLatencyPercentilesByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]*LatencyPercentiles, error)
 	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error)
 	FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*Model, error)
+	// FindByMonitorIDAfterCursor pages through heartbeats newest first by
+	// keyset, cursor is the NextCursor of the previous page or empty
+	FindByMonitorIDAfterCursor(ctx context.Context, monitorID string, limit int, cursor string, important *bool) (*CursorPage, error)
 	DeleteByMonitorID(ctx context.Context, monitorID string) error
 }
 
@@ -55,6 +60,8 @@ func (mr *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Mo
 		Time:      entity.Time,
 		EndTime:   entity.EndTime,
 		Notified:  entity.Notified,
+		Headers:   entity.Headers,
+		Samples:   entity.Samples,
 	}
 
 	created, err := mr.repository.Create(ctx, createModel)
@@ -95,14 +102,58 @@ func (mr *ServiceImpl) FindUptimeStatsByMonitorID(ctx context.Context, monitorID
 	return mr.repository.FindUptimeStatsByMonitorID(ctx, monitorID, periods, now)
 }
 
+// FindLatencyPercentilesByMonitorID computes the response time percentiles of
+// the up heartbeats within each period before now
+func (mr *ServiceImpl) FindLatencyPercentilesByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]*LatencyPercentiles, error) {
+	result := make(map[string]*LatencyPercentiles, len(periods))
+	for key, period := range periods {
+		pings, err := mr.repository.FindPingsByMonitorID(ctx, monitorID, now.Add(-period), now)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = latencyPercentiles(pings)
+	}
+	return result, nil
+}
+
 func (mr *ServiceImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
 	return mr.repository.DeleteOlderThan(ctx, cutoff)
 }
 
+func (mr *ServiceImpl) DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error) {
+	return mr.repository.DeleteOlderThanForMonitor(ctx, monitorID, cutoff)
+}
+
 func (mr *ServiceImpl) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*Model, error) {
 	return mr.repository.FindByMonitorIDPaginated(ctx, monitorID, limit, page, important, reverse)
 }
 
+func (mr *ServiceImpl) FindByMonitorIDAfterCursor(ctx context.Context, monitorID string, limit int, cursor string, important *bool) (*CursorPage, error) {
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	// one extra row tells whether another page follows
+	items, err := mr.repository.FindByMonitorIDAfterCursor(ctx, monitorID, limit+1, after, important)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CursorPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = cursorOf(page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []*Model{}
+	}
+	return page, nil
+}
+
 func (mr *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
 	return mr.repository.DeleteByMonitorID(ctx, monitorID)
 }
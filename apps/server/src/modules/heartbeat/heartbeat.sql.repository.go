@@ -25,6 +25,9 @@ type sqlModel struct {
 	Time      time.Time `bun:"time,nullzero,notnull,default:current_timestamp"`
 	EndTime   time.Time `bun:"end_time,nullzero"`
 	Notified  bool      `bun:"notified,notnull,default:false"`
+	// stored as JSON
+	Headers map[string]string `bun:"headers"`
+	Samples *Samples          `bun:"samples"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -41,6 +44,8 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		Time:      sm.Time,
 		EndTime:   sm.EndTime,
 		Notified:  sm.Notified,
+		Headers:   sm.Headers,
+		Samples:   sm.Samples,
 	}
 }
 
@@ -58,6 +63,8 @@ func toSQLModel(m *Model) *sqlModel {
 		Time:      m.Time,
 		EndTime:   m.EndTime,
 		Notified:  m.Notified,
+		Headers:   m.Headers,
+		Samples:   m.Samples,
 	}
 }
 
@@ -82,6 +89,34 @@ func (r *SQLRepositoryImpl) Create(ctx context.Context, heartbeat *Model) (*Mode
 	return toDomainModelFromSQL(sm), nil
 }
 
+func (r *SQLRepositoryImpl) CreateBatch(ctx context.Context, heartbeats []*Model) ([]*Model, error) {
+	if len(heartbeats) == 0 {
+		return nil, nil
+	}
+
+	sms := make([]*sqlModel, 0, len(heartbeats))
+	for _, hb := range heartbeats {
+		sm := toSQLModel(hb)
+		sm.ID = uuid.New().String()
+		// buffered heartbeats keep the time of their check rather than the insert time
+		if sm.Time.IsZero() {
+			sm.Time = time.Now()
+		}
+		sms = append(sms, sm)
+	}
+
+	_, err := r.db.NewInsert().Model(&sms).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		created = append(created, toDomainModelFromSQL(sm))
+	}
+	return created, nil
+}
+
 func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
 	sm := new(sqlModel)
 	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
@@ -176,6 +211,40 @@ func (r *SQLRepositoryImpl) FindByMonitorIDPaginated(
 	return models, nil
 }
 
+func (r *SQLRepositoryImpl) FindByMonitorIDAfterCursor(
+	ctx context.Context,
+	monitorID string,
+	limit int,
+	cursor *Cursor,
+	important *bool,
+) ([]*Model, error) {
+	query := r.db.NewSelect().
+		Model((*sqlModel)(nil)).
+		Where("monitor_id = ?", monitorID)
+
+	if important != nil {
+		query = query.Where("important = ?", *important)
+	}
+	if cursor != nil {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("time < ?", cursor.Time).
+				WhereOr("time = ? AND id < ?", cursor.Time, cursor.ID)
+		})
+	}
+
+	var sms []*sqlModel
+	err := query.Order("time DESC", "id DESC").Limit(limit).Scan(ctx, &sms)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
 func (r *SQLRepositoryImpl) FindUptimeStatsByMonitorID(
 	ctx context.Context,
 	monitorID string,
@@ -213,6 +282,20 @@ func (r *SQLRepositoryImpl) FindUptimeStatsByMonitorID(
 	return stats, nil
 }
 
+func (r *SQLRepositoryImpl) FindPingsByMonitorID(ctx context.Context, monitorID string, since, until time.Time) ([]int, error) {
+	var pings []int
+	err := r.db.NewSelect().
+		Model((*sqlModel)(nil)).
+		Column("ping").
+		Where("monitor_id = ? AND status = ?", monitorID, shared.MonitorStatusUp).
+		Where("time >= ? AND time <= ?", since, until).
+		Scan(ctx, &pings)
+	if err != nil {
+		return nil, err
+	}
+	return pings, nil
+}
+
 func (r *SQLRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
 	result, err := r.db.NewDelete().
 		Model((*sqlModel)(nil)).
@@ -226,6 +309,20 @@ func (r *SQLRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Tim
 	return rowsAffected, nil
 }
 
+func (r *SQLRepositoryImpl) DeleteOlderThanForMonitor(ctx context.Context, monitorID string, cutoff time.Time) (int64, error) {
+	result, err := r.db.NewDelete().
+		Model((*sqlModel)(nil)).
+		Where("monitor_id = ?", monitorID).
+		Where("time < ?", cutoff).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected, nil
+}
+
 func (r *SQLRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
 	_, err := r.db.NewDelete().
 		Model((*sqlModel)(nil)).
@@ -0,0 +1,57 @@
+package heartbeat
+
+import (
+	"context"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLRepository_Headers(t *testing.T) {
+	repo, _ := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	withHeaders, err := repo.Create(ctx, &Model{
+		MonitorID: "monitor1",
+		Status:    shared.MonitorStatusUp,
+		Headers:   map[string]string{"Server": "nginx", "X-Cache": "HIT"},
+	})
+	require.NoError(t, err)
+	withoutHeaders, err := repo.Create(ctx, &Model{MonitorID: "monitor1", Status: shared.MonitorStatusUp})
+	require.NoError(t, err)
+
+	stored, err := repo.FindByID(ctx, withHeaders.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Server": "nginx", "X-Cache": "HIT"}, stored.Headers)
+
+	stored, err = repo.FindByID(ctx, withoutHeaders.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Headers)
+
+	batch, err := repo.CreateBatch(ctx, []*Model{{MonitorID: "monitor1", Headers: map[string]string{"Via": "1.1 varnish"}}})
+	require.NoError(t, err)
+	stored, err = repo.FindByID(ctx, batch[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1 varnish", stored.Headers["Via"])
+}
+
+func TestSQLRepository_Samples(t *testing.T) {
+	repo, _ := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	samples := &Samples{Count: 5, Up: 4, MinMs: 12, AvgMs: 30, MaxMs: 95, MedianMs: 25, P95Ms: 95}
+	sampled, err := repo.Create(ctx, &Model{MonitorID: "monitor1", Status: shared.MonitorStatusUp, Samples: samples})
+	require.NoError(t, err)
+	single, err := repo.Create(ctx, &Model{MonitorID: "monitor1", Status: shared.MonitorStatusUp})
+	require.NoError(t, err)
+
+	stored, err := repo.FindByID(ctx, sampled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, samples, stored.Samples)
+
+	stored, err = repo.FindByID(ctx, single.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Samples)
+}
@@ -0,0 +1,264 @@
+package heartbeat
+
+import (
+	"context"
+	"peekaping/src/config"
+	"peekaping/src/modules/events"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxRetainedMultiplier bounds how many heartbeats are kept for retry, in
+// multiples of the buffer size, while the database rejects writes
+const maxRetainedMultiplier = 10
+
+// Writer buffers heartbeats and inserts them in batches once the buffer is full
+// or the flush interval has passed. Heartbeat events are published once the
+// batch is stored, like Service.Create does for single heartbeats.
+type Writer struct {
+	repository Repository
+	eventBus   *events.EventBus
+	logger     *zap.SugaredLogger
+	bufferSize int
+	interval   time.Duration
+
+	mu     sync.Mutex
+	buffer []*Model
+	// newest buffered heartbeat per monitor, kept until it is stored
+	latest map[string]*Model
+
+	// serializes flushes so batches are stored in the order they were buffered
+	flushMu sync.Mutex
+
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	started   bool
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func NewWriter(
+	repository Repository,
+	eventBus *events.EventBus,
+	cfg *config.Config,
+	logger *zap.SugaredLogger,
+) *Writer {
+	return newWriter(repository, eventBus, cfg.HeartbeatBufferSize, cfg.HeartbeatFlushInterval, logger)
+}
+
+func newWriter(
+	repository Repository,
+	eventBus *events.EventBus,
+	bufferSize int,
+	interval time.Duration,
+	logger *zap.SugaredLogger,
+) *Writer {
+	return &Writer{
+		repository: repository,
+		eventBus:   eventBus,
+		logger:     logger.Named("[heartbeat-writer]"),
+		bufferSize: bufferSize,
+		interval:   interval,
+		latest:     make(map[string]*Model),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+func (w *Writer) buffered() bool {
+	return w.bufferSize > 1 && w.interval > 0
+}
+
+// Start runs the periodic flush until Stop is called
+func (w *Writer) Start() {
+	if !w.buffered() {
+		return
+	}
+	w.startOnce.Do(func() {
+		w.mu.Lock()
+		w.started = true
+		w.mu.Unlock()
+		go w.run()
+	})
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(context.Background())
+		case <-w.wake:
+			w.flush(context.Background())
+		case <-w.stop:
+			w.flush(context.Background())
+			return
+		}
+	}
+}
+
+// Stop stops the periodic flush and writes everything still buffered
+func (w *Writer) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	w.mu.Lock()
+	started := w.started
+	w.mu.Unlock()
+
+	if started {
+		<-w.done
+		return
+	}
+	w.flush(context.Background())
+}
+
+func toModel(dto *CreateUpdateDto) *Model {
+	return &Model{
+		MonitorID: dto.MonitorID,
+		Status:    dto.Status,
+		Msg:       dto.Msg,
+		Ping:      dto.Ping,
+		Duration:  dto.Duration,
+		DownCount: dto.DownCount,
+		Retries:   dto.Retries,
+		Important: dto.Important,
+		Time:      dto.Time,
+		EndTime:   dto.EndTime,
+		Notified:  dto.Notified,
+		Headers:   dto.Headers,
+		Samples:   dto.Samples,
+	}
+}
+
+// Write buffers the heartbeat, it is stored with the next batch. When
+// buffering is disabled the heartbeat is stored immediately.
+func (w *Writer) Write(ctx context.Context, dto *CreateUpdateDto) error {
+	if !w.buffered() {
+		_, err := w.WriteNow(ctx, dto)
+		return err
+	}
+
+	hb := toModel(dto)
+
+	w.mu.Lock()
+	w.buffer = append(w.buffer, hb)
+	w.latest[hb.MonitorID] = hb
+	full := len(w.buffer) >= w.bufferSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// WriteNow stores the heartbeat right away together with everything buffered
+// before it, for heartbeats that must be persisted before acting on them
+func (w *Writer) WriteNow(ctx context.Context, dto *CreateUpdateDto) (*Model, error) {
+	hb := toModel(dto)
+
+	w.mu.Lock()
+	w.buffer = append(w.buffer, hb)
+	w.latest[hb.MonitorID] = hb
+	w.mu.Unlock()
+
+	created, err := w.flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(created) - 1; i >= 0; i-- {
+		if created[i].MonitorID == hb.MonitorID {
+			return created[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Latest returns the newest heartbeat of the monitor that is not stored yet
+func (w *Writer) Latest(monitorID string) *Model {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.latest[monitorID]
+}
+
+// flush stores the buffered heartbeats in a single batch. On failure they are
+// put back to be retried with the next flush, up to a bounded backlog.
+func (w *Writer) flush(ctx context.Context) ([]*Model, error) {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.buffer
+	w.buffer = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil, nil
+	}
+
+	created, err := w.repository.CreateBatch(ctx, batch)
+	if err != nil {
+		w.requeue(batch)
+		w.logger.Errorw("Failed to write heartbeat batch", "count", len(batch), "error", err)
+		return nil, err
+	}
+
+	w.mu.Lock()
+	for _, hb := range batch {
+		// only forget heartbeats that were not superseded while the batch was written
+		if w.latest[hb.MonitorID] == hb {
+			delete(w.latest, hb.MonitorID)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, hb := range created {
+		w.eventBus.Publish(events.Event{
+			Type:    events.HeartbeatEvent,
+			Payload: hb,
+		})
+	}
+	w.logger.Debugf("Wrote %d heartbeats", len(created))
+	return created, nil
+}
+
+func (w *Writer) requeue(batch []*Model) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// without buffering the caller gets the error, like a direct insert
+	if !w.buffered() {
+		for _, hb := range batch {
+			if w.latest[hb.MonitorID] == hb {
+				delete(w.latest, hb.MonitorID)
+			}
+		}
+		return
+	}
+
+	retained := append(batch, w.buffer...)
+	if limit := w.bufferSize * maxRetainedMultiplier; len(retained) > limit {
+		dropped := retained[:len(retained)-limit]
+		retained = retained[len(retained)-limit:]
+		for _, hb := range dropped {
+			if w.latest[hb.MonitorID] == hb {
+				delete(w.latest, hb.MonitorID)
+			}
+		}
+		w.logger.Errorw("Dropping heartbeats that could not be written", "count", len(dropped))
+	}
+	w.buffer = retained
+}
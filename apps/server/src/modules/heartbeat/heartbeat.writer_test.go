@@ -0,0 +1,237 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// batchRepository records every batch it is asked to insert
+type batchRepository struct {
+	Repository
+	mu      sync.Mutex
+	batches [][]*Model
+	fail    bool
+}
+
+func (r *batchRepository) CreateBatch(ctx context.Context, heartbeats []*Model) ([]*Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail {
+		return nil, errors.New("database unavailable")
+	}
+	created := make([]*Model, 0, len(heartbeats))
+	for _, hb := range heartbeats {
+		stored := *hb
+		stored.ID = fmt.Sprintf("hb-%d", len(r.batches)*1000+len(created))
+		created = append(created, &stored)
+	}
+	r.batches = append(r.batches, created)
+	return created, nil
+}
+
+func (r *batchRepository) setFail(fail bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fail = fail
+}
+
+func (r *batchRepository) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sizes := make([]int, 0, len(r.batches))
+	for _, b := range r.batches {
+		sizes = append(sizes, len(b))
+	}
+	return sizes
+}
+
+func newTestWriter(bufferSize int, interval time.Duration) (*Writer, *batchRepository, *events.EventBus) {
+	repo := &batchRepository{}
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	return newWriter(repo, bus, bufferSize, interval, zap.NewNop().Sugar()), repo, bus
+}
+
+func testHeartbeat(monitorID string, i int) *CreateUpdateDto {
+	return &CreateUpdateDto{
+		MonitorID: monitorID,
+		Status:    shared.MonitorStatusUp,
+		Msg:       fmt.Sprintf("beat %d", i),
+		Time:      time.Now(),
+	}
+}
+
+func waitForBatches(t *testing.T, repo *batchRepository, count int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(repo.batchSizes()) >= count {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d batches, got %v", count, repo.batchSizes())
+}
+
+func TestWriter_FlushesFullBuffer(t *testing.T) {
+	w, repo, bus := newTestWriter(5, time.Hour)
+	w.Start()
+	defer w.Stop()
+
+	published := make(chan *Model, 10)
+	bus.Subscribe(events.HeartbeatEvent, func(event events.Event) {
+		published <- event.Payload.(*Model)
+	})
+
+	for i := 0; i < 4; i++ {
+		if err := w.Write(context.Background(), testHeartbeat("api", i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	if sizes := repo.batchSizes(); len(sizes) != 0 {
+		t.Fatalf("expected nothing to be written below the buffer size, got %v", sizes)
+	}
+	if latest := w.Latest("api"); latest == nil || latest.Msg != "beat 3" {
+		t.Fatalf("expected the newest buffered heartbeat, got %+v", latest)
+	}
+
+	w.Write(context.Background(), testHeartbeat("api", 4))
+	waitForBatches(t, repo, 1)
+
+	if sizes := repo.batchSizes(); sizes[0] != 5 {
+		t.Errorf("expected a single batch of 5, got %v", sizes)
+	}
+	for i := 0; i < 5; i++ {
+		select {
+		case hb := <-published:
+			if hb.ID == "" {
+				t.Errorf("expected the published heartbeat to be stored")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected 5 heartbeat events, got %d", i)
+		}
+	}
+	if latest := w.Latest("api"); latest != nil {
+		t.Errorf("expected no buffered heartbeat after the flush, got %+v", latest)
+	}
+}
+
+func TestWriter_FlushesOnInterval(t *testing.T) {
+	w, repo, _ := newTestWriter(100, 20*time.Millisecond)
+	w.Start()
+	defer w.Stop()
+
+	w.Write(context.Background(), testHeartbeat("api", 0))
+	w.Write(context.Background(), testHeartbeat("db", 0))
+
+	waitForBatches(t, repo, 1)
+	if sizes := repo.batchSizes(); sizes[0] != 2 {
+		t.Errorf("expected both heartbeats in one batch, got %v", sizes)
+	}
+}
+
+func TestWriter_WriteNowIncludesBuffered(t *testing.T) {
+	w, repo, _ := newTestWriter(100, time.Hour)
+	w.Start()
+	defer w.Stop()
+
+	w.Write(context.Background(), testHeartbeat("db", 0))
+	w.Write(context.Background(), testHeartbeat("api", 0))
+
+	down := testHeartbeat("api", 1)
+	down.Status = shared.MonitorStatusDown
+	stored, err := w.WriteNow(context.Background(), down)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.ID == "" || stored.Status != shared.MonitorStatusDown {
+		t.Fatalf("expected the stored status change, got %+v", stored)
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if len(repo.batches) != 1 || len(repo.batches[0]) != 3 {
+		t.Fatalf("expected one batch with the buffered heartbeats, got %v", repo.batches)
+	}
+	// buffered heartbeats are stored before the status change
+	if repo.batches[0][2].Msg != "beat 1" {
+		t.Errorf("expected the status change last, got %q", repo.batches[0][2].Msg)
+	}
+}
+
+func TestWriter_StopFlushesRemaining(t *testing.T) {
+	w, repo, _ := newTestWriter(100, time.Hour)
+	w.Start()
+
+	for i := 0; i < 3; i++ {
+		w.Write(context.Background(), testHeartbeat("api", i))
+	}
+	w.Stop()
+
+	if sizes := repo.batchSizes(); len(sizes) != 1 || sizes[0] != 3 {
+		t.Errorf("expected the remaining heartbeats to be flushed on stop, got %v", sizes)
+	}
+}
+
+func TestWriter_RetriesFailedBatch(t *testing.T) {
+	w, repo, _ := newTestWriter(2, time.Hour)
+	repo.setFail(true)
+
+	w.Write(context.Background(), testHeartbeat("api", 0))
+	if _, err := w.flush(context.Background()); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+	if latest := w.Latest("api"); latest == nil {
+		t.Fatal("expected the heartbeat to stay buffered after a failed write")
+	}
+
+	// the backlog is bounded while the database keeps failing
+	for i := 1; i < 30; i++ {
+		w.Write(context.Background(), testHeartbeat("api", i))
+		w.flush(context.Background())
+	}
+	w.mu.Lock()
+	retained := len(w.buffer)
+	w.mu.Unlock()
+	if retained != 2*maxRetainedMultiplier {
+		t.Errorf("expected %d retained heartbeats, got %d", 2*maxRetainedMultiplier, retained)
+	}
+
+	repo.setFail(false)
+	created, err := w.flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != retained || created[len(created)-1].Msg != "beat 29" {
+		t.Errorf("expected the retained backlog to be written in order, got %d heartbeats", len(created))
+	}
+}
+
+func TestWriter_Unbuffered(t *testing.T) {
+	w, repo, _ := newTestWriter(0, time.Second)
+	w.Start()
+	defer w.Stop()
+
+	w.Write(context.Background(), testHeartbeat("api", 0))
+	w.Write(context.Background(), testHeartbeat("api", 1))
+
+	if sizes := repo.batchSizes(); len(sizes) != 2 || sizes[0] != 1 || sizes[1] != 1 {
+		t.Errorf("expected every heartbeat to be written directly, got %v", sizes)
+	}
+
+	repo.setFail(true)
+	if err := w.Write(context.Background(), testHeartbeat("api", 2)); err == nil {
+		t.Error("expected the write error to be returned")
+	}
+	if latest := w.Latest("api"); latest != nil {
+		t.Errorf("expected nothing to be retained without buffering, got %+v", latest)
+	}
+}
@@ -1,8 +1,10 @@
 package maintenance
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"peekaping/src/modules/monitor"
 	"peekaping/src/utils"
 
 	"github.com/gin-gonic/gin"
@@ -11,16 +13,19 @@ import (
 )
 
 type Controller struct {
-	service Service
-	logger  *zap.SugaredLogger
+	service        Service
+	monitorService monitor.Service
+	logger         *zap.SugaredLogger
 }
 
 func NewController(
 	service Service,
+	monitorService monitor.Service,
 	logger *zap.SugaredLogger,
 ) *Controller {
 	return &Controller{
 		service,
+		monitorService,
 		logger,
 	}
 }
@@ -72,6 +77,46 @@ func (ic *Controller) FindAll(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entities))
 }
 
+// @Router		/maintenances/active [get]
+// @Summary		Get monitors under maintenance
+// @Description	Returns the monitors whose checks an active maintenance window suppresses now. Monitors ignoring maintenance are left out.
+// @Tags			Maintenances
+// @Produce		json
+// @Security  BearerAuth
+// @Success		200	{object}	utils.ApiResponse[ActiveMaintenanceDto]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindActive(ctx *gin.Context) {
+	monitorIDs, err := ic.service.GetActiveMonitorIDs(ctx)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch active maintenances", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	// the lookup also drops monitors deleted while still in a window
+	monitors, err := ic.monitorService.FindByIDs(ctx, monitorIDs)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitors under maintenance", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	suppressed := map[string]bool{}
+	for _, m := range monitors {
+		if !m.IgnoreMaintenance {
+			suppressed[m.ID] = true
+		}
+	}
+
+	result := ActiveMaintenanceDto{MonitorIDs: []string{}}
+	for _, id := range monitorIDs {
+		if suppressed[id] {
+			result.MonitorIDs = append(result.MonitorIDs, id)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", result))
+}
+
 // @Router		/maintenances [post]
 // @Summary		Create maintenance
 // @Tags			Maintenances
@@ -156,6 +201,10 @@ func (ic *Controller) FindByID(ctx *gin.Context) {
 		CreatedAt:     entity.CreatedAt,
 		UpdatedAt:     entity.UpdatedAt,
 		MonitorIds:    monitorIds,
+
+		ApprovalStatus: entity.ApprovalStatus,
+		ReviewedBy:     entity.ReviewedBy,
+		ReviewedAt:     entity.ReviewedAt,
 	}
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
@@ -291,3 +340,61 @@ func (ic *Controller) Resume(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Resumed", updated))
 }
+
+// @Router		/maintenances/{id}/approve [patch]
+// @Summary		Approve maintenance
+// @Description	Only approved maintenances suppress checks, requires MAINTENANCE_APPROVERS
+// @Tags			Maintenances
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Maintenance ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		403	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		409	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Approve(ctx *gin.Context) {
+	ic.review(ctx, true)
+}
+
+// @Router		/maintenances/{id}/reject [patch]
+// @Summary		Reject maintenance
+// @Tags			Maintenances
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Maintenance ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		403	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		409	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Reject(ctx *gin.Context) {
+	ic.review(ctx, false)
+}
+
+func (ic *Controller) review(ctx *gin.Context, approve bool) {
+	id := ctx.Param("id")
+	reviewed, err := ic.service.Review(ctx, id, approve, ctx.GetString("email"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrApprovalDisabled):
+			ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+		case errors.Is(err, ErrNotApprover):
+			ctx.JSON(http.StatusForbidden, utils.NewFailResponse(err.Error()))
+		default:
+			ic.logger.Errorw("Failed to review maintenance", "id", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		}
+		return
+	}
+	if reviewed == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Maintenance not found"))
+		return
+	}
+
+	message := "Rejected"
+	if approve {
+		message = "Approved"
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse(message, reviewed))
+}
@@ -57,4 +57,13 @@ type MaintenanceResponseDto struct {
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 	MonitorIds    []string  `json:"monitor_ids"`
+
+	ApprovalStatus string     `json:"approval_status" example:"pending"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty" example:"approver@example.com"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// ActiveMaintenanceDto lists the monitors currently under maintenance
+type ActiveMaintenanceDto struct {
+	MonitorIDs []string `json:"monitor_ids"`
 }
@@ -2,22 +2,38 @@ package maintenance
 
 import "time"
 
+// Approval states of a maintenance window, only approved windows suppress checks
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
 type Model struct {
-	ID            string    `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	Active        bool      `json:"active"`
-	Strategy      string    `json:"strategy"`
-	StartDateTime *string   `json:"start_date_time,omitempty"`
-	EndDateTime   *string   `json:"end_date_time,omitempty"`
-	StartTime     *string   `json:"start_time,omitempty"`
-	EndTime       *string   `json:"end_time,omitempty"`
-	Weekdays      []int     `json:"weekdays,omitempty"`
-	DaysOfMonth   []int     `json:"days_of_month,omitempty"`
-	IntervalDay   *int      `json:"interval_day,omitempty"`
-	Cron          *string   `json:"cron,omitempty"`
-	Timezone      *string   `json:"timezone,omitempty"`
-	Duration      *int      `json:"duration,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Active         bool       `json:"active"`
+	Strategy       string     `json:"strategy"`
+	StartDateTime  *string    `json:"start_date_time,omitempty"`
+	EndDateTime    *string    `json:"end_date_time,omitempty"`
+	StartTime      *string    `json:"start_time,omitempty"`
+	EndTime        *string    `json:"end_time,omitempty"`
+	Weekdays       []int      `json:"weekdays,omitempty"`
+	DaysOfMonth    []int      `json:"days_of_month,omitempty"`
+	IntervalDay    *int       `json:"interval_day,omitempty"`
+	Cron           *string    `json:"cron,omitempty"`
+	Timezone       *string    `json:"timezone,omitempty"`
+	Duration       *int       `json:"duration,omitempty"`
+	ApprovalStatus string     `json:"approval_status"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Approved tells whether the window may suppress checks, windows stored
+// before approvals existed count as approved
+func (m *Model) Approved() bool {
+	return m.ApprovalStatus == "" || m.ApprovalStatus == ApprovalApproved
 }
@@ -30,6 +30,11 @@ type mongoModel struct {
 	Duration      *int               `bson:"duration,omitempty"`
 	CreatedAt     time.Time          `bson:"created_at"`
 	UpdatedAt     time.Time          `bson:"updated_at"`
+
+	// omitted when empty so full updates keep the approval
+	ApprovalStatus string     `bson:"approval_status,omitempty"`
+	ReviewedBy     string     `bson:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `bson:"reviewed_at,omitempty"`
 }
 
 type mongoUpdateModel struct {
@@ -69,6 +74,10 @@ func toDomainModel(mm *mongoModel) *Model {
 		Duration:      mm.Duration,
 		CreatedAt:     mm.CreatedAt,
 		UpdatedAt:     mm.UpdatedAt,
+
+		ApprovalStatus: mm.ApprovalStatus,
+		ReviewedBy:     mm.ReviewedBy,
+		ReviewedAt:     mm.ReviewedAt,
 	}
 }
 
@@ -84,7 +93,7 @@ func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
 	return &MongoRepositoryImpl{client, db, collection}
 }
 
-func (r *MongoRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+func (r *MongoRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDto, approvalStatus string) (*Model, error) {
 	mm := &mongoModel{
 		ID:            primitive.NewObjectID(),
 		Title:         entity.Title,
@@ -101,6 +110,8 @@ func (r *MongoRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDt
 		Duration:      entity.Duration,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
+
+		ApprovalStatus: approvalStatus,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mm)
@@ -283,6 +294,25 @@ func (r *MongoRepositoryImpl) SetActive(ctx context.Context, id string, active b
 	return r.FindByID(ctx, id)
 }
 
+func (r *MongoRepositoryImpl) SetApproval(ctx context.Context, id string, status string, reviewedBy string, reviewedAt *time.Time) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	update := bson.M{"$set": bson.M{
+		"approval_status": status,
+		"reviewed_by":     reviewedBy,
+		"reviewed_at":     reviewedAt,
+		"updated_at":      now,
+	}}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
 // GetMaintenancesByMonitorID returns all active maintenances for a given monitor_id
 func (r *MongoRepositoryImpl) GetMaintenancesByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
 	objectID, err := primitive.ObjectIDFromHex(monitorID)
@@ -329,3 +359,24 @@ func (r *MongoRepositoryImpl) GetMaintenancesByMonitorID(ctx context.Context, mo
 	}
 	return maintenances, nil
 }
+
+func (r *MongoRepositoryImpl) FindActive(ctx context.Context) ([]*Model, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"active": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var maintenances []*Model
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		maintenances = append(maintenances, toDomainModel(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return maintenances, nil
+}
@@ -1,9 +1,12 @@
 package maintenance
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
-	Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error)
+	Create(ctx context.Context, entity *CreateUpdateDto, approvalStatus string) (*Model, error)
 	FindByID(ctx context.Context, id string) (*Model, error)
 	FindAll(ctx context.Context, page int, limit int, q string, strategy string) ([]*Model, error)
 	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
@@ -11,5 +14,8 @@ type Repository interface {
 	Delete(ctx context.Context, id string) error
 
 	SetActive(ctx context.Context, id string, active bool) (*Model, error)
+	SetApproval(ctx context.Context, id string, status string, reviewedBy string, reviewedAt *time.Time) (*Model, error)
 	GetMaintenancesByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	// FindActive returns every maintenance that is not paused
+	FindActive(ctx context.Context) ([]*Model, error)
 }
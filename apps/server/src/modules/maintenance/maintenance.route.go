@@ -30,6 +30,7 @@ func (uc *Route) ConnectRoute(
 	router.Use(uc.middleware.Auth())
 	router.GET("", uc.controller.FindAll)
 	router.POST("", uc.controller.Create)
+	router.GET("active", uc.controller.FindActive)
 	router.GET(":id", uc.controller.FindByID)
 	router.PUT(":id", uc.controller.UpdateFull)
 	router.PATCH(":id", uc.controller.UpdatePartial)
@@ -37,4 +38,6 @@ func (uc *Route) ConnectRoute(
 
 	router.PATCH(":id/pause", uc.controller.Pause)
 	router.PATCH(":id/resume", uc.controller.Resume)
+	router.PATCH(":id/approve", uc.controller.Approve)
+	router.PATCH(":id/reject", uc.controller.Reject)
 }
@@ -2,10 +2,14 @@ package maintenance
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
+	"peekaping/src/config"
 	"peekaping/src/modules/maintenance/utils"
 	"peekaping/src/modules/monitor_maintenance"
 )
@@ -20,6 +24,9 @@ type Service interface {
 
 	SetActive(ctx context.Context, id string, active bool) (*Model, error)
 
+	// Review approves or rejects the maintenance on behalf of an approver
+	Review(ctx context.Context, id string, approve bool, reviewer string) (*Model, error)
+
 	// GetStatus returns whether the maintenance is currently active
 	IsUnderMaintenance(ctx context.Context, maintenance *Model) (bool, error)
 
@@ -28,11 +35,22 @@ type Service interface {
 
 	// Get monitors for a maintenance
 	GetMonitors(ctx context.Context, id string) ([]string, error)
+
+	// GetActiveMonitorIDs returns the monitors of every maintenance window
+	// that is in effect now, sorted and without duplicates
+	GetActiveMonitorIDs(ctx context.Context) ([]string, error)
 }
 
+// ErrApprovalDisabled is returned when reviewing while no approvers are configured
+var ErrApprovalDisabled = errors.New("maintenance approval is not enabled")
+
+// ErrNotApprover is returned when the reviewer is not one of the configured approvers
+var ErrNotApprover = errors.New("not allowed to review maintenances")
+
 type ServiceImpl struct {
 	repository                Repository
 	monitorMaintenanceService monitor_maintenance.Service
+	approvers                 map[string]bool
 	logger                    *zap.SugaredLogger
 	cronGenerator             *utils.CronGenerator
 	timeWindowChecker         *utils.TimeWindowChecker
@@ -43,11 +61,13 @@ type ServiceImpl struct {
 func NewService(
 	repository Repository,
 	monitorMaintenanceService monitor_maintenance.Service,
+	cfg *config.Config,
 	logger *zap.SugaredLogger,
 ) Service {
 	return &ServiceImpl{
 		repository:                repository,
 		monitorMaintenanceService: monitorMaintenanceService,
+		approvers:                 parseApprovers(cfg.MaintenanceApprovers),
 		logger:                    logger.Named("[maintenance-service]"),
 		cronGenerator:             utils.NewCronGenerator(),
 		timeWindowChecker:         utils.NewTimeWindowChecker(logger),
@@ -56,6 +76,31 @@ func NewService(
 	}
 }
 
+func parseApprovers(list string) map[string]bool {
+	approvers := map[string]bool{}
+	for _, email := range strings.Split(list, ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			approvers[email] = true
+		}
+	}
+	return approvers
+}
+
+// approvalRequired tells whether windows need an approval before they suppress checks
+func (mr *ServiceImpl) approvalRequired() bool {
+	return len(mr.approvers) > 0
+}
+
+// resetApproval sends a changed window back for approval, before the change
+// is stored so the new schedule never suppresses unapproved
+func (mr *ServiceImpl) resetApproval(ctx context.Context, id string) error {
+	if !mr.approvalRequired() {
+		return nil
+	}
+	_, err := mr.repository.SetApproval(ctx, id, ApprovalPending, "", nil)
+	return err
+}
+
 func (mr *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
 	// Validate cron and duration
 	if err := mr.validator.ValidateCronAndDuration(&utils.ValidationParams{
@@ -89,8 +134,13 @@ func (mr *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Mo
 		mr.logger.Debugf("Calculated duration from start/end times: %d minutes", duration)
 	}
 
+	approvalStatus := ApprovalApproved
+	if mr.approvalRequired() {
+		approvalStatus = ApprovalPending
+	}
+
 	// Store times directly without timezone conversion
-	created, err := mr.repository.Create(ctx, entity)
+	created, err := mr.repository.Create(ctx, entity, approvalStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +207,16 @@ func (mr *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *Create
 		mr.logger.Debugf("Calculated duration from start/end times: %d minutes", duration)
 	}
 
+	if err := mr.resetApproval(ctx, id); err != nil {
+		return nil, err
+	}
+
 	// Store times directly without timezone conversion
-	updated, err := mr.repository.UpdateFull(ctx, id, entity)
+	if _, err := mr.repository.UpdateFull(ctx, id, entity); err != nil {
+		return nil, err
+	}
+	// full updates leave the approval out, read the window back with it
+	updated, err := mr.repository.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -260,6 +318,10 @@ func (mr *ServiceImpl) UpdatePartial(ctx context.Context, id string, entity *Par
 		return nil, err
 	}
 
+	if err := mr.resetApproval(ctx, id); err != nil {
+		return nil, err
+	}
+
 	// Store times directly without timezone conversion
 	updated, err := mr.repository.UpdatePartial(ctx, id, entity)
 	if err != nil {
@@ -290,6 +352,30 @@ func (mr *ServiceImpl) SetActive(ctx context.Context, id string, active bool) (*
 	return model, nil
 }
 
+func (mr *ServiceImpl) Review(ctx context.Context, id string, approve bool, reviewer string) (*Model, error) {
+	if !mr.approvalRequired() {
+		return nil, ErrApprovalDisabled
+	}
+	if !mr.approvers[strings.ToLower(reviewer)] {
+		return nil, ErrNotApprover
+	}
+
+	existing, err := mr.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	status := ApprovalRejected
+	if approve {
+		status = ApprovalApproved
+	}
+	now := time.Now().UTC()
+	return mr.repository.SetApproval(ctx, id, status, reviewer, &now)
+}
+
 // IsUnderMaintenance determines if the maintenance is currently active based on strategy and timing
 func (mr *ServiceImpl) IsUnderMaintenance(ctx context.Context, maintenance *Model) (bool, error) {
 	mr.logger.Debugf("Checking if maintenance %s is under maintenance", maintenance.ID)
@@ -300,6 +386,12 @@ func (mr *ServiceImpl) IsUnderMaintenance(ctx context.Context, maintenance *Mode
 		return false, nil
 	}
 
+	// Pending and rejected windows are visible but do not suppress checks
+	if !maintenance.Approved() {
+		mr.logger.Debugf("maintenance %s is %s, not suppressing", maintenance.ID, maintenance.ApprovalStatus)
+		return false, nil
+	}
+
 	if maintenance.Strategy == "manual" {
 		return maintenance.Active, nil
 	}
@@ -330,43 +422,44 @@ func (mr *ServiceImpl) IsUnderMaintenance(ctx context.Context, maintenance *Mode
 		Timezone:      maintenance.Timezone,
 	}
 
-	isInDateTimePeriod, err := mr.timeWindowChecker.IsInDateTimePeriod(timeWindowParams, now, loc)
-	if err != nil {
-		return false, err
-	}
-	mr.logger.Debugf("isInDateTimePeriod: %t", isInDateTimePeriod)
-
 	// Handle single strategy - under-maintenance when within the scheduled time
 	if maintenance.Strategy == "single" {
-		return isInDateTimePeriod, nil
-	}
-
-	// Handle recurring-interval strategy
-	if maintenance.Strategy == "recurring-interval" {
-		isInRecurringInterval, err := mr.timeWindowChecker.IsInRecurringIntervalWindow(timeWindowParams, now, loc)
+		isInDateTimePeriod, err := mr.timeWindowChecker.IsInDateTimePeriod(timeWindowParams, now, loc)
 		if err != nil {
 			return false, err
 		}
-
-		mr.logger.Debugf("isInRecurringInterval: %t", isInRecurringInterval)
-
-		return isInRecurringInterval && isInDateTimePeriod, nil
+		mr.logger.Debugf("isInDateTimePeriod: %t", isInDateTimePeriod)
+		return isInDateTimePeriod, nil
 	}
 
-	// Handle cron strategy
-	if maintenance.Cron != nil && *maintenance.Cron != "" {
-		isInCronWindow, err := mr.timeWindowChecker.IsInCronMaintenanceWindow(timeWindowParams, now, loc)
-		if err != nil {
-			return false, err
-		}
-
-		mr.logger.Debugf("isInCronWindow: %t", isInCronWindow)
+	// Recurring strategies repeat within an optional date time period
+	if !mr.timeWindowChecker.IsInEffectivePeriod(timeWindowParams, now, loc) {
+		mr.logger.Debugf("maintenance %s is outside of its effective period", maintenance.ID)
+		return false, nil
+	}
 
-		return isInCronWindow && isInDateTimePeriod, nil
+	var inWindow bool
+	var err error
+	switch {
+	case maintenance.Strategy == "recurring-interval":
+		inWindow, err = mr.timeWindowChecker.IsInRecurringIntervalWindow(timeWindowParams, now, loc)
+	case maintenance.Strategy == "recurring-weekday":
+		inWindow, err = mr.timeWindowChecker.IsInRecurringWeekdayWindow(timeWindowParams, now, loc)
+	case maintenance.Strategy == "recurring-day-of-month":
+		inWindow, err = mr.timeWindowChecker.IsInRecurringDayOfMonthWindow(timeWindowParams, now, loc)
+	case maintenance.Cron != nil && *maintenance.Cron != "":
+		inWindow, err = mr.timeWindowChecker.IsInCronMaintenanceWindow(timeWindowParams, now, loc)
+	default:
+		// For any other strategy or unhandled cases
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
 
-	// For any other strategy or unhandled cases
-	return false, nil
+	mr.logger.Debugf("in %s window: %t", maintenance.Strategy, inWindow)
+
+	return inWindow, nil
 }
 
 // generateCronExpression generates a cron expression based on the maintenance strategy and parameters
@@ -396,3 +489,37 @@ func (mr *ServiceImpl) GetMaintenancesByMonitorID(ctx context.Context, monitorID
 func (mr *ServiceImpl) GetMonitors(ctx context.Context, id string) ([]string, error) {
 	return mr.monitorMaintenanceService.GetMonitors(ctx, id)
 }
+
+func (mr *ServiceImpl) GetActiveMonitorIDs(ctx context.Context) ([]string, error) {
+	maintenances, err := mr.repository.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	monitorIDs := []string{}
+	for _, m := range maintenances {
+		underMaintenance, err := mr.IsUnderMaintenance(ctx, m)
+		if err != nil {
+			mr.logger.Warnf("Failed to get maintenance status for maintenance %s: %v", m.ID, err)
+			continue
+		}
+		if !underMaintenance {
+			continue
+		}
+
+		ids, err := mr.monitorMaintenanceService.GetMonitors(ctx, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				monitorIDs = append(monitorIDs, id)
+			}
+		}
+	}
+
+	sort.Strings(monitorIDs)
+	return monitorIDs, nil
+}
@@ -0,0 +1,148 @@
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/config"
+	"peekaping/src/modules/monitor_maintenance"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+// newTestService runs the service on the SQL repository over a private
+// in-memory database
+func newTestService(t *testing.T, approvers string) Service {
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(context.Background())
+	require.NoError(t, err)
+
+	return NewService(NewSQLRepository(db), &fakeMonitorMaintenanceService{monitors: map[string][]string{}}, &config.Config{MaintenanceApprovers: approvers}, zap.NewNop().Sugar())
+}
+
+// fakeMonitorMaintenanceService keeps the monitors of each maintenance in memory
+type fakeMonitorMaintenanceService struct {
+	monitor_maintenance.Service
+	monitors map[string][]string
+}
+
+func (f *fakeMonitorMaintenanceService) SetMonitors(ctx context.Context, maintenanceID string, monitorIDs []string) error {
+	f.monitors[maintenanceID] = monitorIDs
+	return nil
+}
+
+func (f *fakeMonitorMaintenanceService) GetMonitors(ctx context.Context, maintenanceID string) ([]string, error) {
+	return f.monitors[maintenanceID], nil
+}
+
+func manualWindow() *CreateUpdateDto {
+	return &CreateUpdateDto{Title: "Database upgrade", Active: true, Strategy: "manual"}
+}
+
+func suppresses(t *testing.T, service Service, id string) bool {
+	window, err := service.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	under, err := service.IsUnderMaintenance(context.Background(), window)
+	require.NoError(t, err)
+	return under
+}
+
+func TestService_OnlyApprovedWindowsSuppress(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t, "lead@example.com, Audit@example.com")
+
+	window, err := service.Create(ctx, manualWindow())
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalPending, window.ApprovalStatus)
+	assert.False(t, suppresses(t, service, window.ID), "pending windows do not suppress")
+
+	_, err = service.Review(ctx, window.ID, true, "engineer@example.com")
+	assert.ErrorIs(t, err, ErrNotApprover)
+	assert.False(t, suppresses(t, service, window.ID))
+
+	approved, err := service.Review(ctx, window.ID, true, "audit@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalApproved, approved.ApprovalStatus)
+	assert.Equal(t, "audit@example.com", approved.ReviewedBy)
+	assert.NotNil(t, approved.ReviewedAt)
+	assert.True(t, suppresses(t, service, window.ID))
+
+	// a changed window needs a new approval
+	title := "Database upgrade, extended"
+	updated, err := service.UpdatePartial(ctx, window.ID, &PartialUpdateDto{Title: &title})
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalPending, updated.ApprovalStatus)
+	assert.False(t, suppresses(t, service, window.ID))
+
+	rejected, err := service.Review(ctx, window.ID, false, "lead@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalRejected, rejected.ApprovalStatus)
+	assert.False(t, suppresses(t, service, window.ID), "rejected windows do not suppress")
+
+	missing, err := service.Review(ctx, "missing", true, "lead@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestService_WithoutApproversWindowsSuppress(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t, "")
+
+	window, err := service.Create(ctx, manualWindow())
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalApproved, window.ApprovalStatus)
+	assert.True(t, suppresses(t, service, window.ID))
+
+	updated, err := service.UpdateFull(ctx, window.ID, manualWindow())
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalApproved, updated.ApprovalStatus)
+	assert.True(t, suppresses(t, service, window.ID))
+
+	_, err = service.Review(ctx, window.ID, false, "lead@example.com")
+	assert.ErrorIs(t, err, ErrApprovalDisabled)
+}
+
+func TestService_GetActiveMonitorIDs(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService(t, "")
+
+	first := manualWindow()
+	first.MonitorIds = []string{"monitor-b", "monitor-a"}
+	_, err := service.Create(ctx, first)
+	require.NoError(t, err)
+
+	second := manualWindow()
+	second.MonitorIds = []string{"monitor-a", "monitor-c"}
+	_, err = service.Create(ctx, second)
+	require.NoError(t, err)
+
+	paused := manualWindow()
+	paused.MonitorIds = []string{"monitor-d"}
+	pausedWindow, err := service.Create(ctx, paused)
+	require.NoError(t, err)
+	_, err = service.SetActive(ctx, pausedWindow.ID, false)
+	require.NoError(t, err)
+
+	// a single window that is over
+	start, end := "2020-01-01T00:00", "2020-01-02T00:00"
+	_, err = service.Create(ctx, &CreateUpdateDto{
+		Title: "Past", Active: true, Strategy: "single",
+		StartDateTime: &start, EndDateTime: &end,
+		MonitorIds: []string{"monitor-e"},
+	})
+	require.NoError(t, err)
+
+	monitorIDs, err := service.GetActiveMonitorIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"monitor-a", "monitor-b", "monitor-c"}, monitorIDs)
+}
@@ -29,6 +29,10 @@ type sqlModel struct {
 	Duration      *int      `bun:"duration"`
 	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	ApprovalStatus string     `bun:"approval_status,notnull,default:'approved'"`
+	ReviewedBy     string     `bun:"reviewed_by,notnull,default:''"`
+	ReviewedAt     *time.Time `bun:"reviewed_at"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -61,6 +65,10 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		Duration:      sm.Duration,
 		CreatedAt:     sm.CreatedAt,
 		UpdatedAt:     sm.UpdatedAt,
+
+		ApprovalStatus: sm.ApprovalStatus,
+		ReviewedBy:     sm.ReviewedBy,
+		ReviewedAt:     sm.ReviewedAt,
 	}
 }
 
@@ -72,7 +80,7 @@ func NewSQLRepository(db *bun.DB) Repository {
 	return &SQLRepositoryImpl{db: db}
 }
 
-func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDto, approvalStatus string) (*Model, error) {
 	// Marshal arrays to JSON strings
 	weekdaysJSON, _ := json.Marshal(entity.Weekdays)
 	daysOfMonthJSON, _ := json.Marshal(entity.DaysOfMonth)
@@ -95,6 +103,8 @@ func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *CreateUpdateDto)
 		Duration:      entity.Duration,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
+
+		ApprovalStatus: approvalStatus,
 	}
 
 	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
@@ -279,6 +289,22 @@ func (r *SQLRepositoryImpl) SetActive(ctx context.Context, id string, active boo
 	return r.FindByID(ctx, id)
 }
 
+func (r *SQLRepositoryImpl) SetApproval(ctx context.Context, id string, status string, reviewedBy string, reviewedAt *time.Time) (*Model, error) {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("approval_status = ?", status).
+		Set("reviewed_by = ?", reviewedBy).
+		Set("reviewed_at = ?", reviewedAt).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
 // GetMaintenancesByMonitorID returns all active maintenances for a given monitor_id
 func (r *SQLRepositoryImpl) GetMaintenancesByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
 	var sms []*sqlModel
@@ -302,3 +328,20 @@ func (r *SQLRepositoryImpl) GetMaintenancesByMonitorID(ctx context.Context, moni
 
 	return models, nil
 }
+
+func (r *SQLRepositoryImpl) FindActive(ctx context.Context) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("m.active = ?", true).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
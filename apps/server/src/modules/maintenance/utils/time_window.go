@@ -2,7 +2,6 @@ package utils
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -74,10 +73,9 @@ func (twc *TimeWindowChecker) IsInCronMaintenanceWindow(params *TimeWindowParams
 	searchStart := now.Add(-duration)
 
 	lastRun := schedule.Next(searchStart)
-	fmt.Println("lastRun", lastRun)
 	if lastRun.After(now) {
 		// The next run is in the future, so no run happened within the window
-		twc.logger.Debugf("lastRun is after now", now)
+		twc.logger.Debugf("lastRun %s is after now %s", lastRun, now)
 		return false, nil
 	}
 
@@ -102,57 +100,15 @@ func (twc *TimeWindowChecker) IsInRecurringIntervalWindow(params *TimeWindowPara
 		return false, errors.New("maintenance has no start or end time for daily window")
 	}
 
-	// Parse start and end times from string format (HH:MM)
-	startTime, err := time.Parse("15:04", *params.StartTime)
-	if err != nil {
-		return false, errors.New("invalid start time format")
-	}
-
-	endTime, err := time.Parse("15:04", *params.EndTime)
-	if err != nil {
-		return false, errors.New("invalid end time format")
-	}
-
 	// Convert start date to the maintenance timezone
 	startDateInTz := twc.convertToTimezone(*params.StartDateTime, loc)
+	interval := *params.IntervalDay
 
-	// Calculate days since the start date
-	daysSinceStart := int(now.Sub(startDateInTz).Hours() / 24)
-
-	// Check if today is a maintenance day based on the interval
-	if daysSinceStart < 0 || daysSinceStart%*params.IntervalDay != 0 {
-		return false, nil
-	}
-
-	// Today is a maintenance day, now check if we're within the daily time window
-	// Create today's start and end times in the maintenance timezone
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, loc)
-	todayEnd := time.Date(now.Year(), now.Month(), now.Day(),
-		endTime.Hour(), endTime.Minute(), 0, 0, loc)
-
-	// Handle case where end time is next day (e.g., 23:00 - 01:00)
-	if endTime.Before(startTime) {
-		// For cross-day windows, we need to check two possible windows:
-		// 1. Window starting today: todayStart to tomorrow's endTime
-		todayEnd = todayEnd.Add(24 * time.Hour)
-		if now.After(todayStart) && now.Before(todayEnd) {
-			return true, nil
-		}
-
-		// 2. Window starting yesterday: yesterdayStart to today's endTime
-		yesterdayStart := todayStart.Add(-24 * time.Hour)
-		todayEndOriginal := time.Date(now.Year(), now.Month(), now.Day(),
-			endTime.Hour(), endTime.Minute(), 0, 0, loc)
-		if now.After(yesterdayStart) && now.Before(todayEndOriginal) {
-			return true, nil
-		}
-
-		return false, nil
-	}
-
-	// Check if we're within the daily time window
-	return now.After(todayStart) && now.Before(todayEnd), nil
+	// Count calendar days rather than 24 hour periods, a day is 23 or 25 hours long when DST changes
+	return inDailyWindow(now, loc, *params.StartTime, *params.EndTime, func(day time.Time) bool {
+		daysSinceStart := calendarDaysBetween(startDateInTz, day)
+		return daysSinceStart >= 0 && daysSinceStart%interval == 0
+	})
 }
 
 // IsInRecurringWeekdayWindow checks if the current time falls within a recurring weekday maintenance window
@@ -165,60 +121,9 @@ func (twc *TimeWindowChecker) IsInRecurringWeekdayWindow(params *TimeWindowParam
 		return false, errors.New("maintenance has no weekdays specified")
 	}
 
-	// Parse start and end times from string format (HH:MM)
-	startTime, err := time.Parse("15:04", *params.StartTime)
-	if err != nil {
-		return false, errors.New("invalid start time format")
-	}
-
-	endTime, err := time.Parse("15:04", *params.EndTime)
-	if err != nil {
-		return false, errors.New("invalid end time format")
-	}
-
-	// Check if today is a maintenance day (weekday)
-	todayWeekday := int(now.Weekday())
-	isMaintenanceDay := false
-	for _, weekday := range params.Weekdays {
-		if weekday == todayWeekday {
-			isMaintenanceDay = true
-			break
-		}
-	}
-
-	if !isMaintenanceDay {
-		return false, nil
-	}
-
-	// Today is a maintenance day, now check if we're within the daily time window
-	// Create today's start and end times in the maintenance timezone
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, loc)
-	todayEnd := time.Date(now.Year(), now.Month(), now.Day(),
-		endTime.Hour(), endTime.Minute(), 0, 0, loc)
-
-	// Handle case where end time is next day (e.g., 23:00 - 01:00)
-	if endTime.Before(startTime) {
-		// For cross-day windows, we need to check two possible windows:
-		// 1. Window starting today: todayStart to tomorrow's endTime
-		todayEnd = todayEnd.Add(24 * time.Hour)
-		if now.After(todayStart) && now.Before(todayEnd) {
-			return true, nil
-		}
-
-		// 2. Window starting yesterday: yesterdayStart to today's endTime
-		yesterdayStart := todayStart.Add(-24 * time.Hour)
-		todayEndOriginal := time.Date(now.Year(), now.Month(), now.Day(),
-			endTime.Hour(), endTime.Minute(), 0, 0, loc)
-		if now.After(yesterdayStart) && now.Before(todayEndOriginal) {
-			return true, nil
-		}
-
-		return false, nil
-	}
-
-	// Check if we're within the daily time window
-	return now.After(todayStart) && now.Before(todayEnd), nil
+	return inDailyWindow(now, loc, *params.StartTime, *params.EndTime, func(day time.Time) bool {
+		return containsInt(params.Weekdays, int(day.Weekday()))
+	})
 }
 
 // IsInRecurringDayOfMonthWindow checks if the current time falls within a recurring day of month maintenance window
@@ -231,60 +136,87 @@ func (twc *TimeWindowChecker) IsInRecurringDayOfMonthWindow(params *TimeWindowPa
 		return false, errors.New("maintenance has no days of month specified")
 	}
 
+	return inDailyWindow(now, loc, *params.StartTime, *params.EndTime, func(day time.Time) bool {
+		return containsInt(params.DaysOfMonth, day.Day())
+	})
+}
+
+// IsInEffectivePeriod checks if the current time falls within the optional date
+// time period limiting a recurring maintenance. A missing start or end leaves
+// that side of the period open.
+func (twc *TimeWindowChecker) IsInEffectivePeriod(params *TimeWindowParams, now time.Time, loc *time.Location) bool {
+	if params.StartDateTime != nil && *params.StartDateTime != "" {
+		if now.Before(twc.convertToTimezone(*params.StartDateTime, loc)) {
+			return false
+		}
+	}
+	if params.EndDateTime != nil && *params.EndDateTime != "" {
+		if !now.Before(twc.convertToTimezone(*params.EndDateTime, loc)) {
+			return false
+		}
+	}
+	return true
+}
+
+// inDailyWindow checks if now falls within the startTime..endTime (HH:MM) window
+// of a day accepted by isMaintenanceDay. A window ending before its start time
+// crosses midnight, so the window that started the day before is checked
+// as well. Both ends are built from calendar dates in loc, which keeps the
+// wall clock times right across DST changes.
+func inDailyWindow(now time.Time, loc *time.Location, startTime, endTime string, isMaintenanceDay func(day time.Time) bool) (bool, error) {
 	// Parse start and end times from string format (HH:MM)
-	startTime, err := time.Parse("15:04", *params.StartTime)
+	start, err := time.Parse("15:04", startTime)
 	if err != nil {
 		return false, errors.New("invalid start time format")
 	}
 
-	endTime, err := time.Parse("15:04", *params.EndTime)
+	end, err := time.Parse("15:04", endTime)
 	if err != nil {
 		return false, errors.New("invalid end time format")
 	}
 
-	// Check if today is a maintenance day (day of month)
-	todayDay := now.Day()
-	isMaintenanceDay := false
-	for _, day := range params.DaysOfMonth {
-		if day == todayDay {
-			isMaintenanceDay = true
+	crossDay := end.Before(start)
+	now = now.In(loc)
+
+	for _, dayOffset := range []int{0, -1} {
+		if dayOffset < 0 && !crossDay {
 			break
 		}
-	}
 
-	if !isMaintenanceDay {
-		return false, nil
-	}
+		day := time.Date(now.Year(), now.Month(), now.Day()+dayOffset, 0, 0, 0, 0, loc)
+		if !isMaintenanceDay(day) {
+			continue
+		}
 
-	// Today is a maintenance day, now check if we're within the daily time window
-	// Create today's start and end times in the maintenance timezone
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, loc)
-	todayEnd := time.Date(now.Year(), now.Month(), now.Day(),
-		endTime.Hour(), endTime.Minute(), 0, 0, loc)
-
-	// Handle case where end time is next day (e.g., 23:00 - 01:00)
-	if endTime.Before(startTime) {
-		// For cross-day windows, we need to check two possible windows:
-		// 1. Window starting today: todayStart to tomorrow's endTime
-		todayEnd = todayEnd.Add(24 * time.Hour)
-		if now.After(todayStart) && now.Before(todayEnd) {
-			return true, nil
+		windowStart := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		endDay := day.Day()
+		if crossDay {
+			endDay++
 		}
+		windowEnd := time.Date(day.Year(), day.Month(), endDay, end.Hour(), end.Minute(), 0, 0, loc)
 
-		// 2. Window starting yesterday: yesterdayStart to today's endTime
-		yesterdayStart := todayStart.Add(-24 * time.Hour)
-		todayEndOriginal := time.Date(now.Year(), now.Month(), now.Day(),
-			endTime.Hour(), endTime.Minute(), 0, 0, loc)
-		if now.After(yesterdayStart) && now.Before(todayEndOriginal) {
+		if !now.Before(windowStart) && now.Before(windowEnd) {
 			return true, nil
 		}
-
-		return false, nil
 	}
 
-	// Check if we're within the daily time window
-	return now.After(todayStart) && now.Before(todayEnd), nil
+	return false, nil
+}
+
+// calendarDaysBetween returns the number of calendar days from the date of a to the date of b
+func calendarDaysBetween(a, b time.Time) int {
+	from := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // convertToTimezone constructs a time in the specified timezone from the components of another time
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newYorkTime(t *testing.T, value string) (time.Time, *time.Location) {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	now, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	return now, loc
+}
+
+func TestTimeWindowChecker_IsInRecurringWeekdayWindow(t *testing.T) {
+	twc := NewTimeWindowChecker(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name     string
+		params   *TimeWindowParams
+		now      string
+		expected bool
+	}{
+		{
+			name:     "inside the window on a maintenance day",
+			params:   &TimeWindowParams{StartTime: stringPtr("02:00"), EndTime: stringPtr("04:00"), Weekdays: []int{1}},
+			now:      "2025-07-21 03:00", // Monday
+			expected: true,
+		},
+		{
+			name:     "same time on another day",
+			params:   &TimeWindowParams{StartTime: stringPtr("02:00"), EndTime: stringPtr("04:00"), Weekdays: []int{1}},
+			now:      "2025-07-22 03:00", // Tuesday
+			expected: false,
+		},
+		{
+			name:     "cross-day window started on the maintenance day",
+			params:   &TimeWindowParams{StartTime: stringPtr("23:00"), EndTime: stringPtr("01:00"), Weekdays: []int{1}},
+			now:      "2025-07-22 00:30", // Tuesday, window opened Monday
+			expected: true,
+		},
+		{
+			name:     "cross-day window not started the day before",
+			params:   &TimeWindowParams{StartTime: stringPtr("23:00"), EndTime: stringPtr("01:00"), Weekdays: []int{1}},
+			now:      "2025-07-21 00:30", // Monday, Sunday had no window
+			expected: false,
+		},
+		{
+			name:     "window end is exclusive",
+			params:   &TimeWindowParams{StartTime: stringPtr("02:00"), EndTime: stringPtr("04:00"), Weekdays: []int{1}},
+			now:      "2025-07-21 04:00",
+			expected: false,
+		},
+		{
+			name:     "wall clock end after the fall back",
+			params:   &TimeWindowParams{StartTime: stringPtr("01:00"), EndTime: stringPtr("03:00"), Weekdays: []int{0}},
+			now:      "2025-11-02 02:30", // Sunday, clocks went from 02:00 back to 01:00
+			expected: true,
+		},
+		{
+			name:     "cross-day window over the spring forward",
+			params:   &TimeWindowParams{StartTime: stringPtr("22:00"), EndTime: stringPtr("06:00"), Weekdays: []int{6}},
+			now:      "2025-03-09 05:30", // Sunday, clocks jumped from 02:00 to 03:00
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, loc := newYorkTime(t, tt.now)
+			result, err := twc.IsInRecurringWeekdayWindow(tt.params, now, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTimeWindowChecker_IsInRecurringIntervalWindow(t *testing.T) {
+	twc := NewTimeWindowChecker(zap.NewNop().Sugar())
+	params := &TimeWindowParams{
+		StartDateTime: stringPtr("2025-03-01T00:00"),
+		StartTime:     stringPtr("00:00"),
+		EndTime:       stringPtr("01:00"),
+		IntervalDay:   intPtr(3),
+	}
+
+	tests := []struct {
+		name     string
+		now      string
+		expected bool
+	}{
+		{name: "first day", now: "2025-03-01 00:30", expected: true},
+		{name: "off day", now: "2025-03-02 00:30", expected: false},
+		// nine calendar days but less than nine times 24 hours after the spring forward
+		{name: "interval day after DST change", now: "2025-03-10 00:30", expected: true},
+		{name: "day after interval day", now: "2025-03-11 00:30", expected: false},
+		{name: "before the start date", now: "2025-02-26 00:30", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, loc := newYorkTime(t, tt.now)
+			result, err := twc.IsInRecurringIntervalWindow(params, now, loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTimeWindowChecker_IsInRecurringDayOfMonthWindow(t *testing.T) {
+	twc := NewTimeWindowChecker(zap.NewNop().Sugar())
+	params := &TimeWindowParams{StartTime: stringPtr("10:00"), EndTime: stringPtr("11:00"), DaysOfMonth: []int{1, 15}}
+
+	for now, expected := range map[string]bool{
+		"2025-07-15 10:30": true,
+		"2025-07-16 10:30": false,
+		"2025-07-01 11:30": false,
+	} {
+		at, loc := newYorkTime(t, now)
+		result, err := twc.IsInRecurringDayOfMonthWindow(params, at, loc)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result, now)
+	}
+}
+
+func TestTimeWindowChecker_IsInEffectivePeriod(t *testing.T) {
+	twc := NewTimeWindowChecker(zap.NewNop().Sugar())
+	now, loc := newYorkTime(t, "2025-07-21 03:00")
+
+	assert.True(t, twc.IsInEffectivePeriod(&TimeWindowParams{}, now, loc), "no period means always effective")
+	assert.True(t, twc.IsInEffectivePeriod(&TimeWindowParams{StartDateTime: stringPtr("2025-07-01T00:00")}, now, loc))
+	assert.False(t, twc.IsInEffectivePeriod(&TimeWindowParams{StartDateTime: stringPtr("2025-08-01T00:00")}, now, loc))
+	assert.False(t, twc.IsInEffectivePeriod(&TimeWindowParams{EndDateTime: stringPtr("2025-07-21T02:00")}, now, loc))
+}
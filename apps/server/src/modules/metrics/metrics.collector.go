@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// uptimePeriods are the windows exported as peekaping_monitor_uptime_ratio
+var uptimePeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// uptimeCacheTTL keeps frequent scrapes from querying heartbeats every time
+const uptimeCacheTTL = time.Minute
+
+var monitorLabels = []string{"monitor", "name", "type"}
+
+var (
+	monitorUpDesc = prometheus.NewDesc(
+		"peekaping_monitor_up",
+		"Whether the last check of the monitor was up (1) or not (0).",
+		monitorLabels, nil,
+	)
+	monitorStatusDesc = prometheus.NewDesc(
+		"peekaping_monitor_status",
+		"Status of the last check: 0 down, 1 up, 2 pending, 3 maintenance.",
+		monitorLabels, nil,
+	)
+	monitorResponseTimeDesc = prometheus.NewDesc(
+		"peekaping_monitor_response_time_ms",
+		"Response time of the last check in milliseconds.",
+		monitorLabels, nil,
+	)
+	monitorUptimeDesc = prometheus.NewDesc(
+		"peekaping_monitor_uptime_ratio",
+		"Share of up heartbeats over the period, from 0 to 1.",
+		append(monitorLabels, "period"), nil,
+	)
+	monitorSkippedRunsDesc = prometheus.NewDesc(
+		"peekaping_monitor_skipped_overlapping_runs_total",
+		"Checks skipped because the previous check of the monitor was still running.",
+		monitorLabels, nil,
+	)
+)
+
+// overlapCounter is implemented by the health check supervisor
+type overlapCounter interface {
+	SkippedOverlappingRuns() map[string]uint64
+}
+
+// monitorSample is the latest heartbeat seen for a monitor
+type monitorSample struct {
+	name        string
+	monitorType string
+	status      shared.MonitorStatus
+	ping        int
+}
+
+// Collector exports monitor state to Prometheus. It is fed by heartbeat events
+// and computes uptime ratios from stored heartbeats when scraped.
+type Collector struct {
+	heartbeatService heartbeat.Service
+	monitorService   monitor.Service
+	overlaps         overlapCounter
+	logger           *zap.SugaredLogger
+
+	mu       sync.RWMutex
+	monitors map[string]*monitorSample
+
+	uptimeMu      sync.Mutex
+	uptime        map[string]map[string]float64
+	uptimeFetched time.Time
+}
+
+func newCollector(
+	heartbeatService heartbeat.Service,
+	monitorService monitor.Service,
+	overlaps overlapCounter,
+	logger *zap.SugaredLogger,
+) *Collector {
+	return &Collector{
+		heartbeatService: heartbeatService,
+		monitorService:   monitorService,
+		overlaps:         overlaps,
+		logger:           logger.Named("[metrics]"),
+		monitors:         make(map[string]*monitorSample),
+	}
+}
+
+// RegisterEventHandlers keeps the exported samples in sync with heartbeats and monitor changes
+func (c *Collector) RegisterEventHandlers(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.HeartbeatEvent, c.handleHeartbeat)
+	eventBus.Subscribe(events.MonitorUpdated, c.handleMonitorUpdated)
+	eventBus.Subscribe(events.MonitorDeleted, c.handleMonitorDeleted)
+}
+
+func (c *Collector) handleHeartbeat(event events.Event) {
+	hb, ok := event.Payload.(*shared.HeartBeatModel)
+	if !ok {
+		return
+	}
+
+	c.mu.RLock()
+	_, known := c.monitors[hb.MonitorID]
+	c.mu.RUnlock()
+
+	var m *monitor.Model
+	if !known {
+		var err error
+		m, err = c.monitorService.FindByID(context.Background(), hb.MonitorID)
+		if err != nil || m == nil {
+			c.logger.Warnw("Failed to find monitor for heartbeat", "monitorID", hb.MonitorID, "error", err)
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sample, ok := c.monitors[hb.MonitorID]
+	if !ok {
+		if m == nil {
+			// dropped while the monitor was being looked up
+			return
+		}
+		sample = &monitorSample{name: m.Name, monitorType: m.Type}
+		c.monitors[hb.MonitorID] = sample
+	}
+	sample.status = hb.Status
+	sample.ping = hb.Ping
+}
+
+func (c *Collector) handleMonitorUpdated(event events.Event) {
+	m, ok := event.Payload.(*shared.Monitor)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !m.Active {
+		delete(c.monitors, m.ID)
+		return
+	}
+	if sample, ok := c.monitors[m.ID]; ok {
+		sample.name = m.Name
+		sample.monitorType = m.Type
+	}
+}
+
+func (c *Collector) handleMonitorDeleted(event events.Event) {
+	monitorID, ok := event.Payload.(string)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.monitors, monitorID)
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- monitorUpDesc
+	ch <- monitorStatusDesc
+	ch <- monitorResponseTimeDesc
+	ch <- monitorUptimeDesc
+	ch <- monitorSkippedRunsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	samples := make(map[string]monitorSample, len(c.monitors))
+	for id, sample := range c.monitors {
+		samples[id] = *sample
+	}
+	c.mu.RUnlock()
+
+	uptime := c.uptimeRatios(samples)
+
+	var skipped map[string]uint64
+	if c.overlaps != nil {
+		skipped = c.overlaps.SkippedOverlappingRuns()
+	}
+
+	for id, sample := range samples {
+		labels := []string{id, sample.name, sample.monitorType}
+
+		up := 0.0
+		if sample.status == shared.MonitorStatusUp {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(monitorUpDesc, prometheus.GaugeValue, up, labels...)
+		ch <- prometheus.MustNewConstMetric(monitorStatusDesc, prometheus.GaugeValue, float64(sample.status), labels...)
+		ch <- prometheus.MustNewConstMetric(monitorResponseTimeDesc, prometheus.GaugeValue, float64(sample.ping), labels...)
+		ch <- prometheus.MustNewConstMetric(monitorSkippedRunsDesc, prometheus.CounterValue, float64(skipped[id]), labels...)
+
+		for period, percent := range uptime[id] {
+			ch <- prometheus.MustNewConstMetric(monitorUptimeDesc, prometheus.GaugeValue, percent/100, append(labels, period)...)
+		}
+	}
+}
+
+// uptimeRatios returns the uptime percentages per monitor and period, refreshed
+// at most once per uptimeCacheTTL
+func (c *Collector) uptimeRatios(samples map[string]monitorSample) map[string]map[string]float64 {
+	c.uptimeMu.Lock()
+	defer c.uptimeMu.Unlock()
+
+	now := time.Now()
+	if c.uptime != nil && now.Sub(c.uptimeFetched) < uptimeCacheTTL {
+		return c.uptime
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	uptime := make(map[string]map[string]float64, len(samples))
+	for id := range samples {
+		stats, err := c.heartbeatService.FindUptimeStatsByMonitorID(ctx, id, uptimePeriods, now)
+		if err != nil {
+			c.logger.Warnw("Failed to compute uptime", "monitorID", id, "error", err)
+			continue
+		}
+		uptime[id] = stats
+	}
+
+	c.uptime = uptime
+	c.uptimeFetched = now
+	return uptime
+}
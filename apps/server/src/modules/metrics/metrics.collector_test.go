@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+type fakeMonitors struct {
+	monitor.Service
+	lookups int
+}
+
+func (f *fakeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	f.lookups++
+	return &monitor.Model{ID: id, Name: "Monitor " + id, Type: "http", Active: true}, nil
+}
+
+type fakeHeartbeats struct {
+	heartbeat.Service
+	uptime  map[string]map[string]float64
+	queries int
+}
+
+func (f *fakeHeartbeats) FindUptimeStatsByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]float64, error) {
+	f.queries++
+	return f.uptime[monitorID], nil
+}
+
+type fakeOverlaps map[string]uint64
+
+func (f fakeOverlaps) SkippedOverlappingRuns() map[string]uint64 { return f }
+
+func heartbeatEvent(monitorID string, status shared.MonitorStatus, ping int) events.Event {
+	return events.Event{
+		Type:    events.HeartbeatEvent,
+		Payload: &shared.HeartBeatModel{MonitorID: monitorID, Status: status, Ping: ping},
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	monitors := &fakeMonitors{}
+	heartbeats := &fakeHeartbeats{uptime: map[string]map[string]float64{
+		"a": {"24h": 100, "7d": 99.5, "30d": 98},
+		"b": {"24h": 50, "7d": 75, "30d": 90},
+	}}
+	c := newCollector(heartbeats, monitors, fakeOverlaps{"b": 2}, zap.NewNop().Sugar())
+
+	c.handleHeartbeat(heartbeatEvent("a", shared.MonitorStatusDown, 10))
+	c.handleHeartbeat(heartbeatEvent("a", shared.MonitorStatusUp, 42))
+	c.handleHeartbeat(heartbeatEvent("b", shared.MonitorStatusDown, 0))
+
+	if monitors.lookups != 2 {
+		t.Errorf("expected monitor names to be looked up once per monitor, got %d lookups", monitors.lookups)
+	}
+
+	expected := `
+# HELP peekaping_monitor_response_time_ms Response time of the last check in milliseconds.
+# TYPE peekaping_monitor_response_time_ms gauge
+peekaping_monitor_response_time_ms{monitor="a",name="Monitor a",type="http"} 42
+peekaping_monitor_response_time_ms{monitor="b",name="Monitor b",type="http"} 0
+# HELP peekaping_monitor_skipped_overlapping_runs_total Checks skipped because the previous check of the monitor was still running.
+# TYPE peekaping_monitor_skipped_overlapping_runs_total counter
+peekaping_monitor_skipped_overlapping_runs_total{monitor="a",name="Monitor a",type="http"} 0
+peekaping_monitor_skipped_overlapping_runs_total{monitor="b",name="Monitor b",type="http"} 2
+# HELP peekaping_monitor_up Whether the last check of the monitor was up (1) or not (0).
+# TYPE peekaping_monitor_up gauge
+peekaping_monitor_up{monitor="a",name="Monitor a",type="http"} 1
+peekaping_monitor_up{monitor="b",name="Monitor b",type="http"} 0
+# HELP peekaping_monitor_uptime_ratio Share of up heartbeats over the period, from 0 to 1.
+# TYPE peekaping_monitor_uptime_ratio gauge
+peekaping_monitor_uptime_ratio{monitor="a",name="Monitor a",period="24h",type="http"} 1
+peekaping_monitor_uptime_ratio{monitor="a",name="Monitor a",period="30d",type="http"} 0.98
+peekaping_monitor_uptime_ratio{monitor="a",name="Monitor a",period="7d",type="http"} 0.995
+peekaping_monitor_uptime_ratio{monitor="b",name="Monitor b",period="24h",type="http"} 0.5
+peekaping_monitor_uptime_ratio{monitor="b",name="Monitor b",period="30d",type="http"} 0.9
+peekaping_monitor_uptime_ratio{monitor="b",name="Monitor b",period="7d",type="http"} 0.75
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"peekaping_monitor_up",
+		"peekaping_monitor_response_time_ms",
+		"peekaping_monitor_uptime_ratio",
+		"peekaping_monitor_skipped_overlapping_runs_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second scrape within the cache window reuses the uptime ratios
+	testutil.CollectAndCount(c)
+	if heartbeats.queries != 2 {
+		t.Errorf("expected uptime to be queried once per monitor, got %d queries", heartbeats.queries)
+	}
+}
+
+func TestCollector_MonitorEvents(t *testing.T) {
+	c := newCollector(&fakeHeartbeats{}, &fakeMonitors{}, nil, zap.NewNop().Sugar())
+
+	c.handleHeartbeat(heartbeatEvent("a", shared.MonitorStatusUp, 1))
+	c.handleHeartbeat(heartbeatEvent("b", shared.MonitorStatusUp, 1))
+	c.handleHeartbeat(heartbeatEvent("c", shared.MonitorStatusUp, 1))
+
+	c.handleMonitorUpdated(events.Event{Type: events.MonitorUpdated, Payload: &shared.Monitor{ID: "a", Name: "Renamed", Type: "tcp", Active: true}})
+	c.handleMonitorUpdated(events.Event{Type: events.MonitorUpdated, Payload: &shared.Monitor{ID: "b", Active: false}})
+	c.handleMonitorDeleted(events.Event{Type: events.MonitorDeleted, Payload: "c"})
+
+	expected := `
+# HELP peekaping_monitor_up Whether the last check of the monitor was up (1) or not (0).
+# TYPE peekaping_monitor_up gauge
+peekaping_monitor_up{monitor="a",name="Renamed",type="tcp"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "peekaping_monitor_up"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+func NewCollector(
+	heartbeatService heartbeat.Service,
+	monitorService monitor.Service,
+	supervisor *healthcheck.HealthCheckSupervisor,
+	logger *zap.SugaredLogger,
+) *Collector {
+	return newCollector(heartbeatService, monitorService, supervisor, logger)
+}
+
+func RegisterDependencies(container *dig.Container) {
+	container.Provide(NewCollector)
+	container.Provide(NewRoute)
+	container.Invoke(func(c *Collector, bus *events.EventBus) {
+		c.RegisterEventHandlers(bus)
+	})
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"peekaping/src/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Route struct {
+	registry *prometheus.Registry
+	token    string
+}
+
+func NewRoute(collector *Collector, cfg *config.Config) (*Route, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		return nil, err
+	}
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, err
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+
+	return &Route{
+		registry: registry,
+		token:    cfg.MetricsToken,
+	}, nil
+}
+
+// ConnectRoute mounts the Prometheus scrape endpoint at /metrics
+func (r *Route) ConnectRoute(rg gin.IRoutes) {
+	handler := promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+	rg.GET("/metrics", r.authorize, gin.WrapH(handler))
+}
+
+// authorize requires the configured bearer token, if any
+func (r *Route) authorize(ctx *gin.Context) {
+	if r.token == "" {
+		return
+	}
+	expected := "Bearer " + r.token
+	if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte(expected)) != 1 {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+	}
+}
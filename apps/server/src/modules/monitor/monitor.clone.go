@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const pushTokenAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newPushToken returns a random token like the ones the web app generates
+func newPushToken() (string, error) {
+	token := make([]byte, 24)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(pushTokenAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		token[i] = pushTokenAlphabet[n.Int64()]
+	}
+	return string(token), nil
+}
+
+// cloneDto turns a monitor into the create request of a paused copy. Push
+// tokens identify the monitor receiving a push, the copy gets its own.
+func cloneDto(source *Model, notificationIds []string, tagIds []string) (*CreateUpdateDto, error) {
+	dto := &CreateUpdateDto{
+		Type:            source.Type,
+		Name:            source.Name + " (copy)",
+		Interval:        source.Interval,
+		MaxRetries:      source.MaxRetries,
+		RetryInterval:   source.RetryInterval,
+		Timeout:         source.Timeout,
+		ResendInterval:  source.ResendInterval,
+		Active:          false,
+		NotificationIds: append([]string{}, notificationIds...),
+		TagIds:          append([]string{}, tagIds...),
+		ProxyId:         source.ProxyId,
+		Config:          source.Config,
+
+		IgnoreMaintenance:    source.IgnoreMaintenance,
+		RetentionDays:        source.RetentionDays,
+		SlowCheckThreshold:   source.SlowCheckThreshold,
+		Notes:                source.Notes,
+		RunbookURL:           source.RunbookURL,
+		FailoverProxyIds:     append([]string{}, source.FailoverProxyIds...),
+		LightProbeAfter:      source.LightProbeAfter,
+		LightProbeInterval:   source.LightProbeInterval,
+		StateWebhookURL:      source.StateWebhookURL,
+		StateWebhookTemplate: source.StateWebhookTemplate,
+	}
+
+	if source.PushToken == "" {
+		return dto, nil
+	}
+
+	token, err := newPushToken()
+	if err != nil {
+		return nil, err
+	}
+	dto.PushToken = token
+
+	// numbers stay as written when the config is encoded again
+	var config map[string]any
+	decoder := json.NewDecoder(strings.NewReader(source.Config))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if _, ok := config["pushToken"]; ok {
+		config["pushToken"] = token
+		rewritten, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		dto.Config = string(rewritten)
+	}
+	return dto, nil
+}
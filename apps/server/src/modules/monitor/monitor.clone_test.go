@@ -0,0 +1,154 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/monitor_notification"
+	"peekaping/src/modules/monitor_tag"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// cloneMonitorService creates every monitor under the id "copy"
+type cloneMonitorService struct {
+	fakeMonitorService
+	created *CreateUpdateDto
+}
+
+func (f *cloneMonitorService) ValidateMonitorConfig(monitorType string, configJSON string) error {
+	return nil
+}
+
+func (f *cloneMonitorService) Create(ctx context.Context, dto *CreateUpdateDto) (*Model, error) {
+	f.created = dto
+	created := &Model{ID: "copy", Name: dto.Name, Type: dto.Type, Active: dto.Active, Config: dto.Config, PushToken: dto.PushToken}
+	f.monitors["copy"] = created
+	return created, nil
+}
+
+func (f *cloneMonitorService) SetParent(ctx context.Context, id string, parentID string) (*Model, error) {
+	f.monitors[id].ParentID = parentID
+	return f.monitors[id], nil
+}
+
+type cloneNotificationService struct {
+	fakeMonitorNotificationService
+	assigned []string
+}
+
+func (f *cloneNotificationService) Create(ctx context.Context, monitorID string, notificationID string) (*monitor_notification.Model, error) {
+	f.assigned = append(f.assigned, monitorID+":"+notificationID)
+	return &monitor_notification.Model{MonitorID: monitorID, NotificationID: notificationID}, nil
+}
+
+type cloneTagService struct {
+	monitor_tag.Service
+	assigned []string
+}
+
+func (f *cloneTagService) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_tag.Model, error) {
+	return []*monitor_tag.Model{{MonitorID: monitorID, TagID: "t1"}, {MonitorID: monitorID, TagID: "t2"}}, nil
+}
+
+func (f *cloneTagService) Create(ctx context.Context, monitorID string, tagID string) (*monitor_tag.Model, error) {
+	f.assigned = append(f.assigned, monitorID+":"+tagID)
+	return &monitor_tag.Model{MonitorID: monitorID, TagID: tagID}, nil
+}
+
+func TestMonitorController_Clone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &cloneMonitorService{fakeMonitorService: fakeMonitorService{monitors: map[string]*Model{
+		"m1": {
+			ID:             "m1",
+			Name:           "API",
+			Type:           "http",
+			Interval:       60,
+			Timeout:        16,
+			RetryInterval:  60,
+			ResendInterval: 3,
+			Active:         true,
+			Config:         `{"url":"https://api.example.com"}`,
+			Notes:          "Restart the api deployment first",
+			ParentID:       "gateway",
+		},
+	}}}
+	notifications := &cloneNotificationService{}
+	tags := &cloneTagService{}
+	controller := &MonitorController{
+		monitorService:             service,
+		logger:                     zap.NewNop().Sugar(),
+		monitorNotificationService: notifications,
+		monitorTagService:          tags,
+	}
+
+	router := gin.New()
+	router.POST("/monitors/:id/clone", controller.Clone)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/monitors/m1/clone", nil))
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data Model `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "copy", response.Data.ID)
+	assert.Equal(t, "API (copy)", response.Data.Name)
+	assert.False(t, response.Data.Active)
+	assert.Equal(t, "gateway", response.Data.ParentID)
+
+	assert.Equal(t, `{"url":"https://api.example.com"}`, service.created.Config)
+	assert.Equal(t, "Restart the api deployment first", service.created.Notes)
+	assert.Equal(t, 3, service.created.ResendInterval)
+	assert.Equal(t, []string{"copy:n1"}, notifications.assigned)
+	assert.Equal(t, []string{"copy:t1", "copy:t2"}, tags.assigned)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/monitors/missing/clone", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestMonitorController_Clone_RunsCreateValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// stored before the minimum interval was raised to 20 seconds
+	service := &cloneMonitorService{fakeMonitorService: fakeMonitorService{monitors: map[string]*Model{
+		"m1": {ID: "m1", Name: "API", Type: "http", Interval: 10, Timeout: 16, RetryInterval: 60},
+	}}}
+	controller := &MonitorController{
+		monitorService:             service,
+		logger:                     zap.NewNop().Sugar(),
+		monitorNotificationService: &cloneNotificationService{},
+		monitorTagService:          &cloneTagService{},
+	}
+
+	router := gin.New()
+	router.POST("/monitors/:id/clone", controller.Clone)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/monitors/m1/clone", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Nil(t, service.created)
+}
+
+func TestCloneDto_RotatesPushToken(t *testing.T) {
+	source := &Model{
+		Name:      "Nightly backup",
+		Type:      "push",
+		PushToken: "abcdefghijklmnopqrstuvwx",
+		Config:    `{"pushToken":"abcdefghijklmnopqrstuvwx"}`,
+	}
+
+	dto, err := cloneDto(source, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, dto.PushToken, 24)
+	assert.NotEqual(t, source.PushToken, dto.PushToken)
+	assert.JSONEq(t, `{"pushToken":"`+dto.PushToken+`"}`, dto.Config)
+	assert.Equal(t, "Nightly backup (copy)", dto.Name)
+	assert.NotNil(t, dto.NotificationIds, "notification_ids is required on create")
+}
@@ -1,10 +1,14 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/stats"
 	"peekaping/src/utils"
 	"strings"
 	"time"
@@ -127,22 +131,38 @@ func (ic *MonitorController) Create(ctx *gin.Context) {
 		return
 	}
 
+	createdMonitor, ok := ic.create(ctx, monitor)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Monitor created successfully", createdMonitor))
+}
+
+// create validates and stores the monitor with its notifications and tags,
+// on failure the response is written and false returned
+func (ic *MonitorController) create(ctx *gin.Context, monitor *CreateUpdateDto) (*Model, bool) {
 	if err := utils.Validate.Struct(monitor); err != nil {
 		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
-		return
+		return nil, false
 	}
 
 	// Validate monitor type and config
 	if err := ic.monitorService.ValidateMonitorConfig(monitor.Type, monitor.Config); err != nil {
 		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Invalid monitor configuration: %v", err)))
-		return
+		return nil, false
+	}
+
+	if err := ValidateStateWebhookTemplate(monitor.StateWebhookTemplate); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return nil, false
 	}
 
 	createdMonitor, err := ic.monitorService.Create(ctx, monitor)
 	if err != nil {
 		ic.logger.Errorw("Failed to create monitor", "error", err)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
-		return
+		return nil, false
 	}
 	ic.logger.Infof("Created monitor: %+v\n", createdMonitor)
 
@@ -153,7 +173,7 @@ func (ic *MonitorController) Create(ctx *gin.Context) {
 			if err != nil {
 				ic.logger.Errorw("Failed to create monitor-notification record", "error", err)
 				ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
-				return
+				return nil, false
 			}
 		}
 	}
@@ -165,12 +185,12 @@ func (ic *MonitorController) Create(ctx *gin.Context) {
 			if err != nil {
 				ic.logger.Errorw("Failed to create monitor-tag record", "error", err)
 				ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
-				return
+				return nil, false
 			}
 		}
 	}
 
-	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Monitor created successfully", createdMonitor))
+	return createdMonitor, true
 }
 
 // @Router		/monitors/{id} [get]
@@ -240,6 +260,19 @@ func (ic *MonitorController) FindByID(ctx *gin.Context) {
 		TagIds:          tagIds,
 		ProxyId:         monitor.ProxyId,
 		Config:          monitor.Config,
+
+		IgnoreMaintenance:    monitor.IgnoreMaintenance,
+		RetentionDays:        monitor.RetentionDays,
+		SlowCheckThreshold:   monitor.SlowCheckThreshold,
+		Notes:                monitor.Notes,
+		RunbookURL:           monitor.RunbookURL,
+		FailoverProxyIds:     monitor.FailoverProxyIds,
+		LightProbeAfter:      monitor.LightProbeAfter,
+		LightProbeInterval:   monitor.LightProbeInterval,
+		StateWebhookURL:      monitor.StateWebhookURL,
+		StateWebhookTemplate: monitor.StateWebhookTemplate,
+		ParentID:             monitor.ParentID,
+		ResumeAt:             monitor.ResumeAt,
 	}
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
@@ -278,6 +311,11 @@ func (ic *MonitorController) UpdateFull(ctx *gin.Context) {
 		return
 	}
 
+	if err := ValidateStateWebhookTemplate(monitor.StateWebhookTemplate); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
 	updatedMonitor, err := ic.monitorService.UpdateFull(ctx, id, &monitor)
 	if err != nil {
 		ic.logger.Errorw("Failed to update monitor", "error", err)
@@ -353,6 +391,13 @@ func (ic *MonitorController) UpdatePartial(ctx *gin.Context) {
 		}
 	}
 
+	if monitor.StateWebhookTemplate != nil {
+		if err := ValidateStateWebhookTemplate(*monitor.StateWebhookTemplate); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+	}
+
 	updatedMonitor, err := ic.monitorService.UpdatePartial(ctx, id, &monitor, false)
 	if err != nil {
 		ic.logger.Errorw("Failed to update monitor", "error", err)
@@ -523,6 +568,91 @@ func (ic *MonitorController) FindByMonitorIDPaginated(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", results))
 }
 
+// @Router	/monitors/{id}/heartbeats/cursor [get]
+// @Summary	Get heartbeats for a monitor by cursor, newest first
+// @Tags		Monitors
+// @Produce	json
+// @Security BearerAuth
+// @Param	id	path	string	true	"Monitor ID"
+// @Param	limit	query	int	false	"Number of heartbeats per page (default 50)"
+// @Param	cursor	query	string	false	"next_cursor of the previous page, empty for the newest heartbeats"
+// @Param	important	query	bool	false	"Filter by important heartbeats only"
+// @Success	200	{object}	utils.ApiResponse[heartbeat.CursorPage]
+// @Failure	400	{object}	utils.APIError[any]
+// @Failure	500	{object}	utils.APIError[any]
+func (ic *MonitorController) FindByMonitorIDAfterCursor(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 50)
+	if err != nil || limit < 1 || limit > 1000 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter (1-1000)"))
+		return
+	}
+
+	var importantPtr *bool
+	if ctx.Query("important") != "" {
+		importantPtr, err = utils.GetQueryBool(ctx, "important")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid important parameter (must be true or false)"))
+			return
+		}
+	}
+
+	result, err := ic.monitorService.GetHeartbeatsAfterCursor(ctx, id, limit, ctx.Query("cursor"), importantPtr)
+	if errors.Is(err, heartbeat.ErrInvalidCursor) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid cursor parameter"))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to get heartbeats", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", result))
+}
+
+// @Router	/monitors/{id}/heartbeats/{heartbeatId} [get]
+// @Summary	Get a heartbeat of a monitor
+// @Description	Includes the response headers captured by HTTP monitors with capture_headers.
+// @Tags		Monitors
+// @Produce	json
+// @Security BearerAuth
+// @Param	id	path	string	true	"Monitor ID"
+// @Param	heartbeatId	path	string	true	"Heartbeat ID"
+// @Success	200	{object}	utils.ApiResponse[heartbeat.Model]
+// @Failure	404	{object}	utils.APIError[any]
+// @Failure	500	{object}	utils.APIError[any]
+func (ic *MonitorController) FindHeartbeatByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	heartbeatID := ctx.Param("heartbeatId")
+
+	hb, err := ic.monitorService.GetHeartbeat(ctx, id, heartbeatID)
+	if err != nil {
+		ic.logger.Errorw("Failed to get heartbeat", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if hb == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Heartbeat not found"))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", hb))
+}
+
+// maxStatPoints bounds the stat points returned for a single request, a day of minutes
+const maxStatPoints = 1441
+
+func granularityForPeriod(period stats.StatPeriod) string {
+	switch period {
+	case stats.StatHourly:
+		return "hour"
+	case stats.StatDaily:
+		return "day"
+	default:
+		return "minute"
+	}
+}
+
 // @Router /monitors/{id}/stats/points [get]
 // @Summary Get monitor stat points (ping/up/down) from stats tables
 // @Tags Monitors
@@ -531,7 +661,7 @@ func (ic *MonitorController) FindByMonitorIDPaginated(ctx *gin.Context) {
 // @Param id path string true "Monitor ID"
 // @Param since query string true "Start time (RFC3339)"
 // @Param until query string false "End time (RFC3339, default now)"
-// @Param granularity query string false "Granularity (minute, hour, day)"
+// @Param granularity query string false "Granularity (minute, hour, day, auto). auto picks the finest one that fits the range"
 // @Success 200 {object} utils.ApiResponse[StatPointsSummaryDto]
 // @Failure 400 {object} utils.APIError[any]
 // @Failure 404 {object} utils.APIError[any]
@@ -563,6 +693,9 @@ func (ic *MonitorController) GetStatPoints(ctx *gin.Context) {
 	}
 
 	granularity := ctx.DefaultQuery("granularity", "minute")
+	if granularity == "auto" {
+		granularity = granularityForPeriod(stats.PeriodForRange(since, until, maxStatPoints))
+	}
 
 	var interval time.Duration
 	switch granularity {
@@ -573,7 +706,7 @@ func (ic *MonitorController) GetStatPoints(ctx *gin.Context) {
 	case "day":
 		interval = 24 * time.Hour
 	default:
-		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'granularity' parameter (must be minute, hour, or day)"))
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'granularity' parameter (must be minute, hour, day or auto)"))
 		return
 	}
 
@@ -584,8 +717,8 @@ func (ic *MonitorController) GetStatPoints(ctx *gin.Context) {
 
 	diff := until.Sub(since)
 	estPoints := int(diff/interval) + 1
-	if estPoints > 1441 {
-		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Too many points requested: %d (max 1441)", estPoints)))
+	if estPoints > maxStatPoints {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Too many points requested: %d (max %d)", estPoints, maxStatPoints)))
 		return
 	}
 
@@ -619,6 +752,28 @@ func (ic *MonitorController) GetUptimeStats(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", stats))
 }
 
+// @Router /monitors/{id}/stats/latency [get]
+// @Summary Get monitor response time percentiles (24h, 7d, 30d)
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} utils.ApiResponse[LatencyPercentilesDto]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) GetLatencyPercentiles(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	percentiles, err := ic.monitorService.GetLatencyPercentiles(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to get latency percentiles", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", percentiles))
+}
+
 // @Router		/monitors/batch [get]
 // @Summary		Get monitors by IDs
 // @Tags			Monitors
@@ -684,3 +839,332 @@ func (ic *MonitorController) ResetMonitorData(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Monitor data reset successfully", nil))
 }
+
+// @Router /monitors/validate-configs [get]
+// @Summary Validate all stored monitor configs
+// @Description Re-runs config validation for every stored monitor and reports the ones that fail, e.g. after a validator or schema change
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only validate monitors of this type"
+// @Success 200 {object} utils.ApiResponse[ConfigValidationReportDto]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) ValidateAllConfigs(ctx *gin.Context) {
+	report, err := ic.monitorService.ValidateAllConfigs(ctx, ctx.Query("type"))
+	if err != nil {
+		ic.logger.Errorw("Failed to validate monitor configs", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", report))
+}
+
+// @Router /monitors/{id}/config-versions [get]
+// @Summary List stored config versions of a monitor
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} utils.ApiResponse[[]monitor_config_version.Model]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) GetConfigVersions(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	versions, err := ic.monitorService.GetConfigVersions(ctx, id)
+	if err != nil {
+		if err.Error() == "monitor not found" {
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+			return
+		}
+		ic.logger.Errorw("Failed to fetch monitor config versions", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", versions))
+}
+
+// @Router /monitors/{id}/config-versions/{versionId}/restore [post]
+// @Summary Restore a stored config version of a monitor
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param versionId path string true "Config version ID"
+// @Success 200 {object} utils.ApiResponse[Model]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) RestoreConfigVersion(ctx *gin.Context) {
+	id := ctx.Param("id")
+	versionID := ctx.Param("versionId")
+
+	updatedMonitor, err := ic.monitorService.RestoreConfigVersion(ctx, id, versionID)
+	if err != nil {
+		switch {
+		case err.Error() == "monitor not found":
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		case err.Error() == "config version not found":
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Config version not found"))
+		case errors.Is(err, ErrInvalidConfigVersion):
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		default:
+			ic.logger.Errorw("Failed to restore monitor config version", "monitorID", id, "versionID", versionID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Config version restored successfully", updatedMonitor))
+}
+
+// @Router /monitors/{id}/ack [post]
+// @Summary Acknowledge the active alert of a monitor
+// @Description Suppresses re-notifications until the duration expires or the monitor recovers
+// @Tags Monitors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param body body AckDto true "Acknowledgment duration"
+// @Success 200 {object} utils.ApiResponse[monitor_ack.Model]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 409 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) Acknowledge(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var dto AckDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	acknowledgedBy := ctx.GetString("email")
+	if acknowledgedBy == "" {
+		acknowledgedBy = ctx.GetString("userId")
+	}
+
+	ack, err := ic.monitorService.AcknowledgeAlert(ctx, id, acknowledgedBy, time.Duration(dto.DurationMinutes)*time.Minute)
+	if err != nil {
+		switch {
+		case err.Error() == "monitor not found":
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		case errors.Is(err, ErrNoActiveAlert):
+			ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+		default:
+			ic.logger.Errorw("Failed to acknowledge monitor alert", "monitorID", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Alert acknowledged", ack))
+}
+
+// @Router /monitors/{id}/parent [put]
+// @Summary Set the parent of a monitor
+// @Description Down alerts of the monitor are held back while its parent is down
+// @Tags Monitors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param body body SetParentDto true "Parent monitor"
+// @Success 200 {object} utils.ApiResponse[Model]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 409 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) SetParent(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var dto SetParentDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	ic.updateParent(ctx, id, dto.ParentID)
+}
+
+// @Router /monitors/{id}/parent [delete]
+// @Summary Clear the parent of a monitor
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} utils.ApiResponse[Model]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) ClearParent(ctx *gin.Context) {
+	ic.updateParent(ctx, ctx.Param("id"), "")
+}
+
+func (ic *MonitorController) updateParent(ctx *gin.Context, id string, parentID string) {
+	updatedMonitor, err := ic.monitorService.SetParent(ctx, id, parentID)
+	if err != nil {
+		switch {
+		case err.Error() == "monitor not found":
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		case errors.Is(err, ErrParentNotFound):
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		case errors.Is(err, ErrParentCycle):
+			ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+		default:
+			ic.logger.Errorw("Failed to update monitor parent", "monitorID", id, "parentID", parentID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Monitor parent updated", updatedMonitor))
+}
+
+// @Router /monitors/{id}/pause [post]
+// @Summary Pause a monitor
+// @Description Stops the checks of the monitor, with resume_at it resumes automatically
+// @Tags Monitors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param body body PauseDto false "Scheduled resume"
+// @Success 200 {object} utils.ApiResponse[Model]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) Pause(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	// the body is optional, an empty one pauses until resumed
+	var dto PauseDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updatedMonitor, err := ic.monitorService.Pause(ctx, id, dto.ResumeAt)
+	if err != nil {
+		switch {
+		case err.Error() == "monitor not found":
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		case errors.Is(err, ErrResumeAtInPast):
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		default:
+			ic.logger.Errorw("Failed to pause monitor", "monitorID", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Monitor paused", updatedMonitor))
+}
+
+// @Router /monitors/{id}/resume [post]
+// @Summary Resume a paused monitor
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} utils.ApiResponse[Model]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) Resume(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	updatedMonitor, err := ic.monitorService.Resume(ctx, id)
+	if err != nil {
+		if err.Error() == "monitor not found" {
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+			return
+		}
+		ic.logger.Errorw("Failed to resume monitor", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Monitor resumed", updatedMonitor))
+}
+
+// @Router		/monitors/{id}/clone [post]
+// @Summary		Clone monitor
+// @Description	Creates a paused copy of the monitor with its config, tags and notifications
+// @Tags			Monitors
+// @Produce		json
+// @Security  BearerAuth
+// @Param       id   path      string  true  "Monitor ID"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *MonitorController) Clone(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	source, err := ic.monitorService.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if source == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		return
+	}
+
+	notificationRels, err := ic.monitorNotificationService.FindByMonitorID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor-notification relations", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	notificationIds := make([]string, 0, len(notificationRels))
+	for _, rel := range notificationRels {
+		notificationIds = append(notificationIds, rel.NotificationID)
+	}
+
+	tagRels, err := ic.monitorTagService.FindByMonitorID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor-tag relations", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	tagIds := make([]string, 0, len(tagRels))
+	for _, rel := range tagRels {
+		tagIds = append(tagIds, rel.TagID)
+	}
+
+	dto, err := cloneDto(source, notificationIds, tagIds)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Invalid monitor configuration: %v", err)))
+		return
+	}
+
+	clonedMonitor, ok := ic.create(ctx, dto)
+	if !ok {
+		return
+	}
+
+	// the copy depends on the same monitor
+	if source.ParentID != "" {
+		clonedMonitor, err = ic.monitorService.SetParent(ctx, clonedMonitor.ID, source.ParentID)
+		if err != nil {
+			ic.logger.Errorw("Failed to set parent of cloned monitor", "monitorID", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Monitor cloned successfully", clonedMonitor))
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/monitor_notification"
+	"peekaping/src/modules/monitor_tag"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeMonitorService struct {
+	Service
+	monitors map[string]*Model
+}
+
+func (f *fakeMonitorService) FindByID(ctx context.Context, id string) (*Model, error) {
+	return f.monitors[id], nil
+}
+
+type fakeMonitorNotificationService struct {
+	monitor_notification.Service
+}
+
+func (f *fakeMonitorNotificationService) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_notification.Model, error) {
+	return []*monitor_notification.Model{{MonitorID: monitorID, NotificationID: "n1"}}, nil
+}
+
+type fakeMonitorTagService struct {
+	monitor_tag.Service
+}
+
+func (f *fakeMonitorTagService) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_tag.Model, error) {
+	return nil, nil
+}
+
+func TestMonitorController_FindByID_NotesAndRunbook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := &MonitorController{
+		monitorService: &fakeMonitorService{monitors: map[string]*Model{
+			"m1": {
+				ID:         "m1",
+				Name:       "API",
+				Type:       "http",
+				CreatedAt:  time.Now(),
+				UpdatedAt:  time.Now(),
+				Notes:      "Restart the **api** deployment first",
+				RunbookURL: "https://wiki.example.com/runbooks/api",
+			},
+		}},
+		logger:                     zap.NewNop().Sugar(),
+		monitorNotificationService: &fakeMonitorNotificationService{},
+		monitorTagService:          &fakeMonitorTagService{},
+	}
+
+	router := gin.New()
+	router.GET("/monitors/:id", controller.FindByID)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/monitors/m1", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data MonitorResponseDto `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "Restart the **api** deployment first", response.Data.Notes)
+	assert.Equal(t, "https://wiki.example.com/runbooks/api", response.Data.RunbookURL)
+	assert.Equal(t, []string{"n1"}, response.Data.NotificationIds)
+}
+
+func TestValidateStateWebhookTemplate(t *testing.T) {
+	assert.NoError(t, ValidateStateWebhookTemplate(""))
+	assert.NoError(t, ValidateStateWebhookTemplate(`{"text": "{{ name }} went {{ transition.status }}"}`))
+	assert.Error(t, ValidateStateWebhookTemplate(`{% frobnicate %}`))
+	assert.Error(t, ValidateStateWebhookTemplate(`{% if status %}down`))
+}
@@ -1,6 +1,9 @@
 package monitor
 
-import "peekaping/src/modules/heartbeat"
+import (
+	"peekaping/src/modules/heartbeat"
+	"time"
+)
 
 type CreateUpdateDto struct {
 	Type            string   `json:"type" validate:"required" example:"http"`
@@ -16,6 +19,19 @@ type CreateUpdateDto struct {
 	ProxyId         string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
 	Config          string   `json:"config"`
 	PushToken       string   `json:"push_token"`
+
+	IgnoreMaintenance    bool   `json:"ignore_maintenance" example:"false"`
+	RetentionDays        int    `json:"retention_days" validate:"min=0" example:"30"`
+	SlowCheckThreshold   int    `json:"slow_check_threshold" validate:"min=0" example:"50"`
+	Notes                string `json:"notes" validate:"max=10000" example:"Check the replica lag first"`
+	RunbookURL           string `json:"runbook_url" validate:"omitempty,http_url,max=2048" example:"https://wiki.example.com/runbooks/api"`
+	LightProbeAfter      int    `json:"light_probe_after" validate:"min=0,max=100" example:"3"`
+	LightProbeInterval   int    `json:"light_probe_interval" validate:"min=0,max=86400" example:"300"`
+	StateWebhookURL      string `json:"state_webhook_url" validate:"omitempty,http_url,max=2048" example:"https://automation.example.com/hooks/monitor"`
+	StateWebhookTemplate string `json:"state_webhook_template" validate:"omitempty,max=10000" example:"{{ name }} went {{ transition.status }}"`
+
+	// Proxies tried in order when the proxy is unhealthy
+	FailoverProxyIds []string `json:"failover_proxy_ids" validate:"max=5,dive,required"`
 }
 
 type PartialUpdateDto struct {
@@ -33,6 +49,35 @@ type PartialUpdateDto struct {
 	Status          *heartbeat.MonitorStatus `json:"status,omitempty" example:"1"`
 	Config          *string                  `json:"config,omitempty"`
 	PushToken       *string                  `json:"push_token,omitempty"`
+
+	IgnoreMaintenance    *bool   `json:"ignore_maintenance,omitempty" example:"false"`
+	RetentionDays        *int    `json:"retention_days,omitempty" validate:"omitempty,min=0" example:"30"`
+	SlowCheckThreshold   *int    `json:"slow_check_threshold,omitempty" validate:"omitempty,min=0" example:"50"`
+	Notes                *string `json:"notes,omitempty" validate:"omitempty,max=10000" example:"Check the replica lag first"`
+	RunbookURL           *string `json:"runbook_url,omitempty" validate:"omitempty,http_url,max=2048" example:"https://wiki.example.com/runbooks/api"`
+	LightProbeAfter      *int    `json:"light_probe_after,omitempty" validate:"omitempty,min=0,max=100" example:"3"`
+	LightProbeInterval   *int    `json:"light_probe_interval,omitempty" validate:"omitempty,min=0,max=86400" example:"300"`
+	StateWebhookURL      *string `json:"state_webhook_url,omitempty" validate:"omitempty,http_url,max=2048" example:"https://automation.example.com/hooks/monitor"`
+	StateWebhookTemplate *string `json:"state_webhook_template,omitempty" validate:"omitempty,max=10000" example:"{{ name }} went {{ transition.status }}"`
+
+	FailoverProxyIds *[]string `json:"failover_proxy_ids,omitempty" validate:"omitempty,max=5,dive,required"`
+}
+
+// AckDto acknowledges the active alert of a monitor for a while
+type AckDto struct {
+	// Minutes to suppress re-notifications for, at most a week
+	DurationMinutes int `json:"duration_minutes" validate:"required,min=1,max=10080" example:"60"`
+}
+
+// SetParentDto makes a monitor depend on another one
+type SetParentDto struct {
+	ParentID string `json:"parent_id" validate:"required" example:"6830ad485361f19c598d6d90"`
+}
+
+// PauseDto optionally schedules the resume of a paused monitor
+type PauseDto struct {
+	// Time after which the monitor resumes automatically, omit to pause until resumed
+	ResumeAt *time.Time `json:"resume_at" example:"2025-07-21T18:00:00Z"`
 }
 
 // UptimeStatsDto represents uptime percentages for various periods
@@ -62,6 +107,19 @@ type MonitorResponseDto struct {
 	ProxyId         string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
 	Config          string   `json:"config"`
 	PushToken       string   `json:"push_token"`
+
+	IgnoreMaintenance    bool       `json:"ignore_maintenance" example:"false"`
+	RetentionDays        int        `json:"retention_days" example:"30"`
+	SlowCheckThreshold   int        `json:"slow_check_threshold" example:"50"`
+	Notes                string     `json:"notes" example:"Check the replica lag first"`
+	RunbookURL           string     `json:"runbook_url" example:"https://wiki.example.com/runbooks/api"`
+	FailoverProxyIds     []string   `json:"failover_proxy_ids"`
+	LightProbeAfter      int        `json:"light_probe_after" example:"3"`
+	LightProbeInterval   int        `json:"light_probe_interval" example:"300"`
+	StateWebhookURL      string     `json:"state_webhook_url" example:"https://automation.example.com/hooks/monitor"`
+	StateWebhookTemplate string     `json:"state_webhook_template" example:"{{ name }} went {{ transition.status }}"`
+	ParentID             string     `json:"parent_id" example:"6830ad485361f19c598d6d90"`
+	ResumeAt             *time.Time `json:"resume_at" example:"2025-07-21T18:00:00Z"`
 }
 
 // StatPointsSummaryDto represents stat points and summary for a period
@@ -71,13 +129,15 @@ type MonitorResponseDto struct {
 // @Property maxPing number "Maximum ping in the period"
 // @Property minPing number "Minimum ping in the period"
 // @Property avgPing number "Average ping in the period"
+// @Property avgDuration number "Average check duration in the period"
 // @Property uptime number "Uptime percentage (0-100) in the period"
 type StatPointsSummaryDto struct {
-	Points  []*StatPoint `json:"points"`
-	MaxPing *float64     `json:"maxPing"`
-	MinPing *float64     `json:"minPing"`
-	AvgPing *float64     `json:"avgPing"`
-	Uptime  *float64     `json:"uptime"`
+	Points      []*StatPoint `json:"points"`
+	MaxPing     *float64     `json:"maxPing"`
+	MinPing     *float64     `json:"minPing"`
+	AvgPing     *float64     `json:"avgPing"`
+	AvgDuration *float64     `json:"avgDuration"`
+	Uptime      *float64     `json:"uptime"`
 }
 
 // CustomUptimeStatsDto represents uptime percentages for 24h, 30d, 365d
@@ -88,3 +148,27 @@ type CustomUptimeStatsDto struct {
 	Uptime30d  float64 `json:"30d"`
 	Uptime365d float64 `json:"365d"`
 }
+
+// LatencyPercentilesDto represents response time percentiles for 24h, 7d, 30d
+// All values are in milliseconds
+type LatencyPercentilesDto struct {
+	Latency24h *heartbeat.LatencyPercentiles `json:"24h"`
+	Latency7d  *heartbeat.LatencyPercentiles `json:"7d"`
+	Latency30d *heartbeat.LatencyPercentiles `json:"30d"`
+}
+
+// ConfigValidationFailureDto describes a stored monitor whose config no longer passes validation
+type ConfigValidationFailureDto struct {
+	MonitorID string `json:"monitor_id" example:"60c72b2f9b1e8b6f1f8e4b1a"`
+	Name      string `json:"name" example:"My Monitor"`
+	Type      string `json:"type" example:"http"`
+	Error     string `json:"error" example:"Key: 'HTTPConfig.Url' Error:Field validation for 'Url' failed on the 'required' tag"`
+}
+
+// ConfigValidationReportDto summarizes a validation run over stored monitor configs
+type ConfigValidationReportDto struct {
+	Checked  int                           `json:"checked" example:"42"`
+	Valid    int                           `json:"valid" example:"41"`
+	Invalid  int                           `json:"invalid" example:"1"`
+	Failures []*ConfigValidationFailureDto `json:"failures"`
+}
@@ -29,6 +29,19 @@ type mongoModel struct {
 	Config         string                  `bson:"config"`
 	ProxyId        *primitive.ObjectID     `bson:"proxy_id,omitempty"`
 	PushToken      string                  `bson:"push_token"`
+
+	IgnoreMaintenance    bool       `bson:"ignore_maintenance"`
+	RetentionDays        int        `bson:"retention_days"`
+	SlowCheckThreshold   int        `bson:"slow_check_threshold"`
+	Notes                string     `bson:"notes"`
+	RunbookURL           string     `bson:"runbook_url"`
+	FailoverProxyIds     []string   `bson:"failover_proxy_ids"`
+	LightProbeAfter      int        `bson:"light_probe_after"`
+	LightProbeInterval   int        `bson:"light_probe_interval"`
+	StateWebhookURL      string     `bson:"state_webhook_url"`
+	StateWebhookTemplate string     `bson:"state_webhook_template"`
+	ParentID             string     `bson:"parent_id"`
+	ResumeAt             *time.Time `bson:"resume_at,omitempty"`
 }
 
 type mongoUpdateModel struct {
@@ -46,6 +59,19 @@ type mongoUpdateModel struct {
 	PushToken      *string                  `bson:"push_token,omitempty"`
 	CreatedAt      *time.Time               `bson:"created_at,omitempty"`
 	UpdatedAt      *time.Time               `bson:"updated_at,omitempty"`
+
+	IgnoreMaintenance    *bool   `bson:"ignore_maintenance,omitempty"`
+	RetentionDays        *int    `bson:"retention_days,omitempty"`
+	SlowCheckThreshold   *int    `bson:"slow_check_threshold,omitempty"`
+	Notes                *string `bson:"notes,omitempty"`
+	RunbookURL           *string `bson:"runbook_url,omitempty"`
+	LightProbeAfter      *int    `bson:"light_probe_after,omitempty"`
+	LightProbeInterval   *int    `bson:"light_probe_interval,omitempty"`
+	StateWebhookURL      *string `bson:"state_webhook_url,omitempty"`
+	StateWebhookTemplate *string `bson:"state_webhook_template,omitempty"`
+	ParentID             *string `bson:"parent_id,omitempty"`
+
+	FailoverProxyIds *[]string `bson:"failover_proxy_ids,omitempty"`
 }
 
 func toDomainModel(mm *mongoModel) *Model {
@@ -71,6 +97,19 @@ func toDomainModel(mm *mongoModel) *Model {
 		PushToken:      mm.PushToken,
 		CreatedAt:      mm.CreatedAt,
 		UpdatedAt:      mm.UpdatedAt,
+
+		IgnoreMaintenance:    mm.IgnoreMaintenance,
+		RetentionDays:        mm.RetentionDays,
+		SlowCheckThreshold:   mm.SlowCheckThreshold,
+		Notes:                mm.Notes,
+		RunbookURL:           mm.RunbookURL,
+		FailoverProxyIds:     mm.FailoverProxyIds,
+		LightProbeAfter:      mm.LightProbeAfter,
+		LightProbeInterval:   mm.LightProbeInterval,
+		StateWebhookURL:      mm.StateWebhookURL,
+		StateWebhookTemplate: mm.StateWebhookTemplate,
+		ParentID:             mm.ParentID,
+		ResumeAt:             mm.ResumeAt,
 	}
 }
 
@@ -146,6 +185,19 @@ func (r *MonitorRepositoryImpl) Create(ctx context.Context, monitor *Model) (*Mo
 		Config:         monitor.Config,
 		ProxyId:        proxyObjectID,
 		PushToken:      monitor.PushToken,
+
+		IgnoreMaintenance:    monitor.IgnoreMaintenance,
+		RetentionDays:        monitor.RetentionDays,
+		SlowCheckThreshold:   monitor.SlowCheckThreshold,
+		Notes:                monitor.Notes,
+		RunbookURL:           monitor.RunbookURL,
+		FailoverProxyIds:     monitor.FailoverProxyIds,
+		LightProbeAfter:      monitor.LightProbeAfter,
+		LightProbeInterval:   monitor.LightProbeInterval,
+		StateWebhookURL:      monitor.StateWebhookURL,
+		StateWebhookTemplate: monitor.StateWebhookTemplate,
+		ParentID:             monitor.ParentID,
+		ResumeAt:             monitor.ResumeAt,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mm)
@@ -323,6 +375,18 @@ func buildSetMapFromModel(m *Model, includeProxyId bool, proxyObjectID primitive
 		"created_at":      time.Now().UTC(),
 		"updated_at":      time.Now().UTC(),
 		"config":          m.Config,
+
+		"ignore_maintenance":     m.IgnoreMaintenance,
+		"retention_days":         m.RetentionDays,
+		"slow_check_threshold":   m.SlowCheckThreshold,
+		"notes":                  m.Notes,
+		"runbook_url":            m.RunbookURL,
+		"failover_proxy_ids":     m.FailoverProxyIds,
+		"light_probe_after":      m.LightProbeAfter,
+		"light_probe_interval":   m.LightProbeInterval,
+		"state_webhook_url":      m.StateWebhookURL,
+		"state_webhook_template": m.StateWebhookTemplate,
+		// parent_id is left alone, it only changes through UpdatePartial
 	}
 	if includeProxyId {
 		set["proxy_id"] = proxyObjectID
@@ -368,6 +432,39 @@ func buildSetMapFromUpdateModel(mu *mongoUpdateModel, includeProxyId bool, proxy
 	if mu.Config != nil {
 		set["config"] = *mu.Config
 	}
+	if mu.IgnoreMaintenance != nil {
+		set["ignore_maintenance"] = *mu.IgnoreMaintenance
+	}
+	if mu.RetentionDays != nil {
+		set["retention_days"] = *mu.RetentionDays
+	}
+	if mu.SlowCheckThreshold != nil {
+		set["slow_check_threshold"] = *mu.SlowCheckThreshold
+	}
+	if mu.Notes != nil {
+		set["notes"] = *mu.Notes
+	}
+	if mu.RunbookURL != nil {
+		set["runbook_url"] = *mu.RunbookURL
+	}
+	if mu.FailoverProxyIds != nil {
+		set["failover_proxy_ids"] = *mu.FailoverProxyIds
+	}
+	if mu.LightProbeAfter != nil {
+		set["light_probe_after"] = *mu.LightProbeAfter
+	}
+	if mu.LightProbeInterval != nil {
+		set["light_probe_interval"] = *mu.LightProbeInterval
+	}
+	if mu.StateWebhookURL != nil {
+		set["state_webhook_url"] = *mu.StateWebhookURL
+	}
+	if mu.StateWebhookTemplate != nil {
+		set["state_webhook_template"] = *mu.StateWebhookTemplate
+	}
+	if mu.ParentID != nil {
+		set["parent_id"] = *mu.ParentID
+	}
 	if includeProxyId && proxyObjectID != nil {
 		set["proxy_id"] = *proxyObjectID
 	}
@@ -383,10 +480,12 @@ func (r *MonitorRepositoryImpl) UpdateFull(ctx context.Context, id string, monit
 	filter := bson.M{"_id": objectID}
 	update := bson.M{}
 
+	// a full update cancels a scheduled resume
+	unset := bson.M{"resume_at": ""}
 	if monitor.ProxyId == "" {
 		set := buildSetMapFromModel(monitor, false, primitive.NilObjectID)
 		update["$set"] = set
-		update["$unset"] = bson.M{"proxy_id": ""}
+		unset["proxy_id"] = ""
 	} else {
 		proxyObjectID, err := primitive.ObjectIDFromHex(monitor.ProxyId)
 		if err != nil {
@@ -395,6 +494,7 @@ func (r *MonitorRepositoryImpl) UpdateFull(ctx context.Context, id string, monit
 		set := buildSetMapFromModel(monitor, true, proxyObjectID)
 		update["$set"] = set
 	}
+	update["$unset"] = unset
 
 	_, err = r.collection.UpdateOne(ctx, filter, update)
 	return err
@@ -431,6 +531,18 @@ func (r *MonitorRepositoryImpl) UpdatePartial(ctx context.Context, id string, mo
 		Config:         monitor.Config,
 		ProxyId:        proxyObjectID,
 		PushToken:      monitor.PushToken,
+
+		IgnoreMaintenance:    monitor.IgnoreMaintenance,
+		RetentionDays:        monitor.RetentionDays,
+		SlowCheckThreshold:   monitor.SlowCheckThreshold,
+		Notes:                monitor.Notes,
+		RunbookURL:           monitor.RunbookURL,
+		FailoverProxyIds:     monitor.FailoverProxyIds,
+		LightProbeAfter:      monitor.LightProbeAfter,
+		LightProbeInterval:   monitor.LightProbeInterval,
+		StateWebhookURL:      monitor.StateWebhookURL,
+		StateWebhookTemplate: monitor.StateWebhookTemplate,
+		ParentID:             monitor.ParentID,
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -448,8 +560,16 @@ func (r *MonitorRepositoryImpl) UpdatePartial(ctx context.Context, id string, mo
 	if len(set) > 0 {
 		update["$set"] = set
 	}
+	unset := bson.M{}
 	if unsetProxyId {
-		update["$unset"] = bson.M{"proxy_id": ""}
+		unset["proxy_id"] = ""
+	}
+	if monitor.Active != nil {
+		// pausing or resuming by hand cancels a scheduled resume
+		unset["resume_at"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 
 	if len(update) == 0 {
@@ -512,10 +632,65 @@ func (r *MonitorRepositoryImpl) RemoveProxyReference(ctx context.Context, proxyI
 	filter := bson.M{"proxy_id": objectID}
 	update := bson.M{"$set": bson.M{"proxy_id": ""}}
 	_, err = r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	filter = bson.M{"failover_proxy_ids": proxyId}
+	update = bson.M{"$pull": bson.M{"failover_proxy_ids": proxyId}}
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// RemoveParentReference clears parent_id of all monitors depending on the given monitor
+func (r *MonitorRepositoryImpl) RemoveParentReference(ctx context.Context, parentID string) error {
+	filter := bson.M{"parent_id": parentID}
+	update := bson.M{"$set": bson.M{"parent_id": ""}}
+	_, err := r.collection.UpdateMany(ctx, filter, update)
 	return err
 }
 
 // FindByProxyId returns all monitors using the given proxyId
+// SetActive pauses or resumes a monitor, a nil resumeAt removes the scheduled resume
+func (r *MonitorRepositoryImpl) SetActive(ctx context.Context, id string, active bool, resumeAt *time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"active": active, "updated_at": time.Now().UTC()}}
+	if resumeAt != nil {
+		update["$set"].(bson.M)["resume_at"] = *resumeAt
+	} else {
+		update["$unset"] = bson.M{"resume_at": ""}
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+// FindDueForResume retrieves the paused monitors whose resume time has come
+func (r *MonitorRepositoryImpl) FindDueForResume(ctx context.Context, now time.Time) ([]*Model, error) {
+	filter := bson.M{"active": false, "resume_at": bson.M{"$lte": now}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var monitors []*Model
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		monitors = append(monitors, toDomainModel(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return monitors, nil
+}
+
 func (r *MonitorRepositoryImpl) FindByProxyId(ctx context.Context, proxyId string) ([]*Model, error) {
 	var monitors []*Model
 
@@ -524,7 +699,10 @@ func (r *MonitorRepositoryImpl) FindByProxyId(ctx context.Context, proxyId strin
 		return nil, err
 	}
 
-	filter := bson.M{"proxy_id": objectID}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"proxy_id": objectID},
+		bson.M{"failover_proxy_ids": proxyId},
+	}}
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
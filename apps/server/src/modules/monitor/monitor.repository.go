@@ -1,6 +1,9 @@
 package monitor
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type MonitorRepository interface {
 	Create(ctx context.Context, monitor *Model) (*Model, error)
@@ -22,4 +25,10 @@ type MonitorRepository interface {
 	RemoveProxyReference(ctx context.Context, proxyId string) error
 	FindByProxyId(ctx context.Context, proxyId string) ([]*Model, error)
 	FindOneByPushToken(ctx context.Context, pushToken string) (*Model, error)
+	// RemoveParentReference detaches the children of a deleted monitor
+	RemoveParentReference(ctx context.Context, parentID string) error
+	// SetActive pauses or resumes the monitor, resumeAt schedules the resume of a paused monitor
+	SetActive(ctx context.Context, id string, active bool, resumeAt *time.Time) error
+	// FindDueForResume returns the paused monitors whose resume time is not after now
+	FindDueForResume(ctx context.Context, now time.Time) ([]*Model, error)
 }
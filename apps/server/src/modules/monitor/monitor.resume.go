@@ -0,0 +1,24 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// autoResumeInterval is how late a paused monitor resumes at most
+const autoResumeInterval = 30 * time.Second
+
+// StartAutoResume resumes paused monitors once their resume_at has passed
+func StartAutoResume(service Service, logger *zap.SugaredLogger) {
+	ticker := time.NewTicker(autoResumeInterval)
+
+	go func() {
+		for now := range ticker.C {
+			if err := service.ResumeDue(context.Background(), now.UTC()); err != nil {
+				logger.Errorw("Failed to resume paused monitors", "error", err)
+			}
+		}
+	}()
+}
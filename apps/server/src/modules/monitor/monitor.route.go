@@ -30,13 +30,25 @@ func (uc *MonitorRoute) ConnectRoute(
 
 	router.GET("", uc.monitorController.FindAll)
 	router.GET("batch", uc.monitorController.FindByIDs)
+	router.GET("validate-configs", uc.monitorController.ValidateAllConfigs)
 	router.POST("", uc.monitorController.Create)
 	router.GET(":id", uc.monitorController.FindByID)
 	router.PUT(":id", uc.monitorController.UpdateFull)
 	router.PATCH(":id", uc.monitorController.UpdatePartial)
 	router.DELETE(":id", uc.monitorController.Delete)
 	router.POST(":id/reset", uc.monitorController.ResetMonitorData)
+	router.POST(":id/clone", uc.monitorController.Clone)
+	router.POST(":id/pause", uc.monitorController.Pause)
+	router.POST(":id/resume", uc.monitorController.Resume)
+	router.POST(":id/ack", uc.monitorController.Acknowledge)
+	router.PUT(":id/parent", uc.monitorController.SetParent)
+	router.DELETE(":id/parent", uc.monitorController.ClearParent)
+	router.GET(":id/config-versions", uc.monitorController.GetConfigVersions)
+	router.POST(":id/config-versions/:versionId/restore", uc.monitorController.RestoreConfigVersion)
 	router.GET(":id/heartbeats", uc.monitorController.FindByMonitorIDPaginated)
+	router.GET(":id/heartbeats/cursor", uc.monitorController.FindByMonitorIDAfterCursor)
+	router.GET(":id/heartbeats/:heartbeatId", uc.monitorController.FindHeartbeatByID)
 	router.GET(":id/stats/uptime", uc.monitorController.GetUptimeStats)
+	router.GET(":id/stats/latency", uc.monitorController.GetLatencyPercentiles)
 	router.GET(":id/stats/points", uc.monitorController.GetStatPoints)
 }
@@ -2,10 +2,13 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_ack"
+	"peekaping/src/modules/monitor_config_version"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/monitor_tag"
 	"peekaping/src/modules/shared"
@@ -25,19 +28,54 @@ type Service interface {
 	UpdatePartial(ctx context.Context, id string, monitor *PartialUpdateDto, noPublish bool) (*Model, error)
 	Delete(ctx context.Context, id string) error
 	ValidateMonitorConfig(monitorType string, configJSON string) error
+	ValidateAllConfigs(ctx context.Context, monitorType string) (*ConfigValidationReportDto, error)
 
 	GetHeartbeats(ctx context.Context, id string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error)
+	GetHeartbeatsAfterCursor(ctx context.Context, id string, limit int, cursor string, important *bool) (*heartbeat.CursorPage, error)
+	// GetHeartbeat returns a heartbeat of the monitor, nil when the monitor has no such heartbeat
+	GetHeartbeat(ctx context.Context, id string, heartbeatID string) (*heartbeat.Model, error)
 
 	RemoveProxyReference(ctx context.Context, proxyId string) error
 	FindByProxyId(ctx context.Context, proxyId string) ([]*Model, error)
 
 	GetStatPoints(ctx context.Context, id string, since, until time.Time, granularity string) (*StatPointsSummaryDto, error)
 	GetUptimeStats(ctx context.Context, id string) (*CustomUptimeStatsDto, error)
+	GetLatencyPercentiles(ctx context.Context, id string) (*LatencyPercentilesDto, error)
 
 	FindOneByPushToken(ctx context.Context, pushToken string) (*Model, error)
 	ResetMonitorData(ctx context.Context, id string) error
+
+	GetConfigVersions(ctx context.Context, id string) ([]*monitor_config_version.Model, error)
+	RestoreConfigVersion(ctx context.Context, id string, versionID string) (*Model, error)
+
+	// AcknowledgeAlert suppresses re-notifications of a down monitor for duration
+	AcknowledgeAlert(ctx context.Context, id string, acknowledgedBy string, duration time.Duration) (*monitor_ack.Model, error)
+
+	// SetParent makes the monitor depend on parentID, an empty parentID clears the parent
+	SetParent(ctx context.Context, id string, parentID string) (*Model, error)
+
+	// Pause stops the checks of the monitor, a non-nil resumeAt resumes it automatically
+	Pause(ctx context.Context, id string, resumeAt *time.Time) (*Model, error)
+	Resume(ctx context.Context, id string) (*Model, error)
+	// ResumeDue resumes the paused monitors whose resume time is not after now
+	ResumeDue(ctx context.Context, now time.Time) error
 }
 
+// ErrInvalidConfigVersion is returned when a stored config version no longer passes validation
+var ErrInvalidConfigVersion = errors.New("config version is not valid for this monitor")
+
+// ErrNoActiveAlert is returned when acknowledging a monitor that is not down
+var ErrNoActiveAlert = errors.New("monitor has no active alert")
+
+// ErrParentNotFound is returned when the parent of a monitor does not exist
+var ErrParentNotFound = errors.New("parent monitor not found")
+
+// ErrParentCycle is returned when a monitor would end up depending on itself
+var ErrParentCycle = errors.New("parent would create a dependency cycle")
+
+// ErrResumeAtInPast is returned when pausing a monitor until a time that has passed
+var ErrResumeAtInPast = errors.New("resume_at must be in the future")
+
 type StatPoint struct {
 	Up          int     `json:"up"`
 	Down        int     `json:"down"`
@@ -45,6 +83,7 @@ type StatPoint struct {
 	Ping        float64 `json:"ping"`
 	PingMin     float64 `json:"ping_min"`
 	PingMax     float64 `json:"ping_max"`
+	Duration    float64 `json:"duration"`
 	Timestamp   int64   `json:"timestamp"`
 }
 
@@ -56,6 +95,8 @@ type MonitorServiceImpl struct {
 	monitorTagService          monitor_tag.Service
 	executorRegistry           *executor.ExecutorRegistry
 	statPointsService          stats.Service
+	configVersionService       monitor_config_version.Service
+	ackService                 monitor_ack.Service
 	logger                     *zap.SugaredLogger
 }
 
@@ -67,6 +108,8 @@ func NewMonitorService(
 	monitorTagService monitor_tag.Service,
 	executorRegistry *executor.ExecutorRegistry,
 	statPointsService stats.Service,
+	configVersionService monitor_config_version.Service,
+	ackService monitor_ack.Service,
 	logger *zap.SugaredLogger,
 ) Service {
 	return &MonitorServiceImpl{
@@ -77,6 +120,8 @@ func NewMonitorService(
 		monitorTagService,
 		executorRegistry,
 		statPointsService,
+		configVersionService,
+		ackService,
 		logger.Named("[monitor-service]"),
 	}
 }
@@ -96,6 +141,17 @@ func (mr *MonitorServiceImpl) Create(ctx context.Context, monitorCreateDto *Crea
 		Config:         monitorCreateDto.Config,
 		ProxyId:        monitorCreateDto.ProxyId,
 		PushToken:      monitorCreateDto.PushToken,
+
+		IgnoreMaintenance:    monitorCreateDto.IgnoreMaintenance,
+		RetentionDays:        monitorCreateDto.RetentionDays,
+		SlowCheckThreshold:   monitorCreateDto.SlowCheckThreshold,
+		Notes:                monitorCreateDto.Notes,
+		RunbookURL:           monitorCreateDto.RunbookURL,
+		FailoverProxyIds:     monitorCreateDto.FailoverProxyIds,
+		LightProbeAfter:      monitorCreateDto.LightProbeAfter,
+		LightProbeInterval:   monitorCreateDto.LightProbeInterval,
+		StateWebhookURL:      monitorCreateDto.StateWebhookURL,
+		StateWebhookTemplate: monitorCreateDto.StateWebhookTemplate,
 	}
 
 	createdModel, err := mr.monitorRepository.Create(ctx, createModel)
@@ -103,6 +159,8 @@ func (mr *MonitorServiceImpl) Create(ctx context.Context, monitorCreateDto *Crea
 		return nil, err
 	}
 
+	mr.recordConfigVersion(ctx, createdModel)
+
 	// Emit monitor created event
 	mr.eventBus.Publish(events.Event{
 		Type:    events.MonitorCreated,
@@ -149,6 +207,17 @@ func (mr *MonitorServiceImpl) UpdateFull(ctx context.Context, id string, monitor
 		Config:         monitor.Config,
 		ProxyId:        monitor.ProxyId,
 		PushToken:      monitor.PushToken,
+
+		IgnoreMaintenance:    monitor.IgnoreMaintenance,
+		RetentionDays:        monitor.RetentionDays,
+		SlowCheckThreshold:   monitor.SlowCheckThreshold,
+		Notes:                monitor.Notes,
+		RunbookURL:           monitor.RunbookURL,
+		FailoverProxyIds:     monitor.FailoverProxyIds,
+		LightProbeAfter:      monitor.LightProbeAfter,
+		LightProbeInterval:   monitor.LightProbeInterval,
+		StateWebhookURL:      monitor.StateWebhookURL,
+		StateWebhookTemplate: monitor.StateWebhookTemplate,
 	}
 
 	err := mr.monitorRepository.UpdateFull(ctx, id, model)
@@ -156,6 +225,8 @@ func (mr *MonitorServiceImpl) UpdateFull(ctx context.Context, id string, monitor
 		return nil, err
 	}
 
+	mr.recordConfigVersion(ctx, model)
+
 	// Emit monitor updated event
 	mr.eventBus.Publish(events.Event{
 		Type:    events.MonitorUpdated,
@@ -177,6 +248,17 @@ func (mr *MonitorServiceImpl) UpdatePartial(ctx context.Context, id string, moni
 		ResendInterval: monitor.ResendInterval,
 		Active:         monitor.Active,
 		Status:         monitor.Status,
+
+		IgnoreMaintenance:    monitor.IgnoreMaintenance,
+		RetentionDays:        monitor.RetentionDays,
+		SlowCheckThreshold:   monitor.SlowCheckThreshold,
+		Notes:                monitor.Notes,
+		RunbookURL:           monitor.RunbookURL,
+		FailoverProxyIds:     monitor.FailoverProxyIds,
+		LightProbeAfter:      monitor.LightProbeAfter,
+		LightProbeInterval:   monitor.LightProbeInterval,
+		StateWebhookURL:      monitor.StateWebhookURL,
+		StateWebhookTemplate: monitor.StateWebhookTemplate,
 	}
 
 	err := mr.monitorRepository.UpdatePartial(ctx, id, model)
@@ -211,6 +293,9 @@ func (mr *MonitorServiceImpl) Delete(ctx context.Context, id string) error {
 	_ = mr.monitorTagService.DeleteByMonitorID(ctx, id)
 	_ = mr.heartbeatService.DeleteByMonitorID(ctx, id)
 	_ = mr.statPointsService.DeleteByMonitorID(ctx, id)
+	_ = mr.configVersionService.DeleteByMonitorID(ctx, id)
+	_ = mr.ackService.DeleteByMonitorID(ctx, id)
+	_ = mr.monitorRepository.RemoveParentReference(ctx, id)
 
 	// Emit monitor deleted event
 	mr.eventBus.Publish(events.Event{
@@ -231,10 +316,69 @@ func (mr *MonitorServiceImpl) ValidateMonitorConfig(
 	return mr.executorRegistry.ValidateConfig(monitorType, configJSON)
 }
 
+// validateAllConfigsBatchSize is the number of monitors loaded per page when validating all configs
+const validateAllConfigsBatchSize = 100
+
+// ValidateAllConfigs re-runs executor validation on every stored monitor config,
+// optionally restricted to a single monitor type, and reports the ones that fail
+func (mr *MonitorServiceImpl) ValidateAllConfigs(ctx context.Context, monitorType string) (*ConfigValidationReportDto, error) {
+	report := &ConfigValidationReportDto{
+		Failures: []*ConfigValidationFailureDto{},
+	}
+
+	for page := 0; ; page++ {
+		monitors, err := mr.monitorRepository.FindAll(ctx, page, validateAllConfigsBatchSize, "", nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range monitors {
+			if monitorType != "" && m.Type != monitorType {
+				continue
+			}
+
+			report.Checked++
+			if err := mr.ValidateMonitorConfig(m.Type, m.Config); err != nil {
+				report.Failures = append(report.Failures, &ConfigValidationFailureDto{
+					MonitorID: m.ID,
+					Name:      m.Name,
+					Type:      m.Type,
+					Error:     err.Error(),
+				})
+				continue
+			}
+			report.Valid++
+		}
+
+		if len(monitors) < validateAllConfigsBatchSize {
+			break
+		}
+	}
+
+	report.Invalid = len(report.Failures)
+	if report.Invalid > 0 {
+		mr.logger.Warnf("%d of %d monitor configs failed validation", report.Invalid, report.Checked)
+	}
+
+	return report, nil
+}
+
 func (mr *MonitorServiceImpl) GetHeartbeats(ctx context.Context, id string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
 	return mr.heartbeatService.FindByMonitorIDPaginated(ctx, id, limit, page, important, reverse)
 }
 
+func (mr *MonitorServiceImpl) GetHeartbeat(ctx context.Context, id string, heartbeatID string) (*heartbeat.Model, error) {
+	hb, err := mr.heartbeatService.FindByID(ctx, heartbeatID)
+	if err != nil || hb == nil || hb.MonitorID != id {
+		return nil, err
+	}
+	return hb, nil
+}
+
+func (mr *MonitorServiceImpl) GetHeartbeatsAfterCursor(ctx context.Context, id string, limit int, cursor string, important *bool) (*heartbeat.CursorPage, error) {
+	return mr.heartbeatService.FindByMonitorIDAfterCursor(ctx, id, limit, cursor, important)
+}
+
 func (mr *MonitorServiceImpl) RemoveProxyReference(ctx context.Context, proxyId string) error {
 	return mr.monitorRepository.RemoveProxyReference(ctx, proxyId)
 }
@@ -280,6 +424,7 @@ func (mr *MonitorServiceImpl) GetStatPoints(ctx context.Context, id string, sinc
 			Ping:        s.Ping,
 			PingMin:     s.PingMin,
 			PingMax:     s.PingMax,
+			Duration:    s.Duration,
 			Timestamp:   s.Timestamp.Unix() * 1000,
 		})
 	}
@@ -287,11 +432,12 @@ func (mr *MonitorServiceImpl) GetStatPoints(ctx context.Context, id string, sinc
 	stats := mr.statPointsService.StatPointsSummary(statsList)
 
 	return &StatPointsSummaryDto{
-		Points:  points,
-		MaxPing: stats.MaxPing,
-		MinPing: stats.MinPing,
-		AvgPing: stats.AvgPing,
-		Uptime:  stats.Uptime,
+		Points:      points,
+		MaxPing:     stats.MaxPing,
+		MinPing:     stats.MinPing,
+		AvgPing:     stats.AvgPing,
+		AvgDuration: stats.AvgDuration,
+		Uptime:      stats.Uptime,
 	}, nil
 }
 
@@ -330,6 +476,26 @@ func (mr *MonitorServiceImpl) GetUptimeStats(ctx context.Context, id string) (*C
 	return stats, nil
 }
 
+// GetLatencyPercentiles returns response time percentiles for 24h, 7d, 30d
+func (mr *MonitorServiceImpl) GetLatencyPercentiles(ctx context.Context, id string) (*LatencyPercentilesDto, error) {
+	periods := map[string]time.Duration{
+		"24h": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+
+	percentiles, err := mr.heartbeatService.FindLatencyPercentilesByMonitorID(ctx, id, periods, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return &LatencyPercentilesDto{
+		Latency24h: percentiles["24h"],
+		Latency7d:  percentiles["7d"],
+		Latency30d: percentiles["30d"],
+	}, nil
+}
+
 func (mr *MonitorServiceImpl) FindOneByPushToken(ctx context.Context, pushToken string) (*Model, error) {
 	return mr.monitorRepository.FindOneByPushToken(ctx, pushToken)
 }
@@ -384,3 +550,194 @@ func (mr *MonitorServiceImpl) ResetMonitorData(ctx context.Context, id string) e
 
 	return nil
 }
+
+// recordConfigVersion stores the monitor config in the version history, failures only get logged
+func (mr *MonitorServiceImpl) recordConfigVersion(ctx context.Context, monitor *Model) {
+	if _, err := mr.configVersionService.Record(ctx, monitor.ID, monitor.Type, monitor.Config); err != nil {
+		mr.logger.Warnw("Failed to record monitor config version", "monitorID", monitor.ID, "error", err)
+	}
+}
+
+func (mr *MonitorServiceImpl) GetConfigVersions(ctx context.Context, id string) ([]*monitor_config_version.Model, error) {
+	monitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor not found")
+	}
+
+	return mr.configVersionService.FindByMonitorID(ctx, id)
+}
+
+// RestoreConfigVersion re-applies a previously stored config after validating it
+// against the current executor, and reschedules the monitor
+func (mr *MonitorServiceImpl) RestoreConfigVersion(ctx context.Context, id string, versionID string) (*Model, error) {
+	monitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor not found")
+	}
+
+	version, err := mr.configVersionService.FindByID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil || version.MonitorID != id {
+		return nil, fmt.Errorf("config version not found")
+	}
+
+	if version.Type != monitor.Type {
+		return nil, fmt.Errorf("%w: version was recorded for monitor type %s, monitor is %s", ErrInvalidConfigVersion, version.Type, monitor.Type)
+	}
+	if err := mr.ValidateMonitorConfig(monitor.Type, version.Config); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfigVersion, err.Error())
+	}
+
+	now := time.Now().UTC()
+	err = mr.monitorRepository.UpdatePartial(ctx, id, &UpdateModel{
+		ID:        &id,
+		Config:    &version.Config,
+		UpdatedAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedMonitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mr.recordConfigVersion(ctx, updatedMonitor)
+
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MonitorUpdated,
+		Payload: updatedMonitor,
+	})
+
+	return updatedMonitor, nil
+}
+
+func (mr *MonitorServiceImpl) AcknowledgeAlert(ctx context.Context, id string, acknowledgedBy string, duration time.Duration) (*monitor_ack.Model, error) {
+	monitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor not found")
+	}
+	if monitor.Status != shared.MonitorStatusDown {
+		return nil, ErrNoActiveAlert
+	}
+
+	return mr.ackService.Acknowledge(ctx, id, acknowledgedBy, duration)
+}
+
+func (mr *MonitorServiceImpl) SetParent(ctx context.Context, id string, parentID string) (*Model, error) {
+	monitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor not found")
+	}
+
+	// walk up from the new parent, reaching the monitor means it would depend on itself
+	visited := map[string]bool{}
+	for ancestorID := parentID; ancestorID != "" && !visited[ancestorID]; {
+		if ancestorID == id {
+			return nil, ErrParentCycle
+		}
+		visited[ancestorID] = true
+		ancestor, err := mr.monitorRepository.FindByID(ctx, ancestorID)
+		if err != nil {
+			return nil, err
+		}
+		if ancestor == nil {
+			if ancestorID == parentID {
+				return nil, ErrParentNotFound
+			}
+			break
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	if err := mr.monitorRepository.UpdatePartial(ctx, id, &UpdateModel{ParentID: &parentID}); err != nil {
+		return nil, err
+	}
+
+	updated, err := mr.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the supervisor reloads the monitor with its new parent
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MonitorUpdated,
+		Payload: updated,
+	})
+
+	return updated, nil
+}
+
+func (mr *MonitorServiceImpl) Pause(ctx context.Context, id string, resumeAt *time.Time) (*Model, error) {
+	if resumeAt != nil && !resumeAt.After(time.Now()) {
+		return nil, ErrResumeAtInPast
+	}
+	return mr.setActive(ctx, id, false, resumeAt)
+}
+
+func (mr *MonitorServiceImpl) Resume(ctx context.Context, id string) (*Model, error) {
+	return mr.setActive(ctx, id, true, nil)
+}
+
+func (mr *MonitorServiceImpl) ResumeDue(ctx context.Context, now time.Time) error {
+	monitors, err := mr.monitorRepository.FindDueForResume(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, monitor := range monitors {
+		if _, err := mr.Resume(ctx, monitor.ID); err != nil {
+			mr.logger.Errorw("Failed to resume monitor", "monitorID", monitor.ID, "error", err)
+			continue
+		}
+		mr.logger.Infow("Resumed monitor", "monitorID", monitor.ID, "resumeAt", monitor.ResumeAt)
+	}
+	return nil
+}
+
+func (mr *MonitorServiceImpl) setActive(ctx context.Context, id string, active bool, resumeAt *time.Time) (*Model, error) {
+	monitor, err := mr.monitorRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor not found")
+	}
+
+	var utcResumeAt *time.Time
+	if resumeAt != nil {
+		t := resumeAt.UTC()
+		utcResumeAt = &t
+	}
+	if err := mr.monitorRepository.SetActive(ctx, id, active, utcResumeAt); err != nil {
+		return nil, err
+	}
+
+	updated, err := mr.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the supervisor starts or stops the checks of the monitor
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MonitorUpdated,
+		Payload: updated,
+	})
+
+	return updated, nil
+}
@@ -0,0 +1,423 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_config_version"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+// fakeMonitorRepository serves a fixed list of monitors through FindAll pagination
+type fakeMonitorRepository struct {
+	MonitorRepository
+	monitors []*Model
+	pages    int
+}
+
+func (f *fakeMonitorRepository) FindAll(ctx context.Context, page int, limit int, q string, active *bool, status *int, tagIds []string) ([]*Model, error) {
+	f.pages++
+	start := page * limit
+	if start >= len(f.monitors) {
+		return nil, nil
+	}
+	end := start + limit
+	if end > len(f.monitors) {
+		end = len(f.monitors)
+	}
+	return f.monitors[start:end], nil
+}
+
+func (f *fakeMonitorRepository) Create(ctx context.Context, monitor *Model) (*Model, error) {
+	created := *monitor
+	created.ID = fmt.Sprintf("monitor%d", len(f.monitors)+1)
+	f.monitors = append(f.monitors, &created)
+	return &created, nil
+}
+
+func (f *fakeMonitorRepository) FindByID(ctx context.Context, id string) (*Model, error) {
+	for _, m := range f.monitors {
+		if m.ID == id {
+			found := *m
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeMonitorRepository) UpdateFull(ctx context.Context, id string, monitor *Model) error {
+	for i, m := range f.monitors {
+		if m.ID == id {
+			updated := *monitor
+			f.monitors[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (f *fakeMonitorRepository) UpdatePartial(ctx context.Context, id string, monitor *UpdateModel) error {
+	for _, m := range f.monitors {
+		if m.ID != id {
+			continue
+		}
+		if monitor.Config != nil {
+			m.Config = *monitor.Config
+		}
+		if monitor.ParentID != nil {
+			m.ParentID = *monitor.ParentID
+		}
+	}
+	return nil
+}
+
+// fakeConfigVersionService keeps recorded versions in memory, newest first
+type fakeConfigVersionService struct {
+	monitor_config_version.Service
+	versions []*monitor_config_version.Model
+}
+
+func (f *fakeConfigVersionService) Record(ctx context.Context, monitorID string, monitorType string, config string) (*monitor_config_version.Model, error) {
+	v := &monitor_config_version.Model{
+		ID:        fmt.Sprintf("version%d", len(f.versions)+1),
+		MonitorID: monitorID,
+		Type:      monitorType,
+		Config:    config,
+	}
+	f.versions = append([]*monitor_config_version.Model{v}, f.versions...)
+	return v, nil
+}
+
+func (f *fakeConfigVersionService) FindByID(ctx context.Context, id string) (*monitor_config_version.Model, error) {
+	for _, v := range f.versions {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeConfigVersionService) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_config_version.Model, error) {
+	var result []*monitor_config_version.Model
+	for _, v := range f.versions {
+		if v.MonitorID == monitorID {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func newValidationTestService(repo MonitorRepository) *MonitorServiceImpl {
+	logger := zap.NewNop().Sugar()
+	return &MonitorServiceImpl{
+		monitorRepository: repo,
+		executorRegistry:  executor.NewExecutorRegistry(logger, nil),
+		logger:            logger,
+	}
+}
+
+func TestMonitorService_ValidateAllConfigs(t *testing.T) {
+	validHTTP := `{"url":"https://example.com","method":"GET","encoding":"json","accepted_statuscodes":["2XX"],"authMethod":"none"}`
+	validTCP := `{"host":"example.com","port":443}`
+
+	repo := &fakeMonitorRepository{
+		monitors: []*Model{
+			{ID: "1", Name: "valid http", Type: "http", Config: validHTTP},
+			{ID: "2", Name: "http missing url", Type: "http", Config: `{"method":"GET","encoding":"json","accepted_statuscodes":["2XX"],"authMethod":"none"}`},
+			{ID: "3", Name: "valid tcp", Type: "tcp", Config: validTCP},
+			{ID: "4", Name: "tcp unknown field", Type: "tcp", Config: `{"host":"example.com","port":443,"legacy":true}`},
+			{ID: "5", Name: "removed type", Type: "legacy-type", Config: `{}`},
+			{ID: "6", Name: "broken json", Type: "http", Config: `{"url":`},
+		},
+	}
+	service := newValidationTestService(repo)
+
+	t.Run("reports every invalid config", func(t *testing.T) {
+		report, err := service.ValidateAllConfigs(context.Background(), "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 6, report.Checked)
+		assert.Equal(t, 2, report.Valid)
+		assert.Equal(t, 4, report.Invalid)
+
+		failed := make(map[string]string)
+		for _, f := range report.Failures {
+			failed[f.MonitorID] = f.Error
+		}
+		assert.Len(t, failed, 4)
+		assert.Contains(t, failed, "2")
+		assert.Contains(t, failed, "4")
+		assert.Contains(t, failed["5"], "executor not found")
+		assert.Contains(t, failed, "6")
+	})
+
+	t.Run("filters by monitor type", func(t *testing.T) {
+		report, err := service.ValidateAllConfigs(context.Background(), "tcp")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, report.Checked)
+		assert.Equal(t, 1, report.Valid)
+		require.Len(t, report.Failures, 1)
+		assert.Equal(t, "4", report.Failures[0].MonitorID)
+		assert.Equal(t, "tcp unknown field", report.Failures[0].Name)
+	})
+
+	t.Run("walks all pages", func(t *testing.T) {
+		many := &fakeMonitorRepository{}
+		for i := 0; i < validateAllConfigsBatchSize+5; i++ {
+			many.monitors = append(many.monitors, &Model{ID: "m", Type: "tcp", Config: validTCP})
+		}
+
+		report, err := newValidationTestService(many).ValidateAllConfigs(context.Background(), "")
+		require.NoError(t, err)
+
+		assert.Equal(t, validateAllConfigsBatchSize+5, report.Checked)
+		assert.Equal(t, 0, report.Invalid)
+		assert.Empty(t, report.Failures)
+		assert.Equal(t, 2, many.pages)
+	})
+}
+
+func TestMonitorService_ConfigVersions(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+	bus := events.NewEventBus(logger)
+	versions := &fakeConfigVersionService{}
+	repo := &fakeMonitorRepository{}
+
+	service := &MonitorServiceImpl{
+		monitorRepository:    repo,
+		eventBus:             bus,
+		executorRegistry:     executor.NewExecutorRegistry(logger, nil),
+		configVersionService: versions,
+		logger:               logger,
+	}
+
+	updated := make(chan *Model, 10)
+	bus.Subscribe(events.MonitorUpdated, func(event events.Event) {
+		updated <- event.Payload.(*Model)
+	})
+
+	goodConfig := `{"host":"good.example.com","port":443}`
+	badConfig := `{"host":"typo.example.com","port":444}`
+
+	created, err := service.Create(ctx, &CreateUpdateDto{Name: "api", Type: "tcp", Interval: 60, Timeout: 5, Active: true, Config: goodConfig})
+	require.NoError(t, err)
+
+	// Editing the monitor stores a new version
+	_, err = service.UpdateFull(ctx, created.ID, &CreateUpdateDto{Name: "api", Type: "tcp", Interval: 60, Timeout: 5, Active: true, Config: badConfig})
+	require.NoError(t, err)
+	<-updated
+
+	history, err := service.GetConfigVersions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, badConfig, history[0].Config)
+	assert.Equal(t, goodConfig, history[1].Config)
+
+	// Restoring the previous version reverts the config and reschedules the monitor
+	restored, err := service.RestoreConfigVersion(ctx, created.ID, history[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, goodConfig, restored.Config)
+
+	stored, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, goodConfig, stored.Config)
+
+	select {
+	case m := <-updated:
+		assert.Equal(t, created.ID, m.ID)
+		assert.Equal(t, goodConfig, m.Config)
+	case <-time.After(time.Second):
+		t.Fatal("expected a monitor updated event after restoring")
+	}
+
+	history, err = service.GetConfigVersions(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, goodConfig, history[0].Config)
+}
+
+func TestMonitorService_RestoreConfigVersion_Rejects(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+	versions := &fakeConfigVersionService{}
+	repo := &fakeMonitorRepository{monitors: []*Model{
+		{ID: "monitor1", Type: "tcp", Config: `{"host":"example.com","port":443}`},
+		{ID: "monitor2", Type: "tcp", Config: `{"host":"example.com","port":443}`},
+	}}
+	service := &MonitorServiceImpl{
+		monitorRepository:    repo,
+		eventBus:             events.NewEventBus(logger),
+		executorRegistry:     executor.NewExecutorRegistry(logger, nil),
+		configVersionService: versions,
+		logger:               logger,
+	}
+
+	invalid, _ := versions.Record(ctx, "monitor1", "tcp", `{"host":"example.com","port":443,"removed_option":true}`)
+	otherType, _ := versions.Record(ctx, "monitor1", "http", `{"url":"https://example.com"}`)
+	otherMonitor, _ := versions.Record(ctx, "monitor2", "tcp", `{"host":"other.example.com","port":443}`)
+
+	_, err := service.RestoreConfigVersion(ctx, "monitor1", invalid.ID)
+	assert.ErrorIs(t, err, ErrInvalidConfigVersion)
+
+	_, err = service.RestoreConfigVersion(ctx, "monitor1", otherType.ID)
+	assert.ErrorIs(t, err, ErrInvalidConfigVersion)
+
+	_, err = service.RestoreConfigVersion(ctx, "monitor1", otherMonitor.ID)
+	assert.EqualError(t, err, "config version not found")
+
+	_, err = service.RestoreConfigVersion(ctx, "missing", invalid.ID)
+	assert.EqualError(t, err, "monitor not found")
+
+	// Nothing was applied
+	stored, _ := repo.FindByID(ctx, "monitor1")
+	assert.Equal(t, `{"host":"example.com","port":443}`, stored.Config)
+}
+
+func TestMonitorService_SetParent(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+	// router <- gateway <- api
+	repo := &fakeMonitorRepository{monitors: []*Model{
+		{ID: "router"},
+		{ID: "gateway", ParentID: "router"},
+		{ID: "api", ParentID: "gateway"},
+		{ID: "db"},
+	}}
+	service := &MonitorServiceImpl{
+		monitorRepository: repo,
+		eventBus:          events.NewEventBus(logger),
+		logger:            logger,
+	}
+
+	updated, err := service.SetParent(ctx, "db", "gateway")
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", updated.ParentID)
+
+	_, err = service.SetParent(ctx, "router", "api")
+	assert.ErrorIs(t, err, ErrParentCycle)
+
+	_, err = service.SetParent(ctx, "gateway", "gateway")
+	assert.ErrorIs(t, err, ErrParentCycle)
+
+	_, err = service.SetParent(ctx, "api", "missing")
+	assert.ErrorIs(t, err, ErrParentNotFound)
+
+	_, err = service.SetParent(ctx, "missing", "router")
+	assert.EqualError(t, err, "monitor not found")
+
+	// rejected changes kept the tree
+	stored, _ := repo.FindByID(ctx, "router")
+	assert.Empty(t, stored.ParentID)
+
+	cleared, err := service.SetParent(ctx, "api", "")
+	require.NoError(t, err)
+	assert.Empty(t, cleared.ParentID)
+}
+
+func TestMonitorService_PauseAndResumeDue(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(ctx)
+	require.NoError(t, err)
+
+	repo := NewSQLRepository(db)
+	service := &MonitorServiceImpl{monitorRepository: repo, eventBus: events.NewEventBus(logger), logger: logger}
+
+	create := func(name string) *Model {
+		m, err := repo.Create(ctx, &Model{Name: name, Type: "http", Active: true})
+		require.NoError(t, err)
+		return m
+	}
+	scheduled, indefinite, resumedByHand := create("scheduled"), create("indefinite"), create("by hand")
+
+	now := time.Now().UTC()
+	resumeAt := now.Add(time.Hour)
+	paused, err := service.Pause(ctx, scheduled.ID, &resumeAt)
+	require.NoError(t, err)
+	assert.False(t, paused.Active)
+	require.NotNil(t, paused.ResumeAt)
+	assert.WithinDuration(t, resumeAt, *paused.ResumeAt, time.Second)
+
+	_, err = service.Pause(ctx, indefinite.ID, nil)
+	require.NoError(t, err)
+
+	_, err = service.Pause(ctx, resumedByHand.ID, &resumeAt)
+	require.NoError(t, err)
+	active := true
+	_, err = service.UpdatePartial(ctx, resumedByHand.ID, &PartialUpdateDto{Active: &active}, true)
+	require.NoError(t, err)
+	inactive := false
+	_, err = service.UpdatePartial(ctx, resumedByHand.ID, &PartialUpdateDto{Active: &inactive}, true)
+	require.NoError(t, err)
+
+	past := now.Add(-time.Minute)
+	_, err = service.Pause(ctx, scheduled.ID, &past)
+	assert.ErrorIs(t, err, ErrResumeAtInPast)
+	_, err = service.Pause(ctx, "missing", nil)
+	assert.EqualError(t, err, "monitor not found")
+
+	// nothing due yet
+	require.NoError(t, service.ResumeDue(ctx, now))
+	stored, _ := repo.FindByID(ctx, scheduled.ID)
+	assert.False(t, stored.Active)
+
+	require.NoError(t, service.ResumeDue(ctx, now.Add(2*time.Hour)))
+	stored, _ = repo.FindByID(ctx, scheduled.ID)
+	assert.True(t, stored.Active)
+	assert.Nil(t, stored.ResumeAt)
+
+	// only scheduled resumes are picked up
+	for _, id := range []string{indefinite.ID, resumedByHand.ID} {
+		stored, _ := repo.FindByID(ctx, id)
+		assert.False(t, stored.Active, stored.Name)
+		assert.Nil(t, stored.ResumeAt, stored.Name)
+	}
+}
+
+type fakeHeartbeatService struct {
+	heartbeat.Service
+	heartbeats map[string]*heartbeat.Model
+}
+
+func (f *fakeHeartbeatService) FindByID(ctx context.Context, id string) (*heartbeat.Model, error) {
+	return f.heartbeats[id], nil
+}
+
+func TestMonitorService_GetHeartbeat(t *testing.T) {
+	service := &MonitorServiceImpl{
+		heartbeatService: &fakeHeartbeatService{heartbeats: map[string]*heartbeat.Model{
+			"hb1": {ID: "hb1", MonitorID: "m1", Headers: map[string]string{"Server": "nginx"}},
+		}},
+		logger: zap.NewNop().Sugar(),
+	}
+	ctx := context.Background()
+
+	hb, err := service.GetHeartbeat(ctx, "m1", "hb1")
+	require.NoError(t, err)
+	require.NotNil(t, hb)
+	assert.Equal(t, "nginx", hb.Headers["Server"])
+
+	hb, err = service.GetHeartbeat(ctx, "m2", "hb1")
+	require.NoError(t, err)
+	assert.Nil(t, hb, "expected the heartbeat of another monitor to be hidden")
+
+	hb, err = service.GetHeartbeat(ctx, "m1", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, hb)
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"peekaping/src/modules/shared"
@@ -28,6 +29,19 @@ type sqlModel struct {
 	Config         string               `bun:"config"`
 	ProxyId        *string              `bun:"proxy_id"`
 	PushToken      string               `bun:"push_token"`
+
+	IgnoreMaintenance    bool       `bun:"ignore_maintenance,notnull,default:false"`
+	RetentionDays        int        `bun:"retention_days,notnull,default:0"`
+	SlowCheckThreshold   int        `bun:"slow_check_threshold,notnull,default:0"`
+	Notes                string     `bun:"notes,notnull,default:''"`
+	RunbookURL           string     `bun:"runbook_url,notnull,default:''"`
+	FailoverProxyIds     []string   `bun:"failover_proxy_ids"`
+	LightProbeAfter      int        `bun:"light_probe_after,notnull,default:0"`
+	LightProbeInterval   int        `bun:"light_probe_interval,notnull,default:0"`
+	StateWebhookURL      string     `bun:"state_webhook_url,notnull,default:''"`
+	StateWebhookTemplate string     `bun:"state_webhook_template,notnull,default:''"`
+	ParentID             string     `bun:"parent_id,notnull,default:''"`
+	ResumeAt             *time.Time `bun:"resume_at"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -53,6 +67,19 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		Config:         sm.Config,
 		ProxyId:        proxyId,
 		PushToken:      sm.PushToken,
+
+		IgnoreMaintenance:    sm.IgnoreMaintenance,
+		RetentionDays:        sm.RetentionDays,
+		SlowCheckThreshold:   sm.SlowCheckThreshold,
+		Notes:                sm.Notes,
+		RunbookURL:           sm.RunbookURL,
+		FailoverProxyIds:     sm.FailoverProxyIds,
+		LightProbeAfter:      sm.LightProbeAfter,
+		LightProbeInterval:   sm.LightProbeInterval,
+		StateWebhookURL:      sm.StateWebhookURL,
+		StateWebhookTemplate: sm.StateWebhookTemplate,
+		ParentID:             sm.ParentID,
+		ResumeAt:             sm.ResumeAt,
 	}
 }
 
@@ -79,6 +106,19 @@ func toSQLModel(m *Model) *sqlModel {
 		Config:         m.Config,
 		ProxyId:        proxyId,
 		PushToken:      m.PushToken,
+
+		IgnoreMaintenance:    m.IgnoreMaintenance,
+		RetentionDays:        m.RetentionDays,
+		SlowCheckThreshold:   m.SlowCheckThreshold,
+		Notes:                m.Notes,
+		RunbookURL:           m.RunbookURL,
+		FailoverProxyIds:     m.FailoverProxyIds,
+		LightProbeAfter:      m.LightProbeAfter,
+		LightProbeInterval:   m.LightProbeInterval,
+		StateWebhookURL:      m.StateWebhookURL,
+		StateWebhookTemplate: m.StateWebhookTemplate,
+		ParentID:             m.ParentID,
+		ResumeAt:             m.ResumeAt,
 	}
 }
 
@@ -212,7 +252,9 @@ func (r *SQLRepositoryImpl) UpdateFull(ctx context.Context, id string, monitor *
 	_, err := r.db.NewUpdate().
 		Model(sm).
 		Where("id = ?", id).
-		ExcludeColumn("id", "created_at").
+		// the parent is only changed through UpdatePartial, resume_at is
+		// written as NULL so a full update cancels a scheduled resume
+		ExcludeColumn("id", "created_at", "parent_id").
 		Exec(ctx)
 	return err
 }
@@ -252,6 +294,8 @@ func (r *SQLRepositoryImpl) UpdatePartial(ctx context.Context, id string, monito
 	}
 	if monitor.Active != nil {
 		query = query.Set("active = ?", *monitor.Active)
+		// pausing or resuming by hand cancels a scheduled resume
+		query = query.Set("resume_at = NULL")
 		hasUpdates = true
 	}
 	if monitor.Status != nil {
@@ -275,6 +319,55 @@ func (r *SQLRepositoryImpl) UpdatePartial(ctx context.Context, id string, monito
 		query = query.Set("push_token = ?", *monitor.PushToken)
 		hasUpdates = true
 	}
+	if monitor.IgnoreMaintenance != nil {
+		query = query.Set("ignore_maintenance = ?", *monitor.IgnoreMaintenance)
+		hasUpdates = true
+	}
+	if monitor.RetentionDays != nil {
+		query = query.Set("retention_days = ?", *monitor.RetentionDays)
+		hasUpdates = true
+	}
+	if monitor.SlowCheckThreshold != nil {
+		query = query.Set("slow_check_threshold = ?", *monitor.SlowCheckThreshold)
+		hasUpdates = true
+	}
+	if monitor.Notes != nil {
+		query = query.Set("notes = ?", *monitor.Notes)
+		hasUpdates = true
+	}
+	if monitor.RunbookURL != nil {
+		query = query.Set("runbook_url = ?", *monitor.RunbookURL)
+		hasUpdates = true
+	}
+	if monitor.FailoverProxyIds != nil {
+		// the column holds JSON, as bun writes it from the model
+		failoverProxyIds, err := json.Marshal(*monitor.FailoverProxyIds)
+		if err != nil {
+			return err
+		}
+		query = query.Set("failover_proxy_ids = ?", string(failoverProxyIds))
+		hasUpdates = true
+	}
+	if monitor.LightProbeAfter != nil {
+		query = query.Set("light_probe_after = ?", *monitor.LightProbeAfter)
+		hasUpdates = true
+	}
+	if monitor.LightProbeInterval != nil {
+		query = query.Set("light_probe_interval = ?", *monitor.LightProbeInterval)
+		hasUpdates = true
+	}
+	if monitor.StateWebhookURL != nil {
+		query = query.Set("state_webhook_url = ?", *monitor.StateWebhookURL)
+		hasUpdates = true
+	}
+	if monitor.StateWebhookTemplate != nil {
+		query = query.Set("state_webhook_template = ?", *monitor.StateWebhookTemplate)
+		hasUpdates = true
+	}
+	if monitor.ParentID != nil {
+		query = query.Set("parent_id = ?", *monitor.ParentID)
+		hasUpdates = true
+	}
 
 	if !hasUpdates {
 		return nil
@@ -298,14 +391,88 @@ func (r *SQLRepositoryImpl) RemoveProxyReference(ctx context.Context, proxyId st
 		Set("proxy_id = ?", nil).
 		Where("proxy_id = ?", proxyId).
 		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sms []*sqlModel
+	err = r.db.NewSelect().
+		Model(&sms).
+		Where("failover_proxy_ids LIKE ?", failoverProxyPattern(proxyId)).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sm := range sms {
+		remaining := []string{}
+		for _, id := range sm.FailoverProxyIds {
+			if id != proxyId {
+				remaining = append(remaining, id)
+			}
+		}
+		sm.FailoverProxyIds = remaining
+		_, err := r.db.NewUpdate().
+			Model(sm).
+			Column("failover_proxy_ids").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failoverProxyPattern matches the JSON list of failover proxies holding the proxy
+func failoverProxyPattern(proxyId string) string {
+	return `%"` + proxyId + `"%`
+}
+
+func (r *SQLRepositoryImpl) RemoveParentReference(ctx context.Context, parentID string) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("parent_id = ?", "").
+		Where("parent_id = ?", parentID).
+		Exec(ctx)
 	return err
 }
 
+func (r *SQLRepositoryImpl) SetActive(ctx context.Context, id string, active bool, resumeAt *time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("active = ?", active).
+		Set("resume_at = ?", resumeAt).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) FindDueForResume(ctx context.Context, now time.Time) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("active = ?", false).
+		Where("resume_at IS NOT NULL").
+		Where("resume_at <= ?", now).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
 func (r *SQLRepositoryImpl) FindByProxyId(ctx context.Context, proxyId string) ([]*Model, error) {
 	var sms []*sqlModel
 	err := r.db.NewSelect().
 		Model(&sms).
 		Where("proxy_id = ?", proxyId).
+		WhereOr("failover_proxy_ids LIKE ?", failoverProxyPattern(proxyId)).
 		Scan(ctx)
 	if err != nil {
 		return nil, err
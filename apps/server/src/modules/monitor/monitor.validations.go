@@ -1,7 +1,10 @@
 package monitor
 
 import (
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
+	liquid "github.com/osteele/liquid"
 )
 
 func CreateUpdateDtoStructLevelValidation(sl validator.StructLevel) {
@@ -11,3 +14,25 @@ func CreateUpdateDtoStructLevelValidation(sl validator.StructLevel) {
 		sl.ReportError(cfg.Timeout, "Timeout", "timeout", "timeout", "")
 	}
 }
+
+// ParseStateWebhookTemplate parses the liquid template of a state-change
+// webhook body, with the engine of the webhook notification channel
+func ParseStateWebhookTemplate(template string) (*liquid.Template, error) {
+	tpl, err := liquid.NewEngine().ParseString(template)
+	if err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// ValidateStateWebhookTemplate parses the body template of the state-change
+// webhook, an empty template sends the default payload
+func ValidateStateWebhookTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if _, err := ParseStateWebhookTemplate(template); err != nil {
+		return fmt.Errorf("state_webhook_template is not a valid template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package monitor_ack
+
+import "time"
+
+// Model is an acknowledgment of an active alert, it suppresses re-notifications
+// of the monitor until it expires or the monitor recovers
+type Model struct {
+	ID             string     `json:"id"`
+	MonitorID      string     `json:"monitor_id"`
+	AcknowledgedBy string     `json:"acknowledged_by"`
+	AcknowledgedAt time.Time  `json:"acknowledged_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	ClearedAt      *time.Time `json:"cleared_at"`
+}
+
+// Active reports whether the acknowledgment still suppresses notifications at now
+func (m *Model) Active(now time.Time) bool {
+	return m.ClearedAt == nil && now.Before(m.ExpiresAt)
+}
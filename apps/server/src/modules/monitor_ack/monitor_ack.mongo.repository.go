@@ -0,0 +1,123 @@
+package monitor_ack
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	MonitorID      primitive.ObjectID `bson:"monitor_id"`
+	AcknowledgedBy string             `bson:"acknowledged_by"`
+	AcknowledgedAt time.Time          `bson:"acknowledged_at"`
+	ExpiresAt      time.Time          `bson:"expires_at"`
+	ClearedAt      *time.Time         `bson:"cleared_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:             mm.ID.Hex(),
+		MonitorID:      mm.MonitorID.Hex(),
+		AcknowledgedBy: mm.AcknowledgedBy,
+		AcknowledgedAt: mm.AcknowledgedAt,
+		ExpiresAt:      mm.ExpiresAt,
+		ClearedAt:      mm.ClearedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("monitor_acks")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "monitor_id", Value: 1},
+			{Key: "expires_at", Value: -1},
+		},
+	})
+	if err != nil {
+		panic("Failed to create index for monitor_acks: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(model.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := &mongoModel{
+		ID:             primitive.NewObjectID(),
+		MonitorID:      monitorObjectID,
+		AcknowledgedBy: model.AcknowledgedBy,
+		AcknowledgedAt: model.AcknowledgedAt,
+		ExpiresAt:      model.ExpiresAt,
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mm); err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindActive(ctx context.Context, monitorID string, now time.Time) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"monitor_id": monitorObjectID,
+		"cleared_at": nil,
+		"expires_at": bson.M{"$gt": now},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "acknowledged_at", Value: -1}})
+
+	var entity mongoModel
+	err = r.collection.FindOne(ctx, filter, opts).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&entity), nil
+}
+
+func (r *MongoRepositoryImpl) Clear(ctx context.Context, monitorID string, at time.Time) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"monitor_id": monitorObjectID, "cleared_at": nil},
+		bson.M{"$set": bson.M{"cleared_at": at}},
+	)
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteMany(ctx, bson.M{"monitor_id": monitorObjectID})
+	return err
+}
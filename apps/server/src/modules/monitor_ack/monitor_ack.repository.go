@@ -0,0 +1,16 @@
+package monitor_ack
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	// FindActive returns the newest acknowledgment of the monitor that is not
+	// cleared and expires after now
+	FindActive(ctx context.Context, monitorID string, now time.Time) (*Model, error)
+	// Clear marks every open acknowledgment of the monitor as cleared at
+	Clear(ctx context.Context, monitorID string, at time.Time) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
@@ -0,0 +1,67 @@
+package monitor_ack
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Acknowledge suppresses notifications of the monitor for duration
+	Acknowledge(ctx context.Context, monitorID string, acknowledgedBy string, duration time.Duration) (*Model, error)
+	// FindActive returns the acknowledgment currently suppressing notifications of the monitor, if any
+	FindActive(ctx context.Context, monitorID string) (*Model, error)
+	// Clear ends the acknowledgments of the monitor, used once it recovers
+	Clear(ctx context.Context, monitorID string) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-ack-service]"),
+	}
+}
+
+func (s *ServiceImpl) Acknowledge(ctx context.Context, monitorID string, acknowledgedBy string, duration time.Duration) (*Model, error) {
+	now := time.Now().UTC()
+
+	// a new acknowledgment replaces the previous one instead of extending it
+	if err := s.repository.Clear(ctx, monitorID, now); err != nil {
+		return nil, err
+	}
+
+	ack, err := s.repository.Create(ctx, &Model{
+		MonitorID:      monitorID,
+		AcknowledgedBy: acknowledgedBy,
+		AcknowledgedAt: now,
+		ExpiresAt:      now.Add(duration),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infow("Alert acknowledged", "monitorID", monitorID, "by", acknowledgedBy, "expiresAt", ack.ExpiresAt)
+	return ack, nil
+}
+
+func (s *ServiceImpl) FindActive(ctx context.Context, monitorID string) (*Model, error) {
+	return s.repository.FindActive(ctx, monitorID, time.Now().UTC())
+}
+
+func (s *ServiceImpl) Clear(ctx context.Context, monitorID string) error {
+	return s.repository.Clear(ctx, monitorID, time.Now().UTC())
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
@@ -0,0 +1,100 @@
+package monitor_ack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memoryRepository keeps acknowledgments in memory
+type memoryRepository struct {
+	acks []*Model
+}
+
+func (r *memoryRepository) Create(ctx context.Context, model *Model) (*Model, error) {
+	stored := *model
+	stored.ID = fmt.Sprintf("ack-%d", len(r.acks))
+	r.acks = append(r.acks, &stored)
+	return &stored, nil
+}
+
+func (r *memoryRepository) FindActive(ctx context.Context, monitorID string, now time.Time) (*Model, error) {
+	for i := len(r.acks) - 1; i >= 0; i-- {
+		if r.acks[i].MonitorID == monitorID && r.acks[i].Active(now) {
+			return r.acks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) Clear(ctx context.Context, monitorID string, at time.Time) error {
+	for _, ack := range r.acks {
+		if ack.MonitorID == monitorID && ack.ClearedAt == nil {
+			cleared := at
+			ack.ClearedAt = &cleared
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return nil
+}
+
+func TestService_Acknowledge(t *testing.T) {
+	repo := &memoryRepository{}
+	service := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	before := time.Now().UTC()
+	ack, err := service.Acknowledge(ctx, "api", "oncall@example.com", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ack.AcknowledgedBy != "oncall@example.com" || ack.AcknowledgedAt.Before(before) {
+		t.Errorf("expected who and when to be recorded, got %+v", ack)
+	}
+	if got := ack.ExpiresAt.Sub(ack.AcknowledgedAt); got != 30*time.Minute {
+		t.Errorf("expected a 30m acknowledgment, got %s", got)
+	}
+
+	active, _ := service.FindActive(ctx, "api")
+	if active == nil || active.ID != ack.ID {
+		t.Fatalf("expected the acknowledgment to be active, got %+v", active)
+	}
+	if other, _ := service.FindActive(ctx, "db"); other != nil {
+		t.Errorf("expected other monitors to be unaffected, got %+v", other)
+	}
+
+	// acknowledging again replaces the previous acknowledgment
+	renewed, _ := service.Acknowledge(ctx, "api", "lead@example.com", time.Hour)
+	if repo.acks[0].ClearedAt == nil {
+		t.Error("expected the previous acknowledgment to be cleared")
+	}
+	if active, _ := service.FindActive(ctx, "api"); active == nil || active.ID != renewed.ID {
+		t.Errorf("expected the renewed acknowledgment to be active, got %+v", active)
+	}
+}
+
+func TestService_ExpiryAndClear(t *testing.T) {
+	repo := &memoryRepository{}
+	service := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	service.Acknowledge(ctx, "api", "oncall@example.com", time.Hour)
+	repo.acks[0].ExpiresAt = time.Now().UTC().Add(-time.Second)
+	if active, _ := service.FindActive(ctx, "api"); active != nil {
+		t.Errorf("expected an expired acknowledgment to be inactive, got %+v", active)
+	}
+
+	service.Acknowledge(ctx, "api", "oncall@example.com", time.Hour)
+	if err := service.Clear(ctx, "api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active, _ := service.FindActive(ctx, "api"); active != nil {
+		t.Errorf("expected a cleared acknowledgment to be inactive, got %+v", active)
+	}
+}
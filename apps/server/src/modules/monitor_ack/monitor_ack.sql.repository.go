@@ -0,0 +1,93 @@
+package monitor_ack
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:monitor_acks,alias:ma"`
+
+	ID             string     `bun:"id,pk"`
+	MonitorID      string     `bun:"monitor_id,notnull"`
+	AcknowledgedBy string     `bun:"acknowledged_by,notnull"`
+	AcknowledgedAt time.Time  `bun:"acknowledged_at,notnull"`
+	ExpiresAt      time.Time  `bun:"expires_at,notnull"`
+	ClearedAt      *time.Time `bun:"cleared_at"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:             sm.ID,
+		MonitorID:      sm.MonitorID,
+		AcknowledgedBy: sm.AcknowledgedBy,
+		AcknowledgedAt: sm.AcknowledgedAt,
+		ExpiresAt:      sm.ExpiresAt,
+		ClearedAt:      sm.ClearedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	sm := &sqlModel{
+		ID:             uuid.New().String(),
+		MonitorID:      model.MonitorID,
+		AcknowledgedBy: model.AcknowledgedBy,
+		AcknowledgedAt: model.AcknowledgedAt,
+		ExpiresAt:      model.ExpiresAt,
+	}
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindActive(ctx context.Context, monitorID string, now time.Time) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().
+		Model(sm).
+		Where("monitor_id = ?", monitorID).
+		Where("cleared_at IS NULL").
+		Where("expires_at > ?", now).
+		Order("acknowledged_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) Clear(ctx context.Context, monitorID string, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("cleared_at = ?", at).
+		Where("monitor_id = ?", monitorID).
+		Where("cleared_at IS NULL").
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	_, err := r.db.NewDelete().
+		Model((*sqlModel)(nil)).
+		Where("monitor_id = ?", monitorID).
+		Exec(ctx)
+	return err
+}
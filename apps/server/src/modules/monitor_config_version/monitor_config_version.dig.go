@@ -0,0 +1,13 @@
+package monitor_config_version
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/utils"
+
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container, cfg *config.Config) {
+	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
+	container.Provide(NewService)
+}
@@ -0,0 +1,12 @@
+package monitor_config_version
+
+import "time"
+
+// Model is a snapshot of a monitor config taken when the monitor was saved
+type Model struct {
+	ID        string    `json:"id"`
+	MonitorID string    `json:"monitor_id"`
+	Type      string    `json:"type"`
+	Config    string    `json:"config"`
+	CreatedAt time.Time `json:"created_at"`
+}
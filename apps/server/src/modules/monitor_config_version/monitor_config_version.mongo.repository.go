@@ -0,0 +1,171 @@
+package monitor_config_version
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	MonitorID primitive.ObjectID `bson:"monitor_id"`
+	Type      string             `bson:"type"`
+	Config    string             `bson:"config"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:        mm.ID.Hex(),
+		MonitorID: mm.MonitorID.Hex(),
+		Type:      mm.Type,
+		Config:    mm.Config,
+		CreatedAt: mm.CreatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("monitor_config_versions")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "monitor_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+	})
+	if err != nil {
+		panic("Failed to create index for monitor_config_versions: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(model.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := &mongoModel{
+		ID:        primitive.NewObjectID(),
+		MonitorID: monitorObjectID,
+		Type:      model.Type,
+		Config:    model.Config,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mm); err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var entity mongoModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&entity), nil
+}
+
+func (r *MongoRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string, limit int) ([]*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"monitor_id": monitorObjectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []*Model
+	for cursor.Next(ctx) {
+		var entity mongoModel
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModelFromMongo(&entity))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+func (r *MongoRepositoryImpl) DeleteOlderThanNewest(ctx context.Context, monitorID string, keep int) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(keep)).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"monitor_id": monitorObjectID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var entity struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&entity); err != nil {
+			return err
+		}
+		ids = append(ids, entity.ID)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteMany(ctx, bson.M{"monitor_id": monitorObjectID})
+	return err
+}
@@ -0,0 +1,13 @@
+package monitor_config_version
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	// FindByMonitorID returns the versions of a monitor, newest first
+	FindByMonitorID(ctx context.Context, monitorID string, limit int) ([]*Model, error)
+	// DeleteOlderThanNewest keeps the newest keep versions of a monitor and deletes the rest
+	DeleteOlderThanNewest(ctx context.Context, monitorID string, keep int) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
@@ -0,0 +1,70 @@
+package monitor_config_version
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// MaxVersionsPerMonitor bounds the config history kept for each monitor
+const MaxVersionsPerMonitor = 20
+
+type Service interface {
+	// Record stores a new version unless it matches the latest stored config
+	Record(ctx context.Context, monitorID string, monitorType string, config string) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-config-version-service]"),
+	}
+}
+
+func (s *ServiceImpl) Record(ctx context.Context, monitorID string, monitorType string, config string) (*Model, error) {
+	latest, err := s.repository.FindByMonitorID(ctx, monitorID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 && latest[0].Type == monitorType && latest[0].Config == config {
+		return latest[0], nil
+	}
+
+	version, err := s.repository.Create(ctx, &Model{
+		MonitorID: monitorID,
+		Type:      monitorType,
+		Config:    config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.DeleteOlderThanNewest(ctx, monitorID, MaxVersionsPerMonitor); err != nil {
+		s.logger.Warnw("Failed to prune config versions", "monitorID", monitorID, "error", err)
+	}
+
+	return version, nil
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
+	return s.repository.FindByMonitorID(ctx, monitorID, MaxVersionsPerMonitor)
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
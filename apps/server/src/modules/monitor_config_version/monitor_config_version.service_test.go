@@ -0,0 +1,123 @@
+package monitor_config_version
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository keeps versions in memory, ordered by insertion
+type memoryRepository struct {
+	mu       sync.Mutex
+	versions []*Model
+	nextID   int
+}
+
+func (r *memoryRepository) Create(ctx context.Context, model *Model) (*Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	v := *model
+	v.ID = fmt.Sprintf("v%d", r.nextID)
+	v.CreatedAt = time.Unix(int64(r.nextID), 0)
+	r.versions = append(r.versions, &v)
+	return &v, nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.versions {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByMonitorID(ctx context.Context, monitorID string, limit int) ([]*Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*Model
+	for _, v := range r.versions {
+		if v.MonitorID == monitorID {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) DeleteOlderThanNewest(ctx context.Context, monitorID string, keep int) error {
+	newest, _ := r.FindByMonitorID(ctx, monitorID, keep)
+	kept := make(map[string]bool)
+	for _, v := range newest {
+		kept[v.ID] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var remaining []*Model
+	for _, v := range r.versions {
+		if v.MonitorID != monitorID || kept[v.ID] {
+			remaining = append(remaining, v)
+		}
+	}
+	r.versions = remaining
+	return nil
+}
+
+func (r *memoryRepository) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return r.DeleteOlderThanNewest(ctx, monitorID, 0)
+}
+
+func TestService_Record(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skips unchanged configs", func(t *testing.T) {
+		service := NewService(&memoryRepository{}, zap.NewNop().Sugar())
+
+		first, err := service.Record(ctx, "m1", "http", `{"url":"https://a.example.com"}`)
+		require.NoError(t, err)
+		same, err := service.Record(ctx, "m1", "http", `{"url":"https://a.example.com"}`)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, same.ID)
+
+		_, err = service.Record(ctx, "m1", "http", `{"url":"https://b.example.com"}`)
+		require.NoError(t, err)
+
+		versions, err := service.FindByMonitorID(ctx, "m1")
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		assert.Equal(t, `{"url":"https://b.example.com"}`, versions[0].Config)
+		assert.Equal(t, `{"url":"https://a.example.com"}`, versions[1].Config)
+	})
+
+	t.Run("keeps a bounded history per monitor", func(t *testing.T) {
+		repo := &memoryRepository{}
+		service := NewService(repo, zap.NewNop().Sugar())
+
+		for i := 0; i < MaxVersionsPerMonitor+5; i++ {
+			_, err := service.Record(ctx, "m1", "tcp", fmt.Sprintf(`{"port":%d}`, i+1))
+			require.NoError(t, err)
+		}
+		_, err := service.Record(ctx, "m2", "tcp", `{"port":1}`)
+		require.NoError(t, err)
+
+		versions, err := service.FindByMonitorID(ctx, "m1")
+		require.NoError(t, err)
+		require.Len(t, versions, MaxVersionsPerMonitor)
+		assert.Equal(t, fmt.Sprintf(`{"port":%d}`, MaxVersionsPerMonitor+5), versions[0].Config)
+		assert.Len(t, repo.versions, MaxVersionsPerMonitor+1)
+	})
+}
@@ -0,0 +1,120 @@
+package monitor_config_version
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:monitor_config_versions,alias:mcv"`
+
+	ID        string    `bun:"id,pk"`
+	MonitorID string    `bun:"monitor_id,notnull"`
+	Type      string    `bun:"type,notnull"`
+	Config    string    `bun:"config"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:        sm.ID,
+		MonitorID: sm.MonitorID,
+		Type:      sm.Type,
+		Config:    sm.Config,
+		CreatedAt: sm.CreatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	sm := &sqlModel{
+		ID:        uuid.New().String(),
+		MonitorID: model.MonitorID,
+		Type:      model.Type,
+		Config:    model.Config,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string, limit int) ([]*Model, error) {
+	var sms []*sqlModel
+	query := r.db.NewSelect().
+		Model(&sms).
+		Where("monitor_id = ?", monitorID).
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, len(sms))
+	for i, sm := range sms {
+		models[i] = toDomainModelFromSQL(sm)
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) DeleteOlderThanNewest(ctx context.Context, monitorID string, keep int) error {
+	// Select first and delete by id, MySQL does not support LIMIT in IN subqueries
+	var ids []string
+	err := r.db.NewSelect().
+		Model((*sqlModel)(nil)).
+		Column("id").
+		Where("monitor_id = ?", monitorID).
+		Order("created_at DESC").
+		Offset(keep).
+		Limit(1000).
+		Scan(ctx, &ids)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err = r.db.NewDelete().
+		Model((*sqlModel)(nil)).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	_, err := r.db.NewDelete().
+		Model((*sqlModel)(nil)).
+		Where("monitor_id = ?", monitorID).
+		Exec(ctx)
+	return err
+}
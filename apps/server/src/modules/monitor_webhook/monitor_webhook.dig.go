@@ -0,0 +1,9 @@
+package monitor_webhook
+
+import (
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container) {
+	container.Provide(NewEventListener)
+}
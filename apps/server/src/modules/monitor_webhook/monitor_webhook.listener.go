@@ -0,0 +1,197 @@
+package monitor_webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/notification_channel/providers"
+	"peekaping/src/modules/shared"
+	"peekaping/src/version"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryAttempts is how often a transition is POSTed before giving up
+	deliveryAttempts = 3
+	// deliveryTimeout bounds a single attempt
+	deliveryTimeout = 10 * time.Second
+)
+
+// Payload is the body POSTed to the state-change webhook of a monitor
+type Payload struct {
+	MonitorID      string    `json:"monitor_id"`
+	MonitorName    string    `json:"monitor_name"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	Timestamp      time.Time `json:"timestamp"`
+	Message        string    `json:"message"`
+}
+
+// EventListener POSTs the status transitions of monitors to their
+// state-change webhook. It runs apart from the notification channels, a
+// monitor without a webhook URL is skipped.
+type EventListener struct {
+	monitorService monitor.Service
+	client         *http.Client
+	// retryDelay is the wait before the first retry, doubled for every next one
+	retryDelay time.Duration
+	logger     *zap.SugaredLogger
+}
+
+func NewEventListener(monitorService monitor.Service, logger *zap.SugaredLogger) *EventListener {
+	return &EventListener{
+		monitorService: monitorService,
+		client:         &http.Client{Timeout: deliveryTimeout},
+		retryDelay:     2 * time.Second,
+		logger:         logger.Named("[monitor-webhook-listener]"),
+	}
+}
+
+// Subscribe subscribes to MonitorStateTransition events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorStateTransition, l.handleStateTransition)
+}
+
+func (l *EventListener) handleStateTransition(event events.Event) {
+	transition, ok := event.Payload.(*shared.MonitorStateTransition)
+	if !ok {
+		l.logger.Errorf("Invalid handleStateTransition event payload type: %v", event.Payload)
+		return
+	}
+
+	ctx := context.Background()
+	m, err := l.monitorService.FindByID(ctx, transition.MonitorID)
+	if err != nil {
+		l.logger.Errorf("Failed to get monitor %s: %v", transition.MonitorID, err)
+		return
+	}
+	if m == nil || m.StateWebhookURL == "" {
+		return
+	}
+
+	body, contentType, err := renderBody(m, transition)
+	if err != nil {
+		l.logger.Errorf("Failed to build state-change webhook of monitor %s: %v", m.ID, err)
+		return
+	}
+
+	if err := l.deliver(ctx, m.StateWebhookURL, body, contentType); err != nil {
+		l.logger.Warnf("State-change webhook of monitor %s failed after %d attempts: %v", m.ID, deliveryAttempts, err)
+	}
+}
+
+// renderBody returns the default JSON payload, or the rendered template of
+// the monitor. Templates get the bindings of the notification webhook plus
+// the payload fields under "transition".
+func renderBody(m *monitor.Model, transition *shared.MonitorStateTransition) ([]byte, string, error) {
+	payload := &Payload{
+		MonitorID:      m.ID,
+		MonitorName:    m.Name,
+		PreviousStatus: statusName(transition.PreviousStatus),
+		Status:         statusName(transition.Status),
+		Timestamp:      transition.Time,
+		Message:        transition.Msg,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	if m.StateWebhookTemplate == "" {
+		return body, "application/json", nil
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, "", err
+	}
+	hb := &heartbeat.Model{
+		MonitorID: m.ID,
+		Status:    transition.Status,
+		Msg:       transition.Msg,
+		Time:      transition.Time,
+	}
+	bindings := providers.PrepareTemplateBindings(m, hb, transition.Msg)
+	bindings["transition"] = fields
+
+	tpl, err := monitor.ParseStateWebhookTemplate(m.StateWebhookTemplate)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse body template: %w", err)
+	}
+	rendered, err := tpl.RenderString(bindings)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render body template: %w", err)
+	}
+	if json.Valid([]byte(rendered)) {
+		return []byte(rendered), "application/json", nil
+	}
+	return []byte(rendered), "text/plain", nil
+}
+
+// deliver POSTs the body, retrying on network errors, 429 and 5xx responses
+func (l *EventListener) deliver(ctx context.Context, url string, body []byte, contentType string) error {
+	delay := l.retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := l.post(ctx, url, body, contentType)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == deliveryAttempts {
+			return err
+		}
+
+		l.logger.Debugf("State-change webhook attempt %d to %s failed, retrying in %s: %v", attempt, url, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+func (l *EventListener) post(ctx context.Context, url string, body []byte, contentType string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "Peekaping-Webhook/"+version.Version)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return false, nil
+}
+
+func statusName(status shared.MonitorStatus) string {
+	switch status {
+	case shared.MonitorStatusDown:
+		return "down"
+	case shared.MonitorStatusUp:
+		return "up"
+	case shared.MonitorStatusMaintenance:
+		return "maintenance"
+	case shared.MonitorStatusDependencyDown:
+		return "dependency_down"
+	default:
+		return "pending"
+	}
+}
@@ -0,0 +1,179 @@
+package monitor_webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeMonitors serves monitors from a map
+type fakeMonitors struct {
+	monitor.Service
+	monitors map[string]*monitor.Model
+}
+
+func (f *fakeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return f.monitors[id], nil
+}
+
+// webhookServer answers with the queued status codes, then 200
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	payloads []Payload
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.payloads = append(s.payloads, payload)
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) received() []Payload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Payload(nil), s.payloads...)
+}
+
+func newTestListener(monitors map[string]*monitor.Model) *EventListener {
+	l := NewEventListener(&fakeMonitors{monitors: monitors}, zap.NewNop().Sugar())
+	l.retryDelay = time.Millisecond
+	return l
+}
+
+func transitionEvent(monitorID string, previous, status shared.MonitorStatus, at time.Time) events.Event {
+	return events.Event{
+		Type: events.MonitorStateTransition,
+		Payload: &shared.MonitorStateTransition{
+			MonitorID:      monitorID,
+			PreviousStatus: previous,
+			Status:         status,
+			Msg:            "connection refused",
+			Time:           at,
+		},
+	}
+}
+
+func TestListener_PostsBothStates(t *testing.T) {
+	server := newWebhookServer(t)
+	l := newTestListener(map[string]*monitor.Model{
+		"api": {ID: "api", Name: "API", StateWebhookURL: server.URL},
+	})
+
+	at := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	l.handleStateTransition(transitionEvent("api", shared.MonitorStatusUp, shared.MonitorStatusDown, at))
+
+	received := server.received()
+	require.Len(t, received, 1)
+	assert.Equal(t, Payload{
+		MonitorID:      "api",
+		MonitorName:    "API",
+		PreviousStatus: "up",
+		Status:         "down",
+		Timestamp:      at,
+		Message:        "connection refused",
+	}, received[0])
+}
+
+func TestListener_RetriesServerErrors(t *testing.T) {
+	server := newWebhookServer(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	l := newTestListener(map[string]*monitor.Model{
+		"api": {ID: "api", Name: "API", StateWebhookURL: server.URL},
+	})
+
+	l.handleStateTransition(transitionEvent("api", shared.MonitorStatusDown, shared.MonitorStatusUp, time.Now().UTC()))
+	assert.Len(t, server.received(), 3)
+}
+
+func TestListener_GivesUp(t *testing.T) {
+	failing := newWebhookServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	rejecting := newWebhookServer(t, http.StatusBadRequest)
+	l := newTestListener(map[string]*monitor.Model{
+		"failing":   {ID: "failing", StateWebhookURL: failing.URL},
+		"rejecting": {ID: "rejecting", StateWebhookURL: rejecting.URL},
+	})
+
+	l.handleStateTransition(transitionEvent("failing", shared.MonitorStatusUp, shared.MonitorStatusDown, time.Now().UTC()))
+	assert.Len(t, failing.received(), deliveryAttempts)
+
+	// client errors are not retried
+	l.handleStateTransition(transitionEvent("rejecting", shared.MonitorStatusUp, shared.MonitorStatusDown, time.Now().UTC()))
+	assert.Len(t, rejecting.received(), 1)
+}
+
+func TestListener_SkipsMonitorsWithoutWebhook(t *testing.T) {
+	server := newWebhookServer(t)
+	l := newTestListener(map[string]*monitor.Model{
+		"api": {ID: "api", Name: "API"},
+	})
+
+	l.handleStateTransition(transitionEvent("api", shared.MonitorStatusUp, shared.MonitorStatusDown, time.Now().UTC()))
+	l.handleStateTransition(transitionEvent("missing", shared.MonitorStatusUp, shared.MonitorStatusDown, time.Now().UTC()))
+	assert.Empty(t, server.received())
+}
+
+func TestListener_RendersTemplate(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	var contentTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+	}))
+	t.Cleanup(server.Close)
+
+	l := newTestListener(map[string]*monitor.Model{
+		"json": {
+			ID: "json", Name: "API", Type: "http", Config: `{"url": "https://api.example.com"}`,
+			StateWebhookURL:      server.URL,
+			StateWebhookTemplate: `{"service": "{{ name }}", "url": "{{ monitor.config.url }}", "from": "{{ transition.previous_status }}", "to": "{{ transition.status }}", "at": "{{ transition.timestamp }}", "detail": "{{ msg }}"}`,
+		},
+		"text": {
+			ID: "text", Name: "DB",
+			StateWebhookURL:      server.URL,
+			StateWebhookTemplate: `{{ name }} is {{ status }}`,
+		},
+	})
+
+	at := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	l.handleStateTransition(transitionEvent("json", shared.MonitorStatusUp, shared.MonitorStatusDown, at))
+	l.handleStateTransition(transitionEvent("text", shared.MonitorStatusDown, shared.MonitorStatusUp, at))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{"service": "API", "url": "https://api.example.com", "from": "up", "to": "down", "at": "2025-07-20T12:00:00Z", "detail": "connection refused"}`, bodies[0])
+	assert.Equal(t, "application/json", contentTypes[0])
+	assert.Equal(t, "DB is UP", bodies[1])
+	assert.Equal(t, "text/plain", contentTypes[1])
+}
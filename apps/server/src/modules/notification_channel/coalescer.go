@@ -0,0 +1,166 @@
+package notification_channel
+
+import (
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxGroupedMonitorNames limits how many monitor names are listed in a grouped message
+const maxGroupedMonitorNames = 10
+
+// pendingNotification is a state change waiting for its coalescing window to close
+type pendingNotification struct {
+	monitor   *monitor.Model
+	heartbeat *heartbeat.Model
+	channels  []*Model
+}
+
+// notificationBatch is what gets delivered to a single channel once the window closes,
+// either one grouped notification or the individual ones
+type notificationBatch struct {
+	channel       *Model
+	status        heartbeat.MonitorStatus
+	notifications []*pendingNotification
+	grouped       bool
+}
+
+// notificationCoalescer collects state changes for a short window so that a shared
+// outage produces one grouped message per channel instead of one per monitor
+type notificationCoalescer struct {
+	window    time.Duration
+	threshold int
+	deliver   func(batches []*notificationBatch)
+
+	mu      sync.Mutex
+	pending []*pendingNotification
+	timer   *time.Timer
+}
+
+func newNotificationCoalescer(window time.Duration, threshold int, deliver func(batches []*notificationBatch)) *notificationCoalescer {
+	return &notificationCoalescer{
+		window:    window,
+		threshold: threshold,
+		deliver:   deliver,
+	}
+}
+
+// add queues a notification, the window starts with the first queued notification
+func (c *notificationCoalescer) add(n *pendingNotification) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = append(c.pending, n)
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.window, c.flush)
+	}
+}
+
+// flush delivers everything queued during the window
+func (c *notificationCoalescer) flush() {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.timer = nil
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	c.deliver(c.batch(pending))
+}
+
+// batch groups the pending notifications by channel and status. Groups reaching
+// the threshold are marked for a single grouped message, keeping arrival order,
+// unless the channel keeps an incident per monitor.
+func (c *notificationCoalescer) batch(pending []*pendingNotification) []*notificationBatch {
+	type batchKey struct {
+		channelID string
+		status    heartbeat.MonitorStatus
+	}
+
+	var batches []*notificationBatch
+	byKey := make(map[batchKey]*notificationBatch)
+	for _, n := range pending {
+		for _, channel := range n.channels {
+			key := batchKey{channelID: channel.ID, status: n.heartbeat.Status}
+			b, ok := byKey[key]
+			if !ok {
+				b = &notificationBatch{channel: channel, status: n.heartbeat.Status}
+				byKey[key] = b
+				batches = append(batches, b)
+			}
+			b.notifications = append(b.notifications, n)
+		}
+	}
+
+	for _, b := range batches {
+		b.grouped = len(b.notifications) >= c.threshold && !keepsPerMonitorIncidents(b.channel)
+	}
+	return batches
+}
+
+// groupedNotification builds the summary message and the stand-in monitor and
+// heartbeat handed to providers for a grouped batch
+func groupedNotification(b *notificationBatch) (string, *monitor.Model, *heartbeat.Model) {
+	count := len(b.notifications)
+
+	names := make([]string, 0, maxGroupedMonitorNames)
+	for i, n := range b.notifications {
+		if i == maxGroupedMonitorNames {
+			break
+		}
+		names = append(names, n.monitor.Name)
+	}
+	list := strings.Join(names, ", ")
+	if count > maxGroupedMonitorNames {
+		list = fmt.Sprintf("%s and %d more", list, count-maxGroupedMonitorNames)
+	}
+
+	message := fmt.Sprintf("%d monitors %s: %s", count, groupedStatusVerb(b.status), list)
+
+	first := b.notifications[0].heartbeat
+	groupMonitor := &monitor.Model{
+		ID:   fmt.Sprintf("group-%s", strings.ToLower(groupedStatusName(b.status))),
+		Name: fmt.Sprintf("%d monitors", count),
+	}
+	groupHeartbeat := &heartbeat.Model{
+		Status:    b.status,
+		Msg:       message,
+		Time:      first.Time,
+		EndTime:   first.EndTime,
+		Important: true,
+		Notified:  true,
+	}
+	return message, groupMonitor, groupHeartbeat
+}
+
+func groupedStatusVerb(status heartbeat.MonitorStatus) string {
+	switch status {
+	case shared.MonitorStatusDown:
+		return "went down"
+	case shared.MonitorStatusUp:
+		return "are back up"
+	case shared.MonitorStatusMaintenance:
+		return "entered maintenance"
+	default:
+		return "are pending"
+	}
+}
+
+func groupedStatusName(status heartbeat.MonitorStatus) string {
+	switch status {
+	case shared.MonitorStatusDown:
+		return "DOWN"
+	case shared.MonitorStatusUp:
+		return "UP"
+	case shared.MonitorStatusMaintenance:
+		return "MAINTENANCE"
+	default:
+		return "PENDING"
+	}
+}
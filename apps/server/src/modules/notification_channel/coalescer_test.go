@@ -0,0 +1,294 @@
+package notification_channel
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_notification"
+	"peekaping/src/modules/shared"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type sentNotification struct {
+	config  string
+	message string
+	monitor *monitor.Model
+}
+
+// recordingProvider keeps every notification it is asked to send
+type recordingProvider struct {
+	mu   sync.Mutex
+	sent []sentNotification
+}
+
+func (p *recordingProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, sentNotification{config: configJSON, message: message, monitor: m})
+	return nil
+}
+
+func (p *recordingProvider) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	return p.Send(ctx, configJSON, "test", nil, &heartbeat.Model{Status: status})
+}
+
+func (p *recordingProvider) Validate(configJSON string) error { return nil }
+
+func (p *recordingProvider) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func (p *recordingProvider) byChannel(config string) []sentNotification {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var result []sentNotification
+	for _, s := range p.sent {
+		if s.config == config {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// fakeMonitorNotifications links every monitor to the channels in channelsByMonitor
+type fakeMonitorNotifications struct {
+	monitor_notification.Service
+	channelsByMonitor map[string][]string
+}
+
+func (f *fakeMonitorNotifications) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_notification.Model, error) {
+	var result []*monitor_notification.Model
+	for _, id := range f.channelsByMonitor[monitorID] {
+		result = append(result, &monitor_notification.Model{MonitorID: monitorID, NotificationID: id})
+	}
+	return result, nil
+}
+
+// fakeMonitors names every monitor after its ID
+type fakeMonitors struct {
+	monitor.Service
+}
+
+func (f *fakeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return &monitor.Model{ID: id, Name: id}, nil
+}
+
+type fakeChannels struct {
+	Service
+	channels map[string]*Model
+}
+
+func (f *fakeChannels) FindByID(ctx context.Context, id string) (*Model, error) {
+	return f.channels[id], nil
+}
+
+func newCoalescingTestListener(t *testing.T, window time.Duration, threshold int, channelsByMonitor map[string][]string) (*recordingProvider, *events.EventBus) {
+	provider := &recordingProvider{}
+	RegisterNotificationChannelProvider("recording", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "recording") })
+
+	configA, configB := "channel-a", "channel-b"
+	listener := &NotificationEventListener{
+		service: &fakeChannels{channels: map[string]*Model{
+			"a": {ID: "a", Name: "A", Type: "recording", Config: &configA},
+			"b": {ID: "b", Name: "B", Type: "recording", Config: &configB},
+		}},
+		monitorSvc:                 &fakeMonitors{},
+		monitorNotificationService: &fakeMonitorNotifications{channelsByMonitor: channelsByMonitor},
+		logger:                     zap.NewNop().Sugar(),
+	}
+	listener.coalescer = newNotificationCoalescer(window, threshold, listener.deliverBatches)
+
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	listener.Subscribe(bus)
+	return provider, bus
+}
+
+func publishStatusChange(bus *events.EventBus, monitorID string, status heartbeat.MonitorStatus) {
+	bus.Publish(events.Event{
+		Type:    events.MonitorStatusChanged,
+		Payload: &heartbeat.Model{MonitorID: monitorID, Status: status, Msg: monitorID + " changed", Time: time.Now()},
+	})
+}
+
+func waitForSent(t *testing.T, provider *recordingProvider, count int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		provider.mu.Lock()
+		n := len(provider.sent)
+		provider.mu.Unlock()
+		if n >= count {
+			// give a late extra send the chance to show up
+			time.Sleep(50 * time.Millisecond)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected at least %d notifications to be sent", count)
+}
+
+func TestNotificationCoalescing_GroupsAboveThreshold(t *testing.T) {
+	channelsByMonitor := map[string][]string{}
+	for i := 1; i <= 12; i++ {
+		channelsByMonitor[fmt.Sprintf("api-%02d", i)] = []string{"a", "b"}
+	}
+	provider, bus := newCoalescingTestListener(t, 100*time.Millisecond, 5, channelsByMonitor)
+
+	for i := 1; i <= 12; i++ {
+		publishStatusChange(bus, fmt.Sprintf("api-%02d", i), shared.MonitorStatusDown)
+	}
+
+	waitForSent(t, provider, 2)
+
+	for _, config := range []string{"channel-a", "channel-b"} {
+		sent := provider.byChannel(config)
+		if len(sent) != 1 {
+			t.Fatalf("expected 1 grouped notification on %s, got %d", config, len(sent))
+		}
+		if !strings.HasPrefix(sent[0].message, "12 monitors went down: ") {
+			t.Errorf("unexpected grouped message: %q", sent[0].message)
+		}
+		if !strings.HasSuffix(sent[0].message, " and 2 more") {
+			t.Errorf("expected the monitor list to be truncated, got: %q", sent[0].message)
+		}
+		if sent[0].monitor.Name != "12 monitors" {
+			t.Errorf("unexpected grouped monitor name: %q", sent[0].monitor.Name)
+		}
+	}
+}
+
+func TestNotificationCoalescing_BelowThresholdSendsIndividually(t *testing.T) {
+	provider, bus := newCoalescingTestListener(t, 100*time.Millisecond, 5, map[string][]string{
+		"api": {"a"},
+		"db":  {"a"},
+	})
+
+	publishStatusChange(bus, "api", shared.MonitorStatusDown)
+	publishStatusChange(bus, "db", shared.MonitorStatusDown)
+
+	waitForSent(t, provider, 2)
+
+	sent := provider.byChannel("channel-a")
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 individual notifications, got %d", len(sent))
+	}
+	got := map[string]bool{sent[0].message: true, sent[1].message: true}
+	if !got["api changed"] || !got["db changed"] {
+		t.Errorf("expected the original messages, got: %v", got)
+	}
+}
+
+func TestNotificationCoalescer_Batch(t *testing.T) {
+	configA, configB := "channel-a", "channel-b"
+	a := &Model{ID: "a", Config: &configA}
+	b := &Model{ID: "b", Config: &configB}
+
+	coalescer := newNotificationCoalescer(time.Second, 3, nil)
+
+	var pending []*pendingNotification
+	for i := 0; i < 3; i++ {
+		pending = append(pending, &pendingNotification{
+			monitor:   &monitor.Model{ID: fmt.Sprintf("down-%d", i), Name: fmt.Sprintf("down-%d", i)},
+			heartbeat: &heartbeat.Model{Status: shared.MonitorStatusDown},
+			channels:  []*Model{a, b},
+		})
+	}
+	pending = append(pending, &pendingNotification{
+		monitor:   &monitor.Model{ID: "up", Name: "up"},
+		heartbeat: &heartbeat.Model{Status: shared.MonitorStatusUp},
+		channels:  []*Model{a},
+	})
+	// only one of the down monitors also notifies channel c, below the threshold
+	pending[0].channels = append(pending[0].channels, &Model{ID: "c"})
+
+	batches := coalescer.batch(pending)
+	if len(batches) != 4 {
+		t.Fatalf("expected 4 batches, got %d", len(batches))
+	}
+
+	expected := []struct {
+		channel string
+		status  heartbeat.MonitorStatus
+		count   int
+		grouped bool
+	}{
+		{"a", shared.MonitorStatusDown, 3, true},
+		{"b", shared.MonitorStatusDown, 3, true},
+		{"c", shared.MonitorStatusDown, 1, false},
+		{"a", shared.MonitorStatusUp, 1, false},
+	}
+	for i, e := range expected {
+		got := batches[i]
+		if got.channel.ID != e.channel || got.status != e.status || len(got.notifications) != e.count || got.grouped != e.grouped {
+			t.Errorf("batch %d: expected %+v, got channel=%s status=%d count=%d grouped=%t",
+				i, e, got.channel.ID, got.status, len(got.notifications), got.grouped)
+		}
+	}
+
+	message, groupMonitor, groupHeartbeat := groupedNotification(batches[0])
+	if message != "3 monitors went down: down-0, down-1, down-2" {
+		t.Errorf("unexpected grouped message: %q", message)
+	}
+	if groupMonitor.Name != "3 monitors" || groupHeartbeat.Status != shared.MonitorStatusDown || groupHeartbeat.Msg != message {
+		t.Errorf("unexpected grouped monitor %+v or heartbeat %+v", groupMonitor, groupHeartbeat)
+	}
+}
+
+// keyedProvider keeps an incident per monitor
+type keyedProvider struct {
+	recordingProvider
+}
+
+func (p *keyedProvider) PerMonitorIncidents() bool { return true }
+
+func TestNotificationCoalescer_PerMonitorIncidentsNotGrouped(t *testing.T) {
+	RegisterNotificationChannelProvider("keyed", &keyedProvider{})
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "keyed") })
+
+	config := "pagerduty"
+	keyed := &Model{ID: "pd", Type: "keyed", Config: &config}
+	coalescer := newNotificationCoalescer(time.Second, 2, nil)
+
+	var pending []*pendingNotification
+	for i := 0; i < 3; i++ {
+		pending = append(pending, &pendingNotification{
+			monitor:   &monitor.Model{ID: fmt.Sprintf("down-%d", i)},
+			heartbeat: &heartbeat.Model{Status: shared.MonitorStatusDown},
+			channels:  []*Model{keyed},
+		})
+	}
+
+	batches := coalescer.batch(pending)
+	if len(batches) != 1 || batches[0].grouped || len(batches[0].notifications) != 3 {
+		t.Fatalf("expected the notifications of a per-monitor channel to stay individual, got %+v", batches)
+	}
+}
+
+func TestListener_GroupedFailureRecordedPerMonitor(t *testing.T) {
+	failures := &fakeFailureService{}
+	listener := newRetryTestListener(t, &flakyProvider{failures: 10}, failures)
+	channel, _ := listener.service.FindByID(context.Background(), "a")
+
+	b := &notificationBatch{channel: channel, status: shared.MonitorStatusDown, grouped: true}
+	for _, id := range []string{"api", "db"} {
+		b.notifications = append(b.notifications, &pendingNotification{
+			monitor:   &monitor.Model{ID: id, Name: id},
+			heartbeat: &heartbeat.Model{MonitorID: id, Status: shared.MonitorStatusDown},
+		})
+	}
+	listener.deliverBatches([]*notificationBatch{b})
+
+	if len(failures.recorded) != 2 {
+		t.Fatalf("expected a failure per monitor, got %d", len(failures.recorded))
+	}
+	for i, id := range []string{"api", "db"} {
+		if failures.recorded[i].MonitorID != id || failures.recorded[i].Message != "2 monitors went down: api, db" {
+			t.Errorf("unexpected failure %+v", failures.recorded[i])
+		}
+	}
+}
@@ -2,12 +2,19 @@ package notification_channel
 
 import (
 	"context"
+	"errors"
 	"peekaping/src/config"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_ack"
 	"peekaping/src/modules/monitor_notification"
+	"peekaping/src/modules/monitor_status_page"
 	"peekaping/src/modules/notification_channel/providers"
+	"peekaping/src/modules/notification_failure"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/status_page"
+	"time"
 
 	"go.uber.org/dig"
 	"go.uber.org/zap"
@@ -19,7 +26,20 @@ type NotificationEventListener struct {
 	monitorSvc                 monitor.Service
 	heartbeatService           heartbeat.Service
 	monitorNotificationService monitor_notification.Service
+	ackService                 monitor_ack.Service
+	monitorStatusPageService   monitor_status_page.Service
+	statusPageService          status_page.Service
 	logger                     *zap.SugaredLogger
+	coalescer                  *notificationCoalescer
+	retry                      retryPolicy
+	// wait sleeps between send attempts, waitContext when nil
+	wait func(context.Context, time.Duration) error
+	// sequence drops the retries of a notification once a newer one of the
+	// same monitor and channel is sent, a late DOWN must not follow the UP
+	sequence sendSequence
+	// failureService stores sends that failed every attempt, nil unless
+	// persisting failures is enabled
+	failureService notification_failure.Service
 }
 
 type NotificationEventListenerParams struct {
@@ -28,6 +48,10 @@ type NotificationEventListenerParams struct {
 	MonitorSvc                 monitor.Service
 	HeartbeatService           heartbeat.Service
 	MonitorNotificationService monitor_notification.Service
+	AckService                 monitor_ack.Service
+	FailureService             notification_failure.Service
+	MonitorStatusPageService   monitor_status_page.Service
+	StatusPageService          status_page.Service
 	Logger                     *zap.SugaredLogger
 	Config                     *config.Config
 }
@@ -41,6 +65,7 @@ func NewNotificationEventListener(p NotificationEventListenerParams) *Notificati
 	RegisterNotificationChannelProvider("pagerduty", providers.NewPagerDutySender(p.Logger, p.Config))
 	RegisterNotificationChannelProvider("opsgenie", providers.NewOpsgenieSender(p.Logger))
 	RegisterNotificationChannelProvider("google_chat", providers.NewGoogleChatSender(p.Logger, p.Config))
+	RegisterNotificationChannelProvider("teams", providers.NewTeamsSender(p.Logger, p.Config))
 	RegisterNotificationChannelProvider("grafana_oncall", providers.NewGrafanaOncallSender(p.Logger))
 	RegisterNotificationChannelProvider("signal", providers.NewSignalSender(p.Logger))
 	RegisterNotificationChannelProvider("gotify", providers.NewGotifySender(p.Logger))
@@ -48,19 +73,39 @@ func NewNotificationEventListener(p NotificationEventListenerParams) *Notificati
 	RegisterNotificationChannelProvider("mattermost", providers.NewMattermostSender(p.Logger))
 	RegisterNotificationChannelProvider("matrix", providers.NewMatrixSender(p.Logger))
 	RegisterNotificationChannelProvider("discord", providers.NewDiscordSender(p.Logger))
+	RegisterNotificationChannelProvider("syslog", providers.NewSyslogSender(p.Logger))
 
-	return &NotificationEventListener{
+	listener := &NotificationEventListener{
 		service:                    p.Service,
 		monitorSvc:                 p.MonitorSvc,
 		heartbeatService:           p.HeartbeatService,
 		monitorNotificationService: p.MonitorNotificationService,
+		ackService:                 p.AckService,
+		monitorStatusPageService:   p.MonitorStatusPageService,
+		statusPageService:          p.StatusPageService,
 		logger:                     p.Logger,
 	}
+
+	if p.Config != nil {
+		listener.retry = retryPolicy{attempts: p.Config.NotificationSendAttempts, baseDelay: p.Config.NotificationRetryBaseDelay}
+		if p.Config.NotificationPersistFailures {
+			listener.failureService = p.FailureService
+		}
+	}
+
+	if p.Config != nil && p.Config.NotificationCoalesceWindow > 0 {
+		p.Logger.Infof("Notification coalescing enabled: window %s, threshold %d monitors", p.Config.NotificationCoalesceWindow, p.Config.NotificationCoalesceThreshold)
+		listener.coalescer = newNotificationCoalescer(p.Config.NotificationCoalesceWindow, p.Config.NotificationCoalesceThreshold, listener.deliverBatches)
+	}
+
+	return listener
 }
 
 // Subscribe subscribes to NotifyEvent and sends notifications
 func (l *NotificationEventListener) Subscribe(eventBus *events.EventBus) {
 	eventBus.Subscribe(events.MonitorStatusChanged, l.handleNotifyEvent)
+	eventBus.Subscribe(events.MonitorSlowCheck, l.handleSlowCheckEvent)
+	eventBus.Subscribe(events.MonitorNeverSucceeded, l.handleNeverSucceededEvent)
 }
 
 func (l *NotificationEventListener) handleNotifyEvent(event events.Event) {
@@ -76,6 +121,55 @@ func (l *NotificationEventListener) handleNotifyEvent(event events.Event) {
 
 	l.logger.Infof("Notification event received for monitor: %s", monitorID)
 
+	if l.acknowledged(ctx, hb) {
+		l.logger.Infof("Skipping notification for acknowledged monitor: %s", monitorID)
+		return
+	}
+
+	l.notify(ctx, hb, true)
+}
+
+// handleSlowCheckEvent notifies that the checks of a monitor are getting
+// slower. The monitor is still up, so acknowledgments are left alone.
+func (l *NotificationEventListener) handleSlowCheckEvent(event events.Event) {
+	hb, ok := event.Payload.(*heartbeat.Model)
+	if !ok {
+		l.logger.Errorf("Invalid handleSlowCheckEvent event payload type: %v", event.Payload)
+		return
+	}
+
+	l.logger.Infof("Slow check event received for monitor: %s", hb.MonitorID)
+	l.notify(context.Background(), hb, false)
+}
+
+// handleNeverSucceededEvent notifies that a monitor has been failing since it
+// was created. A pending monitor has no outage to acknowledge yet, so only a
+// down monitor is checked for an acknowledgment.
+func (l *NotificationEventListener) handleNeverSucceededEvent(event events.Event) {
+	ctx := context.Background()
+
+	hb, ok := event.Payload.(*heartbeat.Model)
+	if !ok {
+		l.logger.Errorf("Invalid handleNeverSucceededEvent event payload type: %v", event.Payload)
+		return
+	}
+
+	l.logger.Infof("Never succeeded event received for monitor: %s", hb.MonitorID)
+
+	if hb.Status == shared.MonitorStatusDown && l.acknowledged(ctx, hb) {
+		l.logger.Infof("Skipping notification for acknowledged monitor: %s", hb.MonitorID)
+		return
+	}
+
+	l.notify(ctx, hb, false)
+}
+
+// notify sends the heartbeat to every notification channel of its monitor.
+// Status changes also go to the channels of the status pages that show the
+// monitor and notify about incidents, each channel is sent to once.
+func (l *NotificationEventListener) notify(ctx context.Context, hb *heartbeat.Model, withStatusPages bool) {
+	monitorID := hb.MonitorID
+
 	// Get monitor-notification records
 	monitorNotifications, err := l.monitorNotificationService.FindByMonitorID(ctx, monitorID)
 	if err != nil {
@@ -83,18 +177,32 @@ func (l *NotificationEventListener) handleNotifyEvent(event events.Event) {
 		return
 	}
 
-	var notificationChannels []*Model
+	notificationIDs := make([]string, 0, len(monitorNotifications))
 	for _, mn := range monitorNotifications {
-		l.logger.Infof("Monitor notification: %s", mn.NotificationID)
-		notification, err := l.service.FindByID(ctx, mn.NotificationID)
+		notificationIDs = append(notificationIDs, mn.NotificationID)
+	}
+	if withStatusPages {
+		notificationIDs = append(notificationIDs, l.statusPageNotificationIDs(ctx, monitorID)...)
+	}
+
+	var notificationChannels []*Model
+	seen := make(map[string]bool, len(notificationIDs))
+	for _, notificationID := range notificationIDs {
+		if seen[notificationID] {
+			continue
+		}
+		seen[notificationID] = true
+
+		l.logger.Infof("Monitor notification: %s", notificationID)
+		notification, err := l.service.FindByID(ctx, notificationID)
 		if err != nil {
-			l.logger.Errorf("Failed to get notification by ID: %s, error: %v", mn.NotificationID, err)
+			l.logger.Errorf("Failed to get notification by ID: %s, error: %v", notificationID, err)
 			continue
 		}
 		if notification != nil {
 			notificationChannels = append(notificationChannels, notification)
 		} else {
-			l.logger.Warnf("Notification not found for monitor-notification: %s", mn.NotificationID)
+			l.logger.Warnf("Notification not found for monitor-notification: %s", notificationID)
 		}
 	}
 
@@ -105,28 +213,175 @@ func (l *NotificationEventListener) handleNotifyEvent(event events.Event) {
 		return
 	}
 
+	if l.coalescer != nil {
+		l.coalescer.add(&pendingNotification{
+			monitor:   monitorModel,
+			heartbeat: hb,
+			channels:  notificationChannels,
+		})
+		return
+	}
+
 	for _, notificationChannel := range notificationChannels {
-		integration, ok := GetNotificationChannelProvider(notificationChannel.Type)
-		if !ok {
-			l.logger.Warnf("No integration registered for notification type: %s", notificationChannel.Type)
+		l.send(ctx, notificationChannel, hb.Msg, monitorModel, hb)
+	}
+}
+
+// statusPageNotificationIDs returns the channels of the status pages that show
+// the monitor and notify about incidents
+func (l *NotificationEventListener) statusPageNotificationIDs(ctx context.Context, monitorID string) []string {
+	if l.monitorStatusPageService == nil || l.statusPageService == nil {
+		return nil
+	}
+
+	relations, err := l.monitorStatusPageService.GetStatusPagesForMonitor(ctx, monitorID)
+	if err != nil {
+		l.logger.Errorf("Failed to get status pages of monitor %s: %v", monitorID, err)
+		return nil
+	}
+
+	var ids []string
+	for _, relation := range relations {
+		if !relation.Active {
+			continue
+		}
+		page, err := l.statusPageService.FindByID(ctx, relation.StatusPageID)
+		if err != nil {
+			l.logger.Errorf("Failed to get status page %s: %v", relation.StatusPageID, err)
 			continue
 		}
-		if notificationChannel.Config == nil {
-			l.logger.Warnf("No config for notification: %s", notificationChannel.Name)
+		if page == nil || !page.NotifyIncidents {
 			continue
 		}
+		ids = append(ids, page.NotificationIDs...)
+	}
+	return ids
+}
+
+// acknowledged reports whether an acknowledgment suppresses the notification.
+// Any notification other than down means the monitor recovered, which ends the
+// acknowledgment so the next outage alerts again.
+func (l *NotificationEventListener) acknowledged(ctx context.Context, hb *heartbeat.Model) bool {
+	if l.ackService == nil {
+		return false
+	}
+
+	if hb.Status != shared.MonitorStatusDown {
+		if err := l.ackService.Clear(ctx, hb.MonitorID); err != nil {
+			l.logger.Errorf("Failed to clear acknowledgments of monitor %s: %v", hb.MonitorID, err)
+		}
+		return false
+	}
+
+	ack, err := l.ackService.FindActive(ctx, hb.MonitorID)
+	if err != nil {
+		// better to notify twice than to miss an outage
+		l.logger.Errorf("Failed to check acknowledgments of monitor %s: %v", hb.MonitorID, err)
+		return false
+	}
+	return ack != nil
+}
 
-		// validate config
-		if err := integration.Validate(*notificationChannel.Config); err != nil {
-			l.logger.Errorf("Failed to validate notification config: %s, error: %v", notificationChannel.Name, err)
+// deliverBatches sends the notifications collected during a coalescing window
+func (l *NotificationEventListener) deliverBatches(batches []*notificationBatch) {
+	ctx := context.Background()
+
+	for _, b := range batches {
+		if b.grouped {
+			message, groupMonitor, groupHeartbeat := groupedNotification(b)
+			l.logger.Infof("Coalesced %d notifications for channel: %s", len(b.notifications), b.channel.Name)
+			attempts, err := l.deliver(ctx, b.channel, message, groupMonitor, groupHeartbeat)
+			if err != nil {
+				// the failure belongs to every monitor of the group, not the stand-in
+				for _, n := range b.notifications {
+					l.recordFailure(ctx, b.channel, message, n.monitor, attempts, err)
+				}
+			}
 			continue
 		}
 
-		err := integration.Send(ctx, *notificationChannel.Config, hb.Msg, monitorModel, hb)
+		for _, n := range b.notifications {
+			l.send(ctx, b.channel, n.heartbeat.Msg, n.monitor, n.heartbeat)
+		}
+	}
+}
+
+// send delivers a single notification through the provider of the channel
+func (l *NotificationEventListener) send(ctx context.Context, notificationChannel *Model, message string, monitorModel *monitor.Model, hb *heartbeat.Model) {
+	attempts, err := l.deliver(ctx, notificationChannel, message, monitorModel, hb)
+	if err != nil {
+		l.recordFailure(ctx, notificationChannel, message, monitorModel, attempts, err)
+	}
+}
+
+// deliver sends the notification with retries. It returns the attempts made
+// and the last error once every attempt failed, nil when the channel cannot
+// send at all.
+func (l *NotificationEventListener) deliver(ctx context.Context, notificationChannel *Model, message string, monitorModel *monitor.Model, hb *heartbeat.Model) (int, error) {
+	integration, ok := GetNotificationChannelProvider(notificationChannel.Type)
+	if !ok {
+		l.logger.Warnf("No integration registered for notification type: %s", notificationChannel.Type)
+		return 0, nil
+	}
+	if notificationChannel.Config == nil {
+		l.logger.Warnf("No config for notification: %s", notificationChannel.Name)
+		return 0, nil
+	}
+
+	// validate config
+	if err := integration.Validate(*notificationChannel.Config); err != nil {
+		l.logger.Errorf("Failed to validate notification config: %s, error: %v", notificationChannel.Name, err)
+		return 0, nil
+	}
+
+	wait := l.wait
+	if wait == nil {
+		wait = waitContext
+	}
+	key := notificationChannel.ID + "|" + monitorModel.ID
+	seq := l.sequence.start(key)
+	defer l.sequence.done(key, seq)
+	attempts, err := sendWithRetry(ctx, l.retry, wait, func() error {
+		if l.sequence.superseded(key, seq) {
+			return errSuperseded
+		}
+		err := integration.Send(ctx, *notificationChannel.Config, message, monitorModel, hb)
 		if err != nil {
-			l.logger.Errorf("Failed to send notification: %s, error: %v", notificationChannel.Name, err)
-		} else {
-			l.logger.Infof("Notification sent to: %s for monitor: %s", notificationChannel.Name, monitorID)
+			l.logger.Warnf("Failed to send notification: %s, error: %v", notificationChannel.Name, err)
 		}
+		return err
+	})
+	if errors.Is(err, errSuperseded) {
+		l.logger.Infof("Dropping notification: %s for monitor: %s after %d attempts, superseded by a newer one", notificationChannel.Name, monitorModel.ID, attempts-1)
+		return attempts - 1, nil
+	}
+	if err != nil {
+		l.logger.Errorf("Giving up on notification: %s for monitor: %s after %d attempts, error: %v", notificationChannel.Name, monitorModel.ID, attempts, err)
+		return attempts, err
+	}
+	l.logger.Infof("Notification sent to: %s for monitor: %s", notificationChannel.Name, monitorModel.ID)
+	return attempts, nil
+}
+
+// recordFailure stores a send that failed every attempt for later inspection
+func (l *NotificationEventListener) recordFailure(ctx context.Context, notificationChannel *Model, message string, monitorModel *monitor.Model, attempts int, sendErr error) {
+	if l.failureService == nil {
+		return
+	}
+
+	failure := &notification_failure.Model{
+		NotificationChannelID: notificationChannel.ID,
+		ChannelName:           notificationChannel.Name,
+		ChannelType:           notificationChannel.Type,
+		Message:               message,
+		Error:                 sendErr.Error(),
+		Attempts:              attempts,
+	}
+	if monitorModel != nil {
+		failure.MonitorID = monitorModel.ID
+	}
+	// the send context may be what ended the attempts
+	if _, err := l.failureService.Record(context.WithoutCancel(ctx), failure); err != nil {
+		l.logger.Errorf("Failed to store notification failure: %s, error: %v", notificationChannel.Name, err)
 	}
 }
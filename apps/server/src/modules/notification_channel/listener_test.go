@@ -0,0 +1,147 @@
+package notification_channel
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_ack"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeAckService holds at most one acknowledgment per monitor
+type fakeAckService struct {
+	monitor_ack.Service
+	acks map[string]*monitor_ack.Model
+}
+
+func (s *fakeAckService) Acknowledge(ctx context.Context, monitorID string, acknowledgedBy string, duration time.Duration) (*monitor_ack.Model, error) {
+	now := time.Now().UTC()
+	ack := &monitor_ack.Model{MonitorID: monitorID, AcknowledgedBy: acknowledgedBy, AcknowledgedAt: now, ExpiresAt: now.Add(duration)}
+	s.acks[monitorID] = ack
+	return ack, nil
+}
+
+func (s *fakeAckService) FindActive(ctx context.Context, monitorID string) (*monitor_ack.Model, error) {
+	if ack, ok := s.acks[monitorID]; ok && ack.Active(time.Now().UTC()) {
+		return ack, nil
+	}
+	return nil, nil
+}
+
+func (s *fakeAckService) Clear(ctx context.Context, monitorID string) error {
+	if ack, ok := s.acks[monitorID]; ok && ack.ClearedAt == nil {
+		now := time.Now().UTC()
+		ack.ClearedAt = &now
+	}
+	return nil
+}
+
+func newAckTestListener(t *testing.T) (*NotificationEventListener, *recordingProvider, *fakeAckService) {
+	provider := &recordingProvider{}
+	RegisterNotificationChannelProvider("recording", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "recording") })
+
+	config := "on-call"
+	acks := &fakeAckService{acks: make(map[string]*monitor_ack.Model)}
+	listener := &NotificationEventListener{
+		service: &fakeChannels{channels: map[string]*Model{
+			"a": {ID: "a", Name: "On-call", Type: "recording", Config: &config},
+		}},
+		monitorSvc:                 &fakeMonitors{},
+		monitorNotificationService: &fakeMonitorNotifications{channelsByMonitor: map[string][]string{"api": {"a"}}},
+		ackService:                 acks,
+		logger:                     zap.NewNop().Sugar(),
+	}
+	return listener, provider, acks
+}
+
+func sentCount(provider *recordingProvider) int {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	return len(provider.sent)
+}
+
+func statusEvent(status heartbeat.MonitorStatus) events.Event {
+	return events.Event{
+		Type:    events.MonitorStatusChanged,
+		Payload: &heartbeat.Model{MonitorID: "api", Status: status, Msg: "check"},
+	}
+}
+
+func TestListener_AckSuppressesRenotifications(t *testing.T) {
+	listener, provider, acks := newAckTestListener(t)
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	if sentCount(provider) != 1 {
+		t.Fatalf("expected the outage to be notified, got %d notifications", sentCount(provider))
+	}
+
+	acks.Acknowledge(context.Background(), "api", "oncall@example.com", time.Hour)
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	if sentCount(provider) != 1 {
+		t.Errorf("expected re-notifications to be suppressed while acknowledged, got %d notifications", sentCount(provider))
+	}
+}
+
+func TestListener_AckExpiryRestoresNotifications(t *testing.T) {
+	listener, provider, acks := newAckTestListener(t)
+
+	ack, _ := acks.Acknowledge(context.Background(), "api", "oncall@example.com", time.Hour)
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	if sentCount(provider) != 0 {
+		t.Fatalf("expected the notification to be suppressed, got %d notifications", sentCount(provider))
+	}
+
+	ack.ExpiresAt = time.Now().UTC().Add(-time.Second)
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	if sentCount(provider) != 1 {
+		t.Errorf("expected notifications to resume once the acknowledgment expired, got %d notifications", sentCount(provider))
+	}
+}
+
+func TestListener_RecoveryClearsAck(t *testing.T) {
+	listener, provider, acks := newAckTestListener(t)
+
+	acks.Acknowledge(context.Background(), "api", "oncall@example.com", time.Hour)
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusUp))
+	if sentCount(provider) != 1 {
+		t.Fatalf("expected the recovery to be notified, got %d notifications", sentCount(provider))
+	}
+	if acks.acks["api"].ClearedAt == nil {
+		t.Fatal("expected the recovery to clear the acknowledgment")
+	}
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+	if sentCount(provider) != 2 {
+		t.Errorf("expected the next outage to be notified, got %d notifications", sentCount(provider))
+	}
+}
+
+func TestListener_NeverSucceededKeepsAck(t *testing.T) {
+	listener, provider, acks := newAckTestListener(t)
+
+	acks.Acknowledge(context.Background(), "api", "oncall@example.com", time.Hour)
+	listener.handleNeverSucceededEvent(events.Event{
+		Type:    events.MonitorNeverSucceeded,
+		Payload: &heartbeat.Model{MonitorID: "api", Status: shared.MonitorStatusPending, Msg: "never succeeded"},
+	})
+	if sentCount(provider) != 1 {
+		t.Fatalf("expected the pending monitor to be notified, got %d notifications", sentCount(provider))
+	}
+	if acks.acks["api"].ClearedAt != nil {
+		t.Error("expected the acknowledgment to be kept")
+	}
+
+	listener.handleNeverSucceededEvent(events.Event{
+		Type:    events.MonitorNeverSucceeded,
+		Payload: &heartbeat.Model{MonitorID: "api", Status: shared.MonitorStatusDown, Msg: "never succeeded"},
+	})
+	if sentCount(provider) != 1 {
+		t.Errorf("expected the acknowledged outage to be suppressed, got %d notifications", sentCount(provider))
+	}
+}
@@ -1,11 +1,11 @@
 package notification_channel
 
 import (
+	"context"
 	"net/http"
-	"peekaping/src/modules/heartbeat"
-	"peekaping/src/modules/monitor"
 	"peekaping/src/modules/shared"
 	"peekaping/src/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -87,14 +87,7 @@ func (ic *Controller) Create(ctx *gin.Context) {
 		return
 	}
 
-	integration, ok := GetNotificationChannelProvider(notification_channel.Type)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Unsupported notification type"))
-		return
-	}
-	err := integration.Validate(notification_channel.Config)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid config: "+err.Error()))
+	if !ic.validateConfig(ctx, notification_channel.Type, notification_channel.Config) {
 		return
 	}
 
@@ -162,6 +155,10 @@ func (ic *Controller) UpdateFull(ctx *gin.Context) {
 		return
 	}
 
+	if !ic.validateConfig(ctx, notification.Type, notification.Config) {
+		return
+	}
+
 	updatedNotification, err := ic.service.UpdateFull(ctx, id, &notification)
 	if err != nil {
 		ic.logger.Errorw("Failed to update notification", "error", err)
@@ -266,24 +263,186 @@ func (ic *Controller) Test(ctx *gin.Context) {
 		return
 	}
 
-	// Create a test message and monitor for the notification
-	testMessage := "This is a test notification from Peekaping"
-	testMonitor := &monitor.Model{
-		Name: "Test Monitor",
-		Type: "http",
+	// Send the test notification
+	err = integration.TestSend(ctx, notificationChannel.Config, shared.MonitorStatusDown)
+	if err != nil {
+		ic.logger.Errorw("Failed to send test notification", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Failed to send test notification: "+err.Error()))
+		return
 	}
-	testHeartbeat := &heartbeat.Model{
-		Status: shared.MonitorStatusDown,
-		Msg:    testMessage,
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Test notification sent successfully", nil))
+}
+
+// @Router		/notification-channels/{id}/test [post]
+// @Summary		Send a test notification through a saved channel
+// @Tags			Notification channels
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param       id   path      string  true  "Notification ID"
+// @Param     body body   TestSendDto  false  "Status of the synthetic event"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) TestByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var dto TestSendDto
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+			return
+		}
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	status := shared.MonitorStatusDown
+	if dto.Status == "up" {
+		status = shared.MonitorStatusUp
 	}
 
-	// Send the test notification
-	err = integration.Send(ctx, notificationChannel.Config, testMessage, testMonitor, testHeartbeat)
+	channel, err := ic.service.FindByID(ctx, id)
 	if err != nil {
-		ic.logger.Errorw("Failed to send test notification", "error", err)
+		ic.logger.Errorw("Failed to fetch notification", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if channel == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Notification not found"))
+		return
+	}
+
+	integration, ok := GetNotificationChannelProvider(channel.Type)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Unsupported notification type"))
+		return
+	}
+	config := ""
+	if channel.Config != nil {
+		config = *channel.Config
+	}
+
+	if err := integration.TestSend(ctx, config, status); err != nil {
+		ic.logger.Errorw("Failed to send test notification", "id", id, "error", err)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Failed to send test notification: "+err.Error()))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Test notification sent successfully", nil))
 }
+
+// @Router		/notification-channels/test-all [post]
+// @Summary		Send a test notification through every or the selected channels
+// @Tags			Notification channels
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param     body body   TestAllDto  false  "Channels to test"
+// @Success		200	{object}	utils.ApiResponse[TestAllReport]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) TestAll(ctx *gin.Context) {
+	var dto TestAllDto
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+			return
+		}
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	status := shared.MonitorStatusDown
+	if dto.Status == "up" {
+		status = shared.MonitorStatusUp
+	}
+	timeout := 30 * time.Second
+	if dto.TimeoutSeconds > 0 {
+		timeout = time.Duration(dto.TimeoutSeconds) * time.Second
+	}
+
+	channels, missing, err := ic.channelsToTest(ctx, dto.ChannelIDs)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch notifications", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	report := testChannels(ctx, channels, status, timeout)
+	for _, id := range missing {
+		report.Results = append(report.Results, ChannelTestResult{ChannelID: id, Error: "notification channel not found"})
+		report.Total++
+		report.Failed++
+	}
+	if report.Failed > 0 {
+		ic.logger.Warnw("Test notifications failed", "failed", report.Failed, "total", report.Total)
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", report))
+}
+
+// channelsToTest loads the selected channels, or every channel when none is
+// selected. ids that match no channel are returned as missing.
+func (ic *Controller) channelsToTest(ctx context.Context, ids []string) ([]*Model, []string, error) {
+	if len(ids) == 0 {
+		var channels []*Model
+		for page := 0; ; page++ {
+			batch, err := ic.service.FindAll(ctx, page, channelsPageSize, "")
+			if err != nil {
+				return nil, nil, err
+			}
+			channels = append(channels, batch...)
+			if len(batch) < channelsPageSize {
+				return channels, nil, nil
+			}
+		}
+	}
+
+	var channels []*Model
+	var missing []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		channel, err := ic.service.FindByID(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if channel == nil {
+			missing = append(missing, id)
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	return channels, missing, nil
+}
+
+// validateConfig checks the config of a channel being saved, and against the
+// API of the provider when it supports that. It responds when the config is
+// invalid.
+func (ic *Controller) validateConfig(ctx *gin.Context, notificationType string, config string) bool {
+	integration, ok := GetNotificationChannelProvider(notificationType)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Unsupported notification type"))
+		return false
+	}
+	if err := integration.Validate(config); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid config: "+err.Error()))
+		return false
+	}
+	if verifier, ok := integration.(ConfigVerifier); ok {
+		if err := verifier.Verify(ctx, config); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid config: "+err.Error()))
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package notification_channel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// testSendProvider records the status of every test send and fails when err is set
+type testSendProvider struct {
+	configs  []string
+	statuses []heartbeat.MonitorStatus
+	err      error
+}
+
+func (p *testSendProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	return p.err
+}
+
+func (p *testSendProvider) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	p.configs = append(p.configs, configJSON)
+	p.statuses = append(p.statuses, status)
+	return p.err
+}
+
+func (p *testSendProvider) Validate(configJSON string) error { return nil }
+
+func (p *testSendProvider) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func newTestByIDRouter(t *testing.T, provider *testSendProvider) *gin.Engine {
+	RegisterNotificationChannelProvider("test-send", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "test-send") })
+
+	config := `{"webhook_url":"https://example.com"}`
+	controller := NewController(&fakeChannels{channels: map[string]*Model{
+		"a": {ID: "a", Name: "On-call", Type: "test-send", Config: &config},
+	}}, zap.NewNop().Sugar())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/notification-channels/:id/test", controller.TestByID)
+	return router
+}
+
+func postTest(router *gin.Engine, id, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/notification-channels/"+id+"/test", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestController_TestByID(t *testing.T) {
+	provider := &testSendProvider{}
+	router := newTestByIDRouter(t, provider)
+
+	if recorder := postTest(router, "a", ""); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the test to succeed, got %d: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := postTest(router, "a", `{"status":"up"}`); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the test to succeed, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if len(provider.statuses) != 2 || provider.statuses[0] != shared.MonitorStatusDown || provider.statuses[1] != shared.MonitorStatusUp {
+		t.Errorf("expected a DOWN then an UP test event, got %v", provider.statuses)
+	}
+	if provider.configs[0] != `{"webhook_url":"https://example.com"}` {
+		t.Errorf("expected the stored config to be used, got %s", provider.configs[0])
+	}
+}
+
+func TestController_TestByID_Errors(t *testing.T) {
+	provider := &testSendProvider{err: errors.New("404 Not Found: no_team")}
+	router := newTestByIDRouter(t, provider)
+
+	recorder := postTest(router, "a", "")
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), "no_team") {
+		t.Errorf("expected the provider error to be returned, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if recorder := postTest(router, "missing", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown channel to be not found, got %d", recorder.Code)
+	}
+	if recorder := postTest(router, "a", `{"status":"pending"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected an unsupported status to be rejected, got %d", recorder.Code)
+	}
+	if len(provider.statuses) != 1 {
+		t.Errorf("expected only the valid request to send, got %d sends", len(provider.statuses))
+	}
+}
@@ -8,6 +8,20 @@ type CreateUpdateDto struct {
 	Config    string `json:"config"`
 }
 
+type TestSendDto struct {
+	// Status of the synthetic event, DOWN by default
+	Status string `json:"status" validate:"omitempty,oneof=up down" example:"down"`
+}
+
+type TestAllDto struct {
+	// Channels to test, every configured channel when empty
+	ChannelIDs []string `json:"channel_ids" validate:"omitempty,dive,required"`
+	// Status of the synthetic event, DOWN by default
+	Status string `json:"status" validate:"omitempty,oneof=up down" example:"down"`
+	// Time limit of each send, 30 seconds by default
+	TimeoutSeconds int `json:"timeout_seconds" validate:"omitempty,min=1,max=120" example:"30"`
+}
+
 type PartialUpdateDto struct {
 	Name      string `json:"name"`
 	Type      string `json:"type"`
@@ -32,8 +32,10 @@ func (uc *Route) ConnectRoute(
 	router.GET("", controller.FindAll)
 	router.POST("", controller.Create)
 	router.POST("/test", controller.Test)
+	router.POST("/test-all", controller.TestAll)
 	router.GET("/:id", controller.FindByID)
 	router.PUT("/:id", controller.UpdateFull)
 	router.PATCH("/:id", controller.UpdatePartial)
 	router.DELETE("/:id", controller.Delete)
+	router.POST("/:id/test", controller.TestByID)
 }
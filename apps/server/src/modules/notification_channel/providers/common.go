@@ -3,9 +3,12 @@ package providers
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
 	"peekaping/src/utils"
+	"strings"
+	"time"
 )
 
 func GenericValidator[T any](cfg *T) error {
@@ -45,6 +48,8 @@ func PrepareTemplateBindings(monitor *monitor.Model, heartbeat *heartbeat.Model,
 		if name, ok := monitorJSON["name"].(string); ok {
 			bindings["name"] = name
 		}
+		bindings["runbook_url"] = monitor.RunbookURL
+		bindings["notes"] = monitor.Notes
 	}
 
 	if heartbeat != nil {
@@ -61,6 +66,92 @@ func PrepareTemplateBindings(monitor *monitor.Model, heartbeat *heartbeat.Model,
 	return bindings
 }
 
+// statusTitle names the monitor and its new status, empty when the
+// notification is not about a status change
+func statusTitle(m *monitor.Model, hb *heartbeat.Model) string {
+	if m == nil || hb == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s is %s", m.Name, humanReadableStatus(int(hb.Status)))
+}
+
+// monitorTarget returns what the monitor checks, its URL or host, empty when
+// the monitor type has neither
+func monitorTarget(m *monitor.Model) string {
+	if m == nil || m.Config == "" {
+		return ""
+	}
+	var cfg struct {
+		URL      string `json:"url"`
+		Host     string `json:"host"`
+		Hostname string `json:"hostname"`
+		Port     any    `json:"port"`
+	}
+	if err := json.Unmarshal([]byte(m.Config), &cfg); err != nil {
+		return ""
+	}
+	if cfg.URL != "" {
+		return cfg.URL
+	}
+	host := cfg.Host
+	if host == "" {
+		host = cfg.Hostname
+	}
+	if port := fmt.Sprint(cfg.Port); host != "" && cfg.Port != nil && port != "" && port != "0" {
+		return host + ":" + port
+	}
+	return host
+}
+
+// runbookFooter returns the runbook link and notes of the monitor as plain
+// text to append to a message, empty when the monitor has neither
+func runbookFooter(m *monitor.Model) string {
+	if m == nil {
+		return ""
+	}
+	var b strings.Builder
+	if m.RunbookURL != "" {
+		b.WriteString("\n\nRunbook: " + m.RunbookURL)
+	}
+	if m.Notes != "" {
+		b.WriteString("\n\n" + m.Notes)
+	}
+	return b.String()
+}
+
+// truncateText shortens text to at most limit runes for length limited fields
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// testEvent builds the synthetic event providers send when a channel is tested
+func testEvent(status heartbeat.MonitorStatus) (string, *monitor.Model, *heartbeat.Model) {
+	message := fmt.Sprintf("This is a test notification from Peekaping, the monitor is %s", humanReadableStatus(int(status)))
+	testMonitor := &monitor.Model{
+		Name: "Test Monitor",
+		Type: "http",
+	}
+	testHeartbeat := &heartbeat.Model{
+		Status: status,
+		Msg:    message,
+		Time:   time.Now().UTC(),
+	}
+	return message, testMonitor, testHeartbeat
+}
+
+// validateServerURL checks that a self-hosted server is addressed by an http(s) URL
+func validateServerURL(field, serverURL string) error {
+	u, err := url.Parse(serverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL", field)
+	}
+	return nil
+}
+
 func humanReadableStatus(status int) string {
 	switch status {
 	case 0:
@@ -9,6 +9,9 @@ import (
 	"net/url"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -16,12 +19,21 @@ import (
 type DiscordConfig struct {
 	WebhookURL          string `json:"webhook_url" validate:"required,url"`
 	BotDisplayName      string `json:"bot_display_name"`
+	BotAvatarURL        string `json:"bot_avatar_url" validate:"omitempty,url"`
 	CustomMessagePrefix string `json:"custom_message_prefix"`
 	MessageType         string `json:"message_type" validate:"omitempty,oneof=send_to_channel send_to_new_forum_post send_to_thread"`
 	ThreadName          string `json:"thread_name"`
 	ThreadID            string `json:"thread_id"`
 }
 
+// Embed colors of the statuses, other statuses are sent without a color
+var discordStatusColors = map[shared.MonitorStatus]int{
+	shared.MonitorStatusDown:        0xE74C3C,
+	shared.MonitorStatusUp:          0x2ECC71,
+	shared.MonitorStatusPending:     0xF1C40F,
+	shared.MonitorStatusMaintenance: 0x3498DB,
+}
+
 type DiscordSender struct {
 	logger *zap.SugaredLogger
 }
@@ -40,7 +52,73 @@ func (s *DiscordSender) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*DiscordConfig))
+	discordCfg := cfg.(*DiscordConfig)
+	if err := GenericValidator(discordCfg); err != nil {
+		return err
+	}
+	if _, err := url.Parse(discordCfg.WebhookURL); err != nil {
+		return fmt.Errorf("webhook_url must be a valid URL")
+	}
+	return nil
+}
+
+// Verify checks the shape of the webhook URL when the channel is saved, a
+// channel saved before the check still sends
+func (s *DiscordSender) Verify(ctx context.Context, configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	discordCfg := cfg.(*DiscordConfig)
+
+	webhookURL, err := url.Parse(discordCfg.WebhookURL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return fmt.Errorf("webhook_url must be an http or https URL")
+	}
+	// webhooks are /api/webhooks/{id}/{token}, optionally behind a versioned api path
+	parts := strings.Split(strings.Trim(webhookURL.Path, "/"), "/")
+	if len(parts) < 4 || parts[len(parts)-3] != "webhooks" || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return fmt.Errorf("webhook_url must be a Discord webhook URL like https://discord.com/api/webhooks/{id}/{token}")
+	}
+	return nil
+}
+
+// buildEmbed describes a status change, the message of the check is the description
+func (s *DiscordSender) buildEmbed(monitor *monitor.Model, heartbeat *heartbeat.Model) map[string]any {
+	status := humanReadableStatus(int(heartbeat.Status))
+	name := heartbeat.MonitorID
+	if monitor != nil {
+		name = monitor.Name
+	}
+
+	timestamp := heartbeat.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	embed := map[string]any{
+		"title":       fmt.Sprintf("%s is %s", name, status),
+		"description": heartbeat.Msg,
+		"timestamp":   timestamp.UTC().Format(time.RFC3339),
+		"fields": []map[string]any{
+			{"name": "Monitor", "value": name, "inline": true},
+			{"name": "Status", "value": status, "inline": true},
+		},
+	}
+	if monitor != nil && monitor.RunbookURL != "" {
+		embed["url"] = monitor.RunbookURL
+		embed["fields"] = append(embed["fields"].([]map[string]any),
+			map[string]any{"name": "Runbook", "value": monitor.RunbookURL})
+	}
+	if monitor != nil && monitor.Notes != "" {
+		// embed field values are limited to 1024 characters
+		embed["fields"] = append(embed["fields"].([]map[string]any),
+			map[string]any{"name": "Notes", "value": truncateText(monitor.Notes, 1024)})
+	}
+	if color, ok := discordStatusColors[heartbeat.Status]; ok {
+		embed["color"] = color
+	}
+	return embed
 }
 
 func (s *DiscordSender) Send(
@@ -65,18 +143,20 @@ func (s *DiscordSender) Send(
 		finalMessage = cfg.CustomMessagePrefix + " " + finalMessage
 	}
 
-	// Prepare Discord webhook payload
-	var payload map[string]interface{}
-
-	if cfg.MessageType == "send_to_new_forum_post" {
-		payload = map[string]interface{}{
-			"content":     finalMessage,
-			"thread_name": cfg.ThreadName,
+	// Prepare Discord webhook payload, status changes are sent as an embed
+	// with the prefix as content so mentions in it still notify
+	payload := map[string]interface{}{}
+	if heartbeat != nil {
+		payload["embeds"] = []map[string]any{s.buildEmbed(monitor, heartbeat)}
+		if cfg.CustomMessagePrefix != "" {
+			payload["content"] = cfg.CustomMessagePrefix
 		}
 	} else {
-		payload = map[string]interface{}{
-			"content": finalMessage,
-		}
+		payload["content"] = finalMessage
+	}
+
+	if cfg.MessageType == "send_to_new_forum_post" {
+		payload["thread_name"] = cfg.ThreadName
 	}
 
 	if cfg.MessageType == "send_to_thread" {
@@ -93,10 +173,13 @@ func (s *DiscordSender) Send(
 		}
 	}
 
-	// Add bot display name if provided
+	// Override the bot name and avatar if provided
 	if cfg.BotDisplayName != "" {
 		payload["username"] = cfg.BotDisplayName
 	}
+	if cfg.BotAvatarURL != "" {
+		payload["avatar_url"] = cfg.BotAvatarURL
+	}
 
 	// Convert payload to JSON
 	jsonPayload, err := json.Marshal(payload)
@@ -129,3 +212,9 @@ func (s *DiscordSender) Send(
 	s.logger.Infof("Discord message sent successfully")
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (s *DiscordSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return s.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
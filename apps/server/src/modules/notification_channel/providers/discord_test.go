@@ -0,0 +1,192 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestDiscordConfig_Validate(t *testing.T) {
+	sender := NewDiscordSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "webhook url", config: `{"webhook_url": "https://discord.com/api/webhooks/123/abc"}`},
+		{name: "webhook url with overrides", config: `{"webhook_url": "https://discord.com/api/webhooks/123/abc", "bot_display_name": "Peekaping", "bot_avatar_url": "https://example.com/avatar.png"}`},
+		// the shape of the webhook is only checked when the channel is saved
+		{name: "not a webhook", config: `{"webhook_url": "https://discord.com/channels/123"}`},
+		{name: "missing webhook url", config: `{"bot_display_name": "Peekaping"}`, wantErr: true},
+		{name: "not a url", config: `{"webhook_url": "discord webhook"}`, wantErr: true},
+		{name: "invalid avatar", config: `{"webhook_url": "https://discord.com/api/webhooks/123/abc", "bot_avatar_url": "avatar.png"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiscordSender_Verify(t *testing.T) {
+	sender := NewDiscordSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "webhook url", config: `{"webhook_url": "https://discord.com/api/webhooks/123/abc"}`},
+		{name: "versioned webhook url", config: `{"webhook_url": "https://discord.com/api/v10/webhooks/123/abc"}`},
+		{name: "not an http url", config: `{"webhook_url": "ftp://discord.com/api/webhooks/123/abc"}`, wantErr: true},
+		{name: "not a webhook", config: `{"webhook_url": "https://discord.com/channels/123"}`, wantErr: true},
+		{name: "missing token", config: `{"webhook_url": "https://discord.com/api/webhooks/123/"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Verify(context.Background(), tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a verification error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected verification error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiscordSender_Send_Embed(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewDiscordSender(zap.NewNop().Sugar())
+	configJSON, _ := json.Marshal(map[string]any{
+		"webhook_url":      server.URL + "/api/webhooks/123/abc",
+		"bot_display_name": "Peekaping",
+		"bot_avatar_url":   "https://example.com/avatar.png",
+	})
+	checkedAt := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status shared.MonitorStatus
+		title  string
+		color  float64
+	}{
+		{status: shared.MonitorStatusDown, title: "API is DOWN", color: 0xE74C3C},
+		{status: shared.MonitorStatusUp, title: "API is UP", color: 0x2ECC71},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			err := sender.Send(
+				context.Background(),
+				string(configJSON),
+				"connection refused",
+				&monitor.Model{ID: "m1", Name: "API"},
+				&heartbeat.Model{MonitorID: "m1", Status: tt.status, Msg: "connection refused", Time: checkedAt},
+			)
+			if err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			if payload["username"] != "Peekaping" || payload["avatar_url"] != "https://example.com/avatar.png" {
+				t.Errorf("expected the username and avatar overrides, got %v", payload)
+			}
+			if _, ok := payload["content"]; ok {
+				t.Errorf("expected no content without a prefix, got %v", payload["content"])
+			}
+
+			embed := payload["embeds"].([]any)[0].(map[string]any)
+			if embed["title"] != tt.title || embed["color"] != tt.color {
+				t.Errorf("expected title %q with color %v, got %v", tt.title, tt.color, embed)
+			}
+			if embed["description"] != "connection refused" || embed["timestamp"] != "2025-07-20T12:00:00Z" {
+				t.Errorf("expected the check message and time, got %v", embed)
+			}
+		})
+	}
+}
+
+func TestDiscordSender_Send_PlainMessage(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewDiscordSender(zap.NewNop().Sugar())
+	configJSON, _ := json.Marshal(map[string]any{
+		"webhook_url":           server.URL + "/api/webhooks/123/abc",
+		"custom_message_prefix": "@here",
+	})
+
+	if err := sender.Send(context.Background(), string(configJSON), "Test notification", nil, nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if payload["content"] != "@here Test notification" {
+		t.Errorf("expected the prefixed message as content, got %v", payload["content"])
+	}
+	if _, ok := payload["embeds"]; ok {
+		t.Error("expected no embed without a heartbeat")
+	}
+}
+
+func TestDiscordSender_Send_Runbook(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewDiscordSender(zap.NewNop().Sugar())
+	configJSON, _ := json.Marshal(map[string]any{"webhook_url": server.URL + "/api/webhooks/123/abc"})
+	m := &monitor.Model{ID: "m1", Name: "API", Notes: strings.Repeat("n", 2000), RunbookURL: "https://wiki.example.com/runbooks/api"}
+	hb := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown, Msg: "connection refused"}
+	if err := sender.Send(context.Background(), string(configJSON), "connection refused", m, hb); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	embed := payload["embeds"].([]any)[0].(map[string]any)
+	if embed["url"] != "https://wiki.example.com/runbooks/api" {
+		t.Errorf("expected the embed to link the runbook, got %v", embed["url"])
+	}
+	fields := map[string]string{}
+	for _, field := range embed["fields"].([]any) {
+		field := field.(map[string]any)
+		fields[field["name"].(string)] = field["value"].(string)
+	}
+	if fields["Runbook"] != "https://wiki.example.com/runbooks/api" {
+		t.Errorf("expected a runbook field, got %v", fields)
+	}
+	if len([]rune(fields["Notes"])) > 1024 {
+		t.Errorf("expected the notes to fit a field, got %d characters", len([]rune(fields["Notes"])))
+	}
+}
@@ -2,24 +2,38 @@ package providers
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"mime"
+	"net/mail"
 	"net/smtp"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/utils"
+	"strings"
+	"time"
 
 	liquid "github.com/osteele/liquid"
 	"go.uber.org/zap"
 )
 
+// emailTimeout bounds a whole SMTP session
+const emailTimeout = 30 * time.Second
+
 // EmailConfig holds the required EmailConfig for email notifications
 // Should match the JSON structure stored in the DB
-// Example JSON: {"smtp_host":"smtp.example.com","smtp_port":587,"username":"user","password":"pass","from":"noreply@example.com"}
+// Example JSON: {"smtp_host":"smtp.example.com","smtp_port":587,"smtp_security":"starttls","username":"user","password":"pass","from":"noreply@example.com","to":"ops@example.com, oncall@example.com"}
 type EmailConfig struct {
-	SMTPSecure    bool   `json:"smtp_secure"`
-	SMTPHost      string `json:"smtp_host" validate:"required"`
-	SMTPPort      int    `json:"smtp_port" validate:"required"`
-	SMTPUsername  string `json:"username" validate:"required"`
-	SMTPPassword  string `json:"password" validate:"required"`
+	// SMTPSecure is the legacy switch for implicit TLS, used when SMTPSecurity is not set
+	SMTPSecure   bool   `json:"smtp_secure"`
+	SMTPSecurity string `json:"smtp_security" validate:"omitempty,oneof=none starttls tls"`
+	SMTPHost     string `json:"smtp_host" validate:"required"`
+	SMTPPort     int    `json:"smtp_port" validate:"required,min=1,max=65535"`
+	// Credentials are optional for relays that accept unauthenticated mail
+	SMTPUsername        string `json:"username" validate:"required_with=SMTPPassword"`
+	SMTPPassword        string `json:"password"`
+	SMTPIgnoreTlsErrors bool   `json:"smtp_ignore_tls_errors"`
+	// Addresses, To, CC and BCC take comma separated lists
 	SMTPFrom      string `json:"from" validate:"required"`
 	SMTPTo        string `json:"to" validate:"required"`
 	SMTPCC        string `json:"cc"`
@@ -28,6 +42,96 @@ type EmailConfig struct {
 	CustomBody    string `json:"custom_body"`
 }
 
+// security returns the effective connection security mode
+func (c *EmailConfig) security() string {
+	if c.SMTPSecurity != "" {
+		return c.SMTPSecurity
+	}
+	if c.SMTPSecure {
+		return utils.SMTPSecurityTLS
+	}
+	return utils.SMTPSecurityStartTLS
+}
+
+// emailAddresses holds the parsed addresses of a message
+type emailAddresses struct {
+	from *mail.Address
+	to   []*mail.Address
+	cc   []*mail.Address
+	bcc  []*mail.Address
+}
+
+func parseAddressList(field, list string) ([]*mail.Address, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address list: %w", field, err)
+	}
+	return addresses, nil
+}
+
+func (c *EmailConfig) addresses() (*emailAddresses, error) {
+	from, err := mail.ParseAddress(c.SMTPFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	parsed := &emailAddresses{from: from}
+	if parsed.to, err = parseAddressList("to", c.SMTPTo); err != nil {
+		return nil, err
+	}
+	if len(parsed.to) == 0 {
+		return nil, fmt.Errorf("at least one to address is required")
+	}
+	if parsed.cc, err = parseAddressList("cc", c.SMTPCC); err != nil {
+		return nil, err
+	}
+	if parsed.bcc, err = parseAddressList("bcc", c.SMTPBCC); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// recipients are the envelope recipients, bcc included
+func (a *emailAddresses) recipients() []string {
+	var recipients []string
+	for _, list := range [][]*mail.Address{a.to, a.cc, a.bcc} {
+		for _, address := range list {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	return recipients
+}
+
+func joinAddresses(addresses []*mail.Address) string {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		formatted = append(formatted, address.String())
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// composeEmail builds a plain text message, bcc recipients are left out of the headers
+func composeEmail(addresses *emailAddresses, subject, body string, now time.Time) []byte {
+	// a rendered subject must not be able to add headers
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	var b strings.Builder
+	b.WriteString("From: " + addresses.from.String() + "\r\n")
+	b.WriteString("To: " + joinAddresses(addresses.to) + "\r\n")
+	if len(addresses.cc) > 0 {
+		b.WriteString("Cc: " + joinAddresses(addresses.cc) + "\r\n")
+	}
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
 type EmailSender struct {
 	logger *zap.SugaredLogger
 }
@@ -46,7 +150,12 @@ func (e *EmailSender) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*EmailConfig))
+	emailCfg := cfg.(*EmailConfig)
+	if err := GenericValidator(emailCfg); err != nil {
+		return err
+	}
+	_, err = emailCfg.addresses()
+	return err
 }
 
 func (e *EmailSender) Send(
@@ -62,28 +171,73 @@ func (e *EmailSender) Send(
 	}
 	cfg := cfgAny.(*EmailConfig)
 
+	addresses, err := cfg.addresses()
+	if err != nil {
+		return err
+	}
+
 	engine := liquid.NewEngine()
 
 	bindings := PrepareTemplateBindings(m, heartbeat, message)
 
 	finalSubject := "Peekaping Notification"
+	if m != nil && heartbeat != nil {
+		finalSubject = fmt.Sprintf("Peekaping: %s is %s", m.Name, humanReadableStatus(int(heartbeat.Status)))
+	}
 	if cfg.CustomSubject != "" {
 		if rendered, err := engine.ParseAndRenderString(cfg.CustomSubject, bindings); err == nil {
 			finalSubject = rendered
 		}
 	}
 
-	finalBody := message
+	finalBody := message + runbookFooter(m)
 	if cfg.CustomBody != "" {
 		if rendered, err := engine.ParseAndRenderString(cfg.CustomBody, bindings); err == nil {
 			finalBody = rendered
 		}
 	}
 
-	to := cfg.SMTPTo
-	auth := smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
-	msg := []byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\nFrom: %s\r\n\r\n%s", to, finalSubject, cfg.SMTPFrom, finalBody))
-	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
-	fmt.Println("Sending email to:", to, "from:", cfg.SMTPFrom, "subject:", finalSubject, "body:", finalBody)
-	return smtp.SendMail(addr, auth, cfg.SMTPFrom, []string{to}, msg)
+	client, err := utils.DialSMTP(ctx, cfg.SMTPHost, cfg.SMTPPort, cfg.security(), &tls.Config{InsecureSkipVerify: cfg.SMTPIgnoreTlsErrors}, emailTimeout)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if cfg.SMTPUsername != "" {
+		auth := smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	recipients := addresses.recipients()
+	e.logger.Infof("Sending email to %d recipients, subject: %s", len(recipients), finalSubject)
+
+	if err := client.Mail(addresses.from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected the sender: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := writer.Write(composeEmail(addresses, finalSubject, finalBody, time.Now())); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write the email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the email: %w", err)
+	}
+	return client.Quit()
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (e *EmailSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return e.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
 }
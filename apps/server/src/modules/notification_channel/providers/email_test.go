@@ -0,0 +1,279 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func testTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+// fakeSMTPServer accepts a single session and records the envelope
+type fakeSMTPServer struct {
+	listener net.Listener
+	tls      *tls.Config
+	startTLS bool
+
+	mu       sync.Mutex
+	secured  bool
+	authed   bool
+	from     string
+	rcpts    []string
+	data     string
+	sessions sync.WaitGroup
+}
+
+func newFakeSMTPServer(t *testing.T, implicitTLS, startTLS bool) *fakeSMTPServer {
+	t.Helper()
+	server := &fakeSMTPServer{tls: testTLSConfig(t), startTLS: startTLS}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, server.tls)
+		server.secured = true
+	}
+	server.listener = listener
+	t.Cleanup(func() { listener.Close() })
+
+	server.sessions.Add(1)
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer s.sessions.Done()
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		upper := strings.ToUpper(command)
+
+		s.mu.Lock()
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			if s.startTLS && !s.secured {
+				reply("250-fake")
+				reply("250-STARTTLS")
+			} else {
+				reply("250-fake")
+			}
+			reply("250 AUTH PLAIN")
+		case upper == "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				s.mu.Unlock()
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			s.secured = true
+		case strings.HasPrefix(upper, "AUTH"):
+			s.authed = true
+			reply("235 ok")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(command[len("MAIL FROM:"):], "<> ")
+			reply("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpts = append(s.rcpts, strings.Trim(command[len("RCPT TO:"):], "<> "))
+			reply("250 ok")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("250 ok")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func emailConfig(port int, extra map[string]any) string {
+	cfg := map[string]any{
+		"smtp_host":              "127.0.0.1",
+		"smtp_port":              port,
+		"smtp_ignore_tls_errors": true,
+		"username":               "peekaping",
+		"password":               "secret",
+		"from":                   "Peekaping <noreply@example.com>",
+		"to":                     "ops@example.com, Oncall <oncall@example.com>",
+		"cc":                     "lead@example.com",
+		"bcc":                    "audit@example.com",
+	}
+	for k, v := range extra {
+		cfg[k] = v
+	}
+	encoded, _ := json.Marshal(cfg)
+	return string(encoded)
+}
+
+func TestEmailConfig_Validate(t *testing.T) {
+	sender := NewEmailSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		extra   map[string]any
+		wantErr bool
+	}{
+		{name: "valid"},
+		{name: "without credentials", extra: map[string]any{"username": "", "password": ""}},
+		{name: "password without username", extra: map[string]any{"username": ""}, wantErr: true},
+		{name: "invalid from", extra: map[string]any{"from": "noreply"}, wantErr: true},
+		{name: "invalid recipient", extra: map[string]any{"to": "ops@example.com, oncall"}, wantErr: true},
+		{name: "invalid bcc", extra: map[string]any{"bcc": "audit"}, wantErr: true},
+		{name: "unknown security", extra: map[string]any{"smtp_security": "ssl"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(emailConfig(587, tt.extra))
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	tests := []struct {
+		name        string
+		implicitTLS bool
+		security    string
+	}{
+		{name: "starttls", security: "starttls"},
+		{name: "implicit tls", implicitTLS: true, security: "tls"},
+		{name: "legacy secure switch", implicitTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.implicitTLS, true)
+			extra := map[string]any{"smtp_security": tt.security, "custom_subject": "{{ name }} went {{ status }}"}
+			if tt.security == "" {
+				extra["smtp_secure"] = true
+			}
+
+			sender := NewEmailSender(zap.NewNop().Sugar())
+			err := sender.Send(
+				context.Background(),
+				emailConfig(server.port(), extra),
+				"connection refused",
+				&monitor.Model{ID: "m1", Name: "API"},
+				&heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown},
+			)
+			if err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			server.sessions.Wait()
+
+			if !server.secured || !server.authed {
+				t.Errorf("expected an authenticated TLS session, secured=%t authed=%t", server.secured, server.authed)
+			}
+			if server.from != "noreply@example.com" {
+				t.Errorf("expected the envelope sender, got %s", server.from)
+			}
+			want := "ops@example.com oncall@example.com lead@example.com audit@example.com"
+			if strings.Join(server.rcpts, " ") != want {
+				t.Errorf("expected recipients %s, got %v", want, server.rcpts)
+			}
+			for _, header := range []string{"Subject: API went DOWN\r\n", "To: <ops@example.com>, \"Oncall\" <oncall@example.com>\r\n", "Cc: <lead@example.com>\r\n"} {
+				if !strings.Contains(server.data, header) {
+					t.Errorf("expected header %q in %q", header, server.data)
+				}
+			}
+			if strings.Contains(server.data, "audit@example.com") {
+				t.Error("expected bcc recipients to stay out of the headers")
+			}
+		})
+	}
+}
+
+func TestEmailSender_Send_RequiresStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, false, false)
+	sender := NewEmailSender(zap.NewNop().Sugar())
+
+	err := sender.Send(context.Background(), emailConfig(server.port(), map[string]any{"smtp_security": "starttls"}), "Test", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not support STARTTLS") {
+		t.Fatalf("expected STARTTLS to be required, got %v", err)
+	}
+}
+
+func TestComposeEmail_SubjectCannotAddHeaders(t *testing.T) {
+	from, _ := mail.ParseAddress("noreply@example.com")
+	to, _ := mail.ParseAddress("ops@example.com")
+	data := string(composeEmail(&emailAddresses{from: from, to: []*mail.Address{to}}, "down\r\nBcc: evil@example.com", "body", time.Now()))
+
+	if strings.Contains(data, "\r\nBcc:") {
+		t.Errorf("expected the subject to stay on one line, got %q", data)
+	}
+}
@@ -98,22 +98,42 @@ func (g *GoogleChatSender) Send(
 		})
 	}
 
-	// Add button for monitor link if available
+	if m != nil && m.Notes != "" {
+		sectionWidgets = append(sectionWidgets, map[string]any{
+			"textParagraph": map[string]string{
+				"text": fmt.Sprintf("<b>Notes:</b>\n%s", m.Notes),
+			},
+		})
+	}
+
+	// Add buttons for the runbook and monitor links if available
+	buttons := []map[string]any{}
+	if m != nil && m.RunbookURL != "" {
+		buttons = append(buttons, map[string]any{
+			"text": "Open runbook",
+			"onClick": map[string]any{
+				"openLink": map[string]string{
+					"url": m.RunbookURL,
+				},
+			},
+		})
+	}
 	if m != nil && g.config.ClientURL != "" {
 		buttonURL := fmt.Sprintf("%s/monitors/%s", strings.TrimRight(g.config.ClientURL, "/"), m.ID)
 
+		buttons = append(buttons, map[string]any{
+			"text": "Visit Peekaping",
+			"onClick": map[string]any{
+				"openLink": map[string]string{
+					"url": buttonURL,
+				},
+			},
+		})
+	}
+	if len(buttons) > 0 {
 		sectionWidgets = append(sectionWidgets, map[string]any{
 			"buttonList": map[string][]map[string]any{
-				"buttons": {
-					{
-						"text": "Visit Peekaping",
-						"onClick": map[string]any{
-							"openLink": map[string]string{
-								"url": buttonURL,
-							},
-						},
-					},
-				},
+				"buttons": buttons,
 			},
 		})
 	}
@@ -166,3 +186,9 @@ func (g *GoogleChatSender) Send(
 	g.logger.Infof("Google Chat notification sent successfully")
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (g *GoogleChatSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return g.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
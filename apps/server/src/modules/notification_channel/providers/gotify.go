@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
 	"peekaping/src/version"
 	"strings"
 	"time"
@@ -21,8 +22,29 @@ type GotifyConfig struct {
 	ServerURL        string `json:"server_url" validate:"required,url"`
 	ApplicationToken string `json:"application_token" validate:"required"`
 	Priority         *int   `json:"priority" validate:"omitempty,min=0,max=10"`
-	Title            string `json:"title"`
-	CustomMessage    string `json:"custom_message"`
+	// Priority of DOWN notifications, 10 when not set
+	DownPriority  *int   `json:"down_priority" validate:"omitempty,min=0,max=10"`
+	Title         string `json:"title"`
+	CustomMessage string `json:"custom_message"`
+}
+
+const (
+	gotifyDefaultPriority     = 8
+	gotifyDefaultDownPriority = 10
+)
+
+// priority returns the priority for a heartbeat, DOWN is sent with the down priority
+func (c *GotifyConfig) priority(hb *heartbeat.Model) int {
+	if hb != nil && hb.Status == shared.MonitorStatusDown {
+		if c.DownPriority != nil {
+			return *c.DownPriority
+		}
+		return gotifyDefaultDownPriority
+	}
+	if c.Priority != nil {
+		return *c.Priority
+	}
+	return gotifyDefaultPriority
 }
 
 // GotifySender handles sending notifications to Gotify
@@ -50,7 +72,11 @@ func (g *GotifySender) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*GotifyConfig))
+	gotifyCfg := cfg.(*GotifyConfig)
+	if err := GenericValidator(gotifyCfg); err != nil {
+		return err
+	}
+	return validateServerURL("server_url", gotifyCfg.ServerURL)
 }
 
 // Send sends a notification to Gotify
@@ -76,6 +102,9 @@ func (g *GotifySender) Send(
 
 	// Set default title if not provided
 	title := "Peekaping"
+	if defaultTitle := statusTitle(monitor, heartbeat); defaultTitle != "" {
+		title = defaultTitle
+	}
 	if cfg.Title != "" {
 		// Use liquid templating for title
 		if rendered, err := engine.ParseAndRenderString(cfg.Title, bindings); err == nil {
@@ -98,11 +127,7 @@ func (g *GotifySender) Send(
 		}
 	}
 
-	// Set default priority if not specified
-	priority := 8 // Default priority
-	if cfg.Priority != nil {
-		priority = *cfg.Priority
-	}
+	priority := cfg.priority(heartbeat)
 
 	// Prepare request payload
 	payload := map[string]interface{}{
@@ -117,8 +142,8 @@ func (g *GotifySender) Send(
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	// Build request URL
-	requestURL := fmt.Sprintf("%s/message?token=%s", serverURL, cfg.ApplicationToken)
+	// the token goes in a header so it stays out of proxy logs and errors
+	requestURL := fmt.Sprintf("%s/message", serverURL)
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBuffer(jsonPayload))
@@ -128,6 +153,7 @@ func (g *GotifySender) Send(
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", cfg.ApplicationToken)
 	req.Header.Set("User-Agent", "Peekaping-Gotify/"+version.Version)
 
 	// Send request
@@ -146,3 +172,9 @@ func (g *GotifySender) Send(
 	g.logger.Infof("Gotify notification sent successfully to %s", serverURL)
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (g *GotifySender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return g.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestGotifyConfig_Validate(t *testing.T) {
+	sender := NewGotifySender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "server and token", config: `{"server_url": "https://gotify.example.com", "application_token": "AbCdEf"}`},
+		{name: "priorities", config: `{"server_url": "https://gotify.example.com", "application_token": "AbCdEf", "priority": 4, "down_priority": 9}`},
+		{name: "missing token", config: `{"server_url": "https://gotify.example.com"}`, wantErr: true},
+		{name: "not an http url", config: `{"server_url": "ws://gotify.example.com", "application_token": "AbCdEf"}`, wantErr: true},
+		{name: "down priority out of range", config: `{"server_url": "https://gotify.example.com", "application_token": "AbCdEf", "down_priority": 11}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGotifySender_Send_PriorityByStatus(t *testing.T) {
+	var messages []map[string]any
+	var paths, tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+		messages = append(messages, message)
+		paths = append(paths, r.URL.RequestURI())
+		tokens = append(tokens, r.Header.Get("X-Gotify-Key"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	configJSON, _ := json.Marshal(map[string]any{
+		"server_url":        server.URL,
+		"application_token": "AbCdEf",
+		"priority":          4,
+	})
+	sender := NewGotifySender(zap.NewNop().Sugar())
+	m := &monitor.Model{ID: "m1", Name: "API"}
+
+	for _, status := range []shared.MonitorStatus{shared.MonitorStatusDown, shared.MonitorStatusUp} {
+		if err := sender.Send(context.Background(), string(configJSON), "status changed", m, &heartbeat.Model{Status: status}); err != nil {
+			t.Fatalf("unexpected send error: %v", err)
+		}
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("expected two messages, got %d", len(messages))
+	}
+	if paths[0] != "/message" || tokens[0] != "AbCdEf" {
+		t.Errorf("expected the token in the header only, got %s with key %q", paths[0], tokens[0])
+	}
+	if messages[0]["title"] != "API is DOWN" || messages[1]["title"] != "API is UP" {
+		t.Errorf("expected status titles, got %v and %v", messages[0]["title"], messages[1]["title"])
+	}
+	if messages[0]["priority"] != float64(10) || messages[1]["priority"] != float64(4) {
+		t.Errorf("expected DOWN at the highest priority and UP at the configured one, got %v and %v", messages[0]["priority"], messages[1]["priority"])
+	}
+}
@@ -127,4 +127,10 @@ func (g *GrafanaOncallSender) Send(
 
 	g.logger.Infof("Grafana OnCall notification sent successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (g *GrafanaOncallSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return g.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -147,3 +147,9 @@ func (m *MatrixSender) Send(
 	m.logger.Infof("Matrix message sent successfully to room: %s", cfg.InternalRoomID)
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (m *MatrixSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return m.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -291,3 +291,9 @@ func (m *MattermostSender) sendMessage(ctx context.Context, webhookURL string, p
 	m.logger.Infof("Mattermost notification sent successfully")
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (m *MattermostSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return m.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
 	"peekaping/src/version"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,17 +17,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// ntfyTopicPattern matches the topic names ntfy accepts
+var ntfyTopicPattern = regexp.MustCompile(`^[-_A-Za-z0-9]{1,64}$`)
+
+// ntfyDefaultDownPriority is "urgent", the highest ntfy priority
+const ntfyDefaultDownPriority = 5
+
 type NTFYConfig struct {
-	ServerUrl          string `json:"server_url" validate:"required"`
+	ServerUrl          string `json:"server_url" validate:"required,url"`
 	Topic              string `json:"topic" validate:"required"`
 	AuthenticationType string `json:"authentication_type" validate:"required,oneof=none basic token"`
 	Username           string `json:"username"`
 	Password           string `json:"password"`
 	Token              string `json:"token"`
 	Priority           int    `json:"priority" validate:"min=1,max=5"`
-	Tags               string `json:"tags"`
-	Title              string `json:"title"`
-	CustomMessage      string `json:"custom_message"`
+	// Priority of DOWN notifications, urgent when not set
+	DownPriority  *int   `json:"down_priority" validate:"omitempty,min=1,max=5"`
+	Tags          string `json:"tags"`
+	Title         string `json:"title"`
+	CustomMessage string `json:"custom_message"`
 }
 
 type NTFYSender struct {
@@ -52,7 +62,31 @@ func (e *NTFYSender) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*NTFYConfig))
+	ntfyCfg := cfg.(*NTFYConfig)
+	if err := GenericValidator(ntfyCfg); err != nil {
+		return err
+	}
+	if err := validateServerURL("server_url", ntfyCfg.ServerUrl); err != nil {
+		return err
+	}
+	if !ntfyTopicPattern.MatchString(ntfyCfg.Topic) {
+		return fmt.Errorf("topic may only contain letters, digits, - and _ and be at most 64 characters long")
+	}
+	return nil
+}
+
+// priority returns the priority for a heartbeat, DOWN is sent with the down priority
+func (c *NTFYConfig) priority(hb *heartbeat.Model) int {
+	if hb != nil && hb.Status == shared.MonitorStatusDown {
+		if c.DownPriority != nil {
+			return *c.DownPriority
+		}
+		return ntfyDefaultDownPriority
+	}
+	if c.Priority == 0 {
+		return 3 // Default priority
+	}
+	return c.Priority
 }
 
 func (e *NTFYSender) Send(
@@ -83,6 +117,9 @@ func (e *NTFYSender) Send(
 
 	// Prepare title
 	finalTitle := "Peekaping Notification"
+	if title := statusTitle(m, heartbeat); title != "" {
+		finalTitle = title
+	}
 	if cfg.Title != "" {
 		if rendered, err := engine.ParseAndRenderString(cfg.Title, bindings); err == nil {
 			finalTitle = rendered
@@ -105,11 +142,7 @@ func (e *NTFYSender) Send(
 		}
 	}
 
-	// Set default priority if not specified
-	priority := cfg.Priority
-	if priority == 0 {
-		priority = 3 // Default priority
-	}
+	priority := cfg.priority(heartbeat)
 
 	// Prepare request URL
 	url := fmt.Sprintf("%s/%s", strings.TrimSuffix(cfg.ServerUrl, "/"), cfg.Topic)
@@ -181,3 +214,9 @@ func (e *NTFYSender) Send(
 	e.logger.Infof("NTFY notification sent successfully to %s", cfg.ServerUrl)
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (e *NTFYSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return e.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
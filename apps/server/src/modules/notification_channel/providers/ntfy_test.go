@@ -0,0 +1,100 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestNTFYConfig_Validate(t *testing.T) {
+	sender := NewNTFYSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "public server", config: `{"server_url": "https://ntfy.sh", "topic": "peekaping-alerts", "authentication_type": "none", "priority": 3}`},
+		{name: "token and down priority", config: `{"server_url": "http://ntfy.local:8080", "topic": "alerts", "authentication_type": "token", "token": "tk_abc", "priority": 2, "down_priority": 4}`},
+		{name: "server without scheme", config: `{"server_url": "ntfy.sh", "topic": "alerts", "authentication_type": "none", "priority": 3}`, wantErr: true},
+		{name: "not an http url", config: `{"server_url": "ftp://ntfy.sh", "topic": "alerts", "authentication_type": "none", "priority": 3}`, wantErr: true},
+		{name: "topic with slash", config: `{"server_url": "https://ntfy.sh", "topic": "team/alerts", "authentication_type": "none", "priority": 3}`, wantErr: true},
+		{name: "down priority out of range", config: `{"server_url": "https://ntfy.sh", "topic": "alerts", "authentication_type": "none", "priority": 3, "down_priority": 6}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNTFYSender_Send_PriorityByStatus(t *testing.T) {
+	type received struct {
+		path, title, priority, auth, body string
+	}
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, received{r.URL.Path, r.Header.Get("X-Title"), r.Header.Get("X-Priority"), r.Header.Get("Authorization"), string(body)})
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	configJSON, _ := json.Marshal(map[string]any{
+		"server_url":          server.URL + "/",
+		"topic":               "alerts",
+		"authentication_type": "token",
+		"token":               "tk_abc",
+		"priority":            2,
+	})
+	sender := NewNTFYSender(zap.NewNop().Sugar())
+	m := &monitor.Model{ID: "m1", Name: "API"}
+
+	for _, status := range []shared.MonitorStatus{shared.MonitorStatusDown, shared.MonitorStatusUp} {
+		if err := sender.Send(context.Background(), string(configJSON), "status changed", m, &heartbeat.Model{Status: status}); err != nil {
+			t.Fatalf("unexpected send error: %v", err)
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected two messages, got %d", len(requests))
+	}
+	down, up := requests[0], requests[1]
+	if down.path != "/alerts" || down.auth != "Bearer tk_abc" || down.body != "status changed" {
+		t.Errorf("unexpected request %+v", down)
+	}
+	if down.title != "API is DOWN" || up.title != "API is UP" {
+		t.Errorf("expected status titles, got %q and %q", down.title, up.title)
+	}
+	if down.priority != "5" || up.priority != "2" {
+		t.Errorf("expected DOWN to be urgent and UP to keep the configured priority, got %s and %s", down.priority, up.priority)
+	}
+}
+
+func TestNTFYConfig_priority(t *testing.T) {
+	downPriority := 4
+	cfg := &NTFYConfig{Priority: 1, DownPriority: &downPriority}
+
+	if p := cfg.priority(&heartbeat.Model{Status: shared.MonitorStatusDown}); p != 4 {
+		t.Errorf("expected the configured down priority, got %d", p)
+	}
+	if p := cfg.priority(nil); p != 1 {
+		t.Errorf("expected the configured priority for test notifications, got %d", p)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"peekaping/src/modules/monitor"
 	"peekaping/src/modules/shared"
 	"peekaping/src/version"
+	"regexp"
 	"time"
 
 	"go.uber.org/zap"
@@ -23,6 +24,9 @@ type OpsgenieConfig struct {
 	Priority int    `json:"priority" validate:"omitempty,min=1,max=5"`
 }
 
+// opsgenieAPIKeyPattern matches the API keys of Opsgenie integrations
+var opsgenieAPIKeyPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // OpsgenieSender handles sending notifications to Opsgenie
 type OpsgenieSender struct {
 	logger *zap.SugaredLogger
@@ -51,6 +55,24 @@ func (o *OpsgenieSender) Validate(configJSON string) error {
 	return GenericValidator(cfg.(*OpsgenieConfig))
 }
 
+// Verify checks the format of the API key when the channel is saved
+func (o *OpsgenieSender) Verify(ctx context.Context, configJSON string) error {
+	cfgAny, err := o.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	if !opsgenieAPIKeyPattern.MatchString(cfgAny.(*OpsgenieConfig).ApiKey) {
+		return fmt.Errorf("api_key must be the API key of an Opsgenie API integration")
+	}
+	return nil
+}
+
+// opsgenieAlias identifies the alert of a monitor. It is derived from the ID
+// so renaming a monitor does not orphan its open alert.
+func opsgenieAlias(monitor *monitor.Model) string {
+	return "peekaping-" + monitor.ID
+}
+
 // getOpsgenieURL returns the appropriate Opsgenie API URL based on region
 func (o *OpsgenieSender) getOpsgenieURL(region string) string {
 	switch region {
@@ -119,18 +141,20 @@ func (o *OpsgenieSender) sendTestNotification(ctx context.Context, cfg *Opsgenie
 
 // sendDownAlert sends an alert when monitor is down
 func (o *OpsgenieSender) sendDownAlert(ctx context.Context, cfg *OpsgenieConfig, baseURL, message string, monitor *monitor.Model, heartbeat *heartbeat.Model, textMsg string) error {
-	monitorName := "Unknown Monitor"
-	if monitor != nil {
-		monitorName = monitor.Name
+	if monitor == nil {
+		return fmt.Errorf("monitor is nil, cannot open alert")
 	}
 
 	data := map[string]any{
-		"message":     fmt.Sprintf("%s: %s", textMsg, monitorName),
-		"alias":       monitorName,
-		"description": message,
+		"message":     fmt.Sprintf("%s: %s", textMsg, monitor.Name),
+		"alias":       opsgenieAlias(monitor),
+		"description": truncateText(message+runbookFooter(monitor), 15000),
 		"source":      "Peekaping",
 		"priority":    o.getPriority(fmt.Sprintf("%d", cfg.Priority)),
 	}
+	if monitor.RunbookURL != "" {
+		data["details"] = map[string]string{"runbook_url": monitor.RunbookURL}
+	}
 
 	return o.postToOpsgenie(ctx, cfg, baseURL, data)
 }
@@ -142,7 +166,7 @@ func (o *OpsgenieSender) sendUpAlert(ctx context.Context, cfg *OpsgenieConfig, b
 	}
 
 	// Create close URL
-	closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", baseURL, url.QueryEscape(monitor.Name))
+	closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", baseURL, url.PathEscape(opsgenieAlias(monitor)))
 
 	data := map[string]any{
 		"source": "Peekaping",
@@ -184,3 +208,15 @@ func (o *OpsgenieSender) postToOpsgenie(ctx context.Context, cfg *OpsgenieConfig
 	o.logger.Infof("Successfully sent Opsgenie notification, status: %d", resp.StatusCode)
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+// PerMonitorIncidents keeps the notifications of every monitor apart, an
+// alert is closed through the alias of its monitor
+func (o *OpsgenieSender) PerMonitorIncidents() bool {
+	return true
+}
+
+func (o *OpsgenieSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return o.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
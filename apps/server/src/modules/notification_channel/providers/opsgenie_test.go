@@ -0,0 +1,84 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+// opsgenieRequest is a request the recording transport received
+type opsgenieRequest struct {
+	url  string
+	auth string
+	body map[string]any
+}
+
+// recordingTransport answers every request with 202 and keeps it
+type recordingTransport struct {
+	requests []opsgenieRequest
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	request := opsgenieRequest{url: r.URL.String(), auth: r.Header.Get("Authorization")}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&request.body)
+	}
+	rt.requests = append(rt.requests, request)
+	return &http.Response{StatusCode: http.StatusAccepted, Body: io.NopCloser(strings.NewReader(`{}`)), Header: http.Header{}}, nil
+}
+
+const opsgenieTestConfig = `{"region": "eu", "api_key": "0d3c4e5f-1a2b-4c3d-8e9f-0a1b2c3d4e5f", "priority": 2}`
+
+func TestOpsgenieSender_Verify(t *testing.T) {
+	sender := NewOpsgenieSender(zap.NewNop().Sugar())
+
+	if err := sender.Verify(context.Background(), opsgenieTestConfig); err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	if err := sender.Verify(context.Background(), `{"region": "us", "api_key": "not-a-key"}`); err == nil {
+		t.Fatal("expected a verification error for a malformed API key")
+	}
+}
+
+func TestOpsgenieSender_Send_OpensAndClosesByMonitorAlias(t *testing.T) {
+	transport := &recordingTransport{}
+	sender := NewOpsgenieSender(zap.NewNop().Sugar())
+	sender.client = &http.Client{Transport: transport}
+
+	m := &monitor.Model{ID: "m1", Name: "Public API"}
+	if err := sender.Send(context.Background(), opsgenieTestConfig, "API went down", m, &heartbeat.Model{Status: shared.MonitorStatusDown}); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+
+	// a renamed monitor still closes the alert it opened
+	m.Name = "API"
+	if err := sender.Send(context.Background(), opsgenieTestConfig, "API is back", m, &heartbeat.Model{Status: shared.MonitorStatusUp}); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("expected an open and a close request, got %d", len(transport.requests))
+	}
+	open, closing := transport.requests[0], transport.requests[1]
+	if open.url != "https://api.eu.opsgenie.com/v2/alerts" {
+		t.Errorf("unexpected open URL %s", open.url)
+	}
+	if open.body["alias"] != "peekaping-m1" || open.body["priority"] != "P2" {
+		t.Errorf("unexpected alert %v", open.body)
+	}
+	if open.auth != "GenieKey 0d3c4e5f-1a2b-4c3d-8e9f-0a1b2c3d4e5f" {
+		t.Errorf("unexpected authorization header %q", open.auth)
+	}
+	if closing.url != "https://api.eu.opsgenie.com/v2/alerts/peekaping-m1/close?identifierType=alias" {
+		t.Errorf("unexpected close URL %s", closing.url)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"peekaping/src/modules/monitor"
 	"peekaping/src/modules/shared"
 	"peekaping/src/version"
+	"regexp"
 	"strings"
 
 	"go.uber.org/zap"
@@ -24,6 +25,9 @@ type PagerDutyConfig struct {
 	AutoResolve    string `json:"pagerduty_auto_resolve"`
 }
 
+// pagerDutyRoutingKeyPattern matches the integration keys of the Events API v2
+var pagerDutyRoutingKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]{32}$`)
+
 // PagerDutySender handles sending notifications to PagerDuty
 type PagerDutySender struct {
 	logger *zap.SugaredLogger
@@ -47,6 +51,29 @@ func (p *PagerDutySender) Validate(configJSON string) error {
 	return GenericValidator(cfg.(*PagerDutyConfig))
 }
 
+// Verify checks the format of the integration key when the channel is saved,
+// PagerDuty offers no way to test a key without opening an incident
+func (p *PagerDutySender) Verify(ctx context.Context, configJSON string) error {
+	cfgAny, err := p.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*PagerDutyConfig)
+
+	if !pagerDutyRoutingKeyPattern.MatchString(cfg.IntegrationKey) {
+		return fmt.Errorf("pagerduty_integration_key must be the 32 character integration key of an Events API v2 integration")
+	}
+	if !strings.HasPrefix(cfg.IntegrationURL, "https://") {
+		return fmt.Errorf("pagerduty_integration_url must be an https URL")
+	}
+	return nil
+}
+
+// pagerDutyDedupKey groups every event of a monitor into one incident
+func pagerDutyDedupKey(monitor *monitor.Model) string {
+	return fmt.Sprintf("Peekaping/%s", monitor.ID)
+}
+
 // getMonitorURL extracts the URL from monitor for PagerDuty source field
 func (p *PagerDutySender) getMonitorURL(monitor *monitor.Model) string {
 	if monitor == nil {
@@ -98,12 +125,14 @@ func (p *PagerDutySender) getEventAction(heartbeat *heartbeat.Model, cfg *PagerD
 
 	switch heartbeat.Status {
 	case shared.MonitorStatusUp:
-		if cfg.AutoResolve == "acknowledge" {
+		switch cfg.AutoResolve {
+		case "acknowledge":
 			return "acknowledge"
-		} else if cfg.AutoResolve == "resolve" {
+		case "", "resolve":
+			// channels saved before the option existed resolve as well
 			return "resolve"
 		}
-		return "" // No action for UP status unless auto-resolve is configured
+		return "" // "0" keeps the incident open
 	case shared.MonitorStatusDown:
 		return "trigger"
 	default:
@@ -183,7 +212,14 @@ func (p *PagerDutySender) Send(
 		},
 		"routing_key":  cfg.IntegrationKey,
 		"event_action": eventAction,
-		"dedup_key":    fmt.Sprintf("Peekaping/%s", monitor.ID),
+		"dedup_key":    pagerDutyDedupKey(monitor),
+	}
+
+	if monitor != nil && monitor.RunbookURL != "" {
+		payload["links"] = []map[string]string{{"href": monitor.RunbookURL, "text": "Runbook"}}
+	}
+	if monitor != nil && monitor.Notes != "" {
+		payload["payload"].(map[string]any)["custom_details"] = map[string]string{"notes": monitor.Notes}
 	}
 
 	// Add client information if base URL is available
@@ -227,3 +263,15 @@ func (p *PagerDutySender) Send(
 	p.logger.Infof("PagerDuty notification sent successfully")
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+// PerMonitorIncidents keeps the notifications of every monitor apart, an
+// incident is resolved through the dedup key of its monitor
+func (p *PagerDutySender) PerMonitorIncidents() bool {
+	return true
+}
+
+func (p *PagerDutySender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return p.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -1,9 +1,13 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"peekaping/src/config"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
 	"peekaping/src/modules/shared"
@@ -257,3 +261,74 @@ func TestPagerDutyPayload_Structure(t *testing.T) {
 		t.Error("Root should contain 'dedup_key' field")
 	}
 }
+
+func TestPagerDutySender_getEventAction_ResolvesByDefault(t *testing.T) {
+	sender := NewPagerDutySender(zap.NewNop().Sugar(), nil)
+
+	action := sender.getEventAction(&heartbeat.Model{Status: shared.MonitorStatusUp}, &PagerDutyConfig{})
+	if action != "resolve" {
+		t.Errorf("Expected action 'resolve' for UP status without an auto-resolve setting, got '%s'", action)
+	}
+}
+
+func TestPagerDutySender_Verify(t *testing.T) {
+	sender := NewPagerDutySender(zap.NewNop().Sugar(), nil)
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "events api v2 key", config: `{"pagerduty_integration_key": "R0123456789abcdefABCDEF012345678", "pagerduty_integration_url": "https://events.pagerduty.com/v2/enqueue"}`},
+		{name: "short key", config: `{"pagerduty_integration_key": "test-key-123", "pagerduty_integration_url": "https://events.pagerduty.com/v2/enqueue"}`, wantErr: true},
+		{name: "plain http", config: `{"pagerduty_integration_key": "R0123456789abcdefABCDEF012345678", "pagerduty_integration_url": "http://events.pagerduty.com/v2/enqueue"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Verify(context.Background(), tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a verification error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected verification error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPagerDutySender_Send_TriggerAndResolveShareDedupKey(t *testing.T) {
+	var events []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewPagerDutySender(zap.NewNop().Sugar(), &config.Config{})
+	configJSON, _ := json.Marshal(map[string]any{
+		"pagerduty_integration_key": "R0123456789abcdefABCDEF012345678",
+		"pagerduty_integration_url": server.URL,
+	})
+	m := &monitor.Model{ID: "m1", Name: "API"}
+
+	for _, status := range []shared.MonitorStatus{shared.MonitorStatusDown, shared.MonitorStatusUp} {
+		if err := sender.Send(context.Background(), string(configJSON), "status changed", m, &heartbeat.Model{Status: status}); err != nil {
+			t.Fatalf("unexpected send error: %v", err)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected a trigger and a resolve event, got %d events", len(events))
+	}
+	if events[0]["event_action"] != "trigger" || events[1]["event_action"] != "resolve" {
+		t.Errorf("Expected trigger then resolve, got %v and %v", events[0]["event_action"], events[1]["event_action"])
+	}
+	if events[0]["dedup_key"] != "Peekaping/m1" || events[1]["dedup_key"] != events[0]["dedup_key"] {
+		t.Errorf("Expected both events to use the dedup key of the monitor, got %v and %v", events[0]["dedup_key"], events[1]["dedup_key"])
+	}
+}
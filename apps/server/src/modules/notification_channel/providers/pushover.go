@@ -140,4 +140,10 @@ func (p *PushoverSender) Send(
 
 	p.logger.Infof("Pushover notification sent successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (p *PushoverSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return p.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -118,4 +118,10 @@ func (s *SignalSender) Send(
 
 	s.logger.Infof("Signal message sent successfully to %s", cfg.SignalURL)
 	return nil
-}
\ No newline at end of file
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (s *SignalSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return s.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
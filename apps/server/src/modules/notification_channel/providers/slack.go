@@ -48,7 +48,17 @@ func (s *SlackSender) Validate(configJSON string) error {
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*SlackConfig))
+	slackCfg := cfg.(*SlackConfig)
+	if err := GenericValidator(slackCfg); err != nil {
+		return err
+	}
+
+	// the url rule accepts any scheme, the webhook is always posted over http(s)
+	webhookURL, err := url.Parse(slackCfg.WebhookURL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return fmt.Errorf("slack_webhook_url must be an http or https URL")
+	}
+	return nil
 }
 
 // extractAddress extracts the URL from monitor for "Visit site" button
@@ -82,6 +92,18 @@ func (s *SlackSender) buildActions(baseURL string, monitor *monitor.Model) []map
 		})
 	}
 
+	if monitor != nil && monitor.RunbookURL != "" {
+		actions = append(actions, map[string]any{
+			"type": "button",
+			"text": map[string]any{
+				"type": "plain_text",
+				"text": "Open runbook",
+			},
+			"value": "Runbook",
+			"url":   monitor.RunbookURL,
+		})
+	}
+
 	// Add "Visit site" button if monitor has a valid address
 	address := s.extractAddress(monitor)
 	if address != "" {
@@ -114,13 +136,24 @@ func (s *SlackSender) buildBlocks(baseURL string, monitor *monitor.Model, heartb
 		},
 	})
 
-	// Section block with message and time
-	fields := []map[string]any{
-		{
+	// Section block with monitor, status, message and time
+	fields := []map[string]any{}
+	if monitor != nil {
+		fields = append(fields, map[string]any{
 			"type": "mrkdwn",
-			"text": "*Message*\n" + msg,
-		},
+			"text": "*Monitor*\n" + monitor.Name,
+		})
 	}
+	if heartbeat != nil {
+		fields = append(fields, map[string]any{
+			"type": "mrkdwn",
+			"text": "*Status*\n" + humanReadableStatus(int(heartbeat.Status)),
+		})
+	}
+	fields = append(fields, map[string]any{
+		"type": "mrkdwn",
+		"text": "*Message*\n" + msg,
+	})
 
 	// Add time field if heartbeat is available
 	if heartbeat != nil {
@@ -136,6 +169,18 @@ func (s *SlackSender) buildBlocks(baseURL string, monitor *monitor.Model, heartb
 		"fields": fields,
 	})
 
+	// Notes of the monitor for whoever handles the alert, section text is
+	// limited to 3000 characters
+	if monitor != nil && monitor.Notes != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{
+				"type": "mrkdwn",
+				"text": truncateText("*Notes*\n"+monitor.Notes, 3000),
+			},
+		})
+	}
+
 	// Actions block with buttons
 	actions := s.buildActions(baseURL, monitor)
 	if len(actions) > 0 {
@@ -207,9 +252,15 @@ func (s *SlackSender) Send(
 		messageText += " <!channel>"
 	}
 
+	// Plain messages carry the runbook as text, rich messages as a button
+	plainText := messageText
+	if !cfg.UseTemplate || cfg.Template == "" {
+		plainText += runbookFooter(monitor)
+	}
+
 	// Prepare Slack payload
 	payload := map[string]any{
-		"text": messageText,
+		"text": plainText,
 	}
 
 	// Set optional parameters
@@ -228,9 +279,17 @@ func (s *SlackSender) Send(
 	// Handle rich message format
 	if cfg.RichMessage && heartbeat != nil {
 		title := "Peekaping Alert"
+		if monitor != nil {
+			title = fmt.Sprintf("%s is %s", monitor.Name, humanReadableStatus(int(heartbeat.Status)))
+		}
+
+		baseURL := ""
+		if s.config != nil {
+			baseURL = s.config.ClientURL
+		}
 
 		// Use blocks for modern Slack message format
-		blocks := s.buildBlocks(s.config.ClientURL, monitor, heartbeat, title, messageText)
+		blocks := s.buildBlocks(baseURL, monitor, heartbeat, title, messageText)
 
 		attachment := map[string]any{
 			"color":  s.getStatusColor(heartbeat.Status),
@@ -276,3 +335,9 @@ func (s *SlackSender) Send(
 	s.logger.Infof("Slack message sent successfully")
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (s *SlackSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return s.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"peekaping/src/config"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestSlackConfig_Validate(t *testing.T) {
+	sender := NewSlackSender(zap.NewNop().Sugar(), nil)
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "webhook url", config: `{"slack_webhook_url": "https://hooks.slack.com/services/T000/B000/XXX"}`},
+		{name: "webhook url with overrides", config: `{"slack_webhook_url": "https://hooks.slack.com/services/T000/B000/XXX", "slack_channel": "#alerts", "slack_username": "peekaping"}`},
+		{name: "missing webhook url", config: `{"slack_channel": "#alerts"}`, wantErr: true},
+		{name: "not a url", config: `{"slack_webhook_url": "hooks.slack.com"}`, wantErr: true},
+		{name: "not an http url", config: `{"slack_webhook_url": "ftp://hooks.slack.com/services/T000"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSlackSender_Send_RichMessage(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(zap.NewNop().Sugar(), &config.Config{ClientURL: "https://peekaping.example.com"})
+	configJSON, _ := json.Marshal(map[string]any{
+		"slack_webhook_url":  server.URL,
+		"slack_channel":      "#alerts",
+		"slack_username":     "peekaping",
+		"slack_rich_message": true,
+	})
+
+	tests := []struct {
+		status shared.MonitorStatus
+		title  string
+		color  string
+	}{
+		{status: shared.MonitorStatusDown, title: "API is DOWN", color: "#e01e5a"},
+		{status: shared.MonitorStatusUp, title: "API is UP", color: "#2eb886"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			err := sender.Send(
+				context.Background(),
+				string(configJSON),
+				"connection refused",
+				&monitor.Model{ID: "m1", Name: "API"},
+				&heartbeat.Model{MonitorID: "m1", Status: tt.status, Msg: "connection refused"},
+			)
+			if err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			if payload["channel"] != "#alerts" || payload["username"] != "peekaping" {
+				t.Errorf("expected the channel and username overrides, got %v", payload)
+			}
+			if payload["text"] != tt.title {
+				t.Errorf("expected fallback text %q, got %v", tt.title, payload["text"])
+			}
+
+			attachments := payload["attachments"].([]any)
+			attachment := attachments[0].(map[string]any)
+			if attachment["color"] != tt.color {
+				t.Errorf("expected color %s, got %v", tt.color, attachment["color"])
+			}
+
+			encoded, _ := json.Marshal(attachment["blocks"])
+			for _, want := range []string{"*Monitor*\\nAPI", "connection refused", "https://peekaping.example.com/monitors/m1"} {
+				if !strings.Contains(string(encoded), want) {
+					t.Errorf("expected the blocks to contain %q, got %s", want, encoded)
+				}
+			}
+		})
+	}
+}
+
+func TestSlackSender_Send_Runbook(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(zap.NewNop().Sugar(), &config.Config{})
+	m := &monitor.Model{ID: "m1", Name: "API", Notes: "Page the database team", RunbookURL: "https://wiki.example.com/runbooks/api"}
+	hb := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown, Msg: "connection refused"}
+
+	t.Run("rich message", func(t *testing.T) {
+		configJSON, _ := json.Marshal(map[string]any{"slack_webhook_url": server.URL, "slack_rich_message": true})
+		if err := sender.Send(context.Background(), string(configJSON), "connection refused", m, hb); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		encoded, _ := json.Marshal(payload["attachments"])
+		for _, want := range []string{"Open runbook", "https://wiki.example.com/runbooks/api", "Page the database team"} {
+			if !strings.Contains(string(encoded), want) {
+				t.Errorf("expected the blocks to contain %q, got %s", want, encoded)
+			}
+		}
+	})
+
+	t.Run("plain message", func(t *testing.T) {
+		configJSON, _ := json.Marshal(map[string]any{"slack_webhook_url": server.URL})
+		if err := sender.Send(context.Background(), string(configJSON), "connection refused", m, hb); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if text, _ := payload["text"].(string); !strings.Contains(text, "Runbook: https://wiki.example.com/runbooks/api") {
+			t.Errorf("expected the text to link the runbook, got %q", text)
+		}
+	})
+}
+
+func TestSlackSender_TestSend(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(zap.NewNop().Sugar(), &config.Config{})
+	configJSON, _ := json.Marshal(map[string]any{"slack_webhook_url": server.URL, "slack_rich_message": true})
+	if err := sender.TestSend(context.Background(), string(configJSON), shared.MonitorStatusUp); err != nil {
+		t.Fatalf("TestSend failed: %v", err)
+	}
+	if payload["text"] != "Test Monitor is UP" {
+		t.Errorf("expected a synthetic UP event, got %v", payload["text"])
+	}
+}
@@ -0,0 +1,249 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SyslogConfig holds the configuration for syslog notifications
+type SyslogConfig struct {
+	Network             string `json:"syslog_network" validate:"omitempty,oneof=udp tcp unix"`
+	Address             string `json:"syslog_address" validate:"required"`
+	Facility            string `json:"syslog_facility" validate:"omitempty,oneof=kern user mail daemon auth syslog lpr news uucp cron authpriv ftp local0 local1 local2 local3 local4 local5 local6 local7"`
+	AppName             string `json:"syslog_app_name" validate:"omitempty,max=48,printascii"`
+	Hostname            string `json:"syslog_hostname" validate:"omitempty,max=255,printascii"`
+	SeverityDown        string `json:"syslog_severity_down" validate:"omitempty,oneof=emerg alert crit err warning notice info debug"`
+	SeverityUp          string `json:"syslog_severity_up" validate:"omitempty,oneof=emerg alert crit err warning notice info debug"`
+	SeverityPending     string `json:"syslog_severity_pending" validate:"omitempty,oneof=emerg alert crit err warning notice info debug"`
+	SeverityMaintenance string `json:"syslog_severity_maintenance" validate:"omitempty,oneof=emerg alert crit err warning notice info debug"`
+}
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+var syslogSeverities = map[string]int{
+	"emerg": 0, "alert": 1, "crit": 2, "err": 3, "warning": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+// syslogStructuredDataID uses the enterprise number reserved for documentation (RFC 5612)
+const syslogStructuredDataID = "peekaping@32473"
+
+// SyslogSender writes RFC 5424 formatted messages to a syslog server
+type SyslogSender struct {
+	logger *zap.SugaredLogger
+}
+
+// NewSyslogSender creates a new SyslogSender
+func NewSyslogSender(logger *zap.SugaredLogger) *SyslogSender {
+	return &SyslogSender{
+		logger: logger,
+	}
+}
+
+func (s *SyslogSender) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[SyslogConfig](configJSON)
+}
+
+func (s *SyslogSender) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	syslogCfg := cfg.(*SyslogConfig)
+	if err := GenericValidator(syslogCfg); err != nil {
+		return err
+	}
+
+	if syslogCfg.Network != "unix" {
+		host, port, err := net.SplitHostPort(syslogCfg.Address)
+		if err != nil {
+			return fmt.Errorf("syslog address must be in host:port format: %w", err)
+		}
+		if host == "" || port == "" {
+			return fmt.Errorf("syslog address must contain both host and port")
+		}
+	}
+
+	return nil
+}
+
+// Send writes a single syslog message for the monitor event
+func (s *SyslogSender) Send(
+	ctx context.Context,
+	configJSON string,
+	message string,
+	monitor *monitor.Model,
+	heartbeat *heartbeat.Model,
+) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	cfg := cfgAny.(*SyslogConfig)
+
+	network := cfg.Network
+	if network == "" {
+		network = "udp"
+	}
+
+	conn, err := s.dial(ctx, network, cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog server: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+	} else {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	}
+
+	line := s.formatMessage(cfg, message, monitor, heartbeat, time.Now())
+
+	// Stream transports use octet counting framing (RFC 6587), datagrams carry one message each
+	payload := line
+	if network == "tcp" {
+		payload = fmt.Sprintf("%d %s", len(line), line)
+	}
+
+	s.logger.Infof("Sending syslog notification to %s://%s", network, cfg.Address)
+	if _, err := conn.Write([]byte(payload)); err != nil {
+		return fmt.Errorf("failed to write syslog message: %w", err)
+	}
+
+	s.logger.Infof("Syslog notification sent successfully to %s", cfg.Address)
+	return nil
+}
+
+// dial connects to the syslog server. For unix sockets the datagram flavour used
+// by most local daemons is preferred, with a fallback to stream sockets.
+func (s *SyslogSender) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if network != "unix" {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	conn, err := dialer.DialContext(ctx, "unixgram", address)
+	if err == nil {
+		return conn, nil
+	}
+	return dialer.DialContext(ctx, "unix", address)
+}
+
+// formatMessage builds an RFC 5424 syslog message
+func (s *SyslogSender) formatMessage(cfg *SyslogConfig, message string, monitor *monitor.Model, heartbeat *heartbeat.Model, now time.Time) string {
+	facility, ok := syslogFacilities[cfg.Facility]
+	if !ok {
+		facility = syslogFacilities["user"]
+	}
+	priority := facility*8 + s.severity(cfg, heartbeat)
+
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	appName := cfg.AppName
+	if appName == "" {
+		appName = "peekaping"
+	}
+
+	msgID := "-"
+	structuredData := "-"
+	if heartbeat != nil {
+		msgID = humanReadableStatus(int(heartbeat.Status))
+	}
+	if monitor != nil || heartbeat != nil {
+		var params []string
+		if monitor != nil {
+			if monitor.ID != "" {
+				params = append(params, syslogParam("monitor_id", monitor.ID))
+			}
+			params = append(params, syslogParam("monitor_name", monitor.Name))
+			if monitor.Type != "" {
+				params = append(params, syslogParam("monitor_type", monitor.Type))
+			}
+		}
+		if heartbeat != nil {
+			params = append(params, syslogParam("status", humanReadableStatus(int(heartbeat.Status))))
+		}
+		structuredData = "[" + syslogStructuredDataID + " " + strings.Join(params, " ") + "]"
+	}
+
+	return fmt.Sprintf(
+		"<%d>1 %s %s %s %d %s %s %s",
+		priority,
+		now.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(appName, 48),
+		os.Getpid(),
+		syslogHeaderField(msgID, 32),
+		structuredData,
+		message,
+	)
+}
+
+// severity maps the heartbeat status to the configured syslog severity
+func (s *SyslogSender) severity(cfg *SyslogConfig, heartbeat *heartbeat.Model) int {
+	name := cfg.SeverityDown
+	fallback := "err"
+	if heartbeat != nil {
+		switch heartbeat.Status {
+		case shared.MonitorStatusUp:
+			name, fallback = cfg.SeverityUp, "notice"
+		case shared.MonitorStatusPending:
+			name, fallback = cfg.SeverityPending, "warning"
+		case shared.MonitorStatusMaintenance:
+			name, fallback = cfg.SeverityMaintenance, "info"
+		}
+	}
+
+	if severity, ok := syslogSeverities[name]; ok {
+		return severity
+	}
+	return syslogSeverities[fallback]
+}
+
+// syslogHeaderField returns a header value restricted to printable US-ASCII, or the nil value "-"
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	result := b.String()
+	if result == "" {
+		return "-"
+	}
+	if len(result) > maxLen {
+		result = result[:maxLen]
+	}
+	return result
+}
+
+// syslogParam formats a structured data parameter, escaping the characters required by RFC 5424
+func syslogParam(name, value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+	return fmt.Sprintf(`%s="%s"`, name, escaped)
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (s *SyslogSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return s.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+// rfc5424Pattern matches <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+var rfc5424Pattern = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|\[(?:[^\]\\]|\\.)*\]) (.*)$`)
+
+func TestSyslogConfig_Validate(t *testing.T) {
+	sender := NewSyslogSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{name: "udp with defaults", config: `{"syslog_address": "127.0.0.1:514"}`},
+		{name: "tcp with facility and severities", config: `{"syslog_network": "tcp", "syslog_address": "logs.example.com:6514", "syslog_facility": "local3", "syslog_severity_down": "crit", "syslog_severity_up": "info"}`},
+		{name: "unix socket path", config: `{"syslog_network": "unix", "syslog_address": "/dev/log"}`},
+		{name: "missing address", config: `{"syslog_network": "udp"}`, wantError: true},
+		{name: "address without port", config: `{"syslog_address": "127.0.0.1"}`, wantError: true},
+		{name: "unsupported network", config: `{"syslog_network": "http", "syslog_address": "127.0.0.1:514"}`, wantError: true},
+		{name: "unknown facility", config: `{"syslog_address": "127.0.0.1:514", "syslog_facility": "local9"}`, wantError: true},
+		{name: "unknown severity", config: `{"syslog_address": "127.0.0.1:514", "syslog_severity_down": "fatal"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantError && err == nil {
+				t.Errorf("Expected error for config %s", tt.config)
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyslogSender_Send_UDP(t *testing.T) {
+	sender := NewSyslogSender(zap.NewNop().Sugar())
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start capture server: %v", err)
+	}
+	defer pc.Close()
+
+	config := fmt.Sprintf(`{
+		"syslog_network": "udp",
+		"syslog_address": "%s",
+		"syslog_facility": "local0",
+		"syslog_hostname": "probe-1",
+		"syslog_severity_down": "crit"
+	}`, pc.LocalAddr().String())
+
+	m := &monitor.Model{ID: "monitor-123", Name: `API "prod"`, Type: "http"}
+	hb := &heartbeat.Model{Status: shared.MonitorStatusDown, Msg: "Connection refused"}
+
+	if err := sender.Send(context.Background(), config, "[API] is down: Connection refused", m, hb); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read syslog message: %v", err)
+	}
+
+	match := rfc5424Pattern.FindStringSubmatch(string(buf[:n]))
+	if match == nil {
+		t.Fatalf("Message is not RFC 5424 formatted: %q", string(buf[:n]))
+	}
+
+	// local0 (16) * 8 + crit (2)
+	if match[1] != "130" {
+		t.Errorf("Expected priority 130, got %s", match[1])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, match[2]); err != nil {
+		t.Errorf("Invalid timestamp %q: %v", match[2], err)
+	}
+	if match[3] != "probe-1" {
+		t.Errorf("Expected hostname 'probe-1', got '%s'", match[3])
+	}
+	if match[4] != "peekaping" {
+		t.Errorf("Expected app name 'peekaping', got '%s'", match[4])
+	}
+	if match[5] != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Expected procid %d, got %s", os.Getpid(), match[5])
+	}
+	if match[6] != "DOWN" {
+		t.Errorf("Expected msgid 'DOWN', got '%s'", match[6])
+	}
+	expectedSD := `[peekaping@32473 monitor_id="monitor-123" monitor_name="API \"prod\"" monitor_type="http" status="DOWN"]`
+	if match[7] != expectedSD {
+		t.Errorf("Expected structured data %s, got %s", expectedSD, match[7])
+	}
+	if match[8] != "[API] is down: Connection refused" {
+		t.Errorf("Unexpected message: %s", match[8])
+	}
+}
+
+func TestSyslogSender_Send_TCP(t *testing.T) {
+	sender := NewSyslogSender(zap.NewNop().Sugar())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start capture server: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+		// Octet counting framing: "<length> <message>"
+		reader := bufio.NewReader(conn)
+		lengthStr, err := reader.ReadString(' ')
+		if err != nil {
+			return
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(lengthStr))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, length)
+		if _, err := reader.Read(msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}()
+
+	config := fmt.Sprintf(`{"syslog_network": "tcp", "syslog_address": "%s"}`, listener.Addr().String())
+	m := &monitor.Model{ID: "monitor-123", Name: "API"}
+	hb := &heartbeat.Model{Status: shared.MonitorStatusUp}
+
+	if err := sender.Send(context.Background(), config, "[API] is up", m, hb); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		match := rfc5424Pattern.FindStringSubmatch(msg)
+		if match == nil {
+			t.Fatalf("Message is not RFC 5424 formatted: %q", msg)
+		}
+		// default facility user (1) * 8 + default up severity notice (5)
+		if match[1] != "13" {
+			t.Errorf("Expected priority 13, got %s", match[1])
+		}
+		if match[6] != "UP" {
+			t.Errorf("Expected msgid 'UP', got '%s'", match[6])
+		}
+		if match[8] != "[API] is up" {
+			t.Errorf("Unexpected message: %s", match[8])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for syslog message")
+	}
+}
+
+func TestSyslogSender_severity(t *testing.T) {
+	sender := NewSyslogSender(zap.NewNop().Sugar())
+	cfg := &SyslogConfig{SeverityPending: "debug"}
+
+	tests := []struct {
+		status   shared.MonitorStatus
+		expected int
+	}{
+		{shared.MonitorStatusDown, 3},
+		{shared.MonitorStatusUp, 5},
+		{shared.MonitorStatusPending, 7},
+		{shared.MonitorStatusMaintenance, 6},
+	}
+
+	for _, tt := range tests {
+		got := sender.severity(cfg, &heartbeat.Model{Status: tt.status})
+		if got != tt.expected {
+			t.Errorf("Status %d: expected severity %d, got %d", tt.status, tt.expected, got)
+		}
+	}
+}
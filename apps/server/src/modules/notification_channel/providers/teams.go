@@ -0,0 +1,288 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"peekaping/src/config"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"peekaping/src/version"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type TeamsConfig struct {
+	WebhookURL string `json:"webhook_url" validate:"required,url"`
+	// adaptive_card for Workflows webhooks, message_card for the legacy
+	// Office 365 connectors. Adaptive cards are sent when not set.
+	CardType string `json:"card_type" validate:"omitempty,oneof=adaptive_card message_card"`
+}
+
+// teamsWebhookHosts are the hosts Teams incoming webhooks and Workflows are served from
+var teamsWebhookHosts = []string{
+	"webhook.office.com",
+	"outlook.office.com",
+	"outlook.office365.com",
+	"logic.azure.com",
+	"powerplatform.com",
+	"powerautomate.com",
+}
+
+// Theme colors of the statuses for message cards
+var teamsThemeColors = map[shared.MonitorStatus]string{
+	shared.MonitorStatusDown:        "E74C3C",
+	shared.MonitorStatusUp:          "2ECC71",
+	shared.MonitorStatusPending:     "F1C40F",
+	shared.MonitorStatusMaintenance: "3498DB",
+}
+
+// Adaptive cards only know named styles, the container takes the style of the status
+var teamsContainerStyles = map[shared.MonitorStatus]string{
+	shared.MonitorStatusDown:        "attention",
+	shared.MonitorStatusUp:          "good",
+	shared.MonitorStatusPending:     "warning",
+	shared.MonitorStatusMaintenance: "accent",
+}
+
+type TeamsSender struct {
+	logger *zap.SugaredLogger
+	client *http.Client
+	config *config.Config
+}
+
+// NewTeamsSender creates a TeamsSender
+func NewTeamsSender(logger *zap.SugaredLogger, config *config.Config) *TeamsSender {
+	return &TeamsSender{
+		logger: logger,
+		config: config,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (t *TeamsSender) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[TeamsConfig](configJSON)
+}
+
+func (t *TeamsSender) Validate(configJSON string) error {
+	cfg, err := t.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	teamsCfg := cfg.(*TeamsConfig)
+	if err := GenericValidator(teamsCfg); err != nil {
+		return err
+	}
+
+	webhookURL, err := url.Parse(teamsCfg.WebhookURL)
+	if err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("webhook_url must be an https URL")
+	}
+	host := strings.ToLower(webhookURL.Hostname())
+	for _, allowed := range teamsWebhookHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook_url must be a Microsoft Teams incoming webhook or Workflows URL")
+}
+
+// teamsFacts lists the details of a status change as name and value pairs
+func (t *TeamsSender) teamsFacts(m *monitor.Model, hb *heartbeat.Model, message string) [][2]string {
+	var facts [][2]string
+	if m != nil {
+		facts = append(facts, [2]string{"Monitor", m.Name})
+		if target := monitorTarget(m); target != "" {
+			facts = append(facts, [2]string{"Target", target})
+		}
+	}
+	if hb != nil {
+		facts = append(facts, [2]string{"Status", humanReadableStatus(int(hb.Status))})
+		timestamp := hb.Time
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		facts = append(facts, [2]string{"Time", timestamp.UTC().Format(time.RFC3339)})
+	}
+	if m != nil && m.Notes != "" {
+		facts = append(facts, [2]string{"Notes", m.Notes})
+	}
+	if message != "" {
+		if hb != nil && hb.Status == shared.MonitorStatusDown {
+			facts = append(facts, [2]string{"Error", message})
+		} else {
+			facts = append(facts, [2]string{"Message", message})
+		}
+	}
+	return facts
+}
+
+// monitorURL links the monitor in the Peekaping UI, empty without a client URL
+func (t *TeamsSender) monitorURL(m *monitor.Model) string {
+	if m == nil || t.config == nil || t.config.ClientURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/monitors/%s", strings.TrimRight(t.config.ClientURL, "/"), m.ID)
+}
+
+func (t *TeamsSender) buildAdaptiveCard(m *monitor.Model, hb *heartbeat.Model, message string) map[string]any {
+	title := statusTitle(m, hb)
+	if title == "" {
+		title = "Peekaping Alert"
+	}
+
+	factSet := []map[string]string{}
+	for _, fact := range t.teamsFacts(m, hb, message) {
+		factSet = append(factSet, map[string]string{"title": fact[0], "value": fact[1]})
+	}
+
+	header := map[string]any{
+		"type":  "Container",
+		"bleed": true,
+		"items": []map[string]any{
+			{"type": "TextBlock", "text": title, "weight": "Bolder", "size": "Medium", "wrap": true},
+		},
+	}
+	if hb != nil {
+		if style, ok := teamsContainerStyles[hb.Status]; ok {
+			header["style"] = style
+		}
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"msteams": map[string]string{"width": "Full"},
+		"body": []map[string]any{
+			header,
+			{"type": "FactSet", "facts": factSet},
+		},
+	}
+	actions := []map[string]string{}
+	if m != nil && m.RunbookURL != "" {
+		actions = append(actions, map[string]string{"type": "Action.OpenUrl", "title": "Open runbook", "url": m.RunbookURL})
+	}
+	if link := t.monitorURL(m); link != "" {
+		actions = append(actions, map[string]string{"type": "Action.OpenUrl", "title": "Visit Peekaping", "url": link})
+	}
+	if len(actions) > 0 {
+		card["actions"] = actions
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
+
+func (t *TeamsSender) buildMessageCard(m *monitor.Model, hb *heartbeat.Model, message string) map[string]any {
+	title := statusTitle(m, hb)
+	if title == "" {
+		title = "Peekaping Alert"
+	}
+
+	facts := []map[string]string{}
+	for _, fact := range t.teamsFacts(m, hb, message) {
+		facts = append(facts, map[string]string{"name": fact[0], "value": fact[1]})
+	}
+
+	card := map[string]any{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  title,
+		"sections": []map[string]any{
+			{"activityTitle": title, "facts": facts, "markdown": true},
+		},
+	}
+	if hb != nil {
+		if color, ok := teamsThemeColors[hb.Status]; ok {
+			card["themeColor"] = color
+		}
+	}
+	actions := []map[string]any{}
+	if m != nil && m.RunbookURL != "" {
+		actions = append(actions, map[string]any{
+			"@type":   "OpenUri",
+			"name":    "Open runbook",
+			"targets": []map[string]string{{"os": "default", "uri": m.RunbookURL}},
+		})
+	}
+	if link := t.monitorURL(m); link != "" {
+		actions = append(actions, map[string]any{
+			"@type":   "OpenUri",
+			"name":    "Visit Peekaping",
+			"targets": []map[string]string{{"os": "default", "uri": link}},
+		})
+	}
+	if len(actions) > 0 {
+		card["potentialAction"] = actions
+	}
+	return card
+}
+
+func (t *TeamsSender) Send(
+	ctx context.Context,
+	configJSON string,
+	message string,
+	m *monitor.Model,
+	hb *heartbeat.Model,
+) error {
+	cfgAny, err := t.Unmarshal(configJSON)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	cfg := cfgAny.(*TeamsConfig)
+
+	t.logger.Infof("Sending Microsoft Teams notification to webhook: %s", cfg.WebhookURL)
+
+	var payload map[string]any
+	if cfg.CardType == "message_card" {
+		payload = t.buildMessageCard(m, hb, message)
+	} else {
+		payload = t.buildAdaptiveCard(m, hb, message)
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.WebhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Peekaping-Teams/"+version.Version)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Teams webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	t.logger.Infof("Microsoft Teams notification sent successfully")
+	return nil
+}
+
+// TestSend sends a synthetic event of the given status through the send path
+func (t *TeamsSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return t.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
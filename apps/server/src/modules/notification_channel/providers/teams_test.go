@@ -0,0 +1,191 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"peekaping/src/config"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestTeamsConfig_Validate(t *testing.T) {
+	sender := NewTeamsSender(zap.NewNop().Sugar(), &config.Config{})
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "incoming webhook", config: `{"webhook_url": "https://contoso.webhook.office.com/webhookb2/abc@def/IncomingWebhook/123/456"}`},
+		{name: "workflows webhook", config: `{"webhook_url": "https://prod-12.westus.logic.azure.com:443/workflows/abc/triggers/manual/paths/invoke?sig=x"}`},
+		{name: "message card", config: `{"webhook_url": "https://outlook.office.com/webhook/abc", "card_type": "message_card"}`},
+		{name: "missing webhook url", config: `{}`, wantErr: true},
+		{name: "plain http", config: `{"webhook_url": "http://contoso.webhook.office.com/webhookb2/abc"}`, wantErr: true},
+		{name: "other host", config: `{"webhook_url": "https://example.com/webhook.office.com"}`, wantErr: true},
+		{name: "lookalike host", config: `{"webhook_url": "https://evilwebhook.office.com.example.com/webhookb2/abc"}`, wantErr: true},
+		{name: "unknown card type", config: `{"webhook_url": "https://outlook.office.com/webhook/abc", "card_type": "hero"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTeamsSender_Send(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewTeamsSender(zap.NewNop().Sugar(), &config.Config{ClientURL: "https://peekaping.example.com/"})
+	m := &monitor.Model{ID: "m1", Name: "API", Config: `{"url": "https://api.example.com/health"}`}
+	hb := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown, Msg: "timeout", Time: time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)}
+
+	t.Run("adaptive card", func(t *testing.T) {
+		configJSON, _ := json.Marshal(map[string]any{"webhook_url": server.URL})
+		if err := sender.Send(context.Background(), string(configJSON), "timeout", m, hb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		attachment := payload["attachments"].([]any)[0].(map[string]any)
+		if attachment["contentType"] != "application/vnd.microsoft.card.adaptive" {
+			t.Fatalf("unexpected content type %v", attachment["contentType"])
+		}
+		card := attachment["content"].(map[string]any)
+		body := card["body"].([]any)
+		header := body[0].(map[string]any)
+		if header["style"] != "attention" {
+			t.Errorf("expected the attention style for DOWN, got %v", header["style"])
+		}
+		if title := header["items"].([]any)[0].(map[string]any)["text"]; title != "API is DOWN" {
+			t.Errorf("unexpected title %v", title)
+		}
+
+		facts := map[string]string{}
+		for _, fact := range body[1].(map[string]any)["facts"].([]any) {
+			fact := fact.(map[string]any)
+			facts[fact["title"].(string)] = fact["value"].(string)
+		}
+		expected := map[string]string{
+			"Monitor": "API",
+			"Target":  "https://api.example.com/health",
+			"Status":  "DOWN",
+			"Time":    "2025-07-20T12:00:00Z",
+			"Error":   "timeout",
+		}
+		for name, value := range expected {
+			if facts[name] != value {
+				t.Errorf("expected fact %s=%q, got %q", name, value, facts[name])
+			}
+		}
+
+		action := card["actions"].([]any)[0].(map[string]any)
+		if action["url"] != "https://peekaping.example.com/monitors/m1" {
+			t.Errorf("unexpected monitor link %v", action["url"])
+		}
+	})
+
+	t.Run("message card", func(t *testing.T) {
+		configJSON, _ := json.Marshal(map[string]any{"webhook_url": server.URL, "card_type": "message_card"})
+		up := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusUp, Msg: "200 - OK"}
+		if err := sender.Send(context.Background(), string(configJSON), "200 - OK", m, up); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if payload["@type"] != "MessageCard" {
+			t.Fatalf("expected a message card, got %v", payload["@type"])
+		}
+		if payload["themeColor"] != "2ECC71" {
+			t.Errorf("expected the UP theme color, got %v", payload["themeColor"])
+		}
+		if payload["summary"] != "API is UP" {
+			t.Errorf("unexpected summary %v", payload["summary"])
+		}
+	})
+}
+
+func TestTeamsSender_Send_Runbook(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewTeamsSender(zap.NewNop().Sugar(), &config.Config{ClientURL: "https://peekaping.example.com"})
+	m := &monitor.Model{ID: "m1", Name: "API", Notes: "Check the load balancer", RunbookURL: "https://wiki.example.com/runbooks/api"}
+	hb := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown, Msg: "timeout"}
+	configJSON, _ := json.Marshal(map[string]any{"webhook_url": server.URL})
+	if err := sender.Send(context.Background(), string(configJSON), "timeout", m, hb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	card := payload["attachments"].([]any)[0].(map[string]any)["content"].(map[string]any)
+	action := card["actions"].([]any)[0].(map[string]any)
+	if action["title"] != "Open runbook" || action["url"] != "https://wiki.example.com/runbooks/api" {
+		t.Errorf("expected the runbook to be the first action, got %v", action)
+	}
+	notes := ""
+	for _, fact := range card["body"].([]any)[1].(map[string]any)["facts"].([]any) {
+		if fact := fact.(map[string]any); fact["title"] == "Notes" {
+			notes = fact["value"].(string)
+		}
+	}
+	if notes != "Check the load balancer" {
+		t.Errorf("expected the notes fact, got %q", notes)
+	}
+}
+
+func TestTeamsSender_Send_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Webhook message delivery failed"))
+	}))
+	defer server.Close()
+
+	sender := NewTeamsSender(zap.NewNop().Sugar(), &config.Config{})
+	configJSON, _ := json.Marshal(map[string]any{"webhook_url": server.URL})
+	if err := sender.Send(context.Background(), string(configJSON), "test", nil, nil); err == nil {
+		t.Fatal("expected an error for a rejected message")
+	}
+}
+
+func TestMonitorTarget(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{config: `{"url": "https://example.com"}`, want: "https://example.com"},
+		{config: `{"host": "db.example.com", "port": 5432}`, want: "db.example.com:5432"},
+		{config: `{"hostname": "broker.example.com", "port": 0}`, want: "broker.example.com"},
+		{config: `{"database_connection_string": "secret"}`, want: ""},
+		{config: `not json`, want: ""},
+	}
+	for _, tt := range tests {
+		if got := monitorTarget(&monitor.Model{Config: tt.config}); got != tt.want {
+			t.Errorf("monitorTarget(%s) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
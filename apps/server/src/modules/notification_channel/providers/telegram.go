@@ -1,17 +1,32 @@
 package providers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"strconv"
+	"strings"
+	"time"
 
 	liquid "github.com/osteele/liquid"
 	"go.uber.org/zap"
 )
 
+const (
+	// telegramMaxAttempts is how often a rate limited message is sent before giving up
+	telegramMaxAttempts = 3
+	// telegramMaxRetryAfter is the longest wait asked by Telegram that is honored
+	telegramMaxRetryAfter = time.Minute
+	// telegramVerifyTimeout bounds the getMe and getChat calls made on save
+	telegramVerifyTimeout = 10 * time.Second
+)
+
 type TelegramConfig struct {
 	BotToken          string `json:"bot_token" validate:"required"`
 	ChatID            string `json:"chat_id" validate:"required"`
@@ -22,15 +37,41 @@ type TelegramConfig struct {
 	Template          string `json:"template"`
 	SendSilently      bool   `json:"send_silently"`
 	ProtectContent    bool   `json:"protect_content"`
+	// VerifyOnSave checks the bot token and chat with the Bot API when the channel is saved
+	VerifyOnSave bool `json:"verify_on_save"`
+}
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
 }
 
 type TelegramSender struct {
 	logger *zap.SugaredLogger
+	client *http.Client
+	// sleep waits between rate limited attempts, replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
 // NewTelegramSender creates an TelegramSender
 func NewTelegramSender(logger *zap.SugaredLogger) *TelegramSender {
-	return &TelegramSender{logger: logger}
+	return &TelegramSender{logger: logger, client: &http.Client{}, sleep: sleepContext}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func (s *TelegramSender) Unmarshal(configJSON string) (any, error) {
@@ -45,6 +86,56 @@ func (s *TelegramSender) Validate(configJSON string) error {
 	return GenericValidator(cfg.(*TelegramConfig))
 }
 
+// Verify checks the bot token with getMe and that the bot can reach the chat
+// with getChat, only when the channel opted in with verify_on_save
+func (s *TelegramSender) Verify(ctx context.Context, configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*TelegramConfig)
+	if !cfg.VerifyOnSave {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, telegramVerifyTimeout)
+	defer cancel()
+
+	if _, err := s.call(ctx, cfg, "getMe", map[string]any{}); err != nil {
+		return fmt.Errorf("invalid bot token: %w", err)
+	}
+	if _, err := s.call(ctx, cfg, "getChat", map[string]any{"chat_id": cfg.ChatID}); err != nil {
+		return fmt.Errorf("chat %s is not reachable by the bot: %w", cfg.ChatID, err)
+	}
+	return nil
+}
+
+// escapeMarkdownV2 escapes the characters MarkdownV2 reserves
+func escapeMarkdownV2(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("_*[]()~`>#+-=|{}.!\\", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatStatusChange renders a status change as MarkdownV2, a bold headline
+// followed by the message and the runbook of the monitor
+func formatStatusChange(monitor *monitor.Model, heartbeat *heartbeat.Model, message string) string {
+	name := heartbeat.MonitorID
+	if monitor != nil {
+		name = monitor.Name
+	}
+	return fmt.Sprintf("*%s is %s*\n%s",
+		escapeMarkdownV2(name),
+		escapeMarkdownV2(humanReadableStatus(int(heartbeat.Status))),
+		escapeMarkdownV2(message+runbookFooter(monitor)),
+	)
+}
+
 func (s *TelegramSender) Send(
 	ctx context.Context,
 	configJSON string,
@@ -60,11 +151,6 @@ func (s *TelegramSender) Send(
 
 	s.logger.Infof("Sending telegram message: %s", message)
 
-	url := cfg.ServerUrl
-	if url == "" {
-		url = "https://api.telegram.org"
-	}
-
 	params := map[string]any{
 		"chat_id":              cfg.ChatID,
 		"text":                 message,
@@ -72,7 +158,11 @@ func (s *TelegramSender) Send(
 		"protect_content":      cfg.ProtectContent,
 	}
 	if cfg.MessageThreadID != "" {
-		params["message_thread_id"] = cfg.MessageThreadID
+		if threadID, err := strconv.Atoi(cfg.MessageThreadID); err == nil {
+			params["message_thread_id"] = threadID
+		} else {
+			params["message_thread_id"] = cfg.MessageThreadID
+		}
 	}
 
 	if cfg.UseTemplate {
@@ -94,39 +184,87 @@ func (s *TelegramSender) Send(
 			}
 		}
 
-		fmt.Println("parse_mode", cfg.TemplateParseMode)
 		if cfg.TemplateParseMode != "plain" && cfg.TemplateParseMode != "" {
 			params["parse_mode"] = cfg.TemplateParseMode
 		}
+	} else if heartbeat != nil {
+		params["text"] = formatStatusChange(monitor, heartbeat, message)
+		params["parse_mode"] = "MarkdownV2"
 	}
 
-	apiUrl := fmt.Sprintf("%s/bot%s/sendMessage", url, cfg.BotToken)
+	if _, err := s.call(ctx, cfg, "sendMessage", params); err != nil {
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+	return nil
+}
+
+// call posts a Bot API method, retrying after the wait Telegram asks for
+// when the bot is rate limited
+func (s *TelegramSender) call(ctx context.Context, cfg *TelegramConfig, method string, params map[string]any) (*telegramResponse, error) {
+	baseURL := cfg.ServerUrl
+	if baseURL == "" {
+		baseURL = "https://api.telegram.org"
+	}
+	apiUrl := fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(baseURL, "/"), cfg.BotToken, method)
 
-	// Prepare request
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", apiUrl, nil)
+	payload, err := json.Marshal(params)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to marshal telegram payload: %w", err)
 	}
 
-	// Add params as query
-	q := req.URL.Query()
-	for k, v := range params {
-		q.Add(k, fmt.Sprintf("%v", v))
-	}
-	req.URL.RawQuery = q.Encode()
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
 
-	s.logger.Debugf("Sending telegram message: %s", req.URL.String())
+		s.logger.Debugf("Calling telegram %s: %s", method, string(payload))
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send telegram message: %w", err)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			// the url holds the bot token, keep it out of the error
+			return nil, fmt.Errorf("request failed: %w", redactTelegramToken(err, cfg.BotToken))
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+
+		var result telegramResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("telegram API returned status: %s, body: %s", resp.Status, body)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < telegramMaxAttempts {
+			wait := time.Duration(result.Parameters.RetryAfter) * time.Second
+			if wait <= 0 {
+				wait = time.Second
+			}
+			if wait > telegramMaxRetryAfter {
+				return nil, fmt.Errorf("rate limited by telegram for %s", wait)
+			}
+			s.logger.Warnf("Rate limited by telegram, retrying %s in %s", method, wait)
+			if err := s.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 || !result.OK {
+			return nil, fmt.Errorf("telegram API returned status: %s, description: %s", resp.Status, result.Description)
+		}
+		return &result, nil
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("telegram API returned status: %s, body: %s", resp.Status, resp.Body)
+func redactTelegramToken(err error, token string) error {
+	if token == "" {
+		return err
 	}
+	return errors.New(strings.ReplaceAll(err.Error(), token, "<redacted>"))
+}
 
-	return nil
+// TestSend sends a synthetic event of the given status through the send path
+func (s *TelegramSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return s.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
 }
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+// fakeTelegramAPI answers Bot API calls, rate limiting the first
+// sendMessage calls when limited is set
+type fakeTelegramAPI struct {
+	mu       sync.Mutex
+	limited  int
+	calls    []string
+	payloads []map[string]any
+}
+
+func (a *fakeTelegramAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	a.calls = append(a.calls, method)
+	var payload map[string]any
+	json.NewDecoder(r.Body).Decode(&payload)
+	a.payloads = append(a.payloads, payload)
+
+	switch {
+	case !strings.HasPrefix(r.URL.Path, "/botvalid-token/"):
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+	case method == "getChat" && payload["chat_id"] != "42":
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	case method == "sendMessage" && a.limited > 0:
+		a.limited--
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 3","parameters":{"retry_after":3}}`))
+	default:
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}
+}
+
+func newTestTelegramSender() (*TelegramSender, *[]time.Duration) {
+	waits := &[]time.Duration{}
+	sender := NewTelegramSender(zap.NewNop().Sugar())
+	sender.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return sender, waits
+}
+
+func telegramConfig(serverURL, token, chatID string, extra map[string]any) string {
+	cfg := map[string]any{"bot_token": token, "chat_id": chatID, "server_url": serverURL}
+	for k, v := range extra {
+		cfg[k] = v
+	}
+	encoded, _ := json.Marshal(cfg)
+	return string(encoded)
+}
+
+func TestTelegramSender_Send_MarkdownStatusChange(t *testing.T) {
+	api := &fakeTelegramAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender, _ := newTestTelegramSender()
+
+	err := sender.Send(
+		context.Background(),
+		telegramConfig(server.URL, "valid-token", "42", map[string]any{"message_thread_id": "7"}),
+		"connection refused (10.0.0.1)",
+		&monitor.Model{ID: "m1", Name: "api-v2"},
+		&heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown},
+	)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	payload := api.payloads[0]
+	if payload["parse_mode"] != "MarkdownV2" {
+		t.Errorf("expected MarkdownV2, got %v", payload["parse_mode"])
+	}
+	if want := "*api\\-v2 is DOWN*\nconnection refused \\(10\\.0\\.0\\.1\\)"; payload["text"] != want {
+		t.Errorf("expected text %q, got %q", want, payload["text"])
+	}
+	if payload["chat_id"] != "42" || payload["message_thread_id"] != float64(7) {
+		t.Errorf("expected the chat and thread, got %v", payload)
+	}
+}
+
+func TestTelegramSender_Send_RetriesRateLimit(t *testing.T) {
+	api := &fakeTelegramAPI{limited: 2}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender, waits := newTestTelegramSender()
+
+	err := sender.Send(context.Background(), telegramConfig(server.URL, "valid-token", "42", nil), "Test", nil, nil)
+	if err != nil {
+		t.Fatalf("expected the message to be sent after the rate limit, got %v", err)
+	}
+	if len(api.calls) != 3 {
+		t.Errorf("expected 3 attempts, got %v", api.calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 3*time.Second {
+		t.Errorf("expected to wait retry_after twice, got %v", *waits)
+	}
+}
+
+func TestTelegramSender_Send_GivesUpWhenRateLimited(t *testing.T) {
+	api := &fakeTelegramAPI{limited: telegramMaxAttempts}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender, _ := newTestTelegramSender()
+
+	err := sender.Send(context.Background(), telegramConfig(server.URL, "valid-token", "42", nil), "Test", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Too Many Requests") {
+		t.Fatalf("expected the rate limit error, got %v", err)
+	}
+	if len(api.calls) != telegramMaxAttempts {
+		t.Errorf("expected %d attempts, got %d", telegramMaxAttempts, len(api.calls))
+	}
+}
+
+func TestTelegramSender_Verify(t *testing.T) {
+	api := &fakeTelegramAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender, _ := newTestTelegramSender()
+	verify := map[string]any{"verify_on_save": true}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "valid", config: telegramConfig(server.URL, "valid-token", "42", verify)},
+		{name: "invalid token", config: telegramConfig(server.URL, "bad-token", "42", verify), wantErr: "invalid bot token"},
+		{name: "unknown chat", config: telegramConfig(server.URL, "valid-token", "7", verify), wantErr: "chat not found"},
+		{name: "not opted in", config: telegramConfig(server.URL, "bad-token", "42", nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Verify(context.Background(), tt.config)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if err != nil && strings.Contains(err.Error(), "bad-token") {
+				t.Errorf("expected the token to stay out of errors, got %v", err)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
 	"peekaping/src/version"
@@ -18,11 +19,56 @@ import (
 
 type WebhookConfig struct {
 	WebhookURL               string `json:"webhook_url" validate:"required,url"`
-	WebhookContentType       string `json:"webhook_content_type" validate:"required,oneof=json form-data custom"`
+	WebhookMethod            string `json:"webhook_method" validate:"omitempty,oneof=GET POST"`
+	WebhookContentType       string `json:"webhook_content_type" validate:"required,oneof=json form-data form-urlencoded custom"`
 	WebhookCustomBody        string `json:"webhook_custom_body"`
 	WebhookAdditionalHeaders string `json:"webhook_additional_headers"`
 }
 
+// method returns the HTTP method of the webhook, POST unless GET is configured
+func (c *WebhookConfig) method() string {
+	if c.WebhookMethod == http.MethodGet {
+		return http.MethodGet
+	}
+	return http.MethodPost
+}
+
+// additionalHeaders parses the additional headers, a JSON object of header names to values
+func (c *WebhookConfig) additionalHeaders() (map[string]any, error) {
+	if c.WebhookAdditionalHeaders == "" {
+		return nil, nil
+	}
+	var headers map[string]any
+	if err := json.Unmarshal([]byte(c.WebhookAdditionalHeaders), &headers); err != nil {
+		return nil, fmt.Errorf("additional Headers is not a valid JSON: %q - %w", c.WebhookAdditionalHeaders, err)
+	}
+	return headers, nil
+}
+
+// webhookFormValues flattens a notification into fields for url-encoded
+// bodies and query strings. data holds the full payload as JSON, like the
+// data field of form-data bodies.
+func webhookFormValues(bindings map[string]any, data []byte) url.Values {
+	values := url.Values{}
+	for _, key := range []string{"name", "status", "msg"} {
+		if value, ok := bindings[key].(string); ok {
+			values.Set(key, value)
+		}
+	}
+	if hb, ok := bindings["heartbeat"].(map[string]any); ok {
+		if t, ok := hb["time"]; ok {
+			values.Set("time", fmt.Sprintf("%v", t))
+		}
+	}
+	if m, ok := bindings["monitor"].(map[string]any); ok {
+		if id, ok := m["id"]; ok {
+			values.Set("monitor_id", fmt.Sprintf("%v", id))
+		}
+	}
+	values.Set("data", string(data))
+	return values
+}
+
 type WebhookSender struct {
 	logger *zap.SugaredLogger
 }
@@ -49,7 +95,30 @@ func (w *WebhookSender) Validate(configJSON string) error {
 		return fmt.Errorf("webhook_custom_body is required when webhook_content_type is 'custom'")
 	}
 
-	return GenericValidator(webhookCfg)
+	if err := GenericValidator(webhookCfg); err != nil {
+		return err
+	}
+
+	if u, err := url.Parse(webhookCfg.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("webhook_url must be an http or https URL")
+	}
+
+	// a GET request has no body, its fields go into the query string
+	if webhookCfg.method() == http.MethodGet && webhookCfg.WebhookContentType != "form-urlencoded" {
+		return fmt.Errorf("webhook_method GET requires webhook_content_type 'form-urlencoded'")
+	}
+
+	if webhookCfg.WebhookContentType == "custom" {
+		if _, err := liquid.NewEngine().ParseString(webhookCfg.WebhookCustomBody); err != nil {
+			return fmt.Errorf("webhook_custom_body is not a valid template: %w", err)
+		}
+	}
+
+	if _, err := webhookCfg.additionalHeaders(); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (w *WebhookSender) Send(
@@ -73,9 +142,11 @@ func (w *WebhookSender) Send(
 		"monitor":   monitor,
 		"msg":       message,
 	}
+	bindings := PrepareTemplateBindings(monitor, heartbeat, message)
 
 	// Prepare request body and headers based on content type
 	var body io.Reader
+	var query url.Values
 	headers := make(map[string]string)
 
 	switch cfg.WebhookContentType {
@@ -113,13 +184,26 @@ func (w *WebhookSender) Send(
 		w.logger.Debugf("Form-data content-type: %s", headers["Content-Type"])
 		w.logger.Debugf("Form-data body length: %d bytes", buf.Len())
 
+	case "form-urlencoded":
+		jsonBytes, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal form data: %w", err)
+		}
+		values := webhookFormValues(bindings, jsonBytes)
+
+		if cfg.method() == http.MethodGet {
+			query = values
+		} else {
+			body = bytes.NewBufferString(values.Encode())
+			headers["Content-Type"] = "application/x-www-form-urlencoded"
+		}
+
 	case "custom":
 		if cfg.WebhookCustomBody == "" {
 			return fmt.Errorf("custom body is required when content type is custom")
 		}
 
 		// Render template for custom body
-		bindings := PrepareTemplateBindings(monitor, heartbeat, message)
 		engine := liquid.NewEngine()
 		rendered, err := engine.ParseAndRenderString(cfg.WebhookCustomBody, bindings)
 		if err != nil {
@@ -128,13 +212,30 @@ func (w *WebhookSender) Send(
 
 		body = bytes.NewBufferString(rendered)
 		headers["Content-Type"] = "text/plain"
+		if json.Valid([]byte(rendered)) {
+			headers["Content-Type"] = "application/json"
+		}
 
 	default:
 		return fmt.Errorf("unsupported content type: %s", cfg.WebhookContentType)
 	}
 
-	// Create HTTP request (always POST)
-	req, err := http.NewRequestWithContext(ctx, "POST", cfg.WebhookURL, body)
+	targetURL := cfg.WebhookURL
+	if query != nil {
+		u, err := url.Parse(cfg.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse webhook URL: %w", err)
+		}
+		// keep the parameters already in the URL
+		merged := u.Query()
+		for key, values := range query {
+			merged[key] = values
+		}
+		u.RawQuery = merged.Encode()
+		targetURL = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cfg.method(), targetURL, body)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -145,22 +246,18 @@ func (w *WebhookSender) Send(
 	}
 
 	// Parse and set additional headers
-	if cfg.WebhookAdditionalHeaders != "" {
-		w.logger.Debugf("Parsing additional headers: %q", cfg.WebhookAdditionalHeaders)
-		var additionalHeaders map[string]any
-		if err := json.Unmarshal([]byte(cfg.WebhookAdditionalHeaders), &additionalHeaders); err != nil {
-			return fmt.Errorf("additional Headers is not a valid JSON: %q - %w", cfg.WebhookAdditionalHeaders, err)
-		}
-
-		for key, value := range additionalHeaders {
-			req.Header.Set(key, fmt.Sprintf("%v", value))
-		}
+	additionalHeaders, err := cfg.additionalHeaders()
+	if err != nil {
+		return err
+	}
+	for key, value := range additionalHeaders {
+		req.Header.Set(key, fmt.Sprintf("%v", value))
 	}
 
 	// Set default user agent
 	req.Header.Set("User-Agent", "Peekaping-Webhook/"+version.Version)
 
-	w.logger.Debugf("Sending webhook POST request to: %s", cfg.WebhookURL)
+	w.logger.Debugf("Sending webhook %s request to: %s", cfg.method(), cfg.WebhookURL)
 
 	// Send request with default HTTP client
 	client := &http.Client{}
@@ -179,3 +276,9 @@ func (w *WebhookSender) Send(
 	w.logger.Infof("Webhook notification sent successfully to: %s", cfg.WebhookURL)
 	return nil
 }
+
+// TestSend sends a synthetic event of the given status through the send path
+func (w *WebhookSender) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	message, testMonitor, testHeartbeat := testEvent(status)
+	return w.Send(ctx, configJSON, message, testMonitor, testHeartbeat)
+}
@@ -0,0 +1,148 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+func TestWebhookConfig_Validate(t *testing.T) {
+	sender := NewWebhookSender(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "json", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "json"}`},
+		{name: "custom template", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "custom", "webhook_custom_body": "{\"text\": \"{{ monitor.name }} is {{ status }}\"}"}`},
+		{name: "get with query", config: `{"webhook_url": "https://example.com/hook", "webhook_method": "GET", "webhook_content_type": "form-urlencoded"}`},
+		{name: "headers", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "json", "webhook_additional_headers": "{\"Authorization\": \"Bearer token\"}"}`},
+		{name: "not an http url", config: `{"webhook_url": "ftp://example.com/hook", "webhook_content_type": "json"}`, wantErr: true},
+		{name: "unknown method", config: `{"webhook_url": "https://example.com/hook", "webhook_method": "PUT", "webhook_content_type": "json"}`, wantErr: true},
+		{name: "get with body", config: `{"webhook_url": "https://example.com/hook", "webhook_method": "GET", "webhook_content_type": "json"}`, wantErr: true},
+		{name: "broken template", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "custom", "webhook_custom_body": "{{ monitor.name | }}"}`, wantErr: true},
+		{name: "unclosed tag", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "custom", "webhook_custom_body": "{% if status %}down"}`, wantErr: true},
+		{name: "invalid headers", config: `{"webhook_url": "https://example.com/hook", "webhook_content_type": "json", "webhook_additional_headers": "Authorization: Bearer"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sender.Validate(tt.config)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+// webhookRequest is what a test server received
+type webhookRequest struct {
+	method      string
+	contentType string
+	query       url.Values
+	body        string
+	headers     http.Header
+}
+
+func sendWebhook(t *testing.T, cfg map[string]any) *webhookRequest {
+	t.Helper()
+	received := &webhookRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received.method = r.Method
+		received.contentType = r.Header.Get("Content-Type")
+		received.query = r.URL.Query()
+		received.body = string(body)
+		received.headers = r.Header
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg["webhook_url"] = server.URL + "/hook?source=peekaping"
+	configJSON, _ := json.Marshal(cfg)
+	m := &monitor.Model{ID: "m1", Name: "API"}
+	hb := &heartbeat.Model{MonitorID: "m1", Status: shared.MonitorStatusDown, Msg: "timeout", Time: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)}
+
+	if err := NewWebhookSender(zap.NewNop().Sugar()).Send(context.Background(), string(configJSON), "API went down", m, hb); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+	return received
+}
+
+func TestWebhookSender_Send_CustomJSONTemplate(t *testing.T) {
+	received := sendWebhook(t, map[string]any{
+		"webhook_content_type":       "custom",
+		"webhook_custom_body":        `{"text": "{{ monitor.name }} is {{ status }}: {{ msg }}", "at": "{{ heartbeat.time }}"}`,
+		"webhook_additional_headers": `{"X-Token": "secret"}`,
+	})
+
+	if received.method != http.MethodPost {
+		t.Errorf("expected a POST request, got %s", received.method)
+	}
+	if received.contentType != "application/json" {
+		t.Errorf("expected a JSON content type for a JSON template, got %q", received.contentType)
+	}
+	if received.headers.Get("X-Token") != "secret" {
+		t.Errorf("expected the custom header, got %v", received.headers)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(received.body), &payload); err != nil {
+		t.Fatalf("expected a JSON body, got %q", received.body)
+	}
+	if payload["text"] != "API is DOWN: API went down" {
+		t.Errorf("unexpected rendered text %q", payload["text"])
+	}
+	if payload["at"] != "2025-07-01T12:00:00Z" {
+		t.Errorf("unexpected rendered time %q", payload["at"])
+	}
+}
+
+func TestWebhookSender_Send_FormURLEncoded(t *testing.T) {
+	received := sendWebhook(t, map[string]any{"webhook_content_type": "form-urlencoded"})
+
+	if received.contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("unexpected content type %q", received.contentType)
+	}
+	form, err := url.ParseQuery(received.body)
+	if err != nil {
+		t.Fatalf("expected a form body, got %q", received.body)
+	}
+	if form.Get("name") != "API" || form.Get("status") != "DOWN" || form.Get("msg") != "API went down" || form.Get("monitor_id") != "m1" {
+		t.Errorf("unexpected form fields %v", form)
+	}
+	if !json.Valid([]byte(form.Get("data"))) {
+		t.Errorf("expected the full payload as JSON in data, got %q", form.Get("data"))
+	}
+}
+
+func TestWebhookSender_Send_GetQuery(t *testing.T) {
+	received := sendWebhook(t, map[string]any{"webhook_method": "GET", "webhook_content_type": "form-urlencoded"})
+
+	if received.method != http.MethodGet {
+		t.Errorf("expected a GET request, got %s", received.method)
+	}
+	if received.body != "" {
+		t.Errorf("expected no body, got %q", received.body)
+	}
+	if received.query.Get("source") != "peekaping" {
+		t.Errorf("expected the parameters of the URL to be kept, got %v", received.query)
+	}
+	if received.query.Get("status") != "DOWN" || received.query.Get("time") != "2025-07-01T12:00:00Z" {
+		t.Errorf("unexpected query %v", received.query)
+	}
+}
@@ -0,0 +1,110 @@
+package notification_channel
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// maxRetryDelay caps the backoff between two send attempts
+const maxRetryDelay = time.Minute
+
+// retryPolicy decides how often a failed send is attempted and how long to
+// wait in between, the zero value sends once
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+func (p retryPolicy) maxAttempts() int {
+	if p.attempts < 1 {
+		return 1
+	}
+	return p.attempts
+}
+
+// delay returns the backoff before the given retry, 1 being the first. The
+// delay doubles with every retry and half of it is random, so channels that
+// failed together do not retry in lockstep.
+func (p retryPolicy) delay(retry int) time.Duration {
+	d := maxRetryDelay
+	if retry < 32 {
+		if backoff := p.baseDelay << (retry - 1); backoff > 0 && backoff < maxRetryDelay {
+			d = backoff
+		}
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// errSuperseded ends the retries of a notification once a newer one of the
+// same monitor went to the channel
+var errSuperseded = errors.New("superseded by a newer notification")
+
+// sendSequence numbers the notifications of every monitor and channel, so a
+// send still retrying can tell that a newer one was sent meanwhile
+type sendSequence struct {
+	mu     sync.Mutex
+	next   uint64
+	latest map[string]uint64
+}
+
+// start numbers a new notification for the key
+func (s *sendSequence) start(key string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latest == nil {
+		s.latest = make(map[string]uint64)
+	}
+	s.next++
+	s.latest[key] = s.next
+	return s.next
+}
+
+// superseded reports whether a newer notification started for the key
+func (s *sendSequence) superseded(key string, seq uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest[key] != seq
+}
+
+// done forgets the key unless a newer notification started
+func (s *sendSequence) done(key string, seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latest[key] == seq {
+		delete(s.latest, key)
+	}
+}
+
+// sendWithRetry calls send until it succeeds, the attempts are used up or the
+// context ends. It returns the number of attempts made and the last error.
+func sendWithRetry(ctx context.Context, policy retryPolicy, wait func(context.Context, time.Duration) error, send func() error) (int, error) {
+	var err error
+	attempts := policy.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		if err = send(); err == nil {
+			return attempt, nil
+		}
+		if attempt == attempts || errors.Is(err, context.Canceled) || errors.Is(err, errSuperseded) {
+			return attempt, err
+		}
+		if waitErr := wait(ctx, policy.delay(attempt)); waitErr != nil {
+			return attempt, err
+		}
+	}
+}
+
+// waitContext sleeps for d unless the context ends first
+func waitContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
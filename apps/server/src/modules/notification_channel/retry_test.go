@@ -0,0 +1,213 @@
+package notification_channel
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/notification_failure"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// flakyProvider fails the first failures sends
+type flakyProvider struct {
+	failures int
+	calls    int
+}
+
+func (p *flakyProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("502 Bad Gateway")
+	}
+	return nil
+}
+
+func (p *flakyProvider) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	return p.Send(ctx, configJSON, "test", nil, nil)
+}
+
+func (p *flakyProvider) Validate(configJSON string) error { return nil }
+
+func (p *flakyProvider) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+type fakeFailureService struct {
+	notification_failure.Service
+	recorded []*notification_failure.Model
+}
+
+func (s *fakeFailureService) Record(ctx context.Context, failure *notification_failure.Model) (*notification_failure.Model, error) {
+	s.recorded = append(s.recorded, failure)
+	return failure, nil
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := retryPolicy{attempts: 5, baseDelay: time.Second}
+
+	for retry, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := policy.delay(retry); d < base/2 || d > base {
+				t.Fatalf("retry %d: expected a delay between %s and %s, got %s", retry, base/2, base, d)
+			}
+		}
+	}
+
+	if d := policy.delay(40); d < maxRetryDelay/2 || d > maxRetryDelay {
+		t.Errorf("expected the delay to be capped at %s, got %s", maxRetryDelay, d)
+	}
+}
+
+func TestSendWithRetry(t *testing.T) {
+	var waits []time.Duration
+	wait := func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	policy := retryPolicy{attempts: 3, baseDelay: 10 * time.Millisecond}
+
+	calls := 0
+	attempts, err := sendWithRetry(context.Background(), policy, wait, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 || len(waits) != 1 {
+		t.Errorf("expected success on the second attempt after one wait, got %d attempts, %d waits, %v", attempts, len(waits), err)
+	}
+
+	waits = nil
+	attempts, err = sendWithRetry(context.Background(), policy, wait, func() error { return errors.New("503") })
+	if err == nil || attempts != 3 || len(waits) != 2 {
+		t.Errorf("expected to give up after 3 attempts and 2 waits, got %d attempts, %d waits, %v", attempts, len(waits), err)
+	}
+
+	attempts, _ = sendWithRetry(context.Background(), retryPolicy{}, wait, func() error { return errors.New("503") })
+	if attempts != 1 {
+		t.Errorf("expected the zero policy to send once, got %d attempts", attempts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts, _ = sendWithRetry(ctx, policy, waitContext, func() error { return errors.New("503") })
+	if attempts != 1 {
+		t.Errorf("expected no retry once the context ended, got %d attempts", attempts)
+	}
+}
+
+func newRetryTestListener(t *testing.T, provider NotificationChannelProvider, failures *fakeFailureService) *NotificationEventListener {
+	RegisterNotificationChannelProvider("flaky", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "flaky") })
+
+	config := "webhook"
+	listener := &NotificationEventListener{
+		service: &fakeChannels{channels: map[string]*Model{
+			"a": {ID: "a", Name: "Webhook", Type: "flaky", Config: &config},
+		}},
+		monitorSvc:                 &fakeMonitors{},
+		monitorNotificationService: &fakeMonitorNotifications{channelsByMonitor: map[string][]string{"api": {"a"}}},
+		logger:                     zap.NewNop().Sugar(),
+		retry:                      retryPolicy{attempts: 3, baseDelay: time.Millisecond},
+		wait:                       func(ctx context.Context, d time.Duration) error { return nil },
+	}
+	if failures != nil {
+		listener.failureService = failures
+	}
+	return listener
+}
+
+func TestListener_RetriesFailedSends(t *testing.T) {
+	provider := &flakyProvider{failures: 2}
+	failures := &fakeFailureService{}
+	listener := newRetryTestListener(t, provider, failures)
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusUp))
+
+	if provider.calls != 3 {
+		t.Errorf("expected the send to succeed on the third attempt, got %d calls", provider.calls)
+	}
+	if len(failures.recorded) != 0 {
+		t.Errorf("expected no failure to be recorded, got %d", len(failures.recorded))
+	}
+}
+
+func TestListener_RecordsExhaustedSends(t *testing.T) {
+	provider := &flakyProvider{failures: 10}
+	failures := &fakeFailureService{}
+	listener := newRetryTestListener(t, provider, failures)
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusUp))
+
+	if provider.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", provider.calls)
+	}
+	if len(failures.recorded) != 1 {
+		t.Fatalf("expected the failure to be recorded, got %d", len(failures.recorded))
+	}
+	failure := failures.recorded[0]
+	if failure.NotificationChannelID != "a" || failure.ChannelType != "flaky" || failure.MonitorID != "api" {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+	if failure.Attempts != 3 || failure.Error != "502 Bad Gateway" || failure.Message != "check" {
+		t.Errorf("unexpected failure details %+v", failure)
+	}
+}
+
+func TestListener_FailuresNotPersistedByDefault(t *testing.T) {
+	provider := &flakyProvider{failures: 10}
+	listener := newRetryTestListener(t, provider, nil)
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusUp))
+
+	if provider.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", provider.calls)
+	}
+}
+
+// statusProvider fails every DOWN notification and records the delivered ones
+type statusProvider struct {
+	flakyProvider
+	delivered []shared.MonitorStatus
+}
+
+func (p *statusProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	p.calls++
+	if hb.Status == shared.MonitorStatusDown {
+		return errors.New("502 Bad Gateway")
+	}
+	p.delivered = append(p.delivered, hb.Status)
+	return nil
+}
+
+func TestListener_DropsRetryOfSupersededNotification(t *testing.T) {
+	provider := &statusProvider{}
+	failures := &fakeFailureService{}
+	listener := newRetryTestListener(t, provider, failures)
+
+	// the monitor recovers while the DOWN notification waits for its retry
+	recovered := false
+	listener.wait = func(ctx context.Context, d time.Duration) error {
+		if !recovered {
+			recovered = true
+			listener.handleNotifyEvent(statusEvent(shared.MonitorStatusUp))
+		}
+		return nil
+	}
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+
+	if len(provider.delivered) != 1 || provider.delivered[0] != shared.MonitorStatusUp {
+		t.Errorf("expected only the UP notification to be delivered, got %v", provider.delivered)
+	}
+	if provider.calls != 2 {
+		t.Errorf("expected the DOWN retry to be dropped after the UP, got %d calls", provider.calls)
+	}
+	if len(failures.recorded) != 0 {
+		t.Errorf("expected no failure for a superseded notification, got %d", len(failures.recorded))
+	}
+}
@@ -10,4 +10,31 @@ type NotificationChannelProvider interface {
 	Send(ctx context.Context, configJSON, message string, monitor *monitor.Model, heartbeat *heartbeat.Model) error
 	Validate(configJSON string) error
 	Unmarshal(configJSON string) (any, error)
+	// TestSend sends a synthetic event of the given status, so a channel can be
+	// checked before relying on it
+	TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error
+}
+
+// ConfigVerifier is implemented by providers that can check a config against
+// their API, it is called when a channel is saved and not on every send
+type ConfigVerifier interface {
+	Verify(ctx context.Context, configJSON string) error
+}
+
+// PerMonitorIncidents is implemented by providers that keep one incident per
+// monitor, keyed on its ID. Their notifications are never coalesced, a grouped
+// incident would not be resolved by the recovery of any single monitor.
+type PerMonitorIncidents interface {
+	PerMonitorIncidents() bool
+}
+
+// keepsPerMonitorIncidents tells whether the provider of the channel keeps an
+// incident per monitor
+func keepsPerMonitorIncidents(channel *Model) bool {
+	provider, ok := GetNotificationChannelProvider(channel.Type)
+	if !ok {
+		return false
+	}
+	keyed, ok := provider.(PerMonitorIncidents)
+	return ok && keyed.PerMonitorIncidents()
 }
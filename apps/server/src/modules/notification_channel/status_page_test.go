@@ -0,0 +1,95 @@
+package notification_channel
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/status_page"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type fakeMonitorStatusPages struct {
+	monitor_status_page.Service
+	pagesByMonitor map[string][]*monitor_status_page.Model
+}
+
+func (f *fakeMonitorStatusPages) GetStatusPagesForMonitor(ctx context.Context, monitorID string) ([]*monitor_status_page.Model, error) {
+	return f.pagesByMonitor[monitorID], nil
+}
+
+type fakeStatusPages struct {
+	status_page.Service
+	pages map[string]*status_page.Model
+}
+
+func (f *fakeStatusPages) FindByID(ctx context.Context, id string) (*status_page.Model, error) {
+	return f.pages[id], nil
+}
+
+func newStatusPageTestListener(t *testing.T) (*NotificationEventListener, *recordingProvider) {
+	provider := &recordingProvider{}
+	RegisterNotificationChannelProvider("recording", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "recording") })
+
+	configs := map[string]string{"oncall": "oncall", "subscribers": "subscribers", "muted": "muted", "hidden": "hidden"}
+	channels := map[string]*Model{}
+	for id := range configs {
+		config := configs[id]
+		channels[id] = &Model{ID: id, Name: id, Type: "recording", Config: &config}
+	}
+
+	listener := &NotificationEventListener{
+		service:                    &fakeChannels{channels: channels},
+		monitorSvc:                 &fakeMonitors{},
+		monitorNotificationService: &fakeMonitorNotifications{channelsByMonitor: map[string][]string{"api": {"oncall"}}},
+		monitorStatusPageService: &fakeMonitorStatusPages{pagesByMonitor: map[string][]*monitor_status_page.Model{
+			"api": {
+				{StatusPageID: "public", MonitorID: "api", Active: true},
+				{StatusPageID: "internal", MonitorID: "api", Active: true},
+				{StatusPageID: "archived", MonitorID: "api", Active: false},
+			},
+		}},
+		statusPageService: &fakeStatusPages{pages: map[string]*status_page.Model{
+			"public":   {ID: "public", NotifyIncidents: true, NotificationIDs: []string{"subscribers", "oncall"}},
+			"internal": {ID: "internal", NotificationIDs: []string{"muted"}},
+			"archived": {ID: "archived", NotifyIncidents: true, NotificationIDs: []string{"hidden"}},
+		}},
+		logger: zap.NewNop().Sugar(),
+	}
+	return listener, provider
+}
+
+func TestListener_StatusPageIncidentsNotifyPageChannels(t *testing.T) {
+	listener, provider := newStatusPageTestListener(t)
+
+	listener.handleNotifyEvent(statusEvent(shared.MonitorStatusDown))
+
+	if sentCount(provider) != 2 {
+		t.Fatalf("expected the monitor and status page channels once each, got %+v", provider.sent)
+	}
+	for _, channel := range []string{"oncall", "subscribers"} {
+		if len(provider.byChannel(channel)) != 1 {
+			t.Errorf("expected one notification through %s, got %d", channel, len(provider.byChannel(channel)))
+		}
+	}
+	if len(provider.byChannel("muted")) != 0 || len(provider.byChannel("hidden")) != 0 {
+		t.Error("expected pages without the toggle and inactive monitors to be skipped")
+	}
+}
+
+func TestListener_SlowCheckSkipsStatusPages(t *testing.T) {
+	listener, provider := newStatusPageTestListener(t)
+
+	listener.handleSlowCheckEvent(events.Event{
+		Type:    events.MonitorSlowCheck,
+		Payload: &heartbeat.Model{MonitorID: "api", Status: shared.MonitorStatusUp, Msg: "slow"},
+	})
+
+	if sentCount(provider) != 1 || len(provider.byChannel("oncall")) != 1 {
+		t.Errorf("expected only the monitor channel to be notified, got %+v", provider.sent)
+	}
+}
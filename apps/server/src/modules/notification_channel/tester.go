@@ -0,0 +1,96 @@
+package notification_channel
+
+import (
+	"context"
+	"peekaping/src/modules/heartbeat"
+	"sync"
+	"time"
+)
+
+// channelsPageSize is the page size used to load every channel for a test
+const channelsPageSize = 100
+
+// maxConcurrentTests caps how many channels are tested at the same time
+const maxConcurrentTests = 8
+
+// ChannelTestResult is the outcome of a test send through one channel
+type ChannelTestResult struct {
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Active    bool   `json:"active"`
+	Success   bool   `json:"success"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TestAllReport aggregates the test sends of a batch, results keep the order
+// the channels were tested in
+type TestAllReport struct {
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []ChannelTestResult `json:"results"`
+}
+
+// testChannels sends a test event of the status through every channel
+// concurrently. Each send is bounded by timeout, so a hanging provider only
+// fails its own channel.
+func testChannels(ctx context.Context, channels []*Model, status heartbeat.MonitorStatus, timeout time.Duration) *TestAllReport {
+	results := make([]ChannelTestResult, len(channels))
+	slots := make(chan struct{}, maxConcurrentTests)
+
+	var wg sync.WaitGroup
+	for i, channel := range channels {
+		wg.Add(1)
+		go func(i int, channel *Model) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = testChannel(ctx, channel, status, timeout)
+		}(i, channel)
+	}
+	wg.Wait()
+
+	report := &TestAllReport{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+func testChannel(ctx context.Context, channel *Model, status heartbeat.MonitorStatus, timeout time.Duration) ChannelTestResult {
+	result := ChannelTestResult{
+		ChannelID: channel.ID,
+		Name:      channel.Name,
+		Type:      channel.Type,
+		Active:    channel.Active,
+	}
+
+	integration, ok := GetNotificationChannelProvider(channel.Type)
+	if !ok {
+		result.Error = "unsupported notification type"
+		return result
+	}
+	config := ""
+	if channel.Config != nil {
+		config = *channel.Config
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := integration.TestSend(sendCtx, config, status)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	return result
+}
@@ -0,0 +1,146 @@
+package notification_channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func (f *fakeChannels) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	ids := make([]string, 0, len(f.channels))
+	for id := range f.channels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var result []*Model
+	for i := page * limit; i < len(ids) && i < (page+1)*limit; i++ {
+		result = append(result, f.channels[ids[i]])
+	}
+	return result, nil
+}
+
+// blockingProvider never answers before the context of the send is done
+type blockingProvider struct {
+	testSendProvider
+}
+
+func (p *blockingProvider) TestSend(ctx context.Context, configJSON string, status shared.MonitorStatus) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func registerTestProviders(t *testing.T) {
+	providers := map[string]NotificationChannelProvider{
+		"test-ok":      &testSendProvider{},
+		"test-failing": &testSendProvider{err: errors.New("401 Unauthorized: invalid token")},
+		"test-hanging": &blockingProvider{},
+	}
+	for name, provider := range providers {
+		RegisterNotificationChannelProvider(name, provider)
+	}
+	t.Cleanup(func() {
+		for name := range providers {
+			delete(NotificationChannelProviderRegistry, name)
+		}
+	})
+}
+
+func testChannelsFixture() map[string]*Model {
+	return map[string]*Model{
+		"a": {ID: "a", Name: "Slack", Type: "test-ok", Active: true},
+		"b": {ID: "b", Name: "PagerDuty", Type: "test-failing", Active: true},
+		"c": {ID: "c", Name: "Webhook", Type: "test-hanging"},
+		"d": {ID: "d", Name: "Legacy", Type: "removed-provider"},
+	}
+}
+
+func TestTestChannels_AggregatesResults(t *testing.T) {
+	registerTestProviders(t)
+	fixture := testChannelsFixture()
+	channels := []*Model{fixture["a"], fixture["b"], fixture["c"], fixture["d"]}
+
+	report := testChannels(context.Background(), channels, shared.MonitorStatusDown, 50*time.Millisecond)
+
+	if report.Total != 4 || report.Succeeded != 1 || report.Failed != 3 {
+		t.Fatalf("expected 1 of 4 channels to succeed, got %+v", report)
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if report.Results[i].ChannelID != id {
+			t.Fatalf("expected the results in channel order, got %+v", report.Results)
+		}
+	}
+	if !report.Results[0].Success || report.Results[0].Error != "" {
+		t.Errorf("expected the working channel to succeed, got %+v", report.Results[0])
+	}
+	if report.Results[1].Error != "401 Unauthorized: invalid token" {
+		t.Errorf("expected the provider error, got %q", report.Results[1].Error)
+	}
+	if !strings.Contains(report.Results[2].Error, "deadline exceeded") || report.Results[2].LatencyMs < 50 {
+		t.Errorf("expected the hanging channel to time out, got %+v", report.Results[2])
+	}
+	if report.Results[3].Error != "unsupported notification type" {
+		t.Errorf("expected an unsupported type error, got %q", report.Results[3].Error)
+	}
+}
+
+func TestController_TestAll(t *testing.T) {
+	registerTestProviders(t)
+	controller := NewController(&fakeChannels{channels: testChannelsFixture()}, zap.NewNop().Sugar())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/notification-channels/test-all", controller.TestAll)
+
+	post := func(body string) (int, TestAllReport) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/notification-channels/test-all", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+
+		var response struct {
+			Data TestAllReport `json:"data"`
+		}
+		json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder.Code, response.Data
+	}
+
+	t.Run("selected channels", func(t *testing.T) {
+		code, report := post(`{"channel_ids": ["a", "b", "a", "missing"], "status": "up"}`)
+		if code != http.StatusOK {
+			t.Fatalf("expected a report, got %d", code)
+		}
+		if report.Total != 3 || report.Succeeded != 1 || report.Failed != 2 {
+			t.Fatalf("expected the selected channels and the missing one, got %+v", report)
+		}
+		if missing := report.Results[2]; missing.ChannelID != "missing" || missing.Error != "notification channel not found" {
+			t.Errorf("expected the unknown channel to be reported, got %+v", missing)
+		}
+	})
+
+	t.Run("every channel", func(t *testing.T) {
+		code, report := post(`{"timeout_seconds": 1}`)
+		if code != http.StatusOK {
+			t.Fatalf("expected a report, got %d", code)
+		}
+		if report.Total != 4 || report.Succeeded != 1 {
+			t.Errorf("expected every configured channel to be tested, got %+v", report)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if code, _ := post(`{"timeout_seconds": 600}`); code != http.StatusBadRequest {
+			t.Errorf("expected a too long timeout to be rejected, got %d", code)
+		}
+	})
+}
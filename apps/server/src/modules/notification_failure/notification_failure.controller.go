@@ -0,0 +1,58 @@
+package notification_failure
+
+import (
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router		/notification-failures [get]
+// @Summary		Get notifications that failed after every retry
+// @Tags			Notification channels
+// @Produce		json
+// @Security	BearerAuth
+// @Param		notification_channel_id	query	string	false	"Only failures of this channel"
+// @Param		page	query	int	false	"Page number"	default(0)
+// @Param		limit	query	int	false	"Items per page"	default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindAll(ctx *gin.Context) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return
+	}
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return
+	}
+
+	entities, err := ic.service.FindAll(ctx, page, limit, ctx.Query("notification_channel_id"))
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch notification failures", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entities))
+}
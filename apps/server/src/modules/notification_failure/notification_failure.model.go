@@ -0,0 +1,17 @@
+package notification_failure
+
+import "time"
+
+// Model is a notification that could not be delivered after every retry. The
+// monitor id of coalesced notifications is a group-* id.
+type Model struct {
+	ID                    string    `json:"id"`
+	NotificationChannelID string    `json:"notification_channel_id"`
+	ChannelName           string    `json:"channel_name"`
+	ChannelType           string    `json:"channel_type"`
+	MonitorID             string    `json:"monitor_id"`
+	Message               string    `json:"message"`
+	Error                 string    `json:"error"`
+	Attempts              int       `json:"attempts"`
+	FailedAt              time.Time `json:"failed_at"`
+}
@@ -0,0 +1,122 @@
+package notification_failure
+
+import (
+	"context"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoModel keeps the channel and monitor ids as strings, coalesced
+// notifications are sent for group-* monitors that are not ObjectIDs
+type mongoModel struct {
+	ID                    primitive.ObjectID `bson:"_id"`
+	NotificationChannelID string             `bson:"notification_channel_id"`
+	ChannelName           string             `bson:"channel_name"`
+	ChannelType           string             `bson:"channel_type"`
+	MonitorID             string             `bson:"monitor_id"`
+	Message               string             `bson:"message"`
+	Error                 string             `bson:"error"`
+	Attempts              int                `bson:"attempts"`
+	FailedAt              time.Time          `bson:"failed_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:                    mm.ID.Hex(),
+		NotificationChannelID: mm.NotificationChannelID,
+		ChannelName:           mm.ChannelName,
+		ChannelType:           mm.ChannelType,
+		MonitorID:             mm.MonitorID,
+		Message:               mm.Message,
+		Error:                 mm.Error,
+		Attempts:              mm.Attempts,
+		FailedAt:              mm.FailedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("notification_failures")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "notification_channel_id", Value: 1},
+			{Key: "failed_at", Value: -1},
+		},
+	})
+	if err != nil {
+		panic("Failed to create index for notification_failures: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	mm := &mongoModel{
+		ID:                    primitive.NewObjectID(),
+		NotificationChannelID: model.NotificationChannelID,
+		ChannelName:           model.ChannelName,
+		ChannelType:           model.ChannelType,
+		MonitorID:             model.MonitorID,
+		Message:               model.Message,
+		Error:                 model.Error,
+		Attempts:              model.Attempts,
+		FailedAt:              model.FailedAt,
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mm); err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindAll(ctx context.Context, page int, limit int, notificationChannelID string) ([]*Model, error) {
+	filter := bson.M{}
+	if notificationChannelID != "" {
+		filter["notification_channel_id"] = notificationChannelID
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "failed_at", Value: -1}}).
+		SetSkip(int64(page * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	models := []*Model{}
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModelFromMongo(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func (r *MongoRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"failed_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,13 @@
+package notification_failure
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	// FindAll returns the failures newest first, optionally of one channel
+	FindAll(ctx context.Context, page int, limit int, notificationChannelID string) ([]*Model, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,32 @@
+package notification_failure
+
+import (
+	"peekaping/src/modules/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+) *Route {
+	return &Route{
+		controller, middleware,
+	}
+}
+
+func (uc *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	router := rg.Group("/notification-failures")
+
+	router.Use(uc.middleware.Auth())
+
+	router.GET("", uc.controller.FindAll)
+}
@@ -0,0 +1,45 @@
+package notification_failure
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Record stores a notification that failed after every retry
+	Record(ctx context.Context, failure *Model) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int, notificationChannelID string) ([]*Model, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[notification-failure-service]"),
+	}
+}
+
+func (s *ServiceImpl) Record(ctx context.Context, failure *Model) (*Model, error) {
+	if failure.FailedAt.IsZero() {
+		failure.FailedAt = time.Now().UTC()
+	}
+	return s.repository.Create(ctx, failure)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int, notificationChannelID string) ([]*Model, error) {
+	return s.repository.FindAll(ctx, page, limit, notificationChannelID)
+}
+
+func (s *ServiceImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repository.DeleteOlderThan(ctx, cutoff)
+}
@@ -0,0 +1,100 @@
+package notification_failure
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:notification_failures,alias:nf"`
+
+	ID                    string    `bun:"id,pk"`
+	NotificationChannelID string    `bun:"notification_channel_id,notnull"`
+	ChannelName           string    `bun:"channel_name,notnull"`
+	ChannelType           string    `bun:"channel_type,notnull"`
+	MonitorID             string    `bun:"monitor_id"`
+	Message               string    `bun:"message"`
+	Error                 string    `bun:"error,notnull"`
+	Attempts              int       `bun:"attempts,notnull"`
+	FailedAt              time.Time `bun:"failed_at,notnull"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:                    sm.ID,
+		NotificationChannelID: sm.NotificationChannelID,
+		ChannelName:           sm.ChannelName,
+		ChannelType:           sm.ChannelType,
+		MonitorID:             sm.MonitorID,
+		Message:               sm.Message,
+		Error:                 sm.Error,
+		Attempts:              sm.Attempts,
+		FailedAt:              sm.FailedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	sm := &sqlModel{
+		ID:                    uuid.New().String(),
+		NotificationChannelID: model.NotificationChannelID,
+		ChannelName:           model.ChannelName,
+		ChannelType:           model.ChannelType,
+		MonitorID:             model.MonitorID,
+		Message:               model.Message,
+		Error:                 model.Error,
+		Attempts:              model.Attempts,
+		FailedAt:              model.FailedAt,
+	}
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindAll(ctx context.Context, page int, limit int, notificationChannelID string) ([]*Model, error) {
+	query := r.db.NewSelect().Model((*sqlModel)(nil))
+
+	if notificationChannelID != "" {
+		query = query.Where("notification_channel_id = ?", notificationChannelID)
+	}
+
+	query = query.Order("failed_at DESC").
+		Limit(limit).
+		Offset(page * limit)
+
+	var sms []*sqlModel
+	if err := query.Scan(ctx, &sms); err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.NewDelete().
+		Model((*sqlModel)(nil)).
+		Where("failed_at < ?", cutoff).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,9 @@
+package provisioning
+
+import (
+	"go.uber.org/dig"
+)
+
+func RegisterDependencies(container *dig.Container) {
+	container.Provide(NewService)
+}
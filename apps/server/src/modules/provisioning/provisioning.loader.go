@@ -0,0 +1,33 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile reads a monitors file, the format is picked from the extension
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitors file: %w", err)
+	}
+
+	file := &File{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, file)
+	case ".json":
+		err = json.Unmarshal(data, file)
+	default:
+		return nil, fmt.Errorf("unsupported monitors file extension %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse monitors file: %w", err)
+	}
+	return file, nil
+}
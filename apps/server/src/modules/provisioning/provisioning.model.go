@@ -0,0 +1,98 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"peekaping/src/modules/monitor"
+)
+
+// File is a declarative list of monitors, monitors are matched by name
+type File struct {
+	Monitors []*MonitorSpec `json:"monitors" yaml:"monitors"`
+}
+
+// MonitorSpec declares a monitor. Config is the executor config of the
+// monitor type written inline instead of as a JSON string.
+type MonitorSpec struct {
+	Name           string         `json:"name" yaml:"name"`
+	Type           string         `json:"type" yaml:"type"`
+	Interval       int            `json:"interval" yaml:"interval"`
+	Timeout        int            `json:"timeout" yaml:"timeout"`
+	MaxRetries     int            `json:"max_retries" yaml:"max_retries"`
+	RetryInterval  int            `json:"retry_interval" yaml:"retry_interval"`
+	ResendInterval int            `json:"resend_interval" yaml:"resend_interval"`
+	Active         *bool          `json:"active" yaml:"active"`
+	Config         map[string]any `json:"config" yaml:"config"`
+	ProxyId        string         `json:"proxy_id" yaml:"proxy_id"`
+	PushToken      string         `json:"push_token" yaml:"push_token"`
+
+	IgnoreMaintenance    bool   `json:"ignore_maintenance" yaml:"ignore_maintenance"`
+	RetentionDays        int    `json:"retention_days" yaml:"retention_days"`
+	SlowCheckThreshold   int    `json:"slow_check_threshold" yaml:"slow_check_threshold"`
+	Notes                string `json:"notes" yaml:"notes"`
+	RunbookURL           string `json:"runbook_url" yaml:"runbook_url"`
+	LightProbeAfter      int    `json:"light_probe_after" yaml:"light_probe_after"`
+	LightProbeInterval   int    `json:"light_probe_interval" yaml:"light_probe_interval"`
+	StateWebhookURL      string `json:"state_webhook_url" yaml:"state_webhook_url"`
+	StateWebhookTemplate string `json:"state_webhook_template" yaml:"state_webhook_template"`
+}
+
+// toDto converts the spec to the dto the monitor service creates and updates
+// monitors from. Omitted intervals fall back to the defaults of the UI.
+func (s *MonitorSpec) toDto() (*monitor.CreateUpdateDto, error) {
+	config := "{}"
+	if len(s.Config) > 0 {
+		encoded, err := json.Marshal(s.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		config = string(encoded)
+	}
+
+	active := true
+	if s.Active != nil {
+		active = *s.Active
+	}
+
+	return &monitor.CreateUpdateDto{
+		Type:            s.Type,
+		Name:            s.Name,
+		Interval:        withDefault(s.Interval, 60),
+		Timeout:         withDefault(s.Timeout, 48),
+		MaxRetries:      s.MaxRetries,
+		RetryInterval:   withDefault(s.RetryInterval, 60),
+		ResendInterval:  s.ResendInterval,
+		Active:          active,
+		NotificationIds: []string{},
+		ProxyId:         s.ProxyId,
+		Config:          config,
+		PushToken:       s.PushToken,
+
+		IgnoreMaintenance:    s.IgnoreMaintenance,
+		RetentionDays:        s.RetentionDays,
+		SlowCheckThreshold:   s.SlowCheckThreshold,
+		Notes:                s.Notes,
+		RunbookURL:           s.RunbookURL,
+		LightProbeAfter:      s.LightProbeAfter,
+		LightProbeInterval:   s.LightProbeInterval,
+		StateWebhookURL:      s.StateWebhookURL,
+		StateWebhookTemplate: s.StateWebhookTemplate,
+	}, nil
+}
+
+func withDefault(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// Result lists the names of the monitors touched by a reconciliation
+type Result struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Unchanged []string `json:"unchanged"`
+	// Drifted monitors were changed outside of the file since it was last applied
+	Drifted []string `json:"drifted"`
+}
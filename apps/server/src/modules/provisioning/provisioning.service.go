@@ -0,0 +1,316 @@
+package provisioning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/setting"
+	"peekaping/src/utils"
+
+	"go.uber.org/zap"
+)
+
+// appliedSettingKey stores the fingerprint of every monitor as last applied
+// from the file, used to tell changes made outside of the file
+const appliedSettingKey = "monitors_file_applied"
+
+// monitorsPageSize is the number of monitors loaded per page
+const monitorsPageSize = 100
+
+type Service interface {
+	// Reconcile creates and updates monitors to match the file. When authoritative
+	// is set, monitors not declared in the file are deleted as well. Nothing is
+	// changed when any declared monitor is invalid.
+	Reconcile(ctx context.Context, file *File, authoritative bool) (*Result, error)
+}
+
+type ServiceImpl struct {
+	monitorService monitor.Service
+	settingService setting.Service
+	logger         *zap.SugaredLogger
+}
+
+func NewService(
+	monitorService monitor.Service,
+	settingService setting.Service,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		monitorService: monitorService,
+		settingService: settingService,
+		logger:         logger.Named("[provisioning-service]"),
+	}
+}
+
+func (s *ServiceImpl) Reconcile(ctx context.Context, file *File, authoritative bool) (*Result, error) {
+	desired, err := s.validate(file)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.existingMonitors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := s.loadApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+		Unchanged: []string{},
+		Drifted:   []string{},
+	}
+	nextApplied := make(map[string]string, len(desired))
+	var errs []error
+
+	for _, dto := range desired {
+		want := fingerprintDto(dto)
+		current, ok := existing[dto.Name]
+
+		if !ok {
+			if _, err := s.monitorService.Create(ctx, dto); err != nil {
+				errs = append(errs, fmt.Errorf("failed to create monitor %q: %w", dto.Name, err))
+				continue
+			}
+			s.logger.Infof("Created monitor %q from the monitors file", dto.Name)
+			result.Created = append(result.Created, dto.Name)
+			nextApplied[dto.Name] = want
+			continue
+		}
+
+		have := fingerprintModel(current)
+		if last, managed := applied[dto.Name]; managed && last != have {
+			s.logger.Warnf("Monitor %q was changed outside of the monitors file, the file wins", dto.Name)
+			result.Drifted = append(result.Drifted, dto.Name)
+		}
+
+		if have == want {
+			result.Unchanged = append(result.Unchanged, dto.Name)
+			nextApplied[dto.Name] = want
+			continue
+		}
+
+		if _, err := s.monitorService.UpdateFull(ctx, current.ID, dto); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update monitor %q: %w", dto.Name, err))
+			continue
+		}
+		s.logger.Infof("Updated monitor %q from the monitors file", dto.Name)
+		result.Updated = append(result.Updated, dto.Name)
+		nextApplied[dto.Name] = want
+	}
+
+	if authoritative {
+		for name, m := range existing {
+			if _, declared := desired[name]; declared {
+				continue
+			}
+			if err := s.monitorService.Delete(ctx, m.ID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete monitor %q: %w", name, err))
+				continue
+			}
+			s.logger.Infof("Deleted monitor %q not declared in the monitors file", name)
+			result.Deleted = append(result.Deleted, name)
+		}
+	} else {
+		for name := range applied {
+			if _, declared := desired[name]; !declared {
+				if _, ok := existing[name]; ok {
+					s.logger.Infof("Monitor %q was removed from the monitors file and is kept, the file is additive", name)
+				}
+			}
+		}
+	}
+
+	if err := s.saveApplied(ctx, nextApplied); err != nil {
+		errs = append(errs, err)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// validate converts every spec and checks it the way the API would
+func (s *ServiceImpl) validate(file *File) (map[string]*monitor.CreateUpdateDto, error) {
+	desired := make(map[string]*monitor.CreateUpdateDto, len(file.Monitors))
+	var errs []error
+
+	for i, spec := range file.Monitors {
+		if spec == nil {
+			errs = append(errs, fmt.Errorf("monitor %d: empty entry", i))
+			continue
+		}
+		if _, duplicate := desired[spec.Name]; duplicate {
+			errs = append(errs, fmt.Errorf("monitor %q: declared more than once", spec.Name))
+			continue
+		}
+
+		dto, err := spec.toDto()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("monitor %q: %w", spec.Name, err))
+			continue
+		}
+		if err := utils.Validate.Struct(dto); err != nil {
+			errs = append(errs, fmt.Errorf("monitor %q: %w", spec.Name, err))
+			continue
+		}
+		if err := s.monitorService.ValidateMonitorConfig(dto.Type, dto.Config); err != nil {
+			errs = append(errs, fmt.Errorf("monitor %q: invalid monitor configuration: %w", spec.Name, err))
+			continue
+		}
+		if err := monitor.ValidateStateWebhookTemplate(dto.StateWebhookTemplate); err != nil {
+			errs = append(errs, fmt.Errorf("monitor %q: %w", spec.Name, err))
+			continue
+		}
+		desired[spec.Name] = dto
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid monitors file: %w", errors.Join(errs...))
+	}
+	return desired, nil
+}
+
+// existingMonitors loads every monitor keyed by name
+func (s *ServiceImpl) existingMonitors(ctx context.Context) (map[string]*monitor.Model, error) {
+	existing := make(map[string]*monitor.Model)
+	for page := 0; ; page++ {
+		monitors, err := s.monitorService.FindAll(ctx, page, monitorsPageSize, "", nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list monitors: %w", err)
+		}
+		for _, m := range monitors {
+			if _, ok := existing[m.Name]; ok {
+				s.logger.Warnf("Several monitors are named %q, only one is matched against the monitors file", m.Name)
+				continue
+			}
+			existing[m.Name] = m
+		}
+		if len(monitors) < monitorsPageSize {
+			return existing, nil
+		}
+	}
+}
+
+func (s *ServiceImpl) loadApplied(ctx context.Context) (map[string]string, error) {
+	applied := map[string]string{}
+	stored, err := s.settingService.GetByKey(ctx, appliedSettingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the applied monitors: %w", err)
+	}
+	if stored == nil || stored.Value == "" {
+		return applied, nil
+	}
+	if err := json.Unmarshal([]byte(stored.Value), &applied); err != nil {
+		s.logger.Warnf("Ignoring unreadable %s setting: %v", appliedSettingKey, err)
+		return map[string]string{}, nil
+	}
+	return applied, nil
+}
+
+func (s *ServiceImpl) saveApplied(ctx context.Context, applied map[string]string) error {
+	encoded, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	if _, err := s.settingService.SetByKey(ctx, appliedSettingKey, &setting.CreateUpdateDto{
+		Value: string(encoded),
+		Type:  "json",
+	}); err != nil {
+		return fmt.Errorf("failed to store the applied monitors: %w", err)
+	}
+	return nil
+}
+
+// fingerprint is the part of a monitor the file manages
+type fingerprint struct {
+	Type                 string `json:"type"`
+	Interval             int    `json:"interval"`
+	Timeout              int    `json:"timeout"`
+	MaxRetries           int    `json:"max_retries"`
+	RetryInterval        int    `json:"retry_interval"`
+	ResendInterval       int    `json:"resend_interval"`
+	Active               bool   `json:"active"`
+	Config               any    `json:"config"`
+	ProxyId              string `json:"proxy_id"`
+	PushToken            string `json:"push_token"`
+	IgnoreMaintenance    bool   `json:"ignore_maintenance"`
+	RetentionDays        int    `json:"retention_days"`
+	SlowCheckThreshold   int    `json:"slow_check_threshold"`
+	Notes                string `json:"notes"`
+	RunbookURL           string `json:"runbook_url"`
+	LightProbeAfter      int    `json:"light_probe_after"`
+	LightProbeInterval   int    `json:"light_probe_interval"`
+	StateWebhookURL      string `json:"state_webhook_url"`
+	StateWebhookTemplate string `json:"state_webhook_template"`
+}
+
+func (f *fingerprint) hash() string {
+	// the re-encoded config has sorted keys, so key order never counts as a change
+	encoded, _ := json.Marshal(f)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizedConfig(config string) any {
+	var decoded any
+	if err := json.Unmarshal([]byte(config), &decoded); err != nil {
+		return config
+	}
+	return decoded
+}
+
+func fingerprintDto(dto *monitor.CreateUpdateDto) string {
+	return (&fingerprint{
+		Type:                 dto.Type,
+		Interval:             dto.Interval,
+		Timeout:              dto.Timeout,
+		MaxRetries:           dto.MaxRetries,
+		RetryInterval:        dto.RetryInterval,
+		ResendInterval:       dto.ResendInterval,
+		Active:               dto.Active,
+		Config:               normalizedConfig(dto.Config),
+		ProxyId:              dto.ProxyId,
+		PushToken:            dto.PushToken,
+		IgnoreMaintenance:    dto.IgnoreMaintenance,
+		RetentionDays:        dto.RetentionDays,
+		SlowCheckThreshold:   dto.SlowCheckThreshold,
+		Notes:                dto.Notes,
+		RunbookURL:           dto.RunbookURL,
+		LightProbeAfter:      dto.LightProbeAfter,
+		LightProbeInterval:   dto.LightProbeInterval,
+		StateWebhookURL:      dto.StateWebhookURL,
+		StateWebhookTemplate: dto.StateWebhookTemplate,
+	}).hash()
+}
+
+func fingerprintModel(m *monitor.Model) string {
+	return (&fingerprint{
+		Type:                 m.Type,
+		Interval:             m.Interval,
+		Timeout:              m.Timeout,
+		MaxRetries:           m.MaxRetries,
+		RetryInterval:        m.RetryInterval,
+		ResendInterval:       m.ResendInterval,
+		Active:               m.Active,
+		Config:               normalizedConfig(m.Config),
+		ProxyId:              m.ProxyId,
+		PushToken:            m.PushToken,
+		IgnoreMaintenance:    m.IgnoreMaintenance,
+		RetentionDays:        m.RetentionDays,
+		SlowCheckThreshold:   m.SlowCheckThreshold,
+		Notes:                m.Notes,
+		RunbookURL:           m.RunbookURL,
+		LightProbeAfter:      m.LightProbeAfter,
+		LightProbeInterval:   m.LightProbeInterval,
+		StateWebhookURL:      m.StateWebhookURL,
+		StateWebhookTemplate: m.StateWebhookTemplate,
+	}).hash()
+}
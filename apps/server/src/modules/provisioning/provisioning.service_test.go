@@ -0,0 +1,303 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/setting"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeMonitorService keeps monitors in memory and rejects configs of type
+// "http" without an url, standing in for the executor validation
+type fakeMonitorService struct {
+	monitor.Service
+	monitors []*monitor.Model
+	nextID   int
+	writes   int
+}
+
+func (s *fakeMonitorService) FindAll(ctx context.Context, page int, limit int, q string, active *bool, status *int, tagIds []string) ([]*monitor.Model, error) {
+	start := page * limit
+	if start >= len(s.monitors) {
+		return nil, nil
+	}
+	end := start + limit
+	if end > len(s.monitors) {
+		end = len(s.monitors)
+	}
+	return s.monitors[start:end], nil
+}
+
+func (s *fakeMonitorService) ValidateMonitorConfig(monitorType string, configJSON string) error {
+	if monitorType == "http" && !strings.Contains(configJSON, `"url"`) {
+		return errors.New("url is required")
+	}
+	return nil
+}
+
+func (s *fakeMonitorService) Create(ctx context.Context, dto *monitor.CreateUpdateDto) (*monitor.Model, error) {
+	s.nextID++
+	s.writes++
+	m := modelFromDto(fmt.Sprintf("m%d", s.nextID), dto)
+	s.monitors = append(s.monitors, m)
+	return m, nil
+}
+
+func (s *fakeMonitorService) UpdateFull(ctx context.Context, id string, dto *monitor.CreateUpdateDto) (*monitor.Model, error) {
+	s.writes++
+	for i, m := range s.monitors {
+		if m.ID == id {
+			s.monitors[i] = modelFromDto(id, dto)
+			return s.monitors[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeMonitorService) Delete(ctx context.Context, id string) error {
+	s.writes++
+	for i, m := range s.monitors {
+		if m.ID == id {
+			s.monitors = append(s.monitors[:i], s.monitors[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *fakeMonitorService) byName(name string) *monitor.Model {
+	for _, m := range s.monitors {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func modelFromDto(id string, dto *monitor.CreateUpdateDto) *monitor.Model {
+	return &monitor.Model{
+		ID:                   id,
+		Type:                 dto.Type,
+		Name:                 dto.Name,
+		Interval:             dto.Interval,
+		Timeout:              dto.Timeout,
+		MaxRetries:           dto.MaxRetries,
+		RetryInterval:        dto.RetryInterval,
+		ResendInterval:       dto.ResendInterval,
+		Active:               dto.Active,
+		ProxyId:              dto.ProxyId,
+		Config:               dto.Config,
+		PushToken:            dto.PushToken,
+		IgnoreMaintenance:    dto.IgnoreMaintenance,
+		RetentionDays:        dto.RetentionDays,
+		SlowCheckThreshold:   dto.SlowCheckThreshold,
+		Notes:                dto.Notes,
+		RunbookURL:           dto.RunbookURL,
+		LightProbeAfter:      dto.LightProbeAfter,
+		LightProbeInterval:   dto.LightProbeInterval,
+		StateWebhookURL:      dto.StateWebhookURL,
+		StateWebhookTemplate: dto.StateWebhookTemplate,
+	}
+}
+
+type fakeSettingService struct {
+	setting.Service
+	values map[string]string
+}
+
+func (s *fakeSettingService) GetByKey(ctx context.Context, key string) (*setting.Model, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, nil
+	}
+	return &setting.Model{Key: key, Value: value}, nil
+}
+
+func (s *fakeSettingService) SetByKey(ctx context.Context, key string, entity *setting.CreateUpdateDto) (*setting.Model, error) {
+	s.values[key] = entity.Value
+	return &setting.Model{Key: key, Value: entity.Value, Type: entity.Type}, nil
+}
+
+func newTestService() (*ServiceImpl, *fakeMonitorService) {
+	monitors := &fakeMonitorService{}
+	service := NewService(monitors, &fakeSettingService{values: map[string]string{}}, zap.NewNop().Sugar())
+	return service.(*ServiceImpl), monitors
+}
+
+func loadSample(t *testing.T) *File {
+	t.Helper()
+	file, err := LoadFile("testdata/monitors.yaml")
+	if err != nil {
+		t.Fatalf("failed to load the sample file: %v", err)
+	}
+	return file
+}
+
+func assertNames(t *testing.T, kind string, got []string, want ...string) {
+	t.Helper()
+	sorted := append([]string{}, got...)
+	sort.Strings(sorted)
+	sort.Strings(want)
+	if fmt.Sprint(sorted) != fmt.Sprint(want) {
+		t.Errorf("expected %s %v, got %v", kind, want, sorted)
+	}
+}
+
+func TestLoadFile_YAMLAndJSON(t *testing.T) {
+	file := loadSample(t)
+	if len(file.Monitors) != 3 {
+		t.Fatalf("expected 3 monitors, got %d", len(file.Monitors))
+	}
+	api := file.Monitors[0]
+	if api.Name != "Public API" || api.Interval != 30 || api.Config["url"] != "https://api.example.com/health" {
+		t.Errorf("unexpected first monitor %+v", api)
+	}
+	if file.Monitors[2].Active == nil || *file.Monitors[2].Active {
+		t.Error("expected the push monitor to be declared inactive")
+	}
+
+	jsonFile, err := LoadFile("testdata/monitors.json")
+	if err != nil {
+		t.Fatalf("failed to load the json file: %v", err)
+	}
+	if len(jsonFile.Monitors) != 1 || jsonFile.Monitors[0].Name != "Public API" {
+		t.Errorf("unexpected json monitors %+v", jsonFile.Monitors)
+	}
+
+	if _, err := LoadFile("testdata/monitors.toml"); err == nil {
+		t.Error("expected an unsupported extension to be rejected")
+	}
+}
+
+func TestReconcile_CreatesThenIsIdempotent(t *testing.T) {
+	service, monitors := newTestService()
+	file := loadSample(t)
+
+	result, err := service.Reconcile(context.Background(), file, false)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	assertNames(t, "created", result.Created, "Public API", "Database", "Nightly job")
+
+	db := monitors.byName("Database")
+	if db.Interval != 60 || db.Timeout != 20 || !db.Active {
+		t.Errorf("expected defaults to fill the omitted fields, got %+v", db)
+	}
+	if monitors.byName("Nightly job").Active {
+		t.Error("expected the declared inactive monitor to be inactive")
+	}
+
+	writes := monitors.writes
+	result, err = service.Reconcile(context.Background(), loadSample(t), false)
+	if err != nil {
+		t.Fatalf("second reconcile failed: %v", err)
+	}
+	assertNames(t, "unchanged", result.Unchanged, "Public API", "Database", "Nightly job")
+	if monitors.writes != writes {
+		t.Errorf("expected an unchanged file to write nothing, got %d writes", monitors.writes-writes)
+	}
+}
+
+func TestReconcile_UpdatesChangedMonitors(t *testing.T) {
+	service, monitors := newTestService()
+	service.Reconcile(context.Background(), loadSample(t), false)
+
+	file := loadSample(t)
+	file.Monitors[1].Config["port"] = 6432
+	result, err := service.Reconcile(context.Background(), file, false)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	assertNames(t, "updated", result.Updated, "Database")
+	if got := monitors.byName("Database").Config; got != `{"host":"db.internal","port":6432}` {
+		t.Errorf("expected the new port to be applied, got %s", got)
+	}
+	if len(result.Drifted) != 0 {
+		t.Errorf("expected a change in the file not to count as drift, got %v", result.Drifted)
+	}
+}
+
+func TestReconcile_AuthoritativeDeletesUndeclared(t *testing.T) {
+	service, monitors := newTestService()
+	monitors.Create(context.Background(), &monitor.CreateUpdateDto{Name: "Hand made", Type: "ping", Config: "{}"})
+
+	result, err := service.Reconcile(context.Background(), loadSample(t), true)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	assertNames(t, "deleted", result.Deleted, "Hand made")
+	if monitors.byName("Hand made") != nil {
+		t.Error("expected the undeclared monitor to be deleted")
+	}
+	if len(monitors.monitors) != 3 {
+		t.Errorf("expected only the declared monitors to remain, got %d", len(monitors.monitors))
+	}
+}
+
+func TestReconcile_AdditiveKeepsUndeclared(t *testing.T) {
+	service, monitors := newTestService()
+	monitors.Create(context.Background(), &monitor.CreateUpdateDto{Name: "Hand made", Type: "ping", Config: "{}"})
+	service.Reconcile(context.Background(), loadSample(t), false)
+
+	file := loadSample(t)
+	file.Monitors = file.Monitors[:1]
+	result, err := service.Reconcile(context.Background(), file, false)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(result.Deleted) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", result.Deleted)
+	}
+	if len(monitors.monitors) != 4 {
+		t.Errorf("expected every monitor to be kept, got %d", len(monitors.monitors))
+	}
+}
+
+func TestReconcile_InvalidFileAppliesNothing(t *testing.T) {
+	service, monitors := newTestService()
+	file := loadSample(t)
+	delete(file.Monitors[0].Config, "url")
+	file.Monitors = append(file.Monitors, &MonitorSpec{Name: "Database", Type: "tcp"}, &MonitorSpec{Name: "x", Type: "tcp"})
+
+	_, err := service.Reconcile(context.Background(), file, true)
+	if err == nil {
+		t.Fatal("expected the invalid file to be rejected")
+	}
+	for _, want := range []string{`"Public API"`, `"Database": declared more than once`, `"x"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %s, got %v", want, err)
+		}
+	}
+	if monitors.writes != 0 {
+		t.Errorf("expected nothing to be applied, got %d writes", monitors.writes)
+	}
+}
+
+func TestReconcile_ReportsDrift(t *testing.T) {
+	service, monitors := newTestService()
+	service.Reconcile(context.Background(), loadSample(t), false)
+
+	// edited in the UI after the file was applied
+	monitors.byName("Public API").Interval = 300
+
+	result, err := service.Reconcile(context.Background(), loadSample(t), false)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	assertNames(t, "drifted", result.Drifted, "Public API")
+	assertNames(t, "updated", result.Updated, "Public API")
+	if got := monitors.byName("Public API").Interval; got != 30 {
+		t.Errorf("expected the file to win over the drift, got interval %d", got)
+	}
+
+	result, _ = service.Reconcile(context.Background(), loadSample(t), false)
+	if len(result.Drifted) != 0 {
+		t.Errorf("expected the drift to be resolved, got %v", result.Drifted)
+	}
+}
@@ -0,0 +1,34 @@
+package provisioning
+
+import (
+	"context"
+	"peekaping/src/config"
+
+	"go.uber.org/zap"
+)
+
+// ReconcileFromConfig applies the monitors file set in the config, if any. A
+// broken file is logged and leaves the stored monitors as they are, so a bad
+// commit does not stop the monitoring.
+func ReconcileFromConfig(ctx context.Context, cfg *config.Config, service Service, logger *zap.SugaredLogger) {
+	if cfg.MonitorsFile == "" {
+		return
+	}
+
+	file, err := LoadFile(cfg.MonitorsFile)
+	if err != nil {
+		logger.Errorf("Skipping monitors file %s: %v", cfg.MonitorsFile, err)
+		return
+	}
+
+	result, err := service.Reconcile(ctx, file, cfg.MonitorsFileAuthoritative)
+	if err != nil {
+		logger.Errorf("Failed to apply monitors file %s: %v", cfg.MonitorsFile, err)
+	}
+	if result != nil {
+		logger.Infof(
+			"Applied monitors file %s: %d created, %d updated, %d deleted, %d unchanged, %d drifted",
+			cfg.MonitorsFile, len(result.Created), len(result.Updated), len(result.Deleted), len(result.Unchanged), len(result.Drifted),
+		)
+	}
+}
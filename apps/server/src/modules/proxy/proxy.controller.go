@@ -62,6 +62,17 @@ func (ic *Controller) FindAll(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entities))
 }
 
+// @Router		/proxies/health [get]
+// @Summary		Get proxy health
+// @Description	Returns the last connection check of every checked proxy, monitors fail over from an unhealthy proxy to their next healthy one
+// @Tags			Proxies
+// @Produce		json
+// @Security  BearerAuth
+// @Success		200	{object}	utils.ApiResponse[[]HealthStatus]
+func (ic *Controller) FindHealth(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", ic.service.HealthStatuses()))
+}
+
 // @Router		/proxies [post]
 // @Summary		Create proxy
 // @Tags			Proxies
@@ -122,6 +133,34 @@ func (ic *Controller) FindByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", entity))
 }
 
+// @Router		/proxies/{id}/stats [get]
+// @Summary		Get proxy stats
+// @Description	Returns the success rate and last error of the monitor checks routed through the proxy
+// @Tags			Proxies
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Proxy ID"
+// @Success		200	{object}	utils.ApiResponse[StatsDto]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindStats(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	stats, err := ic.service.GetStats(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch proxy stats", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if stats == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Proxy not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", stats))
+}
+
 // @Router		/proxies/{id} [put]
 // @Summary		Update proxy
 // @Tags			Proxies
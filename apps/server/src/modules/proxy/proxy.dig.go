@@ -12,4 +12,5 @@ func RegisterDependencies(container *dig.Container, cfg *config.Config) {
 	container.Provide(NewService)
 	container.Provide(NewController)
 	container.Provide(NewRoute)
+	container.Provide(NewEventListener)
 }
@@ -1,5 +1,7 @@
 package proxy
 
+import "time"
+
 // CreateUpdateDto is used for both create and full update operations.
 type CreateUpdateDto struct {
 	Protocol string `json:"protocol" validate:"required,oneof=http https socks socks4 socks5 socks5h"`
@@ -19,3 +21,13 @@ type PartialUpdateDto struct {
 	Username *string `json:"username,omitempty"`
 	Password *string `json:"password,omitempty"`
 }
+
+// StatsDto summarizes the monitor checks routed through a proxy
+type StatsDto struct {
+	ProxyID      string     `json:"proxy_id"`
+	SuccessCount int64      `json:"success_count"`
+	FailureCount int64      `json:"failure_count"`
+	SuccessRate  float64    `json:"success_rate"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
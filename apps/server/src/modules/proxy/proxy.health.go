@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// healthDialTimeout bounds the connection attempt of a proxy health check
+const healthDialTimeout = 5 * time.Second
+
+// healthCheckBatchSize is the number of proxies loaded per page when checking all of them
+const healthCheckBatchSize = 100
+
+// HealthStatus is the outcome of the last connection check of a proxy
+type HealthStatus struct {
+	ProxyID   string    `json:"proxy_id"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// healthChecker dials the proxies and keeps their last status in memory
+type healthChecker struct {
+	mu       sync.RWMutex
+	statuses map[string]HealthStatus
+	dial     func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func newHealthChecker() *healthChecker {
+	dialer := &net.Dialer{Timeout: healthDialTimeout}
+	return &healthChecker{
+		statuses: make(map[string]HealthStatus),
+		dial:     dialer.DialContext,
+	}
+}
+
+// check connects to the proxy and records the outcome, a proxy accepting the
+// connection is healthy
+func (c *healthChecker) check(ctx context.Context, p *Model) HealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthDialTimeout)
+	defer cancel()
+
+	status := HealthStatus{ProxyID: p.ID, Healthy: true, CheckedAt: time.Now().UTC()}
+	conn, err := c.dial(ctx, "tcp", net.JoinHostPort(p.Host, strconv.Itoa(p.Port)))
+	if err != nil {
+		status.Healthy = false
+		status.Error = fmt.Sprintf("failed to connect to proxy: %v", err)
+	} else {
+		conn.Close()
+	}
+
+	c.mu.Lock()
+	c.statuses[p.ID] = status
+	c.mu.Unlock()
+	return status
+}
+
+// isHealthy tells whether the last check of the proxy succeeded, a proxy that
+// was never checked counts as healthy
+func (c *healthChecker) isHealthy(id string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status, ok := c.statuses[id]
+	return !ok || status.Healthy
+}
+
+func (c *healthChecker) all() []HealthStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	statuses := make([]HealthStatus, 0, len(c.statuses))
+	for _, status := range c.statuses {
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (c *healthChecker) forget(id string) {
+	c.mu.Lock()
+	delete(c.statuses, id)
+	c.mu.Unlock()
+}
+
+// StartHealthChecks checks every proxy on the configured interval, 0 leaves
+// the checks to the monitors failing through a proxy
+func StartHealthChecks(service Service, intervalSeconds int, logger *zap.SugaredLogger) {
+	if intervalSeconds <= 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(intervalSeconds) * time.Second)
+
+	check := func() {
+		if err := service.CheckHealth(context.Background()); err != nil {
+			logger.Errorw("Failed to check proxy health", "error", err)
+		}
+	}
+
+	go func() {
+		check()
+		for range ticker.C {
+			check()
+		}
+	}()
+}
@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecker_Check(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	c := newHealthChecker()
+	assert.True(t, c.isHealthy("up"), "expected an unchecked proxy to count as healthy")
+
+	status := c.check(context.Background(), &Model{ID: "up", Host: "127.0.0.1", Port: port})
+	assert.True(t, status.Healthy)
+	assert.Empty(t, status.Error)
+	assert.True(t, c.isHealthy("up"))
+
+	status = c.check(context.Background(), &Model{ID: "down", Host: "127.0.0.1", Port: closedPort})
+	assert.False(t, status.Healthy)
+	assert.Contains(t, status.Error, "failed to connect to proxy")
+	assert.False(t, c.isHealthy("down"))
+	assert.Len(t, c.all(), 2)
+
+	c.forget("down")
+	assert.True(t, c.isHealthy("down"))
+}
@@ -0,0 +1,40 @@
+package proxy
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/zap"
+)
+
+// EventListener records the outcome of the monitor checks routed through a
+// proxy, the executors only report them on the event bus
+type EventListener struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewEventListener(service Service, logger *zap.SugaredLogger) *EventListener {
+	return &EventListener{
+		service: service,
+		logger:  logger.Named("[proxy-listener]"),
+	}
+}
+
+// Subscribe subscribes to ProxyCheckCompleted events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.ProxyCheckCompleted, l.handleCheckCompleted)
+}
+
+func (l *EventListener) handleCheckCompleted(event events.Event) {
+	outcome, ok := event.Payload.(*shared.ProxyCheckOutcome)
+	if !ok {
+		l.logger.Errorf("Invalid handleCheckCompleted event payload type: %v", event.Payload)
+		return
+	}
+
+	if err := l.service.RecordCheck(context.Background(), outcome); err != nil {
+		l.logger.Errorf("Failed to record check of proxy %s: %v", outcome.ProxyID, err)
+	}
+}
@@ -3,6 +3,7 @@ package proxy
 import (
 	"context"
 	"peekaping/src/config"
+	"peekaping/src/modules/shared"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -21,6 +22,11 @@ type mongoModel struct {
 	Password  string             `bson:"password,omitempty"`
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
+
+	SuccessCount int64      `bson:"success_count"`
+	FailureCount int64      `bson:"failure_count"`
+	LastError    string     `bson:"last_error,omitempty"`
+	LastErrorAt  *time.Time `bson:"last_error_at,omitempty"`
 }
 
 type mongoUpdateModel struct {
@@ -44,6 +50,11 @@ func toDomainModel(mm *mongoModel) *Model {
 		Password:  mm.Password,
 		CreatedAt: mm.CreatedAt,
 		UpdatedAt: mm.UpdatedAt,
+
+		SuccessCount: mm.SuccessCount,
+		FailureCount: mm.FailureCount,
+		LastError:    mm.LastError,
+		LastErrorAt:  mm.LastErrorAt,
 	}
 }
 
@@ -176,9 +187,12 @@ func (r *MongoRepositoryImpl) UpdateFull(ctx context.Context, id string, entity
 		return nil, err
 	}
 
-	// Remove immutable fields from setMap
+	// Remove immutable fields from setMap, the check counters are only
+	// changed by RecordCheck
 	delete(setMap, "_id")
 	delete(setMap, "created_at")
+	delete(setMap, "success_count")
+	delete(setMap, "failure_count")
 
 	update := bson.M{"$set": setMap}
 
@@ -253,3 +267,19 @@ func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
 	_, err = r.collection.DeleteOne(ctx, filter)
 	return err
 }
+
+func (r *MongoRepositoryImpl) RecordCheck(ctx context.Context, outcome *shared.ProxyCheckOutcome) error {
+	objectID, err := primitive.ObjectIDFromHex(outcome.ProxyID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$inc": bson.M{"failure_count": 1}}
+	if outcome.Success {
+		update = bson.M{"$inc": bson.M{"success_count": 1}}
+	} else {
+		update["$set"] = bson.M{"last_error": outcome.Error, "last_error_at": outcome.Time}
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
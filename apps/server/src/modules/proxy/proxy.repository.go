@@ -1,6 +1,9 @@
 package proxy
 
-import "context"
+import (
+	"context"
+	"peekaping/src/modules/shared"
+)
 
 type Repository interface {
 	Create(ctx context.Context, entity *Model) (*Model, error)
@@ -9,4 +12,6 @@ type Repository interface {
 	UpdateFull(ctx context.Context, id string, entity *Model) (*Model, error)
 	UpdatePartial(ctx context.Context, id string, entity *UpdateModel) (*Model, error)
 	Delete(ctx context.Context, id string) error
+	// RecordCheck counts the outcome of a monitor check routed through the proxy
+	RecordCheck(ctx context.Context, outcome *shared.ProxyCheckOutcome) error
 }
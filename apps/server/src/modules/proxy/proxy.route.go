@@ -30,7 +30,9 @@ func (uc *Route) ConnectRoute(
 	router.Use(uc.middleware.Auth())
 	router.GET("", uc.controller.FindAll)
 	router.POST("", uc.controller.Create)
+	router.GET("health", uc.controller.FindHealth)
 	router.GET(":id", uc.controller.FindByID)
+	router.GET(":id/stats", uc.controller.FindStats)
 	router.PUT(":id", uc.controller.UpdateFull)
 	router.PATCH(":id", uc.controller.UpdatePartial)
 	router.DELETE(":id", uc.controller.Delete)
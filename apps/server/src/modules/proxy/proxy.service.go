@@ -4,6 +4,7 @@ import (
 	"context"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
 
 	"go.uber.org/dig"
 	"go.uber.org/zap"
@@ -16,12 +17,28 @@ type Service interface {
 	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
 	UpdatePartial(ctx context.Context, id string, entity *PartialUpdateDto) (*Model, error)
 	Delete(ctx context.Context, id string) error
+
+	// CheckHealth connects to every proxy and records whether it is healthy
+	CheckHealth(ctx context.Context) error
+	// CheckProxyHealth connects to the proxy now and records the outcome
+	CheckProxyHealth(ctx context.Context, p *Model) HealthStatus
+	// IsHealthy tells whether the last check of the proxy succeeded, a proxy
+	// that was never checked counts as healthy
+	IsHealthy(id string) bool
+	// HealthStatuses returns the last check of every checked proxy
+	HealthStatuses() []HealthStatus
+
+	// RecordCheck counts the outcome of a monitor check routed through a proxy
+	RecordCheck(ctx context.Context, outcome *shared.ProxyCheckOutcome) error
+	// GetStats returns the check statistics of a proxy, nil when it does not exist
+	GetStats(ctx context.Context, id string) (*StatsDto, error)
 }
 
 type ServiceImpl struct {
 	repository     Repository
 	monitorService monitor.Service
 	eventBus       *events.EventBus
+	health         *healthChecker
 	logger         *zap.SugaredLogger
 }
 
@@ -38,6 +55,7 @@ func NewService(params NewServiceParams) Service {
 		repository:     params.Repository,
 		monitorService: params.MonitorService,
 		eventBus:       params.EventBus,
+		health:         newHealthChecker(),
 		logger:         params.Logger.Named("[proxy-service]"),
 	}
 }
@@ -75,6 +93,8 @@ func (mr *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *Create
 	if err != nil {
 		return nil, err
 	}
+	// the address may have changed, the next check decides again
+	mr.health.forget(id)
 
 	if mr.eventBus != nil {
 		mr.eventBus.Publish(events.Event{
@@ -99,6 +119,7 @@ func (mr *ServiceImpl) UpdatePartial(ctx context.Context, id string, entity *Par
 	if err != nil {
 		return nil, err
 	}
+	mr.health.forget(id)
 	if mr.eventBus != nil {
 		mr.eventBus.Publish(events.Event{
 			Type:    events.ProxyUpdated,
@@ -114,6 +135,7 @@ func (mr *ServiceImpl) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	mr.health.forget(id)
 	if mr.eventBus != nil {
 		mr.eventBus.Publish(events.Event{
 			Type:    events.ProxyDeleted,
@@ -122,3 +144,55 @@ func (mr *ServiceImpl) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+func (mr *ServiceImpl) CheckHealth(ctx context.Context) error {
+	for page := 0; ; page++ {
+		proxies, err := mr.repository.FindAll(ctx, page, healthCheckBatchSize, "")
+		if err != nil {
+			return err
+		}
+		for _, p := range proxies {
+			if status := mr.health.check(ctx, p); !status.Healthy {
+				mr.logger.Warnf("proxy %s:%d is unhealthy: %s", p.Host, p.Port, status.Error)
+			}
+		}
+		if len(proxies) < healthCheckBatchSize {
+			return nil
+		}
+	}
+}
+
+func (mr *ServiceImpl) CheckProxyHealth(ctx context.Context, p *Model) HealthStatus {
+	return mr.health.check(ctx, p)
+}
+
+func (mr *ServiceImpl) IsHealthy(id string) bool {
+	return mr.health.isHealthy(id)
+}
+
+func (mr *ServiceImpl) HealthStatuses() []HealthStatus {
+	return mr.health.all()
+}
+
+func (mr *ServiceImpl) RecordCheck(ctx context.Context, outcome *shared.ProxyCheckOutcome) error {
+	return mr.repository.RecordCheck(ctx, outcome)
+}
+
+func (mr *ServiceImpl) GetStats(ctx context.Context, id string) (*StatsDto, error) {
+	p, err := mr.repository.FindByID(ctx, id)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	stats := &StatsDto{
+		ProxyID:      p.ID,
+		SuccessCount: p.SuccessCount,
+		FailureCount: p.FailureCount,
+		LastError:    p.LastError,
+		LastErrorAt:  p.LastErrorAt,
+	}
+	if total := p.SuccessCount + p.FailureCount; total > 0 {
+		stats.SuccessRate = float64(p.SuccessCount) / float64(total) * 100
+	}
+	return stats, nil
+}
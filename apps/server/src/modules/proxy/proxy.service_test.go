@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T) *ServiceImpl {
+	t.Helper()
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.NewCreateTable().Model((*sqlModel)(nil)).IfNotExists().Exec(context.Background())
+	require.NoError(t, err)
+
+	return NewService(NewServiceParams{
+		Repository: NewSQLRepository(db),
+		Logger:     zap.NewNop().Sugar(),
+	}).(*ServiceImpl)
+}
+
+func TestService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	p, err := s.Create(ctx, &CreateUpdateDto{Protocol: "http", Host: "proxy.local", Port: 3128})
+	require.NoError(t, err)
+
+	stats, err := s.GetStats(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stats.SuccessRate, "expected no rate before any check")
+
+	failedAt := time.Now().UTC().Truncate(time.Second)
+	for _, outcome := range []*shared.ProxyCheckOutcome{
+		{ProxyID: p.ID, Success: true},
+		{ProxyID: p.ID, Success: false, Error: "proxyconnect tcp: connection refused", Time: failedAt},
+		{ProxyID: p.ID, Success: true},
+		{ProxyID: p.ID, Success: true},
+	} {
+		require.NoError(t, s.RecordCheck(ctx, outcome))
+	}
+
+	// a full update leaves the counters alone
+	_, err = s.UpdateFull(ctx, p.ID, &CreateUpdateDto{Protocol: "http", Host: "proxy.local", Port: 8080})
+	require.NoError(t, err)
+
+	stats, err = s.GetStats(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.SuccessCount)
+	assert.Equal(t, int64(1), stats.FailureCount)
+	assert.InDelta(t, 75, stats.SuccessRate, 0.001)
+	assert.Equal(t, "proxyconnect tcp: connection refused", stats.LastError)
+	require.NotNil(t, stats.LastErrorAt)
+	assert.True(t, failedAt.Equal(*stats.LastErrorAt))
+
+	missing, err := s.GetStats(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
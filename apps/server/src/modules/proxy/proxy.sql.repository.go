@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"peekaping/src/modules/shared"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,11 @@ type sqlModel struct {
 	Password  string    `bun:"password"`
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	SuccessCount int64      `bun:"success_count,notnull,default:0"`
+	FailureCount int64      `bun:"failure_count,notnull,default:0"`
+	LastError    string     `bun:"last_error"`
+	LastErrorAt  *time.Time `bun:"last_error_at,nullzero"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -33,6 +39,11 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		Password:  sm.Password,
 		CreatedAt: sm.CreatedAt,
 		UpdatedAt: sm.UpdatedAt,
+
+		SuccessCount: sm.SuccessCount,
+		FailureCount: sm.FailureCount,
+		LastError:    sm.LastError,
+		LastErrorAt:  sm.LastErrorAt,
 	}
 }
 
@@ -173,3 +184,16 @@ func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
 	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
 	return err
 }
+
+func (r *SQLRepositoryImpl) RecordCheck(ctx context.Context, outcome *shared.ProxyCheckOutcome) error {
+	query := r.db.NewUpdate().Model((*sqlModel)(nil)).Where("id = ?", outcome.ProxyID)
+	if outcome.Success {
+		query = query.Set("success_count = success_count + 1")
+	} else {
+		query = query.Set("failure_count = failure_count + 1").
+			Set("last_error = ?", outcome.Error).
+			Set("last_error_at = ?", outcome.Time)
+	}
+	_, err := query.Exec(ctx)
+	return err
+}
@@ -0,0 +1,240 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+	"peekaping/src/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router		/reports [get]
+// @Summary		Get scheduled reports
+// @Tags			Reports
+// @Produce		json
+// @Security  BearerAuth
+// @Param     page query     int     false  "Page number" default(1)
+// @Param     limit query    int     false  "Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) FindAll(ctx *gin.Context) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return
+	}
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return
+	}
+
+	response, err := c.service.FindAll(ctx, page, limit)
+	if err != nil {
+		c.logger.Errorw("Failed to fetch reports", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+}
+
+// @Router		/reports [post]
+// @Summary		Create scheduled report
+// @Tags			Reports
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param     body body   CreateUpdateDto  true  "Report object"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Create(ctx *gin.Context) {
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	created, err := c.service.Create(ctx, &entity)
+	if err != nil {
+		if errors.Is(err, ErrChannelNotSMTP) {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+		c.logger.Errorw("Failed to create report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Report created successfully", created))
+}
+
+// @Router		/reports/{id} [get]
+// @Summary		Get scheduled report by ID
+// @Tags			Reports
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Report ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) FindByID(ctx *gin.Context) {
+	report, ok := c.findReport(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", report))
+}
+
+// @Router		/reports/{id} [put]
+// @Summary		Update scheduled report
+// @Tags			Reports
+// @Produce		json
+// @Accept		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Report ID"
+// @Param       body body     CreateUpdateDto  true  "Report object"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) UpdateFull(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var entity CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updated, err := c.service.UpdateFull(ctx, id, &entity)
+	if err != nil {
+		if errors.Is(err, ErrChannelNotSMTP) {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+		c.logger.Errorw("Failed to update report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if updated == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Report not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Report updated successfully", updated))
+}
+
+// @Router		/reports/{id} [delete]
+// @Summary		Delete scheduled report
+// @Tags			Reports
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Report ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := c.service.Delete(ctx, id); err != nil {
+		c.logger.Errorw("Failed to delete report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Report deleted successfully", nil))
+}
+
+// @Router		/reports/{id}/preview [get]
+// @Summary		Preview the report for the last complete period
+// @Tags			Reports
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Report ID"
+// @Success		200	{object}	utils.ApiResponse[Summary]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Preview(ctx *gin.Context) {
+	report, ok := c.findReport(ctx)
+	if !ok {
+		return
+	}
+
+	summary, err := c.service.Generate(ctx, report, time.Now())
+	if err != nil {
+		c.logger.Errorw("Failed to generate report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", summary))
+}
+
+// @Router		/reports/{id}/send [post]
+// @Summary		Send the report for the last complete period now
+// @Tags			Reports
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Report ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Send(ctx *gin.Context) {
+	report, ok := c.findReport(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Send(ctx, report, time.Now()); err != nil {
+		c.logger.Errorw("Failed to send report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Failed to send report: "+err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Report sent successfully", nil))
+}
+
+func (c *Controller) findReport(ctx *gin.Context) (*Model, bool) {
+	report, err := c.service.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.logger.Errorw("Failed to fetch report", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return nil, false
+	}
+	if report == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Report not found"))
+		return nil, false
+	}
+	return report, true
+}
@@ -0,0 +1,20 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartReportCron checks for due reports shortly after every full hour, so
+// reports go out soon after their period ends at midnight
+func StartReportCron(service Service) {
+	c := cron.New()
+
+	c.AddFunc("5 * * * *", func() {
+		service.SendDue(context.Background(), time.Now())
+	})
+
+	c.Start()
+}
@@ -0,0 +1,11 @@
+package report
+
+type CreateUpdateDto struct {
+	Name                  string   `json:"name" validate:"required,min=1,max=100" example:"Weekly uptime"`
+	Cadence               Cadence  `json:"cadence" validate:"required,oneof=daily weekly monthly" example:"weekly"`
+	NotificationChannelID string   `json:"notification_channel_id" validate:"required" example:"60c72b2f9b1e8b6f1f8e4b1a"`
+	Recipients            []string `json:"recipients" validate:"required,min=1,max=50,dive,email" example:"cto@example.com"`
+	MonitorIDs            []string `json:"monitor_ids" validate:"required_without=StatusPageIDs,dive,required"`
+	StatusPageIDs         []string `json:"status_page_ids" validate:"required_without=MonitorIDs,dive,required"`
+	Active                bool     `json:"active" example:"true"`
+}
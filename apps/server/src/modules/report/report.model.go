@@ -0,0 +1,25 @@
+package report
+
+import "time"
+
+type Cadence string
+
+const (
+	CadenceDaily   Cadence = "daily"
+	CadenceWeekly  Cadence = "weekly"
+	CadenceMonthly Cadence = "monthly"
+)
+
+type Model struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Cadence               Cadence    `json:"cadence"`
+	NotificationChannelID string     `json:"notification_channel_id"`
+	Recipients            []string   `json:"recipients"`
+	MonitorIDs            []string   `json:"monitor_ids"`
+	StatusPageIDs         []string   `json:"status_page_ids"`
+	Active                bool       `json:"active"`
+	LastSentAt            *time.Time `json:"last_sent_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
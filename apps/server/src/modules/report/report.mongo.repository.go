@@ -0,0 +1,186 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID                    primitive.ObjectID `bson:"_id"`
+	Name                  string             `bson:"name"`
+	Cadence               string             `bson:"cadence"`
+	NotificationChannelID string             `bson:"notification_channel_id"`
+	Recipients            []string           `bson:"recipients"`
+	MonitorIDs            []string           `bson:"monitor_ids"`
+	StatusPageIDs         []string           `bson:"status_page_ids"`
+	Active                bool               `bson:"active"`
+	LastSentAt            *time.Time         `bson:"last_sent_at,omitempty"`
+	CreatedAt             time.Time          `bson:"created_at"`
+	UpdatedAt             time.Time          `bson:"updated_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:                    mm.ID.Hex(),
+		Name:                  mm.Name,
+		Cadence:               Cadence(mm.Cadence),
+		NotificationChannelID: mm.NotificationChannelID,
+		Recipients:            mm.Recipients,
+		MonitorIDs:            mm.MonitorIDs,
+		StatusPageIDs:         mm.StatusPageIDs,
+		Active:                mm.Active,
+		LastSentAt:            mm.LastSentAt,
+		CreatedAt:             mm.CreatedAt,
+		UpdatedAt:             mm.UpdatedAt,
+	}
+}
+
+func toMongoModel(m *Model) *mongoModel {
+	var objID primitive.ObjectID
+	if m.ID != "" {
+		objID, _ = primitive.ObjectIDFromHex(m.ID)
+	} else {
+		objID = primitive.NewObjectID()
+	}
+
+	return &mongoModel{
+		ID:                    objID,
+		Name:                  m.Name,
+		Cadence:               string(m.Cadence),
+		NotificationChannelID: m.NotificationChannelID,
+		Recipients:            m.Recipients,
+		MonitorIDs:            m.MonitorIDs,
+		StatusPageIDs:         m.StatusPageIDs,
+		Active:                m.Active,
+		LastSentAt:            m.LastSentAt,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("reports")
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, error) {
+	mm := toMongoModel(entity)
+	mm.ID = primitive.NewObjectID()
+	mm.CreatedAt = time.Now().UTC()
+	mm.UpdatedAt = time.Now().UTC()
+
+	_, err := r.collection.InsertOne(ctx, mm)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var mm mongoModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&mm), nil
+}
+
+func (r *MongoRepositoryImpl) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*Model, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []*Model
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModelFromMongo(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func (r *MongoRepositoryImpl) FindAll(ctx context.Context, page int, limit int) ([]*Model, error) {
+	opts := options.Find().
+		SetSkip(int64(page * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "name", Value: 1}})
+	return r.find(ctx, bson.M{}, opts)
+}
+
+func (r *MongoRepositoryImpl) FindActive(ctx context.Context) ([]*Model, error) {
+	return r.find(ctx, bson.M{"active": true}, options.Find())
+}
+
+func (r *MongoRepositoryImpl) UpdateFull(ctx context.Context, id string, entity *Model) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	mm := toMongoModel(entity)
+	update := bson.M{
+		"$set": bson.M{
+			"name":                    mm.Name,
+			"cadence":                 mm.Cadence,
+			"notification_channel_id": mm.NotificationChannelID,
+			"recipients":              mm.Recipients,
+			"monitor_ids":             mm.MonitorIDs,
+			"status_page_ids":         mm.StatusPageIDs,
+			"active":                  mm.Active,
+			"updated_at":              time.Now().UTC(),
+		},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+func (r *MongoRepositoryImpl) UpdateLastSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_sent_at": sentAt}})
+	return err
+}
+
+func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
@@ -0,0 +1,16 @@
+package report
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, entity *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int) ([]*Model, error)
+	FindActive(ctx context.Context) ([]*Model, error)
+	UpdateFull(ctx context.Context, id string, entity *Model) error
+	UpdateLastSentAt(ctx context.Context, id string, sentAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
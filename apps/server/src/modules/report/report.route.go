@@ -0,0 +1,39 @@
+package report
+
+import (
+	"peekaping/src/modules/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+) *Route {
+	return &Route{
+		controller,
+		middleware,
+	}
+}
+
+func (r *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	router := rg.Group("reports")
+
+	router.Use(r.middleware.Auth())
+
+	router.GET("", controller.FindAll)
+	router.POST("", controller.Create)
+	router.GET("/:id", controller.FindByID)
+	router.PUT("/:id", controller.UpdateFull)
+	router.DELETE("/:id", controller.Delete)
+	router.GET("/:id/preview", controller.Preview)
+	router.POST("/:id/send", controller.Send)
+}
@@ -0,0 +1,350 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"peekaping/src/config"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/stats"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reportChannelType is the notification channel type reports are delivered through
+const reportChannelType = "smtp"
+
+const (
+	importantHeartbeatsPageSize = 100
+	importantHeartbeatsMaxPages = 50
+)
+
+var ErrChannelNotSMTP = errors.New("notification channel must be an smtp channel")
+
+type Service interface {
+	Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int) ([]*Model, error)
+	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
+	Delete(ctx context.Context, id string) error
+
+	// Generate builds the report for the last complete period before now
+	Generate(ctx context.Context, report *Model, now time.Time) (*Summary, error)
+	// Send generates the report and emails it to every recipient
+	Send(ctx context.Context, report *Model, now time.Time) error
+	// SendDue sends every active report whose last complete period was not sent yet
+	SendDue(ctx context.Context, now time.Time)
+}
+
+type ServiceImpl struct {
+	repository                 Repository
+	monitorService             monitor.Service
+	monitorStatusPageService   monitor_status_page.Service
+	statsService               stats.Service
+	heartbeatService           heartbeat.Service
+	notificationChannelService notification_channel.Service
+	location                   *time.Location
+	logger                     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	monitorService monitor.Service,
+	monitorStatusPageService monitor_status_page.Service,
+	statsService stats.Service,
+	heartbeatService heartbeat.Service,
+	notificationChannelService notification_channel.Service,
+	cfg *config.Config,
+	logger *zap.SugaredLogger,
+) Service {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &ServiceImpl{
+		repository,
+		monitorService,
+		monitorStatusPageService,
+		statsService,
+		heartbeatService,
+		notificationChannelService,
+		location,
+		logger.Named("[report-service]"),
+	}
+}
+
+func (s *ServiceImpl) validateChannel(ctx context.Context, channelID string) error {
+	channel, err := s.notificationChannelService.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil || channel.Type != reportChannelType {
+		return ErrChannelNotSMTP
+	}
+	return nil
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+	if err := s.validateChannel(ctx, entity.NotificationChannelID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.Create(ctx, &Model{
+		Name:                  entity.Name,
+		Cadence:               entity.Cadence,
+		NotificationChannelID: entity.NotificationChannelID,
+		Recipients:            entity.Recipients,
+		MonitorIDs:            entity.MonitorIDs,
+		StatusPageIDs:         entity.StatusPageIDs,
+		Active:                entity.Active,
+	})
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int) ([]*Model, error) {
+	return s.repository.FindAll(ctx, page, limit)
+}
+
+func (s *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error) {
+	existing, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if err := s.validateChannel(ctx, entity.NotificationChannelID); err != nil {
+		return nil, err
+	}
+
+	existing.Name = entity.Name
+	existing.Cadence = entity.Cadence
+	existing.NotificationChannelID = entity.NotificationChannelID
+	existing.Recipients = entity.Recipients
+	existing.MonitorIDs = entity.MonitorIDs
+	existing.StatusPageIDs = entity.StatusPageIDs
+	existing.Active = entity.Active
+
+	if err := s.repository.UpdateFull(ctx, id, existing); err != nil {
+		return nil, err
+	}
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
+
+// reportMonitorIDs returns the monitors of the report followed by the ones of
+// its status pages, without duplicates
+func (s *ServiceImpl) reportMonitorIDs(ctx context.Context, report *Model) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range report.MonitorIDs {
+		add(id)
+	}
+	for _, statusPageID := range report.StatusPageIDs {
+		relations, err := s.monitorStatusPageService.GetMonitorsForStatusPage(ctx, statusPageID)
+		if err != nil {
+			return nil, err
+		}
+		for _, relation := range relations {
+			add(relation.MonitorID)
+		}
+	}
+	return ids, nil
+}
+
+// importantHeartbeats returns the important heartbeats of the monitor from the
+// newest down to the last one before since
+func (s *ServiceImpl) importantHeartbeats(ctx context.Context, monitorID string, since time.Time) ([]*heartbeat.Model, error) {
+	important := true
+	var result []*heartbeat.Model
+	for page := 0; page < importantHeartbeatsMaxPages; page++ {
+		heartbeats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, monitorID, importantHeartbeatsPageSize, page, &important, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, hb := range heartbeats {
+			result = append(result, hb)
+			if hb.Time.Before(since) {
+				return result, nil
+			}
+		}
+		if len(heartbeats) < importantHeartbeatsPageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *ServiceImpl) Generate(ctx context.Context, report *Model, now time.Time) (*Summary, error) {
+	start, end := ReportPeriod(report.Cadence, now, s.location)
+
+	summary := &Summary{
+		ReportID:    report.ID,
+		Name:        report.Name,
+		Cadence:     report.Cadence,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Monitors:    []*MonitorSummary{},
+	}
+
+	monitorIDs, err := s.reportMonitorIDs(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, monitorID := range monitorIDs {
+		m, err := s.monitorService.FindByID(ctx, monitorID)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			// deleted since the report was configured
+			continue
+		}
+
+		// hourly buckets are keyed by their start, the bucket at end belongs to the next period
+		points, err := s.statsService.FindStatsByMonitorIDAndTimeRange(ctx, monitorID, start, end.Add(-time.Second), stats.StatHourly)
+		if err != nil {
+			return nil, err
+		}
+		important, err := s.importantHeartbeats(ctx, monitorID, start)
+		if err != nil {
+			return nil, err
+		}
+
+		summary.Monitors = append(summary.Monitors, summarizeMonitor(m, points, important, start, end))
+	}
+
+	combineSummaries(summary)
+	return summary, nil
+}
+
+func (s *ServiceImpl) Send(ctx context.Context, report *Model, now time.Time) error {
+	channel, err := s.notificationChannelService.FindByID(ctx, report.NotificationChannelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil || channel.Type != reportChannelType {
+		return ErrChannelNotSMTP
+	}
+	if channel.Config == nil {
+		return fmt.Errorf("notification channel %s has no config", channel.Name)
+	}
+	provider, ok := notification_channel.GetNotificationChannelProvider(channel.Type)
+	if !ok {
+		return fmt.Errorf("no provider registered for %s channels", channel.Type)
+	}
+
+	summary, err := s.Generate(ctx, report, now)
+	if err != nil {
+		return err
+	}
+	subject, body := summary.Subject(), summary.Text()
+
+	var channelConfig map[string]any
+	if err := json.Unmarshal([]byte(*channel.Config), &channelConfig); err != nil {
+		return err
+	}
+	channelConfig["custom_subject"] = subject
+	delete(channelConfig, "custom_body")
+
+	// one email per recipient so recipients do not see each other, the cc
+	// and bcc of the channel get a single copy instead of one per recipient
+	cc, _ := channelConfig["cc"].(string)
+	bcc, _ := channelConfig["bcc"].(string)
+	cc, bcc = strings.TrimSpace(cc), strings.TrimSpace(bcc)
+	delete(channelConfig, "cc")
+	delete(channelConfig, "bcc")
+
+	var errs []error
+	delivered := 0
+	send := func(label string, config map[string]any) error {
+		configJSON, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if err := provider.Send(ctx, string(configJSON), body, nil, nil); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+			return nil
+		}
+		delivered++
+		return nil
+	}
+	for _, recipient := range report.Recipients {
+		channelConfig["to"] = recipient
+		if err := send(recipient, channelConfig); err != nil {
+			return err
+		}
+	}
+	if cc != "" || bcc != "" {
+		// a bcc only copy is addressed to the sender like any undisclosed recipients mail
+		channelConfig["to"] = cc
+		if cc == "" {
+			channelConfig["to"] = channelConfig["from"]
+		}
+		if bcc != "" {
+			channelConfig["bcc"] = bcc
+		}
+		if err := send("cc and bcc", channelConfig); err != nil {
+			return err
+		}
+	}
+
+	// a partially delivered report is not sent again to avoid duplicates
+	if delivered > 0 {
+		if err := s.repository.UpdateLastSentAt(ctx, report.ID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isDue reports whether the last complete period ended after the report was last sent
+func (s *ServiceImpl) isDue(report *Model, now time.Time) bool {
+	_, end := ReportPeriod(report.Cadence, now, s.location)
+	mark := report.CreatedAt
+	if report.LastSentAt != nil {
+		mark = *report.LastSentAt
+	}
+	return mark.Before(end)
+}
+
+func (s *ServiceImpl) SendDue(ctx context.Context, now time.Time) {
+	reports, err := s.repository.FindActive(ctx)
+	if err != nil {
+		s.logger.Errorw("Failed to fetch active reports", "error", err)
+		return
+	}
+
+	for _, report := range reports {
+		if !s.isDue(report, now) {
+			continue
+		}
+		if err := s.Send(ctx, report, now); err != nil {
+			s.logger.Errorw("Failed to send report", "reportID", report.ID, "error", err)
+			continue
+		}
+		s.logger.Infow("Report sent", "reportID", report.ID, "recipients", len(report.Recipients))
+	}
+}
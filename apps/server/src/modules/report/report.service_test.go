@@ -0,0 +1,472 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/stats"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fakeMonitors struct {
+	monitor.Service
+	monitors map[string]*monitor.Model
+}
+
+func (f *fakeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return f.monitors[id], nil
+}
+
+type fakeStatusPageMonitors struct {
+	monitor_status_page.Service
+	monitorsByPage map[string][]string
+}
+
+func (f *fakeStatusPageMonitors) GetMonitorsForStatusPage(ctx context.Context, statusPageID string) ([]*monitor_status_page.Model, error) {
+	var result []*monitor_status_page.Model
+	for _, id := range f.monitorsByPage[statusPageID] {
+		result = append(result, &monitor_status_page.Model{StatusPageID: statusPageID, MonitorID: id})
+	}
+	return result, nil
+}
+
+// fakeStats returns the hourly points of a monitor falling within the requested range
+type fakeStats struct {
+	stats.Service
+	points map[string][]*stats.Stat
+}
+
+func (f *fakeStats) FindStatsByMonitorIDAndTimeRange(ctx context.Context, monitorID string, since, until time.Time, period stats.StatPeriod) ([]*stats.Stat, error) {
+	var result []*stats.Stat
+	for _, p := range f.points[monitorID] {
+		if !p.Timestamp.Before(since) && !p.Timestamp.After(until) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// fakeHeartbeats pages through the important heartbeats of a monitor, newest first
+type fakeHeartbeats struct {
+	heartbeat.Service
+	important map[string][]*heartbeat.Model
+}
+
+func (f *fakeHeartbeats) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	all := f.important[monitorID]
+	newestFirst := make([]*heartbeat.Model, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		newestFirst = append(newestFirst, all[i])
+	}
+	start := page * limit
+	if start >= len(newestFirst) {
+		return nil, nil
+	}
+	end := start + limit
+	if end > len(newestFirst) {
+		end = len(newestFirst)
+	}
+	return newestFirst[start:end], nil
+}
+
+type fakeChannels struct {
+	notification_channel.Service
+	channels map[string]*notification_channel.Model
+}
+
+func (f *fakeChannels) FindByID(ctx context.Context, id string) (*notification_channel.Model, error) {
+	return f.channels[id], nil
+}
+
+type memoryRepository struct {
+	Repository
+	reports map[string]*Model
+	sentAt  map[string]time.Time
+}
+
+func (r *memoryRepository) FindActive(ctx context.Context) ([]*Model, error) {
+	var result []*Model
+	for _, report := range r.reports {
+		if report.Active {
+			result = append(result, report)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) UpdateLastSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	r.sentAt[id] = sentAt
+	r.reports[id].LastSentAt = &sentAt
+	return nil
+}
+
+type sentEmail struct {
+	config map[string]any
+	body   string
+}
+
+type recordingEmailProvider struct {
+	sent []sentEmail
+}
+
+func (p *recordingEmailProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		return err
+	}
+	p.sent = append(p.sent, sentEmail{config: cfg, body: message})
+	return nil
+}
+
+func (p *recordingEmailProvider) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	return nil
+}
+
+func (p *recordingEmailProvider) Validate(configJSON string) error { return nil }
+
+func (p *recordingEmailProvider) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+// the synthetic report period runs from Monday 2025-07-07 to Monday 2025-07-14 UTC
+var (
+	weekStart = time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	weekEnd   = weekStart.AddDate(0, 0, 7)
+	// a moment during the following week
+	reportNow = time.Date(2025, 7, 16, 9, 30, 0, 0, time.UTC)
+)
+
+func hourly(hour int, up, down, maintenance int, ping float64) *stats.Stat {
+	return &stats.Stat{
+		Timestamp:   weekStart.Add(time.Duration(hour) * time.Hour),
+		Up:          up,
+		Down:        down,
+		Maintenance: maintenance,
+		Ping:        ping,
+	}
+}
+
+func importantHeartbeat(at time.Time, status heartbeat.MonitorStatus) *heartbeat.Model {
+	return &heartbeat.Model{Time: at, Status: status, Important: true}
+}
+
+func newTestService(t *testing.T) (*ServiceImpl, *memoryRepository) {
+	smtpConfig := `{"smtp_host":"smtp.example.com","smtp_port":587,"username":"u","password":"p","from":"noreply@example.com","to":"ops@example.com","custom_body":"{{ msg }}"}`
+	webhookConfig := `{"url":"https://example.com"}`
+
+	repo := &memoryRepository{reports: map[string]*Model{}, sentAt: map[string]time.Time{}}
+	svc := &ServiceImpl{
+		repository: repo,
+		monitorService: &fakeMonitors{monitors: map[string]*monitor.Model{
+			"api": {ID: "api", Name: "API"},
+			"db":  {ID: "db", Name: "Database"},
+			"web": {ID: "web", Name: "Website"},
+		}},
+		monitorStatusPageService: &fakeStatusPageMonitors{monitorsByPage: map[string][]string{
+			"public": {"api", "web"},
+		}},
+		statsService: &fakeStats{points: map[string][]*stats.Stat{
+			"api": {
+				// just before the period, must not be counted
+				{Timestamp: weekStart.Add(-time.Hour), Up: 0, Down: 60},
+				hourly(0, 60, 0, 0, 100),
+				hourly(1, 50, 10, 0, 200),
+				hourly(2, 60, 0, 20, 100),
+				// first bucket of the next period
+				{Timestamp: weekEnd, Up: 0, Down: 60},
+			},
+			"db": {
+				hourly(0, 30, 30, 0, 10),
+			},
+		}},
+		heartbeatService: &fakeHeartbeats{important: map[string][]*heartbeat.Model{
+			"api": {
+				importantHeartbeat(weekStart.Add(-2*time.Hour), shared.MonitorStatusDown),
+				importantHeartbeat(weekStart.Add(-time.Hour), shared.MonitorStatusUp),
+				importantHeartbeat(weekStart.Add(70*time.Minute), shared.MonitorStatusDown),
+				importantHeartbeat(weekStart.Add(80*time.Minute), shared.MonitorStatusUp),
+				importantHeartbeat(weekEnd.Add(-30*time.Minute), shared.MonitorStatusDown),
+				importantHeartbeat(weekEnd.Add(time.Hour), shared.MonitorStatusUp),
+			},
+			"db": {
+				// down since before the period and recovering during it
+				importantHeartbeat(weekStart.Add(-time.Hour), shared.MonitorStatusDown),
+				importantHeartbeat(weekStart.Add(45*time.Minute), shared.MonitorStatusUp),
+			},
+		}},
+		notificationChannelService: &fakeChannels{channels: map[string]*notification_channel.Model{
+			"mail": {ID: "mail", Name: "Mail", Type: "smtp", Config: &smtpConfig},
+			"hook": {ID: "hook", Name: "Hook", Type: "webhook", Config: &webhookConfig},
+		}},
+		location: time.UTC,
+		logger:   zap.NewNop().Sugar(),
+	}
+	return svc, repo
+}
+
+func assertPercent(t *testing.T, name string, expected float64, actual *float64) {
+	t.Helper()
+	if actual == nil {
+		t.Fatalf("%s: expected %.4f, got nil", name, expected)
+	}
+	if math.Abs(expected-*actual) > 1e-9 {
+		t.Errorf("%s: expected %.4f, got %.4f", name, expected, *actual)
+	}
+}
+
+func TestReportService_Generate(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	report := &Model{
+		ID:            "weekly",
+		Name:          "Management",
+		Cadence:       CadenceWeekly,
+		MonitorIDs:    []string{"db", "deleted"},
+		StatusPageIDs: []string{"public"},
+	}
+
+	summary, err := svc.Generate(context.Background(), report, reportNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !summary.PeriodStart.Equal(weekStart) || !summary.PeriodEnd.Equal(weekEnd) {
+		t.Fatalf("unexpected period %s - %s", summary.PeriodStart, summary.PeriodEnd)
+	}
+
+	if len(summary.Monitors) != 3 {
+		t.Fatalf("expected db, api and web in the report, got %d monitors", len(summary.Monitors))
+	}
+	db, api, web := summary.Monitors[0], summary.Monitors[1], summary.Monitors[2]
+	if db.MonitorID != "db" || api.MonitorID != "api" || web.MonitorID != "web" {
+		t.Fatalf("unexpected monitor order: %s, %s, %s", db.MonitorID, api.MonitorID, web.MonitorID)
+	}
+
+	// api: 150 true up checks, 10 down, 20 maintenance checks left out of the uptime
+	assertPercent(t, "api uptime", 150.0/160.0*100, api.Uptime)
+	assertPercent(t, "api avg ping", (60*100+50*200+40*100)/150.0, api.AvgPing)
+	if api.Checks != 180 {
+		t.Errorf("expected 180 api checks, got %d", api.Checks)
+	}
+	if api.Incidents != 2 {
+		t.Errorf("expected 2 api incidents, got %d", api.Incidents)
+	}
+	// 10 minutes during the period and the last 30 minutes until the period end
+	if api.Downtime != 40*time.Minute {
+		t.Errorf("expected 40m api downtime, got %s", api.Downtime)
+	}
+
+	assertPercent(t, "db uptime", 50, db.Uptime)
+	if db.Incidents != 0 || db.Downtime != 45*time.Minute {
+		t.Errorf("expected the ongoing outage to add 45m downtime without an incident, got %d incidents and %s", db.Incidents, db.Downtime)
+	}
+
+	if web.Checks != 0 || web.Uptime != nil || web.AvgPing != nil {
+		t.Errorf("expected no data for web, got %+v", web)
+	}
+
+	// overall uptime is weighted by checks: (93.75% * 180 + 50% * 60) / 240
+	assertPercent(t, "overall uptime", (150.0/160.0*100*180+50*60)/240, summary.Uptime)
+	if summary.Incidents != 2 || summary.Downtime != 85*time.Minute {
+		t.Errorf("unexpected overall incidents %d and downtime %s", summary.Incidents, summary.Downtime)
+	}
+
+	text := summary.Text()
+	for _, expected := range []string{
+		"Weekly uptime report: Management",
+		"Period: 2025-07-07 00:00 UTC - 2025-07-14 00:00 UTC",
+		"Overall uptime: 82.81%",
+		"Incidents: 2 (total downtime 1h25m0s)",
+		"- Database: 50.00% uptime, 10 ms avg response",
+		"- API: 93.75% uptime, 133 ms avg response, 2 incidents (40m0s down)",
+		"- Website: no data",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected report text to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestReportPeriod(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	tests := []struct {
+		name    string
+		cadence Cadence
+		now     time.Time
+		loc     *time.Location
+		start   time.Time
+		end     time.Time
+	}{
+		{
+			name:    "daily",
+			cadence: CadenceDaily,
+			now:     time.Date(2025, 7, 16, 0, 5, 0, 0, time.UTC),
+			loc:     time.UTC,
+			start:   time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+			end:     time.Date(2025, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "weekly on a monday",
+			cadence: CadenceWeekly,
+			now:     time.Date(2025, 7, 14, 1, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			start:   time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC),
+			end:     time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "weekly on a sunday",
+			cadence: CadenceWeekly,
+			now:     time.Date(2025, 7, 13, 23, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			start:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+			end:     time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "monthly across a year",
+			cadence: CadenceMonthly,
+			now:     time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			start:   time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			end:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "daily in the configured timezone",
+			cadence: CadenceDaily,
+			now:     time.Date(2025, 7, 15, 22, 30, 0, 0, time.UTC), // 00:30 in Berlin
+			loc:     berlin,
+			start:   time.Date(2025, 7, 15, 0, 0, 0, 0, berlin),
+			end:     time.Date(2025, 7, 16, 0, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := ReportPeriod(tt.cadence, tt.now, tt.loc)
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("expected %s - %s, got %s - %s", tt.start, tt.end, start, end)
+			}
+		})
+	}
+}
+
+func TestReportService_SendDue(t *testing.T) {
+	provider := &recordingEmailProvider{}
+	notification_channel.RegisterNotificationChannelProvider("smtp", provider)
+	t.Cleanup(func() { delete(notification_channel.NotificationChannelProviderRegistry, "smtp") })
+
+	svc, repo := newTestService(t)
+	lastWeek := weekStart.Add(time.Hour)
+	thisWeek := weekEnd.Add(time.Hour)
+	repo.reports = map[string]*Model{
+		"due": {
+			ID: "due", Name: "Due", Cadence: CadenceWeekly, Active: true,
+			NotificationChannelID: "mail", Recipients: []string{"cto@example.com", "ceo@example.com"},
+			MonitorIDs: []string{"api"}, CreatedAt: weekStart.AddDate(0, 0, -30), LastSentAt: &lastWeek,
+		},
+		"already-sent": {
+			ID: "already-sent", Name: "Sent", Cadence: CadenceWeekly, Active: true,
+			NotificationChannelID: "mail", Recipients: []string{"cto@example.com"},
+			MonitorIDs: []string{"api"}, CreatedAt: weekStart.AddDate(0, 0, -30), LastSentAt: &thisWeek,
+		},
+		"inactive": {
+			ID: "inactive", Name: "Inactive", Cadence: CadenceWeekly, Active: false,
+			NotificationChannelID: "mail", Recipients: []string{"cto@example.com"},
+			MonitorIDs: []string{"api"}, CreatedAt: weekStart.AddDate(0, 0, -30),
+		},
+	}
+
+	svc.SendDue(context.Background(), reportNow)
+
+	if len(provider.sent) != 2 {
+		t.Fatalf("expected one email per recipient of the due report, got %d", len(provider.sent))
+	}
+	for i, recipient := range []string{"cto@example.com", "ceo@example.com"} {
+		email := provider.sent[i]
+		if email.config["to"] != recipient {
+			t.Errorf("expected email %d to go to %s, got %v", i, recipient, email.config["to"])
+		}
+		if email.config["custom_subject"] != "Peekaping weekly report: Due" {
+			t.Errorf("unexpected subject %v", email.config["custom_subject"])
+		}
+		if _, ok := email.config["custom_body"]; ok {
+			t.Errorf("expected the channel body template to be dropped")
+		}
+		if !strings.Contains(email.body, "Weekly uptime report: Due") {
+			t.Errorf("unexpected body:\n%s", email.body)
+		}
+	}
+
+	if sentAt, ok := repo.sentAt["due"]; !ok || !sentAt.Equal(reportNow) {
+		t.Errorf("expected the due report to be marked as sent")
+	}
+	if len(repo.sentAt) != 1 {
+		t.Errorf("expected only the due report to be sent, got %v", repo.sentAt)
+	}
+
+	// running again within the same week sends nothing
+	svc.SendDue(context.Background(), reportNow.Add(time.Hour))
+	if len(provider.sent) != 2 {
+		t.Errorf("expected the report not to be sent twice, got %d emails", len(provider.sent))
+	}
+}
+
+func TestReportService_Send_CopiesOnce(t *testing.T) {
+	provider := &recordingEmailProvider{}
+	notification_channel.RegisterNotificationChannelProvider("smtp", provider)
+	t.Cleanup(func() { delete(notification_channel.NotificationChannelProviderRegistry, "smtp") })
+
+	svc, _ := newTestService(t)
+	copiedConfig := `{"smtp_host":"smtp.example.com","smtp_port":587,"from":"noreply@example.com","to":"ops@example.com","cc":"lead@example.com","bcc":"audit@example.com"}`
+	svc.notificationChannelService.(*fakeChannels).channels["copied"] = &notification_channel.Model{ID: "copied", Name: "Copied", Type: "smtp", Config: &copiedConfig}
+	report := &Model{
+		ID: "r1", Name: "Weekly", Cadence: CadenceWeekly, NotificationChannelID: "copied",
+		Recipients: []string{"cto@example.com", "ceo@example.com"}, MonitorIDs: []string{"api"},
+	}
+	svc.repository.(*memoryRepository).reports["r1"] = report
+
+	if err := svc.Send(context.Background(), report, reportNow); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(provider.sent) != 3 {
+		t.Fatalf("expected an email per recipient and one copy, got %d", len(provider.sent))
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := provider.sent[i].config["cc"]; ok {
+			t.Errorf("expected no cc on the email of a recipient, got %v", provider.sent[i].config)
+		}
+		if _, ok := provider.sent[i].config["bcc"]; ok {
+			t.Errorf("expected no bcc on the email of a recipient, got %v", provider.sent[i].config)
+		}
+	}
+	copied := provider.sent[2].config
+	if copied["to"] != "lead@example.com" || copied["bcc"] != "audit@example.com" {
+		t.Errorf("expected a single copy to the cc and bcc, got %v", copied)
+	}
+}
+
+func TestReportService_RejectsNonSMTPChannel(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	_, err := svc.Create(context.Background(), &CreateUpdateDto{
+		Name:                  "Weekly",
+		Cadence:               CadenceWeekly,
+		NotificationChannelID: "hook",
+		Recipients:            []string{"cto@example.com"},
+		MonitorIDs:            []string{"api"},
+	})
+	if err != ErrChannelNotSMTP {
+		t.Errorf("expected ErrChannelNotSMTP, got %v", err)
+	}
+}
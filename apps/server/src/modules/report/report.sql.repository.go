@@ -0,0 +1,169 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:reports,alias:r"`
+
+	ID                    string     `bun:"id,pk"`
+	Name                  string     `bun:"name,notnull"`
+	Cadence               string     `bun:"cadence,notnull"`
+	NotificationChannelID string     `bun:"notification_channel_id,notnull"`
+	Recipients            string     `bun:"recipients"`      // Store as JSON string for compatibility
+	MonitorIDs            string     `bun:"monitor_ids"`     // Store as JSON string for compatibility
+	StatusPageIDs         string     `bun:"status_page_ids"` // Store as JSON string for compatibility
+	Active                bool       `bun:"active,notnull,default:true"`
+	LastSentAt            *time.Time `bun:"last_sent_at"`
+	CreatedAt             time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt             time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	var recipients, monitorIDs, statusPageIDs []string
+	if sm.Recipients != "" {
+		json.Unmarshal([]byte(sm.Recipients), &recipients)
+	}
+	if sm.MonitorIDs != "" {
+		json.Unmarshal([]byte(sm.MonitorIDs), &monitorIDs)
+	}
+	if sm.StatusPageIDs != "" {
+		json.Unmarshal([]byte(sm.StatusPageIDs), &statusPageIDs)
+	}
+
+	return &Model{
+		ID:                    sm.ID,
+		Name:                  sm.Name,
+		Cadence:               Cadence(sm.Cadence),
+		NotificationChannelID: sm.NotificationChannelID,
+		Recipients:            recipients,
+		MonitorIDs:            monitorIDs,
+		StatusPageIDs:         statusPageIDs,
+		Active:                sm.Active,
+		LastSentAt:            sm.LastSentAt,
+		CreatedAt:             sm.CreatedAt,
+		UpdatedAt:             sm.UpdatedAt,
+	}
+}
+
+func toSQLModel(m *Model) *sqlModel {
+	recipients, _ := json.Marshal(m.Recipients)
+	monitorIDs, _ := json.Marshal(m.MonitorIDs)
+	statusPageIDs, _ := json.Marshal(m.StatusPageIDs)
+
+	return &sqlModel{
+		ID:                    m.ID,
+		Name:                  m.Name,
+		Cadence:               string(m.Cadence),
+		NotificationChannelID: m.NotificationChannelID,
+		Recipients:            string(recipients),
+		MonitorIDs:            string(monitorIDs),
+		StatusPageIDs:         string(statusPageIDs),
+		Active:                m.Active,
+		LastSentAt:            m.LastSentAt,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, error) {
+	sm := toSQLModel(entity)
+	sm.ID = uuid.New().String()
+	sm.CreatedAt = time.Now()
+	sm.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindAll(ctx context.Context, page int, limit int) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Order("name ASC").
+		Limit(limit).
+		Offset(page * limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) FindActive(ctx context.Context) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("active = ?", true).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) UpdateFull(ctx context.Context, id string, entity *Model) error {
+	sm := toSQLModel(entity)
+	sm.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(sm).
+		Where("id = ?", id).
+		ExcludeColumn("id", "created_at", "last_sent_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) UpdateLastSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("last_sent_at = ?", sentAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
@@ -0,0 +1,189 @@
+package report
+
+import (
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/stats"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MonitorSummary holds the aggregates of one monitor over the report period
+type MonitorSummary struct {
+	MonitorID string        `json:"monitor_id"`
+	Name      string        `json:"name"`
+	Uptime    *float64      `json:"uptime"`
+	AvgPing   *float64      `json:"avg_ping"`
+	Checks    int           `json:"checks"`
+	Incidents int           `json:"incidents"`
+	Downtime  time.Duration `json:"downtime"`
+}
+
+// Summary is the content of a generated report
+type Summary struct {
+	ReportID    string            `json:"report_id"`
+	Name        string            `json:"name"`
+	Cadence     Cadence           `json:"cadence"`
+	PeriodStart time.Time         `json:"period_start"`
+	PeriodEnd   time.Time         `json:"period_end"`
+	Uptime      *float64          `json:"uptime"`
+	Incidents   int               `json:"incidents"`
+	Downtime    time.Duration     `json:"downtime"`
+	Monitors    []*MonitorSummary `json:"monitors"`
+}
+
+// ReportPeriod returns the last complete period of the cadence before now,
+// aligned to calendar days in loc. Weeks start on Monday.
+func ReportPeriod(cadence Cadence, now time.Time, loc *time.Location) (time.Time, time.Time) {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch cadence {
+	case CadenceWeekly:
+		daysSinceMonday := (int(today.Weekday()) + 6) % 7
+		end := today.AddDate(0, 0, -daysSinceMonday)
+		return end.AddDate(0, 0, -7), end
+	case CadenceMonthly:
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		return end.AddDate(0, -1, 0), end
+	default:
+		return today.AddDate(0, 0, -1), today
+	}
+}
+
+// summarizeMonitor aggregates the stat points and the important heartbeats of a monitor.
+// Incidents are the down transitions within the period, each lasting until the next
+// important heartbeat that is not down.
+func summarizeMonitor(m *shared.Monitor, points []*stats.Stat, important []*heartbeat.Model, start, end time.Time) *MonitorSummary {
+	summary := &MonitorSummary{MonitorID: m.ID, Name: m.Name}
+
+	var up, down, pingChecks int
+	var pingSum float64
+	for _, p := range points {
+		// the stats aggregation also counts maintenance checks as up
+		trueUp := p.Up - p.Maintenance
+		up += trueUp
+		down += p.Down
+		summary.Checks += p.Up + p.Down
+		if trueUp > 0 {
+			pingSum += p.Ping * float64(trueUp)
+			pingChecks += trueUp
+		}
+	}
+	// checks during maintenance do not count against uptime
+	if up+down > 0 {
+		uptime := float64(up) / float64(up+down) * 100
+		summary.Uptime = &uptime
+	}
+	if pingChecks > 0 {
+		avg := pingSum / float64(pingChecks)
+		summary.AvgPing = &avg
+	}
+
+	sorted := make([]*heartbeat.Model, len(important))
+	copy(sorted, important)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var downSince *time.Time
+	for _, hb := range sorted {
+		if !hb.Time.Before(end) {
+			break
+		}
+		if hb.Time.Before(start) {
+			// an outage still ongoing when the period starts only adds downtime
+			if hb.Status == shared.MonitorStatusDown {
+				periodStart := start
+				downSince = &periodStart
+			} else {
+				downSince = nil
+			}
+			continue
+		}
+		if hb.Status == shared.MonitorStatusDown {
+			if downSince == nil {
+				t := hb.Time
+				downSince = &t
+				summary.Incidents++
+			}
+			continue
+		}
+		if downSince != nil {
+			summary.Downtime += hb.Time.Sub(*downSince)
+			downSince = nil
+		}
+	}
+	if downSince != nil {
+		summary.Downtime += end.Sub(*downSince)
+	}
+
+	return summary
+}
+
+// combineSummaries adds the overall aggregates, the overall uptime is weighted by checks
+func combineSummaries(summary *Summary) {
+	var weighted float64
+	var checks int
+	for _, m := range summary.Monitors {
+		summary.Incidents += m.Incidents
+		summary.Downtime += m.Downtime
+		if m.Uptime != nil && m.Checks > 0 {
+			weighted += *m.Uptime * float64(m.Checks)
+			checks += m.Checks
+		}
+	}
+	if checks > 0 {
+		uptime := weighted / float64(checks)
+		summary.Uptime = &uptime
+	}
+}
+
+// Subject returns the email subject of the report
+func (s *Summary) Subject() string {
+	return fmt.Sprintf("Peekaping %s report: %s", s.Cadence, s.Name)
+}
+
+// Text renders the report as a plain text email body
+func (s *Summary) Text() string {
+	var b strings.Builder
+
+	const layout = "2006-01-02 15:04 MST"
+	fmt.Fprintf(&b, "%s uptime report: %s\n", strings.ToUpper(string(s.Cadence[:1]))+string(s.Cadence[1:]), s.Name)
+	fmt.Fprintf(&b, "Period: %s - %s\n\n", s.PeriodStart.Format(layout), s.PeriodEnd.Format(layout))
+
+	fmt.Fprintf(&b, "Overall uptime: %s\n", formatPercent(s.Uptime))
+	fmt.Fprintf(&b, "Incidents: %d", s.Incidents)
+	if s.Incidents > 0 {
+		fmt.Fprintf(&b, " (total downtime %s)", s.Downtime.Round(time.Second))
+	}
+	b.WriteString("\n\nMonitors:\n")
+
+	for _, m := range s.Monitors {
+		if m.Checks == 0 {
+			fmt.Fprintf(&b, "- %s: no data\n", m.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s uptime", m.Name, formatPercent(m.Uptime))
+		if m.AvgPing != nil {
+			fmt.Fprintf(&b, ", %.0f ms avg response", *m.AvgPing)
+		}
+		switch m.Incidents {
+		case 0:
+		case 1:
+			fmt.Fprintf(&b, ", 1 incident (%s down)", m.Downtime.Round(time.Second))
+		default:
+			fmt.Fprintf(&b, ", %d incidents (%s down)", m.Incidents, m.Downtime.Round(time.Second))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func formatPercent(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f%%", *v)
+}
@@ -0,0 +1,13 @@
+package shared
+
+import "time"
+
+// Blob is a stored payload, HTTP monitors reference it by ID to send a large
+// request body without embedding it in their config
+type Blob struct {
+	ID        string    `json:"id" bson:"_id"`
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdDate" bson:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
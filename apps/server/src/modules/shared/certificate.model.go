@@ -0,0 +1,16 @@
+package shared
+
+import "time"
+
+// Certificate is a stored mTLS client certificate, monitors reference it by
+// ID instead of embedding the PEM material in their config
+type Certificate struct {
+	ID   string `json:"id" bson:"_id"`
+	Name string `json:"name"`
+	Cert string `json:"cert"`
+	// Key is write-only and never returned by the API
+	Key       string    `json:"-"`
+	CA        string    `json:"ca,omitempty"`
+	CreatedAt time.Time `json:"createdDate" bson:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
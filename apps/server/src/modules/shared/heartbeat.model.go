@@ -11,6 +11,9 @@ const (
 	MonitorStatusUp
 	MonitorStatusPending
 	MonitorStatusMaintenance
+	// MonitorStatusDependencyDown is a failed check of a monitor whose parent
+	// is down as well, it does not notify
+	MonitorStatusDependencyDown
 )
 
 type HeartBeatModel struct {
@@ -26,6 +29,32 @@ type HeartBeatModel struct {
 	Time      time.Time     `json:"time"`
 	EndTime   time.Time     `json:"end_time"`
 	Notified  bool          `json:"notified"`
+	// Response headers captured by the check, HTTP monitors with capture_headers
+	Headers map[string]string `json:"headers,omitempty"`
+	// Latency distribution of the probes, set when the check took several samples
+	Samples *HeartBeatSamples `json:"samples,omitempty"`
+}
+
+// HeartBeatSamples summarizes the probes of a check made of several samples,
+// latencies are in milliseconds
+type HeartBeatSamples struct {
+	Count    int `json:"count"`
+	Up       int `json:"up"`
+	MinMs    int `json:"min_ms"`
+	AvgMs    int `json:"avg_ms"`
+	MaxMs    int `json:"max_ms"`
+	MedianMs int `json:"median_ms"`
+	P95Ms    int `json:"p95_ms"`
+}
+
+// MonitorStateTransition is a change of the status of a monitor from one
+// check to the next
+type MonitorStateTransition struct {
+	MonitorID      string        `json:"monitor_id"`
+	PreviousStatus MonitorStatus `json:"previous_status"`
+	Status         MonitorStatus `json:"status"`
+	Msg            string        `json:"msg"`
+	Time           time.Time     `json:"time"`
 }
 
 type HeartBeatChartPoint struct {
@@ -36,3 +65,12 @@ type HeartBeatChartPoint struct {
 	MaxPing   int     `json:"maxPing"`
 	Timestamp int64   `json:"timestamp"`
 }
+
+// HeartBeatLatencyPercentiles are response time percentiles in milliseconds of
+// the up heartbeats over a period
+type HeartBeatLatencyPercentiles struct {
+	P50   int `json:"p50"`
+	P95   int `json:"p95"`
+	P99   int `json:"p99"`
+	Count int `json:"count"`
+}
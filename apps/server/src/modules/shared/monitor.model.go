@@ -38,6 +38,43 @@ type Monitor struct {
 	ProxyId   string `json:"proxy_id"`
 	PushToken string `json:"push_token"`
 
+	// Keep checking and alerting while a maintenance window covers the monitor
+	IgnoreMaintenance bool `json:"ignore_maintenance"`
+
+	// Days of heartbeats to keep, 0 falls back to the global retention setting
+	RetentionDays int `json:"retention_days"`
+
+	// Percent the average check duration may rise over its baseline before alerting, 0 disables
+	SlowCheckThreshold int `json:"slow_check_threshold"`
+
+	// Markdown notes for whoever handles an alert of the monitor
+	Notes string `json:"notes"`
+
+	// Runbook linked from the notifications of the monitor
+	RunbookURL string `json:"runbook_url"`
+
+	// Proxies tried in order when the proxy is unhealthy
+	FailoverProxyIds []string `json:"failover_proxy_ids"`
+
+	// Consecutive timeouts after which only a TCP connect to the host is checked, 0 disables
+	LightProbeAfter int `json:"light_probe_after"`
+
+	// Interval in seconds of the TCP connect checks, 0 uses five times the interval
+	LightProbeInterval int `json:"light_probe_interval"`
+
+	// StateWebhookURL receives a POST on every status transition of the monitor
+	StateWebhookURL string `json:"state_webhook_url"`
+
+	// StateWebhookTemplate is a liquid template for the webhook body, the
+	// default JSON payload is sent when it is empty
+	StateWebhookTemplate string `json:"state_webhook_template"`
+
+	// Monitor this one depends on, alerts are held back while the parent is down
+	ParentID string `json:"parent_id"`
+
+	// When a paused monitor is resumed automatically, nil keeps it paused
+	ResumeAt *time.Time `json:"resume_at"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -57,6 +94,19 @@ type UpdateMonitor struct {
 	ProxyId        *string        `json:"proxy_id"`
 	PushToken      *string        `json:"push_token"`
 
+	IgnoreMaintenance    *bool   `json:"ignore_maintenance"`
+	RetentionDays        *int    `json:"retention_days"`
+	SlowCheckThreshold   *int    `json:"slow_check_threshold"`
+	Notes                *string `json:"notes"`
+	RunbookURL           *string `json:"runbook_url"`
+	LightProbeAfter      *int    `json:"light_probe_after"`
+	LightProbeInterval   *int    `json:"light_probe_interval"`
+	StateWebhookURL      *string `json:"state_webhook_url"`
+	StateWebhookTemplate *string `json:"state_webhook_template"`
+	ParentID             *string `json:"parent_id"`
+
+	FailoverProxyIds *[]string `json:"failover_proxy_ids"`
+
 	CreatedAt *time.Time `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 }
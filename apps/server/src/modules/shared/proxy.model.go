@@ -12,6 +12,12 @@ type Proxy struct {
 	Password  string    `json:"password,omitempty"`
 	CreatedAt time.Time `json:"createdDate" bson:"created_at"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+
+	// Outcomes of the monitor checks routed through the proxy
+	SuccessCount int64      `json:"success_count" bson:"success_count"`
+	FailureCount int64      `json:"failure_count" bson:"failure_count"`
+	LastError    string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty" bson:"last_error_at,omitempty"`
 }
 
 type UpdateProxy struct {
@@ -22,3 +28,12 @@ type UpdateProxy struct {
 	Username *string `json:"username,omitempty"`
 	Password *string `json:"password,omitempty"`
 }
+
+// ProxyCheckOutcome is the result of a monitor check routed through a proxy
+type ProxyCheckOutcome struct {
+	ProxyID   string    `json:"proxy_id"`
+	MonitorID string    `json:"monitor_id"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
@@ -13,13 +13,40 @@ const (
 )
 
 type Stat struct {
-	ID          string    `json:"id"`
-	MonitorID   string    `json:"monitor_id"`
-	Timestamp   time.Time `json:"timestamp"`
-	Ping        float64   `json:"ping"`
-	PingMin     float64   `json:"ping_min"`
-	PingMax     float64   `json:"ping_max"`
-	Up          int       `json:"up"`
-	Down        int       `json:"down"`
-	Maintenance int       `json:"maintenance"`
+	ID        string    `json:"id"`
+	MonitorID string    `json:"monitor_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Ping      float64   `json:"ping"`
+	PingMin   float64   `json:"ping_min"`
+	PingMax   float64   `json:"ping_max"`
+	// average check duration in milliseconds, maintenance beats excluded
+	Duration    float64 `json:"duration"`
+	Up          int     `json:"up"`
+	Down        int     `json:"down"`
+	Maintenance int     `json:"maintenance"`
+}
+
+// Bucket returns the length of the buckets the period aggregates heartbeats into
+func (p StatPeriod) Bucket() time.Duration {
+	switch p {
+	case StatHourly:
+		return time.Hour
+	case StatDaily:
+		return 24 * time.Hour
+	default:
+		return time.Minute
+	}
+}
+
+// PeriodForRange picks the finest period that covers since..until in at most
+// maxPoints buckets. Short ranges keep minute resolution while long ranges are
+// served from the hourly or daily rollups.
+func PeriodForRange(since, until time.Time, maxPoints int) StatPeriod {
+	span := until.Sub(since)
+	for _, period := range []StatPeriod{StatMinutely, StatHourly} {
+		if int(span/period.Bucket())+1 <= maxPoints {
+			return period
+		}
+	}
+	return StatDaily
 }
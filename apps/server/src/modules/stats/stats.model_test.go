@@ -0,0 +1,30 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodForRange(t *testing.T) {
+	until := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		span     time.Duration
+		expected StatPeriod
+	}{
+		{name: "an hour keeps minute resolution", span: time.Hour, expected: StatMinutely},
+		{name: "a full day of minutes fits", span: 24 * time.Hour, expected: StatMinutely},
+		{name: "just over a day uses hourly rollups", span: 24*time.Hour + time.Minute, expected: StatHourly},
+		{name: "a month uses hourly rollups", span: 30 * 24 * time.Hour, expected: StatHourly},
+		{name: "a year uses daily rollups", span: 365 * 24 * time.Hour, expected: StatDaily},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PeriodForRange(until.Add(-tt.span), until, 1441); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
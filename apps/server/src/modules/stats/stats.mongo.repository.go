@@ -42,6 +42,7 @@ type mongoModel struct {
 	Ping        float64            `bson:"ping"`
 	PingMin     float64            `bson:"ping_min"`
 	PingMax     float64            `bson:"ping_max"`
+	Duration    float64            `bson:"duration"`
 	Up          int                `bson:"up"`
 	Down        int                `bson:"down"`
 	Maintenance int                `bson:"maintenance"`
@@ -55,6 +56,7 @@ func toDomainModel(mm *mongoModel) *Stat {
 		Ping:        mm.Ping,
 		PingMin:     mm.PingMin,
 		PingMax:     mm.PingMax,
+		Duration:    mm.Duration,
 		Up:          mm.Up,
 		Down:        mm.Down,
 		Maintenance: mm.Maintenance,
@@ -113,6 +115,7 @@ func (r *MongoRepository) UpsertStat(ctx context.Context, stat *Stat, period Sta
 		Ping:        stat.Ping,
 		PingMin:     stat.PingMin,
 		PingMax:     stat.PingMax,
+		Duration:    stat.Duration,
 		Up:          stat.Up,
 		Down:        stat.Down,
 		Maintenance: stat.Maintenance,
@@ -125,6 +128,7 @@ func (r *MongoRepository) UpsertStat(ctx context.Context, stat *Stat, period Sta
 				"ping":        mm.Ping,
 				"ping_min":    mm.PingMin,
 				"ping_max":    mm.PingMax,
+				"duration":    mm.Duration,
 				"up":          mm.Up,
 				"down":        mm.Down,
 				"maintenance": mm.Maintenance,
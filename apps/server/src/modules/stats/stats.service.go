@@ -14,6 +14,7 @@ type HeartbeatPayload struct {
 	MonitorID string
 	Status    int
 	Ping      int
+	Duration  int
 	Time      int64 // Unix seconds
 }
 
@@ -39,7 +40,7 @@ func (s *ServiceImpl) flatStatus(status int) int {
 	switch status {
 	case 1, 3: // MonitorStatusUp, MonitorStatusMaintenance
 		return 1 // MonitorStatusUp
-	case 0, 2: // MonitorStatusDown, MonitorStatusPending
+	case 0, 2, 4: // MonitorStatusDown, MonitorStatusPending, MonitorStatusDependencyDown
 		return 0 // MonitorStatusDown
 	default:
 		return -1
@@ -101,6 +102,10 @@ func (s *ServiceImpl) AggregateHeartbeat(ctx context.Context, hb *HeartbeatPaylo
 		// Aggregate maintenance status separately
 		if hb.Status == 3 { // MonitorStatusMaintenance
 			statToUpsert.Maintenance = stat.Maintenance + 1
+		} else {
+			// maintenance beats are not checks, keep them out of the duration
+			checks := stat.Up + stat.Down - stat.Maintenance
+			statToUpsert.Duration = (stat.Duration*float64(checks) + float64(hb.Duration)) / float64(checks+1)
 		}
 
 		// Upsert stat
@@ -121,6 +126,7 @@ func (s *ServiceImpl) RegisterEventHandlers(eventBus *events.EventBus) {
 			MonitorID: payload.MonitorID,
 			Status:    int(payload.Status),
 			Ping:      payload.Ping,
+			Duration:  payload.Duration,
 			Time:      payload.Time.Unix(),
 		}
 		_ = s.AggregateHeartbeat(context.Background(), hb)
@@ -137,18 +143,7 @@ func (s *ServiceImpl) FindStatsByMonitorIDAndTimeRangeWithInterval(ctx context.C
 		return nil, err
 	}
 
-	// Determine bucket size
-	var bucket time.Duration
-	switch period {
-	case StatMinutely:
-		bucket = time.Minute
-	case StatHourly:
-		bucket = time.Hour
-	case StatDaily:
-		bucket = 24 * time.Hour
-	default:
-		bucket = time.Minute
-	}
+	bucket := period.Bucket()
 
 	// For minute-level stats, check if we need to group by monitor interval
 	if period == StatMinutely && monitorInterval > 60 {
@@ -264,9 +259,9 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		}
 	}
 
-	var totalPing, minPing, maxPing float64
+	var totalPing, minPing, maxPing, totalDuration float64
 	var totalUp, totalDown, totalMaintenance int
-	var pingCount int
+	var pingCount, checkCount int
 	var hasValidPing bool
 
 	for _, stat := range stats {
@@ -274,6 +269,10 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		totalDown += stat.Down
 		totalMaintenance += stat.Maintenance
 
+		checks := stat.Up + stat.Down - stat.Maintenance
+		totalDuration += stat.Duration * float64(checks)
+		checkCount += checks
+
 		// Only include stats with valid ping values (> 0) for ping calculations
 		if stat.Up > 0 && stat.Ping > 0 {
 			totalPing += stat.Ping * float64(stat.Up)
@@ -300,6 +299,11 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		avgPing = totalPing / float64(pingCount)
 	}
 
+	avgDuration := 0.0
+	if checkCount > 0 {
+		avgDuration = totalDuration / float64(checkCount)
+	}
+
 	// Set min/max ping to 0 if no valid ping data
 	if !hasValidPing {
 		minPing = 0
@@ -313,6 +317,7 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		Ping:        avgPing,
 		PingMin:     minPing,
 		PingMax:     maxPing,
+		Duration:    avgDuration,
 		Up:          totalUp,
 		Down:        totalDown,
 		Maintenance: totalMaintenance,
@@ -324,6 +329,7 @@ type Stats struct {
 	MaxPing     *float64 `json:"maxPing"`
 	MinPing     *float64 `json:"minPing"`
 	AvgPing     *float64 `json:"avgPing"`
+	AvgDuration *float64 `json:"avgDuration"`
 	Uptime      *float64 `json:"uptime"`
 	Maintenance *float64 `json:"maintenance"`
 }
@@ -332,8 +338,8 @@ type Stats struct {
 func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 	var maxPing *float64
 	var minPing *float64
-	var sumPing float64
-	var upCount int
+	var sumPing, sumDuration float64
+	var upCount, checkCount int
 	var totalUp, totalDown, totalMaintenance int
 
 	for _, s := range statsList {
@@ -349,6 +355,10 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 			sumPing += s.Ping * float64(s.Up)
 			upCount += s.Up
 		}
+		checks := s.Up + s.Down - s.Maintenance
+		sumDuration += s.Duration * float64(checks)
+		checkCount += checks
+
 		totalUp += s.Up
 		totalDown += s.Down
 		totalMaintenance += s.Maintenance
@@ -360,6 +370,12 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 		avgPing = &v
 	}
 
+	var avgDuration *float64
+	if checkCount > 0 {
+		v := sumDuration / float64(checkCount)
+		avgDuration = &v
+	}
+
 	var uptime *float64
 	var maintenance *float64
 	total := totalUp + totalDown + totalMaintenance
@@ -375,6 +391,7 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 		MaxPing:     maxPing,
 		MinPing:     minPing,
 		AvgPing:     avgPing,
+		AvgDuration: avgDuration,
 		Uptime:      uptime,
 		Maintenance: maintenance,
 	}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// memoryRepository keeps the stats of every period in memory, keyed by bucket start
+type memoryRepository struct {
+	Repository
+	stats map[StatPeriod]map[int64]*Stat
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{stats: make(map[StatPeriod]map[int64]*Stat)}
+}
+
+func (r *memoryRepository) GetOrCreateStat(ctx context.Context, monitorID string, timestamp time.Time, period StatPeriod) (*Stat, error) {
+	if stat, ok := r.stats[period][timestamp.Unix()]; ok {
+		copied := *stat
+		return &copied, nil
+	}
+	return &Stat{MonitorID: monitorID, Timestamp: timestamp}, nil
+}
+
+func (r *memoryRepository) UpsertStat(ctx context.Context, stat *Stat, period StatPeriod) error {
+	if r.stats[period] == nil {
+		r.stats[period] = make(map[int64]*Stat)
+	}
+	r.stats[period][stat.Timestamp.Unix()] = stat
+	return nil
+}
+
+func TestAggregateHeartbeat_Duration(t *testing.T) {
+	repo := newMemoryRepository()
+	service := NewService(repo, zap.NewNop().Sugar())
+
+	at := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	heartbeats := []*HeartbeatPayload{
+		{MonitorID: "api", Status: 1, Ping: 100, Duration: 100, Time: at.Unix()},
+		{MonitorID: "api", Status: 0, Ping: 5000, Duration: 5000, Time: at.Add(10 * time.Second).Unix()},
+		{MonitorID: "api", Status: 3, Time: at.Add(20 * time.Second).Unix()},
+		{MonitorID: "api", Status: 1, Ping: 300, Duration: 400, Time: at.Add(30 * time.Second).Unix()},
+	}
+	for _, hb := range heartbeats {
+		assert.NoError(t, service.AggregateHeartbeat(context.Background(), hb))
+	}
+
+	for _, period := range []StatPeriod{StatMinutely, StatHourly, StatDaily} {
+		stat := repo.stats[period][at.Truncate(period.Bucket()).Unix()]
+		// maintenance beats are not checks and do not lower the average
+		assert.InDelta(t, 1833.33, stat.Duration, 0.01, period)
+	}
+}
+
+func TestStatPointsSummary_AvgDuration(t *testing.T) {
+	service := NewService(newMemoryRepository(), zap.NewNop().Sugar())
+
+	summary := service.StatPointsSummary([]*Stat{
+		{Up: 3, Duration: 100},
+		{Up: 1, Down: 1, Maintenance: 1, Duration: 400},
+		{},
+	})
+
+	if assert.NotNil(t, summary.AvgDuration) {
+		// weighted by the number of checks of every bucket
+		assert.InDelta(t, 175, *summary.AvgDuration, 0.01)
+	}
+	assert.Nil(t, service.StatPointsSummary(nil).AvgDuration)
+}
@@ -17,6 +17,7 @@ type sqlModel struct {
 	Ping        float64   `bun:"ping,notnull,default:0"`
 	PingMin     float64   `bun:"ping_min,notnull,default:0"`
 	PingMax     float64   `bun:"ping_max,notnull,default:0"`
+	Duration    float64   `bun:"duration,notnull,default:0"`
 	Up          int       `bun:"up,notnull,default:0"`
 	Down        int       `bun:"down,notnull,default:0"`
 	Maintenance int       `bun:"maintenance,notnull,default:0"`
@@ -32,6 +33,7 @@ func toDomainModelFromSQL(sm *sqlModel) *Stat {
 		Ping:        sm.Ping,
 		PingMin:     sm.PingMin,
 		PingMax:     sm.PingMax,
+		Duration:    sm.Duration,
 		Up:          sm.Up,
 		Down:        sm.Down,
 		Maintenance: sm.Maintenance,
@@ -46,6 +48,7 @@ func toSQLModel(s *Stat) *sqlModel {
 		Ping:        s.Ping,
 		PingMin:     s.PingMin,
 		PingMax:     s.PingMax,
+		Duration:    s.Duration,
 		Up:          s.Up,
 		Down:        s.Down,
 		Maintenance: s.Maintenance,
@@ -107,6 +110,7 @@ func (r *SQLRepositoryImpl) UpsertStat(ctx context.Context, stat *Stat, period S
 		Set("ping = ?", sm.Ping).
 		Set("ping_min = ?", sm.PingMin).
 		Set("ping_max = ?", sm.PingMax).
+		Set("duration = ?", sm.Duration).
 		Set("up = ?", sm.Up).
 		Set("down = ?", sm.Down).
 		Set("maintenance = ?", sm.Maintenance).
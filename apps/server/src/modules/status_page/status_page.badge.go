@@ -0,0 +1,143 @@
+package status_page
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"text/template"
+)
+
+const (
+	badgeStyleFlat    = "flat"
+	badgeStylePlastic = "plastic"
+
+	badgeTypeStatus = "status"
+	badgeTypeUptime = "uptime"
+
+	// badgeMaxAge is how long clients and proxies may reuse a badge, in seconds
+	badgeMaxAge = 60
+	// badgePadding is the horizontal space around the text of each half
+	badgePadding = 10
+)
+
+// badgeColors follow the shields.io palette
+const (
+	badgeColorBrightGreen = "#4c1"
+	badgeColorGreen       = "#97ca00"
+	badgeColorYellow      = "#dfb317"
+	badgeColorOrange      = "#fe7d37"
+	badgeColorRed         = "#e05d44"
+	badgeColorBlue        = "#007ec6"
+	badgeColorGrey        = "#9f9f9f"
+)
+
+// badge is the data a badge is rendered from, label and message are escaped
+type badge struct {
+	Label        string
+	Message      string
+	Color        string
+	LabelWidth   int
+	MessageWidth int
+	Width        int
+	LabelX       int
+	MessageX     int
+	Plastic      bool
+}
+
+var badgeTemplate = template.Must(template.New("badge").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{if .Plastic}}18{{else}}20{{end}}" role="img" aria-label="{{.Label}}: {{.Message}}">` +
+		`<title>{{.Label}}: {{.Message}}</title>` +
+		`{{if .Plastic}}<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#fff" stop-opacity=".7"/><stop offset=".1" stop-color="#aaa" stop-opacity=".1"/><stop offset=".9" stop-opacity=".3"/><stop offset="1" stop-opacity=".5"/></linearGradient>` +
+		`{{else}}<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>{{end}}` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="{{if .Plastic}}18{{else}}20{{end}}" rx="{{if .Plastic}}4{{else}}3{{end}}" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="{{.LabelX}}" y="{{if .Plastic}}14{{else}}15{{end}}" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="{{if .Plastic}}13{{else}}14{{end}}">{{.Label}}</text>` +
+		`<text x="{{.MessageX}}" y="{{if .Plastic}}14{{else}}15{{end}}" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageX}}" y="{{if .Plastic}}13{{else}}14{{end}}">{{.Message}}</text>` +
+		`</g></svg>`,
+))
+
+// renderBadge draws a two part badge in the shields.io layout
+func renderBadge(label, message, color, style string) ([]byte, error) {
+	b := &badge{
+		Label:        escapeXML(label),
+		Message:      escapeXML(message),
+		Color:        color,
+		LabelWidth:   textWidth(label) + badgePadding,
+		MessageWidth: textWidth(message) + badgePadding,
+		Plastic:      style == badgeStylePlastic,
+	}
+	b.Width = b.LabelWidth + b.MessageWidth
+	b.LabelX = b.LabelWidth / 2
+	b.MessageX = b.LabelWidth + b.MessageWidth/2
+
+	var buf bytes.Buffer
+	if err := badgeTemplate.Execute(&buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// textWidth estimates the width of text in 11px Verdana
+func textWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == ':' || r == '|' || r == '!' || r == '\'':
+			width += 3.5
+		case r == 'f' || r == 't' || r == 'r' || r == 'I' || r == '(' || r == ')' || r == '-':
+			width += 4.5
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W' || r == '%':
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 6.8
+		}
+	}
+	return int(width + 0.5)
+}
+
+func escapeXML(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// statusBadge returns the message and color for the latest heartbeat
+func statusBadge(latest *heartbeat.Model) (string, string) {
+	if latest == nil {
+		return "unknown", badgeColorGrey
+	}
+	switch latest.Status {
+	case shared.MonitorStatusUp:
+		return "up", badgeColorBrightGreen
+	case shared.MonitorStatusDown:
+		return "down", badgeColorRed
+	case shared.MonitorStatusMaintenance:
+		return "maintenance", badgeColorBlue
+	case shared.MonitorStatusDependencyDown:
+		return "dependency down", badgeColorRed
+	default:
+		return "pending", badgeColorOrange
+	}
+}
+
+// uptimeBadge returns the message and color for an uptime percentage
+func uptimeBadge(uptime float64) (string, string) {
+	message := fmt.Sprintf("%.2f%%", uptime)
+	switch {
+	case uptime >= 99.9:
+		return message, badgeColorBrightGreen
+	case uptime >= 99:
+		return message, badgeColorGreen
+	case uptime >= 95:
+		return message, badgeColorYellow
+	case uptime >= 90:
+		return message, badgeColorOrange
+	default:
+		return message, badgeColorRed
+	}
+}
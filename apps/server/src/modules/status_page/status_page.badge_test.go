@@ -0,0 +1,168 @@
+package status_page
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/config"
+	"peekaping/src/modules/auth"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeMonitorStatusPages struct {
+	monitor_status_page.Service
+	monitorIDs []string
+}
+
+func (f *fakeMonitorStatusPages) GetMonitorsForStatusPage(ctx context.Context, statusPageID string) ([]*monitor_status_page.Model, error) {
+	relations := make([]*monitor_status_page.Model, 0, len(f.monitorIDs))
+	for _, id := range f.monitorIDs {
+		relations = append(relations, &monitor_status_page.Model{StatusPageID: statusPageID, MonitorID: id})
+	}
+	return relations, nil
+}
+
+type fakeBadgeMonitors struct {
+	monitor.Service
+	monitors map[string]*monitor.Model
+}
+
+func (f *fakeBadgeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return f.monitors[id], nil
+}
+
+type fakeBadgeHeartbeats struct {
+	heartbeat.Service
+	latest map[string]*heartbeat.Model
+	uptime float64
+}
+
+func (f *fakeBadgeHeartbeats) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	if hb, ok := f.latest[monitorID]; ok {
+		return []*heartbeat.Model{hb}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeBadgeHeartbeats) FindUptimeStatsByMonitorID(ctx context.Context, monitorID string, periods map[string]time.Duration, now time.Time) (map[string]float64, error) {
+	return map[string]float64{"24h": f.uptime}, nil
+}
+
+func newBadgeTestRouter(t *testing.T) (*gin.Engine, Service) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{ClientURL: "https://peekaping.example.com", AccessTokenSecretKey: "0123456789abcdef"}
+	service := NewService(&memoryRepository{pages: map[string]*Model{}}, nil, &fakeMonitorStatusPages{monitorIDs: []string{"api", "idle"}}, cfg, zap.NewNop().Sugar())
+
+	ctx := context.Background()
+	_, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "acme", Title: "Acme"})
+	require.NoError(t, err)
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "private", Title: "Private", Password: "correct horse"})
+	require.NoError(t, err)
+
+	monitors := &fakeBadgeMonitors{monitors: map[string]*monitor.Model{
+		"api":    {ID: "api", Name: "API"},
+		"idle":   {ID: "idle", Name: "Idle"},
+		"hidden": {ID: "hidden", Name: "Hidden"},
+	}}
+	heartbeats := &fakeBadgeHeartbeats{
+		latest: map[string]*heartbeat.Model{"api": {MonitorID: "api", Status: shared.MonitorStatusDown}},
+		uptime: 99.5,
+	}
+	logger := zap.NewNop().Sugar()
+	controller := NewController(service, monitors, heartbeats, NewPublicCache(cfg, logger), logger)
+	route := NewRoute(controller, auth.NewMiddlewareProvider(auth.NewTokenMaker(cfg)), NewDomainMiddleware(service, cfg, logger), NewPasswordMiddleware(service, logger))
+
+	router := gin.New()
+	route.ConnectRoute(router.Group("/api/v1"), controller)
+	return router, service
+}
+
+func getBadge(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// assertSVG checks that the body is well formed XML and returns it
+func assertSVG(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/svg+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+
+	decoder := xml.NewDecoder(strings.NewReader(rec.Body.String()))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	return rec.Body.String()
+}
+
+func TestBadge_Status(t *testing.T) {
+	router, _ := newBadgeTestRouter(t)
+
+	svg := assertSVG(t, getBadge(router, "/api/v1/status-pages/acme/badge/api.svg"))
+	assert.Contains(t, svg, "API: down")
+	assert.Contains(t, svg, badgeColorRed)
+	assert.Contains(t, svg, `height="20"`)
+
+	svg = assertSVG(t, getBadge(router, "/api/v1/status-pages/acme/badge/idle.svg?style=plastic"))
+	assert.Contains(t, svg, "Idle: unknown")
+	assert.Contains(t, svg, `height="18"`)
+}
+
+func TestBadge_Uptime(t *testing.T) {
+	router, _ := newBadgeTestRouter(t)
+
+	svg := assertSVG(t, getBadge(router, "/api/v1/status-pages/acme/badge/api.svg?type=uptime"))
+	assert.Contains(t, svg, "API: 99.50%")
+	assert.Contains(t, svg, badgeColorGreen)
+}
+
+func TestBadge_Errors(t *testing.T) {
+	router, _ := newBadgeTestRouter(t)
+
+	assert.Equal(t, http.StatusNotFound, getBadge(router, "/api/v1/status-pages/missing/badge/api.svg").Code)
+	assert.Equal(t, http.StatusNotFound, getBadge(router, "/api/v1/status-pages/acme/badge/hidden.svg").Code)
+	assert.Equal(t, http.StatusNotFound, getBadge(router, "/api/v1/status-pages/acme/badge/api.png").Code)
+	assert.Equal(t, http.StatusBadRequest, getBadge(router, "/api/v1/status-pages/acme/badge/api.svg?style=round").Code)
+	assert.Equal(t, http.StatusBadRequest, getBadge(router, "/api/v1/status-pages/acme/badge/api.svg?type=latency").Code)
+	assert.Equal(t, http.StatusUnauthorized, getBadge(router, "/api/v1/status-pages/private/badge/api.svg").Code)
+}
+
+func TestRenderBadge_EscapesLabel(t *testing.T) {
+	svg, err := renderBadge(`<script>&"`, "up", badgeColorBrightGreen, badgeStyleFlat)
+	require.NoError(t, err)
+	assert.NotContains(t, string(svg), "<script>")
+	assert.Contains(t, string(svg), "&lt;script&gt;&amp;")
+}
+
+func TestUptimeBadge_Colors(t *testing.T) {
+	for uptime, color := range map[float64]string{
+		100:   badgeColorBrightGreen,
+		99.9:  badgeColorBrightGreen,
+		99.2:  badgeColorGreen,
+		97:    badgeColorYellow,
+		91:    badgeColorOrange,
+		42.42: badgeColorRed,
+	} {
+		_, got := uptimeBadge(uptime)
+		assert.Equal(t, color, got, "uptime %.2f", uptime)
+	}
+}
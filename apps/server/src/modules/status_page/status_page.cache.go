@@ -0,0 +1,135 @@
+package status_page
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PublicCache keeps the computed public monitors payload of each status page
+// for a short time, so visitors of a busy page don't each re-query heartbeats
+// and uptime. Entries are dropped early when one of their monitors changes.
+type PublicCache struct {
+	ttl    time.Duration
+	now    func() time.Time
+	logger *zap.SugaredLogger
+
+	mu      sync.Mutex
+	entries map[string]*publicCacheEntry
+}
+
+type publicCacheEntry struct {
+	pageID     string
+	monitorIDs map[string]struct{}
+	monitors   []*MonitorWithHeartbeatsAndUptimeDTO
+	expiresAt  time.Time
+}
+
+func NewPublicCache(cfg *config.Config, logger *zap.SugaredLogger) *PublicCache {
+	return newPublicCache(cfg.StatusPageCacheTTL, time.Now, logger)
+}
+
+func newPublicCache(ttl time.Duration, now func() time.Time, logger *zap.SugaredLogger) *PublicCache {
+	return &PublicCache{
+		ttl:     ttl,
+		now:     now,
+		logger:  logger.Named("[status-page-cache]"),
+		entries: make(map[string]*publicCacheEntry),
+	}
+}
+
+// RegisterEventHandlers drops cached pages showing a monitor that changed
+func (c *PublicCache) RegisterEventHandlers(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorStatusChanged, c.handleMonitorEvent)
+	eventBus.Subscribe(events.MonitorUpdated, c.handleMonitorEvent)
+	eventBus.Subscribe(events.MonitorDeleted, c.handleMonitorEvent)
+}
+
+func (c *PublicCache) handleMonitorEvent(event events.Event) {
+	switch payload := event.Payload.(type) {
+	case *shared.HeartBeatModel:
+		c.InvalidateMonitor(payload.MonitorID)
+	case *shared.Monitor:
+		c.InvalidateMonitor(payload.ID)
+	case string:
+		c.InvalidateMonitor(payload)
+	default:
+		c.logger.Warnf("Unexpected payload for %s event: %T", event.Type, event.Payload)
+	}
+}
+
+func publicCacheKey(pageID, variant string) string {
+	return pageID + "/" + variant
+}
+
+// Get returns the cached payload of the page variant, if still fresh
+func (c *PublicCache) Get(pageID, variant string) ([]*MonitorWithHeartbeatsAndUptimeDTO, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := publicCacheKey(pageID, variant)
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.monitors, true
+}
+
+// Set caches the payload of the page variant, monitorIDs are the monitors
+// whose changes invalidate it
+func (c *PublicCache) Set(pageID, variant string, monitorIDs []string, monitors []*MonitorWithHeartbeatsAndUptimeDTO) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	ids := make(map[string]struct{}, len(monitorIDs))
+	for _, id := range monitorIDs {
+		ids[id] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[publicCacheKey(pageID, variant)] = &publicCacheEntry{
+		pageID:     pageID,
+		monitorIDs: ids,
+		monitors:   monitors,
+		expiresAt:  c.now().Add(c.ttl),
+	}
+}
+
+// InvalidatePage drops every cached variant of the page
+func (c *PublicCache) InvalidatePage(pageID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if entry.pageID == pageID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateMonitor drops every cached page showing the monitor
+func (c *PublicCache) InvalidateMonitor(monitorID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if _, ok := entry.monitorIDs[monitorID]; ok {
+			delete(c.entries, key)
+		}
+	}
+}
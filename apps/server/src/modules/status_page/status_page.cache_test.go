@@ -0,0 +1,115 @@
+package status_page
+
+import (
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestCache(ttl time.Duration) (*PublicCache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)}
+	return newPublicCache(ttl, clock.Now, zap.NewNop().Sugar()), clock
+}
+
+func publicMonitors(ids ...string) []*MonitorWithHeartbeatsAndUptimeDTO {
+	monitors := make([]*MonitorWithHeartbeatsAndUptimeDTO, 0, len(ids))
+	for _, id := range ids {
+		monitors = append(monitors, &MonitorWithHeartbeatsAndUptimeDTO{PublicMonitorDTO: &PublicMonitorDTO{ID: id}})
+	}
+	return monitors
+}
+
+func TestPublicCache_ServesWithinTTL(t *testing.T) {
+	cache, clock := newTestCache(10 * time.Second)
+	cache.Set("page1", "full", []string{"api"}, publicMonitors("api"))
+
+	clock.now = clock.now.Add(9 * time.Second)
+	cached, ok := cache.Get("page1", "full")
+	assert.True(t, ok, "expected the payload to be served within the TTL")
+	assert.Equal(t, "api", cached[0].ID)
+
+	_, ok = cache.Get("page1", "homepage")
+	assert.False(t, ok, "expected the variants of a page to be cached separately")
+
+	clock.now = clock.now.Add(time.Second)
+	_, ok = cache.Get("page1", "full")
+	assert.False(t, ok, "expected the payload to expire after the TTL")
+}
+
+func TestPublicCache_Disabled(t *testing.T) {
+	cache, _ := newTestCache(-time.Second)
+	cache.Set("page1", "full", []string{"api"}, publicMonitors("api"))
+
+	_, ok := cache.Get("page1", "full")
+	assert.False(t, ok, "expected a negative TTL to disable the cache")
+}
+
+func TestPublicCache_InvalidatePage(t *testing.T) {
+	cache, _ := newTestCache(10 * time.Second)
+	cache.Set("page1", "full", []string{"api"}, publicMonitors("api"))
+	cache.Set("page1", "homepage", []string{"api"}, publicMonitors("api"))
+	cache.Set("page2", "full", []string{"api"}, publicMonitors("api"))
+
+	cache.InvalidatePage("page1")
+
+	_, full := cache.Get("page1", "full")
+	_, homepage := cache.Get("page1", "homepage")
+	_, other := cache.Get("page2", "full")
+	assert.False(t, full || homepage, "expected every variant of the edited page to be dropped")
+	assert.True(t, other, "expected other pages to stay cached")
+}
+
+func TestPublicCache_InvalidatedOnMonitorEvents(t *testing.T) {
+	bus := events.NewEventBus(zap.NewNop().Sugar())
+	cache, _ := newTestCache(10 * time.Second)
+	cache.RegisterEventHandlers(bus)
+
+	tests := []struct {
+		name  string
+		event events.Event
+	}{
+		{
+			name:  "status change",
+			event: events.Event{Type: events.MonitorStatusChanged, Payload: &heartbeat.Model{MonitorID: "api", Status: shared.MonitorStatusDown}},
+		},
+		{
+			name:  "monitor updated",
+			event: events.Event{Type: events.MonitorUpdated, Payload: &monitor.Model{ID: "api", Name: "Renamed"}},
+		},
+		{
+			name:  "monitor deleted",
+			event: events.Event{Type: events.MonitorDeleted, Payload: "api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache.Set("page1", "full", []string{"api", "db"}, publicMonitors("api", "db"))
+			cache.Set("page2", "full", []string{"db"}, publicMonitors("db"))
+
+			bus.Publish(tt.event)
+
+			// handlers run asynchronously
+			assert.Eventually(t, func() bool {
+				_, ok := cache.Get("page1", "full")
+				return !ok
+			}, time.Second, time.Millisecond, "expected the page showing the monitor to be dropped")
+			_, ok := cache.Get("page2", "full")
+			assert.True(t, ok, "expected pages without the monitor to stay cached")
+		})
+	}
+}
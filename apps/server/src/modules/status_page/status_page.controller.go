@@ -1,10 +1,13 @@
 package status_page
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
 	"peekaping/src/utils"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,14 +18,16 @@ type Controller struct {
 	service          Service
 	monitorService   monitor.Service
 	heartbeatService heartbeat.Service
+	cache            *PublicCache
 	logger           *zap.SugaredLogger
 }
 
-func NewController(service Service, monitorService monitor.Service, heartbeatService heartbeat.Service, logger *zap.SugaredLogger) *Controller {
+func NewController(service Service, monitorService monitor.Service, heartbeatService heartbeat.Service, cache *PublicCache, logger *zap.SugaredLogger) *Controller {
 	return &Controller{
 		service:          service,
 		monitorService:   monitorService,
 		heartbeatService: heartbeatService,
+		cache:            cache,
 		logger:           logger,
 	}
 }
@@ -51,6 +56,14 @@ func (c *Controller) Create(ctx *gin.Context) {
 
 	created, err := c.service.Create(ctx, &dto)
 	if err != nil {
+		if errors.Is(err, ErrInvalidCustomDomain) {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrCustomDomainTaken) {
+			ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+			return
+		}
 		c.logger.Errorw("Failed to create status page", "error", err)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 		return
@@ -106,6 +119,69 @@ func (c *Controller) FindBySlug(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", page))
 }
 
+// @Router    /status-pages/domain [get]
+// @Summary   Get the status page served on the request host
+// @Tags      Status Pages
+// @Produce   json
+// @Success   200  {object}  utils.ApiResponse[DomainPageDTO]
+// @Failure   404  {object}  utils.APIError[any]
+func (c *Controller) FindByDomain(ctx *gin.Context) {
+	page := DomainPage(ctx)
+	if page == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	}
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", DomainPageDTO{
+		Slug:              page.Slug,
+		PasswordProtected: page.PasswordProtected,
+	}))
+}
+
+// @Router    /status-pages/slug/{slug}/unlock [post]
+// @Summary   Unlock a password protected status page
+// @Tags      Status Pages
+// @Accept    json
+// @Produce   json
+// @Param     slug  path      string               true  "Status Page Slug"
+// @Param     body  body      UnlockStatusPageDTO  true  "Page password"
+// @Success   200   {object}  utils.ApiResponse[AccessTokenDTO]
+// @Failure   400   {object}  utils.APIError[any]
+// @Failure   401   {object}  utils.APIError[any]
+// @Failure   404   {object}  utils.APIError[any]
+// @Failure   500   {object}  utils.APIError[any]
+func (c *Controller) Unlock(ctx *gin.Context) {
+	var dto UnlockStatusPageDTO
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	slug := ctx.Param("slug")
+	token, err := c.service.Unlock(ctx, slug, dto.Password)
+	switch {
+	case errors.Is(err, ErrStatusPageNotFound):
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	case errors.Is(err, ErrNotPasswordProtected):
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	case errors.Is(err, ErrIncorrectPassword):
+		ctx.JSON(http.StatusUnauthorized, utils.NewFailResponse("Incorrect password"))
+		return
+	case err != nil:
+		c.logger.Errorw("Failed to unlock status page", "error", err, "slug", slug)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Status page unlocked", token))
+}
+
 // @Router    /status-pages [get]
 // @Summary   Get all status pages
 // @Tags      Status Pages
@@ -159,12 +235,26 @@ func (c *Controller) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := utils.Validate.Struct(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
 	updated, err := c.service.Update(ctx, id, &dto)
 	if err != nil {
+		if errors.Is(err, ErrInvalidCustomDomain) {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrCustomDomainTaken) {
+			ctx.JSON(http.StatusConflict, utils.NewFailResponse(err.Error()))
+			return
+		}
 		c.logger.Errorw("Failed to update status page", "error", err, "id", id)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 		return
 	}
+	c.cache.InvalidatePage(id)
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Status page updated successfully", updated))
 }
 
@@ -185,6 +275,7 @@ func (c *Controller) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 		return
 	}
+	c.cache.InvalidatePage(id)
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Status page deleted successfully", nil))
 }
 
@@ -197,108 +288,128 @@ func (c *Controller) Delete(ctx *gin.Context) {
 // @Failure   404  {object}  utils.APIError[any]
 // @Failure   500  {object}  utils.APIError[any]
 func (c *Controller) GetMonitorsBySlug(ctx *gin.Context) {
-	slug := ctx.Param("slug")
+	c.respondWithPublicMonitors(ctx, "full", 100)
+}
+
+// @Router    /status-pages/slug/{slug}/monitors/homepage [get]
+// @Summary   Get monitors for a status page by slug for homepage
+// @Tags      Status Pages
+// @Produce   json
+// @Param     slug path      string  true  "Status Page Slug"
+// @Success   200  {object}  utils.ApiResponse[[]MonitorWithHeartbeatsAndUptimeDTO]
+// @Failure   404  {object}  utils.APIError[any]
+// @Failure   500  {object}  utils.APIError[any]
+func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
+	c.respondWithPublicMonitors(ctx, "homepage", 1)
+}
+
+// @Router    /status-pages/{slug}/badge/{monitorId}.svg [get]
+// @Summary   Get an SVG badge of a monitor on a status page
+// @Tags      Status Pages
+// @Produce   image/svg+xml
+// @Param     slug       path   string  true   "Status Page Slug"
+// @Param     monitorId  path   string  true   "Monitor ID"
+// @Param     type       query  string  false  "status (default) or uptime for the 24h uptime"
+// @Param     style      query  string  false  "flat (default) or plastic"
+// @Success   200  {string}  string
+// @Failure   400  {object}  utils.APIError[any]
+// @Failure   401  {object}  utils.APIError[any]
+// @Failure   404  {object}  utils.APIError[any]
+// @Failure   500  {object}  utils.APIError[any]
+func (c *Controller) Badge(ctx *gin.Context) {
+	// the slug shares the :id wildcard with the authenticated routes
+	slug := ctx.Param("id")
+	monitorID, ok := strings.CutSuffix(ctx.Param("monitor"), ".svg")
+	if !ok || monitorID == "" {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Badge not found"))
+		return
+	}
+
+	style := ctx.DefaultQuery("style", badgeStyleFlat)
+	if style != badgeStyleFlat && style != badgeStylePlastic {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid style parameter (flat or plastic)"))
+		return
+	}
+	badgeType := ctx.DefaultQuery("type", badgeTypeStatus)
+	if badgeType != badgeTypeStatus && badgeType != badgeTypeUptime {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid type parameter (status or uptime)"))
+		return
+	}
 
-	// First get the status page
 	page, err := c.service.FindBySlug(ctx, slug)
 	if err != nil {
 		c.logger.Errorw("Failed to get status page by slug", "error", err, "slug", slug)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 		return
 	}
-	if page == nil {
+	if domainPage := DomainPage(ctx); page == nil || (domainPage != nil && domainPage.ID != page.ID) {
 		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
 		return
 	}
+	if !c.service.CanAccess(page, bearerToken(ctx)) {
+		ctx.JSON(http.StatusUnauthorized, utils.NewFailResponse("Status page is password protected"))
+		return
+	}
 
-	// Get monitors for the status page
-	monitors, err := c.service.GetMonitorsForStatusPage(ctx, page.ID)
+	relations, err := c.service.GetMonitorsForStatusPage(ctx, page.ID)
 	if err != nil {
 		c.logger.Errorw("Failed to get monitors for status page", "error", err, "statusPageID", page.ID)
 		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 		return
 	}
-
-	// Convert monitor_status_page models to monitor models with heartbeats and uptime
-	monitorModels := make([]*MonitorWithHeartbeatsAndUptimeDTO, 0, len(monitors))
-	for _, msp := range monitors {
-		// Get the actual monitor data
-		monitorModel, err := c.monitorService.FindByID(ctx, msp.MonitorID)
-		if err != nil {
-			c.logger.Errorw("Failed to get monitor by ID", "error", err, "monitorID", msp.MonitorID)
-			continue
-		}
-		if monitorModel == nil {
-			continue
-		}
-
-		// Get 100 heartbeats for this monitor
-		heartbeats, err := c.heartbeatService.FindByMonitorIDPaginated(ctx, msp.MonitorID, 100, 0, nil, true)
-		if err != nil {
-			c.logger.Errorw("Failed to get heartbeats for monitor", "error", err, "monitorID", msp.MonitorID)
-			heartbeats = []*heartbeat.Model{} // Empty slice if error
+	onPage := false
+	for _, relation := range relations {
+		onPage = onPage || relation.MonitorID == monitorID
+	}
+	var monitorModel *monitor.Model
+	if onPage {
+		if monitorModel, err = c.monitorService.FindByID(ctx, monitorID); err != nil {
+			c.logger.Errorw("Failed to get monitor by ID", "error", err, "monitorID", monitorID)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
 		}
+	}
+	if monitorModel == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		return
+	}
 
-		// Convert heartbeats to public DTOs
-		publicHeartbeats := make([]*PublicHeartbeatDTO, 0, len(heartbeats))
-		for _, hb := range heartbeats {
-			publicHeartbeat := &PublicHeartbeatDTO{
-				ID:      hb.ID,
-				Status:  hb.Status,
-				Time:    hb.Time,
-				EndTime: hb.EndTime,
-				Ping:    hb.Ping,
-			}
-			publicHeartbeats = append(publicHeartbeats, publicHeartbeat)
-		}
+	latest, err := c.heartbeatService.FindByMonitorIDPaginated(ctx, monitorID, 1, 0, nil, false)
+	if err != nil {
+		c.logger.Errorw("Failed to get heartbeats for monitor", "error", err, "monitorID", monitorID)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	var latestBeat *heartbeat.Model
+	if len(latest) > 0 {
+		latestBeat = latest[0]
+	}
 
-		// Get 24h uptime for this monitor
-		now := time.Now().UTC()
-		periods := map[string]time.Duration{
-			"24h": 24 * time.Hour,
-		}
-		uptimeStats, err := c.heartbeatService.FindUptimeStatsByMonitorID(ctx, msp.MonitorID, periods, now)
+	message, color := statusBadge(latestBeat)
+	if badgeType == badgeTypeUptime && latestBeat != nil {
+		uptimeStats, err := c.heartbeatService.FindUptimeStatsByMonitorID(ctx, monitorID, map[string]time.Duration{"24h": 24 * time.Hour}, time.Now().UTC())
 		if err != nil {
-			c.logger.Errorw("Failed to get uptime stats for monitor", "error", err, "monitorID", msp.MonitorID)
-			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("failed to get uptime stats for monitor"))
+			c.logger.Errorw("Failed to get uptime stats for monitor", "error", err, "monitorID", monitorID)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
 			return
 		}
+		message, color = uptimeBadge(uptimeStats["24h"])
+	}
 
-		uptime24h := 0.0
-		if uptimeStats != nil {
-			if uptime, exists := uptimeStats["24h"]; exists {
-				uptime24h = uptime
-			}
-		}
-
-		publicMonitor := &PublicMonitorDTO{
-			ID:     monitorModel.ID,
-			Type:   monitorModel.Type,
-			Name:   monitorModel.Name,
-			Active: monitorModel.Active,
-		}
-
-		monitorWithData := &MonitorWithHeartbeatsAndUptimeDTO{
-			PublicMonitorDTO: publicMonitor,
-			Heartbeats:       publicHeartbeats,
-			Uptime24h:        uptime24h,
-		}
-
-		monitorModels = append(monitorModels, monitorWithData)
+	svg, err := renderBadge(monitorModel.Name, message, color, style)
+	if err != nil {
+		c.logger.Errorw("Failed to render badge", "error", err, "monitorID", monitorID)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", monitorModels))
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", badgeMaxAge))
+	ctx.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
 }
 
-// @Router    /status-pages/slug/{slug}/monitors/homepage [get]
-// @Summary   Get monitors for a status page by slug for homepage
-// @Tags      Status Pages
-// @Produce   json
-// @Param     slug path      string  true  "Status Page Slug"
-// @Success   200  {object}  utils.ApiResponse[[]MonitorWithHeartbeatsAndUptimeDTO]
-// @Failure   404  {object}  utils.APIError[any]
-// @Failure   500  {object}  utils.APIError[any]
-func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
+// respondWithPublicMonitors serves the monitors of the status page with their
+// latest heartbeatLimit heartbeats, from the cache while it is fresh
+func (c *Controller) respondWithPublicMonitors(ctx *gin.Context, variant string, heartbeatLimit int) {
 	slug := ctx.Param("slug")
 
 	// First get the status page
@@ -313,6 +424,11 @@ func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
 		return
 	}
 
+	if cached, ok := c.cache.Get(page.ID, variant); ok {
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", cached))
+		return
+	}
+
 	// Get monitors for the status page
 	monitors, err := c.service.GetMonitorsForStatusPage(ctx, page.ID)
 	if err != nil {
@@ -322,8 +438,11 @@ func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
 	}
 
 	// Convert monitor_status_page models to monitor models with heartbeats and uptime
+	monitorIDs := make([]string, 0, len(monitors))
 	monitorModels := make([]*MonitorWithHeartbeatsAndUptimeDTO, 0, len(monitors))
 	for _, msp := range monitors {
+		monitorIDs = append(monitorIDs, msp.MonitorID)
+
 		// Get the actual monitor data
 		monitorModel, err := c.monitorService.FindByID(ctx, msp.MonitorID)
 		if err != nil {
@@ -334,8 +453,8 @@ func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
 			continue
 		}
 
-		// Get 100 heartbeats for this monitor
-		heartbeats, err := c.heartbeatService.FindByMonitorIDPaginated(ctx, msp.MonitorID, 1, 0, nil, true)
+		// Get the latest heartbeats for this monitor
+		heartbeats, err := c.heartbeatService.FindByMonitorIDPaginated(ctx, msp.MonitorID, heartbeatLimit, 0, nil, true)
 		if err != nil {
 			c.logger.Errorw("Failed to get heartbeats for monitor", "error", err, "monitorID", msp.MonitorID)
 			heartbeats = []*heartbeat.Model{} // Empty slice if error
@@ -389,5 +508,6 @@ func (c *Controller) GetMonitorsBySlugForHomepage(ctx *gin.Context) {
 		monitorModels = append(monitorModels, monitorWithData)
 	}
 
+	c.cache.Set(page.ID, variant, monitorIDs, monitorModels)
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", monitorModels))
 }
@@ -0,0 +1,77 @@
+package status_page
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	cssHexEscapeRe  = regexp.MustCompile(`\\([0-9a-fA-F]{1,6})[ \t\n\r\f]?`)
+	cssCharEscapeRe = regexp.MustCompile(`\\([^0-9a-fA-F\n\r\f])`)
+	cssCommentRe    = regexp.MustCompile(`/\*[\s\S]*?(\*/|$)`)
+	cssImportRe     = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	cssURLRe        = regexp.MustCompile(`(?i)url\s*\(\s*(['"]?)([^)]*?)(['"]?)\s*(\)|$)`)
+	// Functions that load resources or run script without url()
+	cssFunctionRe = regexp.MustCompile(`(?i)(^|[^a-z0-9_-])(-webkit-image-set|image-set|cross-fade|element|expression|image|src)\s*\([^)]*(\)|$)`)
+	cssPropertyRe = regexp.MustCompile(`(?i)(-moz-binding|behavior)\s*:[^;}]*;?\s*`)
+	cssSchemeRe   = regexp.MustCompile(`(?i)(javascript|vbscript)\s*:`)
+
+	// Inline raster images are the only resources custom CSS may reference
+	cssAllowedDataURLRe = regexp.MustCompile(`(?i)^data:image/(png|gif|jpeg|webp);base64,[a-z0-9+/=\s]*$`)
+)
+
+// SanitizeCustomCSS strips constructs from user supplied CSS that could load
+// external resources, run script or break out of the surrounding style element.
+// The checks run on CSS with escapes decoded and comments removed so neither can
+// hide a construct, CSS without anything to strip is returned unchanged. The
+// HTML parser does not know CSS comments, so CSS with a "<" anywhere, comments
+// included, is always returned normalized.
+func SanitizeCustomCSS(css string) string {
+	normalized := normalizeCSS(css)
+	cleaned := stripDisallowedCSS(normalized)
+	if cleaned == normalized && !strings.Contains(css, "<") {
+		return strings.TrimSpace(css)
+	}
+
+	// Removing a construct can join the surroundings into a new one
+	for i := 0; i < 5; i++ {
+		next := stripDisallowedCSS(normalizeCSS(cleaned))
+		if next == cleaned {
+			break
+		}
+		cleaned = next
+	}
+	return strings.TrimSpace(cleaned)
+}
+
+// normalizeCSS decodes escapes and removes comments
+func normalizeCSS(css string) string {
+	css = cssHexEscapeRe.ReplaceAllStringFunc(css, func(match string) string {
+		hex := strings.TrimRight(strings.TrimPrefix(match, `\`), " \t\n\r\f")
+		code, err := strconv.ParseInt(hex, 16, 32)
+		if err != nil || code == 0 || code > 0x10FFFF {
+			return "\uFFFD"
+		}
+		return string(rune(code))
+	})
+	css = cssCharEscapeRe.ReplaceAllString(css, "$1")
+	return cssCommentRe.ReplaceAllString(css, "")
+}
+
+func stripDisallowedCSS(css string) string {
+	// "<" is never needed in CSS and would allow closing the style element
+	css = strings.ReplaceAll(css, "<", "")
+
+	css = cssImportRe.ReplaceAllString(css, "")
+	css = cssURLRe.ReplaceAllStringFunc(css, func(match string) string {
+		target := strings.TrimSpace(cssURLRe.FindStringSubmatch(match)[2])
+		if cssAllowedDataURLRe.MatchString(target) {
+			return match
+		}
+		return "none"
+	})
+	css = cssFunctionRe.ReplaceAllString(css, "${1}none")
+	css = cssPropertyRe.ReplaceAllString(css, "")
+	return cssSchemeRe.ReplaceAllString(css, "")
+}
@@ -0,0 +1,77 @@
+package status_page
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeCustomCSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		css      string
+		expected string
+	}{
+		{
+			name:     "plain css is kept as is",
+			css:      ".header { color: #1a2b3c; } /* brand */ .title > span { content: \"\\201C\"; }",
+			expected: ".header { color: #1a2b3c; } /* brand */ .title > span { content: \"\\201C\"; }",
+		},
+		{
+			name:     "import is removed",
+			css:      "@import url(\"https://evil.example.com/x.css\"); body { margin: 0; }",
+			expected: "body { margin: 0; }",
+		},
+		{
+			name:     "external url is replaced",
+			css:      "body { background: url('https://tracker.example.com/pixel.png') no-repeat; }",
+			expected: "body { background: none no-repeat; }",
+		},
+		{
+			name:     "inline raster image is allowed",
+			css:      "body { background: url(data:image/png;base64,iVBORw0KGgo=); }",
+			expected: "body { background: url(data:image/png;base64,iVBORw0KGgo=); }",
+		},
+		{
+			name:     "inline svg is not allowed",
+			css:      "body { background: url(\"data:image/svg+xml,<svg onload=alert(1)>\"); }",
+			expected: "body { background: none>\"); }",
+		},
+		{
+			name:     "image-set loads without url",
+			css:      "body { background-image: image-set(\"https://evil.example.com/a.png\" 1x); }",
+			expected: "body { background-image: none; }",
+		},
+		{
+			name:     "escaped url is decoded before checking",
+			css:      "body { background: \\75rl(https://evil.example.com/a.png); }",
+			expected: "body { background: none; }",
+		},
+		{
+			name:     "comment splitting a keyword",
+			css:      "@imp/**/ort 'https://evil.example.com/x.css'; a { color: red; }",
+			expected: "a { color: red; }",
+		},
+		{
+			name:     "script constructs are removed",
+			css:      "a { width: expression(alert(1)); behavior: url(x.htc); -moz-binding: url(x.xml#x); background: javascript:alert(1); }",
+			expected: "a { width: none); background: alert(1); }",
+		},
+		{
+			name:     "closing the style element",
+			css:      "a { color: red; }</style><script>alert(1)</script>",
+			expected: "a { color: red; }/style>script>alert(1)/script>",
+		},
+		{
+			name:     "closing the style element inside a comment",
+			css:      "/* </style><script>alert(1)</script> */ body{color:red}",
+			expected: "body{color:red}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeCustomCSS(tt.css))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package status_page
 
 import (
 	"peekaping/src/config"
+	"peekaping/src/modules/events"
 	"peekaping/src/utils"
 
 	"go.uber.org/dig"
@@ -10,6 +11,12 @@ import (
 func RegisterDependencies(container *dig.Container, cfg *config.Config) {
 	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
 	container.Provide(NewService)
+	container.Provide(NewPublicCache)
+	container.Provide(NewDomainMiddleware)
+	container.Provide(NewPasswordMiddleware)
 	container.Provide(NewController)
 	container.Provide(NewRoute)
+	container.Invoke(func(cache *PublicCache, bus *events.EventBus) {
+		cache.RegisterEventHandlers(bus)
+	})
 }
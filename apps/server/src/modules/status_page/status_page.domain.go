@@ -0,0 +1,126 @@
+package status_page
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"peekaping/src/config"
+	"peekaping/src/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidCustomDomain = errors.New("custom domain must be a fully qualified domain name")
+	ErrCustomDomainTaken   = errors.New("custom domain is already used by another status page")
+)
+
+// domainPageKey holds the status page resolved from the Host header
+const domainPageKey = "status_page_domain_page"
+
+// NormalizeDomain lowercases a host and strips its port and trailing dot
+func NormalizeDomain(host string) string {
+	host = strings.TrimSpace(strings.ToLower(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// clientHost is the host the dashboard and API are served on
+func clientHost(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	u, err := url.Parse(cfg.ClientURL)
+	if err != nil {
+		return ""
+	}
+	return NormalizeDomain(u.Host)
+}
+
+// checkCustomDomain validates a normalized domain and that no page other
+// than the one with the id is served on it. An empty domain is always valid.
+func (s *ServiceImpl) checkCustomDomain(ctx context.Context, domain, id string) error {
+	if domain == "" {
+		return nil
+	}
+	if utils.Validate.Var(domain, "fqdn") != nil || domain == clientHost(s.cfg) {
+		return ErrInvalidCustomDomain
+	}
+
+	existing, err := s.repository.FindByDomain(ctx, domain)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return ErrCustomDomainTaken
+	}
+	return nil
+}
+
+// DomainMiddleware resolves requests arriving on a custom domain to the
+// status page served there. Requests on the dashboard host pass through, so
+// one server can answer the API and any number of status page domains.
+type DomainMiddleware struct {
+	service Service
+	apiHost string
+	logger  *zap.SugaredLogger
+}
+
+func NewDomainMiddleware(service Service, cfg *config.Config, logger *zap.SugaredLogger) *DomainMiddleware {
+	return &DomainMiddleware{
+		service: service,
+		apiHost: clientHost(cfg),
+		logger:  logger.Named("[status-page-domain]"),
+	}
+}
+
+// Resolve stores the page of the Host header in the context. On a custom
+// domain, routes addressing a page by slug only serve that domain's page.
+func (m *DomainMiddleware) Resolve() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		host := NormalizeDomain(ctx.Request.Host)
+		if m.isAPIHost(host) {
+			ctx.Next()
+			return
+		}
+
+		page, err := m.service.FindByDomain(ctx, host)
+		if err != nil {
+			m.logger.Errorw("Failed to find status page by domain", "error", err, "host", host)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		if page == nil {
+			ctx.Next()
+			return
+		}
+
+		if slug := ctx.Param("slug"); slug != "" && slug != page.Slug {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+			return
+		}
+		ctx.Set(domainPageKey, page)
+		ctx.Next()
+	}
+}
+
+func (m *DomainMiddleware) isAPIHost(host string) bool {
+	return host == "" || host == m.apiHost || host == "localhost" || net.ParseIP(host) != nil
+}
+
+// DomainPage returns the page resolved by DomainMiddleware, nil when the
+// request did not arrive on a custom domain
+func DomainPage(ctx *gin.Context) *Model {
+	if value, ok := ctx.Get(domainPageKey); ok {
+		if page, ok := value.(*Model); ok {
+			return page
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package status_page
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newDomainTestService() Service {
+	cfg := &config.Config{ClientURL: "https://peekaping.example.com"}
+	return NewService(&memoryRepository{pages: map[string]*Model{}}, nil, nil, cfg, zap.NewNop().Sugar())
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	assert.Equal(t, "status.acme.example", NormalizeDomain("Status.Acme.Example"))
+	assert.Equal(t, "status.acme.example", NormalizeDomain("status.acme.example:8443"))
+	assert.Equal(t, "status.acme.example", NormalizeDomain(" status.acme.example. "))
+	assert.Equal(t, "", NormalizeDomain(""))
+}
+
+func TestService_CustomDomainValidation(t *testing.T) {
+	ctx := context.Background()
+	service := newDomainTestService()
+
+	created, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "acme", Title: "Acme", CustomDomain: "Status.Acme.Example"})
+	require.NoError(t, err)
+	assert.Equal(t, "status.acme.example", created.CustomDomain)
+
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "other", Title: "Other", CustomDomain: "status.acme.example"})
+	assert.ErrorIs(t, err, ErrCustomDomainTaken)
+
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "bad", Title: "Bad", CustomDomain: "not a domain"})
+	assert.ErrorIs(t, err, ErrInvalidCustomDomain)
+
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "api", Title: "API", CustomDomain: "peekaping.example.com"})
+	assert.ErrorIs(t, err, ErrInvalidCustomDomain)
+
+	// saving a page keeps its own domain
+	same := "status.acme.example"
+	_, err = service.Update(ctx, created.ID, &UpdateStatusPageDTO{CustomDomain: &same})
+	assert.NoError(t, err)
+
+	other, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "other", Title: "Other"})
+	require.NoError(t, err)
+	_, err = service.Update(ctx, other.ID, &UpdateStatusPageDTO{CustomDomain: &same})
+	assert.ErrorIs(t, err, ErrCustomDomainTaken)
+
+	cleared := ""
+	_, err = service.Update(ctx, created.ID, &UpdateStatusPageDTO{CustomDomain: &cleared})
+	require.NoError(t, err)
+	_, err = service.Update(ctx, other.ID, &UpdateStatusPageDTO{CustomDomain: &same})
+	assert.NoError(t, err)
+}
+
+func TestDomainMiddleware_Resolve(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	service := newDomainTestService()
+	_, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "acme", Title: "Acme", CustomDomain: "status.acme.example"})
+	require.NoError(t, err)
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "internal", Title: "Internal"})
+	require.NoError(t, err)
+
+	middleware := NewDomainMiddleware(service, &config.Config{ClientURL: "https://peekaping.example.com"}, zap.NewNop().Sugar())
+	router := gin.New()
+	public := router.Group("/status-pages", middleware.Resolve())
+	public.GET("/domain", func(c *gin.Context) {
+		if page := DomainPage(c); page != nil {
+			c.String(http.StatusOK, page.Slug)
+			return
+		}
+		c.Status(http.StatusNotFound)
+	})
+	public.GET("/slug/:slug", func(c *gin.Context) { c.String(http.StatusOK, c.Param("slug")) })
+
+	request := func(host, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("Status.Acme.Example:443", "/status-pages/domain")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme", rec.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, request("peekaping.example.com", "/status-pages/domain").Code)
+	assert.Equal(t, http.StatusNotFound, request("unknown.example", "/status-pages/domain").Code)
+
+	// the dashboard host serves every page, a custom domain only its own
+	assert.Equal(t, http.StatusOK, request("peekaping.example.com", "/status-pages/slug/internal").Code)
+	assert.Equal(t, http.StatusOK, request("status.acme.example", "/status-pages/slug/acme").Code)
+	assert.Equal(t, http.StatusNotFound, request("status.acme.example", "/status-pages/slug/internal").Code)
+}
+
+func TestController_FindByDomain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	service := newDomainTestService()
+	_, err := service.Create(ctx, &CreateStatusPageDTO{
+		Slug: "acme", Title: "Acme internal", Description: "Private", CustomDomain: "status.acme.example", Password: "page-secret",
+	})
+	require.NoError(t, err)
+
+	middleware := NewDomainMiddleware(service, &config.Config{ClientURL: "https://peekaping.example.com"}, zap.NewNop().Sugar())
+	controller := &Controller{service: service, logger: zap.NewNop().Sugar()}
+	router := gin.New()
+	router.Group("/status-pages", middleware.Resolve()).GET("/domain", controller.FindByDomain)
+
+	req := httptest.NewRequest(http.MethodGet, "/status-pages/domain", nil)
+	req.Host = "status.acme.example"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.JSONEq(t, `{"slug": "acme", "password_protected": true}`, string(body.Data))
+	assert.NotContains(t, rec.Body.String(), "Acme internal", "expected the page to stay behind its password")
+}
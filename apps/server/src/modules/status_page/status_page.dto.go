@@ -14,14 +14,20 @@ type CreateStatusPageDTO struct {
 	Published             bool     `json:"published"`
 	SearchEngineIndex     bool     `json:"search_engine_index"`
 	ShowTags              bool     `json:"show_tags"`
-	Password              string   `json:"password,omitempty"`
+	Password              string   `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
 	FooterText            string   `json:"footer_text"`
-	CustomCSS             string   `json:"custom_css"`
+	CustomCSS             string   `json:"custom_css" validate:"max=20000"`
+	LogoURL               string   `json:"logo_url" validate:"omitempty,http_url"`
+	PrimaryColor          string   `json:"primary_color" validate:"omitempty,hexcolor"`
 	ShowPoweredBy         bool     `json:"show_powered_by"`
 	GoogleAnalyticsTagID  string   `json:"google_analytics_tag_id"`
 	ShowCertificateExpiry bool     `json:"show_certificate_expiry"`
 	AutoRefreshInterval   int      `json:"auto_refresh_interval"`
 	MonitorIDs            []string `json:"monitor_ids,omitempty"`
+	NotifyIncidents       bool     `json:"notify_incidents"`
+	NotificationIDs       []string `json:"notification_ids" validate:"omitempty,dive,required"`
+	SubscriberChannelID   string   `json:"subscriber_channel_id"`
+	CustomDomain          string   `json:"custom_domain" validate:"max=253"`
 }
 
 type UpdateStatusPageDTO struct {
@@ -33,14 +39,20 @@ type UpdateStatusPageDTO struct {
 	Published             *bool     `json:"published,omitempty"`
 	SearchEngineIndex     *bool     `json:"search_engine_index,omitempty"`
 	ShowTags              *bool     `json:"show_tags,omitempty"`
-	Password              *string   `json:"password,omitempty"`
+	Password              *string   `json:"password,omitempty" validate:"omitempty,eq=|min=8,max=72"`
 	FooterText            *string   `json:"footer_text,omitempty"`
-	CustomCSS             *string   `json:"custom_css,omitempty"`
+	CustomCSS             *string   `json:"custom_css,omitempty" validate:"omitempty,max=20000"`
+	LogoURL               *string   `json:"logo_url,omitempty" validate:"omitempty,http_url"`
+	PrimaryColor          *string   `json:"primary_color,omitempty" validate:"omitempty,hexcolor"`
 	ShowPoweredBy         *bool     `json:"show_powered_by,omitempty"`
 	GoogleAnalyticsTagID  *string   `json:"google_analytics_tag_id,omitempty"`
 	ShowCertificateExpiry *bool     `json:"show_certificate_expiry,omitempty"`
 	AutoRefreshInterval   *int      `json:"auto_refresh_interval,omitempty"`
 	MonitorIDs            *[]string `json:"monitor_ids,omitempty"`
+	NotifyIncidents       *bool     `json:"notify_incidents,omitempty"`
+	NotificationIDs       *[]string `json:"notification_ids,omitempty" validate:"omitempty,dive,required"`
+	SubscriberChannelID   *string   `json:"subscriber_channel_id,omitempty"`
+	CustomDomain          *string   `json:"custom_domain,omitempty" validate:"omitempty,max=253"`
 }
 
 type StatusPageWithMonitorsResponseDTO struct {
@@ -58,11 +70,34 @@ type StatusPageWithMonitorsResponseDTO struct {
 	UpdatedAt             time.Time `json:"updated_at"`
 	FooterText            string    `json:"footer_text"`
 	CustomCSS             string    `json:"custom_css"`
+	LogoURL               string    `json:"logo_url"`
+	PrimaryColor          string    `json:"primary_color"`
 	ShowPoweredBy         bool      `json:"show_powered_by"`
 	GoogleAnalyticsTagID  string    `json:"google_analytics_tag_id"`
 	ShowCertificateExpiry bool      `json:"show_certificate_expiry"`
 	AutoRefreshInterval   int       `json:"auto_refresh_interval"`
 	MonitorIDs            []string  `json:"monitor_ids"`
+	NotifyIncidents       bool      `json:"notify_incidents"`
+	NotificationIDs       []string  `json:"notification_ids"`
+	SubscriberChannelID   string    `json:"subscriber_channel_id"`
+	CustomDomain          string    `json:"custom_domain"`
+	PasswordProtected     bool      `json:"password_protected"`
+}
+
+type UnlockStatusPageDTO struct {
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// DomainPageDTO names the page served on a custom domain, the page itself is
+// read from the slug endpoints which check its password
+type DomainPageDTO struct {
+	Slug              string `json:"slug"`
+	PasswordProtected bool   `json:"password_protected"`
+}
+
+type AccessTokenDTO struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type PublicMonitorDTO struct {
@@ -12,18 +12,42 @@ type Model struct {
 	Published           bool   `json:"published" bson:"published"`
 	FooterText          string `json:"footer_text" bson:"footer_text"`
 	AutoRefreshInterval int    `json:"auto_refresh_interval" bson:"auto_refresh_interval"`
+	LogoURL             string `json:"logo_url" bson:"logo_url"`
+	PrimaryColor        string `json:"primary_color" bson:"primary_color"`
+	CustomCSS           string `json:"custom_css" bson:"custom_css"`
+	// NotifyIncidents sends the status changes of the monitors on the page
+	// through NotificationIDs too
+	NotifyIncidents bool     `json:"notify_incidents" bson:"notify_incidents"`
+	NotificationIDs []string `json:"notification_ids" bson:"notification_ids"`
+	// SubscriberChannelID is the smtp notification channel that emails the
+	// subscribers of the page, subscribing is disabled without it
+	SubscriberChannelID string `json:"subscriber_channel_id" bson:"subscriber_channel_id"`
+	// CustomDomain serves the page on its own domain, lowercase without port
+	CustomDomain string `json:"custom_domain" bson:"custom_domain"`
+	// PasswordHash gates the public page when set, hashed like user passwords
+	PasswordHash string `json:"-" bson:"password_hash"`
+	// PasswordProtected is derived from PasswordHash by the repositories
+	PasswordProtected bool `json:"password_protected" bson:"-"`
 
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 type UpdateModel struct {
-	Slug                *string `json:"slug,omitempty" bson:"slug,omitempty"`
-	Title               *string `json:"title,omitempty" bson:"title,omitempty"`
-	Description         *string `json:"description,omitempty" bson:"description,omitempty"`
-	Icon                *string `json:"icon,omitempty" bson:"icon,omitempty"`
-	Theme               *string `json:"theme,omitempty" bson:"theme,omitempty"`
-	Published           *bool   `json:"published,omitempty" bson:"published,omitempty"`
-	FooterText          *string `json:"footer_text,omitempty" bson:"footer_text,omitempty"`
-	AutoRefreshInterval *int    `json:"auto_refresh_interval,omitempty" bson:"auto_refresh_interval,omitempty"`
+	Slug                *string   `json:"slug,omitempty" bson:"slug,omitempty"`
+	Title               *string   `json:"title,omitempty" bson:"title,omitempty"`
+	Description         *string   `json:"description,omitempty" bson:"description,omitempty"`
+	Icon                *string   `json:"icon,omitempty" bson:"icon,omitempty"`
+	Theme               *string   `json:"theme,omitempty" bson:"theme,omitempty"`
+	Published           *bool     `json:"published,omitempty" bson:"published,omitempty"`
+	FooterText          *string   `json:"footer_text,omitempty" bson:"footer_text,omitempty"`
+	AutoRefreshInterval *int      `json:"auto_refresh_interval,omitempty" bson:"auto_refresh_interval,omitempty"`
+	LogoURL             *string   `json:"logo_url,omitempty" bson:"logo_url,omitempty"`
+	PrimaryColor        *string   `json:"primary_color,omitempty" bson:"primary_color,omitempty"`
+	CustomCSS           *string   `json:"custom_css,omitempty" bson:"custom_css,omitempty"`
+	NotifyIncidents     *bool     `json:"notify_incidents,omitempty" bson:"notify_incidents,omitempty"`
+	NotificationIDs     *[]string `json:"notification_ids,omitempty" bson:"notification_ids,omitempty"`
+	SubscriberChannelID *string   `json:"subscriber_channel_id,omitempty" bson:"subscriber_channel_id,omitempty"`
+	CustomDomain        *string   `json:"custom_domain,omitempty" bson:"custom_domain,omitempty"`
+	PasswordHash        *string   `json:"-" bson:"password_hash,omitempty"`
 }
@@ -25,6 +25,14 @@ type mongoModel struct {
 	FooterText           string             `bson:"footer_text"`
 	GoogleAnalyticsTagID string             `bson:"google_analytics_tag_id"`
 	AutoRefreshInterval  int                `bson:"auto_refresh_interval"`
+	LogoURL              string             `bson:"logo_url"`
+	PrimaryColor         string             `bson:"primary_color"`
+	CustomCSS            string             `bson:"custom_css"`
+	NotifyIncidents      bool               `bson:"notify_incidents"`
+	NotificationIDs      []string           `bson:"notification_ids"`
+	SubscriberChannelID  string             `bson:"subscriber_channel_id"`
+	CustomDomain         string             `bson:"custom_domain"`
+	PasswordHash         string             `bson:"password_hash"`
 
 	CreatedAt time.Time `bson:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at"`
@@ -41,6 +49,15 @@ func toDomainModel(m *mongoModel) *Model {
 		Published:           m.Published,
 		FooterText:          m.FooterText,
 		AutoRefreshInterval: m.AutoRefreshInterval,
+		LogoURL:             m.LogoURL,
+		PrimaryColor:        m.PrimaryColor,
+		CustomCSS:           m.CustomCSS,
+		NotifyIncidents:     m.NotifyIncidents,
+		NotificationIDs:     m.NotificationIDs,
+		SubscriberChannelID: m.SubscriberChannelID,
+		CustomDomain:        m.CustomDomain,
+		PasswordHash:        m.PasswordHash,
+		PasswordProtected:   m.PasswordHash != "",
 
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
@@ -66,6 +83,16 @@ func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
 		if err != nil {
 			// Handle error appropriately, e.g., log it
 		}
+		// pages without a custom domain store an empty string
+		_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.D{{Key: "custom_domain", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"custom_domain": bson.M{"$gt": ""},
+			}),
+		})
+		if err != nil {
+			// Handle error appropriately, e.g., log it
+		}
 	}()
 
 	return &MongoRepository{
@@ -88,6 +115,14 @@ func (r *MongoRepository) Create(ctx context.Context, statusPage *Model) (*Model
 		UpdatedAt:           time.Now().UTC(),
 		FooterText:          statusPage.FooterText,
 		AutoRefreshInterval: statusPage.AutoRefreshInterval,
+		LogoURL:             statusPage.LogoURL,
+		PrimaryColor:        statusPage.PrimaryColor,
+		CustomCSS:           statusPage.CustomCSS,
+		NotifyIncidents:     statusPage.NotifyIncidents,
+		NotificationIDs:     statusPage.NotificationIDs,
+		SubscriberChannelID: statusPage.SubscriberChannelID,
+		CustomDomain:        statusPage.CustomDomain,
+		PasswordHash:        statusPage.PasswordHash,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mm)
@@ -127,6 +162,18 @@ func (r *MongoRepository) FindBySlug(ctx context.Context, slug string) (*Model,
 	return toDomainModel(&mm), nil
 }
 
+func (r *MongoRepository) FindByDomain(ctx context.Context, domain string) (*Model, error) {
+	var mm mongoModel
+	err := r.collection.FindOne(ctx, bson.M{"custom_domain": domain}).Decode(&mm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModel(&mm), nil
+}
+
 func (r *MongoRepository) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
 	skip := int64(page * limit)
 	limit64 := int64(limit)
@@ -193,6 +240,30 @@ func (r *MongoRepository) Update(ctx context.Context, id string, statusPage *Upd
 	if statusPage.AutoRefreshInterval != nil {
 		updatePayload["auto_refresh_interval"] = *statusPage.AutoRefreshInterval
 	}
+	if statusPage.LogoURL != nil {
+		updatePayload["logo_url"] = *statusPage.LogoURL
+	}
+	if statusPage.PrimaryColor != nil {
+		updatePayload["primary_color"] = *statusPage.PrimaryColor
+	}
+	if statusPage.CustomCSS != nil {
+		updatePayload["custom_css"] = *statusPage.CustomCSS
+	}
+	if statusPage.NotifyIncidents != nil {
+		updatePayload["notify_incidents"] = *statusPage.NotifyIncidents
+	}
+	if statusPage.NotificationIDs != nil {
+		updatePayload["notification_ids"] = *statusPage.NotificationIDs
+	}
+	if statusPage.SubscriberChannelID != nil {
+		updatePayload["subscriber_channel_id"] = *statusPage.SubscriberChannelID
+	}
+	if statusPage.CustomDomain != nil {
+		updatePayload["custom_domain"] = *statusPage.CustomDomain
+	}
+	if statusPage.PasswordHash != nil {
+		updatePayload["password_hash"] = *statusPage.PasswordHash
+	}
 
 	if len(updatePayload) == 0 {
 		return nil // nothing to update
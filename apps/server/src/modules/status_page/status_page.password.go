@@ -0,0 +1,143 @@
+package status_page
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"peekaping/src/modules/auth"
+	"peekaping/src/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrStatusPageNotFound   = errors.New("status page not found")
+	ErrNotPasswordProtected = errors.New("status page is not password protected")
+	ErrIncorrectPassword    = errors.New("incorrect password")
+)
+
+// accessTokenDuration is how long an unlocked page stays accessible
+const accessTokenDuration = 24 * time.Hour
+
+// accessClaims grant access to one password protected page. Version is
+// derived from the password hash, so changing the password revokes them.
+type accessClaims struct {
+	PageID  string `json:"page_id"`
+	Version string `json:"ver"`
+	jwt.RegisteredClaims
+}
+
+func passwordVersion(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// accessSecret keeps page tokens from being accepted as user tokens and back
+func (s *ServiceImpl) accessSecret() []byte {
+	return []byte("status-page:" + s.cfg.AccessTokenSecretKey)
+}
+
+func (s *ServiceImpl) Unlock(ctx context.Context, slug, password string) (*AccessTokenDTO, error) {
+	page, err := s.repository.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return nil, ErrStatusPageNotFound
+	}
+	if page.PasswordHash == "" {
+		return nil, ErrNotPasswordProtected
+	}
+	if auth.ComparePassword(page.PasswordHash, password) != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(accessTokenDuration)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &accessClaims{
+		PageID:  page.ID,
+		Version: passwordVersion(page.PasswordHash),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	signed, err := token.SignedString(s.accessSecret())
+	if err != nil {
+		return nil, err
+	}
+	return &AccessTokenDTO{Token: signed, ExpiresAt: expiresAt}, nil
+}
+
+func (s *ServiceImpl) CanAccess(page *Model, token string) bool {
+	if page.PasswordHash == "" {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+
+	claims := &accessClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, auth.ErrInvalidToken
+		}
+		return s.accessSecret(), nil
+	})
+	if err != nil || !parsed.Valid {
+		return false
+	}
+	return claims.PageID == page.ID && claims.Version == passwordVersion(page.PasswordHash)
+}
+
+// PasswordMiddleware answers 401 on the public routes of a password
+// protected page until the visitor presents the token returned on unlock
+type PasswordMiddleware struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewPasswordMiddleware(service Service, logger *zap.SugaredLogger) *PasswordMiddleware {
+	return &PasswordMiddleware{
+		service: service,
+		logger:  logger.Named("[status-page-password]"),
+	}
+}
+
+// Protect gates routes addressing a page by its slug
+func (m *PasswordMiddleware) Protect() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		slug := ctx.Param("slug")
+		if slug == "" {
+			ctx.Next()
+			return
+		}
+
+		page, err := m.service.FindBySlug(ctx, slug)
+		if err != nil {
+			m.logger.Errorw("Failed to get status page by slug", "error", err, "slug", slug)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		// unknown pages are answered by the handlers
+		if page == nil || m.service.CanAccess(page, bearerToken(ctx)) {
+			ctx.Next()
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.NewFailResponse("Status page is password protected"))
+	}
+}
+
+func bearerToken(ctx *gin.Context) string {
+	fields := strings.Fields(ctx.GetHeader("Authorization"))
+	if len(fields) == 2 && fields[0] == "Bearer" {
+		return fields[1]
+	}
+	return ""
+}
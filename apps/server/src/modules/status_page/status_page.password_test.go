@@ -0,0 +1,103 @@
+package status_page
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/config"
+	"peekaping/src/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newPasswordTestService() Service {
+	cfg := &config.Config{ClientURL: "https://peekaping.example.com", AccessTokenSecretKey: "0123456789abcdef"}
+	return NewService(&memoryRepository{pages: map[string]*Model{}}, nil, nil, cfg, zap.NewNop().Sugar())
+}
+
+func TestService_UnlockPasswordProtectedPage(t *testing.T) {
+	ctx := context.Background()
+	service := newPasswordTestService()
+
+	created, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "private", Title: "Private", Password: "correct horse"})
+	require.NoError(t, err)
+	assert.True(t, created.PasswordProtected)
+	assert.NotEqual(t, "correct horse", created.PasswordHash)
+
+	_, err = service.Unlock(ctx, "private", "wrong password")
+	assert.ErrorIs(t, err, ErrIncorrectPassword)
+	_, err = service.Unlock(ctx, "missing", "correct horse")
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+
+	access, err := service.Unlock(ctx, "private", "correct horse")
+	require.NoError(t, err)
+	page, _ := service.FindBySlug(ctx, "private")
+	assert.True(t, service.CanAccess(page, access.Token))
+	assert.False(t, service.CanAccess(page, ""))
+	assert.False(t, service.CanAccess(page, "not-a-token"))
+
+	// a token of one page does not open another
+	other, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "other", Title: "Other", Password: "correct horse"})
+	require.NoError(t, err)
+	assert.False(t, service.CanAccess(other, access.Token))
+
+	// changing the password revokes issued tokens
+	changed := "battery staple"
+	_, err = service.Update(ctx, created.ID, &UpdateStatusPageDTO{Password: &changed})
+	require.NoError(t, err)
+	page, _ = service.FindBySlug(ctx, "private")
+	assert.False(t, service.CanAccess(page, access.Token))
+
+	removed := ""
+	_, err = service.Update(ctx, created.ID, &UpdateStatusPageDTO{Password: &removed})
+	require.NoError(t, err)
+	page, _ = service.FindBySlug(ctx, "private")
+	assert.False(t, page.PasswordProtected)
+	assert.True(t, service.CanAccess(page, ""))
+	_, err = service.Unlock(ctx, "private", "battery staple")
+	assert.ErrorIs(t, err, ErrNotPasswordProtected)
+}
+
+func TestPasswordMiddleware_Protect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	service := newPasswordTestService()
+	_, err := service.Create(ctx, &CreateStatusPageDTO{Slug: "private", Title: "Private", Password: "correct horse"})
+	require.NoError(t, err)
+	_, err = service.Create(ctx, &CreateStatusPageDTO{Slug: "public", Title: "Public"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	protected := router.Group("/status-pages", NewPasswordMiddleware(service, zap.NewNop().Sugar()).Protect())
+	protected.GET("/slug/:slug", func(c *gin.Context) { c.String(http.StatusOK, c.Param("slug")) })
+
+	request := func(slug, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/status-pages/slug/"+slug, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("public", ""))
+	assert.Equal(t, http.StatusOK, request("missing", ""))
+	assert.Equal(t, http.StatusUnauthorized, request("private", ""))
+	assert.Equal(t, http.StatusUnauthorized, request("private", "forged"))
+
+	access, err := service.Unlock(ctx, "private", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request("private", access.Token))
+}
+
+func TestUpdateStatusPageDTO_PasswordValidation(t *testing.T) {
+	removed, short, valid := "", "short", "long enough"
+	assert.NoError(t, utils.Validate.Struct(&UpdateStatusPageDTO{Password: &removed}))
+	assert.Error(t, utils.Validate.Struct(&UpdateStatusPageDTO{Password: &short}))
+	assert.NoError(t, utils.Validate.Struct(&UpdateStatusPageDTO{Password: &valid}))
+}
@@ -9,21 +9,37 @@ import (
 type Route struct {
 	controller *Controller
 	middleware *auth.MiddlewareProvider
+	domains    *DomainMiddleware
+	passwords  *PasswordMiddleware
 }
 
-func NewRoute(controller *Controller, middleware *auth.MiddlewareProvider) *Route {
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+	domains *DomainMiddleware,
+	passwords *PasswordMiddleware,
+) *Route {
 	return &Route{
 		controller: controller,
 		middleware: middleware,
+		domains:    domains,
+		passwords:  passwords,
 	}
 }
 
 func (r *Route) ConnectRoute(rg *gin.RouterGroup, controller *Controller) {
 	// Public routes
 	sp := rg.Group("status-pages")
-	sp.GET("/slug/:slug", r.controller.FindBySlug)
-	sp.GET("/slug/:slug/monitors", r.controller.GetMonitorsBySlug)
-	sp.GET("/slug/:slug/monitors/homepage", r.controller.GetMonitorsBySlugForHomepage)
+	public := sp.Group("", r.domains.Resolve())
+	public.GET("/domain", r.controller.FindByDomain)
+	public.POST("/slug/:slug/unlock", r.controller.Unlock)
+	// the slug takes the place of :id, gin needs one wildcard name per segment
+	public.GET("/:id/badge/:monitor", r.controller.Badge)
+
+	protected := public.Group("", r.passwords.Protect())
+	protected.GET("/slug/:slug", r.controller.FindBySlug)
+	protected.GET("/slug/:slug/monitors", r.controller.GetMonitorsBySlug)
+	protected.GET("/slug/:slug/monitors/homepage", r.controller.GetMonitorsBySlugForHomepage)
 
 	sp.Use(r.middleware.Auth())
 	{
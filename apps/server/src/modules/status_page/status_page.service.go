@@ -2,6 +2,8 @@ package status_page
 
 import (
 	"context"
+	"peekaping/src/config"
+	"peekaping/src/modules/auth"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/monitor_status_page"
 
@@ -13,6 +15,11 @@ type Service interface {
 	FindByID(ctx context.Context, id string) (*Model, error)
 	FindByIDWithMonitors(ctx context.Context, id string) (*StatusPageWithMonitorsResponseDTO, error)
 	FindBySlug(ctx context.Context, slug string) (*Model, error)
+	FindByDomain(ctx context.Context, domain string) (*Model, error)
+	// Unlock returns an access token for a password protected page
+	Unlock(ctx context.Context, slug, password string) (*AccessTokenDTO, error)
+	// CanAccess tells whether the token grants access to the page
+	CanAccess(page *Model, token string) bool
 	FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error)
 	Update(ctx context.Context, id string, dto *UpdateStatusPageDTO) (*Model, error)
 	Delete(ctx context.Context, id string) error
@@ -29,6 +36,7 @@ type ServiceImpl struct {
 	repository               Repository
 	eventBus                 *events.EventBus
 	monitorStatusPageService monitor_status_page.Service
+	cfg                      *config.Config
 	logger                   *zap.SugaredLogger
 }
 
@@ -36,12 +44,14 @@ func NewService(
 	repository Repository,
 	eventBus *events.EventBus,
 	monitorStatusPageService monitor_status_page.Service,
+	cfg *config.Config,
 	logger *zap.SugaredLogger,
 ) Service {
 	return &ServiceImpl{
 		repository:               repository,
 		eventBus:                 eventBus,
 		monitorStatusPageService: monitorStatusPageService,
+		cfg:                      cfg,
 		logger:                   logger.Named("[status-page-service]"),
 	}
 }
@@ -56,6 +66,23 @@ func (s *ServiceImpl) Create(ctx context.Context, dto *CreateStatusPageDTO) (*Mo
 		Published:           dto.Published,
 		FooterText:          dto.FooterText,
 		AutoRefreshInterval: dto.AutoRefreshInterval,
+		LogoURL:             dto.LogoURL,
+		PrimaryColor:        dto.PrimaryColor,
+		CustomCSS:           SanitizeCustomCSS(dto.CustomCSS),
+		NotifyIncidents:     dto.NotifyIncidents,
+		NotificationIDs:     dto.NotificationIDs,
+		SubscriberChannelID: dto.SubscriberChannelID,
+		CustomDomain:        NormalizeDomain(dto.CustomDomain),
+	}
+	if err := s.checkCustomDomain(ctx, model.CustomDomain, ""); err != nil {
+		return nil, err
+	}
+	if dto.Password != "" {
+		hash, err := auth.HashPassword(dto.Password)
+		if err != nil {
+			return nil, err
+		}
+		model.PasswordHash = hash
 	}
 
 	created, err := s.repository.Create(ctx, model)
@@ -127,6 +154,14 @@ func (s *ServiceImpl) FindBySlug(ctx context.Context, slug string) (*Model, erro
 	return s.repository.FindBySlug(ctx, slug)
 }
 
+func (s *ServiceImpl) FindByDomain(ctx context.Context, domain string) (*Model, error) {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil, nil
+	}
+	return s.repository.FindByDomain(ctx, domain)
+}
+
 func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
 	return s.repository.FindAll(ctx, page, limit, q)
 }
@@ -141,6 +176,32 @@ func (s *ServiceImpl) Update(ctx context.Context, id string, dto *UpdateStatusPa
 		Published:           dto.Published,
 		FooterText:          dto.FooterText,
 		AutoRefreshInterval: dto.AutoRefreshInterval,
+		LogoURL:             dto.LogoURL,
+		PrimaryColor:        dto.PrimaryColor,
+		NotifyIncidents:     dto.NotifyIncidents,
+		NotificationIDs:     dto.NotificationIDs,
+		SubscriberChannelID: dto.SubscriberChannelID,
+	}
+	if dto.CustomCSS != nil {
+		css := SanitizeCustomCSS(*dto.CustomCSS)
+		updateModel.CustomCSS = &css
+	}
+	if dto.CustomDomain != nil {
+		domain := NormalizeDomain(*dto.CustomDomain)
+		if err := s.checkCustomDomain(ctx, domain, id); err != nil {
+			return nil, err
+		}
+		updateModel.CustomDomain = &domain
+	}
+	if dto.Password != nil {
+		hash := ""
+		if *dto.Password != "" {
+			var err error
+			if hash, err = auth.HashPassword(*dto.Password); err != nil {
+				return nil, err
+			}
+		}
+		updateModel.PasswordHash = &hash
 	}
 
 	err := s.repository.Update(ctx, id, updateModel)
@@ -243,6 +304,14 @@ func (s *ServiceImpl) mapModelToStatusPageWithMonitorsDTO(model *Model, monitorI
 		UpdatedAt:           model.UpdatedAt,
 		FooterText:          model.FooterText,
 		AutoRefreshInterval: model.AutoRefreshInterval,
+		LogoURL:             model.LogoURL,
+		PrimaryColor:        model.PrimaryColor,
+		CustomCSS:           model.CustomCSS,
 		MonitorIDs:          monitorIDs,
+		NotifyIncidents:     model.NotifyIncidents,
+		NotificationIDs:     model.NotificationIDs,
+		SubscriberChannelID: model.SubscriberChannelID,
+		CustomDomain:        model.CustomDomain,
+		PasswordProtected:   model.PasswordProtected,
 	}
 }
@@ -0,0 +1,123 @@
+package status_page
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository keeps status pages in memory
+type memoryRepository struct {
+	Repository
+	pages map[string]*Model
+}
+
+func (r *memoryRepository) Create(ctx context.Context, statusPage *Model) (*Model, error) {
+	created := *statusPage
+	created.ID = fmt.Sprintf("page%d", len(r.pages)+1)
+	created.PasswordProtected = created.PasswordHash != ""
+	r.pages[created.ID] = &created
+	return &created, nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Model, error) {
+	if page, ok := r.pages[id]; ok {
+		found := *page
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindBySlug(ctx context.Context, slug string) (*Model, error) {
+	for _, page := range r.pages {
+		if page.Slug == slug {
+			found := *page
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByDomain(ctx context.Context, domain string) (*Model, error) {
+	for _, page := range r.pages {
+		if page.CustomDomain == domain {
+			found := *page
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, id string, statusPage *UpdateModel) error {
+	page, ok := r.pages[id]
+	if !ok {
+		return nil
+	}
+	if statusPage.LogoURL != nil {
+		page.LogoURL = *statusPage.LogoURL
+	}
+	if statusPage.PrimaryColor != nil {
+		page.PrimaryColor = *statusPage.PrimaryColor
+	}
+	if statusPage.CustomCSS != nil {
+		page.CustomCSS = *statusPage.CustomCSS
+	}
+	if statusPage.CustomDomain != nil {
+		page.CustomDomain = *statusPage.CustomDomain
+	}
+	if statusPage.PasswordHash != nil {
+		page.PasswordHash = *statusPage.PasswordHash
+		page.PasswordProtected = page.PasswordHash != ""
+	}
+	return nil
+}
+
+func TestService_ThemeFieldsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(&memoryRepository{pages: map[string]*Model{}}, nil, nil, nil, zap.NewNop().Sugar())
+
+	created, err := service.Create(ctx, &CreateStatusPageDTO{
+		Slug:         "status",
+		Title:        "Acme status",
+		LogoURL:      "https://cdn.acme.example/logo.svg",
+		PrimaryColor: "#ff6600",
+		CustomCSS:    "@import url(https://fonts.example.com/x.css); .header { color: #ff6600; }",
+	})
+	require.NoError(t, err)
+
+	public, err := service.FindBySlug(ctx, "status")
+	require.NoError(t, err)
+	require.NotNil(t, public)
+	assert.Equal(t, "https://cdn.acme.example/logo.svg", public.LogoURL)
+	assert.Equal(t, "#ff6600", public.PrimaryColor)
+	assert.Equal(t, ".header { color: #ff6600; }", public.CustomCSS)
+
+	color := "#123456"
+	css := ".header { background: url(https://tracker.example.com/p.gif) #123456; }"
+	_, err = service.Update(ctx, created.ID, &UpdateStatusPageDTO{PrimaryColor: &color, CustomCSS: &css})
+	require.NoError(t, err)
+
+	public, err = service.FindBySlug(ctx, "status")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.acme.example/logo.svg", public.LogoURL)
+	assert.Equal(t, "#123456", public.PrimaryColor)
+	assert.Equal(t, ".header { background: none #123456; }", public.CustomCSS)
+}
+
+func TestCreateStatusPageDTO_ThemeValidation(t *testing.T) {
+	valid := CreateStatusPageDTO{Slug: "status", Title: "Acme status", LogoURL: "https://cdn.acme.example/logo.png", PrimaryColor: "#f60"}
+	assert.NoError(t, utils.Validate.Struct(&valid))
+
+	invalidColor := valid
+	invalidColor.PrimaryColor = "orange; background: url(x)"
+	assert.Error(t, utils.Validate.Struct(&invalidColor))
+
+	scriptLogo := valid
+	scriptLogo.LogoURL = "javascript:alert(1)"
+	assert.Error(t, utils.Validate.Struct(&scriptLogo))
+}
@@ -2,6 +2,7 @@ package status_page
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +23,16 @@ type sqlModel struct {
 	UpdatedAt           time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 	FooterText          string    `bun:"footer_text"`
 	AutoRefreshInterval int       `bun:"auto_refresh_interval,notnull,default:30"`
+	LogoURL             string    `bun:"logo_url"`
+	PrimaryColor        string    `bun:"primary_color"`
+	CustomCSS           string    `bun:"custom_css"`
+	NotifyIncidents     bool      `bun:"notify_incidents,notnull,default:false"`
+	// JSON array of notification channel IDs
+	NotificationIDs     string `bun:"notification_ids,notnull,default:'[]'"`
+	SubscriberChannelID string `bun:"subscriber_channel_id,notnull,default:''"`
+	// NULL without a custom domain so the unique index allows many such pages
+	CustomDomain string `bun:"custom_domain,nullzero,unique"`
+	PasswordHash string `bun:"password_hash,notnull,default:''"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -37,6 +48,15 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		UpdatedAt:           sm.UpdatedAt,
 		FooterText:          sm.FooterText,
 		AutoRefreshInterval: sm.AutoRefreshInterval,
+		LogoURL:             sm.LogoURL,
+		PrimaryColor:        sm.PrimaryColor,
+		CustomCSS:           sm.CustomCSS,
+		NotifyIncidents:     sm.NotifyIncidents,
+		NotificationIDs:     decodeNotificationIDs(sm.NotificationIDs),
+		SubscriberChannelID: sm.SubscriberChannelID,
+		CustomDomain:        sm.CustomDomain,
+		PasswordHash:        sm.PasswordHash,
+		PasswordProtected:   sm.PasswordHash != "",
 	}
 }
 
@@ -53,9 +73,33 @@ func toSQLModel(m *Model) *sqlModel {
 		UpdatedAt:           m.UpdatedAt,
 		FooterText:          m.FooterText,
 		AutoRefreshInterval: m.AutoRefreshInterval,
+		LogoURL:             m.LogoURL,
+		PrimaryColor:        m.PrimaryColor,
+		CustomCSS:           m.CustomCSS,
+		NotifyIncidents:     m.NotifyIncidents,
+		NotificationIDs:     encodeNotificationIDs(m.NotificationIDs),
+		SubscriberChannelID: m.SubscriberChannelID,
+		CustomDomain:        m.CustomDomain,
+		PasswordHash:        m.PasswordHash,
 	}
 }
 
+func encodeNotificationIDs(ids []string) string {
+	if ids == nil {
+		ids = []string{}
+	}
+	encoded, _ := json.Marshal(ids)
+	return string(encoded)
+}
+
+func decodeNotificationIDs(encoded string) []string {
+	var ids []string
+	if err := json.Unmarshal([]byte(encoded), &ids); err != nil {
+		return nil
+	}
+	return ids
+}
+
 type SQLRepositoryImpl struct {
 	db *bun.DB
 }
@@ -102,6 +146,18 @@ func (r *SQLRepositoryImpl) FindBySlug(ctx context.Context, slug string) (*Model
 	return toDomainModelFromSQL(sm), nil
 }
 
+func (r *SQLRepositoryImpl) FindByDomain(ctx context.Context, domain string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("custom_domain = ?", domain).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
 func (r *SQLRepositoryImpl) FindAll(
 	ctx context.Context,
 	page int,
@@ -168,6 +224,42 @@ func (r *SQLRepositoryImpl) Update(ctx context.Context, id string, statusPage *U
 		query = query.Set("auto_refresh_interval = ?", *statusPage.AutoRefreshInterval)
 		hasUpdates = true
 	}
+	if statusPage.LogoURL != nil {
+		query = query.Set("logo_url = ?", *statusPage.LogoURL)
+		hasUpdates = true
+	}
+	if statusPage.PrimaryColor != nil {
+		query = query.Set("primary_color = ?", *statusPage.PrimaryColor)
+		hasUpdates = true
+	}
+	if statusPage.CustomCSS != nil {
+		query = query.Set("custom_css = ?", *statusPage.CustomCSS)
+		hasUpdates = true
+	}
+	if statusPage.NotifyIncidents != nil {
+		query = query.Set("notify_incidents = ?", *statusPage.NotifyIncidents)
+		hasUpdates = true
+	}
+	if statusPage.NotificationIDs != nil {
+		query = query.Set("notification_ids = ?", encodeNotificationIDs(*statusPage.NotificationIDs))
+		hasUpdates = true
+	}
+	if statusPage.SubscriberChannelID != nil {
+		query = query.Set("subscriber_channel_id = ?", *statusPage.SubscriberChannelID)
+		hasUpdates = true
+	}
+	if statusPage.CustomDomain != nil {
+		var domain any
+		if *statusPage.CustomDomain != "" {
+			domain = *statusPage.CustomDomain
+		}
+		query = query.Set("custom_domain = ?", domain)
+		hasUpdates = true
+	}
+	if statusPage.PasswordHash != nil {
+		query = query.Set("password_hash = ?", *statusPage.PasswordHash)
+		hasUpdates = true
+	}
 
 	if !hasUpdates {
 		return nil
@@ -0,0 +1,275 @@
+package status_page_incident
+
+import (
+	"errors"
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxPageLimit bounds the incidents returned at once, the timeline is public
+const maxPageLimit = 100
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// pagination reads the page and limit query parameters, it answers 400 itself
+func pagination(ctx *gin.Context) (int, int, bool) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return 0, 0, false
+	}
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return 0, 0, false
+	}
+	return page, limit, true
+}
+
+// @Router		/status-pages/slug/{slug}/incidents [get]
+// @Summary		Get the incident timeline of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Param		slug	path	string	true	"Status Page Slug"
+// @Param		page	query	int	false	"Page number" default(0)
+// @Param		limit	query	int	false	"Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindBySlug(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	incidents, err := ic.service.FindBySlug(ctx, ctx.Param("slug"), page, limit)
+	if errors.Is(err, ErrStatusPageNotFound) {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch status page incidents", "error", err, "slug", ctx.Param("slug"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", incidents))
+}
+
+// @Router		/status-pages/{id}/incidents [post]
+// @Summary		Open an incident on a status page
+// @Tags			Status Pages
+// @Accept		json
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		body	body	CreateIncidentDto	true	"Incident"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Create(ctx *gin.Context) {
+	var entity CreateIncidentDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	incident, err := ic.service.Create(ctx, ctx.Param("id"), &entity)
+	if errors.Is(err, ErrStatusPageNotFound) {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	}
+	if errors.Is(err, ErrMonitorNotOnPage) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to create status page incident", "error", err, "statusPageID", ctx.Param("id"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Incident created successfully", incident))
+}
+
+// @Router		/status-pages/{id}/incidents [get]
+// @Summary		Get the incidents of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		page	query	int	false	"Page number" default(0)
+// @Param		limit	query	int	false	"Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindByStatusPageID(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	incidents, err := ic.service.FindByStatusPageID(ctx, ctx.Param("id"), page, limit)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch status page incidents", "error", err, "statusPageID", ctx.Param("id"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", incidents))
+}
+
+// @Router		/status-pages/{id}/incidents/{incidentId} [get]
+// @Summary		Get an incident of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		incidentId	path	string	true	"Incident ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindByID(ctx *gin.Context) {
+	incident, err := ic.service.FindByID(ctx, ctx.Param("id"), ctx.Param("incidentId"))
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch status page incident", "error", err, "incidentID", ctx.Param("incidentId"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if incident == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Incident not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", incident))
+}
+
+// @Router		/status-pages/{id}/incidents/{incidentId} [patch]
+// @Summary		Update the title or affected monitors of an incident
+// @Tags			Status Pages
+// @Accept		json
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		incidentId	path	string	true	"Incident ID"
+// @Param		body	body	UpdateIncidentDto	true	"Incident"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Update(ctx *gin.Context) {
+	var entity UpdateIncidentDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	incident, err := ic.service.Update(ctx, ctx.Param("id"), ctx.Param("incidentId"), &entity)
+	if errors.Is(err, ErrMonitorNotOnPage) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to update status page incident", "error", err, "incidentID", ctx.Param("incidentId"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if incident == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Incident not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Incident updated successfully", incident))
+}
+
+// @Router		/status-pages/{id}/incidents/{incidentId}/updates [post]
+// @Summary		Post an update to the timeline of an incident
+// @Tags			Status Pages
+// @Accept		json
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		incidentId	path	string	true	"Incident ID"
+// @Param		body	body	CreateIncidentUpdateDto	true	"Incident update"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) PostUpdate(ctx *gin.Context) {
+	var entity CreateIncidentUpdateDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	incident, err := ic.service.PostUpdate(ctx, ctx.Param("id"), ctx.Param("incidentId"), &entity)
+	if err != nil {
+		ic.logger.Errorw("Failed to post status page incident update", "error", err, "incidentID", ctx.Param("incidentId"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if incident == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Incident not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Incident update posted successfully", incident))
+}
+
+// @Router		/status-pages/{id}/incidents/{incidentId} [delete]
+// @Summary		Delete an incident of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		incidentId	path	string	true	"Incident ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Delete(ctx *gin.Context) {
+	found, err := ic.service.Delete(ctx, ctx.Param("id"), ctx.Param("incidentId"))
+	if err != nil {
+		ic.logger.Errorw("Failed to delete status page incident", "error", err, "incidentID", ctx.Param("incidentId"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if !found {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Incident not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Incident deleted successfully", nil))
+}
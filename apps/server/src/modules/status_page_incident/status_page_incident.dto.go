@@ -0,0 +1,19 @@
+package status_page_incident
+
+type CreateIncidentDto struct {
+	Title   string `json:"title" validate:"required,max=255" example:"Elevated API error rates"`
+	Status  string `json:"status" validate:"required,oneof=investigating identified monitoring resolved" example:"investigating"`
+	Message string `json:"message" validate:"required,max=5000" example:"We are looking into failing API requests."`
+	// MonitorIDs must be shown on the status page
+	MonitorIDs []string `json:"monitor_ids" validate:"omitempty,dive,required"`
+}
+
+type UpdateIncidentDto struct {
+	Title      *string   `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	MonitorIDs *[]string `json:"monitor_ids,omitempty" validate:"omitempty,dive,required"`
+}
+
+type CreateIncidentUpdateDto struct {
+	Status  string `json:"status" validate:"required,oneof=investigating identified monitoring resolved" example:"identified"`
+	Message string `json:"message" validate:"required,max=5000" example:"A faulty deploy was rolled back."`
+}
@@ -0,0 +1,44 @@
+package status_page_incident
+
+import "time"
+
+// Incident statuses, an incident moves through them by the updates posted to it
+const (
+	StatusInvestigating = "investigating"
+	StatusIdentified    = "identified"
+	StatusMonitoring    = "monitoring"
+	StatusResolved      = "resolved"
+)
+
+// Model is an incident posted by operators on a status page, independent of
+// the automatic state of the monitors. Status is the one of its latest update.
+type Model struct {
+	ID           string `json:"id"`
+	StatusPageID string `json:"status_page_id"`
+	Title        string `json:"title"`
+	Status       string `json:"status"`
+	// MonitorIDs are the affected monitors, all of them shown on the page
+	MonitorIDs []string   `json:"monitor_ids"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	// Updates is the timeline of the incident, newest first
+	Updates []*Update `json:"updates"`
+}
+
+// Update is an entry of the timeline of an incident
+type Update struct {
+	ID         string    `json:"id"`
+	IncidentID string    `json:"incident_id"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type UpdateModel struct {
+	Title *string
+	// Status is written together with ResolvedAt, a nil ResolvedAt clears it
+	Status     *string
+	ResolvedAt *time.Time
+	MonitorIDs *[]string
+}
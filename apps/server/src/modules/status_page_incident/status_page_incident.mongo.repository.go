@@ -0,0 +1,254 @@
+package status_page_incident
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	StatusPageID primitive.ObjectID `bson:"status_page_id"`
+	Title        string             `bson:"title"`
+	Status       string             `bson:"status"`
+	MonitorIDs   []string           `bson:"monitor_ids"`
+	ResolvedAt   *time.Time         `bson:"resolved_at"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
+
+type mongoUpdateModel struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	IncidentID primitive.ObjectID `bson:"incident_id"`
+	Status     string             `bson:"status"`
+	Message    string             `bson:"message"`
+	CreatedAt  time.Time          `bson:"created_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	monitorIDs := mm.MonitorIDs
+	if monitorIDs == nil {
+		monitorIDs = []string{}
+	}
+	return &Model{
+		ID:           mm.ID.Hex(),
+		StatusPageID: mm.StatusPageID.Hex(),
+		Title:        mm.Title,
+		Status:       mm.Status,
+		MonitorIDs:   monitorIDs,
+		ResolvedAt:   mm.ResolvedAt,
+		CreatedAt:    mm.CreatedAt,
+		UpdatedAt:    mm.UpdatedAt,
+	}
+}
+
+func toDomainUpdateFromMongo(mu *mongoUpdateModel) *Update {
+	return &Update{
+		ID:         mu.ID.Hex(),
+		IncidentID: mu.IncidentID.Hex(),
+		Status:     mu.Status,
+		Message:    mu.Message,
+		CreatedAt:  mu.CreatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+	updates    *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("status_page_incidents")
+	updates := db.Collection("status_page_incident_updates")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "status_page_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		panic("Failed to create index for status_page_incidents: " + err.Error())
+	}
+	_, err = updates.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "incident_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		panic("Failed to create index for status_page_incident_updates: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection, updates}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	statusPageID, err := primitive.ObjectIDFromHex(model.StatusPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	mm := &mongoModel{
+		ID:           primitive.NewObjectID(),
+		StatusPageID: statusPageID,
+		Title:        model.Title,
+		Status:       model.Status,
+		MonitorIDs:   model.MonitorIDs,
+		ResolvedAt:   model.ResolvedAt,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if mm.MonitorIDs == nil {
+		mm.MonitorIDs = []string{}
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mm); err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var mm mongoModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByStatusPageID(ctx context.Context, statusPageID string, page int, limit int) ([]*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(statusPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64(page * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"status_page_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	models := []*Model{}
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModelFromMongo(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func (r *MongoRepositoryImpl) Update(ctx context.Context, id string, model *UpdateModel) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{}
+	if model.Title != nil {
+		set["title"] = *model.Title
+	}
+	if model.Status != nil {
+		set["status"] = *model.Status
+		set["resolved_at"] = model.ResolvedAt
+	}
+	if model.MonitorIDs != nil {
+		set["monitor_ids"] = *model.MonitorIDs
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	set["updated_at"] = time.Now().UTC()
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": set})
+	return err
+}
+
+func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	if _, err := r.updates.DeleteMany(ctx, bson.M{"incident_id": objectID}); err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+func (r *MongoRepositoryImpl) CreateUpdate(ctx context.Context, update *Update) (*Update, error) {
+	incidentID, err := primitive.ObjectIDFromHex(update.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	mu := &mongoUpdateModel{
+		ID:         primitive.NewObjectID(),
+		IncidentID: incidentID,
+		Status:     update.Status,
+		Message:    update.Message,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if _, err := r.updates.InsertOne(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	return toDomainUpdateFromMongo(mu), nil
+}
+
+func (r *MongoRepositoryImpl) FindUpdatesByIncidentIDs(ctx context.Context, incidentIDs []string) ([]*Update, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(incidentIDs))
+	for _, id := range incidentIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return []*Update{}, nil
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.updates.Find(ctx, bson.M{"incident_id": bson.M{"$in": objectIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	updates := []*Update{}
+	for cursor.Next(ctx) {
+		var mu mongoUpdateModel
+		if err := cursor.Decode(&mu); err != nil {
+			return nil, err
+		}
+		updates = append(updates, toDomainUpdateFromMongo(&mu))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
@@ -0,0 +1,18 @@
+package status_page_incident
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	// FindByStatusPageID returns the incidents of a page newest first, without
+	// their updates
+	FindByStatusPageID(ctx context.Context, statusPageID string, page int, limit int) ([]*Model, error)
+	Update(ctx context.Context, id string, model *UpdateModel) error
+	// Delete removes the incident together with its updates
+	Delete(ctx context.Context, id string) error
+
+	CreateUpdate(ctx context.Context, update *Update) (*Update, error)
+	// FindUpdatesByIncidentIDs returns the updates of the incidents newest first
+	FindUpdatesByIncidentIDs(ctx context.Context, incidentIDs []string) ([]*Update, error)
+}
@@ -0,0 +1,45 @@
+package status_page_incident
+
+import (
+	"peekaping/src/modules/auth"
+	"peekaping/src/modules/status_page"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+	domains    *status_page.DomainMiddleware
+	passwords  *status_page.PasswordMiddleware
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+	domains *status_page.DomainMiddleware,
+	passwords *status_page.PasswordMiddleware,
+) *Route {
+	return &Route{
+		controller, middleware, domains, passwords,
+	}
+}
+
+func (uc *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	// Public timeline
+	public := rg.Group("/status-pages", uc.domains.Resolve(), uc.passwords.Protect())
+	public.GET("/slug/:slug/incidents", uc.controller.FindBySlug)
+
+	router := rg.Group("/status-pages")
+	router.Use(uc.middleware.Auth())
+
+	router.POST("/:id/incidents", uc.controller.Create)
+	router.GET("/:id/incidents", uc.controller.FindByStatusPageID)
+	router.GET("/:id/incidents/:incidentId", uc.controller.FindByID)
+	router.PATCH("/:id/incidents/:incidentId", uc.controller.Update)
+	router.DELETE("/:id/incidents/:incidentId", uc.controller.Delete)
+	router.POST("/:id/incidents/:incidentId/updates", uc.controller.PostUpdate)
+}
@@ -0,0 +1,241 @@
+package status_page_incident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/status_page"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrStatusPageNotFound = errors.New("status page not found")
+	ErrMonitorNotOnPage   = errors.New("monitor is not shown on the status page")
+)
+
+type Service interface {
+	// Create opens an incident on the status page with its first update
+	Create(ctx context.Context, statusPageID string, dto *CreateIncidentDto) (*Model, error)
+	// FindByID returns the incident with its updates, nil when it does not
+	// belong to the status page
+	FindByID(ctx context.Context, statusPageID, id string) (*Model, error)
+	FindByStatusPageID(ctx context.Context, statusPageID string, page int, limit int) ([]*Model, error)
+	// FindBySlug returns the incidents of a page for its public timeline
+	FindBySlug(ctx context.Context, slug string, page int, limit int) ([]*Model, error)
+	// Update changes the title or affected monitors, nil when the incident
+	// does not belong to the status page
+	Update(ctx context.Context, statusPageID, id string, dto *UpdateIncidentDto) (*Model, error)
+	// PostUpdate adds an update to the timeline and moves the incident to its
+	// status, nil when the incident does not belong to the status page
+	PostUpdate(ctx context.Context, statusPageID, id string, dto *CreateIncidentUpdateDto) (*Model, error)
+	// Delete removes an incident of the status page, it reports whether the
+	// incident existed
+	Delete(ctx context.Context, statusPageID, id string) (bool, error)
+}
+
+type ServiceImpl struct {
+	repository        Repository
+	statusPageService status_page.Service
+	logger            *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	statusPageService status_page.Service,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		statusPageService,
+		logger.Named("[status-page-incident-service]"),
+	}
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, statusPageID string, dto *CreateIncidentDto) (*Model, error) {
+	page, err := s.statusPageService.FindByID(ctx, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return nil, ErrStatusPageNotFound
+	}
+	if err := s.checkMonitors(ctx, statusPageID, dto.MonitorIDs); err != nil {
+		return nil, err
+	}
+
+	incident := &Model{
+		StatusPageID: statusPageID,
+		Title:        dto.Title,
+		Status:       dto.Status,
+		MonitorIDs:   dto.MonitorIDs,
+		ResolvedAt:   resolvedAt(dto.Status, nil),
+	}
+	created, err := s.repository.Create(ctx, incident)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := s.repository.CreateUpdate(ctx, &Update{
+		IncidentID: created.ID,
+		Status:     dto.Status,
+		Message:    dto.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	created.Updates = []*Update{update}
+	return created, nil
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, statusPageID, id string) (*Model, error) {
+	incident, err := s.find(ctx, statusPageID, id)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+	if err := s.attachUpdates(ctx, []*Model{incident}); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+func (s *ServiceImpl) FindByStatusPageID(ctx context.Context, statusPageID string, page int, limit int) ([]*Model, error) {
+	incidents, err := s.repository.FindByStatusPageID(ctx, statusPageID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachUpdates(ctx, incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+func (s *ServiceImpl) FindBySlug(ctx context.Context, slug string, page int, limit int) ([]*Model, error) {
+	statusPage, err := s.statusPageService.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if statusPage == nil {
+		return nil, ErrStatusPageNotFound
+	}
+	return s.FindByStatusPageID(ctx, statusPage.ID, page, limit)
+}
+
+func (s *ServiceImpl) Update(ctx context.Context, statusPageID, id string, dto *UpdateIncidentDto) (*Model, error) {
+	incident, err := s.find(ctx, statusPageID, id)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+	if dto.MonitorIDs != nil {
+		if err := s.checkMonitors(ctx, statusPageID, *dto.MonitorIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.Update(ctx, id, &UpdateModel{Title: dto.Title, MonitorIDs: dto.MonitorIDs}); err != nil {
+		return nil, err
+	}
+	return s.FindByID(ctx, statusPageID, id)
+}
+
+func (s *ServiceImpl) PostUpdate(ctx context.Context, statusPageID, id string, dto *CreateIncidentUpdateDto) (*Model, error) {
+	incident, err := s.find(ctx, statusPageID, id)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	if _, err := s.repository.CreateUpdate(ctx, &Update{
+		IncidentID: id,
+		Status:     dto.Status,
+		Message:    dto.Message,
+	}); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Update(ctx, id, &UpdateModel{
+		Status:     &dto.Status,
+		ResolvedAt: resolvedAt(dto.Status, incident.ResolvedAt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.FindByID(ctx, statusPageID, id)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, statusPageID, id string) (bool, error) {
+	incident, err := s.find(ctx, statusPageID, id)
+	if err != nil || incident == nil {
+		return false, err
+	}
+	return true, s.repository.Delete(ctx, id)
+}
+
+// find returns the incident when it belongs to the status page
+func (s *ServiceImpl) find(ctx context.Context, statusPageID, id string) (*Model, error) {
+	incident, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if incident == nil || incident.StatusPageID != statusPageID {
+		return nil, nil
+	}
+	return incident, nil
+}
+
+// checkMonitors makes sure every affected monitor is shown on the page
+func (s *ServiceImpl) checkMonitors(ctx context.Context, statusPageID string, monitorIDs []string) error {
+	if len(monitorIDs) == 0 {
+		return nil
+	}
+
+	relations, err := s.statusPageService.GetMonitorsForStatusPage(ctx, statusPageID)
+	if err != nil {
+		return err
+	}
+	onPage := make(map[string]bool, len(relations))
+	for _, relation := range relations {
+		onPage[relation.MonitorID] = true
+	}
+	for _, monitorID := range monitorIDs {
+		if !onPage[monitorID] {
+			return fmt.Errorf("%w: %s", ErrMonitorNotOnPage, monitorID)
+		}
+	}
+	return nil
+}
+
+// attachUpdates loads the timelines of the incidents in one query
+func (s *ServiceImpl) attachUpdates(ctx context.Context, incidents []*Model) error {
+	ids := make([]string, 0, len(incidents))
+	byID := make(map[string]*Model, len(incidents))
+	for _, incident := range incidents {
+		incident.Updates = []*Update{}
+		ids = append(ids, incident.ID)
+		byID[incident.ID] = incident
+	}
+
+	updates, err := s.repository.FindUpdatesByIncidentIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		if incident, ok := byID[update.IncidentID]; ok {
+			incident.Updates = append(incident.Updates, update)
+		}
+	}
+	return nil
+}
+
+// resolvedAt keeps the time an incident was first resolved and clears it
+// when the incident is reopened
+func resolvedAt(status string, current *time.Time) *time.Time {
+	if status != StatusResolved {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	now := time.Now().UTC()
+	return &now
+}
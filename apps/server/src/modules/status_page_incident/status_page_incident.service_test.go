@@ -0,0 +1,170 @@
+package status_page_incident
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/status_page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.uber.org/zap"
+)
+
+type fakeStatusPageService struct {
+	status_page.Service
+	pages    map[string]*status_page.Model
+	monitors map[string][]string
+}
+
+func (f *fakeStatusPageService) FindByID(ctx context.Context, id string) (*status_page.Model, error) {
+	return f.pages[id], nil
+}
+
+func (f *fakeStatusPageService) FindBySlug(ctx context.Context, slug string) (*status_page.Model, error) {
+	for _, page := range f.pages {
+		if page.Slug == slug {
+			return page, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStatusPageService) GetMonitorsForStatusPage(ctx context.Context, statusPageID string) ([]*monitor_status_page.Model, error) {
+	var relations []*monitor_status_page.Model
+	for _, monitorID := range f.monitors[statusPageID] {
+		relations = append(relations, &monitor_status_page.Model{StatusPageID: statusPageID, MonitorID: monitorID})
+	}
+	return relations, nil
+}
+
+// newTestService runs the service on the SQL repository over a private
+// in-memory database
+func newTestService(t *testing.T) Service {
+	sqldb, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	for _, model := range []any{(*sqlModel)(nil), (*sqlUpdateModel)(nil)} {
+		_, err := db.NewCreateTable().Model(model).IfNotExists().Exec(context.Background())
+		require.NoError(t, err)
+	}
+
+	statusPages := &fakeStatusPageService{
+		pages: map[string]*status_page.Model{
+			"page1": {ID: "page1", Slug: "acme"},
+			"page2": {ID: "page2", Slug: "other"},
+		},
+		monitors: map[string][]string{"page1": {"api", "web"}, "page2": {"db"}},
+	}
+	return NewService(NewSQLRepository(db), statusPages, zap.NewNop().Sugar())
+}
+
+func TestService_IncidentTimeline(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	incident, err := service.Create(ctx, "page1", &CreateIncidentDto{
+		Title:      "Elevated API errors",
+		Status:     StatusInvestigating,
+		Message:    "Looking into it",
+		MonitorIDs: []string{"api"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusInvestigating, incident.Status)
+	assert.Nil(t, incident.ResolvedAt)
+	require.Len(t, incident.Updates, 1)
+
+	incident, err = service.PostUpdate(ctx, "page1", incident.ID, &CreateIncidentUpdateDto{Status: StatusIdentified, Message: "Bad deploy"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusIdentified, incident.Status)
+
+	incident, err = service.PostUpdate(ctx, "page1", incident.ID, &CreateIncidentUpdateDto{Status: StatusResolved, Message: "Rolled back"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusResolved, incident.Status)
+	require.NotNil(t, incident.ResolvedAt)
+	resolvedAt := *incident.ResolvedAt
+
+	// the timeline is newest first
+	require.Len(t, incident.Updates, 3)
+	assert.Equal(t, []string{"Rolled back", "Bad deploy", "Looking into it"}, []string{
+		incident.Updates[0].Message, incident.Updates[1].Message, incident.Updates[2].Message,
+	})
+
+	// resolving again keeps the first resolution, reopening clears it
+	incident, err = service.PostUpdate(ctx, "page1", incident.ID, &CreateIncidentUpdateDto{Status: StatusResolved, Message: "Confirmed"})
+	require.NoError(t, err)
+	assert.True(t, resolvedAt.Equal(*incident.ResolvedAt))
+	incident, err = service.PostUpdate(ctx, "page1", incident.ID, &CreateIncidentUpdateDto{Status: StatusMonitoring, Message: "Errors are back"})
+	require.NoError(t, err)
+	assert.Nil(t, incident.ResolvedAt)
+
+	public, err := service.FindBySlug(ctx, "acme", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, public, 1)
+	assert.Len(t, public[0].Updates, 5)
+	assert.Equal(t, []string{"api"}, public[0].MonitorIDs)
+
+	_, err = service.FindBySlug(ctx, "missing", 0, 10)
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+}
+
+func TestService_AffectedMonitorsMustBeOnPage(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, "page1", &CreateIncidentDto{Title: "DB down", Status: StatusInvestigating, Message: "...", MonitorIDs: []string{"db"}})
+	assert.ErrorIs(t, err, ErrMonitorNotOnPage)
+
+	_, err = service.Create(ctx, "missing", &CreateIncidentDto{Title: "DB down", Status: StatusInvestigating, Message: "..."})
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+
+	incident, err := service.Create(ctx, "page1", &CreateIncidentDto{Title: "Slow", Status: StatusInvestigating, Message: "..."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, incident.MonitorIDs)
+
+	monitorIDs := []string{"api", "web"}
+	title := "Slow responses"
+	incident, err = service.Update(ctx, "page1", incident.ID, &UpdateIncidentDto{Title: &title, MonitorIDs: &monitorIDs})
+	require.NoError(t, err)
+	assert.Equal(t, "Slow responses", incident.Title)
+	assert.Equal(t, monitorIDs, incident.MonitorIDs)
+
+	monitorIDs = []string{"db"}
+	_, err = service.Update(ctx, "page1", incident.ID, &UpdateIncidentDto{MonitorIDs: &monitorIDs})
+	assert.ErrorIs(t, err, ErrMonitorNotOnPage)
+}
+
+func TestService_IncidentsBelongToTheirPage(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	incident, err := service.Create(ctx, "page1", &CreateIncidentDto{Title: "Outage", Status: StatusInvestigating, Message: "..."})
+	require.NoError(t, err)
+
+	found, err := service.FindByID(ctx, "page2", incident.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	updated, err := service.PostUpdate(ctx, "page2", incident.ID, &CreateIncidentUpdateDto{Status: StatusResolved, Message: "..."})
+	require.NoError(t, err)
+	assert.Nil(t, updated)
+
+	deleted, err := service.Delete(ctx, "page2", incident.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = service.Delete(ctx, "page1", incident.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	incidents, err := service.FindByStatusPageID(ctx, "page1", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
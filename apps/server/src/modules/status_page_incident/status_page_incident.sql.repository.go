@@ -0,0 +1,212 @@
+package status_page_incident
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:status_page_incidents,alias:spi"`
+
+	ID           string `bun:"id,pk"`
+	StatusPageID string `bun:"status_page_id,notnull"`
+	Title        string `bun:"title,notnull"`
+	Status       string `bun:"status,notnull"`
+	// JSON array of monitor IDs
+	MonitorIDs string     `bun:"monitor_ids,notnull,default:'[]'"`
+	ResolvedAt *time.Time `bun:"resolved_at"`
+	CreatedAt  time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type sqlUpdateModel struct {
+	bun.BaseModel `bun:"table:status_page_incident_updates,alias:spiu"`
+
+	ID         string    `bun:"id,pk"`
+	IncidentID string    `bun:"incident_id,notnull"`
+	Status     string    `bun:"status,notnull"`
+	Message    string    `bun:"message,notnull"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:           sm.ID,
+		StatusPageID: sm.StatusPageID,
+		Title:        sm.Title,
+		Status:       sm.Status,
+		MonitorIDs:   decodeMonitorIDs(sm.MonitorIDs),
+		ResolvedAt:   sm.ResolvedAt,
+		CreatedAt:    sm.CreatedAt,
+		UpdatedAt:    sm.UpdatedAt,
+	}
+}
+
+func toDomainUpdateFromSQL(su *sqlUpdateModel) *Update {
+	return &Update{
+		ID:         su.ID,
+		IncidentID: su.IncidentID,
+		Status:     su.Status,
+		Message:    su.Message,
+		CreatedAt:  su.CreatedAt,
+	}
+}
+
+func encodeMonitorIDs(ids []string) string {
+	if ids == nil {
+		ids = []string{}
+	}
+	encoded, _ := json.Marshal(ids)
+	return string(encoded)
+}
+
+func decodeMonitorIDs(encoded string) []string {
+	ids := []string{}
+	if err := json.Unmarshal([]byte(encoded), &ids); err != nil {
+		return []string{}
+	}
+	return ids
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	now := time.Now().UTC()
+	sm := &sqlModel{
+		ID:           uuid.New().String(),
+		StatusPageID: model.StatusPageID,
+		Title:        model.Title,
+		Status:       model.Status,
+		MonitorIDs:   encodeMonitorIDs(model.MonitorIDs),
+		ResolvedAt:   model.ResolvedAt,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByStatusPageID(ctx context.Context, statusPageID string, page int, limit int) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("status_page_id = ?", statusPageID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(page * limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) Update(ctx context.Context, id string, model *UpdateModel) error {
+	query := r.db.NewUpdate().Model((*sqlModel)(nil)).Where("id = ?", id)
+
+	hasUpdates := false
+
+	if model.Title != nil {
+		query = query.Set("title = ?", *model.Title)
+		hasUpdates = true
+	}
+	if model.Status != nil {
+		query = query.Set("status = ?", *model.Status)
+		query = query.Set("resolved_at = ?", model.ResolvedAt)
+		hasUpdates = true
+	}
+	if model.MonitorIDs != nil {
+		query = query.Set("monitor_ids = ?", encodeMonitorIDs(*model.MonitorIDs))
+		hasUpdates = true
+	}
+
+	if !hasUpdates {
+		return nil
+	}
+
+	query = query.Set("updated_at = ?", time.Now().UTC())
+
+	_, err := query.Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*sqlUpdateModel)(nil)).Where("incident_id = ?", id).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
+		return err
+	})
+}
+
+func (r *SQLRepositoryImpl) CreateUpdate(ctx context.Context, update *Update) (*Update, error) {
+	su := &sqlUpdateModel{
+		ID:         uuid.New().String(),
+		IncidentID: update.IncidentID,
+		Status:     update.Status,
+		Message:    update.Message,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	_, err := r.db.NewInsert().Model(su).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainUpdateFromSQL(su), nil
+}
+
+func (r *SQLRepositoryImpl) FindUpdatesByIncidentIDs(ctx context.Context, incidentIDs []string) ([]*Update, error) {
+	if len(incidentIDs) == 0 {
+		return []*Update{}, nil
+	}
+
+	var sus []*sqlUpdateModel
+	err := r.db.NewSelect().
+		Model(&sus).
+		Where("incident_id IN (?)", bun.In(incidentIDs)).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]*Update, 0, len(sus))
+	for _, su := range sus {
+		updates = append(updates, toDomainUpdateFromSQL(su))
+	}
+	return updates, nil
+}
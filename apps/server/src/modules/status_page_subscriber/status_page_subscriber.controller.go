@@ -0,0 +1,190 @@
+package status_page_subscriber
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"peekaping/src/config"
+	"peekaping/src/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service   Service
+	clientURL string
+	logger    *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	cfg *config.Config,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		strings.TrimRight(cfg.ClientURL, "/"),
+		logger,
+	}
+}
+
+// @Router		/status-pages/slug/{slug}/subscribe [post]
+// @Summary		Subscribe an email address to the incidents of a status page
+// @Tags			Status Pages
+// @Accept		json
+// @Produce		json
+// @Param		slug	path	string	true	"Status Page Slug"
+// @Param		body	body	SubscribeDto	true	"Subscriber"
+// @Success		202	{object}	utils.ApiResponse[any]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Subscribe(ctx *gin.Context) {
+	var entity SubscribeDto
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(entity); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	err := ic.service.Subscribe(ctx, ctx.Param("slug"), entity.Email)
+	if errors.Is(err, ErrStatusPageNotFound) {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	}
+	if errors.Is(err, ErrSubscriptionsDisabled) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to subscribe to status page", "error", err, "slug", ctx.Param("slug"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, utils.NewSuccessResponse[any]("Check your inbox to confirm the subscription", nil))
+}
+
+// @Router		/status-pages/subscriptions/confirm [get]
+// @Summary		Confirm a status page subscription and redirect to the page
+// @Tags			Status Pages
+// @Param		token	query	string	true	"Token from the confirmation email"
+// @Success		303
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Confirm(ctx *gin.Context) {
+	page, err := ic.service.Confirm(ctx, ctx.Query("token"))
+	if err != nil {
+		ic.logger.Errorw("Failed to confirm status page subscription", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if page == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Subscription not found"))
+		return
+	}
+
+	ctx.Redirect(http.StatusSeeOther, fmt.Sprintf("%s/status/%s?subscription=confirmed", ic.clientURL, page.Slug))
+}
+
+// @Router		/status-pages/subscriptions/unsubscribe [get]
+// @Summary		Unsubscribe from a status page and redirect to the page
+// @Tags			Status Pages
+// @Param		token	query	string	true	"Token from a notification email"
+// @Success		303
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Unsubscribe(ctx *gin.Context) {
+	page, err := ic.service.Unsubscribe(ctx, ctx.Query("token"))
+	if err != nil {
+		ic.logger.Errorw("Failed to unsubscribe from status page", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if page == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Subscription not found"))
+		return
+	}
+
+	ctx.Redirect(http.StatusSeeOther, fmt.Sprintf("%s/status/%s?subscription=unsubscribed", ic.clientURL, page.Slug))
+}
+
+// @Router		/status-pages/slug/{slug}/feed.atom [get]
+// @Summary		Get the incidents of a status page as an Atom feed
+// @Tags			Status Pages
+// @Produce		xml
+// @Param		slug	path	string	true	"Status Page Slug"
+// @Success		200	{object}	AtomFeed
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Feed(ctx *gin.Context) {
+	feed, err := ic.service.Feed(ctx, ctx.Param("slug"))
+	if errors.Is(err, ErrStatusPageNotFound) {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Status page not found"))
+		return
+	}
+	if err != nil {
+		ic.logger.Errorw("Failed to build status page feed", "error", err, "slug", ctx.Param("slug"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		ic.logger.Errorw("Failed to encode status page feed", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	ctx.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// @Router		/status-pages/{id}/subscribers [get]
+// @Summary		Get the subscribers of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) FindByStatusPageID(ctx *gin.Context) {
+	subscribers, err := ic.service.FindByStatusPageID(ctx, ctx.Param("id"))
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch status page subscribers", "error", err, "statusPageID", ctx.Param("id"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", subscribers))
+}
+
+// @Router		/status-pages/{id}/subscribers/{subscriberId} [delete]
+// @Summary		Remove a subscriber of a status page
+// @Tags			Status Pages
+// @Produce		json
+// @Security	BearerAuth
+// @Param		id	path	string	true	"Status Page ID"
+// @Param		subscriberId	path	string	true	"Subscriber ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (ic *Controller) Delete(ctx *gin.Context) {
+	found, err := ic.service.Delete(ctx, ctx.Param("id"), ctx.Param("subscriberId"))
+	if err != nil {
+		ic.logger.Errorw("Failed to delete status page subscriber", "error", err, "subscriberID", ctx.Param("subscriberId"))
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if !found {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Subscriber not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Subscriber deleted successfully", nil))
+}
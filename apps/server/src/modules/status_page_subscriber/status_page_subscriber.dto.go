@@ -0,0 +1,5 @@
+package status_page_subscriber
+
+type SubscribeDto struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
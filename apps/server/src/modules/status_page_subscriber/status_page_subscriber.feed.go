@@ -0,0 +1,111 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"sort"
+	"time"
+)
+
+const (
+	// feedEntriesLimit is the number of incidents kept in the feed
+	feedEntriesLimit = 50
+	// feedMonitorHeartbeats is the number of important heartbeats read per monitor
+	feedMonitorHeartbeats = 50
+)
+
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    AtomLink    `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    AtomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+// feedIncident is a status change of a monitor on the page
+type feedIncident struct {
+	monitorName string
+	heartbeat   *heartbeat.Model
+}
+
+func (s *ServiceImpl) Feed(ctx context.Context, slug string) (*AtomFeed, error) {
+	page, err := s.statusPageService.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil || !page.Published {
+		return nil, ErrStatusPageNotFound
+	}
+
+	relations, err := s.statusPageService.GetMonitorsForStatusPage(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	important := true
+	var incidents []feedIncident
+	for _, relation := range relations {
+		monitorModel, err := s.monitorService.FindByID(ctx, relation.MonitorID)
+		if err != nil || monitorModel == nil {
+			continue
+		}
+		heartbeats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, relation.MonitorID, feedMonitorHeartbeats, 0, &important, false)
+		if err != nil {
+			s.logger.Errorf("Failed to get important heartbeats of monitor %s: %v", relation.MonitorID, err)
+			continue
+		}
+		for _, hb := range heartbeats {
+			incidents = append(incidents, feedIncident{monitorName: monitorModel.Name, heartbeat: hb})
+		}
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].heartbeat.Time.After(incidents[j].heartbeat.Time)
+	})
+	if len(incidents) > feedEntriesLimit {
+		incidents = incidents[:feedEntriesLimit]
+	}
+
+	pageURL := s.pageURL(page)
+	feed := &AtomFeed{
+		XMLNS:   "http://www.w3.org/2005/Atom",
+		ID:      pageURL,
+		Title:   page.Title,
+		Updated: page.UpdatedAt.UTC().Format(time.RFC3339),
+		Link:    AtomLink{Href: pageURL, Rel: "alternate"},
+		Entries: make([]AtomEntry, 0, len(incidents)),
+	}
+	if len(incidents) > 0 {
+		feed.Updated = incidents[0].heartbeat.Time.UTC().Format(time.RFC3339)
+	}
+
+	for _, incident := range incidents {
+		hb := incident.heartbeat
+		at := hb.Time.UTC().Format(time.RFC3339)
+		feed.Entries = append(feed.Entries, AtomEntry{
+			ID:      fmt.Sprintf("%s#%s", pageURL, hb.ID),
+			Title:   fmt.Sprintf("%s is %s", incident.monitorName, statusName(hb.Status)),
+			Updated: at,
+			Link:    AtomLink{Href: pageURL},
+			Summary: fmt.Sprintf("%s is %s since %s", incident.monitorName, statusName(hb.Status), hb.Time.UTC().Format(time.RFC1123)),
+		})
+	}
+	return feed, nil
+}
@@ -0,0 +1,37 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+
+	"go.uber.org/zap"
+)
+
+// EventListener emails the subscribers of status pages when a monitor on the
+// page changes state
+type EventListener struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewEventListener(service Service, logger *zap.SugaredLogger) *EventListener {
+	return &EventListener{
+		service: service,
+		logger:  logger.Named("[status-page-subscriber-listener]"),
+	}
+}
+
+// Subscribe subscribes to MonitorStatusChanged events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorStatusChanged, l.handleStatusChanged)
+}
+
+func (l *EventListener) handleStatusChanged(event events.Event) {
+	hb, ok := event.Payload.(*heartbeat.Model)
+	if !ok {
+		l.logger.Errorf("Invalid handleStatusChanged event payload type: %v", event.Payload)
+		return
+	}
+	l.service.NotifyStatusChange(context.Background(), hb)
+}
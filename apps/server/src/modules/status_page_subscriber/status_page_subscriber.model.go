@@ -0,0 +1,18 @@
+package status_page_subscriber
+
+import "time"
+
+// Model is an email address subscribed to the incidents of a status page. The
+// token confirms the subscription and later unsubscribes it, it is only ever
+// sent to the address itself.
+type Model struct {
+	ID           string `json:"id"`
+	StatusPageID string `json:"status_page_id"`
+	Email        string `json:"email"`
+	Token        string `json:"-"`
+	Confirmed    bool   `json:"confirmed"`
+	// ConfirmationSentAt limits how often a pending address is emailed
+	ConfirmationSentAt time.Time  `json:"-"`
+	ConfirmedAt        *time.Time `json:"confirmed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
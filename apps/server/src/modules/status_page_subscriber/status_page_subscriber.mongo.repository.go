@@ -0,0 +1,177 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID                 primitive.ObjectID `bson:"_id"`
+	StatusPageID       primitive.ObjectID `bson:"status_page_id"`
+	Email              string             `bson:"email"`
+	Token              string             `bson:"token"`
+	Confirmed          bool               `bson:"confirmed"`
+	ConfirmationSentAt time.Time          `bson:"confirmation_sent_at"`
+	ConfirmedAt        *time.Time         `bson:"confirmed_at"`
+	CreatedAt          time.Time          `bson:"created_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:                 mm.ID.Hex(),
+		StatusPageID:       mm.StatusPageID.Hex(),
+		Email:              mm.Email,
+		Token:              mm.Token,
+		Confirmed:          mm.Confirmed,
+		ConfirmationSentAt: mm.ConfirmationSentAt,
+		ConfirmedAt:        mm.ConfirmedAt,
+		CreatedAt:          mm.CreatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("status_page_subscribers")
+
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status_page_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		panic("Failed to create index for status_page_subscribers: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	statusPageID, err := primitive.ObjectIDFromHex(model.StatusPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := &mongoModel{
+		ID:                 primitive.NewObjectID(),
+		StatusPageID:       statusPageID,
+		Email:              model.Email,
+		Token:              model.Token,
+		Confirmed:          model.Confirmed,
+		ConfirmationSentAt: model.ConfirmationSentAt,
+		ConfirmedAt:        model.ConfirmedAt,
+		CreatedAt:          time.Now().UTC(),
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mm); err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) findOne(ctx context.Context, filter bson.M) (*Model, error) {
+	var mm mongoModel
+	err := r.collection.FindOne(ctx, filter).Decode(&mm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+func (r *MongoRepositoryImpl) FindByToken(ctx context.Context, token string) (*Model, error) {
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *MongoRepositoryImpl) FindByStatusPageAndEmail(ctx context.Context, statusPageID, email string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"status_page_id": objectID, "email": email})
+}
+
+func (r *MongoRepositoryImpl) FindByStatusPageID(ctx context.Context, statusPageID string, confirmedOnly bool) ([]*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(statusPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"status_page_id": objectID}
+	if confirmedOnly {
+		filter["confirmed"] = true
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	models := []*Model{}
+	for cursor.Next(ctx) {
+		var mm mongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		models = append(models, toDomainModelFromMongo(&mm))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func (r *MongoRepositoryImpl) update(ctx context.Context, id string, set bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": set})
+	return err
+}
+
+func (r *MongoRepositoryImpl) UpdateConfirmationSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	return r.update(ctx, id, bson.M{"confirmation_sent_at": sentAt})
+}
+
+func (r *MongoRepositoryImpl) Confirm(ctx context.Context, id string, confirmedAt time.Time) error {
+	return r.update(ctx, id, bson.M{"confirmed": true, "confirmed_at": confirmedAt})
+}
+
+func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
@@ -0,0 +1,19 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByToken(ctx context.Context, token string) (*Model, error)
+	FindByStatusPageAndEmail(ctx context.Context, statusPageID, email string) (*Model, error)
+	// FindByStatusPageID returns the subscribers oldest first, optionally only
+	// the confirmed ones
+	FindByStatusPageID(ctx context.Context, statusPageID string, confirmedOnly bool) ([]*Model, error)
+	UpdateConfirmationSentAt(ctx context.Context, id string, sentAt time.Time) error
+	Confirm(ctx context.Context, id string, confirmedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,45 @@
+package status_page_subscriber
+
+import (
+	"peekaping/src/modules/auth"
+	"peekaping/src/modules/status_page"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Route struct {
+	controller *Controller
+	middleware *auth.MiddlewareProvider
+	domains    *status_page.DomainMiddleware
+	passwords  *status_page.PasswordMiddleware
+}
+
+func NewRoute(
+	controller *Controller,
+	middleware *auth.MiddlewareProvider,
+	domains *status_page.DomainMiddleware,
+	passwords *status_page.PasswordMiddleware,
+) *Route {
+	return &Route{
+		controller, middleware, domains, passwords,
+	}
+}
+
+func (uc *Route) ConnectRoute(
+	rg *gin.RouterGroup,
+	controller *Controller,
+) {
+	// Public routes, the tokens of the email links authorize them
+	public := rg.Group("/status-pages", uc.domains.Resolve())
+	protected := public.Group("", uc.passwords.Protect())
+	protected.POST("/slug/:slug/subscribe", uc.controller.Subscribe)
+	protected.GET("/slug/:slug/feed.atom", uc.controller.Feed)
+	public.GET("/subscriptions/confirm", uc.controller.Confirm)
+	public.GET("/subscriptions/unsubscribe", uc.controller.Unsubscribe)
+
+	router := rg.Group("/status-pages")
+	router.Use(uc.middleware.Auth())
+
+	router.GET("/:id/subscribers", uc.controller.FindByStatusPageID)
+	router.DELETE("/:id/subscribers/:subscriberId", uc.controller.Delete)
+}
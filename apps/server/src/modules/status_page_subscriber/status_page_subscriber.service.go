@@ -0,0 +1,311 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"peekaping/src/config"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/status_page"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// subscriberChannelType is the notification channel type subscribers are emailed through
+const subscriberChannelType = "smtp"
+
+// confirmationResendInterval is how long a pending address waits before the
+// confirmation email is sent again, so the subscribe form cannot flood it
+const confirmationResendInterval = 5 * time.Minute
+
+var (
+	ErrStatusPageNotFound    = errors.New("status page not found")
+	ErrSubscriptionsDisabled = errors.New("status page does not accept subscribers")
+	ErrChannelNotSMTP        = errors.New("notification channel must be an smtp channel")
+)
+
+type Service interface {
+	// Subscribe registers a pending subscriber and emails the confirmation
+	// link. Subscribing an address again re-sends the link instead.
+	Subscribe(ctx context.Context, slug string, email string) error
+	// Confirm confirms the subscription of the token and returns its page,
+	// nil when the token is unknown
+	Confirm(ctx context.Context, token string) (*status_page.Model, error)
+	// Unsubscribe removes the subscription of the token and returns its page,
+	// nil when the token is unknown
+	Unsubscribe(ctx context.Context, token string) (*status_page.Model, error)
+	FindByStatusPageID(ctx context.Context, statusPageID string) ([]*Model, error)
+	// Delete removes a subscriber of the status page, it reports whether the
+	// subscriber existed
+	Delete(ctx context.Context, statusPageID, id string) (bool, error)
+
+	// NotifyStatusChange emails the confirmed subscribers of every status page
+	// that shows the monitor of the heartbeat
+	NotifyStatusChange(ctx context.Context, hb *heartbeat.Model)
+	// Feed returns the incidents of the published status page as an Atom feed
+	Feed(ctx context.Context, slug string) (*AtomFeed, error)
+}
+
+type ServiceImpl struct {
+	repository                 Repository
+	statusPageService          status_page.Service
+	monitorStatusPageService   monitor_status_page.Service
+	monitorService             monitor.Service
+	heartbeatService           heartbeat.Service
+	notificationChannelService notification_channel.Service
+	clientURL                  string
+	logger                     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	statusPageService status_page.Service,
+	monitorStatusPageService monitor_status_page.Service,
+	monitorService monitor.Service,
+	heartbeatService heartbeat.Service,
+	notificationChannelService notification_channel.Service,
+	cfg *config.Config,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		statusPageService,
+		monitorStatusPageService,
+		monitorService,
+		heartbeatService,
+		notificationChannelService,
+		strings.TrimRight(cfg.ClientURL, "/"),
+		logger.Named("[status-page-subscriber-service]"),
+	}
+}
+
+func (s *ServiceImpl) Subscribe(ctx context.Context, slug string, email string) error {
+	page, err := s.statusPageService.FindBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if page == nil || !page.Published {
+		return ErrStatusPageNotFound
+	}
+	if page.SubscriberChannelID == "" {
+		return ErrSubscriptionsDisabled
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	now := time.Now().UTC()
+
+	subscriber, err := s.repository.FindByStatusPageAndEmail(ctx, page.ID, email)
+	if err != nil {
+		return err
+	}
+	if subscriber != nil {
+		// the response is the same either way so the form does not reveal who is subscribed
+		if subscriber.Confirmed || now.Sub(subscriber.ConfirmationSentAt) < confirmationResendInterval {
+			return nil
+		}
+		if err := s.repository.UpdateConfirmationSentAt(ctx, subscriber.ID, now); err != nil {
+			return err
+		}
+		return s.sendConfirmation(ctx, page, subscriber)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	subscriber, err = s.repository.Create(ctx, &Model{
+		StatusPageID:       page.ID,
+		Email:              email,
+		Token:              token,
+		ConfirmationSentAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	return s.sendConfirmation(ctx, page, subscriber)
+}
+
+func (s *ServiceImpl) Confirm(ctx context.Context, token string) (*status_page.Model, error) {
+	subscriber, err := s.repository.FindByToken(ctx, token)
+	if err != nil || subscriber == nil {
+		return nil, err
+	}
+
+	page, err := s.statusPageService.FindByID(ctx, subscriber.StatusPageID)
+	if err != nil || page == nil {
+		return nil, err
+	}
+
+	if !subscriber.Confirmed {
+		if err := s.repository.Confirm(ctx, subscriber.ID, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (s *ServiceImpl) Unsubscribe(ctx context.Context, token string) (*status_page.Model, error) {
+	subscriber, err := s.repository.FindByToken(ctx, token)
+	if err != nil || subscriber == nil {
+		return nil, err
+	}
+
+	page, err := s.statusPageService.FindByID(ctx, subscriber.StatusPageID)
+	if err != nil || page == nil {
+		return nil, err
+	}
+
+	if err := s.repository.Delete(ctx, subscriber.ID); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (s *ServiceImpl) FindByStatusPageID(ctx context.Context, statusPageID string) ([]*Model, error) {
+	return s.repository.FindByStatusPageID(ctx, statusPageID, false)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, statusPageID, id string) (bool, error) {
+	subscriber, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if subscriber == nil || subscriber.StatusPageID != statusPageID {
+		return false, nil
+	}
+	return true, s.repository.Delete(ctx, id)
+}
+
+func (s *ServiceImpl) NotifyStatusChange(ctx context.Context, hb *heartbeat.Model) {
+	relations, err := s.monitorStatusPageService.GetStatusPagesForMonitor(ctx, hb.MonitorID)
+	if err != nil {
+		s.logger.Errorf("Failed to get status pages of monitor %s: %v", hb.MonitorID, err)
+		return
+	}
+	if len(relations) == 0 {
+		return
+	}
+
+	monitorModel, err := s.monitorService.FindByID(ctx, hb.MonitorID)
+	if err != nil || monitorModel == nil {
+		s.logger.Warnf("Monitor %s not found for subscriber notification", hb.MonitorID)
+		return
+	}
+
+	for _, relation := range relations {
+		if !relation.Active {
+			continue
+		}
+		page, err := s.statusPageService.FindByID(ctx, relation.StatusPageID)
+		if err != nil {
+			s.logger.Errorf("Failed to get status page %s: %v", relation.StatusPageID, err)
+			continue
+		}
+		if page == nil || !page.Published || page.SubscriberChannelID == "" {
+			continue
+		}
+
+		subscribers, err := s.repository.FindByStatusPageID(ctx, page.ID, true)
+		if err != nil {
+			s.logger.Errorf("Failed to get subscribers of status page %s: %v", page.ID, err)
+			continue
+		}
+
+		// the public name of the monitor only, the heartbeat message and the
+		// notes of the monitor are not meant for visitors
+		subject := fmt.Sprintf("[%s] %s is %s", page.Title, monitorModel.Name, statusName(hb.Status))
+		for _, subscriber := range subscribers {
+			body := fmt.Sprintf(
+				"%s is %s since %s.\n\nCurrent status: %s\n\nUnsubscribe: %s",
+				monitorModel.Name, statusName(hb.Status), hb.Time.UTC().Format(time.RFC1123),
+				s.pageURL(page), s.linkURL("unsubscribe", subscriber.Token),
+			)
+			if err := s.sendEmail(ctx, page.SubscriberChannelID, subscriber.Email, subject, body); err != nil {
+				s.logger.Errorf("Failed to notify subscriber %s of status page %s: %v", subscriber.ID, page.ID, err)
+			}
+		}
+	}
+}
+
+func (s *ServiceImpl) sendConfirmation(ctx context.Context, page *status_page.Model, subscriber *Model) error {
+	subject := fmt.Sprintf("Confirm your subscription to %s", page.Title)
+	body := fmt.Sprintf(
+		"You asked to be notified about incidents on %s.\n\nConfirm the subscription: %s\n\nIf you did not ask for this, ignore this email.",
+		page.Title, s.linkURL("confirm", subscriber.Token),
+	)
+	return s.sendEmail(ctx, page.SubscriberChannelID, subscriber.Email, subject, body)
+}
+
+// sendEmail sends through the smtp channel with the recipient and subject
+// replaced, the same way reports are delivered
+func (s *ServiceImpl) sendEmail(ctx context.Context, channelID, to, subject, body string) error {
+	channel, err := s.notificationChannelService.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil || channel.Type != subscriberChannelType {
+		return ErrChannelNotSMTP
+	}
+	if channel.Config == nil {
+		return fmt.Errorf("notification channel %s has no config", channel.Name)
+	}
+	provider, ok := notification_channel.GetNotificationChannelProvider(channel.Type)
+	if !ok {
+		return fmt.Errorf("no provider registered for %s channels", channel.Type)
+	}
+
+	var channelConfig map[string]any
+	if err := json.Unmarshal([]byte(*channel.Config), &channelConfig); err != nil {
+		return err
+	}
+	channelConfig["to"] = to
+	channelConfig["custom_subject"] = subject
+	delete(channelConfig, "custom_body")
+	delete(channelConfig, "cc")
+	delete(channelConfig, "bcc")
+
+	configJSON, err := json.Marshal(channelConfig)
+	if err != nil {
+		return err
+	}
+	return provider.Send(ctx, string(configJSON), body, nil, nil)
+}
+
+func (s *ServiceImpl) pageURL(page *status_page.Model) string {
+	return fmt.Sprintf("%s/status/%s", s.clientURL, page.Slug)
+}
+
+func (s *ServiceImpl) linkURL(action, token string) string {
+	return fmt.Sprintf("%s/api/v1/status-pages/subscriptions/%s?token=%s", s.clientURL, action, url.QueryEscape(token))
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func statusName(status heartbeat.MonitorStatus) string {
+	switch status {
+	case shared.MonitorStatusDown:
+		return "DOWN"
+	case shared.MonitorStatusUp:
+		return "UP"
+	case shared.MonitorStatusMaintenance:
+		return "under MAINTENANCE"
+	default:
+		return "PENDING"
+	}
+}
@@ -0,0 +1,356 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_status_page"
+	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/shared"
+	"peekaping/src/modules/status_page"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryRepository struct {
+	Repository
+	subscribers map[string]*Model
+}
+
+func (r *memoryRepository) Create(ctx context.Context, model *Model) (*Model, error) {
+	created := *model
+	created.ID = fmt.Sprintf("sub%d", len(r.subscribers)+1)
+	created.CreatedAt = time.Now().UTC()
+	r.subscribers[created.ID] = &created
+	return &created, nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Model, error) {
+	if subscriber, ok := r.subscribers[id]; ok {
+		found := *subscriber
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByToken(ctx context.Context, token string) (*Model, error) {
+	for _, subscriber := range r.subscribers {
+		if subscriber.Token == token {
+			found := *subscriber
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByStatusPageAndEmail(ctx context.Context, statusPageID, email string) (*Model, error) {
+	for _, subscriber := range r.subscribers {
+		if subscriber.StatusPageID == statusPageID && subscriber.Email == email {
+			found := *subscriber
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) FindByStatusPageID(ctx context.Context, statusPageID string, confirmedOnly bool) ([]*Model, error) {
+	var found []*Model
+	for _, subscriber := range r.subscribers {
+		if subscriber.StatusPageID == statusPageID && (subscriber.Confirmed || !confirmedOnly) {
+			found = append(found, subscriber)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryRepository) UpdateConfirmationSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	r.subscribers[id].ConfirmationSentAt = sentAt
+	return nil
+}
+
+func (r *memoryRepository) Confirm(ctx context.Context, id string, confirmedAt time.Time) error {
+	r.subscribers[id].Confirmed = true
+	r.subscribers[id].ConfirmedAt = &confirmedAt
+	return nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id string) error {
+	delete(r.subscribers, id)
+	return nil
+}
+
+type fakeStatusPages struct {
+	status_page.Service
+	pages          map[string]*status_page.Model
+	monitorsByPage map[string][]string
+}
+
+func (f *fakeStatusPages) FindByID(ctx context.Context, id string) (*status_page.Model, error) {
+	return f.pages[id], nil
+}
+
+func (f *fakeStatusPages) FindBySlug(ctx context.Context, slug string) (*status_page.Model, error) {
+	for _, page := range f.pages {
+		if page.Slug == slug {
+			return page, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStatusPages) GetMonitorsForStatusPage(ctx context.Context, statusPageID string) ([]*monitor_status_page.Model, error) {
+	var relations []*monitor_status_page.Model
+	for _, monitorID := range f.monitorsByPage[statusPageID] {
+		relations = append(relations, &monitor_status_page.Model{StatusPageID: statusPageID, MonitorID: monitorID, Active: true})
+	}
+	return relations, nil
+}
+
+type fakeStatusPageMonitors struct {
+	monitor_status_page.Service
+	pages *fakeStatusPages
+}
+
+func (f *fakeStatusPageMonitors) GetStatusPagesForMonitor(ctx context.Context, monitorID string) ([]*monitor_status_page.Model, error) {
+	var relations []*monitor_status_page.Model
+	for pageID, monitorIDs := range f.pages.monitorsByPage {
+		for _, id := range monitorIDs {
+			if id == monitorID {
+				relations = append(relations, &monitor_status_page.Model{StatusPageID: pageID, MonitorID: monitorID, Active: true})
+			}
+		}
+	}
+	return relations, nil
+}
+
+type fakeMonitors struct {
+	monitor.Service
+	monitors map[string]*monitor.Model
+}
+
+func (f *fakeMonitors) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return f.monitors[id], nil
+}
+
+type fakeHeartbeats struct {
+	heartbeat.Service
+	important map[string][]*heartbeat.Model
+}
+
+func (f *fakeHeartbeats) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	return f.important[monitorID], nil
+}
+
+type fakeChannels struct {
+	notification_channel.Service
+	channels map[string]*notification_channel.Model
+}
+
+func (f *fakeChannels) FindByID(ctx context.Context, id string) (*notification_channel.Model, error) {
+	return f.channels[id], nil
+}
+
+type sentEmail struct {
+	config map[string]any
+	body   string
+}
+
+type recordingEmailProvider struct {
+	sent []sentEmail
+}
+
+func (p *recordingEmailProvider) Send(ctx context.Context, configJSON, message string, m *monitor.Model, hb *heartbeat.Model) error {
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		return err
+	}
+	p.sent = append(p.sent, sentEmail{config: cfg, body: message})
+	return nil
+}
+
+func (p *recordingEmailProvider) TestSend(ctx context.Context, configJSON string, status heartbeat.MonitorStatus) error {
+	return nil
+}
+
+func (p *recordingEmailProvider) Validate(configJSON string) error { return nil }
+
+func (p *recordingEmailProvider) Unmarshal(configJSON string) (any, error) { return nil, nil }
+
+func newTestService(t *testing.T) (*ServiceImpl, *memoryRepository, *recordingEmailProvider) {
+	provider := &recordingEmailProvider{}
+	notification_channel.RegisterNotificationChannelProvider("smtp", provider)
+	t.Cleanup(func() { delete(notification_channel.NotificationChannelProviderRegistry, "smtp") })
+
+	smtpConfig := `{"smtp_host":"smtp.example.com","from":"status@example.com","to":"ops@example.com","bcc":"audit@example.com","custom_body":"{{ msg }}"}`
+	pages := &fakeStatusPages{
+		pages: map[string]*status_page.Model{
+			"public":   {ID: "public", Slug: "acme", Title: "Acme", Published: true, SubscriberChannelID: "mail"},
+			"internal": {ID: "internal", Slug: "internal", Title: "Internal", Published: true},
+			"draft":    {ID: "draft", Slug: "draft", Title: "Draft", SubscriberChannelID: "mail"},
+		},
+		monitorsByPage: map[string][]string{
+			"public":   {"api", "web"},
+			"internal": {"api"},
+		},
+	}
+
+	repo := &memoryRepository{subscribers: map[string]*Model{}}
+	svc := &ServiceImpl{
+		repository:               repo,
+		statusPageService:        pages,
+		monitorStatusPageService: &fakeStatusPageMonitors{pages: pages},
+		monitorService: &fakeMonitors{monitors: map[string]*monitor.Model{
+			"api": {ID: "api", Name: "API", Notes: "restart the pods"},
+			"web": {ID: "web", Name: "Website"},
+		}},
+		heartbeatService:           &fakeHeartbeats{important: map[string][]*heartbeat.Model{}},
+		notificationChannelService: &fakeChannels{channels: map[string]*notification_channel.Model{"mail": {ID: "mail", Name: "Mail", Type: "smtp", Config: &smtpConfig}}},
+		clientURL:                  "https://status.example.com",
+		logger:                     zap.NewNop().Sugar(),
+	}
+	return svc, repo, provider
+}
+
+func downHeartbeat() *heartbeat.Model {
+	return &heartbeat.Model{MonitorID: "api", Status: shared.MonitorStatusDown, Msg: "dial tcp 10.0.0.5:443: connection refused", Time: time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestSubscriberService_SubscribeConfirmUnsubscribe(t *testing.T) {
+	svc, repo, provider := newTestService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Subscribe(ctx, "acme", " Visitor@Example.com "))
+	require.Len(t, provider.sent, 1)
+	confirmation := provider.sent[0]
+	assert.Equal(t, "visitor@example.com", confirmation.config["to"])
+	assert.NotContains(t, confirmation.config, "bcc", "the bcc of the channel must not receive subscriber emails")
+	assert.NotContains(t, confirmation.config, "custom_body")
+
+	subscriber, _ := repo.FindByStatusPageAndEmail(ctx, "public", "visitor@example.com")
+	require.NotNil(t, subscriber)
+	assert.Contains(t, confirmation.body, "https://status.example.com/api/v1/status-pages/subscriptions/confirm?token="+subscriber.Token)
+
+	// pending subscribers are not notified
+	svc.NotifyStatusChange(ctx, downHeartbeat())
+	require.Len(t, provider.sent, 1)
+
+	page, err := svc.Confirm(ctx, subscriber.Token)
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Equal(t, "acme", page.Slug)
+
+	svc.NotifyStatusChange(ctx, downHeartbeat())
+	require.Len(t, provider.sent, 2)
+	notification := provider.sent[1]
+	assert.Equal(t, "[Acme] API is DOWN", notification.config["custom_subject"])
+	assert.Contains(t, notification.body, "https://status.example.com/status/acme")
+	assert.Contains(t, notification.body, "https://status.example.com/api/v1/status-pages/subscriptions/unsubscribe?token="+subscriber.Token)
+	assert.NotContains(t, notification.body, "connection refused", "the heartbeat message is internal")
+	assert.NotContains(t, notification.body, "restart the pods", "the monitor notes are internal")
+
+	page, err = svc.Unsubscribe(ctx, subscriber.Token)
+	require.NoError(t, err)
+	require.NotNil(t, page)
+
+	svc.NotifyStatusChange(ctx, downHeartbeat())
+	assert.Len(t, provider.sent, 2)
+
+	page, err = svc.Unsubscribe(ctx, subscriber.Token)
+	require.NoError(t, err)
+	assert.Nil(t, page, "the token is gone once unsubscribed")
+}
+
+func TestSubscriberService_ConfirmationResendIsThrottled(t *testing.T) {
+	svc, repo, provider := newTestService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Subscribe(ctx, "acme", "visitor@example.com"))
+	require.NoError(t, svc.Subscribe(ctx, "acme", "visitor@example.com"))
+	require.Len(t, provider.sent, 1)
+
+	subscriber, _ := repo.FindByStatusPageAndEmail(ctx, "public", "visitor@example.com")
+	repo.subscribers[subscriber.ID].ConfirmationSentAt = time.Now().UTC().Add(-confirmationResendInterval - time.Minute)
+	require.NoError(t, svc.Subscribe(ctx, "acme", "visitor@example.com"))
+	require.Len(t, provider.sent, 2)
+	assert.Len(t, repo.subscribers, 1)
+
+	// a confirmed address is not emailed again
+	_, err := svc.Confirm(ctx, subscriber.Token)
+	require.NoError(t, err)
+	repo.subscribers[subscriber.ID].ConfirmationSentAt = time.Time{}
+	require.NoError(t, svc.Subscribe(ctx, "acme", "visitor@example.com"))
+	assert.Len(t, provider.sent, 2)
+}
+
+func TestSubscriberService_SubscribeRejectsPagesWithoutSubscriptions(t *testing.T) {
+	svc, repo, provider := newTestService(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.Subscribe(ctx, "internal", "visitor@example.com"), ErrSubscriptionsDisabled)
+	assert.ErrorIs(t, svc.Subscribe(ctx, "draft", "visitor@example.com"), ErrStatusPageNotFound)
+	assert.ErrorIs(t, svc.Subscribe(ctx, "missing", "visitor@example.com"), ErrStatusPageNotFound)
+	assert.Empty(t, repo.subscribers)
+	assert.Empty(t, provider.sent)
+}
+
+func TestSubscriberService_DeleteChecksStatusPage(t *testing.T) {
+	svc, repo, _ := newTestService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Subscribe(ctx, "acme", "visitor@example.com"))
+	subscriber, _ := repo.FindByStatusPageAndEmail(ctx, "public", "visitor@example.com")
+
+	found, err := svc.Delete(ctx, "internal", subscriber.ID)
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Len(t, repo.subscribers, 1)
+
+	found, err = svc.Delete(ctx, "public", subscriber.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, repo.subscribers)
+}
+
+func TestSubscriberService_Feed(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	at := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	svc.heartbeatService = &fakeHeartbeats{important: map[string][]*heartbeat.Model{
+		"api": {
+			{ID: "hb3", MonitorID: "api", Status: shared.MonitorStatusUp, Time: at.Add(time.Hour)},
+			{ID: "hb1", MonitorID: "api", Status: shared.MonitorStatusDown, Msg: "connection refused", Time: at},
+		},
+		"web": {
+			{ID: "hb2", MonitorID: "web", Status: shared.MonitorStatusDown, Time: at.Add(30 * time.Minute)},
+		},
+	}}
+
+	feed, err := svc.Feed(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", feed.Title)
+	assert.Equal(t, "2025-07-20T13:00:00Z", feed.Updated)
+
+	titles := make([]string, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		titles = append(titles, entry.Title)
+	}
+	assert.Equal(t, []string{"API is UP", "Website is DOWN", "API is DOWN"}, titles)
+	assert.Equal(t, "https://status.example.com/status/acme#hb3", feed.Entries[0].ID)
+
+	body, err := xml.Marshal(feed)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), `<feed xmlns="http://www.w3.org/2005/Atom">`))
+	assert.NotContains(t, string(body), "connection refused")
+
+	_, err = svc.Feed(ctx, "draft")
+	assert.ErrorIs(t, err, ErrStatusPageNotFound)
+}
@@ -0,0 +1,129 @@
+package status_page_subscriber
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:status_page_subscribers,alias:sps"`
+
+	ID                 string     `bun:"id,pk"`
+	StatusPageID       string     `bun:"status_page_id,notnull"`
+	Email              string     `bun:"email,notnull"`
+	Token              string     `bun:"token,notnull,unique"`
+	Confirmed          bool       `bun:"confirmed,notnull,default:false"`
+	ConfirmationSentAt time.Time  `bun:"confirmation_sent_at,notnull"`
+	ConfirmedAt        *time.Time `bun:"confirmed_at"`
+	CreatedAt          time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:                 sm.ID,
+		StatusPageID:       sm.StatusPageID,
+		Email:              sm.Email,
+		Token:              sm.Token,
+		Confirmed:          sm.Confirmed,
+		ConfirmationSentAt: sm.ConfirmationSentAt,
+		ConfirmedAt:        sm.ConfirmedAt,
+		CreatedAt:          sm.CreatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	sm := &sqlModel{
+		ID:                 uuid.New().String(),
+		StatusPageID:       model.StatusPageID,
+		Email:              model.Email,
+		Token:              model.Token,
+		Confirmed:          model.Confirmed,
+		ConfirmationSentAt: model.ConfirmationSentAt,
+		ConfirmedAt:        model.ConfirmedAt,
+		CreatedAt:          time.Now().UTC(),
+	}
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) findOne(ctx context.Context, where string, args ...any) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where(where, args...).Limit(1).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *SQLRepositoryImpl) FindByToken(ctx context.Context, token string) (*Model, error) {
+	return r.findOne(ctx, "token = ?", token)
+}
+
+func (r *SQLRepositoryImpl) FindByStatusPageAndEmail(ctx context.Context, statusPageID, email string) (*Model, error) {
+	return r.findOne(ctx, "status_page_id = ? AND email = ?", statusPageID, email)
+}
+
+func (r *SQLRepositoryImpl) FindByStatusPageID(ctx context.Context, statusPageID string, confirmedOnly bool) ([]*Model, error) {
+	query := r.db.NewSelect().Model((*sqlModel)(nil)).Where("status_page_id = ?", statusPageID)
+	if confirmedOnly {
+		query = query.Where("confirmed = ?", true)
+	}
+
+	var sms []*sqlModel
+	if err := query.Order("created_at ASC").Scan(ctx, &sms); err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) UpdateConfirmationSentAt(ctx context.Context, id string, sentAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("confirmation_sent_at = ?", sentAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) Confirm(ctx context.Context, id string, confirmedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("confirmed = ?", true).
+		Set("confirmed_at = ?", confirmedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
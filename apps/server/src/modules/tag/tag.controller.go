@@ -3,6 +3,7 @@ package tag
 import (
 	"net/http"
 	"peekaping/src/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -227,3 +228,67 @@ func (c *Controller) Delete(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Tag deleted successfully", nil))
 }
+
+// @Router		/tags/{id}/uptime [get]
+// @Summary		Get the combined uptime of the monitors with a tag
+// @Description	method: average (mean of the monitor uptimes), weighted (up checks over all checks), worst (lowest monitor uptime) or all_up (share of time buckets in which no monitor was down)
+// @Tags			Tags
+// @Produce		json
+// @Security BearerAuth
+// @Param       id     path      string  true   "Tag ID"
+// @Param       since  query     string  false  "Start time (RFC3339), defaults to 24 hours before until"
+// @Param       until  query     string  false  "End time (RFC3339), defaults to now"
+// @Param       method query     string  false  "Aggregation method" Enums(average, weighted, worst, all_up) default(average)
+// @Success		200	{object}	utils.ApiResponse[AggregateUptimeDto]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) GetAggregateUptime(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	until := time.Now().UTC()
+	if untilStr := ctx.Query("until"); untilStr != "" {
+		parsed, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'until' parameter (must be RFC3339)"))
+			return
+		}
+		until = parsed
+	}
+
+	since := until.Add(-24 * time.Hour)
+	if sinceStr := ctx.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'since' parameter (must be RFC3339)"))
+			return
+		}
+		since = parsed
+	}
+	if !since.Before(until) {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("'since' must be before 'until'"))
+		return
+	}
+
+	method := UptimeAggregation(ctx.DefaultQuery("method", string(UptimeAverage)))
+	switch method {
+	case UptimeAverage, UptimeWeighted, UptimeWorst, UptimeAllUp:
+	default:
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'method' parameter (must be average, weighted, worst or all_up)"))
+		return
+	}
+
+	uptime, err := c.service.AggregateUptime(ctx, id, since, until, method)
+	if err != nil {
+		c.logger.Errorw("Failed to aggregate tag uptime", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if uptime == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Tag not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", uptime))
+}
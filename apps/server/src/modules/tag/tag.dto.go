@@ -1,5 +1,7 @@
 package tag
 
+import "time"
+
 type CreateUpdateDto struct {
 	Name        string  `json:"name" validate:"required,min=1,max=100" example:"Production"`
 	Color       string  `json:"color" validate:"required,hexcolor" example:"#3B82F6"`
@@ -11,3 +13,38 @@ type PartialUpdateDto struct {
 	Color       *string `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#3B82F6"`
 	Description *string `json:"description,omitempty" example:"Production environment monitors"`
 }
+
+// UptimeAggregation is how the uptime of the monitors of a tag is combined
+type UptimeAggregation string
+
+const (
+	// UptimeAverage is the mean of the monitor uptimes, every monitor weighs the same
+	UptimeAverage UptimeAggregation = "average"
+	// UptimeWeighted is the share of up checks across all monitors, monitors
+	// checked more often weigh more
+	UptimeWeighted UptimeAggregation = "weighted"
+	// UptimeWorst is the uptime of the least available monitor
+	UptimeWorst UptimeAggregation = "worst"
+	// UptimeAllUp is the share of time buckets in which no monitor was down
+	UptimeAllUp UptimeAggregation = "all_up"
+)
+
+type MonitorUptimeDto struct {
+	MonitorID string `json:"monitor_id" example:"60c72b2f9b1e8b6f1f8e4b1a"`
+	// nil when the monitor has no checks in the window
+	Uptime *float64 `json:"uptime" example:"99.95"`
+	Up     int      `json:"up" example:"1438"`
+	Down   int      `json:"down" example:"2"`
+}
+
+type AggregateUptimeDto struct {
+	TagID  string            `json:"tag_id" example:"60c72b2f9b1e8b6f1f8e4b1b"`
+	Method UptimeAggregation `json:"method" example:"average"`
+	Since  time.Time         `json:"since"`
+	Until  time.Time         `json:"until"`
+	// Resolution of the all_up buckets: minutely, hourly or daily
+	Period string `json:"period" example:"minutely"`
+	// nil when no monitor of the tag has checks in the window
+	Uptime   *float64            `json:"uptime" example:"99.9"`
+	Monitors []*MonitorUptimeDto `json:"monitors"`
+}
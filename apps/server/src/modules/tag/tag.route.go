@@ -32,6 +32,7 @@ func (r *Route) ConnectRoute(
 	router.GET("", controller.FindAll)
 	router.POST("", controller.Create)
 	router.GET("/:id", controller.FindByID)
+	router.GET("/:id/uptime", controller.GetAggregateUptime)
 	router.PUT("/:id", controller.UpdateFull)
 	router.PATCH("/:id", controller.UpdatePartial)
 	router.DELETE("/:id", controller.Delete)
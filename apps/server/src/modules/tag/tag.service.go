@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/stats"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -16,22 +18,28 @@ type Service interface {
 	UpdatePartial(ctx context.Context, id string, entity *PartialUpdateDto) (*Model, error)
 	Delete(ctx context.Context, id string) error
 	FindByName(ctx context.Context, name string) (*Model, error)
+	// AggregateUptime combines the uptime of every monitor carrying the tag
+	// between since and until, nil when the tag does not exist
+	AggregateUptime(ctx context.Context, id string, since, until time.Time, method UptimeAggregation) (*AggregateUptimeDto, error)
 }
 
 type ServiceImpl struct {
 	repository        Repository
 	monitorTagService monitor_tag.Service
+	statsService      stats.Service
 	logger            *zap.SugaredLogger
 }
 
 func NewService(
 	repository Repository,
 	monitorTagService monitor_tag.Service,
+	statsService stats.Service,
 	logger *zap.SugaredLogger,
 ) Service {
 	return &ServiceImpl{
 		repository,
 		monitorTagService,
+		statsService,
 		logger.Named("[tag-service]"),
 	}
 }
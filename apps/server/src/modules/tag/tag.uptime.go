@@ -0,0 +1,114 @@
+package tag
+
+import (
+	"context"
+	"peekaping/src/modules/stats"
+	"time"
+)
+
+// maxUptimeBuckets bounds the stats read per monitor. Windows up to a day are
+// aggregated per minute, up to 60 days per hour and longer ones per day.
+const maxUptimeBuckets = 1440
+
+// aggregateUptime combines the stats of every monitor of a tag. Maintenance
+// counts as up, as it does for the uptime of a single monitor. Monitors
+// without checks in the window are listed but do not count.
+func aggregateUptime(method UptimeAggregation, statsByMonitor map[string][]*stats.Stat, monitorIDs []string) (*float64, []*MonitorUptimeDto) {
+	monitors := make([]*MonitorUptimeDto, 0, len(monitorIDs))
+	var sum, worst float64
+	var counted, totalUp, totalChecks int
+
+	// bucket timestamp -> whether every monitor with checks in it stayed up
+	buckets := make(map[int64]bool)
+
+	for _, id := range monitorIDs {
+		entry := &MonitorUptimeDto{MonitorID: id}
+		for _, s := range statsByMonitor[id] {
+			entry.Up += s.Up
+			entry.Down += s.Down
+			if s.Up+s.Down == 0 {
+				continue
+			}
+			key := s.Timestamp.Unix()
+			up, seen := buckets[key]
+			buckets[key] = (up || !seen) && s.Down == 0
+		}
+		monitors = append(monitors, entry)
+
+		checks := entry.Up + entry.Down
+		if checks == 0 {
+			continue
+		}
+		uptime := float64(entry.Up) / float64(checks) * 100
+		entry.Uptime = &uptime
+
+		if counted == 0 || uptime < worst {
+			worst = uptime
+		}
+		sum += uptime
+		counted++
+		totalUp += entry.Up
+		totalChecks += checks
+	}
+
+	if counted == 0 {
+		return nil, monitors
+	}
+
+	var result float64
+	switch method {
+	case UptimeWeighted:
+		result = float64(totalUp) / float64(totalChecks) * 100
+	case UptimeWorst:
+		result = worst
+	case UptimeAllUp:
+		upBuckets := 0
+		for _, up := range buckets {
+			if up {
+				upBuckets++
+			}
+		}
+		result = float64(upBuckets) / float64(len(buckets)) * 100
+	default:
+		result = sum / float64(counted)
+	}
+	return &result, monitors
+}
+
+func (s *ServiceImpl) AggregateUptime(ctx context.Context, id string, since, until time.Time, method UptimeAggregation) (*AggregateUptimeDto, error) {
+	tag, err := s.repository.FindByID(ctx, id)
+	if err != nil || tag == nil {
+		return nil, err
+	}
+
+	monitorTags, err := s.monitorTagService.FindByTagID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	period := stats.PeriodForRange(since, until, maxUptimeBuckets)
+	monitorIDs := make([]string, 0, len(monitorTags))
+	statsByMonitor := make(map[string][]*stats.Stat, len(monitorTags))
+	for _, mt := range monitorTags {
+		if _, ok := statsByMonitor[mt.MonitorID]; ok {
+			continue
+		}
+		monitorStats, err := s.statsService.FindStatsByMonitorIDAndTimeRange(ctx, mt.MonitorID, since, until, period)
+		if err != nil {
+			return nil, err
+		}
+		monitorIDs = append(monitorIDs, mt.MonitorID)
+		statsByMonitor[mt.MonitorID] = monitorStats
+	}
+
+	uptime, monitors := aggregateUptime(method, statsByMonitor, monitorIDs)
+	return &AggregateUptimeDto{
+		TagID:    id,
+		Method:   method,
+		Since:    since,
+		Until:    until,
+		Period:   string(period),
+		Uptime:   uptime,
+		Monitors: monitors,
+	}, nil
+}
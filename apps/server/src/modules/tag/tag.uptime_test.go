@@ -0,0 +1,138 @@
+package tag
+
+import (
+	"context"
+	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/stats"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeTagRepository struct {
+	Repository
+	tags map[string]*Model
+}
+
+func (r *fakeTagRepository) FindByID(ctx context.Context, id string) (*Model, error) {
+	return r.tags[id], nil
+}
+
+type fakeMonitorTagService struct {
+	monitor_tag.Service
+	byTag map[string][]string
+}
+
+func (s *fakeMonitorTagService) FindByTagID(ctx context.Context, tagID string) ([]*monitor_tag.Model, error) {
+	var models []*monitor_tag.Model
+	for _, monitorID := range s.byTag[tagID] {
+		models = append(models, &monitor_tag.Model{MonitorID: monitorID, TagID: tagID})
+	}
+	return models, nil
+}
+
+// fakeStatsService serves fixed stats per monitor and remembers the period asked for
+type fakeStatsService struct {
+	stats.Service
+	byMonitor map[string][]*stats.Stat
+	period    stats.StatPeriod
+}
+
+func (s *fakeStatsService) FindStatsByMonitorIDAndTimeRange(ctx context.Context, monitorID string, since, until time.Time, period stats.StatPeriod) ([]*stats.Stat, error) {
+	s.period = period
+	return s.byMonitor[monitorID], nil
+}
+
+var uptimeStart = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+// hourly builds one stat per hour from up/down pairs
+func hourly(monitorID string, counts ...[2]int) []*stats.Stat {
+	var result []*stats.Stat
+	for i, c := range counts {
+		result = append(result, &stats.Stat{MonitorID: monitorID, Timestamp: uptimeStart.Add(time.Duration(i) * time.Hour), Up: c[0], Down: c[1]})
+	}
+	return result
+}
+
+// three API monitors: gateway always up, search down 10% of the checks in
+// the first hour, billing checked half as often and down in the second hour
+func newUptimeTestService() (*ServiceImpl, *fakeStatsService) {
+	statsService := &fakeStatsService{byMonitor: map[string][]*stats.Stat{
+		"gateway": hourly("gateway", [2]int{60, 0}, [2]int{60, 0}),
+		"search":  hourly("search", [2]int{48, 12}, [2]int{60, 0}),
+		"billing": hourly("billing", [2]int{30, 0}, [2]int{15, 15}),
+	}}
+	service := &ServiceImpl{
+		repository:        &fakeTagRepository{tags: map[string]*Model{"api": {ID: "api", Name: "api"}}},
+		monitorTagService: &fakeMonitorTagService{byTag: map[string][]string{"api": {"gateway", "search", "billing", "unchecked"}}},
+		statsService:      statsService,
+		logger:            zap.NewNop().Sugar(),
+	}
+	return service, statsService
+}
+
+func TestAggregateUptime_Methods(t *testing.T) {
+	service, _ := newUptimeTestService()
+
+	tests := []struct {
+		method UptimeAggregation
+		want   float64
+	}{
+		// (100 + 90 + 75) / 3
+		{method: UptimeAverage, want: 88.333},
+		// (120 + 108 + 45) / (120 + 120 + 60)
+		{method: UptimeWeighted, want: 91},
+		{method: UptimeWorst, want: 75},
+		// search is down in the first hour, billing in the second
+		{method: UptimeAllUp, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			result, err := service.AggregateUptime(context.Background(), "api", uptimeStart, uptimeStart.Add(2*time.Hour), tt.method)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotNil(t, result.Uptime)
+			assert.InDelta(t, tt.want, *result.Uptime, 0.01)
+			assert.Equal(t, tt.method, result.Method)
+		})
+	}
+}
+
+func TestAggregateUptime_AllUpCountsBucketsWithoutOutage(t *testing.T) {
+	service, statsService := newUptimeTestService()
+	statsService.byMonitor["billing"] = hourly("billing", [2]int{30, 0}, [2]int{30, 0}, [2]int{30, 0})
+
+	result, err := service.AggregateUptime(context.Background(), "api", uptimeStart, uptimeStart.Add(3*time.Hour), UptimeAllUp)
+	require.NoError(t, err)
+	require.NotNil(t, result.Uptime)
+	// only the first of three hours had an outage
+	assert.InDelta(t, 66.67, *result.Uptime, 0.01)
+}
+
+func TestAggregateUptime_MonitorsWithoutChecks(t *testing.T) {
+	service, statsService := newUptimeTestService()
+
+	result, err := service.AggregateUptime(context.Background(), "api", uptimeStart, uptimeStart.Add(2*time.Hour), UptimeWorst)
+	require.NoError(t, err)
+	require.Len(t, result.Monitors, 4)
+	assert.Equal(t, "unchecked", result.Monitors[3].MonitorID)
+	assert.Nil(t, result.Monitors[3].Uptime)
+	assert.Equal(t, stats.StatMinutely, statsService.period)
+
+	statsService.byMonitor = nil
+	result, err = service.AggregateUptime(context.Background(), "api", uptimeStart, uptimeStart.Add(2*time.Hour), UptimeAverage)
+	require.NoError(t, err)
+	assert.Nil(t, result.Uptime)
+}
+
+func TestAggregateUptime_UnknownTag(t *testing.T) {
+	service, _ := newUptimeTestService()
+
+	result, err := service.AggregateUptime(context.Background(), "missing", uptimeStart, uptimeStart.Add(time.Hour), UptimeAverage)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -4,14 +4,22 @@ import (
 	"net/http"
 	"peekaping/src/config"
 	"peekaping/src/modules/auth"
+	"peekaping/src/modules/blob"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/client_cert"
 	"peekaping/src/modules/healthcheck"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/metrics"
 	"peekaping/src/modules/monitor"
 	"peekaping/src/modules/notification_channel"
+	"peekaping/src/modules/notification_failure"
 	"peekaping/src/modules/proxy"
+	"peekaping/src/modules/report"
 	"peekaping/src/modules/setting"
 	"peekaping/src/modules/status_page"
+	"peekaping/src/modules/status_page_incident"
+	"peekaping/src/modules/status_page_subscriber"
 	"peekaping/src/modules/tag"
 	"peekaping/src/modules/websocket"
 	"peekaping/src/version"
@@ -61,10 +69,18 @@ func ProvideServer(
 	wsServer *websocket.Server,
 	notificationChannelRoute *notification_channel.Route,
 	notificationChannelController *notification_channel.Controller,
+	notificationFailureRoute *notification_failure.Route,
+	notificationFailureController *notification_failure.Controller,
 	proxyRoute *proxy.Route,
 	proxyController *proxy.Controller,
+	certificateRoute *certificate.Route,
+	certificateController *certificate.Controller,
+	blobRoute *blob.Route,
+	blobController *blob.Controller,
 	settingRoute *setting.Route,
 	settingController *setting.Controller,
+	clientCertRoute *client_cert.Route,
+	clientCertController *client_cert.Controller,
 	heartbeatService heartbeat.Service,
 	monitorService monitor.Service,
 	healthcheckSupervisor *healthcheck.HealthCheckSupervisor,
@@ -72,8 +88,15 @@ func ProvideServer(
 	maintenanceController *maintenance.Controller,
 	statusPageRoute *status_page.Route,
 	statusPageController *status_page.Controller,
+	statusPageSubscriberRoute *status_page_subscriber.Route,
+	statusPageSubscriberController *status_page_subscriber.Controller,
+	statusPageIncidentRoute *status_page_incident.Route,
+	statusPageIncidentController *status_page_incident.Controller,
 	tagRoute *tag.Route,
 	tagController *tag.Controller,
+	metricsRoute *metrics.Route,
+	reportRoute *report.Route,
+	reportController *report.Controller,
 ) *Server {
 	server := gin.Default()
 	// server := gin.New()
@@ -92,6 +115,7 @@ func ProvideServer(
 	// server.Use(LogMiddleware(logger))
 
 	server.GET("/health", healthHandler)
+	metricsRoute.ConnectRoute(server)
 	router := server.Group("/api/v1")
 	router.GET("/health", healthHandler)
 	router.GET("/version", versionHandler)
@@ -100,14 +124,22 @@ func ProvideServer(
 	monitorRoute.ConnectRoute(router, monitorController)
 	authRoute.ConnectRoute(router, authController)
 	notificationChannelRoute.ConnectRoute(router, notificationChannelController)
+	notificationFailureRoute.ConnectRoute(router, notificationFailureController)
 	proxyRoute.ConnectRoute(router, proxyController)
+	certificateRoute.ConnectRoute(router, certificateController)
+	blobRoute.ConnectRoute(router, blobController)
 	settingRoute.ConnectRoute(router, settingController)
+	clientCertRoute.ConnectRoute(router, clientCertController)
 	maintenanceRoute.ConnectRoute(router, maintenanceController)
 	statusPageRoute.ConnectRoute(router, statusPageController)
+	statusPageSubscriberRoute.ConnectRoute(router, statusPageSubscriberController)
+	statusPageIncidentRoute.ConnectRoute(router, statusPageIncidentController)
 	tagRoute.ConnectRoute(router, tagController)
+	reportRoute.ConnectRoute(router, reportController)
 
 	// Register push endpoint
 	healthcheck.RegisterPushEndpoint(router, monitorService, heartbeatService, healthcheckSupervisor, logger)
+	healthcheck.RegisterAlertmanagerEndpoint(router, monitorService, healthcheckSupervisor, cfg, logger)
 
 	// Swagger routes
 	url := ginSwagger.URL("/swagger/doc.json")
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP connection security modes
+const (
+	// SMTPSecurityNone talks plain SMTP, credentials are only sent to localhost
+	SMTPSecurityNone = "none"
+	// SMTPSecurityStartTLS upgrades the plain connection and fails when the
+	// server does not offer STARTTLS
+	SMTPSecurityStartTLS = "starttls"
+	// SMTPSecurityTLS connects over TLS from the start, usually port 465
+	SMTPSecurityTLS = "tls"
+)
+
+// DialSMTP connects to host:port and greets the server, upgrading the
+// connection according to security. tlsConfig may be nil, its ServerName
+// defaults to host. The dial is bounded by ctx and timeout, which also bounds
+// the whole session.
+func DialSMTP(ctx context.Context, host string, port int, security string, tlsConfig *tls.Config, timeout time.Duration) (*smtp.Client, error) {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	switch security {
+	case SMTPSecurityTLS:
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	case SMTPSecurityNone, SMTPSecurityStartTLS:
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	default:
+		return nil, fmt.Errorf("unknown SMTP security mode: %s", security)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP greeting failed: %w", err)
+	}
+
+	if security == SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("SMTP server %s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	return client, nil
+}
@@ -48,6 +48,7 @@ import {
   getStatusPages,
   postStatusPages,
   getStatusPagesSlugBySlug,
+  getStatusPagesSlugBySlugIncidents,
   getStatusPagesSlugBySlugMonitors,
   getStatusPagesSlugBySlugMonitorsHomepage,
   deleteStatusPagesById,
@@ -186,6 +187,7 @@ import type {
   PostStatusPagesError,
   PostStatusPagesResponse,
   GetStatusPagesSlugBySlugData,
+  GetStatusPagesSlugBySlugIncidentsData,
   GetStatusPagesSlugBySlugMonitorsData,
   GetStatusPagesSlugBySlugMonitorsHomepageData,
   DeleteStatusPagesByIdData,
@@ -2077,6 +2079,30 @@ export const getStatusPagesSlugBySlugOptions = (
   });
 };
 
+export const getStatusPagesSlugBySlugIncidentsQueryKey = (
+  options: Options<GetStatusPagesSlugBySlugIncidentsData>,
+) => createQueryKey("getStatusPagesSlugBySlugIncidents", options);
+
+/**
+ * Get the incident timeline of a status page
+ */
+export const getStatusPagesSlugBySlugIncidentsOptions = (
+  options: Options<GetStatusPagesSlugBySlugIncidentsData>,
+) => {
+  return queryOptions({
+    queryFn: async ({ queryKey, signal }) => {
+      const { data } = await getStatusPagesSlugBySlugIncidents({
+        ...options,
+        ...queryKey[0],
+        signal,
+        throwOnError: true,
+      });
+      return data;
+    },
+    queryKey: getStatusPagesSlugBySlugIncidentsQueryKey(options),
+  });
+};
+
 export const getStatusPagesSlugBySlugMonitorsQueryKey = (
   options: Options<GetStatusPagesSlugBySlugMonitorsData>,
 ) => createQueryKey("getStatusPagesSlugBySlugMonitors", options);
@@ -139,6 +139,9 @@ import type {
   GetStatusPagesSlugBySlugData,
   GetStatusPagesSlugBySlugResponses,
   GetStatusPagesSlugBySlugErrors,
+  GetStatusPagesSlugBySlugIncidentsData,
+  GetStatusPagesSlugBySlugIncidentsResponses,
+  GetStatusPagesSlugBySlugIncidentsErrors,
   GetStatusPagesSlugBySlugMonitorsData,
   GetStatusPagesSlugBySlugMonitorsResponses,
   GetStatusPagesSlugBySlugMonitorsErrors,
@@ -1305,6 +1308,25 @@ export const getStatusPagesSlugBySlug = <ThrowOnError extends boolean = false>(
   });
 };
 
+/**
+ * Get the incident timeline of a status page
+ */
+export const getStatusPagesSlugBySlugIncidents = <
+  ThrowOnError extends boolean = false,
+>(
+  options: Options<GetStatusPagesSlugBySlugIncidentsData, ThrowOnError>,
+) => {
+  return (options.client ?? _heyApiClient).get<
+    GetStatusPagesSlugBySlugIncidentsResponses,
+    GetStatusPagesSlugBySlugIncidentsErrors,
+    ThrowOnError
+  >({
+    responseType: "json",
+    url: "/status-pages/slug/{slug}/incidents",
+    ...options,
+  });
+};
+
 /**
  * Get monitors for a status page by slug with heartbeats and uptime
  */
@@ -86,6 +86,10 @@ export type HeartbeatModel = {
   notified?: boolean;
   ping?: number;
   retries?: number;
+  /**
+   * Latency distribution of the probes, set when the check took several samples
+   */
+  samples?: SharedHeartBeatSamples;
   status?: SharedMonitorStatus;
   time?: string;
 };
@@ -360,6 +364,16 @@ export type SettingModel = {
   value?: string;
 };
 
+export type SharedHeartBeatSamples = {
+  avg_ms?: number;
+  count?: number;
+  max_ms?: number;
+  median_ms?: number;
+  min_ms?: number;
+  p95_ms?: number;
+  up?: number;
+};
+
 export type SharedMonitorStatus = 0 | 1 | 2 | 3;
 
 export type StatusPageCreateStatusPageDto = {
@@ -395,6 +409,32 @@ export type StatusPageModel = {
   updated_at?: string;
 };
 
+export type StatusPageIncidentModel = {
+  created_at?: string;
+  id?: string;
+  /**
+   * MonitorIDs are the affected monitors, all of them shown on the page
+   */
+  monitor_ids?: Array<string>;
+  resolved_at?: string;
+  status?: string;
+  status_page_id?: string;
+  title?: string;
+  updated_at?: string;
+  /**
+   * Updates is the timeline of the incident, newest first
+   */
+  updates?: Array<StatusPageIncidentUpdate>;
+};
+
+export type StatusPageIncidentUpdate = {
+  created_at?: string;
+  id?: string;
+  incident_id?: string;
+  message?: string;
+  status?: string;
+};
+
 export type StatusPageMonitorWithHeartbeatsAndUptimeDto = {
   active?: boolean;
   heartbeats?: Array<StatusPagePublicHeartbeatDto>;
@@ -509,6 +549,11 @@ export type UtilsApiResponseArrayProxyModel = {
   message: string;
 };
 
+export type UtilsApiResponseArrayStatusPageIncidentModel = {
+  data: Array<StatusPageIncidentModel>;
+  message: string;
+};
+
 export type UtilsApiResponseArrayStatusPageModel = {
   data: Array<StatusPageModel>;
   message: string;
@@ -2420,6 +2465,55 @@ export type GetStatusPagesSlugBySlugResponses = {
 export type GetStatusPagesSlugBySlugResponse =
   GetStatusPagesSlugBySlugResponses[keyof GetStatusPagesSlugBySlugResponses];
 
+export type GetStatusPagesSlugBySlugIncidentsData = {
+  body?: never;
+  path: {
+    /**
+     * Status Page Slug
+     */
+    slug: string;
+  };
+  query?: {
+    /**
+     * Page number
+     */
+    page?: number;
+    /**
+     * Items per page
+     */
+    limit?: number;
+  };
+  url: "/status-pages/slug/{slug}/incidents";
+};
+
+export type GetStatusPagesSlugBySlugIncidentsErrors = {
+  /**
+   * Bad Request
+   */
+  400: UtilsApiError;
+  /**
+   * Not Found
+   */
+  404: UtilsApiError;
+  /**
+   * Internal Server Error
+   */
+  500: UtilsApiError;
+};
+
+export type GetStatusPagesSlugBySlugIncidentsError =
+  GetStatusPagesSlugBySlugIncidentsErrors[keyof GetStatusPagesSlugBySlugIncidentsErrors];
+
+export type GetStatusPagesSlugBySlugIncidentsResponses = {
+  /**
+   * OK
+   */
+  200: UtilsApiResponseArrayStatusPageIncidentModel;
+};
+
+export type GetStatusPagesSlugBySlugIncidentsResponse =
+  GetStatusPagesSlugBySlugIncidentsResponses[keyof GetStatusPagesSlugBySlugIncidentsResponses];
+
 export type GetStatusPagesSlugBySlugMonitorsData = {
   body?: never;
   path: {
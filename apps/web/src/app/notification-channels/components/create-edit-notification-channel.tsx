@@ -30,6 +30,7 @@ import * as NtfyForm from "../integrations/ntfy-form";
 import * as PagerDutyForm from "../integrations/pagerduty-form";
 import * as OpsgenieForm from "../integrations/opsgenie-form";
 import * as GoogleChatForm from "../integrations/google-chat-form";
+import * as TeamsForm from "../integrations/teams-form";
 import * as GrafanaOncallForm from "../integrations/grafana-oncall-form";
 import * as SignalForm from "../integrations/signal-form";
 import * as GotifyForm from "../integrations/gotify-form";
@@ -49,6 +50,7 @@ const typeFormRegistry = {
   pagerduty: PagerDutyForm,
   opsgenie: OpsgenieForm,
   google_chat: GoogleChatForm,
+  teams: TeamsForm,
   grafana_oncall: GrafanaOncallForm,
   signal: SignalForm,
   gotify: GotifyForm,
@@ -74,6 +76,7 @@ const notificationSchema = z
       PagerDutyForm.schema,
       OpsgenieForm.schema,
       GoogleChatForm.schema,
+      TeamsForm.schema,
       GrafanaOncallForm.schema,
       SignalForm.schema,
       GotifyForm.schema,
@@ -194,6 +197,7 @@ export default function CreateEditNotificationChannel({
                     | "pagerduty"
                     | "signal"
                     | "google_chat"
+                    | "teams"
                     | "grafana_oncall"
                     | "opsgenie"
                     | "gotify"
@@ -23,6 +23,7 @@ export const schema = z.object({
   type: z.literal("discord"),
   webhook_url: z.string().url({ message: "Valid webhook URL is required" }),
   bot_display_name: z.string().min(1, { message: "Bot display name is required" }),
+  bot_avatar_url: z.string().url({ message: "Valid avatar URL is required" }).optional().or(z.literal("")),
   custom_message_prefix: z.string().optional(),
   message_type: z.enum(["send_to_channel", "send_to_new_forum_post", "send_to_thread"], { message: "Message type is required" }),
   thread_name: z.string().optional(),
@@ -35,6 +36,7 @@ export const defaultValues: DiscordFormValues = {
   type: "discord",
   webhook_url: "",
   bot_display_name: "Peekaping",
+  bot_avatar_url: "",
   custom_message_prefix: "",
   message_type: "send_to_channel",
   thread_name: "",
@@ -101,6 +103,26 @@ export default function DiscordForm() {
         )}
       />
 
+      <FormField
+        control={form.control}
+        name="bot_avatar_url"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>Bot Avatar URL</FormLabel>
+            <FormControl>
+              <Input
+                placeholder="https://example.com/avatar.png"
+                {...field}
+              />
+            </FormControl>
+            <FormDescription>
+              Optional image shown as the avatar of the Discord messages.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+
       <FormField
         control={form.control}
         name="custom_message_prefix"
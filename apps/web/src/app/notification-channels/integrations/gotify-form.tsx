@@ -23,6 +23,7 @@ export const schema = z.object({
   server_url: z.string().url({ message: "Valid server URL is required" }),
   application_token: z.string().min(1, { message: "Application token is required" }),
   priority: z.coerce.number().min(0).max(10).optional(),
+  down_priority: z.coerce.number().min(0).max(10).optional(),
   title: z.string().optional(),
   custom_message: z.string().optional(),
 });
@@ -32,6 +33,7 @@ export const defaultValues = {
   server_url: "",
   application_token: "",
   priority: 8,
+  down_priority: 10,
   title: "",
   custom_message: "",
 };
@@ -129,6 +131,48 @@ export default function GotifyForm() {
         )}
       />
 
+      <FormField
+        control={form.control}
+        name="down_priority"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>Down Priority</FormLabel>
+            <Select
+              onValueChange={(val) => {
+                if (!val) {
+                  return;
+                }
+                field.onChange(parseInt(val));
+              }}
+              value={field.value?.toString()}
+            >
+              <FormControl>
+                <SelectTrigger>
+                  <SelectValue placeholder="Select priority" />
+                </SelectTrigger>
+              </FormControl>
+              <SelectContent>
+                <SelectItem value="0">0 - Lowest</SelectItem>
+                <SelectItem value="1">1 - Very Low</SelectItem>
+                <SelectItem value="2">2 - Low</SelectItem>
+                <SelectItem value="3">3 - Below Normal</SelectItem>
+                <SelectItem value="4">4 - Normal</SelectItem>
+                <SelectItem value="5">5 - Above Normal</SelectItem>
+                <SelectItem value="6">6 - Moderate</SelectItem>
+                <SelectItem value="7">7 - High</SelectItem>
+                <SelectItem value="8">8 - Very High (Default)</SelectItem>
+                <SelectItem value="9">9 - Emergency</SelectItem>
+                <SelectItem value="10">10 - Highest</SelectItem>
+              </SelectContent>
+            </Select>
+            <FormDescription>
+              Priority of messages about a monitor going down. Default is 10.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+
       <FormField
         control={form.control}
         name="title"
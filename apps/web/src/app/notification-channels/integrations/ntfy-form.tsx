@@ -28,6 +28,7 @@ export const schema = z.object({
   password: z.string().optional(),
   token: z.string().optional(),
   priority: z.coerce.number().min(1).max(5),
+  down_priority: z.coerce.number().min(1).max(5).optional(),
   tags: z.string().optional(),
   title: z.string().optional(),
   custom_message: z.string().optional(),
@@ -44,6 +45,7 @@ export const defaultValues: NtfyFormValues = {
   password: "",
   token: "",
   priority: 3,
+  down_priority: 5,
   tags: "peekaping,monitoring",
   title: "Peekaping Alert - {{ name }}",
   custom_message: "{{ msg }}",
@@ -264,6 +266,43 @@ export default function NtfyForm() {
         )}
       />
 
+      <FormField
+        control={form.control}
+        name="down_priority"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>Down Priority</FormLabel>
+            <Select
+              onValueChange={(val) => {
+                if (!val) {
+                  return;
+                }
+                field.onChange(parseInt(val));
+              }}
+              value={field.value?.toString()}
+            >
+              <FormControl>
+                <SelectTrigger>
+                  <SelectValue placeholder="Select priority" />
+                </SelectTrigger>
+              </FormControl>
+              <SelectContent>
+                <SelectItem value="1">1 - Min (Lowest)</SelectItem>
+                <SelectItem value="2">2 - Low</SelectItem>
+                <SelectItem value="3">3 - Default</SelectItem>
+                <SelectItem value="4">4 - High</SelectItem>
+                <SelectItem value="5">5 - Urgent (Highest)</SelectItem>
+              </SelectContent>
+            </Select>
+            <FormDescription>
+              The priority of notifications about a monitor going down.
+              Defaults to urgent.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+
       <FormField
         control={form.control}
         name="tags"
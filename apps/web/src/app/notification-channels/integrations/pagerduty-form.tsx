@@ -32,7 +32,7 @@ export const defaultValues: PagerDutyFormValues = {
   pagerduty_integration_key: "",
   pagerduty_integration_url: "https://events.pagerduty.com/v2/enqueue",
   pagerduty_priority: "warning",
-  pagerduty_auto_resolve: "0",
+  pagerduty_auto_resolve: "resolve",
 };
 
 export const displayName = "PagerDuty";
@@ -8,17 +8,24 @@ import {
   FormDescription,
 } from "@/components/ui/form";
 import { z } from "zod";
-import { Switch } from "@/components/ui/switch";
 import { Textarea } from "@/components/ui/textarea";
 import { useFormContext } from "react-hook-form";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
 
 export const schema = z.object({
   type: z.literal("smtp"),
-  smtp_secure: z.boolean(),
+  smtp_secure: z.boolean().optional(),
+  smtp_security: z.enum(["none", "starttls", "tls"]).optional(),
   smtp_host: z.string(),
   smtp_port: z.coerce.number().min(1, { message: "Port is required" }),
-  username: z.string().min(1, { message: "Username is required" }),
-  password: z.string().min(1, { message: "Password is required" }),
+  username: z.string().optional(),
+  password: z.string().optional(),
   from: z.string().email({ message: "Sender email is required" }),
   to: z.string().min(1, { message: "Recipient(s) required" }),
   cc: z.string().optional(),
@@ -32,6 +39,7 @@ export type SmtpFormValues = z.infer<typeof schema>;
 export const defaultValues: SmtpFormValues = {
   type: "smtp",
   smtp_secure: false,
+  smtp_security: "starttls",
   smtp_host: "example.com",
   smtp_port: 587,
   username: "username",
@@ -85,16 +93,22 @@ export default function SmtpForm() {
         />
         <FormField
           control={form.control}
-          name="smtp_secure"
+          name="smtp_security"
           render={({ field }) => (
             <FormItem>
-              <FormLabel>SSL/TLS</FormLabel>
-              <FormControl>
-                <Switch
-                  checked={field.value || false}
-                  onCheckedChange={field.onChange}
-                />
-              </FormControl>
+              <FormLabel>Security</FormLabel>
+              <Select onValueChange={field.onChange} defaultValue={field.value}>
+                <FormControl>
+                  <SelectTrigger>
+                    <SelectValue placeholder="Select security" />
+                  </SelectTrigger>
+                </FormControl>
+                <SelectContent>
+                  <SelectItem value="starttls">STARTTLS</SelectItem>
+                  <SelectItem value="tls">SSL/TLS</SelectItem>
+                  <SelectItem value="none">None</SelectItem>
+                </SelectContent>
+              </Select>
               <FormMessage />
             </FormItem>
           )}
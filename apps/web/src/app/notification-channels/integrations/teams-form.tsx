@@ -0,0 +1,104 @@
+import { Input } from "@/components/ui/input";
+import {
+  FormField,
+  FormItem,
+  FormLabel,
+  FormControl,
+  FormMessage,
+  FormDescription,
+} from "@/components/ui/form";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { z } from "zod";
+import { useFormContext } from "react-hook-form";
+
+export const schema = z.object({
+  type: z.literal("teams"),
+  webhook_url: z.string().url({ message: "Valid webhook URL is required" }),
+  card_type: z.enum(["adaptive_card", "message_card"]).optional(),
+});
+
+export type TeamsFormValues = z.infer<typeof schema>;
+
+export const defaultValues: TeamsFormValues = {
+  type: "teams",
+  webhook_url: "",
+  card_type: "adaptive_card",
+};
+
+export const displayName = "Microsoft Teams";
+
+export default function TeamsForm() {
+  const form = useFormContext();
+
+  return (
+    <>
+      <FormField
+        control={form.control}
+        name="webhook_url"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>
+              Webhook URL <span className="text-red-500">*</span>
+            </FormLabel>
+            <FormControl>
+              <Input
+                placeholder="https://xxx.webhook.office.com/webhookb2/..."
+                type="url"
+                required
+                {...field}
+              />
+            </FormControl>
+            <FormDescription>
+              <span className="text-red-500">*</span> Required
+              <br />
+              <span className="mt-2 block">
+                More info about Webhooks:{" "}
+                <a
+                  href="https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/add-incoming-webhook"
+                  target="_blank"
+                  rel="noopener noreferrer"
+                  className="underline text-blue-600"
+                >
+                  Create an Incoming Webhook
+                </a>
+              </span>
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+
+      <FormField
+        control={form.control}
+        name="card_type"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>Card Type</FormLabel>
+            <Select onValueChange={field.onChange} defaultValue={field.value}>
+              <FormControl>
+                <SelectTrigger>
+                  <SelectValue placeholder="Select card type" />
+                </SelectTrigger>
+              </FormControl>
+              <SelectContent>
+                <SelectItem value="adaptive_card">Adaptive Card</SelectItem>
+                <SelectItem value="message_card">MessageCard</SelectItem>
+              </SelectContent>
+            </Select>
+            <FormDescription>
+              Workflows webhooks accept Adaptive Cards only. Use MessageCard
+              for legacy Office 365 connectors.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+    </>
+  );
+}
@@ -32,6 +32,7 @@ export const schema = z.object({
   template: z.string().optional(),
   send_silently: z.boolean().optional(),
   protect_content: z.boolean().optional(),
+  verify_on_save: z.boolean().optional(),
 });
 
 export type TelegramFormValues = z.infer<typeof schema>;
@@ -47,6 +48,7 @@ export const defaultValues: TelegramFormValues = {
   template: `Peekaping Alert - {{ monitor.name }}\n\n{{ msg }}`,
   send_silently: false,
   protect_content: false,
+  verify_on_save: false,
 };
 
 export const displayName = "Telegram";
@@ -374,6 +376,29 @@ export default function TelegramForm() {
           </FormItem>
         )}
       />
+
+      <FormField
+        control={form.control}
+        name="verify_on_save"
+        render={({ field }) => (
+          <FormItem>
+            <div className="flex items-center gap-2">
+              <FormControl>
+                <Switch
+                  checked={field.value || false}
+                  onCheckedChange={field.onChange}
+                />
+              </FormControl>
+              <FormLabel>Verify on Save</FormLabel>
+            </div>
+            <FormDescription>
+              If enabled, the bot token and chat ID are checked with Telegram
+              when the channel is saved.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
     </>
   );
 }
@@ -17,7 +17,8 @@ import * as React from "react";
 export const schema = z.object({
   type: z.literal("webhook"),
   webhook_url: z.string().url({ message: "Valid URL is required" }),
-  webhook_content_type: z.enum(["json", "form-data", "custom"]),
+  webhook_method: z.enum(["GET", "POST"]).optional(),
+  webhook_content_type: z.enum(["json", "form-data", "form-urlencoded", "custom"]),
   webhook_custom_body: z.string().optional(),
   webhook_additional_headers: z.string().optional(),
 });
@@ -27,6 +28,7 @@ export type WebhookFormValues = z.infer<typeof schema>;
 export const defaultValues: WebhookFormValues = {
   type: "webhook",
   webhook_url: "https://example.com/webhook",
+  webhook_method: "POST",
   webhook_content_type: "json",
   webhook_custom_body: `{
     "Title": "Uptime Alert - {{ monitor.name }}",
@@ -40,6 +42,14 @@ export const displayName = "Webhook";
 export default function WebhookForm() {
   const form = useFormContext();
   const contentType = form.watch("webhook_content_type");
+  const method = form.watch("webhook_method");
+
+  React.useEffect(() => {
+    // a GET request carries its fields in the query string
+    if (method === "GET" && contentType !== "form-urlencoded") {
+      form.setValue("webhook_content_type", "form-urlencoded");
+    }
+  }, [method, contentType, form]);
   const [showAdditionalHeaders, setShowAdditionalHeaders] = React.useState(
     !!form.getValues("webhook_additional_headers")
   );
@@ -61,7 +71,7 @@ export default function WebhookForm() {
     "Title": "Uptime Alert - {{ monitor.name }}",
     "Body": "{{ msg }}",
     "Status": "{{ status }}",
-    "Timestamp": "{{ timestamp }}"
+    "Timestamp": "{{ heartbeat.time }}"
 }`;
 
   return (
@@ -88,6 +98,31 @@ export default function WebhookForm() {
         )}
       />
 
+      <FormField
+        control={form.control}
+        name="webhook_method"
+        render={({ field }) => (
+          <FormItem>
+            <FormLabel>Method</FormLabel>
+            <Select onValueChange={field.onChange} value={field.value ?? "POST"}>
+              <FormControl>
+                <SelectTrigger>
+                  <SelectValue placeholder="Select method" />
+                </SelectTrigger>
+              </FormControl>
+              <SelectContent>
+                <SelectItem value="POST">POST</SelectItem>
+                <SelectItem value="GET">GET</SelectItem>
+              </SelectContent>
+            </Select>
+            <FormDescription>
+              GET requests send the notification fields in the query string.
+            </FormDescription>
+            <FormMessage />
+          </FormItem>
+        )}
+      />
+
       <FormField
         control={form.control}
         name="webhook_content_type"
@@ -101,9 +136,10 @@ export default function WebhookForm() {
                 </SelectTrigger>
               </FormControl>
               <SelectContent>
-                <SelectItem value="json">application/json</SelectItem>
-                <SelectItem value="form-data">multipart/form-data</SelectItem>
-                <SelectItem value="custom">Custom</SelectItem>
+                <SelectItem value="json" disabled={method === "GET"}>application/json</SelectItem>
+                <SelectItem value="form-data" disabled={method === "GET"}>multipart/form-data</SelectItem>
+                <SelectItem value="form-urlencoded">application/x-www-form-urlencoded</SelectItem>
+                <SelectItem value="custom" disabled={method === "GET"}>Custom</SelectItem>
               </SelectContent>
             </Select>
             <FormDescription>
@@ -116,6 +152,12 @@ export default function WebhookForm() {
                   <strong>json_decode($_POST['data'])</strong> in PHP.
                 </>
               )}
+              {contentType === "form-urlencoded" && (
+                <>
+                  Content will be sent as the fields name, status, msg, time and monitor_id, with the full
+                  payload as JSON in <strong>data</strong>.
+                </>
+              )}
               {contentType === "custom" && (
                 <>Define your own custom request body format below.</>
               )}
@@ -145,7 +187,7 @@ export default function WebhookForm() {
                 <code className="text-pink-500 ml-1">{"{{ msg }}"}</code>,{" "}
                 <code className="text-pink-500">{"{{ monitor.name }}"}</code>,{" "}
                 <code className="text-pink-500">{"{{ status }}"}</code>,{" "}
-                <code className="text-pink-500">{"{{ timestamp }}"}</code>
+                <code className="text-pink-500">{"{{ heartbeat.time }}"}</code>
               </FormDescription>
               <FormMessage />
             </FormItem>
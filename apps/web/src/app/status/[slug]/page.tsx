@@ -4,6 +4,7 @@ import { useQuery } from "@tanstack/react-query";
 import {
   getStatusPagesSlugBySlugOptions,
   getStatusPagesSlugBySlugMonitorsOptions,
+  getStatusPagesSlugBySlugIncidentsOptions,
 } from "@/api/@tanstack/react-query.gen";
 import { Card, CardContent } from "@/components/ui/card";
 import { Badge } from "@/components/ui/badge";
@@ -22,6 +23,7 @@ import type { StatusPageMonitorWithHeartbeatsAndUptimeDto } from "@/api/types.ge
 import BarHistory from "@/components/bars";
 import { last } from "@/lib/utils";
 import { ThemeToggle } from "../../../components/theme-toggle";
+import IncidentTimeline from "../components/incident-timeline";
 
 const PublicStatusPage = () => {
   const { slug } = useParams<{ slug: string }>();
@@ -73,6 +75,18 @@ const PublicStatusPage = () => {
 
   const monitors = monitorsData?.data || [];
 
+  // Fetch the incident timeline posted on the status page
+  const { data: incidentsData, refetch: refetchIncidents } = useQuery({
+    ...getStatusPagesSlugBySlugIncidentsOptions({
+      path: {
+        slug: slug!,
+      },
+    }),
+    enabled: !!slug && !!statusPage,
+  });
+
+  const incidents = incidentsData?.data || [];
+
   // Auto-refresh logic
   useEffect(() => {
     if (!slug || !statusPage) return;
@@ -83,6 +97,7 @@ const PublicStatusPage = () => {
           // Time to refresh
           refetchStatusPage();
           refetchMonitors();
+          refetchIncidents();
           setLastUpdated(new Date());
           return refreshInterval;
         }
@@ -91,7 +106,14 @@ const PublicStatusPage = () => {
     }, 1000);
 
     return () => clearInterval(interval);
-  }, [slug, statusPage, refreshInterval, refetchStatusPage, refetchMonitors]);
+  }, [
+    slug,
+    statusPage,
+    refreshInterval,
+    refetchStatusPage,
+    refetchMonitors,
+    refetchIncidents,
+  ]);
 
   // Format countdown as MM:SS
   const formatCountdown = (seconds: number) => {
@@ -345,6 +367,8 @@ const PublicStatusPage = () => {
               )}
           </div>
 
+          <IncidentTimeline incidents={incidents} />
+
           {statusPage.footer_text && (
             <div className="mt-8 pt-8 border-t text-center">
               <p className="text-sm text-muted-foreground">
@@ -369,6 +393,7 @@ const PublicStatusPage = () => {
               onClick={() => {
                 refetchStatusPage();
                 refetchMonitors();
+                refetchIncidents();
                 setLastUpdated(new Date());
                 setCountdown(refreshInterval);
               }}
@@ -0,0 +1,78 @@
+import { Card, CardContent } from "@/components/ui/card";
+import { Badge } from "@/components/ui/badge";
+import type { StatusPageIncidentModel } from "@/api/types.gen";
+
+const statusLabels: Record<string, string> = {
+  investigating: "Investigating",
+  identified: "Identified",
+  monitoring: "Monitoring",
+  resolved: "Resolved",
+};
+
+const getStatusLabel = (status?: string) =>
+  (status && statusLabels[status]) || "Unknown";
+
+const formatTime = (time?: string) => {
+  if (!time) return "";
+  return new Date(time).toLocaleString("en-US", {
+    year: "numeric",
+    month: "short",
+    day: "2-digit",
+    hour: "2-digit",
+    minute: "2-digit",
+    hour12: false,
+  });
+};
+
+// IncidentTimeline lists the incidents posted on a status page, newest first,
+// each with the timeline of its updates
+const IncidentTimeline = ({
+  incidents,
+}: {
+  incidents: StatusPageIncidentModel[];
+}) => {
+  if (incidents.length === 0) return null;
+
+  return (
+    <div className="space-y-4 mt-8 text-left">
+      <h2 className="text-xl font-semibold">Incidents</h2>
+
+      {incidents.map((incident) => (
+        <Card key={incident.id}>
+          <CardContent className="space-y-4">
+            <div className="flex items-center justify-between gap-2">
+              <h3 className="font-semibold">{incident.title}</h3>
+              <Badge
+                variant={
+                  incident.status === "resolved" ? "secondary" : "destructive"
+                }
+              >
+                {getStatusLabel(incident.status)}
+              </Badge>
+            </div>
+
+            <ol className="space-y-3 border-l pl-4">
+              {(incident.updates || []).map((update) => (
+                <li key={update.id} className="space-y-1">
+                  <div className="flex items-center gap-2 text-sm">
+                    <span className="font-medium">
+                      {getStatusLabel(update.status)}
+                    </span>
+                    <span className="text-muted-foreground">
+                      {formatTime(update.created_at)}
+                    </span>
+                  </div>
+                  <p className="text-sm whitespace-pre-line">
+                    {update.message}
+                  </p>
+                </li>
+              ))}
+            </ol>
+          </CardContent>
+        </Card>
+      ))}
+    </div>
+  );
+};
+
+export default IncidentTimeline;